@@ -249,6 +249,16 @@ DB_UPDATE_ERROR
 DB_DELETE_ERROR
 {{- end}}
 
+{{/* An UPPER_SNAKE_CASE reason for an error occurred while interacting with the blob store */}}
+{{- define "global-blobStoreErrorReason" -}}
+BLOB_STORE_ERROR
+{{- end}}
+
+{{/* An UPPER_SNAKE_CASE reason for a request whose etag does not match the current etag of the resource */}}
+{{- define "global-etagMismatchErrorReason" -}}
+ETAG_MISMATCH_ERROR
+{{- end}}
+
 {{- define "global-globalDomainKey" -}}
 GLOBAL_DOMAIN
 {{- end}}
@@ -285,6 +295,167 @@ DB_UPDATE_ERROR_REASON
 DB_DELETE_ERROR_REASON
 {{- end}}
 
+{{- define "global-blobStoreErrorReasonKey" -}}
+BLOB_STORE_ERROR_REASON
+{{- end}}
+
+{{- define "global-etagMismatchErrorReasonKey" -}}
+ETAG_MISMATCH_ERROR_REASON
+{{- end}}
+
+{{- define "global-tombstoneRetentionKey" -}}
+TOMBSTONE_RETENTION
+{{- end}}
+
+{{- define "global-blobBackendKey" -}}
+BLOB_BACKEND
+{{- end}}
+
+{{- define "global-blobFilesystemDirKey" -}}
+BLOB_FILESYSTEM_DIR
+{{- end}}
+
+{{- define "global-blobS3EndpointKey" -}}
+BLOB_S3_ENDPOINT
+{{- end}}
+
+{{- define "global-blobS3RegionKey" -}}
+BLOB_S3_REGION
+{{- end}}
+
+{{- define "global-blobS3BucketKey" -}}
+BLOB_S3_BUCKET
+{{- end}}
+
+{{- define "global-attachmentMaxSizeKey" -}}
+ATTACHMENT_MAX_SIZE
+{{- end}}
+
+{{- define "blobS3AccessKeyIdKey" -}}
+BLOB_S3_ACCESS_KEY_ID
+{{- end}}
+
+{{- define "blobS3SecretAccessKeyKey" -}}
+BLOB_S3_SECRET_ACCESS_KEY
+{{- end}}
+
+{{- define "blob-name-volume" -}}
+blobs
+{{- end}}
+
+{{/* Accepts the root context as parameter and renders the environment variables configuring the blob store */}}
+{{- define "blobEnv" -}}
+{{- $blob := .Values.haExpenseSplitter.blob }}
+- name: {{ include "global-blobBackendKey" . }}
+  valueFrom:
+    configMapKeyRef:
+      name: {{ include "global-name-configMap" . }}
+      key: {{ include "global-blobBackendKey" . }}
+- name: {{ include "global-attachmentMaxSizeKey" . }}
+  valueFrom:
+    configMapKeyRef:
+      name: {{ include "global-name-configMap" . }}
+      key: {{ include "global-attachmentMaxSizeKey" . }}
+{{- if eq $blob.backend "s3" }}
+- name: {{ include "global-blobS3EndpointKey" . }}
+  valueFrom:
+    configMapKeyRef:
+      name: {{ include "global-name-configMap" . }}
+      key: {{ include "global-blobS3EndpointKey" . }}
+- name: {{ include "global-blobS3RegionKey" . }}
+  valueFrom:
+    configMapKeyRef:
+      name: {{ include "global-name-configMap" . }}
+      key: {{ include "global-blobS3RegionKey" . }}
+- name: {{ include "global-blobS3BucketKey" . }}
+  valueFrom:
+    configMapKeyRef:
+      name: {{ include "global-name-configMap" . }}
+      key: {{ include "global-blobS3BucketKey" . }}
+- name: {{ include "blobS3AccessKeyIdKey" . }}
+  valueFrom:
+    secretKeyRef:
+      name: {{ $blob.s3.credentialsSecret.name }}
+      key: {{ $blob.s3.credentialsSecret.accessKeyIdKey }}
+- name: {{ include "blobS3SecretAccessKeyKey" . }}
+  valueFrom:
+    secretKeyRef:
+      name: {{ $blob.s3.credentialsSecret.name }}
+      key: {{ $blob.s3.credentialsSecret.secretAccessKeyKey }}
+{{- else if eq $blob.backend "filesystem" }}
+- name: {{ include "global-blobFilesystemDirKey" . }}
+  valueFrom:
+    configMapKeyRef:
+      name: {{ include "global-name-configMap" . }}
+      key: {{ include "global-blobFilesystemDirKey" . }}
+{{- end }}
+{{- end}}
+
+{{/* Accepts the root context as parameter and renders the volume mounts of the filesystem blob store */}}
+{{- define "blobVolumeMounts" -}}
+- name: {{ include "blob-name-volume" . }}
+  mountPath: {{ .Values.haExpenseSplitter.blob.filesystem.dir }}
+{{- end}}
+
+{{/* Accepts the root context as parameter and renders the volumes of the filesystem blob store */}}
+{{- define "blobVolumes" -}}
+- name: {{ include "blob-name-volume" . }}
+  persistentVolumeClaim:
+    claimName: {{ .Values.haExpenseSplitter.blob.filesystem.existingClaim }}
+{{- end}}
+
+{{- define "global-smtpHostKey" -}}
+SMTP_HOST
+{{- end}}
+
+{{- define "global-smtpPortKey" -}}
+SMTP_PORT
+{{- end}}
+
+{{- define "global-smtpFromKey" -}}
+SMTP_FROM
+{{- end}}
+
+{{- define "smtpUsernameKey" -}}
+SMTP_USERNAME
+{{- end}}
+
+{{- define "smtpPasswordKey" -}}
+SMTP_PASSWORD
+{{- end}}
+
+{{/* Accepts the root context as parameter and renders the environment variables configuring the SMTP server emails are sent through */}}
+{{- define "mailEnv" -}}
+{{- $smtp := .Values.haExpenseSplitter.mail.smtp }}
+- name: {{ include "global-smtpHostKey" . }}
+  valueFrom:
+    configMapKeyRef:
+      name: {{ include "global-name-configMap" . }}
+      key: {{ include "global-smtpHostKey" . }}
+- name: {{ include "global-smtpPortKey" . }}
+  valueFrom:
+    configMapKeyRef:
+      name: {{ include "global-name-configMap" . }}
+      key: {{ include "global-smtpPortKey" . }}
+- name: {{ include "global-smtpFromKey" . }}
+  valueFrom:
+    configMapKeyRef:
+      name: {{ include "global-name-configMap" . }}
+      key: {{ include "global-smtpFromKey" . }}
+{{- if $smtp.credentialsSecret.name }}
+- name: {{ include "smtpUsernameKey" . }}
+  valueFrom:
+    secretKeyRef:
+      name: {{ $smtp.credentialsSecret.name }}
+      key: {{ $smtp.credentialsSecret.usernameKey }}
+- name: {{ include "smtpPasswordKey" . }}
+  valueFrom:
+    secretKeyRef:
+      name: {{ $smtp.credentialsSecret.name }}
+      key: {{ $smtp.credentialsSecret.passwordKey }}
+{{- end }}
+{{- end}}
+
 {{- define "global-natsServerHostKey" -}}
 NATS_SERVER_HOST
 {{- end}}
@@ -333,13 +504,6 @@ processor-rolebinding
 
 
 
-{{/* Accepts the name of the table as parameter */}}
-{{- define "table-name-resource" -}}
-{{ . }}-table
-{{- end}}
-
-
-
 
 
 {{/* Accepts the primary and the fallback image pull secrets as parameters "primary" and "fallback" respectively */}}
@@ -388,4 +552,21 @@ DB_HOST
 
 {{- define "global-dbPortKey" -}}
 DB_PORT
+{{- end}}
+
+{{- define "dbReadEndpointsKey" -}}
+DB_READ_ENDPOINTS
+{{- end}}
+
+{{- define "dbReadReplicaDsnKey" -}}
+DB_READ_REPLICA_DSN
+{{- end}}
+
+{{/* Accepts the map of endpoints to read modes of a service as parameter and renders it as comma separated <endpoint>=<mode> pairs */}}
+{{- define "dbReadEndpoints" -}}
+{{- $pairs := list }}
+{{- range $endpoint, $mode := . }}
+{{- $pairs = append $pairs (printf "%s=%s" $endpoint $mode) }}
+{{- end }}
+{{- join "," $pairs }}
 {{- end}}
\ No newline at end of file
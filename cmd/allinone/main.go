@@ -0,0 +1,546 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"connectrpc.com/connect"
+	grpcreflect "connectrpc.com/grpcreflect"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	activityv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/activity/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/activity/v1/activityv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/attachment/v1/attachmentv1connect"
+	budgetv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/budget/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/budget/v1/budgetv1connect"
+	categoryv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/category/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/category/v1/categoryv1connect"
+	commentv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/comment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/comment/v1/commentv1connect"
+	currencyv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/currency/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/currency/v1/currencyv1connect"
+	debtreminderv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/debtreminder/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/debtreminder/v1/debtreminderv1connect"
+	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expense/v1/expensev1connect"
+	expensecategoryrelationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensecategoryrelation/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensecategoryrelation/v1/expensecategoryrelationv1connect"
+	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensestake/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensestake/v1/expensestakev1connect"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/export/v1/exportv1connect"
+	groupv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/group/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/group/v1/groupv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/importer/v1/importerv1connect"
+	notificationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/notification/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/notification/v1/notificationv1connect"
+	personv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/person/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/person/v1/personv1connect"
+	recurringexpensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/recurringexpense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/recurringexpense/v1/recurringexpensev1connect"
+	reportv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/report/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/report/v1/reportv1connect"
+	webhookv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/webhook/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/webhook/v1/webhookv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/migrations"
+	activityprocessor "github.com/nico151999/high-availability-expense-splitter/internal/processor/activity"
+	attachmentprocessor "github.com/nico151999/high-availability-expense-splitter/internal/processor/attachment"
+	budgetprocessor "github.com/nico151999/high-availability-expense-splitter/internal/processor/budget"
+	categoryprocessor "github.com/nico151999/high-availability-expense-splitter/internal/processor/category"
+	currencyprocessor "github.com/nico151999/high-availability-expense-splitter/internal/processor/currency"
+	debtreminderprocessor "github.com/nico151999/high-availability-expense-splitter/internal/processor/debtreminder"
+	expenseprocessor "github.com/nico151999/high-availability-expense-splitter/internal/processor/expense"
+	expensecategoryrelationprocessor "github.com/nico151999/high-availability-expense-splitter/internal/processor/expensecategoryrelation"
+	expensestakeprocessor "github.com/nico151999/high-availability-expense-splitter/internal/processor/expensestake"
+	groupprocessor "github.com/nico151999/high-availability-expense-splitter/internal/processor/group"
+	notificationprocessor "github.com/nico151999/high-availability-expense-splitter/internal/processor/notification"
+	personprocessor "github.com/nico151999/high-availability-expense-splitter/internal/processor/person"
+	recurringexpenseprocessor "github.com/nico151999/high-availability-expense-splitter/internal/processor/recurringexpense"
+	webhookprocessor "github.com/nico151999/high-availability-expense-splitter/internal/processor/webhook"
+	activityservice "github.com/nico151999/high-availability-expense-splitter/internal/service/activity"
+	attachmentservice "github.com/nico151999/high-availability-expense-splitter/internal/service/attachment"
+	budgetservice "github.com/nico151999/high-availability-expense-splitter/internal/service/budget"
+	categoryservice "github.com/nico151999/high-availability-expense-splitter/internal/service/category"
+	commentservice "github.com/nico151999/high-availability-expense-splitter/internal/service/comment"
+	currencyservice "github.com/nico151999/high-availability-expense-splitter/internal/service/currency"
+	debtreminderservice "github.com/nico151999/high-availability-expense-splitter/internal/service/debtreminder"
+	expenseservice "github.com/nico151999/high-availability-expense-splitter/internal/service/expense"
+	expensecategoryrelationservice "github.com/nico151999/high-availability-expense-splitter/internal/service/expensecategoryrelation"
+	expensestakeservice "github.com/nico151999/high-availability-expense-splitter/internal/service/expensestake"
+	exportservice "github.com/nico151999/high-availability-expense-splitter/internal/service/export"
+	groupservice "github.com/nico151999/high-availability-expense-splitter/internal/service/group"
+	importerservice "github.com/nico151999/high-availability-expense-splitter/internal/service/importer"
+	notificationservice "github.com/nico151999/high-availability-expense-splitter/internal/service/notification"
+	personservice "github.com/nico151999/high-availability-expense-splitter/internal/service/person"
+	recurringexpenseservice "github.com/nico151999/high-availability-expense-splitter/internal/service/recurringexpense"
+	reportservice "github.com/nico151999/high-availability-expense-splitter/internal/service/report"
+	webhookservice "github.com/nico151999/high-availability-expense-splitter/internal/service/webhook"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/blob"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/server"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mail"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/embedded"
+	"github.com/uptrace/bun"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+)
+
+const serviceName = "allInOneService"
+
+// defaultEnvironment holds the values of the environment variables required by the services and processors which are
+// applied unless they are set explicitly, so that the whole system can be started without any configuration
+var defaultEnvironment = map[string]string{
+	"ALL_IN_ONE_SERVER_PORT":             "8080",
+	"GLOBAL_DOMAIN":                      "localhost",
+	"DB_BACKEND":                         string(client.BackendSQLite),
+	"DB_SQLITE_DSN":                      "file:expense_splitter.db?_pragma=busy_timeout(5000)",
+	"DB_SELECT_ERROR_REASON":             "DB_SELECT_ERROR",
+	"DB_INSERT_ERROR_REASON":             "DB_INSERT_ERROR",
+	"DB_UPDATE_ERROR_REASON":             "DB_UPDATE_ERROR",
+	"DB_DELETE_ERROR_REASON":             "DB_DELETE_ERROR",
+	"ETAG_MISMATCH_ERROR_REASON":         "ETAG_MISMATCH_ERROR",
+	"MESSAGE_PUBLICATION_ERROR_REASON":   "MESSAGE_PUBLICATION_ERROR",
+	"MESSAGE_SUBSCRIPTION_ERROR_REASON":  "MESSAGE_SUBSCRIPTION_ERROR",
+	"SEND_CURRENT_RESOURCE_ERROR_REASON": "SEND_CURRENT_RESOURCE_ERROR",
+	"SEND_STREAM_ALIVE_ERROR_REASON":     "SEND_STREAM_ALIVE_ERROR",
+	"TOMBSTONE_RETENTION":                "720h",
+	"BLOB_STORE_ERROR_REASON":            "BLOB_STORE_ERROR",
+	"BLOB_BACKEND":                       string(blob.BackendFilesystem),
+	"BLOB_FILESYSTEM_DIR":                "blobs",
+	"ATTACHMENT_MAX_SIZE":                "10485760",
+	"SMTP_HOST":                          "localhost",
+	"SMTP_PORT":                          "1025",
+	"SMTP_FROM":                          "Expense Splitter <noreply@localhost>",
+}
+
+// processor is implemented by all processors in internal/processor
+type processor interface {
+	Process(ctx context.Context) error
+}
+
+// allInOneHandler bundles the handlers of all services so that a single server can serve them
+type allInOneHandler struct {
+	activity                activityv1connect.ActivityServiceHandler
+	attachment              attachmentv1connect.AttachmentServiceHandler
+	budget                  budgetv1connect.BudgetServiceHandler
+	category                categoryv1connect.CategoryServiceHandler
+	comment                 commentv1connect.CommentServiceHandler
+	currency                currencyv1connect.CurrencyServiceHandler
+	debtReminder            debtreminderv1connect.DebtReminderServiceHandler
+	expense                 expensev1connect.ExpenseServiceHandler
+	expenseCategoryRelation expensecategoryrelationv1connect.ExpenseCategoryRelationServiceHandler
+	expenseStake            expensestakev1connect.ExpenseStakeServiceHandler
+	export                  exportv1connect.ExportServiceHandler
+	group                   groupv1connect.GroupServiceHandler
+	importer                importerv1connect.ImporterServiceHandler
+	notification            notificationv1connect.NotificationServiceHandler
+	person                  personv1connect.PersonServiceHandler
+	recurringExpense        recurringexpensev1connect.RecurringExpenseServiceHandler
+	report                  reportv1connect.ReportServiceHandler
+	webhook                 webhookv1connect.WebhookServiceHandler
+}
+
+func main() {
+	log := logging.GetLogger().Named(serviceName)
+	ctx := logging.IntoContext(context.Background(), log)
+
+	for k, v := range defaultEnvironment {
+		if _, exists := os.LookupEnv(k); exists {
+			continue
+		}
+		if err := os.Setenv(k, v); err != nil {
+			log.Panic("failed setting default environment variable", logging.String("envKey", k), logging.Error(err))
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	natsUrl, shutdownMQServer := runMQServer(ctx)
+	defer shutdownMQServer()
+
+	dbConfig, err := client.ConfigFromEnvironment(ctx)
+	if err != nil {
+		log.Panic(
+			"failed reading database configuration",
+			logging.Error(err))
+	}
+	db, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		log.Panic("failed creating database client", logging.Error(err))
+	}
+	defer db.Close()
+	migrateUp(ctx, db)
+
+	blobConfig, err := blob.ConfigFromEnvironment(ctx)
+	if err != nil {
+		log.Panic(
+			"failed reading blob store configuration",
+			logging.Error(err))
+	}
+	// the attachment service and processor have to share the store so that the in-memory backend works as well
+	blobStore, err := blob.NewStore(blobConfig)
+	if err != nil {
+		log.Panic("failed creating blob store", logging.Error(err))
+	}
+
+	mailSender, err := mail.NewSMTPSender(mail.ConfigFromEnvironment(ctx))
+	if err != nil {
+		log.Panic("failed creating mail sender", logging.Error(err))
+	}
+
+	for name, p := range newProcessors(ctx, natsUrl, db, blobStore, mailSender) {
+		name, p := name, p
+		go func() {
+			if err := p.Process(ctx); err != nil {
+				log.Panic("failed processing events", logging.String("processor", name), logging.Error(err))
+			}
+		}()
+	}
+
+	svc, closeServices := newServices(ctx, natsUrl, db, blobStore)
+	defer closeServices()
+
+	var spanExporter sdktrace.SpanExporter = tracetest.NewNoopExporter()
+	if traceCollectorHost := environment.LookupString("TRACE_COLLECTOR_HOST"); traceCollectorHost != "" {
+		spanExporter, err = server.NewOtlpExporter(
+			ctx,
+			fmt.Sprintf("%s:%d",
+				traceCollectorHost,
+				environment.GetTraceCollectorPort(ctx)))
+		if err != nil {
+			log.Panic("failed creating OTLP span exporter", logging.Error(err))
+		}
+	}
+
+	serverAddress := fmt.Sprintf(":%d", environment.GetAllInOneServerPort(ctx))
+	ln, err := server.Listen(ctx, serverAddress)
+	if err != nil {
+		log.Panic("failed listening", logging.Error(err))
+	}
+	srv, err := server.NewServer(
+		ctx,
+		ln,
+		svc,
+		registerServiceHandlers,
+		createServiceHandler,
+		serviceName,
+		spanExporter)
+	if err != nil {
+		log.Panic("failed creating server", logging.Error(err))
+	}
+	if err := srv.Serve(ctx, ln); err != nil {
+		log.Panic(
+			"failed running server",
+			logging.Error(err))
+	}
+}
+
+// runMQServer starts the embedded MQ server and returns its URL along with a function shutting it down
+func runMQServer(ctx context.Context) (string, func()) {
+	log := logging.FromContext(ctx)
+
+	storeDir := environment.GetAllInOneNatsStoreDir()
+	removeStoreDir := func() {}
+	if storeDir == "" {
+		var err error
+		storeDir, err = os.MkdirTemp("", "expense-splitter-nats-")
+		if err != nil {
+			log.Panic("failed creating temporary directory for the embedded MQ server", logging.Error(err))
+		}
+		removeStoreDir = func() {
+			if err := os.RemoveAll(storeDir); err != nil {
+				log.Error("failed removing temporary directory of the embedded MQ server", logging.Error(err))
+			}
+		}
+	}
+
+	mqServer, port, err := embedded.RunJetStreamMQServer("127.0.0.1", -1, storeDir)
+	if err != nil {
+		removeStoreDir()
+		log.Panic("failed running embedded MQ server", logging.Error(err))
+	}
+	log.Info("running embedded MQ server", logging.Int("port", port), logging.String("storeDir", storeDir))
+	return fmt.Sprintf("127.0.0.1:%d", port), func() {
+		mqServer.Shutdown()
+		mqServer.WaitForShutdown()
+		removeStoreDir()
+	}
+}
+
+// migrateUp applies pending migrations so that an empty database can be used right away
+func migrateUp(ctx context.Context, db *bun.DB) {
+	log := logging.FromContext(ctx)
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		log.Panic("failed creating migrator", logging.Error(err))
+	}
+	applied, err := migrator.Up(ctx, false)
+	if err != nil {
+		log.Panic("failed migrating the database up", logging.Error(err))
+	}
+	for _, migration := range applied {
+		log.Info("applied migration", logging.String("migration", migration.String()))
+	}
+}
+
+// newProcessors creates all processors sharing the passed database client and blob store
+func newProcessors(ctx context.Context, natsUrl string, db bun.IDB, blobStore blob.Store, mailSender mail.Sender) map[string]processor {
+	log := logging.FromContext(ctx)
+
+	processors := make(map[string]processor)
+	add := func(name string, p processor, err error) {
+		if err != nil {
+			log.Panic("failed creating processor", logging.String("processor", name), logging.Error(err))
+		}
+		processors[name] = p
+	}
+	{
+		p, err := activityprocessor.NewActivityProcessorWithDBClient(natsUrl, db)
+		add("activity", p, err)
+	}
+	{
+		p, err := attachmentprocessor.NewAttachmentProcessorWithClients(natsUrl, db, blobStore)
+		add("attachment", p, err)
+	}
+	{
+		p, err := budgetprocessor.NewBudgetProcessorWithDBClient(natsUrl, db)
+		add("budget", p, err)
+	}
+	{
+		p, err := categoryprocessor.NewCategoryProcessorWithDBClient(natsUrl, db)
+		add("category", p, err)
+	}
+	{
+		p, err := currencyprocessor.NewCurrencyProcessorWithDBClient(natsUrl, db)
+		add("currency", p, err)
+	}
+	{
+		p, err := debtreminderprocessor.NewDebtReminderProcessorWithDBClient(natsUrl, db)
+		add("debtreminder", p, err)
+	}
+	{
+		p, err := expenseprocessor.NewExpenseProcessorWithDBClient(natsUrl, db)
+		add("expense", p, err)
+	}
+	{
+		p, err := expensecategoryrelationprocessor.NewExpenseCategoryRelationProcessorWithDBClient(natsUrl, db)
+		add("expensecategoryrelation", p, err)
+	}
+	{
+		p, err := expensestakeprocessor.NewExpenseStakeProcessorWithDBClient(natsUrl, db)
+		add("expensestake", p, err)
+	}
+	{
+		p, err := groupprocessor.NewGroupProcessorWithDBClient(natsUrl, db)
+		add("group", p, err)
+	}
+	{
+		p, err := notificationprocessor.NewNotificationProcessorWithClients(natsUrl, db, mailSender)
+		add("notification", p, err)
+	}
+	{
+		p, err := personprocessor.NewPersonProcessorWithDBClient(natsUrl, db)
+		add("person", p, err)
+	}
+	{
+		p, err := recurringexpenseprocessor.NewRecurringExpenseProcessorWithDBClient(natsUrl, db)
+		add("recurringexpense", p, err)
+	}
+	{
+		p, err := webhookprocessor.NewWebhookProcessorWithDBClient(natsUrl, db)
+		add("webhook", p, err)
+	}
+	return processors
+}
+
+// newServices creates all services sharing the passed database client and blob store and returns them along with a function closing them
+func newServices(ctx context.Context, natsUrl string, db bun.IDB, blobStore blob.Store) (*allInOneHandler, func()) {
+	log := logging.FromContext(ctx)
+
+	var closers []func() error
+	check := func(name string, err error) {
+		if err != nil {
+			log.Panic("failed creating service", logging.String("service", name), logging.Error(err))
+		}
+	}
+	svc := &allInOneHandler{}
+	{
+		s, err := activityservice.NewActivityServerWithDBClient(ctx, db, natsUrl)
+		check("activity", err)
+		svc.activity, closers = s, append(closers, s.Close)
+	}
+	{
+		s, err := attachmentservice.NewAttachmentServerWithClients(ctx, db, blobStore, natsUrl)
+		check("attachment", err)
+		svc.attachment, closers = s, append(closers, s.Close)
+	}
+	{
+		s, err := budgetservice.NewBudgetServerWithDBClient(ctx, db, natsUrl)
+		check("budget", err)
+		svc.budget, closers = s, append(closers, s.Close)
+	}
+	{
+		s, err := categoryservice.NewCategoryServerWithDBClient(ctx, db, natsUrl)
+		check("category", err)
+		svc.category, closers = s, append(closers, s.Close)
+	}
+	{
+		s, err := commentservice.NewCommentServerWithDBClient(ctx, db, natsUrl)
+		check("comment", err)
+		svc.comment, closers = s, append(closers, s.Close)
+	}
+	{
+		s, err := currencyservice.NewCurrencyServerWithDBClient(ctx, db, natsUrl)
+		check("currency", err)
+		svc.currency, closers = s, append(closers, s.Close)
+	}
+	{
+		s, err := debtreminderservice.NewDebtReminderServerWithDBClient(ctx, db, natsUrl)
+		check("debtreminder", err)
+		svc.debtReminder, closers = s, append(closers, s.Close)
+	}
+	{
+		s, err := expenseservice.NewExpenseServerWithDBClient(ctx, db, natsUrl)
+		check("expense", err)
+		svc.expense, closers = s, append(closers, s.Close)
+	}
+	{
+		s, err := expensecategoryrelationservice.NewExpenseCategoryRelationServerWithDBClient(ctx, db, natsUrl)
+		check("expensecategoryrelation", err)
+		svc.expenseCategoryRelation, closers = s, append(closers, s.Close)
+	}
+	{
+		s, err := expensestakeservice.NewExpenseStakeServerWithDBClient(ctx, db, natsUrl)
+		check("expensestake", err)
+		svc.expenseStake, closers = s, append(closers, s.Close)
+	}
+	{
+		s, err := exportservice.NewExportServerWithDBClient(ctx, db, natsUrl)
+		check("export", err)
+		svc.export, closers = s, append(closers, s.Close)
+	}
+	{
+		s, err := groupservice.NewGroupServerWithDBClient(ctx, db, natsUrl)
+		check("group", err)
+		svc.group, closers = s, append(closers, s.Close)
+	}
+	{
+		s, err := importerservice.NewImporterServerWithDBClient(ctx, db, natsUrl)
+		check("importer", err)
+		svc.importer, closers = s, append(closers, s.Close)
+	}
+	{
+		s, err := notificationservice.NewNotificationServerWithDBClient(ctx, db, natsUrl)
+		check("notification", err)
+		svc.notification, closers = s, append(closers, s.Close)
+	}
+	{
+		s, err := personservice.NewPersonServerWithDBClient(ctx, db, natsUrl)
+		check("person", err)
+		svc.person, closers = s, append(closers, s.Close)
+	}
+	{
+		s, err := recurringexpenseservice.NewRecurringExpenseServerWithDBClient(ctx, db, natsUrl)
+		check("recurringexpense", err)
+		svc.recurringExpense, closers = s, append(closers, s.Close)
+	}
+	{
+		s, err := reportservice.NewReportServerWithDBClient(ctx, db, natsUrl)
+		check("report", err)
+		svc.report, closers = s, append(closers, s.Close)
+	}
+	{
+		s, err := webhookservice.NewWebhookServerWithDBClient(ctx, db, natsUrl)
+		check("webhook", err)
+		svc.webhook, closers = s, append(closers, s.Close)
+	}
+	return svc, func() {
+		for _, c := range closers {
+			if err := c(); err != nil {
+				log.Error("failed closing service", logging.Error(err))
+			}
+		}
+	}
+}
+
+// registerServiceHandlers registers the REST gateways of all services
+func registerServiceHandlers(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	for _, register := range []server.ServiceHandlerRegistrarFunc{
+		activityv1.RegisterActivityServiceHandler,
+		attachmentservice.RegisterAttachmentServiceHandler,
+		budgetv1.RegisterBudgetServiceHandler,
+		categoryv1.RegisterCategoryServiceHandler,
+		commentv1.RegisterCommentServiceHandler,
+		currencyv1.RegisterCurrencyServiceHandler,
+		debtreminderv1.RegisterDebtReminderServiceHandler,
+		expensev1.RegisterExpenseServiceHandler,
+		expensecategoryrelationv1.RegisterExpenseCategoryRelationServiceHandler,
+		expensestakev1.RegisterExpenseStakeServiceHandler,
+		exportservice.RegisterExportServiceHandler,
+		groupv1.RegisterGroupServiceHandler,
+		importerservice.RegisterImporterServiceHandler,
+		notificationv1.RegisterNotificationServiceHandler,
+		personv1.RegisterPersonServiceHandler,
+		recurringexpensev1.RegisterRecurringExpenseServiceHandler,
+		reportv1.RegisterReportServiceHandler,
+		webhookv1.RegisterWebhookServiceHandler,
+	} {
+		if err := register(ctx, mux, conn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createServiceHandler creates the Connect handlers of all services along with gRPC reflection
+func createServiceHandler(svc *allInOneHandler, options ...connect.HandlerOption) (string, http.Handler) {
+	mux := http.NewServeMux()
+	mux.Handle(activityv1connect.NewActivityServiceHandler(svc.activity, options...))
+	mux.Handle(attachmentv1connect.NewAttachmentServiceHandler(svc.attachment, options...))
+	mux.Handle(budgetv1connect.NewBudgetServiceHandler(svc.budget, options...))
+	mux.Handle(categoryv1connect.NewCategoryServiceHandler(svc.category, options...))
+	mux.Handle(commentv1connect.NewCommentServiceHandler(svc.comment, options...))
+	mux.Handle(currencyv1connect.NewCurrencyServiceHandler(svc.currency, options...))
+	mux.Handle(debtreminderv1connect.NewDebtReminderServiceHandler(svc.debtReminder, options...))
+	mux.Handle(expensev1connect.NewExpenseServiceHandler(svc.expense, options...))
+	mux.Handle(expensecategoryrelationv1connect.NewExpenseCategoryRelationServiceHandler(svc.expenseCategoryRelation, options...))
+	mux.Handle(expensestakev1connect.NewExpenseStakeServiceHandler(svc.expenseStake, options...))
+	mux.Handle(exportv1connect.NewExportServiceHandler(svc.export, options...))
+	mux.Handle(groupv1connect.NewGroupServiceHandler(svc.group, options...))
+	mux.Handle(importerv1connect.NewImporterServiceHandler(svc.importer, options...))
+	mux.Handle(notificationv1connect.NewNotificationServiceHandler(svc.notification, options...))
+	mux.Handle(personv1connect.NewPersonServiceHandler(svc.person, options...))
+	mux.Handle(recurringexpensev1connect.NewRecurringExpenseServiceHandler(svc.recurringExpense, options...))
+	mux.Handle(reportv1connect.NewReportServiceHandler(svc.report, options...))
+	mux.Handle(webhookv1connect.NewWebhookServiceHandler(svc.webhook, options...))
+
+	reflector := grpcreflect.NewStaticReflector(
+		activityv1connect.ActivityServiceName,
+		attachmentv1connect.AttachmentServiceName,
+		budgetv1connect.BudgetServiceName,
+		categoryv1connect.CategoryServiceName,
+		commentv1connect.CommentServiceName,
+		currencyv1connect.CurrencyServiceName,
+		debtreminderv1connect.DebtReminderServiceName,
+		expensev1connect.ExpenseServiceName,
+		expensecategoryrelationv1connect.ExpenseCategoryRelationServiceName,
+		expensestakev1connect.ExpenseStakeServiceName,
+		exportv1connect.ExportServiceName,
+		groupv1connect.GroupServiceName,
+		importerv1connect.ImporterServiceName,
+		notificationv1connect.NotificationServiceName,
+		personv1connect.PersonServiceName,
+		recurringexpensev1connect.RecurringExpenseServiceName,
+		reportv1connect.ReportServiceName,
+		webhookv1connect.WebhookServiceName,
+	)
+	mux.Handle(grpcreflect.NewHandlerV1Alpha(reflector, options...))
+	mux.Handle(grpcreflect.NewHandlerV1(reflector, options...))
+	return "/", mux
+}
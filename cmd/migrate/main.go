@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/migrations"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+const commandName = "migrate"
+
+func main() {
+	log := logging.GetLogger().Named(commandName)
+	ctx := logging.IntoContext(context.Background(), log)
+
+	dryRun := flag.Bool("dry-run", false, "print the migrations and their SQL instead of executing them")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-dry-run] up|down|status\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	dbConfig, err := client.ConfigFromEnvironment(ctx)
+	if err != nil {
+		log.Panic("failed reading database configuration", logging.Error(err))
+	}
+	db, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		log.Panic("failed creating database client", logging.Error(err))
+	}
+	defer db.Close()
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		log.Panic("failed creating migrator", logging.Error(err))
+	}
+
+	switch command := flag.Arg(0); command {
+	case "up":
+		applied, err := migrator.Up(ctx, *dryRun)
+		if err != nil {
+			log.Panic("failed migrating the database up", logging.Error(err))
+		}
+		printMigrations(applied, *dryRun, "applied")
+	case "down":
+		rolledBack, err := migrator.Down(ctx, *dryRun)
+		if err != nil {
+			log.Panic("failed migrating the database down", logging.Error(err))
+		}
+		printMigrations(rolledBack, *dryRun, "rolled back")
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			log.Panic("failed getting the migration status", logging.Error(err))
+		}
+		for _, migration := range status {
+			if migration.IsApplied() {
+				fmt.Printf("applied  %s (group %d, %s)\n", migration.String(), migration.GroupID, migration.MigratedAt.Format("2006-01-02 15:04:05"))
+			} else {
+				fmt.Printf("pending  %s\n", migration.String())
+			}
+		}
+	default:
+		fmt.Fprintf(flag.CommandLine.Output(), "unknown command %q\n", command)
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func printMigrations(pending []migrations.PendingMigration, dryRun bool, action string) {
+	if len(pending) == 0 {
+		fmt.Println("there are no migrations to execute")
+		return
+	}
+	for _, migration := range pending {
+		if dryRun {
+			fmt.Printf("-- would be %s: %s\n%s\n", action, migration.String(), migration.SQL)
+		} else {
+			fmt.Printf("%s %s\n", action, migration.String())
+		}
+	}
+}
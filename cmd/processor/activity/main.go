@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/nico151999/high-availability-expense-splitter/internal/processor/activity"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+const processorName = "activityProcessor"
+
+func main() {
+	log := logging.GetLogger().Named(processorName)
+	ctx := logging.IntoContext(context.Background(), log)
+
+	// ensure mandatory environment variables are set
+	environment.GetNatsServerHost(ctx)
+	environment.GetNatsServerPort(ctx)
+
+	dbConfig, err := client.ConfigFromEnvironment(ctx)
+	if err != nil {
+		log.Panic(
+			"failed reading database configuration",
+			logging.Error(err))
+	}
+
+	rpProcessor, err := activity.NewActivityProcessor(
+		fmt.Sprintf("%s:%d",
+			environment.GetNatsServerHost(ctx),
+			environment.GetNatsServerPort(ctx)),
+		dbConfig)
+	if err != nil {
+		log.Panic("failed creating activity processor", logging.Error(err))
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	go func() {
+		if err := rpProcessor.Process(ctx); err != nil {
+			log.Panic("failed processing activity-related events", logging.Error(err))
+		}
+	}()
+
+	log.Info("Processing activity-related events...")
+	<-ctx.Done()
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/nico151999/high-availability-expense-splitter/internal/processor/attachment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/blob"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+const processorName = "attachmentProcessor"
+
+func main() {
+	log := logging.GetLogger().Named(processorName)
+	ctx := logging.IntoContext(context.Background(), log)
+
+	// ensure mandatory environment variables are set
+	environment.GetNatsServerHost(ctx)
+	environment.GetNatsServerPort(ctx)
+	environment.GetTombstoneRetention(ctx)
+
+	dbConfig, err := client.ConfigFromEnvironment(ctx)
+	if err != nil {
+		log.Panic(
+			"failed reading database configuration",
+			logging.Error(err))
+	}
+	blobConfig, err := blob.ConfigFromEnvironment(ctx)
+	if err != nil {
+		log.Panic(
+			"failed reading blob store configuration",
+			logging.Error(err))
+	}
+
+	rpProcessor, err := attachment.NewAttachmentProcessor(
+		fmt.Sprintf("%s:%d",
+			environment.GetNatsServerHost(ctx),
+			environment.GetNatsServerPort(ctx)),
+		dbConfig,
+		blobConfig)
+	if err != nil {
+		log.Panic("failed creating attachment processor", logging.Error(err))
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	go func() {
+		if err := rpProcessor.Process(ctx); err != nil {
+			log.Panic("failed processing attachment-related events", logging.Error(err))
+		}
+	}()
+
+	log.Info("Processing attachment-related events...")
+	<-ctx.Done()
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/nico151999/high-availability-expense-splitter/internal/processor/debtreminder"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+const processorName = "debtReminderProcessor"
+
+func main() {
+	log := logging.GetLogger().Named(processorName)
+	ctx := logging.IntoContext(context.Background(), log)
+
+	// ensure mandatory environment variables are set
+	environment.GetNatsServerHost(ctx)
+	environment.GetNatsServerPort(ctx)
+
+	dbConfig, err := client.ConfigFromEnvironment(ctx)
+	if err != nil {
+		log.Panic(
+			"failed reading database configuration",
+			logging.Error(err))
+	}
+
+	rpProcessor, err := debtreminder.NewDebtReminderProcessor(
+		fmt.Sprintf("%s:%d",
+			environment.GetNatsServerHost(ctx),
+			environment.GetNatsServerPort(ctx)),
+		dbConfig)
+	if err != nil {
+		log.Panic("failed creating debt reminder processor", logging.Error(err))
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	go func() {
+		if err := rpProcessor.Process(ctx); err != nil {
+			log.Panic("failed processing debt reminders", logging.Error(err))
+		}
+	}()
+
+	log.Info("Processing debt reminders...")
+	<-ctx.Done()
+}
@@ -7,6 +7,7 @@ import (
 	"os/signal"
 
 	"github.com/nico151999/high-availability-expense-splitter/internal/processor/expense"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
 )
@@ -21,14 +22,18 @@ func main() {
 	environment.GetNatsServerHost(ctx)
 	environment.GetNatsServerPort(ctx)
 
+	dbConfig, err := client.ConfigFromEnvironment(ctx)
+	if err != nil {
+		log.Panic(
+			"failed reading database configuration",
+			logging.Error(err))
+	}
+
 	rpProcessor, err := expense.NewExpenseProcessor(
 		fmt.Sprintf("%s:%d",
 			environment.GetNatsServerHost(ctx),
 			environment.GetNatsServerPort(ctx)),
-		environment.GetDbUser(ctx),
-		environment.GetDbPassword(ctx),
-		fmt.Sprintf("%s:%d", environment.GetDbHost(ctx), environment.GetDbPort(ctx)),
-		environment.GetDbName(ctx))
+		dbConfig)
 	if err != nil {
 		log.Panic("failed creating expense processor", logging.Error(err))
 	}
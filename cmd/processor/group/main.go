@@ -7,6 +7,7 @@ import (
 	"os/signal"
 
 	"github.com/nico151999/high-availability-expense-splitter/internal/processor/group"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
 )
@@ -20,11 +21,20 @@ func main() {
 	// ensure mandatory environment variables are set
 	environment.GetNatsServerHost(ctx)
 	environment.GetNatsServerPort(ctx)
+	environment.GetTombstoneRetention(ctx)
+
+	dbConfig, err := client.ConfigFromEnvironment(ctx)
+	if err != nil {
+		log.Panic(
+			"failed reading database configuration",
+			logging.Error(err))
+	}
 
 	rpProcessor, err := group.NewGroupProcessor(
 		fmt.Sprintf("%s:%d",
 			environment.GetNatsServerHost(ctx),
-			environment.GetNatsServerPort(ctx)))
+			environment.GetNatsServerPort(ctx)),
+		dbConfig)
 	if err != nil {
 		log.Panic("failed creating group processor", logging.Error(err))
 	}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/nico151999/high-availability-expense-splitter/internal/processor/notification"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mail"
+)
+
+const processorName = "notificationProcessor"
+
+func main() {
+	log := logging.GetLogger().Named(processorName)
+	ctx := logging.IntoContext(context.Background(), log)
+
+	// ensure mandatory environment variables are set
+	environment.GetNatsServerHost(ctx)
+	environment.GetNatsServerPort(ctx)
+
+	dbConfig, err := client.ConfigFromEnvironment(ctx)
+	if err != nil {
+		log.Panic(
+			"failed reading database configuration",
+			logging.Error(err))
+	}
+
+	rpProcessor, err := notification.NewNotificationProcessor(
+		fmt.Sprintf("%s:%d",
+			environment.GetNatsServerHost(ctx),
+			environment.GetNatsServerPort(ctx)),
+		dbConfig,
+		mail.ConfigFromEnvironment(ctx))
+	if err != nil {
+		log.Panic("failed creating notification processor", logging.Error(err))
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	go func() {
+		if err := rpProcessor.Process(ctx); err != nil {
+			log.Panic("failed processing notifications", logging.Error(err))
+		}
+	}()
+
+	log.Info("Processing notifications...")
+	<-ctx.Done()
+}
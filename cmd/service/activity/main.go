@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	activityv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/activity/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/activity/v1/activityv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/service/activity"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/server"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+const serviceName = "activityService"
+
+func main() {
+	log := logging.GetLogger().Named(serviceName)
+	ctx := logging.IntoContext(context.Background(), log)
+
+	// ensure mandatory environment variables are set
+	environment.GetActivityServerPort(ctx)
+	environment.GetNatsServerHost(ctx)
+	environment.GetNatsServerPort(ctx)
+	environment.GetGlobalDomain(ctx)
+	environment.GetTraceCollectorHost(ctx)
+	environment.GetTraceCollectorPort(ctx)
+	environment.GetDBSelectErrorReason(ctx)
+	environment.GetMessageSubscriptionErrorReason(ctx)
+	environment.GetSendCurrentResourceErrorReason(ctx)
+	environment.GetSendStreamAliveErrorReason(ctx)
+	environment.GetActivitiesSubject("foo")
+	environment.GetActivitySubject("foo", "bar")
+	environment.GetActivityRecordedSubject("foo", "bar")
+
+	dbConfig, err := client.ConfigFromEnvironment(ctx)
+	if err != nil {
+		log.Panic(
+			"failed reading database configuration",
+			logging.Error(err))
+	}
+
+	svc, err := activity.NewActivityServer(
+		ctx,
+		fmt.Sprintf("%s:%d",
+			environment.GetNatsServerHost(ctx),
+			environment.GetNatsServerPort(ctx)),
+		dbConfig)
+	if err != nil {
+		log.Panic(
+			"failed creating new activity server",
+			logging.Error(err),
+		)
+	}
+	defer svc.Close()
+
+	serverAddress := fmt.Sprintf(":%d", environment.GetActivityServerPort(ctx))
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	err = server.ListenAndServe[activityv1connect.ActivityServiceHandler](
+		ctx,
+		serverAddress,
+		svc,
+		activityv1.RegisterActivityServiceHandler,
+		activityv1connect.NewActivityServiceHandler,
+		serviceName,
+		fmt.Sprintf("%s:%d",
+			environment.GetTraceCollectorHost(ctx),
+			environment.GetTraceCollectorPort(ctx)))
+	if err != nil {
+		log.Panic(
+			"failed running server",
+			logging.Error(err))
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/attachment/v1/attachmentv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/service/attachment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/blob"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/server"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+const serviceName = "attachmentService"
+
+func main() {
+	log := logging.GetLogger().Named(serviceName)
+	ctx := logging.IntoContext(context.Background(), log)
+
+	// ensure mandatory environment variables are set
+	environment.GetAttachmentServerPort(ctx)
+	environment.GetNatsServerHost(ctx)
+	environment.GetNatsServerPort(ctx)
+	environment.GetGlobalDomain(ctx)
+	environment.GetTraceCollectorHost(ctx)
+	environment.GetTraceCollectorPort(ctx)
+	environment.GetMessagePublicationErrorReason(ctx)
+	environment.GetDBSelectErrorReason(ctx)
+	environment.GetDBDeleteErrorReason(ctx)
+	environment.GetDBInsertErrorReason(ctx)
+	environment.GetBlobStoreErrorReason(ctx)
+	environment.GetSendCurrentResourceErrorReason(ctx)
+	environment.GetAttachmentMaxSize(ctx)
+	environment.GetAttachmentCreatedSubject("foo", "bar", "baz")
+	environment.GetAttachmentDeletedSubject("foo", "bar", "baz")
+
+	dbConfig, err := client.ConfigFromEnvironment(ctx)
+	if err != nil {
+		log.Panic(
+			"failed reading database configuration",
+			logging.Error(err))
+	}
+	blobConfig, err := blob.ConfigFromEnvironment(ctx)
+	if err != nil {
+		log.Panic(
+			"failed reading blob store configuration",
+			logging.Error(err))
+	}
+
+	svc, err := attachment.NewAttachmentServer(
+		ctx,
+		fmt.Sprintf("%s:%d",
+			environment.GetNatsServerHost(ctx),
+			environment.GetNatsServerPort(ctx)),
+		dbConfig,
+		blobConfig)
+	if err != nil {
+		log.Panic(
+			"failed creating new attachment server",
+			logging.Error(err),
+		)
+	}
+	defer svc.Close()
+
+	serverAddress := fmt.Sprintf(":%d", environment.GetAttachmentServerPort(ctx))
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	err = server.ListenAndServe[attachmentv1connect.AttachmentServiceHandler](
+		ctx,
+		serverAddress,
+		svc,
+		attachment.RegisterAttachmentServiceHandler,
+		attachmentv1connect.NewAttachmentServiceHandler,
+		serviceName,
+		fmt.Sprintf("%s:%d",
+			environment.GetTraceCollectorHost(ctx),
+			environment.GetTraceCollectorPort(ctx)))
+	if err != nil {
+		log.Panic(
+			"failed running server",
+			logging.Error(err))
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	commentv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/comment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/comment/v1/commentv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/service/comment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/server"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+const serviceName = "commentService"
+
+func main() {
+	log := logging.GetLogger().Named(serviceName)
+	ctx := logging.IntoContext(context.Background(), log)
+
+	// ensure mandatory environment variables are set
+	environment.GetCommentServerPort(ctx)
+	environment.GetNatsServerHost(ctx)
+	environment.GetNatsServerPort(ctx)
+	environment.GetGlobalDomain(ctx)
+	environment.GetTraceCollectorHost(ctx)
+	environment.GetTraceCollectorPort(ctx)
+	environment.GetMessagePublicationErrorReason(ctx)
+	environment.GetDBSelectErrorReason(ctx)
+	environment.GetDBDeleteErrorReason(ctx)
+	environment.GetDBInsertErrorReason(ctx)
+	environment.GetDBUpdateErrorReason(ctx)
+	environment.GetMessageSubscriptionErrorReason(ctx)
+	environment.GetSendCurrentResourceErrorReason(ctx)
+	environment.GetSendStreamAliveErrorReason(ctx)
+	environment.GetEtagMismatchErrorReason(ctx)
+	environment.GetCommentsSubject("foo", "bar")
+	environment.GetCommentSubject("foo", "bar", "baz")
+	environment.GetCommentCreatedSubject("foo", "bar", "baz")
+	environment.GetCommentUpdatedSubject("foo", "bar", "baz")
+	environment.GetCommentDeletedSubject("foo", "bar", "baz")
+
+	dbConfig, err := client.ConfigFromEnvironment(ctx)
+	if err != nil {
+		log.Panic(
+			"failed reading database configuration",
+			logging.Error(err))
+	}
+
+	svc, err := comment.NewCommentServer(
+		ctx,
+		fmt.Sprintf("%s:%d",
+			environment.GetNatsServerHost(ctx),
+			environment.GetNatsServerPort(ctx)),
+		dbConfig)
+	if err != nil {
+		log.Panic(
+			"failed creating new comment server",
+			logging.Error(err),
+		)
+	}
+	defer svc.Close()
+
+	serverAddress := fmt.Sprintf(":%d", environment.GetCommentServerPort(ctx))
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	err = server.ListenAndServe[commentv1connect.CommentServiceHandler](
+		ctx,
+		serverAddress,
+		svc,
+		commentv1.RegisterCommentServiceHandler,
+		commentv1connect.NewCommentServiceHandler,
+		serviceName,
+		fmt.Sprintf("%s:%d",
+			environment.GetTraceCollectorHost(ctx),
+			environment.GetTraceCollectorPort(ctx)))
+	if err != nil {
+		log.Panic(
+			"failed running server",
+			logging.Error(err))
+	}
+}
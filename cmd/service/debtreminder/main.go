@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	debtreminderv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/debtreminder/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/debtreminder/v1/debtreminderv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/service/debtreminder"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/server"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+const serviceName = "debtReminderService"
+
+func main() {
+	log := logging.GetLogger().Named(serviceName)
+	ctx := logging.IntoContext(context.Background(), log)
+
+	// ensure mandatory environment variables are set
+	environment.GetDebtReminderServerPort(ctx)
+	environment.GetNatsServerHost(ctx)
+	environment.GetNatsServerPort(ctx)
+	environment.GetGlobalDomain(ctx)
+	environment.GetTraceCollectorHost(ctx)
+	environment.GetTraceCollectorPort(ctx)
+	environment.GetDBSelectErrorReason(ctx)
+	environment.GetDBDeleteErrorReason(ctx)
+	environment.GetDBInsertErrorReason(ctx)
+	environment.GetDBUpdateErrorReason(ctx)
+	environment.GetEtagMismatchErrorReason(ctx)
+
+	dbConfig, err := client.ConfigFromEnvironment(ctx)
+	if err != nil {
+		log.Panic(
+			"failed reading database configuration",
+			logging.Error(err))
+	}
+
+	svc, err := debtreminder.NewDebtReminderServer(
+		ctx,
+		fmt.Sprintf("%s:%d",
+			environment.GetNatsServerHost(ctx),
+			environment.GetNatsServerPort(ctx)),
+		dbConfig)
+	if err != nil {
+		log.Panic(
+			"failed creating new debt reminder server",
+			logging.Error(err),
+		)
+	}
+	defer svc.Close()
+
+	serverAddress := fmt.Sprintf(":%d", environment.GetDebtReminderServerPort(ctx))
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	err = server.ListenAndServe[debtreminderv1connect.DebtReminderServiceHandler](
+		ctx,
+		serverAddress,
+		svc,
+		debtreminderv1.RegisterDebtReminderServiceHandler,
+		debtreminderv1connect.NewDebtReminderServiceHandler,
+		serviceName,
+		fmt.Sprintf("%s:%d",
+			environment.GetTraceCollectorHost(ctx),
+			environment.GetTraceCollectorPort(ctx)))
+	if err != nil {
+		log.Panic(
+			"failed running server",
+			logging.Error(err))
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expense/v1/expensev1connect"
 	"github.com/nico151999/high-availability-expense-splitter/internal/service/expense"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/server"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
 )
@@ -24,10 +25,6 @@ func main() {
 	environment.GetExpenseServerPort(ctx)
 	environment.GetNatsServerHost(ctx)
 	environment.GetNatsServerPort(ctx)
-	environment.GetDbUser(ctx)
-	environment.GetDbPassword(ctx)
-	environment.GetDbHost(ctx)
-	environment.GetDbPort(ctx)
 	environment.GetGlobalDomain(ctx)
 	environment.GetTraceCollectorHost(ctx)
 	environment.GetTraceCollectorPort(ctx)
@@ -45,15 +42,19 @@ func main() {
 	environment.GetExpenseDeletedSubject("foo", "bar")
 	environment.GetExpenseUpdatedSubject("foo", "bar")
 
+	dbConfig, err := client.ConfigFromEnvironment(ctx)
+	if err != nil {
+		log.Panic(
+			"failed reading database configuration",
+			logging.Error(err))
+	}
+
 	svc, err := expense.NewExpenseServer(
 		ctx,
 		fmt.Sprintf("%s:%d",
 			environment.GetNatsServerHost(ctx),
 			environment.GetNatsServerPort(ctx)),
-		environment.GetDbUser(ctx),
-		environment.GetDbPassword(ctx),
-		fmt.Sprintf("%s:%d", environment.GetDbHost(ctx), environment.GetDbPort(ctx)),
-		environment.GetDbName(ctx))
+		dbConfig)
 	if err != nil {
 		log.Panic(
 			"failed creating new expense server",
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	recurringexpensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/recurringexpense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/recurringexpense/v1/recurringexpensev1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/service/recurringexpense"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/server"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+const serviceName = "recurringExpenseService"
+
+func main() {
+	log := logging.GetLogger().Named(serviceName)
+	ctx := logging.IntoContext(context.Background(), log)
+
+	// ensure mandatory environment variables are set
+	environment.GetRecurringexpenseServerPort(ctx)
+	environment.GetNatsServerHost(ctx)
+	environment.GetNatsServerPort(ctx)
+	environment.GetGlobalDomain(ctx)
+	environment.GetTraceCollectorHost(ctx)
+	environment.GetTraceCollectorPort(ctx)
+	environment.GetMessagePublicationErrorReason(ctx)
+	environment.GetDBSelectErrorReason(ctx)
+	environment.GetDBDeleteErrorReason(ctx)
+	environment.GetDBInsertErrorReason(ctx)
+	environment.GetDBUpdateErrorReason(ctx)
+	environment.GetEtagMismatchErrorReason(ctx)
+	environment.GetRecurringExpensesSubject("foo")
+	environment.GetRecurringExpenseSubject("foo", "bar")
+	environment.GetRecurringExpenseCreatedSubject("foo", "bar")
+	environment.GetRecurringExpenseDeletedSubject("foo", "bar")
+	environment.GetRecurringExpenseUpdatedSubject("foo", "bar")
+
+	dbConfig, err := client.ConfigFromEnvironment(ctx)
+	if err != nil {
+		log.Panic(
+			"failed reading database configuration",
+			logging.Error(err))
+	}
+
+	svc, err := recurringexpense.NewRecurringExpenseServer(
+		ctx,
+		fmt.Sprintf("%s:%d",
+			environment.GetNatsServerHost(ctx),
+			environment.GetNatsServerPort(ctx)),
+		dbConfig)
+	if err != nil {
+		log.Panic(
+			"failed creating new recurring expense server",
+			logging.Error(err),
+		)
+	}
+	defer svc.Close()
+
+	serverAddress := fmt.Sprintf(":%d", environment.GetRecurringexpenseServerPort(ctx))
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	err = server.ListenAndServe[recurringexpensev1connect.RecurringExpenseServiceHandler](
+		ctx,
+		serverAddress,
+		svc,
+		recurringexpensev1.RegisterRecurringExpenseServiceHandler,
+		recurringexpensev1connect.NewRecurringExpenseServiceHandler,
+		serviceName,
+		fmt.Sprintf("%s:%d",
+			environment.GetTraceCollectorHost(ctx),
+			environment.GetTraceCollectorPort(ctx)))
+	if err != nil {
+		log.Panic(
+			"failed running server",
+			logging.Error(err))
+	}
+}
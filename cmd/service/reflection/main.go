@@ -10,13 +10,24 @@ import (
 	"connectrpc.com/connect"
 	grpcreflect "connectrpc.com/grpcreflect"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/activity/v1/activityv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/attachment/v1/attachmentv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/budget/v1/budgetv1connect"
 	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/category/v1/categoryv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/comment/v1/commentv1connect"
 	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/currency/v1/currencyv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/debtreminder/v1/debtreminderv1connect"
 	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expense/v1/expensev1connect"
 	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensecategoryrelation/v1/expensecategoryrelationv1connect"
 	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensestake/v1/expensestakev1connect"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/export/v1/exportv1connect"
 	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/group/v1/groupv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/importer/v1/importerv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/notification/v1/notificationv1connect"
 	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/person/v1/personv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/recurringexpense/v1/recurringexpensev1connect"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/report/v1/reportv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/webhook/v1/webhookv1connect"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/server"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
@@ -36,13 +47,24 @@ func main() {
 	serverAddress := fmt.Sprintf(":%d", environment.GetReflectionServerPort(ctx))
 
 	svc := grpcreflect.NewStaticReflector(
+		activityv1connect.ActivityServiceName,
+		attachmentv1connect.AttachmentServiceName,
+		budgetv1connect.BudgetServiceName,
 		categoryv1connect.CategoryServiceName,
+		commentv1connect.CommentServiceName,
 		currencyv1connect.CurrencyServiceName,
+		debtreminderv1connect.DebtReminderServiceName,
 		expensev1connect.ExpenseServiceName,
 		expensecategoryrelationv1connect.ExpenseCategoryRelationServiceName,
 		expensestakev1connect.ExpenseStakeServiceName,
+		exportv1connect.ExportServiceName,
 		groupv1connect.GroupServiceName,
+		importerv1connect.ImporterServiceName,
+		notificationv1connect.NotificationServiceName,
 		personv1connect.PersonServiceName,
+		recurringexpensev1connect.RecurringExpenseServiceName,
+		reportv1connect.ReportServiceName,
+		webhookv1connect.WebhookServiceName,
 	)
 
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
@@ -0,0 +1,25 @@
+DROP TABLE IF EXISTS people;
+
+--bun:split
+
+DROP TABLE IF EXISTS groups;
+
+--bun:split
+
+DROP TABLE IF EXISTS expense_stakes;
+
+--bun:split
+
+DROP TABLE IF EXISTS expense_category_relations;
+
+--bun:split
+
+DROP TABLE IF EXISTS expenses;
+
+--bun:split
+
+DROP TABLE IF EXISTS currencies;
+
+--bun:split
+
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,65 @@
+CREATE TABLE IF NOT EXISTS categories (
+	id text NOT NULL,
+	group_id text NOT NULL,
+	name text NOT NULL,
+	PRIMARY KEY (id)
+);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS currencies (
+	id text NOT NULL,
+	acronym text NOT NULL,
+	name text NOT NULL,
+	deprecated boolean NOT NULL DEFAULT false,
+	PRIMARY KEY (id)
+);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS expenses (
+	id text NOT NULL,
+	group_id text NOT NULL,
+	name text,
+	by_id text NOT NULL,
+	timestamp timestamptz NOT NULL,
+	currency_id text NOT NULL,
+	PRIMARY KEY (id)
+);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS expense_category_relations (
+	expense_id text NOT NULL,
+	category_id text NOT NULL,
+	PRIMARY KEY (expense_id, category_id)
+);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS expense_stakes (
+	id text NOT NULL,
+	expense_id text NOT NULL,
+	for_id text NOT NULL,
+	main_value integer NOT NULL,
+	fractional_value smallint,
+	PRIMARY KEY (id)
+);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS groups (
+	id text NOT NULL,
+	name text NOT NULL,
+	currency_id text NOT NULL,
+	PRIMARY KEY (id)
+);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS people (
+	id text NOT NULL,
+	group_id text NOT NULL,
+	name text NOT NULL,
+	PRIMARY KEY (id)
+);
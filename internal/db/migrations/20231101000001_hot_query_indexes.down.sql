@@ -0,0 +1,37 @@
+DROP INDEX IF EXISTS people_group_id_idx;
+
+--bun:split
+
+DROP INDEX IF EXISTS groups_currency_id_idx;
+
+--bun:split
+
+DROP INDEX IF EXISTS expense_stakes_for_id_idx;
+
+--bun:split
+
+DROP INDEX IF EXISTS expense_stakes_expense_id_idx;
+
+--bun:split
+
+DROP INDEX IF EXISTS expense_category_relations_category_id_idx;
+
+--bun:split
+
+DROP INDEX IF EXISTS expenses_currency_id_idx;
+
+--bun:split
+
+DROP INDEX IF EXISTS expenses_by_id_idx;
+
+--bun:split
+
+DROP INDEX IF EXISTS expenses_group_id_idx;
+
+--bun:split
+
+DROP INDEX IF EXISTS categories_group_id_idx;
+
+--bun:split
+
+DROP INDEX IF EXISTS currencies_acronym_idx;
@@ -0,0 +1,38 @@
+-- no foreign keys since we do not want to rely on Postgres features; the indexes cover the lookups of the services and processors
+CREATE UNIQUE INDEX IF NOT EXISTS currencies_acronym_idx ON currencies (acronym);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS categories_group_id_idx ON categories (group_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS expenses_group_id_idx ON expenses (group_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS expenses_by_id_idx ON expenses (by_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS expenses_currency_id_idx ON expenses (currency_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS expense_category_relations_category_id_idx ON expense_category_relations (category_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS expense_stakes_expense_id_idx ON expense_stakes (expense_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS expense_stakes_for_id_idx ON expense_stakes (for_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS groups_currency_id_idx ON groups (currency_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS people_group_id_idx ON people (group_id);
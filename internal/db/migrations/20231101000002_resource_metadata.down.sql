@@ -0,0 +1,109 @@
+ALTER TABLE people DROP COLUMN last_modifier;
+
+--bun:split
+
+ALTER TABLE people DROP COLUMN creator;
+
+--bun:split
+
+ALTER TABLE people DROP COLUMN update_time;
+
+--bun:split
+
+ALTER TABLE people DROP COLUMN create_time;
+
+--bun:split
+
+ALTER TABLE groups DROP COLUMN last_modifier;
+
+--bun:split
+
+ALTER TABLE groups DROP COLUMN creator;
+
+--bun:split
+
+ALTER TABLE groups DROP COLUMN update_time;
+
+--bun:split
+
+ALTER TABLE groups DROP COLUMN create_time;
+
+--bun:split
+
+ALTER TABLE expense_stakes DROP COLUMN last_modifier;
+
+--bun:split
+
+ALTER TABLE expense_stakes DROP COLUMN creator;
+
+--bun:split
+
+ALTER TABLE expense_stakes DROP COLUMN update_time;
+
+--bun:split
+
+ALTER TABLE expense_stakes DROP COLUMN create_time;
+
+--bun:split
+
+ALTER TABLE expense_category_relations DROP COLUMN last_modifier;
+
+--bun:split
+
+ALTER TABLE expense_category_relations DROP COLUMN creator;
+
+--bun:split
+
+ALTER TABLE expense_category_relations DROP COLUMN update_time;
+
+--bun:split
+
+ALTER TABLE expense_category_relations DROP COLUMN create_time;
+
+--bun:split
+
+ALTER TABLE expenses DROP COLUMN last_modifier;
+
+--bun:split
+
+ALTER TABLE expenses DROP COLUMN creator;
+
+--bun:split
+
+ALTER TABLE expenses DROP COLUMN update_time;
+
+--bun:split
+
+ALTER TABLE expenses DROP COLUMN create_time;
+
+--bun:split
+
+ALTER TABLE currencies DROP COLUMN last_modifier;
+
+--bun:split
+
+ALTER TABLE currencies DROP COLUMN creator;
+
+--bun:split
+
+ALTER TABLE currencies DROP COLUMN update_time;
+
+--bun:split
+
+ALTER TABLE currencies DROP COLUMN create_time;
+
+--bun:split
+
+ALTER TABLE categories DROP COLUMN last_modifier;
+
+--bun:split
+
+ALTER TABLE categories DROP COLUMN creator;
+
+--bun:split
+
+ALTER TABLE categories DROP COLUMN update_time;
+
+--bun:split
+
+ALTER TABLE categories DROP COLUMN create_time;
@@ -0,0 +1,139 @@
+-- each column is added by its own statement since SQLite cannot add several at once and cannot default to the current time;
+-- the services set the metadata of new resources so only the existing ones are backfilled
+ALTER TABLE categories ADD COLUMN create_time timestamptz;
+
+--bun:split
+
+ALTER TABLE categories ADD COLUMN update_time timestamptz;
+
+--bun:split
+
+ALTER TABLE categories ADD COLUMN creator text NOT NULL DEFAULT '';
+
+--bun:split
+
+ALTER TABLE categories ADD COLUMN last_modifier text NOT NULL DEFAULT '';
+
+--bun:split
+
+UPDATE categories SET create_time = CURRENT_TIMESTAMP, update_time = CURRENT_TIMESTAMP WHERE create_time IS NULL;
+
+--bun:split
+
+ALTER TABLE currencies ADD COLUMN create_time timestamptz;
+
+--bun:split
+
+ALTER TABLE currencies ADD COLUMN update_time timestamptz;
+
+--bun:split
+
+ALTER TABLE currencies ADD COLUMN creator text NOT NULL DEFAULT '';
+
+--bun:split
+
+ALTER TABLE currencies ADD COLUMN last_modifier text NOT NULL DEFAULT '';
+
+--bun:split
+
+UPDATE currencies SET create_time = CURRENT_TIMESTAMP, update_time = CURRENT_TIMESTAMP WHERE create_time IS NULL;
+
+--bun:split
+
+ALTER TABLE expenses ADD COLUMN create_time timestamptz;
+
+--bun:split
+
+ALTER TABLE expenses ADD COLUMN update_time timestamptz;
+
+--bun:split
+
+ALTER TABLE expenses ADD COLUMN creator text NOT NULL DEFAULT '';
+
+--bun:split
+
+ALTER TABLE expenses ADD COLUMN last_modifier text NOT NULL DEFAULT '';
+
+--bun:split
+
+UPDATE expenses SET create_time = CURRENT_TIMESTAMP, update_time = CURRENT_TIMESTAMP WHERE create_time IS NULL;
+
+--bun:split
+
+ALTER TABLE expense_category_relations ADD COLUMN create_time timestamptz;
+
+--bun:split
+
+ALTER TABLE expense_category_relations ADD COLUMN update_time timestamptz;
+
+--bun:split
+
+ALTER TABLE expense_category_relations ADD COLUMN creator text NOT NULL DEFAULT '';
+
+--bun:split
+
+ALTER TABLE expense_category_relations ADD COLUMN last_modifier text NOT NULL DEFAULT '';
+
+--bun:split
+
+UPDATE expense_category_relations SET create_time = CURRENT_TIMESTAMP, update_time = CURRENT_TIMESTAMP WHERE create_time IS NULL;
+
+--bun:split
+
+ALTER TABLE expense_stakes ADD COLUMN create_time timestamptz;
+
+--bun:split
+
+ALTER TABLE expense_stakes ADD COLUMN update_time timestamptz;
+
+--bun:split
+
+ALTER TABLE expense_stakes ADD COLUMN creator text NOT NULL DEFAULT '';
+
+--bun:split
+
+ALTER TABLE expense_stakes ADD COLUMN last_modifier text NOT NULL DEFAULT '';
+
+--bun:split
+
+UPDATE expense_stakes SET create_time = CURRENT_TIMESTAMP, update_time = CURRENT_TIMESTAMP WHERE create_time IS NULL;
+
+--bun:split
+
+ALTER TABLE groups ADD COLUMN create_time timestamptz;
+
+--bun:split
+
+ALTER TABLE groups ADD COLUMN update_time timestamptz;
+
+--bun:split
+
+ALTER TABLE groups ADD COLUMN creator text NOT NULL DEFAULT '';
+
+--bun:split
+
+ALTER TABLE groups ADD COLUMN last_modifier text NOT NULL DEFAULT '';
+
+--bun:split
+
+UPDATE groups SET create_time = CURRENT_TIMESTAMP, update_time = CURRENT_TIMESTAMP WHERE create_time IS NULL;
+
+--bun:split
+
+ALTER TABLE people ADD COLUMN create_time timestamptz;
+
+--bun:split
+
+ALTER TABLE people ADD COLUMN update_time timestamptz;
+
+--bun:split
+
+ALTER TABLE people ADD COLUMN creator text NOT NULL DEFAULT '';
+
+--bun:split
+
+ALTER TABLE people ADD COLUMN last_modifier text NOT NULL DEFAULT '';
+
+--bun:split
+
+UPDATE people SET create_time = CURRENT_TIMESTAMP, update_time = CURRENT_TIMESTAMP WHERE create_time IS NULL;
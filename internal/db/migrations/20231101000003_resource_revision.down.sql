@@ -0,0 +1,13 @@
+ALTER TABLE people DROP COLUMN revision;
+
+--bun:split
+
+ALTER TABLE groups DROP COLUMN revision;
+
+--bun:split
+
+ALTER TABLE expenses DROP COLUMN revision;
+
+--bun:split
+
+ALTER TABLE categories DROP COLUMN revision;
@@ -0,0 +1,14 @@
+-- the revision backs the etag of the resources that can be updated; existing resources start at the first revision
+ALTER TABLE categories ADD COLUMN revision bigint NOT NULL DEFAULT 1;
+
+--bun:split
+
+ALTER TABLE expenses ADD COLUMN revision bigint NOT NULL DEFAULT 1;
+
+--bun:split
+
+ALTER TABLE groups ADD COLUMN revision bigint NOT NULL DEFAULT 1;
+
+--bun:split
+
+ALTER TABLE people ADD COLUMN revision bigint NOT NULL DEFAULT 1;
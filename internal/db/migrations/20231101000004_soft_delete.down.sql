@@ -0,0 +1,69 @@
+DROP INDEX IF EXISTS people_delete_time_idx;
+
+--bun:split
+
+ALTER TABLE people DROP COLUMN delete_cause;
+
+--bun:split
+
+ALTER TABLE people DROP COLUMN delete_time;
+
+--bun:split
+
+DROP INDEX IF EXISTS groups_delete_time_idx;
+
+--bun:split
+
+ALTER TABLE groups DROP COLUMN delete_cause;
+
+--bun:split
+
+ALTER TABLE groups DROP COLUMN delete_time;
+
+--bun:split
+
+DROP INDEX IF EXISTS expenses_delete_time_idx;
+
+--bun:split
+
+ALTER TABLE expenses DROP COLUMN delete_cause;
+
+--bun:split
+
+ALTER TABLE expenses DROP COLUMN delete_time;
+
+--bun:split
+
+DROP INDEX IF EXISTS expense_stakes_delete_time_idx;
+
+--bun:split
+
+ALTER TABLE expense_stakes DROP COLUMN delete_cause;
+
+--bun:split
+
+ALTER TABLE expense_stakes DROP COLUMN delete_time;
+
+--bun:split
+
+DROP INDEX IF EXISTS expense_category_relations_delete_time_idx;
+
+--bun:split
+
+ALTER TABLE expense_category_relations DROP COLUMN delete_cause;
+
+--bun:split
+
+ALTER TABLE expense_category_relations DROP COLUMN delete_time;
+
+--bun:split
+
+DROP INDEX IF EXISTS categories_delete_time_idx;
+
+--bun:split
+
+ALTER TABLE categories DROP COLUMN delete_cause;
+
+--bun:split
+
+ALTER TABLE categories DROP COLUMN delete_time;
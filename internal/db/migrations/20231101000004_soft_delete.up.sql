@@ -0,0 +1,71 @@
+-- deleted resources are kept as tombstones until they are purged; the delete cause is the ID of the resource
+-- whose deletion deleted the row so that restoring that resource restores exactly what was deleted along with it
+ALTER TABLE categories ADD COLUMN delete_time timestamptz;
+
+--bun:split
+
+ALTER TABLE categories ADD COLUMN delete_cause text;
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS categories_delete_time_idx ON categories (delete_time);
+
+--bun:split
+
+ALTER TABLE expense_category_relations ADD COLUMN delete_time timestamptz;
+
+--bun:split
+
+ALTER TABLE expense_category_relations ADD COLUMN delete_cause text;
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS expense_category_relations_delete_time_idx ON expense_category_relations (delete_time);
+
+--bun:split
+
+ALTER TABLE expense_stakes ADD COLUMN delete_time timestamptz;
+
+--bun:split
+
+ALTER TABLE expense_stakes ADD COLUMN delete_cause text;
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS expense_stakes_delete_time_idx ON expense_stakes (delete_time);
+
+--bun:split
+
+ALTER TABLE expenses ADD COLUMN delete_time timestamptz;
+
+--bun:split
+
+ALTER TABLE expenses ADD COLUMN delete_cause text;
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS expenses_delete_time_idx ON expenses (delete_time);
+
+--bun:split
+
+ALTER TABLE groups ADD COLUMN delete_time timestamptz;
+
+--bun:split
+
+ALTER TABLE groups ADD COLUMN delete_cause text;
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS groups_delete_time_idx ON groups (delete_time);
+
+--bun:split
+
+ALTER TABLE people ADD COLUMN delete_time timestamptz;
+
+--bun:split
+
+ALTER TABLE people ADD COLUMN delete_cause text;
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS people_delete_time_idx ON people (delete_time);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS activities_resource_id_id_idx;
+
+--bun:split
+
+DROP INDEX IF EXISTS activities_group_id_id_idx;
+
+--bun:split
+
+DROP TABLE IF EXISTS activities;
@@ -0,0 +1,24 @@
+-- the activity log is append-only; the snapshot holds the last known state of the resource so that the next activity
+-- concerning the same resource can be diffed against it
+CREATE TABLE IF NOT EXISTS activities (
+	id text NOT NULL,
+	group_id text NOT NULL,
+	actor text NOT NULL,
+	action integer NOT NULL,
+	resource_type integer NOT NULL,
+	resource_id text NOT NULL,
+	expense_id text,
+	before_json text,
+	after_json text,
+	snapshot_json text,
+	create_time timestamptz NOT NULL,
+	PRIMARY KEY (id)
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS activities_group_id_id_idx ON activities (group_id, id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS activities_resource_id_id_idx ON activities (resource_id, id);
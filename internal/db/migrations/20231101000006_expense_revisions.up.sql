@@ -0,0 +1,11 @@
+-- every revision of an expense is stored with its stakes and categories so that the expense can be reverted to it;
+-- expenses existing before this migration get their first revision with their next change
+CREATE TABLE IF NOT EXISTS expense_revisions (
+	expense_id text NOT NULL,
+	revision bigint NOT NULL,
+	group_id text NOT NULL,
+	content_json text NOT NULL,
+	create_time timestamptz NOT NULL,
+	creator text NOT NULL,
+	PRIMARY KEY (expense_id, revision)
+);
@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS recurring_expense_occurrences_published_idx;
+
+--bun:split
+
+DROP TABLE IF EXISTS recurring_expense_occurrences;
+
+--bun:split
+
+DROP INDEX IF EXISTS recurring_expenses_next_occurrence_time_idx;
+
+--bun:split
+
+DROP INDEX IF EXISTS recurring_expenses_group_id_idx;
+
+--bun:split
+
+DROP TABLE IF EXISTS recurring_expenses;
@@ -0,0 +1,47 @@
+-- the next occurrence is stored rather than derived so that the scheduler can select the due recurring expenses;
+-- it is NULL while a recurring expense is paused or after it ended
+CREATE TABLE IF NOT EXISTS recurring_expenses (
+	id text NOT NULL,
+	group_id text NOT NULL,
+	name text,
+	by_id text NOT NULL,
+	currency_id text NOT NULL,
+	stakes_json text NOT NULL,
+	frequency integer NOT NULL,
+	schedule_interval integer NOT NULL,
+	start_time timestamptz NOT NULL,
+	end_time timestamptz,
+	paused boolean NOT NULL DEFAULT false,
+	next_occurrence_time timestamptz,
+	create_time timestamptz,
+	update_time timestamptz,
+	creator text NOT NULL DEFAULT '',
+	last_modifier text NOT NULL DEFAULT '',
+	revision bigint NOT NULL DEFAULT 1,
+	PRIMARY KEY (id)
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS recurring_expenses_group_id_idx ON recurring_expenses (group_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS recurring_expenses_next_occurrence_time_idx ON recurring_expenses (next_occurrence_time);
+
+--bun:split
+
+-- every materialized occurrence is recorded in the transaction creating its expense so that no occurrence is materialized twice;
+-- the published flag makes the scheduler publish the events of occurrences whose publication was interrupted
+CREATE TABLE IF NOT EXISTS recurring_expense_occurrences (
+	recurring_expense_id text NOT NULL,
+	occurrence_time timestamptz NOT NULL,
+	group_id text NOT NULL,
+	expense_id text NOT NULL,
+	published boolean NOT NULL DEFAULT false,
+	PRIMARY KEY (recurring_expense_id, occurrence_time)
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS recurring_expense_occurrences_published_idx ON recurring_expense_occurrences (published);
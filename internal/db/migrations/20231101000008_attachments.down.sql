@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS attachments_delete_time_idx;
+
+--bun:split
+
+DROP INDEX IF EXISTS attachments_expense_id_idx;
+
+--bun:split
+
+DROP TABLE IF EXISTS attachments;
@@ -0,0 +1,24 @@
+-- the content of an attachment is kept in the blob store under the ID of the attachment
+CREATE TABLE IF NOT EXISTS attachments (
+	id text NOT NULL,
+	expense_id text NOT NULL,
+	group_id text NOT NULL,
+	file_name text NOT NULL,
+	content_type text NOT NULL,
+	size bigint NOT NULL,
+	create_time timestamptz,
+	update_time timestamptz,
+	creator text NOT NULL DEFAULT '',
+	last_modifier text NOT NULL DEFAULT '',
+	delete_time timestamptz,
+	delete_cause text,
+	PRIMARY KEY (id)
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS attachments_expense_id_idx ON attachments (expense_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS attachments_delete_time_idx ON attachments (delete_time);
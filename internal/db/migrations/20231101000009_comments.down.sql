@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS comment_revisions;
+
+--bun:split
+
+DROP INDEX IF EXISTS comments_delete_time_idx;
+
+--bun:split
+
+DROP INDEX IF EXISTS comments_expense_id_idx;
+
+--bun:split
+
+DROP TABLE IF EXISTS comments;
@@ -0,0 +1,39 @@
+-- the mentioned persons are kept in an array since they are only ever read along with the comment
+CREATE TABLE IF NOT EXISTS comments (
+	id text NOT NULL,
+	expense_id text NOT NULL,
+	group_id text NOT NULL,
+	author_id text NOT NULL,
+	text text NOT NULL,
+	mentioned_person_ids text[],
+	revision bigint NOT NULL DEFAULT 1,
+	create_time timestamptz,
+	update_time timestamptz,
+	creator text NOT NULL DEFAULT '',
+	last_modifier text NOT NULL DEFAULT '',
+	delete_time timestamptz,
+	delete_cause text,
+	PRIMARY KEY (id)
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS comments_expense_id_idx ON comments (expense_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS comments_delete_time_idx ON comments (delete_time);
+
+--bun:split
+
+-- every version of a comment is kept as its edit history until the comment is deleted
+CREATE TABLE IF NOT EXISTS comment_revisions (
+	comment_id text NOT NULL,
+	revision bigint NOT NULL,
+	group_id text NOT NULL,
+	text text NOT NULL,
+	mentioned_person_ids text[],
+	create_time timestamptz NOT NULL,
+	creator text NOT NULL,
+	PRIMARY KEY (comment_id, revision)
+);
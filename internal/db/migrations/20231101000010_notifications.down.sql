@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS pending_notifications_principal_idx;
+
+--bun:split
+
+DROP TABLE IF EXISTS pending_notifications;
+
+--bun:split
+
+DROP INDEX IF EXISTS notification_preference_people_group_id_idx;
+
+--bun:split
+
+DROP TABLE IF EXISTS notification_preference_people;
+
+--bun:split
+
+DROP TABLE IF EXISTS notification_preferences;
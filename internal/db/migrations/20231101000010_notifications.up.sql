@@ -0,0 +1,48 @@
+-- the event types are kept in an array since they are only ever read along with the preference
+CREATE TABLE IF NOT EXISTS notification_preferences (
+	principal text NOT NULL,
+	email text NOT NULL,
+	locale text NOT NULL,
+	event_types integer[],
+	delivery integer NOT NULL,
+	digest_hour integer NOT NULL DEFAULT 0,
+	time_zone text NOT NULL,
+	last_digest_time timestamptz,
+	revision bigint NOT NULL DEFAULT 1,
+	create_time timestamptz,
+	update_time timestamptz,
+	creator text NOT NULL DEFAULT '',
+	last_modifier text NOT NULL DEFAULT '',
+	PRIMARY KEY (principal)
+);
+
+--bun:split
+
+-- the persons representing a user in their groups are kept in a table of their own so that the recipients of an event can be selected by group
+CREATE TABLE IF NOT EXISTS notification_preference_people (
+	principal text NOT NULL,
+	person_id text NOT NULL,
+	group_id text NOT NULL,
+	PRIMARY KEY (principal, person_id)
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS notification_preference_people_group_id_idx ON notification_preference_people (group_id);
+
+--bun:split
+
+-- the notifications collected for the next daily digest of a user
+CREATE TABLE IF NOT EXISTS pending_notifications (
+	id text NOT NULL,
+	principal text NOT NULL,
+	group_id text NOT NULL,
+	event_type integer NOT NULL,
+	summary text NOT NULL,
+	create_time timestamptz NOT NULL,
+	PRIMARY KEY (id)
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS pending_notifications_principal_idx ON pending_notifications (principal);
@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS webhook_deliveries_next_attempt_time_idx;
+
+--bun:split
+
+DROP INDEX IF EXISTS webhook_deliveries_webhook_id_activity_id_idx;
+
+--bun:split
+
+DROP TABLE IF EXISTS webhook_deliveries;
+
+--bun:split
+
+DROP INDEX IF EXISTS webhooks_group_id_idx;
+
+--bun:split
+
+DROP TABLE IF EXISTS webhooks;
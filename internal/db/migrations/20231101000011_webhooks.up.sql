@@ -0,0 +1,51 @@
+-- the filter is kept in arrays since it is only ever read along with the webhook
+CREATE TABLE IF NOT EXISTS webhooks (
+	id text NOT NULL,
+	group_id text NOT NULL,
+	url text NOT NULL,
+	secret text NOT NULL,
+	resource_types integer[],
+	actions integer[],
+	disabled boolean NOT NULL DEFAULT false,
+	disabled_reason text NOT NULL DEFAULT '',
+	consecutive_failures integer NOT NULL DEFAULT 0,
+	revision bigint NOT NULL DEFAULT 1,
+	create_time timestamptz,
+	update_time timestamptz,
+	creator text NOT NULL DEFAULT '',
+	last_modifier text NOT NULL DEFAULT '',
+	PRIMARY KEY (id)
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS webhooks_group_id_idx ON webhooks (group_id);
+
+--bun:split
+
+-- the deliveries of activities to webhooks which make up the delivery log of a webhook
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+	id text NOT NULL,
+	webhook_id text NOT NULL,
+	group_id text NOT NULL,
+	activity_id text NOT NULL,
+	event text NOT NULL,
+	payload text NOT NULL,
+	status integer NOT NULL,
+	attempts integer NOT NULL DEFAULT 0,
+	response_status integer NOT NULL DEFAULT 0,
+	error text NOT NULL DEFAULT '',
+	create_time timestamptz NOT NULL,
+	last_attempt_time timestamptz,
+	next_attempt_time timestamptz,
+	PRIMARY KEY (id)
+);
+
+--bun:split
+
+-- an activity is delivered to a webhook only once even if its event is redelivered
+CREATE UNIQUE INDEX IF NOT EXISTS webhook_deliveries_webhook_id_activity_id_idx ON webhook_deliveries (webhook_id, activity_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_next_attempt_time_idx ON webhook_deliveries (next_attempt_time);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS debt_reminders;
+
+--bun:split
+
+DROP TABLE IF EXISTS debt_reminder_snoozes;
+
+--bun:split
+
+DROP TABLE IF EXISTS debt_reminder_policies;
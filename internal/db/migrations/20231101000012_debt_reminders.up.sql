@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS debt_reminder_policies (
+	group_id text NOT NULL,
+	threshold_main_value integer NOT NULL DEFAULT 0,
+	threshold_fractional_value integer NOT NULL DEFAULT 0,
+	min_age_days integer NOT NULL,
+	repeat_interval_days integer NOT NULL,
+	revision bigint NOT NULL DEFAULT 1,
+	create_time timestamptz,
+	update_time timestamptz,
+	creator text NOT NULL DEFAULT '',
+	last_modifier text NOT NULL DEFAULT '',
+	PRIMARY KEY (group_id)
+);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS debt_reminder_snoozes (
+	group_id text NOT NULL,
+	person_id text NOT NULL,
+	snooze_until timestamptz NOT NULL,
+	create_time timestamptz,
+	update_time timestamptz,
+	creator text NOT NULL DEFAULT '',
+	last_modifier text NOT NULL DEFAULT '',
+	PRIMARY KEY (group_id, person_id)
+);
+
+--bun:split
+
+-- the time each person was last reminded at so that the reminders of a debt that is still outstanding are repeated at the interval of the policy
+CREATE TABLE IF NOT EXISTS debt_reminders (
+	group_id text NOT NULL,
+	person_id text NOT NULL,
+	remind_time timestamptz NOT NULL,
+	PRIMARY KEY (group_id, person_id)
+);
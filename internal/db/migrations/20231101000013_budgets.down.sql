@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS budget_alerts;
+
+--bun:split
+
+DROP INDEX IF EXISTS budgets_category_id_idx;
+
+--bun:split
+
+DROP INDEX IF EXISTS budgets_group_id_idx;
+
+--bun:split
+
+DROP TABLE IF EXISTS budgets;
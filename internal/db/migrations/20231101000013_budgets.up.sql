@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS budgets (
+	id text NOT NULL,
+	group_id text NOT NULL,
+	category_id text NOT NULL,
+	period integer NOT NULL,
+	main_value integer NOT NULL,
+	fractional_value integer NOT NULL DEFAULT 0,
+	start_time timestamptz,
+	end_time timestamptz,
+	revision bigint NOT NULL DEFAULT 1,
+	create_time timestamptz,
+	update_time timestamptz,
+	creator text NOT NULL DEFAULT '',
+	last_modifier text NOT NULL DEFAULT '',
+	PRIMARY KEY (id)
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS budgets_group_id_idx ON budgets (group_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS budgets_category_id_idx ON budgets (category_id);
+
+--bun:split
+
+-- the thresholds each budget reached per period so that reaching a threshold is only alerted once per period
+CREATE TABLE IF NOT EXISTS budget_alerts (
+	budget_id text NOT NULL,
+	period_start_time timestamptz NOT NULL,
+	threshold integer NOT NULL,
+	create_time timestamptz NOT NULL,
+	PRIMARY KEY (budget_id, period_start_time, threshold)
+);
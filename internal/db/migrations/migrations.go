@@ -0,0 +1,154 @@
+package migrations
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+// sqlMigrations holds the versioned migrations named <version>_<comment>.up.sql and <version>_<comment>.down.sql
+//
+//go:embed *.sql
+var sqlMigrations embed.FS
+
+var (
+	errDiscoverMigrations = eris.New("failed discovering migrations")
+	errInitMigrations     = eris.New("failed initialising the migration tables")
+	errLockMigrations     = eris.New("failed locking the migration tables")
+	errSelectMigrations   = eris.New("failed selecting the migration status")
+	errApplyMigrations    = eris.New("failed applying migrations")
+	errRollbackMigrations = eris.New("failed rolling back migrations")
+	errReadMigration      = eris.New("failed reading migration")
+)
+
+// Migrator applies and rolls back the schema migrations of the expense splitter database
+type Migrator struct {
+	migrator *migrate.Migrator
+}
+
+// PendingMigration is a migration that would be applied or rolled back along with the SQL it would execute
+type PendingMigration struct {
+	migrate.Migration
+	SQL string
+}
+
+// NewMigrator creates a migrator for the embedded migrations and the passed database
+func NewMigrator(db *bun.DB) (*Migrator, error) {
+	migrations := migrate.NewMigrations()
+	if err := migrations.Discover(sqlMigrations); err != nil {
+		return nil, eris.Wrap(errDiscoverMigrations, err.Error())
+	}
+	return &Migrator{
+		migrator: migrate.NewMigrator(db, migrations, migrate.WithMarkAppliedOnSuccess(true)),
+	}, nil
+}
+
+// Status returns all known migrations, creating the migration tables if missing; applied migrations have a non-zero ID and group ID
+func (m *Migrator) Status(ctx context.Context) (migrate.MigrationSlice, error) {
+	if err := m.migrator.Init(ctx); err != nil {
+		logging.FromContext(ctx).Error("failed initialising the migration tables", logging.Error(err))
+		return nil, eris.Wrap(errInitMigrations, err.Error())
+	}
+	migrations, err := m.migrator.MigrationsWithStatus(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed selecting the migration status", logging.Error(err))
+		return nil, eris.Wrap(errSelectMigrations, err.Error())
+	}
+	return migrations, nil
+}
+
+// Up applies all pending migrations as a new group. With dryRun the pending migrations are returned without being applied.
+func (m *Migrator) Up(ctx context.Context, dryRun bool) ([]PendingMigration, error) {
+	log := logging.FromContext(ctx)
+
+	migrations, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := withSQL(migrations.Unapplied(), "up")
+	if err != nil {
+		log.Error("failed reading pending migrations", logging.Error(err))
+		return nil, err
+	}
+	if dryRun || len(pending) == 0 {
+		return pending, nil
+	}
+
+	err = m.locked(ctx, func() error {
+		_, err := m.migrator.Migrate(ctx)
+		return err
+	})
+	if err != nil {
+		log.Error("failed applying migrations", logging.Error(err))
+		return nil, eris.Wrap(errApplyMigrations, err.Error())
+	}
+	return pending, nil
+}
+
+// Down rolls back the most recently applied group of migrations. With dryRun the migrations are returned without being rolled back.
+func (m *Migrator) Down(ctx context.Context, dryRun bool) ([]PendingMigration, error) {
+	log := logging.FromContext(ctx)
+
+	migrations, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lastGroup := migrations.LastGroup()
+	rollback := make(migrate.MigrationSlice, 0, len(lastGroup.Migrations))
+	for i := len(lastGroup.Migrations) - 1; i >= 0; i-- {
+		rollback = append(rollback, lastGroup.Migrations[i])
+	}
+	pending, err := withSQL(rollback, "down")
+	if err != nil {
+		log.Error("failed reading migrations to roll back", logging.Error(err))
+		return nil, err
+	}
+	if dryRun || len(pending) == 0 {
+		return pending, nil
+	}
+
+	err = m.locked(ctx, func() error {
+		_, err := m.migrator.Rollback(ctx)
+		return err
+	})
+	if err != nil {
+		log.Error("failed rolling back migrations", logging.Error(err))
+		return nil, eris.Wrap(errRollbackMigrations, err.Error())
+	}
+	return pending, nil
+}
+
+// locked runs f while holding the migration lock so concurrently started migrations fail instead of interfering
+func (m *Migrator) locked(ctx context.Context, f func() error) error {
+	if err := m.migrator.Lock(ctx); err != nil {
+		return eris.Wrap(errLockMigrations, err.Error())
+	}
+	defer func() {
+		if err := m.migrator.Unlock(ctx); err != nil {
+			logging.FromContext(ctx).Error("failed unlocking the migration tables", logging.Error(err))
+		}
+	}()
+	return f()
+}
+
+// withSQL attaches the SQL of the passed direction ("up" or "down") to the migrations
+func withSQL(migrations migrate.MigrationSlice, direction string) ([]PendingMigration, error) {
+	pending := make([]PendingMigration, len(migrations))
+	for i, migration := range migrations {
+		sql, err := fs.ReadFile(sqlMigrations, fmt.Sprintf("%s_%s.%s.sql", migration.Name, migration.Comment, direction))
+		if err != nil {
+			return nil, eris.Wrapf(errReadMigration, "%s: %s", migration.String(), err.Error())
+		}
+		pending[i] = PendingMigration{
+			Migration: migration,
+			SQL:       string(sql),
+		}
+	}
+	return pending, nil
+}
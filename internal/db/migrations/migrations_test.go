@@ -0,0 +1,118 @@
+package migrations_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/migrations"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestMigrator(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	migrator, err := migrations.NewMigrator(bun.NewDB(db, pgdialect.New()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Dry run migrating up lists all pending migrations", func(t *testing.T) {
+		mock.ExpectExec(`CREATE TABLE IF NOT EXISTS bun_migrations (.+)`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`CREATE TABLE IF NOT EXISTS bun_migration_locks (.+)`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`SELECT (.+) FROM bun_migrations(.*)`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "group_id", "migrated_at"}))
+
+		pending, err := migrator.Up(context.Background(), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pending) < 2 {
+			t.Fatalf("expected at least the initial schema and the index migration to be pending but got %d migrations", len(pending))
+		}
+		if pending[0].Comment != "initial_schema" {
+			t.Errorf("expected the initial schema to be migrated first but got %s", pending[0].String())
+		}
+		for _, migration := range pending {
+			if strings.TrimSpace(migration.SQL) == "" {
+				t.Errorf("expected migration %s to have SQL", migration.String())
+			}
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Dry run migrating down lists the last group in reverse order", func(t *testing.T) {
+		mock.ExpectExec(`CREATE TABLE IF NOT EXISTS bun_migrations (.+)`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`CREATE TABLE IF NOT EXISTS bun_migration_locks (.+)`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`SELECT (.+) FROM bun_migrations(.*)`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "group_id", "migrated_at"}).
+				AddRow(1, "20231101000000", 1, "2023-11-01 00:00:00").
+				AddRow(2, "20231101000001", 1, "2023-11-01 00:00:00"))
+
+		rollback, err := migrator.Down(context.Background(), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rollback) != 2 {
+			t.Fatalf("expected 2 migrations to be rolled back but got %d", len(rollback))
+		}
+		if rollback[0].Name != "20231101000001" || rollback[1].Name != "20231101000000" {
+			t.Errorf("expected the migrations to be rolled back in reverse order but got %s and %s", rollback[0].String(), rollback[1].String())
+		}
+		if !strings.Contains(rollback[1].SQL, "DROP TABLE") {
+			t.Errorf("expected the initial schema to be rolled back by dropping tables")
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+}
+
+func TestMigratorSQLite(t *testing.T) {
+	db, err := client.NewSQLiteDBClient("file::memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	t.Run("Migrating up creates the schema on the embedded database", func(t *testing.T) {
+		applied, err := migrator.Up(ctx, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(applied) < 2 {
+			t.Fatalf("expected at least 2 migrations to be applied but got %d", len(applied))
+		}
+		if _, err := db.ExecContext(ctx, "INSERT INTO groups (id, name, currency_id) VALUES ('group-1', 'Holiday', 'currency-1')"); err != nil {
+			t.Errorf("expected the groups table to exist: %+v", err)
+		}
+	})
+
+	t.Run("Migrating down drops the schema on the embedded database", func(t *testing.T) {
+		if _, err := migrator.Down(ctx, false); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.ExecContext(ctx, "SELECT id FROM groups"); err == nil {
+			t.Error("expected the groups table to be dropped")
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	activityv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/activity/v1"
+	"github.com/rotisserie/eris"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Activity is an entry of the activity log of a group. Besides the changed fields of the resource it stores a snapshot of
+// the resource after the change which the next activity concerning the same resource is diffed against.
+type Activity struct {
+	activityv1.Activity
+	CreateTime   *Timestamp
+	BeforeJson   string `bun:",nullzero"`
+	AfterJson    string `bun:",nullzero"`
+	SnapshotJson string `bun:",nullzero"`
+}
+
+// NewActivity returns an activity recorded at the passed time with the passed changed fields and snapshot of the resource
+func NewActivity(activity *activityv1.Activity, before, after, snapshot map[string]interface{}, t time.Time) (*Activity, error) {
+	a := &Activity{
+		Activity: activityv1.Activity{
+			Id:           activity.GetId(),
+			GroupId:      activity.GetGroupId(),
+			Actor:        activity.GetActor(),
+			Action:       activity.GetAction(),
+			ResourceType: activity.GetResourceType(),
+			ResourceId:   activity.GetResourceId(),
+			ExpenseId:    activity.GetExpenseId(),
+		},
+		CreateTime: NewTimestamp(timestamppb.New(t)),
+	}
+	var err error
+	if a.BeforeJson, err = marshalFields(before); err != nil {
+		return nil, err
+	}
+	if a.AfterJson, err = marshalFields(after); err != nil {
+		return nil, err
+	}
+	if a.SnapshotJson, err = marshalFields(snapshot); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Snapshot returns the fields of the resource after the activity or nil if the activity has no snapshot
+func (a *Activity) Snapshot() (map[string]interface{}, error) {
+	return unmarshalFields(a.SnapshotJson)
+}
+
+func (a *Activity) IntoProtoActivity() (*activityv1.Activity, error) {
+	if a.CreateTime != nil {
+		a.Activity.CreateTime = a.CreateTime.IntoProtoTimestamp()
+	}
+	var err error
+	if a.Activity.Before, err = unmarshalStruct(a.BeforeJson); err != nil {
+		return nil, err
+	}
+	if a.Activity.After, err = unmarshalStruct(a.AfterJson); err != nil {
+		return nil, err
+	}
+	return &a.Activity, nil
+}
+
+func marshalFields(fields map[string]interface{}) (string, error) {
+	if fields == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return "", eris.Wrap(err, "failed marshalling fields")
+	}
+	return string(data), nil
+}
+
+func unmarshalFields(data string) (map[string]interface{}, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return nil, eris.Wrap(err, "failed unmarshalling fields")
+	}
+	return fields, nil
+}
+
+func unmarshalStruct(data string) (*structpb.Struct, error) {
+	if data == "" {
+		return nil, nil
+	}
+	s := new(structpb.Struct)
+	if err := s.UnmarshalJSON([]byte(data)); err != nil {
+		return nil, eris.Wrap(err, "failed unmarshalling fields")
+	}
+	return s, nil
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	attachmentv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/attachment/v1"
+)
+
+type Attachment struct {
+	attachmentv1.Attachment
+	Metadata
+	Tombstone
+}
+
+func NewAttachment(attachment *attachmentv1.Attachment, metadata Metadata) *Attachment {
+	return &Attachment{
+		Attachment: attachmentv1.Attachment{
+			Id:          attachment.GetId(),
+			ExpenseId:   attachment.GetExpenseId(),
+			GroupId:     attachment.GetGroupId(),
+			FileName:    attachment.GetFileName(),
+			ContentType: attachment.GetContentType(),
+			Size:        attachment.GetSize(),
+		},
+		Metadata: metadata,
+	}
+}
+
+func (a *Attachment) IntoProtoAttachment() *attachmentv1.Attachment {
+	a.Attachment.CreateTime, a.Attachment.UpdateTime, a.Attachment.Creator, a.Attachment.LastModifier = a.Metadata.intoProto()
+	a.Attachment.DeleteTime = a.Tombstone.intoProto()
+	return &a.Attachment
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	budgetv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/budget/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/balance"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/budget"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Budget struct {
+	budgetv1.Budget
+	StartTime *time.Time `bun:",nullzero"`
+	EndTime   *time.Time `bun:",nullzero"`
+	Metadata
+	Revision
+}
+
+// BudgetAlert records that the spending reached a threshold of a budget within the period starting at the period start time
+type BudgetAlert struct {
+	BudgetId        string    `bun:",pk"`
+	PeriodStartTime time.Time `bun:",pk"`
+	Threshold       int32     `bun:",pk"`
+	CreateTime      time.Time
+}
+
+func NewBudget(b *budgetv1.Budget, metadata Metadata) *Budget {
+	r := &Budget{
+		Budget: budgetv1.Budget{
+			Id:              b.GetId(),
+			GroupId:         b.GetGroupId(),
+			CategoryId:      b.GetCategoryId(),
+			Period:          b.GetPeriod(),
+			MainValue:       b.GetMainValue(),
+			FractionalValue: b.GetFractionalValue(),
+		},
+		Metadata: metadata,
+	}
+	if b.GetStartTime() != nil {
+		startTime := b.GetStartTime().AsTime()
+		r.StartTime = &startTime
+	}
+	if b.GetEndTime() != nil {
+		endTime := b.GetEndTime().AsTime()
+		r.EndTime = &endTime
+	}
+	return r
+}
+
+// Amount returns the amount of the budget in minor units of the currency of the group
+func (b *Budget) Amount() int64 {
+	return balance.Amount(b.GetMainValue(), b.GetFractionalValue())
+}
+
+// PeriodAt returns the period of the budget the passed time is in and false if there is none, i.e. the time is outside the trip of a trip budget
+func (b *Budget) PeriodAt(t time.Time) (budget.Period, bool) {
+	if b.GetPeriod() == budgetv1.Budget_PERIOD_TRIP {
+		if b.StartTime == nil || b.EndTime == nil {
+			return budget.Period{}, false
+		}
+		period := budget.Period{
+			Start: *b.StartTime,
+			End:   *b.EndTime,
+		}
+		return period, period.Contains(t)
+	}
+	return budget.Month(t), true
+}
+
+func (b *Budget) IntoProtoBudget() *budgetv1.Budget {
+	if b.StartTime != nil {
+		b.Budget.StartTime = timestamppb.New(*b.StartTime)
+	}
+	if b.EndTime != nil {
+		b.Budget.EndTime = timestamppb.New(*b.EndTime)
+	}
+	b.Budget.CreateTime, b.Budget.UpdateTime, b.Budget.Creator, b.Budget.LastModifier = b.Metadata.intoProto()
+	b.Budget.Etag = b.Revision.Etag()
+	return &b.Budget
+}
+
+// SelectBudgetExpenses returns the expenses of the category of the budget within the period along with the sums of their stakes which
+// are computed by the database. Deleted expenses, stakes and relations do not count towards the budget.
+func SelectBudgetExpenses(ctx context.Context, db bun.IDB, b *Budget, period budget.Period) ([]budget.Expense, error) {
+	var rows []struct {
+		Acronym   string
+		Timestamp time.Time
+		Amount    int64
+	}
+	if err := db.NewSelect().
+		TableExpr("expenses AS e").
+		Join("JOIN expense_category_relations AS r ON r.expense_id = e.id AND r.delete_time IS NULL").
+		Join("JOIN expense_stakes AS s ON s.expense_id = e.id AND s.delete_time IS NULL").
+		Join("JOIN currencies AS c ON c.id = e.currency_id").
+		ColumnExpr("c.acronym, e.timestamp").
+		ColumnExpr("SUM(s.main_value * 100 + COALESCE(s.fractional_value, 0)) AS amount").
+		Where("r.category_id = ?", b.GetCategoryId()).
+		Where("e.group_id = ?", b.GetGroupId()).
+		Where("e.delete_time IS NULL").
+		Where("e.timestamp >= ?", period.Start).
+		Where("e.timestamp < ?", period.End).
+		GroupExpr("e.id, c.acronym, e.timestamp").
+		Scan(ctx, &rows); err != nil {
+		return nil, err
+	}
+	expenses := make([]budget.Expense, 0, len(rows))
+	for _, row := range rows {
+		expenses = append(expenses, budget.Expense{
+			Currency: row.Acronym,
+			Time:     row.Timestamp,
+			Amount:   row.Amount,
+		})
+	}
+	return expenses, nil
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	categoryv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/category/v1"
+)
+
+type Category struct {
+	categoryv1.Category
+	Metadata
+	Revision
+	Tombstone
+}
+
+func NewCategory(category *categoryv1.Category, metadata Metadata) *Category {
+	return &Category{
+		Category: categoryv1.Category{
+			Id:      category.GetId(),
+			GroupId: category.GetGroupId(),
+			Name:    category.GetName(),
+		},
+		Metadata: metadata,
+	}
+}
+
+func (c *Category) IntoProtoCategory() *categoryv1.Category {
+	c.Category.CreateTime, c.Category.UpdateTime, c.Category.Creator, c.Category.LastModifier = c.Metadata.intoProto()
+	c.Category.Etag = c.Revision.Etag()
+	c.Category.DeleteTime = c.Tombstone.intoProto()
+	return &c.Category
+}
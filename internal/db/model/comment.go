@@ -0,0 +1,75 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	commentv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/comment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Comment struct {
+	commentv1.Comment
+	Metadata
+	Revision
+	Tombstone
+}
+
+// CommentRevision is a version of the text and mentions of a comment which together make up the edit history of the comment
+type CommentRevision struct {
+	commentv1.CommentRevision
+	CreateTime *Timestamp
+}
+
+func NewComment(comment *commentv1.Comment, metadata Metadata) *Comment {
+	return &Comment{
+		Comment: commentv1.Comment{
+			Id:                 comment.GetId(),
+			ExpenseId:          comment.GetExpenseId(),
+			GroupId:            comment.GetGroupId(),
+			AuthorId:           comment.GetAuthorId(),
+			Text:               comment.GetText(),
+			MentionedPersonIds: comment.GetMentionedPersonIds(),
+		},
+		Metadata: metadata,
+	}
+}
+
+func (c *Comment) IntoProtoComment() *commentv1.Comment {
+	c.Comment.CreateTime, c.Comment.UpdateTime, c.Comment.Creator, c.Comment.LastModifier = c.Metadata.intoProto()
+	c.Comment.Etag = c.Revision.Etag()
+	// every comment starts at the first revision which only edits increment
+	c.Comment.Edited = c.Revision.Revision > 1
+	c.Comment.DeleteTime = c.Tombstone.intoProto()
+	return &c.Comment
+}
+
+// RecordCommentRevision stores the passed comment as the revision it is currently in recorded by the principal of the context
+// at the passed time. It is expected to run in the transaction that created or edited the comment.
+func RecordCommentRevision(ctx context.Context, db bun.IDB, comment *Comment, t time.Time) error {
+	revision := &CommentRevision{
+		CommentRevision: commentv1.CommentRevision{
+			CommentId:          comment.GetId(),
+			Revision:           comment.Revision.Revision,
+			GroupId:            comment.GetGroupId(),
+			Text:               comment.GetText(),
+			MentionedPersonIds: comment.GetMentionedPersonIds(),
+			Creator:            principal.FromContext(ctx),
+		},
+		CreateTime: NewTimestamp(timestamppb.New(t)),
+	}
+	if _, err := db.NewInsert().Model(revision).Exec(ctx); err != nil {
+		return eris.Wrap(err, "failed inserting comment revision")
+	}
+	return nil
+}
+
+func (r *CommentRevision) IntoProtoCommentRevision() *commentv1.CommentRevision {
+	if r.CreateTime != nil {
+		r.CommentRevision.CreateTime = r.CreateTime.IntoProtoTimestamp()
+	}
+	return &r.CommentRevision
+}
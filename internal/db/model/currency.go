@@ -0,0 +1,27 @@
+package model
+
+import (
+	currencyv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/currency/v1"
+)
+
+type Currency struct {
+	currencyv1.Currency
+	Metadata
+}
+
+func NewCurrency(currency *currencyv1.Currency, metadata Metadata) *Currency {
+	return &Currency{
+		Currency: currencyv1.Currency{
+			Id:         currency.GetId(),
+			Acronym:    currency.GetAcronym(),
+			Name:       currency.GetName(),
+			Deprecated: currency.GetDeprecated(),
+		},
+		Metadata: metadata,
+	}
+}
+
+func (c *Currency) IntoProtoCurrency() *currencyv1.Currency {
+	c.Currency.CreateTime, c.Currency.UpdateTime, c.Currency.Creator, c.Currency.LastModifier = c.Metadata.intoProto()
+	return &c.Currency
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	debtreminderv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/debtreminder/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/balance"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type DebtReminderPolicy struct {
+	debtreminderv1.DebtReminderPolicy
+	Metadata
+	Revision
+}
+
+type DebtReminderSnooze struct {
+	debtreminderv1.DebtReminderSnooze
+	SnoozeUntil time.Time
+	Metadata
+}
+
+// DebtReminder records the time a person was last reminded of a debt; it is dropped once the person no longer owes anything
+type DebtReminder struct {
+	GroupId    string `bun:",pk"`
+	PersonId   string `bun:",pk"`
+	RemindTime time.Time
+}
+
+func NewDebtReminderPolicy(policy *debtreminderv1.DebtReminderPolicy, metadata Metadata) *DebtReminderPolicy {
+	return &DebtReminderPolicy{
+		DebtReminderPolicy: debtreminderv1.DebtReminderPolicy{
+			GroupId:                  policy.GetGroupId(),
+			ThresholdMainValue:       policy.GetThresholdMainValue(),
+			ThresholdFractionalValue: policy.GetThresholdFractionalValue(),
+			MinAgeDays:               policy.GetMinAgeDays(),
+			RepeatIntervalDays:       policy.GetRepeatIntervalDays(),
+		},
+		Metadata: metadata,
+	}
+}
+
+// Threshold returns the amount in minor units of the currency of the group a debt has to exceed for its person to be reminded
+func (p *DebtReminderPolicy) Threshold() int64 {
+	return balance.Amount(p.GetThresholdMainValue(), p.GetThresholdFractionalValue())
+}
+
+func (p *DebtReminderPolicy) IntoProtoDebtReminderPolicy() *debtreminderv1.DebtReminderPolicy {
+	p.DebtReminderPolicy.CreateTime, p.DebtReminderPolicy.UpdateTime, p.DebtReminderPolicy.Creator, p.DebtReminderPolicy.LastModifier = p.Metadata.intoProto()
+	p.DebtReminderPolicy.Etag = p.Revision.Etag()
+	return &p.DebtReminderPolicy
+}
+
+func (s *DebtReminderSnooze) IntoProtoDebtReminderSnooze() *debtreminderv1.DebtReminderSnooze {
+	s.DebtReminderSnooze.SnoozeUntil = timestamppb.New(s.SnoozeUntil)
+	s.DebtReminderSnooze.CreateTime, s.DebtReminderSnooze.UpdateTime, s.DebtReminderSnooze.Creator, s.DebtReminderSnooze.LastModifier = s.Metadata.intoProto()
+	return &s.DebtReminderSnooze
+}
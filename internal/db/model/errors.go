@@ -11,3 +11,13 @@ type UnsupportedDataTypeError struct {
 func (e UnsupportedDataTypeError) Error() string {
 	return fmt.Sprintf("unsupported data type %s", e.DataType)
 }
+
+var _ error = (*UnsupportedTimeFormatError)(nil)
+
+type UnsupportedTimeFormatError struct {
+	Value string
+}
+
+func (e UnsupportedTimeFormatError) Error() string {
+	return fmt.Sprintf("unsupported time format of %s", e.Value)
+}
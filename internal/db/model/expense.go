@@ -7,9 +7,12 @@ import (
 type Expense struct {
 	expensev1.Expense
 	Timestamp *Timestamp
+	Metadata
+	Revision
+	Tombstone
 }
 
-func NewExpense(expense *expensev1.Expense) *Expense {
+func NewExpense(expense *expensev1.Expense, metadata Metadata) *Expense {
 	var name *string
 	if expense != nil {
 		name = expense.Name
@@ -23,10 +26,14 @@ func NewExpense(expense *expensev1.Expense) *Expense {
 			CurrencyId: expense.GetCurrencyId(),
 		},
 		Timestamp: NewTimestamp(expense.GetTimestamp()),
+		Metadata:  metadata,
 	}
 }
 
 func (e *Expense) IntoProtoExpense() *expensev1.Expense {
 	e.Expense.Timestamp = e.Timestamp.IntoProtoTimestamp()
+	e.Expense.CreateTime, e.Expense.UpdateTime, e.Expense.Creator, e.Expense.LastModifier = e.Metadata.intoProto()
+	e.Expense.Etag = e.Revision.Etag()
+	e.Expense.DeleteTime = e.Tombstone.intoProto()
 	return &e.Expense
 }
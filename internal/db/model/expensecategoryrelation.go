@@ -0,0 +1,26 @@
+package model
+
+import (
+	expensecategoryrelationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensecategoryrelation/v1"
+)
+
+type ExpenseCategoryRelation struct {
+	expensecategoryrelationv1.ExpenseCategoryRelation
+	Metadata
+	Tombstone
+}
+
+func NewExpenseCategoryRelation(relation *expensecategoryrelationv1.ExpenseCategoryRelation, metadata Metadata) *ExpenseCategoryRelation {
+	return &ExpenseCategoryRelation{
+		ExpenseCategoryRelation: expensecategoryrelationv1.ExpenseCategoryRelation{
+			ExpenseId:  relation.GetExpenseId(),
+			CategoryId: relation.GetCategoryId(),
+		},
+		Metadata: metadata,
+	}
+}
+
+func (r *ExpenseCategoryRelation) IntoProtoExpenseCategoryRelation() *expensecategoryrelationv1.ExpenseCategoryRelation {
+	r.ExpenseCategoryRelation.CreateTime, r.ExpenseCategoryRelation.UpdateTime, r.ExpenseCategoryRelation.Creator, r.ExpenseCategoryRelation.LastModifier = r.Metadata.intoProto()
+	return &r.ExpenseCategoryRelation
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expense/v1"
+	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensestake/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ExpenseRevision is a version of an expense. The expense, its stakes and its category IDs are stored as JSON
+// since they are only ever read as a whole.
+type ExpenseRevision struct {
+	expensev1.ExpenseRevision
+	ContentJson string
+	CreateTime  *Timestamp
+}
+
+// NewExpenseRevision returns the revision of the passed expense with the passed stakes and category IDs recorded by the principal
+// of the context at the passed time. The expense is expected to carry the revision it is in.
+func NewExpenseRevision(ctx context.Context, expense *Expense, stakes []*ExpenseStake, categoryIds []string, t time.Time) (*ExpenseRevision, error) {
+	content := &expensev1.ExpenseRevision{
+		Expense:     expense.IntoProtoExpense(),
+		Stakes:      make([]*expensestakev1.ExpenseStake, len(stakes)),
+		CategoryIds: categoryIds,
+	}
+	for i, s := range stakes {
+		content.Stakes[i] = s.IntoProtoExpenseStake()
+	}
+	data, err := protojson.Marshal(content)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed marshalling expense revision")
+	}
+	return &ExpenseRevision{
+		ExpenseRevision: expensev1.ExpenseRevision{
+			ExpenseId: expense.Id,
+			Revision:  expense.Revision.Revision,
+			GroupId:   expense.GroupId,
+			Creator:   principal.FromContext(ctx),
+		},
+		ContentJson: string(data),
+		CreateTime:  NewTimestamp(timestamppb.New(t)),
+	}, nil
+}
+
+func (r *ExpenseRevision) IntoProtoExpenseRevision() (*expensev1.ExpenseRevision, error) {
+	var content expensev1.ExpenseRevision
+	if err := protojson.Unmarshal([]byte(r.ContentJson), &content); err != nil {
+		return nil, eris.Wrap(err, "failed unmarshalling expense revision")
+	}
+	r.ExpenseRevision.Expense = content.Expense
+	r.ExpenseRevision.Stakes = content.Stakes
+	r.ExpenseRevision.CategoryIds = content.CategoryIds
+	if r.CreateTime != nil {
+		r.ExpenseRevision.CreateTime = r.CreateTime.IntoProtoTimestamp()
+	}
+	return &r.ExpenseRevision, nil
+}
+
+// RecordExpenseRevision stores the current version of the expense with the passed ID including its stakes and categories
+// as the revision the expense is currently in and returns the expense. It is expected to run in the transaction that changed the expense.
+func RecordExpenseRevision(ctx context.Context, db bun.IDB, expenseId string) (*Expense, error) {
+	expense, err := util.CheckResourceExists[*Expense](ctx, db, expenseId)
+	if err != nil {
+		return nil, err
+	}
+	stakes, categoryIds, err := SelectExpenseContent(ctx, db, expenseId)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := NewExpenseRevision(ctx, expense, stakes, categoryIds, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.NewInsert().Model(revision).Exec(ctx); err != nil {
+		return nil, eris.Wrap(err, "failed inserting expense revision")
+	}
+	return expense, nil
+}
+
+// SelectExpenseContent returns the stakes of the expense with the passed ID ordered by their IDs and the sorted IDs of its categories
+func SelectExpenseContent(ctx context.Context, db bun.IDB, expenseId string) ([]*ExpenseStake, []string, error) {
+	var stakes []*ExpenseStake
+	if err := db.NewSelect().Model(&stakes).
+		Where("expense_id = ?", expenseId).
+		Order("id").
+		Scan(ctx); err != nil {
+		return nil, nil, eris.Wrap(err, "failed selecting stakes of expense")
+	}
+	var categoryIds []string
+	if err := db.NewSelect().Model((*ExpenseCategoryRelation)(nil)).
+		Column("category_id").
+		Where("expense_id = ?", expenseId).
+		Order("category_id").
+		Scan(ctx, &categoryIds); err != nil {
+		return nil, nil, eris.Wrap(err, "failed selecting categories of expense")
+	}
+	return stakes, categoryIds, nil
+}
+
+// ReviseExpense increments the revision of the expense with the passed ID because one of its stakes or categories changed,
+// makes the principal of the context its last modifier and records the new revision. It returns the expense in the new revision.
+func ReviseExpense(ctx context.Context, db bun.IDB, expenseId string) (*Expense, error) {
+	expense := &Expense{
+		Expense: expensev1.Expense{
+			Id: expenseId,
+		},
+		Metadata: NewModifiedMetadata(ctx, time.Now()),
+	}
+	if _, err := IncrementRevision(db.NewUpdate().Model(expense).Column(MetadataUpdateColumns...)).
+		WherePK().
+		Exec(ctx); err != nil {
+		return nil, eris.Wrap(err, "failed incrementing revision of expense")
+	}
+	return RecordExpenseRevision(ctx, db, expenseId)
+}
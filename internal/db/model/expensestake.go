@@ -0,0 +1,33 @@
+package model
+
+import (
+	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensestake/v1"
+)
+
+type ExpenseStake struct {
+	expensestakev1.ExpenseStake
+	Metadata
+	Tombstone
+}
+
+func NewExpenseStake(stake *expensestakev1.ExpenseStake, metadata Metadata) *ExpenseStake {
+	var fractionalValue *int32
+	if stake != nil {
+		fractionalValue = stake.FractionalValue
+	}
+	return &ExpenseStake{
+		ExpenseStake: expensestakev1.ExpenseStake{
+			Id:              stake.GetId(),
+			ExpenseId:       stake.GetExpenseId(),
+			ForId:           stake.GetForId(),
+			MainValue:       stake.GetMainValue(),
+			FractionalValue: fractionalValue,
+		},
+		Metadata: metadata,
+	}
+}
+
+func (s *ExpenseStake) IntoProtoExpenseStake() *expensestakev1.ExpenseStake {
+	s.ExpenseStake.CreateTime, s.ExpenseStake.UpdateTime, s.ExpenseStake.Creator, s.ExpenseStake.LastModifier = s.Metadata.intoProto()
+	return &s.ExpenseStake
+}
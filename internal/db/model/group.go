@@ -0,0 +1,30 @@
+package model
+
+import (
+	groupv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/group/v1"
+)
+
+type Group struct {
+	groupv1.Group
+	Metadata
+	Revision
+	Tombstone
+}
+
+func NewGroup(group *groupv1.Group, metadata Metadata) *Group {
+	return &Group{
+		Group: groupv1.Group{
+			Id:         group.GetId(),
+			Name:       group.GetName(),
+			CurrencyId: group.GetCurrencyId(),
+		},
+		Metadata: metadata,
+	}
+}
+
+func (g *Group) IntoProtoGroup() *groupv1.Group {
+	g.Group.CreateTime, g.Group.UpdateTime, g.Group.Creator, g.Group.LastModifier = g.Metadata.intoProto()
+	g.Group.Etag = g.Revision.Etag()
+	g.Group.DeleteTime = g.Tombstone.intoProto()
+	return &g.Group
+}
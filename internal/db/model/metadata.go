@@ -0,0 +1,100 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	metadatav1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/metadata/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Metadata holds the server-managed metadata of a resource. Embedded after the protobuf message of a resource it provides the
+// columns of the message's create_time, update_time, creator and last_modifier fields which bun ignores since it cannot scan timestamps into them.
+type Metadata struct {
+	CreateTime   *Timestamp
+	UpdateTime   *Timestamp
+	Creator      string
+	LastModifier string
+}
+
+// MetadataUpdateColumns are the columns an update has to set in addition to the updated fields of a resource
+var MetadataUpdateColumns = []string{"update_time", "last_modifier"}
+
+// MetadataReturningColumns are the metadata columns an update has to return for the updated resource to carry its complete metadata
+var MetadataReturningColumns = []string{"create_time", "creator"}
+
+// MetadataFields are the JSON names of the server-managed fields of the resources which change with every change of a resource
+// and are therefore left out when comparing different states of a resource
+var MetadataFields = []string{"create_time", "update_time", "creator", "last_modifier", "etag", "delete_time"}
+
+var metadataOrderColumns = map[metadatav1.MetadataOrder_Field]string{
+	metadatav1.MetadataOrder_FIELD_CREATE_TIME:   "create_time",
+	metadatav1.MetadataOrder_FIELD_UPDATE_TIME:   "update_time",
+	metadatav1.MetadataOrder_FIELD_CREATOR:       "creator",
+	metadatav1.MetadataOrder_FIELD_LAST_MODIFIER: "last_modifier",
+}
+
+// NewCreatedMetadata returns the metadata of a resource created by the principal of the context at the passed time
+func NewCreatedMetadata(ctx context.Context, t time.Time) Metadata {
+	p := principal.FromContext(ctx)
+	return Metadata{
+		CreateTime:   NewTimestamp(timestamppb.New(t)),
+		UpdateTime:   NewTimestamp(timestamppb.New(t)),
+		Creator:      p,
+		LastModifier: p,
+	}
+}
+
+// NewModifiedMetadata returns the metadata of a resource modified by the principal of the context at the passed time.
+// The creation metadata is left empty and is expected to be returned by the update.
+func NewModifiedMetadata(ctx context.Context, t time.Time) Metadata {
+	return Metadata{
+		UpdateTime:   NewTimestamp(timestamppb.New(t)),
+		LastModifier: principal.FromContext(ctx),
+	}
+}
+
+// intoProto returns the metadata as values of the create_time, update_time, creator and last_modifier fields of a protobuf message
+func (m *Metadata) intoProto() (*timestamppb.Timestamp, *timestamppb.Timestamp, string, string) {
+	var createTime, updateTime *timestamppb.Timestamp
+	if m.CreateTime != nil {
+		createTime = m.CreateTime.IntoProtoTimestamp()
+	}
+	if m.UpdateTime != nil {
+		updateTime = m.UpdateTime.IntoProtoTimestamp()
+	}
+	return createTime, updateTime, m.Creator, m.LastModifier
+}
+
+// ApplyMetadataListOptions restricts a query listing resources to those matching the filter and sorts them by the order.
+// Orders applied to the query afterwards only take effect for resources the metadata order considers equal.
+func ApplyMetadataListOptions(q *bun.SelectQuery, order *metadatav1.MetadataOrder, filter *metadatav1.MetadataFilter) *bun.SelectQuery {
+	if t := filter.GetCreateTimeAfter(); t != nil {
+		q = q.Where("?TableAlias.create_time >= ?", t.AsTime())
+	}
+	if t := filter.GetCreateTimeBefore(); t != nil {
+		q = q.Where("?TableAlias.create_time < ?", t.AsTime())
+	}
+	if t := filter.GetUpdateTimeAfter(); t != nil {
+		q = q.Where("?TableAlias.update_time >= ?", t.AsTime())
+	}
+	if t := filter.GetUpdateTimeBefore(); t != nil {
+		q = q.Where("?TableAlias.update_time < ?", t.AsTime())
+	}
+	if filter.GetCreator() != "" {
+		q = q.Where("?TableAlias.creator = ?", filter.GetCreator())
+	}
+	if filter.GetLastModifier() != "" {
+		q = q.Where("?TableAlias.last_modifier = ?", filter.GetLastModifier())
+	}
+	if column, ok := metadataOrderColumns[order.GetField()]; ok {
+		direction := "ASC"
+		if order.GetDescending() {
+			direction = "DESC"
+		}
+		q = q.OrderExpr("?TableAlias.? "+direction, bun.Ident(column))
+	}
+	return q
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	notificationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/notification/v1"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+// NotificationPreference tells which events a user is notified about. The persons representing the user in their groups
+// are stored as NotificationPreferencePerson so that the recipients of the events of a group can be selected.
+type NotificationPreference struct {
+	notificationv1.NotificationPreference
+	// LastDigestTime is the time the last daily digest was sent at
+	LastDigestTime *time.Time `bun:",nullzero"`
+	Metadata
+	Revision
+}
+
+// NotificationPreferencePerson links a user to a person representing the user in a group
+type NotificationPreferencePerson struct {
+	Principal string `bun:",pk"`
+	PersonId  string `bun:",pk"`
+	GroupId   string
+}
+
+// PendingNotification is a notification collected for the next daily digest of a user
+type PendingNotification struct {
+	Id        string `bun:",pk"`
+	Principal string
+	GroupId   string
+	EventType notificationv1.NotificationPreference_EventType
+	// Summary is the notification rendered in the locale of the user as a single line of the digest
+	Summary    string
+	CreateTime time.Time
+}
+
+func NewNotificationPreference(preference *notificationv1.NotificationPreference, metadata Metadata) *NotificationPreference {
+	return &NotificationPreference{
+		NotificationPreference: notificationv1.NotificationPreference{
+			Principal:  preference.GetPrincipal(),
+			Email:      preference.GetEmail(),
+			Locale:     preference.GetLocale(),
+			PersonIds:  preference.GetPersonIds(),
+			EventTypes: preference.GetEventTypes(),
+			Delivery:   preference.GetDelivery(),
+			DigestHour: preference.GetDigestHour(),
+			TimeZone:   preference.GetTimeZone(),
+		},
+		Metadata: metadata,
+	}
+}
+
+// SelectNotificationPreference returns the notification preference of the principal along with the persons representing the principal.
+// It returns an error wrapping sql.ErrNoRows if the principal has no notification preference.
+func SelectNotificationPreference(ctx context.Context, db bun.IDB, principal string) (*NotificationPreference, error) {
+	preference := &NotificationPreference{}
+	if err := db.NewSelect().Model(preference).Where("principal = ?", principal).Limit(1).Scan(ctx); err != nil {
+		return nil, eris.Wrap(err, "failed selecting notification preference")
+	}
+	if err := db.NewSelect().Model((*NotificationPreferencePerson)(nil)).
+		Column("person_id").
+		Where("principal = ?", principal).
+		Order("person_id ASC").
+		Scan(ctx, &preference.PersonIds); err != nil {
+		return nil, eris.Wrap(err, "failed selecting persons of notification preference")
+	}
+	return preference, nil
+}
+
+// Notifies tells if the user wants to be notified about events of the passed type
+func (p *NotificationPreference) Notifies(eventType notificationv1.NotificationPreference_EventType) bool {
+	for _, t := range p.GetEventTypes() {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *NotificationPreference) IntoProtoNotificationPreference() *notificationv1.NotificationPreference {
+	p.NotificationPreference.CreateTime, p.NotificationPreference.UpdateTime, p.NotificationPreference.Creator, p.NotificationPreference.LastModifier = p.Metadata.intoProto()
+	p.NotificationPreference.Etag = p.Revision.Etag()
+	return &p.NotificationPreference
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	personv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/person/v1"
+)
+
+type Person struct {
+	personv1.Person
+	Metadata
+	Revision
+	Tombstone
+}
+
+func NewPerson(person *personv1.Person, metadata Metadata) *Person {
+	return &Person{
+		Person: personv1.Person{
+			Id:      person.GetId(),
+			GroupId: person.GetGroupId(),
+			Name:    person.GetName(),
+		},
+		Metadata: metadata,
+	}
+}
+
+func (p *Person) IntoProtoPerson() *personv1.Person {
+	p.Person.CreateTime, p.Person.UpdateTime, p.Person.Creator, p.Person.LastModifier = p.Metadata.intoProto()
+	p.Person.Etag = p.Revision.Etag()
+	p.Person.DeleteTime = p.Tombstone.intoProto()
+	return &p.Person
+}
@@ -0,0 +1,131 @@
+package model
+
+import (
+	"time"
+
+	recurringexpensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/recurringexpense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/recurrence"
+	"github.com/rotisserie/eris"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// RecurringExpense is a template of an expense which the recurring expense processor creates expenses from. The stakes
+// are stored as JSON since they are only ever read as a whole and the schedule is stored in columns of its own.
+type RecurringExpense struct {
+	recurringexpensev1.RecurringExpense
+	StakesJson         string
+	Frequency          recurringexpensev1.RecurringExpense_Schedule_Frequency
+	ScheduleInterval   int32
+	StartTime          *Timestamp
+	EndTime            *time.Time `bun:",nullzero"`
+	NextOccurrenceTime *time.Time `bun:",nullzero"`
+	Metadata
+	Revision
+}
+
+// RecurringExpenseOccurrence records that an occurrence of a recurring expense was turned into the expense with the expense ID
+type RecurringExpenseOccurrence struct {
+	RecurringExpenseId string    `bun:",pk"`
+	OccurrenceTime     time.Time `bun:",pk"`
+	GroupId            string
+	ExpenseId          string
+	// Published tells that the events about the created expense and its stakes were published
+	Published bool
+}
+
+var frequencies = map[recurringexpensev1.RecurringExpense_Schedule_Frequency]recurrence.Frequency{
+	recurringexpensev1.RecurringExpense_Schedule_FREQUENCY_DAILY:   recurrence.Daily,
+	recurringexpensev1.RecurringExpense_Schedule_FREQUENCY_WEEKLY:  recurrence.Weekly,
+	recurringexpensev1.RecurringExpense_Schedule_FREQUENCY_MONTHLY: recurrence.Monthly,
+	recurringexpensev1.RecurringExpense_Schedule_FREQUENCY_YEARLY:  recurrence.Yearly,
+}
+
+// NewRecurringExpense returns the passed recurring expense whose first occurrence is the first one not before the passed time
+func NewRecurringExpense(recurringExpense *recurringexpensev1.RecurringExpense, metadata Metadata, t time.Time) (*RecurringExpense, error) {
+	var name *string
+	if recurringExpense != nil {
+		name = recurringExpense.Name
+	}
+	stakes, err := protojson.Marshal(&recurringexpensev1.RecurringExpense{
+		Stakes: recurringExpense.GetStakes(),
+	})
+	if err != nil {
+		return nil, eris.Wrap(err, "failed marshalling stakes")
+	}
+	schedule := recurringExpense.GetSchedule()
+	r := &RecurringExpense{
+		RecurringExpense: recurringexpensev1.RecurringExpense{
+			Id:         recurringExpense.GetId(),
+			GroupId:    recurringExpense.GetGroupId(),
+			Name:       name,
+			ById:       recurringExpense.GetById(),
+			CurrencyId: recurringExpense.GetCurrencyId(),
+		},
+		StakesJson:       string(stakes),
+		Frequency:        schedule.GetFrequency(),
+		ScheduleInterval: schedule.GetInterval(),
+		StartTime:        NewTimestamp(schedule.GetStartTime()),
+		Metadata:         metadata,
+	}
+	if schedule.GetEndTime() != nil {
+		endTime := schedule.GetEndTime().AsTime()
+		r.EndTime = &endTime
+	}
+	r.ScheduleFrom(t)
+	return r, nil
+}
+
+// Rule returns the schedule of the recurring expense
+func (r *RecurringExpense) Rule() recurrence.Rule {
+	rule := recurrence.Rule{
+		Frequency: frequencies[r.Frequency],
+		Interval:  int(r.ScheduleInterval),
+		Start:     r.StartTime.AsTime(),
+	}
+	if r.EndTime != nil {
+		rule.End = *r.EndTime
+	}
+	return rule
+}
+
+// ScheduleFrom sets the next occurrence to the first one not before the passed time unless the recurring expense is paused
+func (r *RecurringExpense) ScheduleFrom(t time.Time) {
+	r.ScheduleAfter(t.Add(-time.Nanosecond))
+}
+
+// ScheduleAfter sets the next occurrence to the first one after the passed time, e.g. the occurrence that was just
+// turned into an expense, unless the recurring expense is paused
+func (r *RecurringExpense) ScheduleAfter(t time.Time) {
+	r.NextOccurrenceTime = nil
+	if r.Paused {
+		return
+	}
+	if next, ok := r.Rule().Next(t); ok {
+		r.NextOccurrenceTime = &next
+	}
+}
+
+func (r *RecurringExpense) IntoProtoRecurringExpense() (*recurringexpensev1.RecurringExpense, error) {
+	var stakes recurringexpensev1.RecurringExpense
+	if err := protojson.Unmarshal([]byte(r.StakesJson), &stakes); err != nil {
+		return nil, eris.Wrap(err, "failed unmarshalling stakes")
+	}
+	r.RecurringExpense.Stakes = stakes.Stakes
+	r.RecurringExpense.Schedule = &recurringexpensev1.RecurringExpense_Schedule{
+		Frequency: r.Frequency,
+		Interval:  r.ScheduleInterval,
+	}
+	if r.StartTime != nil {
+		r.RecurringExpense.Schedule.StartTime = r.StartTime.IntoProtoTimestamp()
+	}
+	if r.EndTime != nil {
+		r.RecurringExpense.Schedule.EndTime = timestamppb.New(*r.EndTime)
+	}
+	if r.NextOccurrenceTime != nil {
+		r.RecurringExpense.NextOccurrenceTime = timestamppb.New(*r.NextOccurrenceTime)
+	}
+	r.RecurringExpense.CreateTime, r.RecurringExpense.UpdateTime, r.RecurringExpense.Creator, r.RecurringExpense.LastModifier = r.Metadata.intoProto()
+	r.RecurringExpense.Etag = r.Revision.Etag()
+	return &r.RecurringExpense, nil
+}
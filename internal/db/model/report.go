@@ -0,0 +1,121 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/report"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// ReportStakeRow is the sum of the stakes of the expenses of a day sharing the payer, the person the stake is for and the currency
+type ReportStakeRow struct {
+	ById       string
+	ForId      string
+	CurrencyId string
+	report.Spending
+}
+
+// ReportCategoryRow is the sum of the stakes of the expenses of a day sharing a category and the currency; expenses without category
+// have an empty category ID
+type ReportCategoryRow struct {
+	CategoryId string
+	report.Spending
+}
+
+type reportRow struct {
+	ById       string
+	ForId      string
+	CategoryId string
+	CurrencyId string
+	Acronym    string
+	Day        string
+	Amount     int64
+}
+
+// SelectReportStakeRows returns the sums of the stakes of the non-deleted expenses of the group within the time range from start
+// inclusive to end exclusive which are computed by the database per payer, person the stake is for, currency and day
+func SelectReportStakeRows(ctx context.Context, db bun.IDB, groupId string, start time.Time, end time.Time) ([]ReportStakeRow, error) {
+	var rows []reportRow
+	if err := selectReportRows(db, groupId, start, end).
+		ColumnExpr("e.by_id, s.for_id").
+		GroupExpr("e.by_id, s.for_id").
+		Scan(ctx, &rows); err != nil {
+		return nil, err
+	}
+	stakeRows := make([]ReportStakeRow, 0, len(rows))
+	for _, row := range rows {
+		spending, err := row.spending()
+		if err != nil {
+			return nil, err
+		}
+		stakeRows = append(stakeRows, ReportStakeRow{
+			ById:       row.ById,
+			ForId:      row.ForId,
+			CurrencyId: row.CurrencyId,
+			Spending:   spending,
+		})
+	}
+	return stakeRows, nil
+}
+
+// SelectReportCategoryRows returns the sums of the stakes of the non-deleted expenses of the group within the time range from start
+// inclusive to end exclusive which are computed by the database per category, currency and day. An expense with several categories
+// counts towards each of them.
+func SelectReportCategoryRows(ctx context.Context, db bun.IDB, groupId string, start time.Time, end time.Time) ([]ReportCategoryRow, error) {
+	var rows []reportRow
+	if err := selectReportRows(db, groupId, start, end).
+		Join("LEFT JOIN expense_category_relations AS r ON r.expense_id = e.id AND r.delete_time IS NULL").
+		ColumnExpr("COALESCE(r.category_id, '') AS category_id").
+		GroupExpr("r.category_id").
+		Scan(ctx, &rows); err != nil {
+		return nil, err
+	}
+	categoryRows := make([]ReportCategoryRow, 0, len(rows))
+	for _, row := range rows {
+		spending, err := row.spending()
+		if err != nil {
+			return nil, err
+		}
+		categoryRows = append(categoryRows, ReportCategoryRow{
+			CategoryId: row.CategoryId,
+			Spending:   spending,
+		})
+	}
+	return categoryRows, nil
+}
+
+// selectReportRows selects the sums of the stakes of the non-deleted expenses of the group within the time range per currency and day
+// in UTC; the exchange rates are daily which is why the database cannot sum up across days before they are converted
+func selectReportRows(db bun.IDB, groupId string, start time.Time, end time.Time) *bun.SelectQuery {
+	day := "to_char(e.timestamp AT TIME ZONE 'UTC', 'YYYY-MM-DD')"
+	if db.Dialect().Name() == dialect.SQLite {
+		day = "strftime('%Y-%m-%d', e.timestamp)"
+	}
+	return db.NewSelect().
+		TableExpr("expenses AS e").
+		Join("JOIN expense_stakes AS s ON s.expense_id = e.id AND s.delete_time IS NULL").
+		Join("JOIN currencies AS c ON c.id = e.currency_id").
+		ColumnExpr("e.currency_id, c.acronym").
+		ColumnExpr(day+" AS day").
+		ColumnExpr("SUM(s.main_value * 100 + COALESCE(s.fractional_value, 0)) AS amount").
+		Where("e.group_id = ?", groupId).
+		Where("e.delete_time IS NULL").
+		Where("e.timestamp >= ?", start).
+		Where("e.timestamp < ?", end).
+		GroupExpr("e.currency_id, c.acronym").
+		GroupExpr(day)
+}
+
+func (r reportRow) spending() (report.Spending, error) {
+	day, err := time.Parse("2006-01-02", r.Day)
+	if err != nil {
+		return report.Spending{}, err
+	}
+	return report.Spending{
+		Currency: r.Acronym,
+		Day:      day,
+		Amount:   r.Amount,
+	}, nil
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/proto"
+)
+
+var _ error = (*EtagMismatchError)(nil)
+
+// EtagMismatchError tells that a resource was modified since the client read the etag it passed
+type EtagMismatchError struct {
+	CurrentEtag string
+}
+
+func (e EtagMismatchError) Error() string {
+	return fmt.Sprintf("the passed etag does not match the current etag %s", e.CurrentEtag)
+}
+
+// Revision counts the modifications of a resource. Embedded in a model it provides the revision column
+// which new resources start at 1 with and which is exposed as the etag of the resource.
+type Revision struct {
+	Revision int64 `bun:",nullzero,notnull,default:1"`
+}
+
+// Etag returns the revision as strong HTTP entity tag so that it can also be passed in an If-Match header
+func (r *Revision) Etag() string {
+	return strconv.Quote(strconv.FormatInt(r.Revision, 10))
+}
+
+// CheckEtag returns an EtagMismatchError unless the passed etag is empty or matches the current revision
+func (r *Revision) CheckEtag(etag string) error {
+	if etag == "" || etag == r.Etag() {
+		return nil
+	}
+	return EtagMismatchError{
+		CurrentEtag: r.Etag(),
+	}
+}
+
+// IncrementRevision makes an update increment the revision of the updated resource
+func IncrementRevision(q *bun.UpdateQuery) *bun.UpdateQuery {
+	return q.Column("revision").Value("revision", "? + 1", bun.Ident("revision"))
+}
+
+// MatchEtag restricts an update to the revision the passed etag denotes unless the etag is empty, see WhereEtag
+func MatchEtag(q *bun.UpdateQuery, etag string) *bun.UpdateQuery {
+	return q.ApplyQueryBuilder(func(q bun.QueryBuilder) bun.QueryBuilder {
+		return WhereEtag(q, etag)
+	})
+}
+
+// WhereEtag restricts a query to the revision the passed etag denotes unless the etag is empty. Since etags are checked before
+// a resource is modified, an update or delete matching no row tells that the resource was modified concurrently after the check.
+func WhereEtag(q bun.QueryBuilder, etag string) bun.QueryBuilder {
+	if etag == "" {
+		return q
+	}
+	// an etag that cannot be parsed matches no revision
+	revision := int64(-1)
+	if unquoted, err := strconv.Unquote(etag); err == nil {
+		if parsed, err := strconv.ParseInt(unquoted, 10, 64); err == nil {
+			revision = parsed
+		}
+	}
+	return q.Where("? = ?", bun.Ident("revision"), revision)
+}
+
+type revisionedModel interface {
+	proto.Message
+	GetId() string
+	Etag() string
+	CheckEtag(etag string) error
+}
+
+// CheckCurrentEtag checks the passed etag against the current one of the resource with the passed ID unless the etag is empty.
+// It returns a util.ResourceNotFoundError if the resource does not exist and an EtagMismatchError if the etags do not match.
+func CheckCurrentEtag[T revisionedModel](ctx context.Context, db bun.IDB, id string, etag string) error {
+	if etag == "" {
+		return nil
+	}
+	current, err := util.CheckResourceExists[T](ctx, db, id)
+	if err != nil {
+		return err
+	}
+	return current.CheckEtag(etag)
+}
+
+// ConcurrentEtagMismatch returns an EtagMismatchError with the current etag of the resource with the passed ID after an update or delete
+// restricted by WhereEtag matched no row. It returns a util.ResourceNotFoundError if the resource was deleted concurrently instead.
+func ConcurrentEtagMismatch[T revisionedModel](ctx context.Context, db bun.IDB, id string) error {
+	current, err := util.CheckResourceExists[T](ctx, db, id)
+	if err != nil {
+		return err
+	}
+	return EtagMismatchError{
+		CurrentEtag: current.Etag(),
+	}
+}
@@ -20,6 +20,14 @@ func (ts *Timestamp) Scan(src interface{}) (err error) {
 	switch src := src.(type) {
 	case time.Time:
 		t = src
+	case string:
+		if t, err = parseTime(src); err != nil {
+			return err
+		}
+	case []byte:
+		if t, err = parseTime(string(src)); err != nil {
+			return err
+		}
 	case nil:
 		t = time.Time{}
 	default:
@@ -34,6 +42,25 @@ func (ts *Timestamp) Scan(src interface{}) (err error) {
 	return nil
 }
 
+// timeLayouts are the layouts of timestamps drivers like the SQLite one return as text; the last one is SQLite's CURRENT_TIMESTAMP in UTC
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+}
+
+func parseTime(src string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, src); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, UnsupportedTimeFormatError{
+		Value: src,
+	}
+}
+
 var _ driver.Valuer = (*Timestamp)(nil)
 
 func (ts *Timestamp) Value() (driver.Value, error) {
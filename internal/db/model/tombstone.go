@@ -0,0 +1,128 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	RestoreViolationNotDeleted    = "NOT_DELETED"
+	RestoreViolationDeletedAlong  = "DELETED_ALONG"
+	RestoreViolationParentDeleted = "PARENT_DELETED"
+)
+
+var _ error = (*RestoreError)(nil)
+
+// RestoreError tells that a resource cannot be restored from the trash. The subject is the ID of the resource causing the violation,
+// e.g. the resource the restored resource was deleted along with and which has to be restored instead.
+type RestoreError struct {
+	Type        string
+	Subject     string
+	Description string
+}
+
+func (e RestoreError) Error() string {
+	return fmt.Sprintf("the resource cannot be restored: %s", e.Description)
+}
+
+// Tombstone marks a resource as deleted. Embedded in a model it provides the delete_time column which makes bun leave out deleted
+// resources unless a query asks for them and the delete_cause column which holds the ID of the resource whose deletion deleted the
+// resource so that restoring that resource restores exactly the resources deleted along with it.
+type Tombstone struct {
+	DeleteTime  *time.Time `bun:",soft_delete,nullzero"`
+	DeleteCause string     `bun:",nullzero"`
+}
+
+// SoftDelete makes an update mark the resources it matches as deleted at the passed time because of the deletion of the resource with the passed ID
+func SoftDelete(q *bun.UpdateQuery, cause string, t time.Time) *bun.UpdateQuery {
+	return q.Set("delete_time = ?", t).Set("delete_cause = ?", cause)
+}
+
+// Undelete makes an update restore the resources it matches
+func Undelete(q *bun.UpdateQuery) *bun.UpdateQuery {
+	return q.Set("delete_time = NULL").Set("delete_cause = NULL")
+}
+
+// WhereDeletedBecauseOf restricts a query to the resources deleted because of the deletion of the resource with the passed ID
+func WhereDeletedBecauseOf(q bun.QueryBuilder, cause string) bun.QueryBuilder {
+	return q.WhereDeleted().Where("delete_cause = ?", cause)
+}
+
+// IsDeleted tells whether the resource is in the trash
+func (t *Tombstone) IsDeleted() bool {
+	return t.DeleteTime != nil
+}
+
+// intoProto returns the delete time as value of the delete_time field of a protobuf message
+func (t *Tombstone) intoProto() *timestamppb.Timestamp {
+	if t.DeleteTime == nil {
+		return nil
+	}
+	return timestamppb.New(*t.DeleteTime)
+}
+
+func (t *Tombstone) tombstone() *Tombstone {
+	return t
+}
+
+type deletableModel interface {
+	proto.Message
+	GetId() string
+	tombstone() *Tombstone
+}
+
+// CheckRestorable returns the deleted resource with the passed ID if it can be restored, i.e. if it was deleted directly.
+// It returns a util.ResourceNotFoundError if the resource does not exist and a RestoreError if it cannot be restored.
+func CheckRestorable[T deletableModel](ctx context.Context, db bun.IDB, id string) (T, error) {
+	resource, err := util.CheckResourceExistsWithDeleted[T](ctx, db, id)
+	if err != nil {
+		return resource, err
+	}
+	t := resource.tombstone()
+	if !t.IsDeleted() {
+		return resource, RestoreError{
+			Type:        RestoreViolationNotDeleted,
+			Subject:     id,
+			Description: fmt.Sprintf("the resource with ID %s is not deleted", id),
+		}
+	}
+	if t.DeleteCause != id {
+		return resource, RestoreError{
+			Type:        RestoreViolationDeletedAlong,
+			Subject:     t.DeleteCause,
+			Description: fmt.Sprintf("the resource was deleted along with the resource with ID %s which has to be restored instead", t.DeleteCause),
+		}
+	}
+	return resource, nil
+}
+
+// CheckParentNotDeleted returns a RestoreError if the resource with the passed ID a restored resource belongs to is deleted
+func CheckParentNotDeleted[T deletableModel](ctx context.Context, db bun.IDB, id string) error {
+	if _, err := util.CheckResourceExists[T](ctx, db, id); err != nil {
+		if resErr := new(util.ResourceNotFoundError); eris.As(err, resErr) {
+			return RestoreError{
+				Type:        RestoreViolationParentDeleted,
+				Subject:     id,
+				Description: fmt.Sprintf("the %s with ID %s the resource belongs to is deleted and has to be restored first", resErr.ResourceName, id),
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// Restore restores a resource returned by CheckRestorable from the trash
+func Restore(ctx context.Context, db bun.IDB, resource deletableModel) error {
+	if _, err := Undelete(db.NewUpdate().Model(resource)).WherePK().WhereDeleted().Exec(ctx); err != nil {
+		return err
+	}
+	*resource.tombstone() = Tombstone{}
+	return nil
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"time"
+
+	activityv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/activity/v1"
+	webhookv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/webhook/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Webhook struct {
+	webhookv1.Webhook
+	Metadata
+	Revision
+}
+
+// WebhookDelivery is the delivery of an activity to a webhook. Pending deliveries are attempted once their next attempt time passed.
+type WebhookDelivery struct {
+	webhookv1.WebhookDelivery
+	CreateTime      time.Time
+	LastAttemptTime *time.Time `bun:",nullzero"`
+	NextAttemptTime *time.Time `bun:",nullzero"`
+}
+
+func NewWebhook(webhook *webhookv1.Webhook, metadata Metadata) *Webhook {
+	return &Webhook{
+		Webhook: webhookv1.Webhook{
+			Id:                  webhook.GetId(),
+			GroupId:             webhook.GetGroupId(),
+			Url:                 webhook.GetUrl(),
+			Secret:              webhook.GetSecret(),
+			ResourceTypes:       webhook.GetResourceTypes(),
+			Actions:             webhook.GetActions(),
+			Disabled:            webhook.GetDisabled(),
+			DisabledReason:      webhook.GetDisabledReason(),
+			ConsecutiveFailures: webhook.GetConsecutiveFailures(),
+		},
+		Metadata: metadata,
+	}
+}
+
+// Matches tells if the filter of the webhook lets the passed activity through
+func (w *Webhook) Matches(activity *activityv1.Activity) bool {
+	return (len(w.GetResourceTypes()) == 0 || containsEnum(w.GetResourceTypes(), activity.GetResourceType())) &&
+		(len(w.GetActions()) == 0 || containsEnum(w.GetActions(), activity.GetAction()))
+}
+
+// IntoProtoWebhook returns the webhook without its secret which is never handed out
+func (w *Webhook) IntoProtoWebhook() *webhookv1.Webhook {
+	w.Webhook.CreateTime, w.Webhook.UpdateTime, w.Webhook.Creator, w.Webhook.LastModifier = w.Metadata.intoProto()
+	w.Webhook.Etag = w.Revision.Etag()
+	w.Webhook.Secret = ""
+	return &w.Webhook
+}
+
+func (d *WebhookDelivery) IntoProtoWebhookDelivery() *webhookv1.WebhookDelivery {
+	d.WebhookDelivery.CreateTime = timestamppb.New(d.CreateTime)
+	if d.LastAttemptTime != nil {
+		d.WebhookDelivery.LastAttemptTime = timestamppb.New(*d.LastAttemptTime)
+	}
+	if d.NextAttemptTime != nil {
+		d.WebhookDelivery.NextAttemptTime = timestamppb.New(*d.NextAttemptTime)
+	}
+	return &d.WebhookDelivery
+}
+
+func containsEnum[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
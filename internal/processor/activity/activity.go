@@ -0,0 +1,107 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/processor"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+type activityProcessor struct {
+	natsClient *nats.Conn
+	dbClient   bun.IDB
+}
+
+var errSelectSnapshot = eris.New("failed selecting snapshot of changed resource")
+var errInsertActivity = eris.New("failed inserting activity")
+var errConvertResource = eris.New("could not convert changed resource")
+
+// NewActivityProcessor creates a new instance of activity processor.
+func NewActivityProcessor(natsUrl string, dbConfig client.Config) (*activityProcessor, error) {
+	db, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed creating database client")
+	}
+	return NewActivityProcessorWithDBClient(natsUrl, db)
+}
+
+// NewActivityProcessorWithDBClient creates a new instance of activity processor using the passed database client.
+func NewActivityProcessorWithDBClient(natsUrl string, db bun.IDB) (*activityProcessor, error) {
+	nc, err := nats.Connect(natsUrl)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed connecting to NATS server")
+	}
+	return &activityProcessor{
+		natsClient: nc,
+		dbClient:   db,
+	}, nil
+}
+
+// Process starts the processing of subscriptions and returns a cancel function allowing for cancelation
+func (rpProcessor *activityProcessor) Process(ctx context.Context) error {
+	log := logging.FromContext(ctx).Named("Process")
+	ctx = logging.IntoContext(ctx, log)
+
+	// the recorded activities are published to a stream of their own so that other processors, e.g. the webhook processor,
+	// can consume them reliably while clients streaming them subscribe to the subject directly
+	if _, err := processor.CreateOrUpdateSourceStream(
+		ctx,
+		rpProcessor.natsClient,
+		environment.GetActivitySourceStreamName(),
+		fmt.Sprintf("%s.*", environment.GetActivitySubject("*", "*")),
+	); err != nil {
+		return err
+	}
+
+	// the activity log covers all events of the resources belonging to a group, i.e. all events but those of currencies and of the
+	// activities themselves; they are consumed from the source streams of the processors owning the resources, each of which is
+	// created with the same subject here in case this processor starts first
+	sources := []struct {
+		sourceStreamName string
+		subject          string
+		consumerName     string
+	}{
+		{environment.GetGroupSourceStreamName(), fmt.Sprintf("%s.*", environment.GetGroupSubject("*")), "EXPENSESPLITTER_ACTIVITY_PROCESSOR_GROUP"},
+		{environment.GetPersonSourceStreamName(), fmt.Sprintf("%s.*", environment.GetPersonSubject("*", "*")), "EXPENSESPLITTER_ACTIVITY_PROCESSOR_PERSON"},
+		{environment.GetCategorySourceStreamName(), fmt.Sprintf("%s.*", environment.GetCategorySubject("*", "*")), "EXPENSESPLITTER_ACTIVITY_PROCESSOR_CATEGORY"},
+		{environment.GetExpenseSourceStreamName(), fmt.Sprintf("%s.*", environment.GetExpenseSubject("*", "*")), "EXPENSESPLITTER_ACTIVITY_PROCESSOR_EXPENSE"},
+		{environment.GetExpenseStakeSourceStreamName(), fmt.Sprintf("%s.*", environment.GetExpenseStakeSubject("*", "*", "*")), "EXPENSESPLITTER_ACTIVITY_PROCESSOR_EXPENSESTAKE"},
+		{environment.GetExpenseCategoryRelationSourceStreamName(), fmt.Sprintf("%s.*", environment.GetExpenseCategoryRelationSubject("*", "*", "*")), "EXPENSESPLITTER_ACTIVITY_PROCESSOR_EXPENSECATEGORYRELATION"},
+		{environment.GetRecurringExpenseSourceStreamName(), fmt.Sprintf("%s.*", environment.GetRecurringExpenseSubject("*", "*")), "EXPENSESPLITTER_ACTIVITY_PROCESSOR_RECURRINGEXPENSE"},
+		{environment.GetAttachmentSourceStreamName(), fmt.Sprintf("%s.*", environment.GetAttachmentSubject("*", "*", "*")), "EXPENSESPLITTER_ACTIVITY_PROCESSOR_ATTACHMENT"},
+		{environment.GetCommentSourceStreamName(), fmt.Sprintf("%s.*", environment.GetCommentSubject("*", "*", "*")), "EXPENSESPLITTER_ACTIVITY_PROCESSOR_COMMENT"},
+		{environment.GetBudgetSourceStreamName(), fmt.Sprintf("%s.*", environment.GetBudgetSubject("*", "*")), "EXPENSESPLITTER_ACTIVITY_PROCESSOR_BUDGET"},
+		{environment.GetDebtReminderSourceStreamName(), fmt.Sprintf("%s.*", environment.GetDebtReminderSubject("*", "*")), "EXPENSESPLITTER_ACTIVITY_PROCESSOR_DEBTREMINDER"},
+	}
+
+	cCtxs := make([]jetstream.ConsumeContext, 0, len(sources))
+	for _, source := range sources {
+		if _, err := processor.CreateOrUpdateSourceStream(
+			ctx,
+			rpProcessor.natsClient,
+			source.sourceStreamName,
+			source.subject,
+		); err != nil {
+			processor.UnsubscribeConsumeContexts(cCtxs...)
+			return err
+		}
+		cCtx, err := processor.GetMsgStreamProcessor(ctx, rpProcessor.natsClient, source.sourceStreamName, source.consumerName, source.subject, rpProcessor.recordActivity)
+		if err != nil {
+			processor.UnsubscribeConsumeContexts(cCtxs...)
+			return eris.Wrapf(err, "an error occurred processing subject %s", source.subject)
+		}
+		cCtxs = append(cCtxs, cCtx)
+	}
+
+	<-ctx.Done()
+	log.Info("the context is done")
+	processor.UnsubscribeConsumeContexts(cCtxs...)
+	return nil
+}
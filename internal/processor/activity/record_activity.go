@@ -0,0 +1,185 @@
+package activity
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	activityv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/activity/v1"
+	attachmentv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/attachment/v1"
+	categoryv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/category/v1"
+	commentv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/comment/v1"
+	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expense/v1"
+	expensecategoryrelationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensecategoryrelation/v1"
+	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensestake/v1"
+	groupv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/group/v1"
+	personv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/person/v1"
+	recurringexpensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/recurringexpense/v1"
+	activityprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/activity/v1"
+	budgetprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/budget/v1"
+	debtreminderprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/debtreminder/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/diff"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
+	"github.com/rotisserie/eris"
+	"google.golang.org/protobuf/proto"
+)
+
+func (rpProcessor *activityProcessor) recordActivity(ctx context.Context, msg jetstream.Msg) error {
+	log := logging.FromContext(ctx).With(logging.String("subject", msg.Subject()))
+
+	subject, ok := parseActivitySubject(msg.Subject())
+	if !ok {
+		log.Debug("ignoring event which does not belong to a resource of a group")
+		return nil
+	}
+	log = log.With(logging.String("groupId", subject.groupId), logging.String("resourceId", subject.resourceId))
+	log.Info("recording activity")
+
+	current, err := eventFields(subject, msg)
+	if err != nil {
+		log.Error("failed reading state of changed resource from event", logging.Error(err))
+		return errConvertResource
+	}
+	previous, err := rpProcessor.retrieveSnapshot(ctx, subject)
+	if err != nil {
+		log.Error("failed getting snapshot of changed resource", logging.Error(err))
+		return errSelectSnapshot
+	}
+
+	// the state of the resource at the time of the event is diffed against the state recorded by the previous activity concerning
+	// the resource rather than the current state which may have changed again by now. Deleting and restoring a resource do not
+	// change its fields which is why the events telling about it carry no state and the recorded state is kept.
+	var before, after map[string]interface{}
+	snapshot := previous
+	switch subject.action {
+	case activityv1.Activity_ACTION_CREATED, activityv1.Activity_ACTION_UPDATED:
+		if current == nil {
+			log.Warn("the event carries no state of the changed resource")
+			break
+		}
+		if previous == nil || subject.action == activityv1.Activity_ACTION_CREATED {
+			after = current
+		} else {
+			before, after = diff.Changes(previous, current)
+		}
+		snapshot = current
+	case activityv1.Activity_ACTION_DELETED:
+		before = previous
+	case activityv1.Activity_ACTION_UNDELETED:
+		after = previous
+	default:
+		// the event tells what happened to the resource without changing it
+		after = current
+	}
+
+	activity, err := model.NewActivity(&activityv1.Activity{
+		Id:           util.GenerateIdWithPrefix("activity"),
+		GroupId:      subject.groupId,
+		Actor:        principal.FromContext(ctx),
+		Action:       subject.action,
+		ResourceType: subject.resourceType,
+		ResourceId:   subject.resourceId,
+		ExpenseId:    subject.expenseId,
+	}, before, after, snapshot, time.Now())
+	if err != nil {
+		log.Error("failed creating activity", logging.Error(err))
+		return errConvertResource
+	}
+	if _, err := rpProcessor.dbClient.NewInsert().Model(activity).Exec(ctx); err != nil {
+		log.Error("failed inserting activity", logging.Error(err))
+		return errInsertActivity
+	}
+
+	// the activity is already recorded and would be recorded twice if the event was redelivered, so failing to
+	// publish it only means that streaming clients miss it until they list the activities
+	protoActivity, err := activity.IntoProtoActivity()
+	if err != nil {
+		log.Error("failed converting activity", logging.Error(err))
+		return nil
+	}
+	marshalled, err := proto.Marshal(&activityprocv1.ActivityRecorded{
+		Activity: protoActivity,
+	})
+	if err != nil {
+		log.Error("failed marshalling activity recorded event", logging.Error(err))
+		return nil
+	}
+	if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetActivityRecordedSubject(subject.groupId, activity.Id), marshalled); err != nil {
+		log.Error("failed publishing activity recorded event", logging.Error(err))
+	}
+	return nil
+}
+
+// eventFields returns the fields of the state of the changed resource the event carries or nil if it carries none. Since events
+// not changing a resource tell what happened to it by themselves, the fields of such events are returned instead.
+func eventFields(subject *activitySubject, msg jetstream.Msg) (map[string]interface{}, error) {
+	message, err := newEventMessage(subject.resourceType)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := eventActions[subject.action]; ok {
+		if err := proto.Unmarshal(msg.Data(), message); err != nil {
+			return nil, eris.Wrap(err, "failed unmarshalling event")
+		}
+		return diff.Fields(message)
+	}
+	if ok, err := mqClient.EventResource(msg.Headers(), message); err != nil || !ok {
+		return nil, err
+	}
+	// the metadata is left out since it changes with every change and the activity itself tells who changed the resource when
+	return diff.Fields(message, model.MetadataFields...)
+}
+
+// newEventMessage returns the message the events of resources of the passed type carry, i.e. the resource itself or,
+// for resources whose events do not change them, the event
+func newEventMessage(resourceType activityv1.Activity_ResourceType) (proto.Message, error) {
+	switch resourceType {
+	case activityv1.Activity_RESOURCE_TYPE_GROUP:
+		return &groupv1.Group{}, nil
+	case activityv1.Activity_RESOURCE_TYPE_PERSON:
+		return &personv1.Person{}, nil
+	case activityv1.Activity_RESOURCE_TYPE_CATEGORY:
+		return &categoryv1.Category{}, nil
+	case activityv1.Activity_RESOURCE_TYPE_EXPENSE:
+		return &expensev1.Expense{}, nil
+	case activityv1.Activity_RESOURCE_TYPE_EXPENSE_STAKE:
+		return &expensestakev1.ExpenseStake{}, nil
+	case activityv1.Activity_RESOURCE_TYPE_EXPENSE_CATEGORY_RELATION:
+		return &expensecategoryrelationv1.ExpenseCategoryRelation{}, nil
+	case activityv1.Activity_RESOURCE_TYPE_RECURRING_EXPENSE:
+		return &recurringexpensev1.RecurringExpense{}, nil
+	case activityv1.Activity_RESOURCE_TYPE_ATTACHMENT:
+		return &attachmentv1.Attachment{}, nil
+	case activityv1.Activity_RESOURCE_TYPE_COMMENT:
+		return &commentv1.Comment{}, nil
+	case activityv1.Activity_RESOURCE_TYPE_BUDGET:
+		return &budgetprocv1.BudgetThresholdReached{}, nil
+	case activityv1.Activity_RESOURCE_TYPE_DEBT_REMINDER:
+		return &debtreminderprocv1.DebtReminderDue{}, nil
+	}
+	return nil, eris.Errorf("unknown resource type %s", resourceType)
+}
+
+// retrieveSnapshot returns the state of the changed resource recorded by its latest activity or nil if there is none
+func (rpProcessor *activityProcessor) retrieveSnapshot(ctx context.Context, subject *activitySubject) (map[string]interface{}, error) {
+	var activities []*model.Activity
+	query := rpProcessor.dbClient.NewSelect().Model(&activities).
+		Column("snapshot_json").
+		Where("resource_id = ?", subject.resourceId).
+		Where("resource_type = ?", subject.resourceType)
+	if subject.expenseId != "" {
+		query = query.Where("expense_id = ?", subject.expenseId)
+	}
+	if err := query.Order("id DESC").Limit(1).Scan(ctx); err != nil {
+		return nil, err
+	}
+	if len(activities) == 0 {
+		return nil, nil
+	}
+	return activities[0].Snapshot()
+}
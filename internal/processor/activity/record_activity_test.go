@@ -0,0 +1,263 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	activityv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/activity/v1"
+	personv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/person/v1"
+	activityprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/activity/v1"
+	budgetprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/budget/v1"
+	personprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/person/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	mqtesting "github.com/nico151999/high-availability-expense-splitter/pkg/mq/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// testMsg is a message of a stream carrying the subject, data and headers of a message received from NATS
+type testMsg struct {
+	jetstream.Msg
+	msg *nats.Msg
+}
+
+func (m *testMsg) Subject() string {
+	return m.msg.Subject
+}
+
+func (m *testMsg) Data() []byte {
+	return m.msg.Data
+}
+
+func (m *testMsg) Headers() nats.Header {
+	return m.msg.Header
+}
+
+func TestRecordActivity(t *testing.T) {
+	log := logging.GetLogger().Named("testRecordActivity")
+	ctx := principal.IntoContext(logging.IntoContext(context.Background(), log), "ab@c.de")
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	server, port := mqtesting.RunMQServer(-1)
+	defer server.Shutdown()
+	nc, err := nats.Connect(fmt.Sprintf("nats://127.0.0.1:%d", port))
+	if err != nil {
+		t.Fatalf("failed connecting to NATS server: %+v", err)
+	}
+	defer nc.Close()
+
+	rpProcessor := &activityProcessor{
+		natsClient: nc,
+		dbClient:   bun.NewDB(db, pgdialect.New()),
+	}
+
+	groupId := "group-123456789012345"
+	personId := "person-123456789012345"
+	budgetId := "budget-123456789012345"
+	snapshot := fmt.Sprintf(`{"id":"%s","group_id":"%s","name":"old-name"}`, personId, groupId)
+
+	// receive publishes the passed event like the services do and returns it as it is received from the stream
+	receive := func(t *testing.T, subject string, event proto.Message, resource proto.Message) jetstream.Msg {
+		t.Helper()
+		sub, err := nc.SubscribeSync(subject)
+		if err != nil {
+			t.Fatalf("failed subscribing: %+v", err)
+		}
+		defer sub.Unsubscribe()
+		data, err := proto.Marshal(event)
+		if err != nil {
+			t.Fatalf("failed marshalling event: %+v", err)
+		}
+		if resource != nil {
+			err = mqClient.PublishResourceEventData(ctx, nc, subject, data, resource)
+		} else {
+			err = mqClient.PublishEventData(ctx, nc, subject, data)
+		}
+		if err != nil {
+			t.Fatalf("failed publishing event: %+v", err)
+		}
+		msg, err := sub.NextMsg(time.Second)
+		if err != nil {
+			t.Fatalf("failed receiving event: %+v", err)
+		}
+		return &testMsg{msg: msg}
+	}
+	// record records the activity of the passed message and returns the activity published as recorded
+	record := func(t *testing.T, msg jetstream.Msg) *activityv1.Activity {
+		t.Helper()
+		sub, err := nc.SubscribeSync(environment.GetActivityRecordedSubject(groupId, "*"))
+		if err != nil {
+			t.Fatalf("failed subscribing: %+v", err)
+		}
+		defer sub.Unsubscribe()
+		if err := rpProcessor.recordActivity(ctx, msg); err != nil {
+			t.Fatalf("failed recording activity: %+v", err)
+		}
+		recorded, err := sub.NextMsg(time.Second)
+		if err != nil {
+			t.Fatalf("failed receiving activity recorded event: %+v", err)
+		}
+		var event activityprocv1.ActivityRecorded
+		if err := proto.Unmarshal(recorded.Data, &event); err != nil {
+			t.Fatalf("failed unmarshalling activity recorded event: %+v", err)
+		}
+		return event.GetActivity()
+	}
+	expectSnapshot := func(resourceId string, snapshot string) {
+		rows := sqlmock.NewRows([]string{"snapshot_json"})
+		if snapshot != "" {
+			rows.AddRow(snapshot)
+		}
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "activities" (.+) WHERE (.+)resource_id = '%s'(.+) ORDER BY id DESC LIMIT 1`, resourceId)).
+			WillReturnRows(rows)
+	}
+
+	t.Run("Record creation with the state carried by the event", func(t *testing.T) {
+		msg := receive(t, environment.GetPersonCreatedSubject(groupId, personId), &personprocv1.PersonCreated{
+			Id:      personId,
+			GroupId: groupId,
+			Name:    "new-name",
+		}, &personv1.Person{
+			Id:      personId,
+			GroupId: groupId,
+			Name:    "new-name",
+		})
+		expectSnapshot(personId, "")
+		mock.ExpectExec(`INSERT INTO "activities" (.+)"name":"new-name"(.+)`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		activity := record(t, msg)
+		if activity.GetAction() != activityv1.Activity_ACTION_CREATED || activity.GetResourceType() != activityv1.Activity_RESOURCE_TYPE_PERSON {
+			t.Errorf("expected the creation of a person; got: %+v", activity)
+		}
+		if actor := activity.GetActor(); actor != "ab@c.de" {
+			t.Errorf("expected the principal of the event as actor; got: %s", actor)
+		}
+		if activity.GetBefore() != nil {
+			t.Errorf("expected no fields before the creation; got: %+v", activity.GetBefore())
+		}
+		if name := activity.GetAfter().AsMap()["name"]; name != "new-name" {
+			t.Errorf("expected the fields of the created person; got: %+v", activity.GetAfter())
+		}
+		if _, ok := activity.GetAfter().AsMap()["etag"]; ok {
+			t.Errorf("expected the metadata to be left out; got: %+v", activity.GetAfter())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Record update diffing the state carried by the event against the snapshot", func(t *testing.T) {
+		msg := receive(t, environment.GetPersonUpdatedSubject(groupId, personId), &personprocv1.PersonUpdated{
+			Id:      personId,
+			GroupId: groupId,
+		}, &personv1.Person{
+			Id:      personId,
+			GroupId: groupId,
+			Name:    "new-name",
+		})
+		expectSnapshot(personId, snapshot)
+		mock.ExpectExec(`INSERT INTO "activities" (.+)`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		activity := record(t, msg)
+		before, after := activity.GetBefore().AsMap(), activity.GetAfter().AsMap()
+		if len(before) != 1 || before["name"] != "old-name" {
+			t.Errorf("expected only the name before the update; got: %+v", before)
+		}
+		if len(after) != 1 || after["name"] != "new-name" {
+			t.Errorf("expected only the name after the update; got: %+v", after)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Record deletion with the snapshot", func(t *testing.T) {
+		msg := receive(t, environment.GetPersonDeletedSubject(groupId, personId), &personprocv1.PersonDeleted{
+			Id:      personId,
+			GroupId: groupId,
+		}, nil)
+		expectSnapshot(personId, snapshot)
+		// the snapshot is kept so that restoring the person is recorded with its fields
+		mock.ExpectExec(`INSERT INTO "activities" (.+)"name":"old-name"(.+)`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		activity := record(t, msg)
+		if name := activity.GetBefore().AsMap()["name"]; name != "old-name" {
+			t.Errorf("expected the fields of the deleted person; got: %+v", activity.GetBefore())
+		}
+		if activity.GetAfter() != nil {
+			t.Errorf("expected no fields after the deletion; got: %+v", activity.GetAfter())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Record undeletion with the snapshot", func(t *testing.T) {
+		msg := receive(t, environment.GetPersonUndeletedSubject(groupId, personId), &personprocv1.PersonUndeleted{
+			Id:      personId,
+			GroupId: groupId,
+		}, nil)
+		expectSnapshot(personId, snapshot)
+		mock.ExpectExec(`INSERT INTO "activities" (.+)`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		activity := record(t, msg)
+		if activity.GetBefore() != nil {
+			t.Errorf("expected no fields before the undeletion; got: %+v", activity.GetBefore())
+		}
+		if name := activity.GetAfter().AsMap()["name"]; name != "old-name" {
+			t.Errorf("expected the fields of the restored person; got: %+v", activity.GetAfter())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Record reached budget threshold with the fields of the event", func(t *testing.T) {
+		msg := receive(t, environment.GetBudgetThresholdReachedSubject(groupId, budgetId), &budgetprocv1.BudgetThresholdReached{
+			BudgetId:        budgetId,
+			GroupId:         groupId,
+			CategoryId:      "category-123456789012345",
+			Threshold:       80,
+			PeriodStartTime: timestamppb.New(time.Unix(1693523248, 0)),
+			PeriodEndTime:   timestamppb.New(time.Unix(1696115248, 0)),
+		}, nil)
+		expectSnapshot(budgetId, "")
+		mock.ExpectExec(`INSERT INTO "activities" (.+)`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		activity := record(t, msg)
+		if activity.GetAction() != activityv1.Activity_ACTION_THRESHOLD_REACHED || activity.GetResourceType() != activityv1.Activity_RESOURCE_TYPE_BUDGET {
+			t.Errorf("expected a reached threshold of a budget; got: %+v", activity)
+		}
+		if threshold := activity.GetAfter().AsMap()["threshold"]; threshold != float64(80) {
+			t.Errorf("expected the reached threshold; got: %+v", activity.GetAfter())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Ignore event of currency", func(t *testing.T) {
+		msg := receive(t, environment.GetCurrencyCreatedSubject("currency-123456789012345"), &personprocv1.PersonCreated{}, nil)
+		if err := rpProcessor.recordActivity(ctx, msg); err != nil {
+			t.Fatalf("failed ignoring event: %+v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+}
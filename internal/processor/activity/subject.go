@@ -0,0 +1,93 @@
+package activity
+
+import (
+	"strings"
+
+	activityv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/activity/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+)
+
+// activitySubject describes the change of a resource an event subject tells about
+type activitySubject struct {
+	groupId      string
+	expenseId    string
+	resourceType activityv1.Activity_ResourceType
+	resourceId   string
+	action       activityv1.Activity_Action
+}
+
+var activityActions = map[string]activityv1.Activity_Action{
+	"created":          activityv1.Activity_ACTION_CREATED,
+	"updated":          activityv1.Activity_ACTION_UPDATED,
+	"deleted":          activityv1.Activity_ACTION_DELETED,
+	"undeleted":        activityv1.Activity_ACTION_UNDELETED,
+	"thresholdreached": activityv1.Activity_ACTION_THRESHOLD_REACHED,
+	"due":              activityv1.Activity_ACTION_DUE,
+}
+
+// eventActions are the actions which do not change a resource but tell that something happened to it; unlike
+// other actions they are limited to the resource type whose events they are
+var eventActions = map[activityv1.Activity_Action]activityv1.Activity_ResourceType{
+	activityv1.Activity_ACTION_THRESHOLD_REACHED: activityv1.Activity_RESOURCE_TYPE_BUDGET,
+	activityv1.Activity_ACTION_DUE:               activityv1.Activity_RESOURCE_TYPE_DEBT_REMINDER,
+}
+
+// parseActivitySubject returns the change the passed event subject tells about or false if the subject does not belong
+// to a resource of a group. The resource subjects are rebuilt from the parsed IDs so that they are only defined by the environment.
+func parseActivitySubject(subject string) (*activitySubject, bool) {
+	i := strings.LastIndex(subject, ".")
+	if i < 0 {
+		return nil, false
+	}
+	action, ok := activityActions[subject[i+1:]]
+	if !ok {
+		return nil, false
+	}
+	resourceSubject := subject[:i]
+	tokens := strings.Split(resourceSubject, ".")
+	s := &activitySubject{
+		action: action,
+	}
+	switch len(tokens) {
+	case 3:
+		s.groupId, s.resourceId = tokens[2], tokens[2]
+		if resourceSubject == environment.GetGroupSubject(s.groupId) {
+			s.resourceType = activityv1.Activity_RESOURCE_TYPE_GROUP
+		}
+	case 5:
+		s.groupId, s.resourceId = tokens[2], tokens[4]
+		switch resourceSubject {
+		case environment.GetPersonSubject(s.groupId, s.resourceId):
+			s.resourceType = activityv1.Activity_RESOURCE_TYPE_PERSON
+		case environment.GetCategorySubject(s.groupId, s.resourceId):
+			s.resourceType = activityv1.Activity_RESOURCE_TYPE_CATEGORY
+		case environment.GetExpenseSubject(s.groupId, s.resourceId):
+			s.resourceType = activityv1.Activity_RESOURCE_TYPE_EXPENSE
+		case environment.GetRecurringExpenseSubject(s.groupId, s.resourceId):
+			s.resourceType = activityv1.Activity_RESOURCE_TYPE_RECURRING_EXPENSE
+		case environment.GetBudgetSubject(s.groupId, s.resourceId):
+			s.resourceType = activityv1.Activity_RESOURCE_TYPE_BUDGET
+		case environment.GetDebtReminderSubject(s.groupId, s.resourceId):
+			s.resourceType = activityv1.Activity_RESOURCE_TYPE_DEBT_REMINDER
+		}
+	case 7:
+		s.groupId, s.expenseId, s.resourceId = tokens[2], tokens[4], tokens[6]
+		switch resourceSubject {
+		case environment.GetExpenseStakeSubject(s.groupId, s.expenseId, s.resourceId):
+			s.resourceType = activityv1.Activity_RESOURCE_TYPE_EXPENSE_STAKE
+		case environment.GetExpenseCategoryRelationSubject(s.groupId, s.expenseId, s.resourceId):
+			s.resourceType = activityv1.Activity_RESOURCE_TYPE_EXPENSE_CATEGORY_RELATION
+		case environment.GetAttachmentSubject(s.groupId, s.expenseId, s.resourceId):
+			s.resourceType = activityv1.Activity_RESOURCE_TYPE_ATTACHMENT
+		case environment.GetCommentSubject(s.groupId, s.expenseId, s.resourceId):
+			s.resourceType = activityv1.Activity_RESOURCE_TYPE_COMMENT
+		}
+	}
+	if s.resourceType == activityv1.Activity_RESOURCE_TYPE_UNSPECIFIED {
+		return nil, false
+	}
+	if resourceType, ok := eventActions[s.action]; ok && resourceType != s.resourceType {
+		return nil, false
+	}
+	return s, true
+}
@@ -0,0 +1,97 @@
+package activity
+
+import (
+	"testing"
+
+	activityv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/activity/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+)
+
+func TestParseActivitySubject(t *testing.T) {
+	groupId := "group-123456789012345"
+	expenseId := "expense-123456789012345"
+	resourceId := "resource-123456789012345"
+
+	for name, params := range map[string]struct {
+		subject  string
+		expected *activitySubject
+	}{
+		"Parse created group": {
+			environment.GetGroupCreatedSubject(groupId),
+			&activitySubject{groupId: groupId, resourceType: activityv1.Activity_RESOURCE_TYPE_GROUP, resourceId: groupId, action: activityv1.Activity_ACTION_CREATED},
+		},
+		"Parse updated person": {
+			environment.GetPersonUpdatedSubject(groupId, resourceId),
+			&activitySubject{groupId: groupId, resourceType: activityv1.Activity_RESOURCE_TYPE_PERSON, resourceId: resourceId, action: activityv1.Activity_ACTION_UPDATED},
+		},
+		"Parse deleted category": {
+			environment.GetCategoryDeletedSubject(groupId, resourceId),
+			&activitySubject{groupId: groupId, resourceType: activityv1.Activity_RESOURCE_TYPE_CATEGORY, resourceId: resourceId, action: activityv1.Activity_ACTION_DELETED},
+		},
+		"Parse undeleted expense": {
+			environment.GetExpenseUndeletedSubject(groupId, resourceId),
+			&activitySubject{groupId: groupId, resourceType: activityv1.Activity_RESOURCE_TYPE_EXPENSE, resourceId: resourceId, action: activityv1.Activity_ACTION_UNDELETED},
+		},
+		"Parse created expense stake": {
+			environment.GetExpenseStakeCreatedSubject(groupId, expenseId, resourceId),
+			&activitySubject{groupId: groupId, expenseId: expenseId, resourceType: activityv1.Activity_RESOURCE_TYPE_EXPENSE_STAKE, resourceId: resourceId, action: activityv1.Activity_ACTION_CREATED},
+		},
+		"Parse created expense category relation": {
+			environment.GetExpenseCategoryRelationCreatedSubject(groupId, expenseId, resourceId),
+			&activitySubject{groupId: groupId, expenseId: expenseId, resourceType: activityv1.Activity_RESOURCE_TYPE_EXPENSE_CATEGORY_RELATION, resourceId: resourceId, action: activityv1.Activity_ACTION_CREATED},
+		},
+		"Parse updated recurring expense": {
+			environment.GetRecurringExpenseUpdatedSubject(groupId, resourceId),
+			&activitySubject{groupId: groupId, resourceType: activityv1.Activity_RESOURCE_TYPE_RECURRING_EXPENSE, resourceId: resourceId, action: activityv1.Activity_ACTION_UPDATED},
+		},
+		"Parse created attachment": {
+			environment.GetAttachmentCreatedSubject(groupId, expenseId, resourceId),
+			&activitySubject{groupId: groupId, expenseId: expenseId, resourceType: activityv1.Activity_RESOURCE_TYPE_ATTACHMENT, resourceId: resourceId, action: activityv1.Activity_ACTION_CREATED},
+		},
+		"Parse updated comment": {
+			environment.GetCommentUpdatedSubject(groupId, expenseId, resourceId),
+			&activitySubject{groupId: groupId, expenseId: expenseId, resourceType: activityv1.Activity_RESOURCE_TYPE_COMMENT, resourceId: resourceId, action: activityv1.Activity_ACTION_UPDATED},
+		},
+		"Parse reached budget threshold": {
+			environment.GetBudgetThresholdReachedSubject(groupId, resourceId),
+			&activitySubject{groupId: groupId, resourceType: activityv1.Activity_RESOURCE_TYPE_BUDGET, resourceId: resourceId, action: activityv1.Activity_ACTION_THRESHOLD_REACHED},
+		},
+		"Parse due debt reminder": {
+			environment.GetDebtReminderDueSubject(groupId, resourceId),
+			&activitySubject{groupId: groupId, resourceType: activityv1.Activity_RESOURCE_TYPE_DEBT_REMINDER, resourceId: resourceId, action: activityv1.Activity_ACTION_DUE},
+		},
+		"Ignore currency": {
+			environment.GetCurrencyCreatedSubject(resourceId),
+			nil,
+		},
+		"Ignore recorded activity": {
+			environment.GetActivityRecordedSubject(groupId, resourceId),
+			nil,
+		},
+		"Ignore event action of other resource type": {
+			environment.GetPersonSubject(groupId, resourceId) + ".due",
+			nil,
+		},
+		"Ignore unknown action": {
+			environment.GetPersonSubject(groupId, resourceId) + ".renamed",
+			nil,
+		},
+	} {
+		params := params
+		t.Run(name, func(t *testing.T) {
+			s, ok := parseActivitySubject(params.subject)
+			if params.expected == nil {
+				if ok {
+					t.Fatalf("expected subject %s to be ignored but got: %+v", params.subject, s)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("expected subject %s to be parsed", params.subject)
+			}
+			if *s != *params.expected {
+				t.Errorf("expected: %+v; got: %+v", params.expected, s)
+			}
+		})
+	}
+}
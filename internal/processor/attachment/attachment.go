@@ -0,0 +1,126 @@
+package attachment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/blob"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/election"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/processor"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+type attachmentProcessor struct {
+	natsClient *nats.Conn
+	dbClient   bun.IDB
+	blobStore  blob.Store
+}
+
+const tickerPeriod = time.Hour
+const leaseDuration = 15 * time.Second
+const leaderElectionKey = "attachment-purge"
+
+var errDeleteAttachments = eris.New("failed deleting attachments")
+var errMarshalAttachmentDeleted = eris.New("could not marshal attachment deleted message")
+var errPublishAttachmentDeleted = eris.New("could not publish attachment deleted event")
+var errUndeleteAttachments = eris.New("failed restoring attachments")
+var errMarshalAttachmentUndeleted = eris.New("could not marshal attachment undeleted message")
+var errPublishAttachmentUndeleted = eris.New("could not publish attachment undeleted event")
+var errDeleteContent = eris.New("failed deleting content of attachment")
+var errPurgeTombstones = eris.New("failed purging attachment tombstones")
+
+// NewAttachmentProcessor creates a new instance of attachment processor.
+func NewAttachmentProcessor(natsUrl string, dbConfig client.Config, blobConfig blob.Config) (*attachmentProcessor, error) {
+	db, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed creating database client")
+	}
+	blobStore, err := blob.NewStore(blobConfig)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed creating blob store")
+	}
+	return NewAttachmentProcessorWithClients(natsUrl, db, blobStore)
+}
+
+// NewAttachmentProcessorWithClients creates a new instance of attachment processor using the passed database client and blob store.
+func NewAttachmentProcessorWithClients(natsUrl string, db bun.IDB, blobStore blob.Store) (*attachmentProcessor, error) {
+	nc, err := nats.Connect(natsUrl)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed connecting to NATS server")
+	}
+	return &attachmentProcessor{
+		natsClient: nc,
+		dbClient:   db,
+		blobStore:  blobStore,
+	}, nil
+}
+
+// Process starts the processing of subscriptions and returns a cancel function allowing for cancelation
+func (rpProcessor *attachmentProcessor) Process(ctx context.Context) error {
+	log := logging.FromContext(ctx).Named("Process")
+	ctx = logging.IntoContext(ctx, log)
+
+	sourceStreamName := environment.GetAttachmentSourceStreamName()
+	expenseSourceStreamName := environment.GetExpenseSourceStreamName()
+
+	_, err := processor.CreateOrUpdateSourceStream(
+		ctx,
+		rpProcessor.natsClient,
+		sourceStreamName,
+		fmt.Sprintf("%s.*", environment.GetAttachmentSubject("*", "*", "*")),
+	)
+	if err != nil {
+		return err
+	}
+
+	var adCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetAttachmentDeletedSubject("*", "*", "*")
+		var err error
+		adCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, sourceStreamName, "EXPENSESPLITTER_ATTACHMENT_PROCESSOR_ATTACHMENT_DELETED", eventSubject, rpProcessor.attachmentDeleted)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var edCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetExpenseDeletedSubject("*", "*")
+		var err error
+		edCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, expenseSourceStreamName, "EXPENSESPLITTER_ATTACHMENT_PROCESSOR_EXPENSE_DELETED", eventSubject, rpProcessor.expenseDeleted)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var euCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetExpenseUndeletedSubject("*", "*")
+		var err error
+		euCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, expenseSourceStreamName, "EXPENSESPLITTER_ATTACHMENT_PROCESSOR_EXPENSE_UNDELETED", eventSubject, rpProcessor.expenseUndeleted)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+
+	leaderElection, err := election.NewLeaderElection(
+		ctx,
+		rpProcessor.natsClient,
+		environment.GetLeaderElectionBucketName(),
+		leaderElectionKey,
+		leaseDuration)
+	if err != nil {
+		processor.UnsubscribeConsumeContexts(adCCtx, edCCtx, euCCtx)
+		return eris.Wrap(err, "failed creating leader election for periodic attachment purges")
+	}
+	// only the leading replica purges tombstones periodically so that replicas do not race each other
+	leaderElection.Run(ctx, rpProcessor.purgeTombstonesPeriodically)
+
+	processor.UnsubscribeConsumeContexts(adCCtx, edCCtx, euCCtx)
+	return nil
+}
@@ -0,0 +1,23 @@
+package attachment
+
+import (
+	"context"
+
+	attachmentprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/attachment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+func (rpProcessor *attachmentProcessor) attachmentDeleted(ctx context.Context, req *attachmentprocv1.AttachmentDeleted) error {
+	log := logging.FromContext(ctx).With(logging.String("attachmentId", req.GetId()))
+	log.Info("processing attachment.AttachmentDeleted event")
+
+	// attachments deleted along with their expense are kept in the trash and their content is only deleted once they are purged
+	if req.GetDeleteCause() != "" {
+		return nil
+	}
+	if err := rpProcessor.blobStore.Delete(ctx, req.GetId()); err != nil {
+		log.Error("failed deleting content of deleted attachment", logging.Error(err))
+		return errDeleteContent
+	}
+	return nil
+}
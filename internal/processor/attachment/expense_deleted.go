@@ -0,0 +1,59 @@
+package attachment
+
+import (
+	"context"
+	"time"
+
+	attachmentprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/attachment/v1"
+	expenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/uptrace/bun"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
+)
+
+func (rpProcessor *attachmentProcessor) expenseDeleted(ctx context.Context, req *expenseprocv1.ExpenseDeleted) error {
+	log := logging.FromContext(ctx).With(logging.String("expenseId", req.GetId()))
+	log.Info("processing expense.ExpenseDeleted event")
+
+	var attachments []*model.Attachment
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := util.UpdateMatchedReturning(ctx, tx, model.SoftDelete(tx.NewUpdate().Model(&attachments), req.GetDeleteCause(), time.Now()), func(q bun.QueryBuilder) bun.QueryBuilder {
+			return q.Where("expense_id = ?", req.GetId())
+		}, "id"); err != nil {
+			log.Error("failed deleting attachments related to deleted expense", logging.Error(err))
+			return errDeleteAttachments
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	g, _ := errgroup.WithContext(ctx)
+	for _, c := range attachments {
+		attachment := c
+		g.Go(func() error {
+			marshalled, err := proto.Marshal(&attachmentprocv1.AttachmentDeleted{
+				Id:          attachment.Id,
+				ExpenseId:   req.GetId(),
+				GroupId:     req.GetGroupId(),
+				DeleteCause: req.GetDeleteCause(),
+			})
+			if err != nil {
+				log.Error("failed marshalling attachment deleted event", logging.Error(err))
+				return errMarshalAttachmentDeleted
+			}
+			if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetAttachmentDeletedSubject(req.GetGroupId(), req.GetId(), attachment.Id), marshalled); err != nil {
+				log.Error("failed publishing attachment deleted event", logging.Error(err))
+				return errPublishAttachmentDeleted
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
@@ -0,0 +1,58 @@
+package attachment
+
+import (
+	"context"
+
+	attachmentprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/attachment/v1"
+	expenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/uptrace/bun"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
+)
+
+func (rpProcessor *attachmentProcessor) expenseUndeleted(ctx context.Context, req *expenseprocv1.ExpenseUndeleted) error {
+	log := logging.FromContext(ctx).With(logging.String("expenseId", req.GetId()))
+	log.Info("processing expense.ExpenseUndeleted event")
+
+	var attachments []*model.Attachment
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := util.UpdateMatchedReturning(ctx, tx, model.Undelete(tx.NewUpdate().Model(&attachments)), func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereDeletedBecauseOf(q.Where("expense_id = ?", req.GetId()), req.GetDeleteCause())
+		}, "id"); err != nil {
+			log.Error("failed restoring attachments related to restored expense", logging.Error(err))
+			return errUndeleteAttachments
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	g, _ := errgroup.WithContext(ctx)
+	for _, c := range attachments {
+		attachment := c
+		g.Go(func() error {
+			marshalled, err := proto.Marshal(&attachmentprocv1.AttachmentUndeleted{
+				Id:          attachment.Id,
+				ExpenseId:   req.GetId(),
+				GroupId:     req.GetGroupId(),
+				DeleteCause: req.GetDeleteCause(),
+			})
+			if err != nil {
+				log.Error("failed marshalling attachment undeleted event", logging.Error(err))
+				return errMarshalAttachmentUndeleted
+			}
+			if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetAttachmentUndeletedSubject(req.GetGroupId(), req.GetId(), attachment.Id), marshalled); err != nil {
+				log.Error("failed publishing attachment undeleted event", logging.Error(err))
+				return errPublishAttachmentUndeleted
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
@@ -0,0 +1,65 @@
+package attachment
+
+import (
+	"context"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+// purgeTombstonesPeriodically purges the attachment tombstones initially and then once per ticker period until the context is done
+func (rpProcessor *attachmentProcessor) purgeTombstonesPeriodically(ctx context.Context) {
+	log := logging.FromContext(ctx)
+
+	if err := rpProcessor.purgeTombstones(ctx); err != nil {
+		log.Error("could not purge attachment tombstones initially", logging.Error(err))
+	} else {
+		log.Info("successfully purged attachment tombstones initially")
+	}
+
+	ticker := time.NewTicker(tickerPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := rpProcessor.purgeTombstones(ctx); err != nil {
+				log.Error("could not purge attachment tombstones", logging.Error(err))
+			} else {
+				log.Info("successfully purged attachment tombstones")
+			}
+		case <-ctx.Done():
+			log.Info("stopped purging attachment tombstones")
+			return
+		}
+	}
+}
+
+// purgeTombstones permanently deletes the attachments that have been in the trash for longer than the tombstone retention.
+// The content of an attachment is deleted before the attachment itself so that no content outlives its attachment if deleting it fails.
+func (rpProcessor *attachmentProcessor) purgeTombstones(ctx context.Context) error {
+	log := logging.FromContext(ctx)
+
+	deletedBefore := time.Now().Add(-environment.GetTombstoneRetention(ctx))
+	var attachmentIds []string
+	if err := rpProcessor.dbClient.NewSelect().Model((*model.Attachment)(nil)).Column("id").WhereDeleted().Where("delete_time < ?", deletedBefore).Scan(ctx, &attachmentIds); err != nil {
+		log.Error("failed selecting attachment tombstones", logging.Error(err))
+		return errPurgeTombstones
+	}
+	for _, attachmentId := range attachmentIds {
+		if err := rpProcessor.blobStore.Delete(ctx, attachmentId); err != nil {
+			log.Error("failed deleting content of attachment tombstone", logging.String("attachmentId", attachmentId), logging.Error(err))
+			return errPurgeTombstones
+		}
+		if _, err := rpProcessor.dbClient.NewDelete().Model((*model.Attachment)(nil)).WhereDeleted().ForceDelete().Where("id = ?", attachmentId).Exec(ctx); err != nil {
+			log.Error("failed purging attachment tombstone", logging.String("attachmentId", attachmentId), logging.Error(err))
+			return errPurgeTombstones
+		}
+	}
+	if len(attachmentIds) > 0 {
+		log.Info("purged attachment tombstones", logging.Int("count", len(attachmentIds)))
+	}
+	return nil
+}
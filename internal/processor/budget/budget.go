@@ -0,0 +1,137 @@
+package budget
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	curClient "github.com/nico151999/high-availability-expense-splitter/pkg/currency/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/processor"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+type budgetProcessor struct {
+	natsClient     *nats.Conn
+	dbClient       bun.IDB
+	currencyClient curClient.Client
+}
+
+var errSelectExpense = eris.New("failed selecting expense")
+var errSelectGroup = eris.New("failed selecting group of expense")
+var errSelectCurrency = eris.New("failed selecting currency of group")
+var errSelectBudgets = eris.New("failed selecting budgets of expense")
+var errSelectBudgetExpenses = eris.New("failed selecting expenses of budget")
+var errGetExchangeRate = eris.New("failed getting exchange rate into currency of group")
+var errClaimAlert = eris.New("failed claiming budget alert")
+var errReleaseAlert = eris.New("failed releasing budget alert")
+var errMarshalBudgetThresholdReached = eris.New("failed marshalling budget threshold reached event")
+var errPublishBudgetThresholdReached = eris.New("failed publishing budget threshold reached event")
+
+// NewBudgetProcessor creates a new instance of budget processor.
+func NewBudgetProcessor(natsUrl string, dbConfig client.Config) (*budgetProcessor, error) {
+	db, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed creating database client")
+	}
+	return NewBudgetProcessorWithDBClient(natsUrl, db)
+}
+
+// NewBudgetProcessorWithDBClient creates a new instance of budget processor using the passed database client.
+func NewBudgetProcessorWithDBClient(natsUrl string, db bun.IDB) (*budgetProcessor, error) {
+	nc, err := nats.Connect(natsUrl)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed connecting to NATS server")
+	}
+	return &budgetProcessor{
+		natsClient:     nc,
+		dbClient:       db,
+		currencyClient: curClient.NewCachingCurrencyClient(curClient.NewCurrencyClient()),
+	}, nil
+}
+
+// Process starts the processing of subscriptions and returns a cancel function allowing for cancelation
+func (rpProcessor *budgetProcessor) Process(ctx context.Context) error {
+	log := logging.FromContext(ctx).Named("Process")
+	ctx = logging.IntoContext(ctx, log)
+
+	expenseSourceStreamName := environment.GetExpenseSourceStreamName()
+	expenseStakeSourceStreamName := environment.GetExpenseStakeSourceStreamName()
+	expenseCategoryRelationSourceStreamName := environment.GetExpenseCategoryRelationSourceStreamName()
+
+	// the budget source stream carries the threshold events this processor publishes; the others are owned by the processors of the
+	// resources the spending is made of and created with the same subjects here in case this processor starts first
+	for _, source := range []struct {
+		sourceStreamName string
+		subject          string
+	}{
+		{environment.GetBudgetSourceStreamName(), fmt.Sprintf("%s.*", environment.GetBudgetSubject("*", "*"))},
+		{expenseSourceStreamName, fmt.Sprintf("%s.*", environment.GetExpenseSubject("*", "*"))},
+		{expenseStakeSourceStreamName, fmt.Sprintf("%s.*", environment.GetExpenseStakeSubject("*", "*", "*"))},
+		{expenseCategoryRelationSourceStreamName, fmt.Sprintf("%s.*", environment.GetExpenseCategoryRelationSubject("*", "*", "*"))},
+	} {
+		if _, err := processor.CreateOrUpdateSourceStream(
+			ctx,
+			rpProcessor.natsClient,
+			source.sourceStreamName,
+			source.subject,
+		); err != nil {
+			return err
+		}
+	}
+
+	var ecCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetExpenseCreatedSubject("*", "*")
+		var err error
+		ecCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, expenseSourceStreamName, "EXPENSESPLITTER_BUDGET_PROCESSOR_EXPENSE_CREATED", eventSubject, rpProcessor.expenseCreated)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var euCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetExpenseUpdatedSubject("*", "*")
+		var err error
+		euCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, expenseSourceStreamName, "EXPENSESPLITTER_BUDGET_PROCESSOR_EXPENSE_UPDATED", eventSubject, rpProcessor.expenseUpdated)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var escCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetExpenseStakeCreatedSubject("*", "*", "*")
+		var err error
+		escCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, expenseStakeSourceStreamName, "EXPENSESPLITTER_BUDGET_PROCESSOR_EXPENSESTAKE_CREATED", eventSubject, rpProcessor.expenseStakeCreated)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var esuCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetExpenseStakeUpdatedSubject("*", "*", "*")
+		var err error
+		esuCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, expenseStakeSourceStreamName, "EXPENSESPLITTER_BUDGET_PROCESSOR_EXPENSESTAKE_UPDATED", eventSubject, rpProcessor.expenseStakeUpdated)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var ecrcCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetExpenseCategoryRelationCreatedSubject("*", "*", "*")
+		var err error
+		ecrcCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, expenseCategoryRelationSourceStreamName, "EXPENSESPLITTER_BUDGET_PROCESSOR_EXPENSECATEGORYRELATION_CREATED", eventSubject, rpProcessor.expenseCategoryRelationCreated)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+
+	<-ctx.Done()
+	log.Info("the context is done")
+	processor.UnsubscribeConsumeContexts(ecCCtx, euCCtx, escCCtx, esuCCtx, ecrcCCtx)
+	return nil
+}
@@ -0,0 +1,151 @@
+package budget
+
+import (
+	"context"
+	"time"
+
+	budgetprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/budget/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/balance"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/budget"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// checkBudgets publishes a BudgetThresholdReached event for every threshold the spending reaches of the budgets of the categories of the
+// expense within the period the expense is in. Each threshold is alerted once per period which is ensured by claiming a budget alert
+// before publishing; the claim is released if publishing fails so that the event is published on redelivery.
+func (rpProcessor *budgetProcessor) checkBudgets(ctx context.Context, expenseId string) error {
+	log := logging.FromContext(ctx)
+
+	expense, err := util.CheckResourceExists[*model.Expense](ctx, rpProcessor.dbClient, expenseId)
+	if err != nil {
+		if eris.As(err, &util.ResourceNotFoundError{}) {
+			log.Debug("not checking the budgets of a deleted expense")
+			return nil
+		}
+		log.Error("failed selecting expense", logging.Error(err))
+		return errSelectExpense
+	}
+
+	var budgets []*model.Budget
+	if err := rpProcessor.dbClient.NewSelect().Model(&budgets).
+		Where("group_id = ?", expense.GetGroupId()).
+		Where("category_id IN (?)", rpProcessor.dbClient.NewSelect().Model((*model.ExpenseCategoryRelation)(nil)).
+			Column("category_id").
+			Where("expense_id = ?", expenseId)).
+		Scan(ctx); err != nil {
+		log.Error("failed selecting budgets of categories of expense", logging.Error(err))
+		return errSelectBudgets
+	}
+	if len(budgets) == 0 {
+		return nil
+	}
+
+	group, err := util.CheckResourceExists[*model.Group](ctx, rpProcessor.dbClient, expense.GetGroupId())
+	if err != nil {
+		if eris.As(err, &util.ResourceNotFoundError{}) {
+			log.Debug("not checking the budgets of a deleted group")
+			return nil
+		}
+		log.Error("failed selecting group of expense", logging.Error(err))
+		return errSelectGroup
+	}
+	currency, err := util.CheckResourceExists[*model.Currency](ctx, rpProcessor.dbClient, group.GetCurrencyId())
+	if err != nil {
+		log.Error("failed selecting currency of group", logging.Error(err))
+		return errSelectCurrency
+	}
+
+	for _, b := range budgets {
+		log := log.With(logging.String("budgetId", b.GetId()))
+		ctx := logging.IntoContext(ctx, log)
+
+		period, ok := b.PeriodAt(expense.Timestamp.AsTime())
+		if !ok {
+			continue
+		}
+		expenses, err := model.SelectBudgetExpenses(ctx, rpProcessor.dbClient, b, period)
+		if err != nil {
+			log.Error("failed selecting expenses of budget", logging.Error(err))
+			return errSelectBudgetExpenses
+		}
+		spent, err := budget.Spent(ctx, expenses, currency.GetAcronym(), rpProcessor.currencyClient.GetExchangeRate)
+		if err != nil {
+			log.Error("failed getting exchange rate into currency of group", logging.Error(err))
+			return errGetExchangeRate
+		}
+		for _, threshold := range budget.Reached(spent, b.Amount()) {
+			if err := rpProcessor.alert(ctx, b, period, threshold, group.GetCurrencyId(), spent); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// alert publishes a BudgetThresholdReached event unless the threshold has already been alerted within the period
+func (rpProcessor *budgetProcessor) alert(ctx context.Context, b *model.Budget, period budget.Period, threshold int32, currencyId string, spent int64) error {
+	log := logging.FromContext(ctx).With(logging.Int32("threshold", threshold))
+
+	alert := &model.BudgetAlert{
+		BudgetId:        b.GetId(),
+		PeriodStartTime: period.Start,
+		Threshold:       threshold,
+		CreateTime:      time.Now(),
+	}
+	res, err := rpProcessor.dbClient.NewInsert().Model(alert).
+		On("CONFLICT DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		log.Error("failed claiming budget alert", logging.Error(err))
+		return errClaimAlert
+	}
+	if rows, err := res.RowsAffected(); err != nil {
+		log.Error("failed claiming budget alert", logging.Error(err))
+		return errClaimAlert
+	} else if rows == 0 {
+		log.Debug("the threshold has already been alerted within the period")
+		return nil
+	}
+
+	if err := rpProcessor.publishBudgetThresholdReached(ctx, b, period, threshold, currencyId, spent); err != nil {
+		if _, delErr := rpProcessor.dbClient.NewDelete().Model(alert).WherePK().Exec(ctx); delErr != nil {
+			log.Error("failed releasing budget alert", logging.Error(delErr))
+			return errReleaseAlert
+		}
+		return err
+	}
+	return nil
+}
+
+func (rpProcessor *budgetProcessor) publishBudgetThresholdReached(ctx context.Context, b *model.Budget, period budget.Period, threshold int32, currencyId string, spent int64) error {
+	log := logging.FromContext(ctx)
+
+	spentMainValue, spentFractionalValue := balance.Split(spent)
+	marshalled, err := proto.Marshal(&budgetprocv1.BudgetThresholdReached{
+		BudgetId:             b.GetId(),
+		GroupId:              b.GetGroupId(),
+		CategoryId:           b.GetCategoryId(),
+		Threshold:            threshold,
+		PeriodStartTime:      timestamppb.New(period.Start),
+		PeriodEndTime:        timestamppb.New(period.End),
+		CurrencyId:           currencyId,
+		SpentMainValue:       spentMainValue,
+		SpentFractionalValue: spentFractionalValue,
+	})
+	if err != nil {
+		log.Error("failed marshalling budget threshold reached event", logging.Error(err))
+		return errMarshalBudgetThresholdReached
+	}
+	if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetBudgetThresholdReachedSubject(b.GetGroupId(), b.GetId()), marshalled); err != nil {
+		log.Error("failed publishing budget threshold reached event", logging.Error(err))
+		return errPublishBudgetThresholdReached
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package budget
+
+import (
+	"context"
+
+	expensecategoryrelationprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expensecategoryrelation/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+func (rpProcessor *budgetProcessor) expenseCategoryRelationCreated(ctx context.Context, req *expensecategoryrelationprocv1.ExpenseCategoryRelationCreated) error {
+	log := logging.FromContext(ctx).With(
+		logging.String("expenseId", req.GetExpenseId()))
+	log.Info("processing expensecategoryrelation.ExpenseCategoryRelationCreated event")
+
+	return rpProcessor.checkBudgets(logging.IntoContext(ctx, log), req.GetExpenseId())
+}
@@ -0,0 +1,16 @@
+package budget
+
+import (
+	"context"
+
+	expensestakeprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expensestake/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+func (rpProcessor *budgetProcessor) expenseStakeCreated(ctx context.Context, req *expensestakeprocv1.ExpenseStakeCreated) error {
+	log := logging.FromContext(ctx).With(
+		logging.String("expenseId", req.GetExpenseId()))
+	log.Info("processing expensestake.ExpenseStakeCreated event")
+
+	return rpProcessor.checkBudgets(logging.IntoContext(ctx, log), req.GetExpenseId())
+}
@@ -0,0 +1,16 @@
+package budget
+
+import (
+	"context"
+
+	expenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+func (rpProcessor *budgetProcessor) expenseUpdated(ctx context.Context, req *expenseprocv1.ExpenseUpdated) error {
+	log := logging.FromContext(ctx).With(
+		logging.String("expenseId", req.GetId()))
+	log.Info("processing expense.ExpenseUpdated event")
+
+	return rpProcessor.checkBudgets(logging.IntoContext(ctx, log), req.GetId())
+}
@@ -22,16 +22,28 @@ type categoryProcessor struct {
 var errDeleteCategories = eris.New("failed deleting categories")
 var errMarshalCategoryDeleted = eris.New("could not marshal category deleted message")
 var errPublishCategoryDeleted = eris.New("could not publish category deleted event")
+var errUndeleteCategories = eris.New("failed restoring categories")
+var errMarshalCategoryUndeleted = eris.New("could not marshal category undeleted message")
+var errPublishCategoryUndeleted = eris.New("could not publish category undeleted event")
 
 // NewCategoryServer creates a new instance of category server.
-func NewCategoryProcessor(natsUrl, dbUser, dbPass, dbAddr, db string) (*categoryProcessor, error) {
+func NewCategoryProcessor(natsUrl string, dbConfig client.Config) (*categoryProcessor, error) {
+	db, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed creating database client")
+	}
+	return NewCategoryProcessorWithDBClient(natsUrl, db)
+}
+
+// NewCategoryProcessorWithDBClient creates a new instance of category processor using the passed database client.
+func NewCategoryProcessorWithDBClient(natsUrl string, db bun.IDB) (*categoryProcessor, error) {
 	nc, err := nats.Connect(natsUrl)
 	if err != nil {
 		return nil, eris.Wrap(err, "failed connecting to NATS server")
 	}
 	return &categoryProcessor{
 		natsClient: nc,
-		dbClient:   client.NewPostgresDBClient(dbUser, dbPass, dbAddr, db),
+		dbClient:   db,
 	}, nil
 }
 
@@ -89,9 +101,18 @@ func (rpProcessor *categoryProcessor) Process(ctx context.Context) error {
 			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
 		}
 	}
+	var guCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetGroupUndeletedSubject("*")
+		var err error
+		guCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, groupSourceStreamName, "EXPENSESPLITTER_CATEGORY_PROCESSOR_GROUP_UNDELETED", eventSubject, rpProcessor.groupUndeleted)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
 
 	<-ctx.Done()
 	log.Info("the context is done")
-	processor.UnsubscribeConsumeContexts(ccCCtx, cdCCtx, cuCCtx, gdCCtx)
+	processor.UnsubscribeConsumeContexts(ccCCtx, cdCCtx, cuCCtx, gdCCtx, guCCtx)
 	return nil
 }
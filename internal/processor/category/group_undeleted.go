@@ -0,0 +1,57 @@
+package category
+
+import (
+	"context"
+
+	categoryprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/category/v1"
+	groupprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/group/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/uptrace/bun"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
+)
+
+func (rpProcessor *categoryProcessor) groupUndeleted(ctx context.Context, req *groupprocv1.GroupUndeleted) error {
+	log := logging.FromContext(ctx).With(logging.String("groupId", req.GetId()))
+	log.Info("processing group.GroupUndeleted event")
+
+	var categories []*model.Category
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := util.UpdateMatchedReturning(ctx, tx, model.Undelete(tx.NewUpdate().Model(&categories)), func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereDeletedBecauseOf(q.Where("group_id = ?", req.GetId()), req.GetDeleteCause())
+		}, "id"); err != nil {
+			log.Error("failed restoring categories related to restored group", logging.Error(err))
+			return errUndeleteCategories
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	g, _ := errgroup.WithContext(ctx)
+	for _, c := range categories {
+		category := c
+		g.Go(func() error {
+			marshalled, err := proto.Marshal(&categoryprocv1.CategoryUndeleted{
+				Id:          category.Id,
+				GroupId:     req.GetId(),
+				DeleteCause: req.GetDeleteCause(),
+			})
+			if err != nil {
+				log.Error("failed marshalling category undeleted event", logging.Error(err))
+				return errMarshalCategoryUndeleted
+			}
+			if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetCategoryUndeletedSubject(req.GetId(), category.Id), marshalled); err != nil {
+				log.Error("failed publishing category undeleted event", logging.Error(err))
+				return errPublishCategoryUndeleted
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
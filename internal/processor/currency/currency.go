@@ -1,10 +1,7 @@
 package currency
 
-// TODO: periodically update currency acronyms in database
-
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"strings"
 	"time"
@@ -13,12 +10,17 @@ import (
 	"github.com/nats-io/nats.go/jetstream"
 	currencyv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/currency/v1"
 	currencyprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/currency/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	curClient "github.com/nico151999/high-availability-expense-splitter/pkg/currency/client"
 	dbClient "github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/election"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/processor"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
 	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"google.golang.org/protobuf/proto"
@@ -31,21 +33,43 @@ type currencyProcessor struct {
 }
 
 const tickerPeriod = time.Hour
+const leaseDuration = 15 * time.Second
+const leaderElectionKey = "currency-sync"
+
+// minUpstreamShare is the inverse of the minimum share of the stored currencies an upstream response needs to provide to be reconciled
+const minUpstreamShare = 2
 
-var errSelectCurrencyByAcronym = eris.New("failed selecting currency by acronym")
+var errSelectCurrencies = eris.New("failed selecting currencies")
+var errIncompleteUpstreamCurrencies = eris.New("the currencies provided upstream are incomplete")
+var errSelectCurrencyReferences = eris.New("failed selecting resources referencing currency")
 var errInsertNewCurrency = eris.New("failed inserting currency into database")
+var errUpdateCurrency = eris.New("failed updating currency in database")
+var errDeleteCurrency = eris.New("failed deleting currency from database")
 var errMarshalCurrencyCreated = eris.New("could not marshal currency created message")
 var errPublishCurrencyCreated = eris.New("could not publish currency created event")
+var errMarshalCurrencyUpdated = eris.New("could not marshal currency updated message")
+var errPublishCurrencyUpdated = eris.New("could not publish currency updated event")
+var errMarshalCurrencyDeleted = eris.New("could not marshal currency deleted message")
+var errPublishCurrencyDeleted = eris.New("could not publish currency deleted event")
 
 // NewCurrencyServer creates a new instance of currency server.
-func NewCurrencyProcessor(natsUrl, dbUser, dbPass, dbAddr, db string) (*currencyProcessor, error) {
+func NewCurrencyProcessor(natsUrl string, dbConfig dbClient.Config) (*currencyProcessor, error) {
+	db, err := dbClient.NewDBClient(dbConfig)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed creating database client")
+	}
+	return NewCurrencyProcessorWithDBClient(natsUrl, db)
+}
+
+// NewCurrencyProcessorWithDBClient creates a new instance of currency processor using the passed database client.
+func NewCurrencyProcessorWithDBClient(natsUrl string, db bun.IDB) (*currencyProcessor, error) {
 	nc, err := nats.Connect(natsUrl)
 	if err != nil {
 		return nil, eris.Wrap(err, "failed connecting to NATS server")
 	}
 	return &currencyProcessor{
 		natsClient:     nc,
-		dbClient:       dbClient.NewPostgresDBClient(dbUser, dbPass, dbAddr, db),
+		dbClient:       db,
 		currencyClient: curClient.NewCurrencyClient(),
 	}, nil
 }
@@ -95,6 +119,27 @@ func (rpProcessor *currencyProcessor) Process(ctx context.Context) error {
 		}
 	}
 
+	leaderElection, err := election.NewLeaderElection(
+		ctx,
+		rpProcessor.natsClient,
+		environment.GetLeaderElectionBucketName(),
+		leaderElectionKey,
+		leaseDuration)
+	if err != nil {
+		processor.UnsubscribeConsumeContexts(ccCCtx, cdCCtx, cuCCtx)
+		return eris.Wrap(err, "failed creating leader election for periodic currency updates")
+	}
+	// only the leading replica fetches currencies periodically so that replicas do not race each other
+	leaderElection.Run(ctx, rpProcessor.updateCurrenciesPeriodically)
+
+	processor.UnsubscribeConsumeContexts(ccCCtx, cdCCtx, cuCCtx)
+	return nil
+}
+
+// updateCurrenciesPeriodically updates the currencies initially and then once per ticker period until the context is done
+func (rpProcessor *currencyProcessor) updateCurrenciesPeriodically(ctx context.Context) {
+	log := logging.FromContext(ctx)
+
 	if err := rpProcessor.updateCurrencies(ctx); err != nil {
 		log.Error("could not update currencies initially", logging.Error(err))
 	} else {
@@ -104,7 +149,6 @@ func (rpProcessor *currencyProcessor) Process(ctx context.Context) error {
 	ticker := time.NewTicker(tickerPeriod)
 	defer ticker.Stop()
 
-loop:
 	for {
 		select {
 		case <-ticker.C:
@@ -114,69 +158,218 @@ loop:
 				log.Info("successfully updated currencies")
 			}
 		case <-ctx.Done():
-			log.Info("the context is done")
-			processor.UnsubscribeConsumeContexts(ccCCtx, cdCCtx, cuCCtx)
-			break loop
+			log.Info("stopped updating currencies")
+			return
 		}
 	}
-
-	return nil
 }
 
+// updateCurrencies reconciles the currencies in the database with the ones provided upstream.
+// New currencies are inserted, changed names are applied and currencies that were dropped upstream
+// are deleted unless they are still referenced by a group, an expense or a recurring expense, in which case they are deprecated.
 func (rpProcessor *currencyProcessor) updateCurrencies(ctx context.Context) error {
+	ctx = principal.IntoContext(ctx, principal.System)
 	log := logging.FromContext(ctx)
 
-	currencies, err := rpProcessor.currencyClient.FetchCurrencies(ctx)
+	upstreamCurrencies, err := rpProcessor.currencyClient.FetchCurrencies(ctx)
 	if err != nil {
 		return err
 	}
+	currencies := make(map[string]string, len(upstreamCurrencies))
+	for acronym, name := range upstreamCurrencies {
+		currencies[strings.ToUpper(acronym)] = name
+	}
+	if len(currencies) == 0 {
+		log.Error("upstream provided no currencies")
+		return errIncompleteUpstreamCurrencies
+	}
+
+	var existingCurrencies []*currencyv1.Currency
+	if err := rpProcessor.dbClient.NewSelect().Model(&existingCurrencies).Scan(ctx); err != nil {
+		log.Error("failed getting currencies", logging.Error(err))
+		return errSelectCurrencies
+	}
+	existingCurrenciesByAcronym := make(map[string]*currencyv1.Currency, len(existingCurrencies))
+	for _, currency := range existingCurrencies {
+		existingCurrenciesByAcronym[currency.GetAcronym()] = currency
+	}
+	// a response much smaller than the stored currencies is most likely truncated rather than a mass removal upstream, which is why
+	// nothing is reconciled so that the currencies are not deprecated or deleted by mistake
+	if len(currencies)*minUpstreamShare < len(existingCurrenciesByAcronym) {
+		log.Error("upstream provided much fewer currencies than stored",
+			logging.Int("upstreamCurrencies", len(currencies)),
+			logging.Int("storedCurrencies", len(existingCurrenciesByAcronym)))
+		return errIncompleteUpstreamCurrencies
+	}
 
 	for acronym, name := range currencies {
-		acronym = strings.ToUpper(acronym)
 		log := log.With(logging.String("currency", acronym))
+		ctx := logging.IntoContext(ctx, log)
 
-		err := rpProcessor.dbClient.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-			if err := tx.NewSelect().Model(&currencyv1.Currency{}).Where("acronym = ?", acronym).Limit(1).Scan(ctx); err == nil {
-				log.Debug("currency already exists in database")
-			} else {
-				if eris.Is(err, sql.ErrNoRows) {
-					log.Info("inserting new currency into database")
-					currency := currencyv1.Currency{
-						Id:      util.GenerateIdWithPrefix("currency"),
-						Acronym: acronym,
-						Name:    name,
-					}
-					if _, err := tx.NewInsert().Model(&currency).Exec(ctx); err != nil {
-						log.Error("failed inserting currency into database", logging.Error(err))
-						return errInsertNewCurrency
-					}
-
-					marshalled, err := proto.Marshal(&currencyprocv1.CurrencyCreated{
-						Id:      currency.GetId(),
-						Acronym: currency.GetAcronym(),
-						Name:    currency.GetName(),
-					})
-					if err != nil {
-						log.Error("failed marshalling currency created event", logging.Error(err))
-						return errMarshalCurrencyCreated
-					}
-					if err := rpProcessor.natsClient.Publish(environment.GetCurrencyCreatedSubject(currency.GetId()), marshalled); err != nil {
-						log.Error("failed publishing currency created event", logging.Error(err))
-						return errPublishCurrencyCreated
-					}
-					return nil
-				} else {
-					log.Error("failed getting currency by acronym", logging.Error(err))
-					return errSelectCurrencyByAcronym
-				}
-			}
-			return nil
-		})
+		var err error
+		if currency, ok := existingCurrenciesByAcronym[acronym]; !ok {
+			err = rpProcessor.insertCurrency(ctx, acronym, name)
+		} else if currency.GetName() != name || currency.GetDeprecated() {
+			err = rpProcessor.updateCurrency(ctx, currency.GetId(), name, false)
+		} else {
+			log.Debug("currency is up to date")
+		}
 		if err != nil {
 			msg := "failed updating currency"
 			log.Error(msg, logging.Error(err))
 			return eris.Wrap(err, msg)
 		}
 	}
+
+	for acronym, currency := range existingCurrenciesByAcronym {
+		if _, ok := currencies[acronym]; ok {
+			continue
+		}
+		log := log.With(logging.String("currency", acronym))
+		ctx := logging.IntoContext(ctx, log)
+
+		if err := rpProcessor.retireCurrency(ctx, currency); err != nil {
+			msg := "failed retiring currency"
+			log.Error(msg, logging.Error(err))
+			return eris.Wrap(err, msg)
+		}
+	}
+	return nil
+}
+
+func (rpProcessor *currencyProcessor) insertCurrency(ctx context.Context, acronym, name string) error {
+	log := logging.FromContext(ctx)
+
+	log.Info("inserting new currency into database")
+	currency := model.NewCurrency(&currencyv1.Currency{
+		Id:      util.GenerateIdWithPrefix("currency"),
+		Acronym: acronym,
+		Name:    name,
+	}, model.NewCreatedMetadata(ctx, time.Now()))
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(currency).Exec(ctx); err != nil {
+			log.Error("failed inserting currency into database", logging.Error(err))
+			return errInsertNewCurrency
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	marshalled, err := proto.Marshal(&currencyprocv1.CurrencyCreated{
+		Id:      currency.GetId(),
+		Acronym: currency.GetAcronym(),
+		Name:    currency.GetName(),
+	})
+	if err != nil {
+		log.Error("failed marshalling currency created event", logging.Error(err))
+		return errMarshalCurrencyCreated
+	}
+	if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetCurrencyCreatedSubject(currency.GetId()), marshalled); err != nil {
+		log.Error("failed publishing currency created event", logging.Error(err))
+		return errPublishCurrencyCreated
+	}
+	return nil
+}
+
+func (rpProcessor *currencyProcessor) updateCurrency(ctx context.Context, currencyId, name string, deprecated bool) error {
+	log := logging.FromContext(ctx).With(
+		logging.String("currencyId", currencyId),
+		logging.Bool("deprecated", deprecated))
+
+	log.Info("updating currency in database")
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewUpdate().Model(model.NewCurrency(&currencyv1.Currency{
+			Id:         currencyId,
+			Name:       name,
+			Deprecated: deprecated,
+		}, model.NewModifiedMetadata(ctx, time.Now()))).Column("name", "deprecated").Column(model.MetadataUpdateColumns...).WherePK().Exec(ctx); err != nil {
+			log.Error("failed updating currency in database", logging.Error(err))
+			return errUpdateCurrency
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	marshalled, err := proto.Marshal(&currencyprocv1.CurrencyUpdated{
+		Id:         currencyId,
+		Name:       name,
+		Deprecated: deprecated,
+	})
+	if err != nil {
+		log.Error("failed marshalling currency updated event", logging.Error(err))
+		return errMarshalCurrencyUpdated
+	}
+	if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetCurrencyUpdatedSubject(currencyId), marshalled); err != nil {
+		log.Error("failed publishing currency updated event", logging.Error(err))
+		return errPublishCurrencyUpdated
+	}
+	return nil
+}
+
+// retireCurrency deletes a currency that is no longer provided upstream or deprecates it if it is still in use
+func (rpProcessor *currencyProcessor) retireCurrency(ctx context.Context, currency *currencyv1.Currency) error {
+	log := logging.FromContext(ctx).With(logging.String("currencyId", currency.GetId()))
+
+	// the reference check and the deletion share a transaction so that no group or expense can start referencing the currency in between
+	referenced := false
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		if referenced, err = isCurrencyReferenced(ctx, tx, currency.GetId()); err != nil || referenced {
+			return err
+		}
+		log.Info("deleting currency from database")
+		if _, err := tx.NewDelete().Model(&currencyv1.Currency{
+			Id: currency.GetId(),
+		}).WherePK().Exec(ctx); err != nil {
+			log.Error("failed deleting currency from database", logging.Error(err))
+			return errDeleteCurrency
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if referenced {
+		if currency.GetDeprecated() {
+			log.Debug("currency is already deprecated")
+			return nil
+		}
+		return rpProcessor.updateCurrency(ctx, currency.GetId(), currency.GetName(), true)
+	}
+
+	marshalled, err := proto.Marshal(&currencyprocv1.CurrencyDeleted{
+		Id: currency.GetId(),
+	})
+	if err != nil {
+		log.Error("failed marshalling currency deleted event", logging.Error(err))
+		return errMarshalCurrencyDeleted
+	}
+	if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetCurrencyDeletedSubject(currency.GetId()), marshalled); err != nil {
+		log.Error("failed publishing currency deleted event", logging.Error(err))
+		return errPublishCurrencyDeleted
+	}
 	return nil
 }
+
+// isCurrencyReferenced tells whether a group or an expense still uses the currency including the ones in the trash since they may be restored
+func isCurrencyReferenced(ctx context.Context, db bun.IDB, currencyId string) (bool, error) {
+	log := logging.FromContext(ctx)
+
+	for _, query := range []*bun.SelectQuery{
+		db.NewSelect().Model((*model.Group)(nil)).WhereAllWithDeleted(),
+		db.NewSelect().Model((*model.Expense)(nil)).WhereAllWithDeleted(),
+		// recurring expenses are not soft deleted
+		db.NewSelect().Model((*model.RecurringExpense)(nil)),
+	} {
+		exists, err := query.Where("currency_id = ?", currencyId).Exists(ctx)
+		if err != nil {
+			log.Error("failed checking whether currency is referenced", logging.Error(err))
+			return false, errSelectCurrencyReferences
+		}
+		if exists {
+			return true, nil
+		}
+	}
+	return false, nil
+}
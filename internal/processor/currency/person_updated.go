@@ -10,7 +10,9 @@ import (
 func (rpProcessor *currencyProcessor) currencyUpdated(ctx context.Context, req *currencyv1.CurrencyUpdated) error {
 	log := logging.FromContext(ctx)
 	log.Info("processing currency.CurrencyUpdated event",
-		logging.String("currencyId", req.GetId()))
+		logging.String("name", req.GetName()),
+		logging.String("currencyId", req.GetId()),
+		logging.Bool("deprecated", req.GetDeprecated()))
 	// TODO: actually process message like sending a project updated notification and publish an event telling what was done (e.g. project updated notification sent)
 	return nil
 }
@@ -0,0 +1,93 @@
+package debtreminder
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	curClient "github.com/nico151999/high-availability-expense-splitter/pkg/currency/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/election"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/processor"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+type debtReminderProcessor struct {
+	natsClient     *nats.Conn
+	dbClient       bun.IDB
+	currencyClient curClient.Client
+}
+
+// tickerPeriod is how often debts are checked; it is short compared to the days the policies are configured in
+const tickerPeriod = time.Hour
+const leaseDuration = 15 * time.Second
+const leaderElectionKey = "debt-reminder-scheduler"
+
+var errSelectPolicies = eris.New("failed selecting debt reminder policies")
+var errSelectGroup = eris.New("failed selecting group of debt reminder policy")
+var errSelectExpenses = eris.New("failed selecting expenses of group")
+var errSelectCurrencies = eris.New("failed selecting currencies of expenses")
+var errGetExchangeRate = eris.New("failed getting exchange rate into currency of group")
+var errSelectPersons = eris.New("failed selecting persons of group")
+var errSelectSnoozes = eris.New("failed selecting debt reminder snoozes")
+var errSelectReminders = eris.New("failed selecting debt reminders")
+var errMarshalDebtReminderDue = eris.New("could not marshal debt reminder due message")
+var errPublishDebtReminderDue = eris.New("could not publish debt reminder due event")
+var errRecordReminder = eris.New("failed recording debt reminder")
+var errDeleteReminders = eris.New("failed deleting debt reminders of settled debts")
+
+// NewDebtReminderProcessor creates a new instance of debt reminder processor.
+func NewDebtReminderProcessor(natsUrl string, dbConfig client.Config) (*debtReminderProcessor, error) {
+	db, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed creating database client")
+	}
+	return NewDebtReminderProcessorWithDBClient(natsUrl, db)
+}
+
+// NewDebtReminderProcessorWithDBClient creates a new instance of debt reminder processor using the passed database client.
+func NewDebtReminderProcessorWithDBClient(natsUrl string, db bun.IDB) (*debtReminderProcessor, error) {
+	nc, err := nats.Connect(natsUrl)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed connecting to NATS server")
+	}
+	return &debtReminderProcessor{
+		natsClient: nc,
+		dbClient:   db,
+		// the debts are converted at the exchange rates of the days of the expenses which do not change anymore
+		currencyClient: curClient.NewCachingCurrencyClient(curClient.NewCurrencyClient()),
+	}, nil
+}
+
+// Process starts checking the debts of the groups with a debt reminder policy once this replica leads and returns when the context is done
+func (rpProcessor *debtReminderProcessor) Process(ctx context.Context) error {
+	log := logging.FromContext(ctx).Named("Process")
+	ctx = logging.IntoContext(ctx, log)
+
+	_, err := processor.CreateOrUpdateSourceStream(
+		ctx,
+		rpProcessor.natsClient,
+		environment.GetDebtReminderSourceStreamName(),
+		fmt.Sprintf("%s.*", environment.GetDebtReminderSubject("*", "*")),
+	)
+	if err != nil {
+		return err
+	}
+
+	leaderElection, err := election.NewLeaderElection(
+		ctx,
+		rpProcessor.natsClient,
+		environment.GetLeaderElectionBucketName(),
+		leaderElectionKey,
+		leaseDuration)
+	if err != nil {
+		return eris.Wrap(err, "failed creating leader election for scheduling debt reminders")
+	}
+	// only the leading replica checks the debts so that persons are not reminded by every replica
+	leaderElection.Run(ctx, rpProcessor.remindPeriodically)
+	return nil
+}
@@ -0,0 +1,271 @@
+package debtreminder
+
+import (
+	"context"
+	"time"
+
+	debtreminderprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/debtreminder/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/balance"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const day = 24 * time.Hour
+
+// remindPeriodically checks the debts initially and then once per ticker period until the context is done
+func (rpProcessor *debtReminderProcessor) remindPeriodically(ctx context.Context) {
+	log := logging.FromContext(ctx)
+
+	if err := rpProcessor.remind(ctx); err != nil {
+		log.Error("could not check debts initially", logging.Error(err))
+	} else {
+		log.Info("successfully checked debts initially")
+	}
+
+	ticker := time.NewTicker(tickerPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := rpProcessor.remind(ctx); err != nil {
+				log.Error("could not check debts", logging.Error(err))
+			} else {
+				log.Debug("successfully checked debts")
+			}
+		case <-ctx.Done():
+			log.Info("stopped checking debts")
+			return
+		}
+	}
+}
+
+// remind checks the debts of every group with a debt reminder policy
+func (rpProcessor *debtReminderProcessor) remind(ctx context.Context) error {
+	// the reminders are not caused by a request
+	ctx = principal.IntoContext(ctx, principal.System)
+	log := logging.FromContext(ctx)
+
+	var policies []*model.DebtReminderPolicy
+	if err := rpProcessor.dbClient.NewSelect().Model(&policies).Order("group_id ASC").Scan(ctx); err != nil {
+		log.Error("failed selecting debt reminder policies", logging.Error(err))
+		return errSelectPolicies
+	}
+	now := time.Now()
+	for _, policy := range policies {
+		log := log.With(logging.String("groupId", policy.GetGroupId()))
+		ctx := logging.IntoContext(ctx, log)
+
+		// a failing group must not keep the persons of the others from being reminded
+		if err := rpProcessor.remindGroup(ctx, policy, now); err != nil {
+			log.Error("failed checking debts of group", logging.Error(err))
+		}
+	}
+	return nil
+}
+
+// remindGroup publishes a DebtReminderDue event for every person of the group whose debt exceeds the threshold of the policy, has been
+// outstanding for the minimum age and whom the policy lets be reminded again, unless the person is snoozed. The event is published before
+// the reminder is recorded so that a person may be reminded twice if recording fails but is never left out.
+func (rpProcessor *debtReminderProcessor) remindGroup(ctx context.Context, policy *model.DebtReminderPolicy, now time.Time) error {
+	log := logging.FromContext(ctx)
+
+	group, err := util.CheckResourceExists[*model.Group](ctx, rpProcessor.dbClient, policy.GetGroupId())
+	if err != nil {
+		if eris.As(err, &util.ResourceNotFoundError{}) {
+			log.Debug("not reminding the persons of a deleted group")
+			return nil
+		}
+		log.Error("failed selecting group", logging.Error(err))
+		return errSelectGroup
+	}
+	debts, err := rpProcessor.selectDebts(ctx, group)
+	if err != nil {
+		return err
+	}
+
+	var personIds []string
+	if err := rpProcessor.dbClient.NewSelect().Model((*model.Person)(nil)).
+		Column("id").
+		Where("group_id = ?", group.GetId()).
+		Scan(ctx, &personIds); err != nil {
+		log.Error("failed selecting persons of group", logging.Error(err))
+		return errSelectPersons
+	}
+	var snoozedPersonIds []string
+	if err := rpProcessor.dbClient.NewSelect().Model((*model.DebtReminderSnooze)(nil)).
+		Column("person_id").
+		Where("group_id = ?", group.GetId()).
+		Where("snooze_until > ?", now).
+		Scan(ctx, &snoozedPersonIds); err != nil {
+		log.Error("failed selecting debt reminder snoozes", logging.Error(err))
+		return errSelectSnoozes
+	}
+	var reminders []*model.DebtReminder
+	if err := rpProcessor.dbClient.NewSelect().Model(&reminders).
+		Where("group_id = ?", group.GetId()).
+		Scan(ctx); err != nil {
+		log.Error("failed selecting debt reminders", logging.Error(err))
+		return errSelectReminders
+	}
+	persons := toSet(personIds)
+	snoozed := toSet(snoozedPersonIds)
+	lastReminded := make(map[string]time.Time, len(reminders))
+	for _, reminder := range reminders {
+		lastReminded[reminder.PersonId] = reminder.RemindTime
+	}
+
+	minAge := time.Duration(policy.GetMinAgeDays()) * day
+	repeatInterval := time.Duration(policy.GetRepeatIntervalDays()) * day
+	debtorIds := make([]string, 0, len(debts))
+	for _, debt := range debts {
+		// persons deleted from the group cannot be reminded anymore
+		if !persons[debt.PersonId] {
+			continue
+		}
+		debtorIds = append(debtorIds, debt.PersonId)
+		if debt.Amount <= policy.Threshold() || now.Sub(debt.Since) < minAge || snoozed[debt.PersonId] {
+			continue
+		}
+		if last, ok := lastReminded[debt.PersonId]; ok && now.Sub(last) < repeatInterval {
+			continue
+		}
+		if err := rpProcessor.publishDebtReminderDue(ctx, group, debt); err != nil {
+			return err
+		}
+		if _, err := rpProcessor.dbClient.NewInsert().Model(&model.DebtReminder{
+			GroupId:    group.GetId(),
+			PersonId:   debt.PersonId,
+			RemindTime: now,
+		}).
+			On("CONFLICT (group_id, person_id) DO UPDATE").
+			Set("remind_time = EXCLUDED.remind_time").
+			Exec(ctx); err != nil {
+			log.Error("failed recording debt reminder", logging.String("personId", debt.PersonId), logging.Error(err))
+			return errRecordReminder
+		}
+	}
+
+	// a person who settled their debt is reminded of a new debt as soon as it is old enough rather than after the repeat interval
+	query := rpProcessor.dbClient.NewDelete().Model((*model.DebtReminder)(nil)).Where("group_id = ?", group.GetId())
+	if len(debtorIds) > 0 {
+		query = query.Where("person_id NOT IN (?)", bun.In(debtorIds))
+	}
+	if _, err := query.Exec(ctx); err != nil {
+		log.Error("failed deleting debt reminders of settled debts", logging.Error(err))
+		return errDeleteReminders
+	}
+	return nil
+}
+
+// selectDebts returns the debts of the persons of the group converted into the currency of the group at the exchange rates of the days of the expenses
+func (rpProcessor *debtReminderProcessor) selectDebts(ctx context.Context, group *model.Group) ([]balance.Debt, error) {
+	log := logging.FromContext(ctx)
+
+	var expenses []*model.Expense
+	if err := rpProcessor.dbClient.NewSelect().Model(&expenses).
+		Column("id", "by_id", "timestamp", "currency_id").
+		Where("group_id = ?", group.GetId()).
+		Scan(ctx); err != nil {
+		log.Error("failed selecting expenses of group", logging.Error(err))
+		return nil, errSelectExpenses
+	}
+	if len(expenses) == 0 {
+		return nil, nil
+	}
+	var stakes []*model.ExpenseStake
+	if err := rpProcessor.dbClient.NewSelect().Model(&stakes).
+		Column("expense_id", "for_id", "main_value", "fractional_value").
+		Where("expense_id IN (?)", rpProcessor.dbClient.NewSelect().Model((*model.Expense)(nil)).
+			Column("id").
+			Where("group_id = ?", group.GetId())).
+		Scan(ctx); err != nil {
+		log.Error("failed selecting expense stakes of group", logging.Error(err))
+		return nil, errSelectExpenses
+	}
+	var currencies []*model.Currency
+	if err := rpProcessor.dbClient.NewSelect().Model(&currencies).
+		Column("id", "acronym").
+		Where("id IN (?)", rpProcessor.dbClient.NewSelect().Model((*model.Expense)(nil)).
+			Column("currency_id").
+			Where("group_id = ?", group.GetId())).
+		WhereOr("id = ?", group.GetCurrencyId()).
+		Scan(ctx); err != nil {
+		log.Error("failed selecting currencies of expenses", logging.Error(err))
+		return nil, errSelectCurrencies
+	}
+	acronyms := make(map[string]string, len(currencies))
+	for _, currency := range currencies {
+		acronyms[currency.GetId()] = currency.GetAcronym()
+	}
+
+	stakesByExpense := make(map[string][]balance.Stake, len(expenses))
+	for _, stake := range stakes {
+		stakesByExpense[stake.GetExpenseId()] = append(stakesByExpense[stake.GetExpenseId()], balance.Stake{
+			ForId:  stake.GetForId(),
+			Amount: balance.Amount(stake.GetMainValue(), stake.GetFractionalValue()),
+		})
+	}
+	balanceExpenses := make([]balance.Expense, 0, len(expenses))
+	for _, expense := range expenses {
+		timestamp := expense.Timestamp.AsTime()
+		rate := 1.0
+		if expense.GetCurrencyId() != group.GetCurrencyId() {
+			var err error
+			rate, err = rpProcessor.currencyClient.GetExchangeRate(ctx, acronyms[expense.GetCurrencyId()], acronyms[group.GetCurrencyId()], timestamp)
+			if err != nil {
+				log.Error("failed getting exchange rate", logging.String("expenseId", expense.GetId()), logging.Error(err))
+				return nil, errGetExchangeRate
+			}
+		}
+		expenseStakes := stakesByExpense[expense.GetId()]
+		for i := range expenseStakes {
+			expenseStakes[i].Amount = balance.Convert(expenseStakes[i].Amount, rate)
+		}
+		balanceExpenses = append(balanceExpenses, balance.Expense{
+			Time:   timestamp,
+			ById:   expense.GetById(),
+			Stakes: expenseStakes,
+		})
+	}
+	return balance.Debts(balanceExpenses), nil
+}
+
+func (rpProcessor *debtReminderProcessor) publishDebtReminderDue(ctx context.Context, group *model.Group, debt balance.Debt) error {
+	log := logging.FromContext(ctx).With(logging.String("personId", debt.PersonId))
+
+	mainValue, fractionalValue := balance.Split(debt.Amount)
+	marshalled, err := proto.Marshal(&debtreminderprocv1.DebtReminderDue{
+		GroupId:         group.GetId(),
+		PersonId:        debt.PersonId,
+		CurrencyId:      group.GetCurrencyId(),
+		MainValue:       mainValue,
+		FractionalValue: fractionalValue,
+		Since:           timestamppb.New(debt.Since),
+	})
+	if err != nil {
+		log.Error("failed marshalling debt reminder due event", logging.Error(err))
+		return errMarshalDebtReminderDue
+	}
+	if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetDebtReminderDueSubject(group.GetId(), debt.PersonId), marshalled); err != nil {
+		log.Error("failed publishing debt reminder due event", logging.Error(err))
+		return errPublishDebtReminderDue
+	}
+	return nil
+}
+
+func toSet(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
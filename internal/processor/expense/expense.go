@@ -22,16 +22,34 @@ type expenseProcessor struct {
 var errDeleteExpenses = eris.New("failed deleting expenses")
 var errMarshalExpenseDeleted = eris.New("could not marshal expense deleted message")
 var errPublishExpenseDeleted = eris.New("could not publish expense deleted event")
+var errUndeleteExpenses = eris.New("failed restoring expenses")
+var errMarshalExpenseUndeleted = eris.New("could not marshal expense undeleted message")
+var errPublishExpenseUndeleted = eris.New("could not publish expense undeleted event")
+var errDeleteComments = eris.New("failed deleting comments")
+var errMarshalCommentDeleted = eris.New("could not marshal comment deleted message")
+var errPublishCommentDeleted = eris.New("could not publish comment deleted event")
+var errUndeleteComments = eris.New("failed restoring comments")
+var errMarshalCommentUndeleted = eris.New("could not marshal comment undeleted message")
+var errPublishCommentUndeleted = eris.New("could not publish comment undeleted event")
 
 // NewExpenseServer creates a new instance of expense server.
-func NewExpenseProcessor(natsUrl, dbUser, dbPass, dbAddr, db string) (*expenseProcessor, error) {
+func NewExpenseProcessor(natsUrl string, dbConfig client.Config) (*expenseProcessor, error) {
+	db, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed creating database client")
+	}
+	return NewExpenseProcessorWithDBClient(natsUrl, db)
+}
+
+// NewExpenseProcessorWithDBClient creates a new instance of expense processor using the passed database client.
+func NewExpenseProcessorWithDBClient(natsUrl string, db bun.IDB) (*expenseProcessor, error) {
 	nc, err := nats.Connect(natsUrl)
 	if err != nil {
 		return nil, eris.Wrap(err, "failed connecting to NATS server")
 	}
 	return &expenseProcessor{
 		natsClient: nc,
-		dbClient:   client.NewPostgresDBClient(dbUser, dbPass, dbAddr, db),
+		dbClient:   db,
 	}, nil
 }
 
@@ -53,6 +71,15 @@ func (rpProcessor *expenseProcessor) Process(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	// the comments of an expense are cascaded by this processor which is why it provides their stream
+	if _, err := processor.CreateOrUpdateSourceStream(
+		ctx,
+		rpProcessor.natsClient,
+		environment.GetCommentSourceStreamName(),
+		fmt.Sprintf("%s.*", environment.GetCommentSubject("*", "*", "*")),
+	); err != nil {
+		return err
+	}
 
 	var ecCCtx jetstream.ConsumeContext
 	{
@@ -72,6 +99,15 @@ func (rpProcessor *expenseProcessor) Process(ctx context.Context) error {
 			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
 		}
 	}
+	var eudCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetExpenseUndeletedSubject("*", "*")
+		var err error
+		eudCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, sourceStreamName, "EXPENSESPLITTER_EXPENSE_PROCESSOR_EXPENSE_UNDELETED", eventSubject, rpProcessor.expenseUndeleted)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
 	var euCCtx jetstream.ConsumeContext
 	{
 		eventSubject := environment.GetExpenseUpdatedSubject("*", "*")
@@ -99,9 +135,27 @@ func (rpProcessor *expenseProcessor) Process(ctx context.Context) error {
 			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
 		}
 	}
+	var guCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetGroupUndeletedSubject("*")
+		var err error
+		guCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, groupSourceStreamName, "EXPENSESPLITTER_EXPENSE_PROCESSOR_GROUP_UNDELETED", eventSubject, rpProcessor.groupUndeleted)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var puCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetPersonUndeletedSubject("*", "*")
+		var err error
+		puCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, personSourceStreamName, "EXPENSESPLITTER_EXPENSE_PROCESSOR_PERSON_UNDELETED", eventSubject, rpProcessor.personUndeleted)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
 
 	<-ctx.Done()
 	log.Info("the context is done")
-	processor.UnsubscribeConsumeContexts(ecCCtx, edCCtx, euCCtx, gdCCtx, pdCCtx)
+	processor.UnsubscribeConsumeContexts(ecCCtx, edCCtx, eudCCtx, euCCtx, gdCCtx, pdCCtx, guCCtx, puCCtx)
 	return nil
 }
@@ -2,15 +2,59 @@ package expense
 
 import (
 	"context"
+	"time"
 
+	commentprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/comment/v1"
 	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/uptrace/bun"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
 )
 
+// expenseDeleted moves the comments of the deleted expense to the trash along with it
 func (rpProcessor *expenseProcessor) expenseDeleted(ctx context.Context, req *expensev1.ExpenseDeleted) error {
-	log := logging.FromContext(ctx)
-	log.Info("processing expense.ExpenseDeleted event",
-		logging.String("expenseId", req.GetId()))
-	// TODO: actually process message like sending a project deleted notification and publish an event telling what was done (e.g. project deleted notification sent)
-	return nil
+	log := logging.FromContext(ctx).With(logging.String("expenseId", req.GetId()))
+	log.Info("processing expense.ExpenseDeleted event")
+
+	var comments []*model.Comment
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := util.UpdateMatchedReturning(ctx, tx, model.SoftDelete(tx.NewUpdate().Model(&comments), req.GetDeleteCause(), time.Now()), func(q bun.QueryBuilder) bun.QueryBuilder {
+			return q.Where("expense_id = ?", req.GetId())
+		}, "id"); err != nil {
+			log.Error("failed deleting comments related to deleted expense", logging.Error(err))
+			return errDeleteComments
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	g, _ := errgroup.WithContext(ctx)
+	for _, c := range comments {
+		comment := c
+		g.Go(func() error {
+			marshalled, err := proto.Marshal(&commentprocv1.CommentDeleted{
+				Id:          comment.GetId(),
+				ExpenseId:   req.GetId(),
+				GroupId:     req.GetGroupId(),
+				DeleteCause: req.GetDeleteCause(),
+			})
+			if err != nil {
+				log.Error("failed marshalling comment deleted event", logging.Error(err))
+				return errMarshalCommentDeleted
+			}
+			if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetCommentDeletedSubject(req.GetGroupId(), req.GetId(), comment.GetId()), marshalled); err != nil {
+				log.Error("failed publishing comment deleted event", logging.Error(err))
+				return errPublishCommentDeleted
+			}
+			return nil
+		})
+	}
+	return g.Wait()
 }
@@ -0,0 +1,59 @@
+package expense
+
+import (
+	"context"
+
+	commentprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/comment/v1"
+	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/uptrace/bun"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
+)
+
+// expenseUndeleted restores the comments that were moved to the trash along with the restored expense
+func (rpProcessor *expenseProcessor) expenseUndeleted(ctx context.Context, req *expensev1.ExpenseUndeleted) error {
+	log := logging.FromContext(ctx).With(logging.String("expenseId", req.GetId()))
+	log.Info("processing expense.ExpenseUndeleted event")
+
+	var comments []*model.Comment
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := util.UpdateMatchedReturning(ctx, tx, model.Undelete(tx.NewUpdate().Model(&comments)), func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereDeletedBecauseOf(q.Where("expense_id = ?", req.GetId()), req.GetDeleteCause())
+		}, "id"); err != nil {
+			log.Error("failed restoring comments related to restored expense", logging.Error(err))
+			return errUndeleteComments
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	g, _ := errgroup.WithContext(ctx)
+	for _, c := range comments {
+		comment := c
+		g.Go(func() error {
+			marshalled, err := proto.Marshal(&commentprocv1.CommentUndeleted{
+				Id:          comment.GetId(),
+				ExpenseId:   req.GetId(),
+				GroupId:     req.GetGroupId(),
+				DeleteCause: req.GetDeleteCause(),
+			})
+			if err != nil {
+				log.Error("failed marshalling comment undeleted event", logging.Error(err))
+				return errMarshalCommentUndeleted
+			}
+			if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetCommentUndeletedSubject(req.GetGroupId(), req.GetId(), comment.GetId()), marshalled); err != nil {
+				log.Error("failed publishing comment undeleted event", logging.Error(err))
+				return errPublishCommentUndeleted
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
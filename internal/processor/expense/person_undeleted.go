@@ -0,0 +1,57 @@
+package expense
+
+import (
+	"context"
+
+	expenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expense/v1"
+	personprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/person/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/uptrace/bun"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
+)
+
+func (rpProcessor *expenseProcessor) personUndeleted(ctx context.Context, req *personprocv1.PersonUndeleted) error {
+	log := logging.FromContext(ctx).With(logging.String("personId", req.GetId()))
+	log.Info("processing person.PersonUndeleted event")
+
+	var expenseModels []*model.Expense
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := util.UpdateMatchedReturning(ctx, tx, model.Undelete(tx.NewUpdate().Model(&expenseModels)), func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereDeletedBecauseOf(q.Where("by_id = ?", req.GetId()), req.GetDeleteCause())
+		}, "id"); err != nil {
+			log.Error("failed restoring expenses related to restored person", logging.Error(err))
+			return errUndeleteExpenses
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	g, _ := errgroup.WithContext(ctx)
+	for _, e := range expenseModels {
+		expense := e
+		g.Go(func() error {
+			marshalled, err := proto.Marshal(&expenseprocv1.ExpenseUndeleted{
+				Id:          expense.GetId(),
+				GroupId:     req.GetGroupId(),
+				DeleteCause: req.GetDeleteCause(),
+			})
+			if err != nil {
+				log.Error("failed marshalling expense undeleted event", logging.Error(err))
+				return errMarshalExpenseUndeleted
+			}
+			if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetExpenseUndeletedSubject(req.GetGroupId(), expense.GetId()), marshalled); err != nil {
+				log.Error("failed publishing expense undeleted event", logging.Error(err))
+				return errPublishExpenseUndeleted
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
@@ -2,13 +2,16 @@ package expensecategoryrelation
 
 import (
 	"context"
-	"database/sql"
+	"time"
 
-	expensecategoryrelationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensecategoryrelation/v1"
 	categoryprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/category/v1"
 	expensecategoryrelationprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expensecategoryrelation/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/uptrace/bun"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/proto"
@@ -16,34 +19,40 @@ import (
 
 func (rpProcessor *expensecategoryrelationProcessor) categoryDeleted(ctx context.Context, req *categoryprocv1.CategoryDeleted) error {
 	log := logging.FromContext(ctx).With(logging.String("categoryId", req.GetId()))
-	log.Info("processing expense.CategoryDeleted event")
+	log.Info("processing category.CategoryDeleted event")
 
-	return rpProcessor.dbClient.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		var expensecategoryrelations []*expensecategoryrelationv1.ExpenseCategoryRelation
-		if err := tx.NewDelete().Model(&expensecategoryrelations).Where("category_id = ?", req.GetId()).Returning("expense_id").Scan(ctx); err != nil {
+	var expensecategoryrelations []*model.ExpenseCategoryRelation
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := util.UpdateMatchedReturning(ctx, tx, model.SoftDelete(tx.NewUpdate().Model(&expensecategoryrelations), req.GetDeleteCause(), time.Now()), func(q bun.QueryBuilder) bun.QueryBuilder {
+			return q.Where("category_id = ?", req.GetId())
+		}, "expense_id"); err != nil {
 			log.Error("failed deleting expense category relations related to deleted category", logging.Error(err))
 			return errDeleteExpenseCategoryRelations
 		}
+		return nil
+	}); err != nil {
+		return err
+	}
 
-		g, _ := errgroup.WithContext(ctx)
-		for _, c := range expensecategoryrelations {
-			expensecategoryrelation := c
-			g.Go(func() error {
-				marshalled, err := proto.Marshal(&expensecategoryrelationprocv1.ExpenseCategoryRelationDeleted{
-					ExpenseId:  expensecategoryrelation.GetExpenseId(),
-					CategoryId: req.GetId(),
-				})
-				if err != nil {
-					log.Error("failed marshalling expensecategoryrelation deleted event", logging.Error(err))
-					return errMarshalExpenseCategoryRelationDeleted
-				}
-				if err := rpProcessor.natsClient.Publish(environment.GetExpenseCategoryRelationDeletedSubject(req.GetGroupId(), expensecategoryrelation.GetExpenseId(), req.GetId()), marshalled); err != nil {
-					log.Error("failed publishing expensecategoryrelation deleted event", logging.Error(err))
-					return errPublishExpenseCategoryRelationDeleted
-				}
-				return nil
+	g, _ := errgroup.WithContext(ctx)
+	for _, c := range expensecategoryrelations {
+		expensecategoryrelation := c
+		g.Go(func() error {
+			marshalled, err := proto.Marshal(&expensecategoryrelationprocv1.ExpenseCategoryRelationDeleted{
+				ExpenseId:   expensecategoryrelation.GetExpenseId(),
+				CategoryId:  req.GetId(),
+				DeleteCause: req.GetDeleteCause(),
 			})
-		}
-		return g.Wait()
-	})
+			if err != nil {
+				log.Error("failed marshalling expensecategoryrelation deleted event", logging.Error(err))
+				return errMarshalExpenseCategoryRelationDeleted
+			}
+			if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetExpenseCategoryRelationDeletedSubject(req.GetGroupId(), expensecategoryrelation.GetExpenseId(), req.GetId()), marshalled); err != nil {
+				log.Error("failed publishing expensecategoryrelation deleted event", logging.Error(err))
+				return errPublishExpenseCategoryRelationDeleted
+			}
+			return nil
+		})
+	}
+	return g.Wait()
 }
@@ -0,0 +1,57 @@
+package expensecategoryrelation
+
+import (
+	"context"
+
+	categoryprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/category/v1"
+	expensecategoryrelationprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expensecategoryrelation/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/uptrace/bun"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
+)
+
+func (rpProcessor *expensecategoryrelationProcessor) categoryUndeleted(ctx context.Context, req *categoryprocv1.CategoryUndeleted) error {
+	log := logging.FromContext(ctx).With(logging.String("categoryId", req.GetId()))
+	log.Info("processing category.CategoryUndeleted event")
+
+	var expensecategoryrelations []*model.ExpenseCategoryRelation
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := util.UpdateMatchedReturning(ctx, tx, model.Undelete(tx.NewUpdate().Model(&expensecategoryrelations)), func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereDeletedBecauseOf(q.Where("category_id = ?", req.GetId()), req.GetDeleteCause())
+		}, "expense_id"); err != nil {
+			log.Error("failed restoring expense category relations related to restored category", logging.Error(err))
+			return errUndeleteExpenseCategoryRelations
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	g, _ := errgroup.WithContext(ctx)
+	for _, c := range expensecategoryrelations {
+		expensecategoryrelation := c
+		g.Go(func() error {
+			marshalled, err := proto.Marshal(&expensecategoryrelationprocv1.ExpenseCategoryRelationUndeleted{
+				ExpenseId:   expensecategoryrelation.GetExpenseId(),
+				CategoryId:  req.GetId(),
+				DeleteCause: req.GetDeleteCause(),
+			})
+			if err != nil {
+				log.Error("failed marshalling expensecategoryrelation undeleted event", logging.Error(err))
+				return errMarshalExpenseCategoryRelationUndeleted
+			}
+			if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetExpenseCategoryRelationUndeletedSubject(req.GetGroupId(), expensecategoryrelation.GetExpenseId(), req.GetId()), marshalled); err != nil {
+				log.Error("failed publishing expensecategoryrelation undeleted event", logging.Error(err))
+				return errPublishExpenseCategoryRelationUndeleted
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
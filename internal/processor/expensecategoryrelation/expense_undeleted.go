@@ -0,0 +1,66 @@
+package expensecategoryrelation
+
+import (
+	"context"
+
+	expenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expense/v1"
+	expensecategoryrelationprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expensecategoryrelation/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/uptrace/bun"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
+)
+
+func (rpProcessor *expensecategoryrelationProcessor) expenseUndeleted(ctx context.Context, req *expenseprocv1.ExpenseUndeleted) error {
+	log := logging.FromContext(ctx).With(logging.String("expenseId", req.GetId()))
+	log.Info("processing expense.ExpenseUndeleted event")
+
+	var expensecategoryrelations []*model.ExpenseCategoryRelation
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := util.UpdateMatchedReturning(ctx, tx, model.Undelete(tx.NewUpdate().Model(&expensecategoryrelations)), func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereDeletedBecauseOf(q.Where("expense_id = ?", req.GetId()), req.GetDeleteCause())
+		}, "category_id"); err != nil {
+			log.Error("failed restoring expense category relations related to restored expense", logging.Error(err))
+			return errUndeleteExpenseCategoryRelations
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	g, _ := errgroup.WithContext(ctx)
+	for _, c := range expensecategoryrelations {
+		expensecategoryrelation := c
+		g.Go(func() error {
+			marshalled, err := proto.Marshal(&expensecategoryrelationprocv1.ExpenseCategoryRelationUndeleted{
+				ExpenseId:   req.GetId(),
+				CategoryId:  expensecategoryrelation.GetCategoryId(),
+				DeleteCause: req.GetDeleteCause(),
+			})
+			if err != nil {
+				log.Error("failed marshalling expensecategoryrelation undeleted event", logging.Error(err))
+				return errMarshalExpenseCategoryRelationUndeleted
+			}
+			if err := mqClient.PublishEventData(
+				ctx,
+				rpProcessor.natsClient,
+				environment.GetExpenseCategoryRelationUndeletedSubject(
+					req.GetGroupId(),
+					req.GetId(),
+					expensecategoryrelation.GetCategoryId(),
+				),
+				marshalled,
+			); err != nil {
+				log.Error("failed publishing expensecategoryrelation undeleted event", logging.Error(err))
+				return errPublishExpenseCategoryRelationUndeleted
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
@@ -22,16 +22,28 @@ type expensecategoryrelationProcessor struct {
 var errDeleteExpenseCategoryRelations = eris.New("failed deleting expense category relations")
 var errMarshalExpenseCategoryRelationDeleted = eris.New("could not marshal expensecategoryrelation deleted message")
 var errPublishExpenseCategoryRelationDeleted = eris.New("could not publish expensecategoryrelation deleted event")
+var errUndeleteExpenseCategoryRelations = eris.New("failed restoring expense category relations")
+var errMarshalExpenseCategoryRelationUndeleted = eris.New("could not marshal expensecategoryrelation undeleted message")
+var errPublishExpenseCategoryRelationUndeleted = eris.New("could not publish expensecategoryrelation undeleted event")
 
 // NewExpenseCategoryRelationServer creates a new instance of expensecategoryrelation server.
-func NewExpenseCategoryRelationProcessor(natsUrl, dbUser, dbPass, dbAddr, db string) (*expensecategoryrelationProcessor, error) {
+func NewExpenseCategoryRelationProcessor(natsUrl string, dbConfig client.Config) (*expensecategoryrelationProcessor, error) {
+	db, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed creating database client")
+	}
+	return NewExpenseCategoryRelationProcessorWithDBClient(natsUrl, db)
+}
+
+// NewExpenseCategoryRelationProcessorWithDBClient creates a new instance of expensecategoryrelation processor using the passed database client.
+func NewExpenseCategoryRelationProcessorWithDBClient(natsUrl string, db bun.IDB) (*expensecategoryrelationProcessor, error) {
 	nc, err := nats.Connect(natsUrl)
 	if err != nil {
 		return nil, eris.Wrap(err, "failed connecting to NATS server")
 	}
 	return &expensecategoryrelationProcessor{
 		natsClient: nc,
-		dbClient:   client.NewPostgresDBClient(dbUser, dbPass, dbAddr, db),
+		dbClient:   db,
 	}, nil
 }
 
@@ -90,9 +102,27 @@ func (rpProcessor *expensecategoryrelationProcessor) Process(ctx context.Context
 			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
 		}
 	}
+	var euCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetExpenseUndeletedSubject("*", "*")
+		var err error
+		euCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, expenseSourceStreamName, "EXPENSESPLITTER_EXPENSECATEGORYRELATION_PROCESSOR_EXPENSE_UNDELETED", eventSubject, rpProcessor.expenseUndeleted)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var cuCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetCategoryUndeletedSubject("*", "*")
+		var err error
+		cuCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, categorySourceStreamName, "EXPENSESPLITTER_EXPENSECATEGORYRELATION_PROCESSOR_CATEGORY_UNDELETED", eventSubject, rpProcessor.categoryUndeleted)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
 
 	<-ctx.Done()
 	log.Info("the context is done")
-	processor.UnsubscribeConsumeContexts(escCCtx, esdCCtx, edCCtx, cdCCtx)
+	processor.UnsubscribeConsumeContexts(escCCtx, esdCCtx, edCCtx, cdCCtx, euCCtx, cuCCtx)
 	return nil
 }
@@ -2,13 +2,16 @@ package expensestake
 
 import (
 	"context"
-	"database/sql"
+	"time"
 
-	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensestake/v1"
 	expenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expense/v1"
 	expensestakeprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expensestake/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/uptrace/bun"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/proto"
@@ -18,31 +21,39 @@ func (rpProcessor *expensestakeProcessor) expenseDeleted(ctx context.Context, re
 	log := logging.FromContext(ctx).With(logging.String("expenseId", req.GetId()))
 	log.Info("processing expense.ExpenseDeleted event")
 
-	return rpProcessor.dbClient.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		var expensestakes []*expensestakev1.ExpenseStake
-		if err := tx.NewDelete().Model(&expensestakes).Where("expense_id = ?", req.GetId()).Returning("id").Scan(ctx); err != nil {
+	var expensestakes []*model.ExpenseStake
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := util.UpdateMatchedReturning(ctx, tx, model.SoftDelete(tx.NewUpdate().Model(&expensestakes), req.GetDeleteCause(), time.Now()), func(q bun.QueryBuilder) bun.QueryBuilder {
+			return q.Where("expense_id = ?", req.GetId())
+		}, "id"); err != nil {
 			log.Error("failed deleting expense stakes related to deleted expense", logging.Error(err))
 			return errDeleteExpenseStakes
 		}
+		return nil
+	}); err != nil {
+		return err
+	}
 
-		g, _ := errgroup.WithContext(ctx)
-		for _, c := range expensestakes {
-			expensestake := c
-			g.Go(func() error {
-				marshalled, err := proto.Marshal(&expensestakeprocv1.ExpenseStakeDeleted{
-					Id: expensestake.Id,
-				})
-				if err != nil {
-					log.Error("failed marshalling expensestake deleted event", logging.Error(err))
-					return errMarshalExpenseStakeDeleted
-				}
-				if err := rpProcessor.natsClient.Publish(environment.GetExpenseStakeDeletedSubject(req.GetGroupId(), req.GetId(), expensestake.Id), marshalled); err != nil {
-					log.Error("failed publishing expensestake deleted event", logging.Error(err))
-					return errPublishExpenseStakeDeleted
-				}
-				return nil
+	g, _ := errgroup.WithContext(ctx)
+	for _, c := range expensestakes {
+		expensestake := c
+		g.Go(func() error {
+			marshalled, err := proto.Marshal(&expensestakeprocv1.ExpenseStakeDeleted{
+				Id:          expensestake.Id,
+				ExpenseId:   req.GetId(),
+				GroupId:     req.GetGroupId(),
+				DeleteCause: req.GetDeleteCause(),
 			})
-		}
-		return g.Wait()
-	})
+			if err != nil {
+				log.Error("failed marshalling expensestake deleted event", logging.Error(err))
+				return errMarshalExpenseStakeDeleted
+			}
+			if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetExpenseStakeDeletedSubject(req.GetGroupId(), req.GetId(), expensestake.Id), marshalled); err != nil {
+				log.Error("failed publishing expensestake deleted event", logging.Error(err))
+				return errPublishExpenseStakeDeleted
+			}
+			return nil
+		})
+	}
+	return g.Wait()
 }
@@ -0,0 +1,58 @@
+package expensestake
+
+import (
+	"context"
+
+	expenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expense/v1"
+	expensestakeprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expensestake/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/uptrace/bun"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
+)
+
+func (rpProcessor *expensestakeProcessor) expenseUndeleted(ctx context.Context, req *expenseprocv1.ExpenseUndeleted) error {
+	log := logging.FromContext(ctx).With(logging.String("expenseId", req.GetId()))
+	log.Info("processing expense.ExpenseUndeleted event")
+
+	var expensestakes []*model.ExpenseStake
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := util.UpdateMatchedReturning(ctx, tx, model.Undelete(tx.NewUpdate().Model(&expensestakes)), func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereDeletedBecauseOf(q.Where("expense_id = ?", req.GetId()), req.GetDeleteCause())
+		}, "id"); err != nil {
+			log.Error("failed restoring expense stakes related to restored expense", logging.Error(err))
+			return errUndeleteExpenseStakes
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	g, _ := errgroup.WithContext(ctx)
+	for _, c := range expensestakes {
+		expensestake := c
+		g.Go(func() error {
+			marshalled, err := proto.Marshal(&expensestakeprocv1.ExpenseStakeUndeleted{
+				Id:          expensestake.Id,
+				ExpenseId:   req.GetId(),
+				GroupId:     req.GetGroupId(),
+				DeleteCause: req.GetDeleteCause(),
+			})
+			if err != nil {
+				log.Error("failed marshalling expensestake undeleted event", logging.Error(err))
+				return errMarshalExpenseStakeUndeleted
+			}
+			if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetExpenseStakeUndeletedSubject(req.GetGroupId(), req.GetId(), expensestake.Id), marshalled); err != nil {
+				log.Error("failed publishing expensestake undeleted event", logging.Error(err))
+				return errPublishExpenseStakeUndeleted
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
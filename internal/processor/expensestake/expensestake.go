@@ -22,16 +22,28 @@ type expensestakeProcessor struct {
 var errDeleteExpenseStakes = eris.New("failed deleting expense stakes")
 var errMarshalExpenseStakeDeleted = eris.New("could not marshal expensestake deleted message")
 var errPublishExpenseStakeDeleted = eris.New("could not publish expensestake deleted event")
+var errUndeleteExpenseStakes = eris.New("failed restoring expense stakes")
+var errMarshalExpenseStakeUndeleted = eris.New("could not marshal expensestake undeleted message")
+var errPublishExpenseStakeUndeleted = eris.New("could not publish expensestake undeleted event")
 
 // NewExpenseStakeServer creates a new instance of expensestake server.
-func NewExpenseStakeProcessor(natsUrl, dbUser, dbPass, dbAddr, db string) (*expensestakeProcessor, error) {
+func NewExpenseStakeProcessor(natsUrl string, dbConfig client.Config) (*expensestakeProcessor, error) {
+	db, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed creating database client")
+	}
+	return NewExpenseStakeProcessorWithDBClient(natsUrl, db)
+}
+
+// NewExpenseStakeProcessorWithDBClient creates a new instance of expensestake processor using the passed database client.
+func NewExpenseStakeProcessorWithDBClient(natsUrl string, db bun.IDB) (*expensestakeProcessor, error) {
 	nc, err := nats.Connect(natsUrl)
 	if err != nil {
 		return nil, eris.Wrap(err, "failed connecting to NATS server")
 	}
 	return &expensestakeProcessor{
 		natsClient: nc,
-		dbClient:   client.NewPostgresDBClient(dbUser, dbPass, dbAddr, db),
+		dbClient:   db,
 	}, nil
 }
 
@@ -89,9 +101,18 @@ func (rpProcessor *expensestakeProcessor) Process(ctx context.Context) error {
 			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
 		}
 	}
+	var euCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetExpenseUndeletedSubject("*", "*")
+		var err error
+		euCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, expenseSourceStreamName, "EXPENSESPLITTER_EXPENSESTAKE_PROCESSOR_EXPENSE_UNDELETED", eventSubject, rpProcessor.expenseUndeleted)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
 
 	<-ctx.Done()
 	log.Info("the context is done")
-	processor.UnsubscribeConsumeContexts(escCCtx, esdCCtx, esuCCtx, edCCtx)
+	processor.UnsubscribeConsumeContexts(escCCtx, esdCCtx, esuCCtx, edCCtx, euCCtx)
 	return nil
 }
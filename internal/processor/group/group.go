@@ -3,27 +3,48 @@ package group
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/election"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/processor"
 	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
 )
 
 type groupProcessor struct {
 	natsClient *nats.Conn
+	dbClient   bun.IDB
 }
 
+const tickerPeriod = time.Hour
+const leaseDuration = 15 * time.Second
+const leaderElectionKey = "tombstone-purge"
+
+var errPurgeTombstones = eris.New("failed purging tombstones")
+
 // NewGroupServer creates a new instance of group server.
-func NewGroupProcessor(natsUrl string) (*groupProcessor, error) {
+func NewGroupProcessor(natsUrl string, dbConfig client.Config) (*groupProcessor, error) {
+	db, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed creating database client")
+	}
+	return NewGroupProcessorWithDBClient(natsUrl, db)
+}
+
+// NewGroupProcessorWithDBClient creates a new instance of group processor using the passed database client.
+func NewGroupProcessorWithDBClient(natsUrl string, db bun.IDB) (*groupProcessor, error) {
 	nc, err := nats.Connect(natsUrl)
 	if err != nil {
 		return nil, eris.Wrap(err, "failed connecting to NATS server")
 	}
 	return &groupProcessor{
 		natsClient: nc,
+		dbClient:   db,
 	}, nil
 }
 
@@ -72,8 +93,19 @@ func (rpProcessor *groupProcessor) Process(ctx context.Context) error {
 		}
 	}
 
-	<-ctx.Done()
-	log.Info("the context is done")
+	leaderElection, err := election.NewLeaderElection(
+		ctx,
+		rpProcessor.natsClient,
+		environment.GetLeaderElectionBucketName(),
+		leaderElectionKey,
+		leaseDuration)
+	if err != nil {
+		processor.UnsubscribeConsumeContexts(gcCCtx, gdCCtx, guCCtx)
+		return eris.Wrap(err, "failed creating leader election for periodic tombstone purges")
+	}
+	// only the leading replica purges tombstones periodically so that replicas do not race each other
+	leaderElection.Run(ctx, rpProcessor.purgeTombstonesPeriodically)
+
 	processor.UnsubscribeConsumeContexts(gcCCtx, gdCCtx, guCCtx)
 	return nil
 }
@@ -0,0 +1,135 @@
+package group
+
+import (
+	"context"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+// purgeTombstonesPeriodically purges the tombstones initially and then once per ticker period until the context is done
+func (rpProcessor *groupProcessor) purgeTombstonesPeriodically(ctx context.Context) {
+	log := logging.FromContext(ctx)
+
+	if err := rpProcessor.purgeTombstones(ctx); err != nil {
+		log.Error("could not purge tombstones initially", logging.Error(err))
+	} else {
+		log.Info("successfully purged tombstones initially")
+	}
+
+	ticker := time.NewTicker(tickerPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := rpProcessor.purgeTombstones(ctx); err != nil {
+				log.Error("could not purge tombstones", logging.Error(err))
+			} else {
+				log.Info("successfully purged tombstones")
+			}
+		case <-ctx.Done():
+			log.Info("stopped purging tombstones")
+			return
+		}
+	}
+}
+
+// purgeTombstones permanently deletes the resources that have been in the trash for longer than the tombstone retention.
+// Resources are purged before the ones they belong to, which were deleted no later than them, so that no resource outlives its parent.
+func (rpProcessor *groupProcessor) purgeTombstones(ctx context.Context) error {
+	log := logging.FromContext(ctx)
+
+	deletedBefore := time.Now().Add(-environment.GetTombstoneRetention(ctx))
+	// the revisions of an expense are not deleted along with it but belong to it until it is purged
+	purgedExpenses := rpProcessor.dbClient.NewSelect().Model((*model.Expense)(nil)).Column("id").WhereDeleted().Where("delete_time < ?", deletedBefore)
+	if _, err := rpProcessor.dbClient.NewDelete().Model((*model.ExpenseRevision)(nil)).Where("expense_id IN (?)", purgedExpenses).Exec(ctx); err != nil {
+		log.Error("failed purging revisions of expenses", logging.Error(err))
+		return errPurgeTombstones
+	}
+	// the edit history of a comment is only deleted along with the comment once it is purged
+	purgedComments := rpProcessor.dbClient.NewSelect().Model((*model.Comment)(nil)).Column("id").WhereDeleted().Where("delete_time < ?", deletedBefore)
+	if _, err := rpProcessor.dbClient.NewDelete().Model((*model.CommentRevision)(nil)).Where("comment_id IN (?)", purgedComments).Exec(ctx); err != nil {
+		log.Error("failed purging revisions of comments", logging.Error(err))
+		return errPurgeTombstones
+	}
+	// recurring expenses are not soft deleted but the scheduler pauses them once their group is deleted
+	purgedGroups := rpProcessor.dbClient.NewSelect().Model((*model.Group)(nil)).Column("id").WhereDeleted().Where("delete_time < ?", deletedBefore)
+	for _, m := range []interface{}{
+		(*model.RecurringExpenseOccurrence)(nil),
+		(*model.RecurringExpense)(nil),
+	} {
+		if _, err := rpProcessor.dbClient.NewDelete().Model(m).Where("group_id IN (?)", purgedGroups).Exec(ctx); err != nil {
+			log.Error("failed purging recurring expenses of groups", logging.Error(err))
+			return errPurgeTombstones
+		}
+	}
+	// users keep being notified about a deleted person or group until it is purged, which unlinks the users from the person
+	// and drops the notifications about the group which are pending for the next digest
+	purgedPersons := rpProcessor.dbClient.NewSelect().Model((*model.Person)(nil)).Column("id").WhereDeleted().Where("delete_time < ?", deletedBefore)
+	if _, err := rpProcessor.dbClient.NewDelete().Model((*model.NotificationPreferencePerson)(nil)).Where("person_id IN (?)", purgedPersons).Exec(ctx); err != nil {
+		log.Error("failed unlinking users from persons", logging.Error(err))
+		return errPurgeTombstones
+	}
+	if _, err := rpProcessor.dbClient.NewDelete().Model((*model.PendingNotification)(nil)).Where("group_id IN (?)", purgedGroups).Exec(ctx); err != nil {
+		log.Error("failed purging pending notifications of groups", logging.Error(err))
+		return errPurgeTombstones
+	}
+	// webhooks keep receiving the activities of a deleted group, e.g. its restoration, until the group is purged
+	for _, m := range []interface{}{
+		(*model.WebhookDelivery)(nil),
+		(*model.Webhook)(nil),
+	} {
+		if _, err := rpProcessor.dbClient.NewDelete().Model(m).Where("group_id IN (?)", purgedGroups).Exec(ctx); err != nil {
+			log.Error("failed purging webhooks of groups", logging.Error(err))
+			return errPurgeTombstones
+		}
+	}
+	// the debt reminder settings of a deleted group or person are kept for its restoration until it is purged
+	for _, m := range []interface{}{
+		(*model.DebtReminder)(nil),
+		(*model.DebtReminderSnooze)(nil),
+	} {
+		if _, err := rpProcessor.dbClient.NewDelete().Model(m).Where("group_id IN (?) OR person_id IN (?)", purgedGroups, purgedPersons).Exec(ctx); err != nil {
+			log.Error("failed purging debt reminders of groups and persons", logging.Error(err))
+			return errPurgeTombstones
+		}
+	}
+	if _, err := rpProcessor.dbClient.NewDelete().Model((*model.DebtReminderPolicy)(nil)).Where("group_id IN (?)", purgedGroups).Exec(ctx); err != nil {
+		log.Error("failed purging debt reminder policies of groups", logging.Error(err))
+		return errPurgeTombstones
+	}
+	// the budgets of a deleted group or category are kept for its restoration until it is purged
+	purgedCategories := rpProcessor.dbClient.NewSelect().Model((*model.Category)(nil)).Column("id").WhereDeleted().Where("delete_time < ?", deletedBefore)
+	purgedBudgets := rpProcessor.dbClient.NewSelect().Model((*model.Budget)(nil)).Column("id").Where("group_id IN (?) OR category_id IN (?)", purgedGroups, purgedCategories)
+	if _, err := rpProcessor.dbClient.NewDelete().Model((*model.BudgetAlert)(nil)).Where("budget_id IN (?)", purgedBudgets).Exec(ctx); err != nil {
+		log.Error("failed purging alerts of budgets", logging.Error(err))
+		return errPurgeTombstones
+	}
+	if _, err := rpProcessor.dbClient.NewDelete().Model((*model.Budget)(nil)).Where("group_id IN (?) OR category_id IN (?)", purgedGroups, purgedCategories).Exec(ctx); err != nil {
+		log.Error("failed purging budgets of groups and categories", logging.Error(err))
+		return errPurgeTombstones
+	}
+	for _, m := range []interface{}{
+		(*model.Comment)(nil),
+		(*model.ExpenseCategoryRelation)(nil),
+		(*model.ExpenseStake)(nil),
+		(*model.Expense)(nil),
+		(*model.Category)(nil),
+		(*model.Person)(nil),
+		(*model.Group)(nil),
+	} {
+		query := rpProcessor.dbClient.NewDelete().Model(m).WhereDeleted().ForceDelete().Where("delete_time < ?", deletedBefore)
+		res, err := query.Exec(ctx)
+		if err != nil {
+			log.Error("failed purging tombstones", logging.Error(err))
+			return errPurgeTombstones
+		}
+		if purged, err := res.RowsAffected(); err == nil && purged > 0 {
+			log.Info("purged tombstones", logging.String("table", query.GetTableName()), logging.Int64("count", purged))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+)
+
+// commentTemplateData returns the data of the templates notifying about a comment or nil if the comment does not exist anymore
+func (rpProcessor *notificationProcessor) commentTemplateData(ctx context.Context, commentId string, expenseId string, groupId string, authorId string) (*templateData, error) {
+	comment, err := selectWithDeleted[*model.Comment](ctx, rpProcessor.dbClient, commentId)
+	if err != nil {
+		return nil, err
+	}
+	expense, err := selectWithDeleted[*model.Expense](ctx, rpProcessor.dbClient, expenseId)
+	if err != nil {
+		return nil, err
+	}
+	group, err := selectWithDeleted[*model.Group](ctx, rpProcessor.dbClient, groupId)
+	if err != nil {
+		return nil, err
+	}
+	author, err := selectWithDeleted[*model.Person](ctx, rpProcessor.dbClient, authorId)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil || expense == nil || group == nil || author == nil {
+		return nil, nil
+	}
+	return &templateData{
+		GroupName: group.GetName(),
+		Name:      expense.GetName(),
+		Author:    author.GetName(),
+		Text:      excerpt(comment.GetText()),
+	}, nil
+}
@@ -0,0 +1,45 @@
+package notification
+
+import (
+	"context"
+
+	notificationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/notification/v1"
+	commentprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/comment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+func (rpProcessor *notificationProcessor) commentCreated(ctx context.Context, req *commentprocv1.CommentCreated) error {
+	log := logging.FromContext(ctx).With(
+		logging.String("groupId", req.GetGroupId()),
+		logging.String("expenseId", req.GetExpenseId()),
+		logging.String("commentId", req.GetId()))
+	log.Info("processing comment.CommentCreated event")
+
+	data, err := rpProcessor.commentTemplateData(ctx, req.GetId(), req.GetExpenseId(), req.GetGroupId(), req.GetAuthorId())
+	if err != nil {
+		log.Error("failed selecting created comment", logging.Error(err))
+		return errSelectResource
+	}
+	if data == nil {
+		log.Info("the created comment does not exist anymore")
+		return nil
+	}
+	// users are told about being mentioned rather than about the comment if they want to be notified about both;
+	// the persons are copied into a non-nil slice since nil would notify all users of the group about the mention
+	mentioned, err := rpProcessor.notify(ctx, notification{
+		groupId:   req.GetGroupId(),
+		eventType: notificationv1.NotificationPreference_EVENT_TYPE_MENTIONED,
+		personIds: append([]string{}, req.GetMentionedPersonIds()...),
+		data:      *data,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = rpProcessor.notify(ctx, notification{
+		groupId:            req.GetGroupId(),
+		eventType:          notificationv1.NotificationPreference_EVENT_TYPE_COMMENT_CREATED,
+		excludedPrincipals: mentioned,
+		data:               *data,
+	})
+	return err
+}
@@ -0,0 +1,38 @@
+package notification
+
+import (
+	"context"
+
+	notificationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/notification/v1"
+	commentprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/comment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+func (rpProcessor *notificationProcessor) commentUpdated(ctx context.Context, req *commentprocv1.CommentUpdated) error {
+	log := logging.FromContext(ctx).With(
+		logging.String("groupId", req.GetGroupId()),
+		logging.String("expenseId", req.GetExpenseId()),
+		logging.String("commentId", req.GetId()))
+	log.Info("processing comment.CommentUpdated event")
+
+	// editing a comment only notifies the persons it mentions for the first time
+	if len(req.GetNewlyMentionedPersonIds()) == 0 {
+		return nil
+	}
+	data, err := rpProcessor.commentTemplateData(ctx, req.GetId(), req.GetExpenseId(), req.GetGroupId(), req.GetAuthorId())
+	if err != nil {
+		log.Error("failed selecting updated comment", logging.Error(err))
+		return errSelectResource
+	}
+	if data == nil {
+		log.Info("the updated comment does not exist anymore")
+		return nil
+	}
+	_, err = rpProcessor.notify(ctx, notification{
+		groupId:   req.GetGroupId(),
+		eventType: notificationv1.NotificationPreference_EVENT_TYPE_MENTIONED,
+		personIds: req.GetNewlyMentionedPersonIds(),
+		data:      *data,
+	})
+	return err
+}
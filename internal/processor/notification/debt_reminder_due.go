@@ -0,0 +1,49 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+
+	notificationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/notification/v1"
+	debtreminderprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/debtreminder/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+)
+
+func (rpProcessor *notificationProcessor) debtReminderDue(ctx context.Context, req *debtreminderprocv1.DebtReminderDue) error {
+	log := logging.FromContext(ctx).With(
+		logging.String("groupId", req.GetGroupId()),
+		logging.String("personId", req.GetPersonId()))
+	log.Info("processing debtreminder.DebtReminderDue event")
+
+	// unlike the other events the reminder is pointless once the group is deleted
+	group, err := util.CheckResourceExists[*model.Group](ctx, rpProcessor.dbClient, req.GetGroupId())
+	if err != nil {
+		if eris.As(err, &util.ResourceNotFoundError{}) {
+			log.Info("the group of the debt does not exist anymore")
+			return nil
+		}
+		log.Error("failed selecting group of debt", logging.Error(err))
+		return errSelectResource
+	}
+	currency, err := util.CheckResourceExists[*model.Currency](ctx, rpProcessor.dbClient, req.GetCurrencyId())
+	if err != nil {
+		log.Error("failed selecting currency of debt", logging.Error(err))
+		return errSelectResource
+	}
+	_, err = rpProcessor.notify(ctx, notification{
+		groupId:   req.GetGroupId(),
+		eventType: notificationv1.NotificationPreference_EVENT_TYPE_DEBT_REMINDER,
+		// only the users represented by the person owing the debt are reminded
+		personIds: []string{req.GetPersonId()},
+		data: templateData{
+			GroupName: group.GetName(),
+			Amount:    fmt.Sprintf("%d.%02d", req.GetMainValue(), req.GetFractionalValue()),
+			Currency:  currency.GetAcronym(),
+			Since:     req.GetSince().AsTime().Format("2006-01-02"),
+		},
+	})
+	return err
+}
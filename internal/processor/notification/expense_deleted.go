@@ -0,0 +1,46 @@
+package notification
+
+import (
+	"context"
+
+	notificationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/notification/v1"
+	expenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+func (rpProcessor *notificationProcessor) expenseDeleted(ctx context.Context, req *expenseprocv1.ExpenseDeleted) error {
+	log := logging.FromContext(ctx).With(
+		logging.String("groupId", req.GetGroupId()),
+		logging.String("expenseId", req.GetId()))
+	log.Info("processing expense.ExpenseDeleted event")
+
+	if req.GetDeleteCause() != req.GetId() {
+		log.Debug("not notifying about expense deleted along with another resource")
+		return nil
+	}
+
+	expense, err := selectWithDeleted[*model.Expense](ctx, rpProcessor.dbClient, req.GetId())
+	if err != nil {
+		log.Error("failed selecting deleted expense", logging.Error(err))
+		return errSelectResource
+	}
+	group, err := selectWithDeleted[*model.Group](ctx, rpProcessor.dbClient, req.GetGroupId())
+	if err != nil {
+		log.Error("failed selecting group of deleted expense", logging.Error(err))
+		return errSelectResource
+	}
+	if expense == nil || group == nil {
+		log.Info("the deleted expense does not exist anymore")
+		return nil
+	}
+	_, err = rpProcessor.notify(ctx, notification{
+		groupId:   req.GetGroupId(),
+		eventType: notificationv1.NotificationPreference_EVENT_TYPE_EXPENSE_DELETED,
+		data: templateData{
+			GroupName: group.GetName(),
+			Name:      expense.GetName(),
+		},
+	})
+	return err
+}
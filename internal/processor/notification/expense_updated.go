@@ -0,0 +1,41 @@
+package notification
+
+import (
+	"context"
+
+	notificationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/notification/v1"
+	expenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+func (rpProcessor *notificationProcessor) expenseUpdated(ctx context.Context, req *expenseprocv1.ExpenseUpdated) error {
+	log := logging.FromContext(ctx).With(
+		logging.String("groupId", req.GetGroupId()),
+		logging.String("expenseId", req.GetId()))
+	log.Info("processing expense.ExpenseUpdated event")
+
+	expense, err := selectWithDeleted[*model.Expense](ctx, rpProcessor.dbClient, req.GetId())
+	if err != nil {
+		log.Error("failed selecting updated expense", logging.Error(err))
+		return errSelectResource
+	}
+	group, err := selectWithDeleted[*model.Group](ctx, rpProcessor.dbClient, req.GetGroupId())
+	if err != nil {
+		log.Error("failed selecting group of updated expense", logging.Error(err))
+		return errSelectResource
+	}
+	if expense == nil || group == nil {
+		log.Info("the updated expense does not exist anymore")
+		return nil
+	}
+	_, err = rpProcessor.notify(ctx, notification{
+		groupId:   req.GetGroupId(),
+		eventType: notificationv1.NotificationPreference_EVENT_TYPE_EXPENSE_UPDATED,
+		data: templateData{
+			GroupName: group.GetName(),
+			Name:      expense.GetName(),
+		},
+	})
+	return err
+}
@@ -0,0 +1,33 @@
+package notification
+
+import (
+	"context"
+
+	notificationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/notification/v1"
+	groupprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/group/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+func (rpProcessor *notificationProcessor) groupUpdated(ctx context.Context, req *groupprocv1.GroupUpdated) error {
+	log := logging.FromContext(ctx).With(logging.String("groupId", req.GetId()))
+	log.Info("processing group.GroupUpdated event")
+
+	group, err := selectWithDeleted[*model.Group](ctx, rpProcessor.dbClient, req.GetId())
+	if err != nil {
+		log.Error("failed selecting updated group", logging.Error(err))
+		return errSelectResource
+	}
+	if group == nil {
+		log.Info("the updated group does not exist anymore")
+		return nil
+	}
+	_, err = rpProcessor.notify(ctx, notification{
+		groupId:   req.GetId(),
+		eventType: notificationv1.NotificationPreference_EVENT_TYPE_GROUP_UPDATED,
+		data: templateData{
+			GroupName: group.GetName(),
+		},
+	})
+	return err
+}
@@ -0,0 +1,211 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mail"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/election"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/processor"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+
+	// the digests are scheduled in the time zones of the users which have to be known even if the image lacks a zoneinfo database
+	_ "time/tzdata"
+)
+
+type notificationProcessor struct {
+	natsClient *nats.Conn
+	dbClient   bun.IDB
+	sender     mail.Sender
+	templates  templates
+}
+
+const tickerPeriod = time.Minute
+const leaseDuration = 15 * time.Second
+const leaderElectionKey = "notification-digest"
+
+var errSelectResource = eris.New("failed selecting resource the event is about")
+var errSelectRecipients = eris.New("failed selecting recipients of notification")
+var errDeliverNotification = eris.New("failed delivering notification to some of its recipients")
+var errSelectPendingNotifications = eris.New("failed selecting pending notifications")
+var errSendDigest = eris.New("failed sending digest to some of its recipients")
+
+// NewNotificationProcessor creates a new instance of notification processor sending emails through the configured SMTP server.
+func NewNotificationProcessor(natsUrl string, dbConfig client.Config, mailConfig mail.Config) (*notificationProcessor, error) {
+	db, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed creating database client")
+	}
+	sender, err := mail.NewSMTPSender(mailConfig)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed creating SMTP sender")
+	}
+	return NewNotificationProcessorWithClients(natsUrl, db, sender)
+}
+
+// NewNotificationProcessorWithClients creates a new instance of notification processor using the passed database client and mail sender.
+func NewNotificationProcessorWithClients(natsUrl string, db bun.IDB, sender mail.Sender) (*notificationProcessor, error) {
+	t, err := parseTemplates()
+	if err != nil {
+		return nil, eris.Wrap(err, "failed parsing notification templates")
+	}
+	nc, err := nats.Connect(natsUrl)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed connecting to NATS server")
+	}
+	return &notificationProcessor{
+		natsClient: nc,
+		dbClient:   db,
+		sender:     sender,
+		templates:  t,
+	}, nil
+}
+
+// Process starts the processing of subscriptions and sends the daily digests once this replica leads until the context is done
+func (rpProcessor *notificationProcessor) Process(ctx context.Context) error {
+	log := logging.FromContext(ctx).Named("Process")
+	ctx = logging.IntoContext(ctx, log)
+
+	groupSourceStreamName := environment.GetGroupSourceStreamName()
+	personSourceStreamName := environment.GetPersonSourceStreamName()
+	expenseSourceStreamName := environment.GetExpenseSourceStreamName()
+	commentSourceStreamName := environment.GetCommentSourceStreamName()
+	debtReminderSourceStreamName := environment.GetDebtReminderSourceStreamName()
+
+	// the notifications are about events of the resources of other processors whose source streams are created with the same
+	// subjects here in case this processor starts first
+	for _, source := range []struct {
+		sourceStreamName string
+		subject          string
+	}{
+		{groupSourceStreamName, fmt.Sprintf("%s.*", environment.GetGroupSubject("*"))},
+		{personSourceStreamName, fmt.Sprintf("%s.*", environment.GetPersonSubject("*", "*"))},
+		{expenseSourceStreamName, fmt.Sprintf("%s.*", environment.GetExpenseSubject("*", "*"))},
+		{commentSourceStreamName, fmt.Sprintf("%s.*", environment.GetCommentSubject("*", "*", "*"))},
+		{debtReminderSourceStreamName, fmt.Sprintf("%s.*", environment.GetDebtReminderSubject("*", "*"))},
+	} {
+		if _, err := processor.CreateOrUpdateSourceStream(
+			ctx,
+			rpProcessor.natsClient,
+			source.sourceStreamName,
+			source.subject,
+		); err != nil {
+			return err
+		}
+	}
+
+	var guCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetGroupUpdatedSubject("*")
+		var err error
+		guCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, groupSourceStreamName, "EXPENSESPLITTER_NOTIFICATION_PROCESSOR_GROUP_UPDATED", eventSubject, rpProcessor.groupUpdated)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var gdCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetGroupDeletedSubject("*")
+		var err error
+		gdCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, groupSourceStreamName, "EXPENSESPLITTER_NOTIFICATION_PROCESSOR_GROUP_DELETED", eventSubject, rpProcessor.groupDeleted)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var pcCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetPersonCreatedSubject("*", "*")
+		var err error
+		pcCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, personSourceStreamName, "EXPENSESPLITTER_NOTIFICATION_PROCESSOR_PERSON_CREATED", eventSubject, rpProcessor.personCreated)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var pdCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetPersonDeletedSubject("*", "*")
+		var err error
+		pdCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, personSourceStreamName, "EXPENSESPLITTER_NOTIFICATION_PROCESSOR_PERSON_DELETED", eventSubject, rpProcessor.personDeleted)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var ecCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetExpenseCreatedSubject("*", "*")
+		var err error
+		ecCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, expenseSourceStreamName, "EXPENSESPLITTER_NOTIFICATION_PROCESSOR_EXPENSE_CREATED", eventSubject, rpProcessor.expenseCreated)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var euCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetExpenseUpdatedSubject("*", "*")
+		var err error
+		euCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, expenseSourceStreamName, "EXPENSESPLITTER_NOTIFICATION_PROCESSOR_EXPENSE_UPDATED", eventSubject, rpProcessor.expenseUpdated)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var edCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetExpenseDeletedSubject("*", "*")
+		var err error
+		edCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, expenseSourceStreamName, "EXPENSESPLITTER_NOTIFICATION_PROCESSOR_EXPENSE_DELETED", eventSubject, rpProcessor.expenseDeleted)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var ccCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetCommentCreatedSubject("*", "*", "*")
+		var err error
+		ccCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, commentSourceStreamName, "EXPENSESPLITTER_NOTIFICATION_PROCESSOR_COMMENT_CREATED", eventSubject, rpProcessor.commentCreated)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var cuCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetCommentUpdatedSubject("*", "*", "*")
+		var err error
+		cuCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, commentSourceStreamName, "EXPENSESPLITTER_NOTIFICATION_PROCESSOR_COMMENT_UPDATED", eventSubject, rpProcessor.commentUpdated)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var drdCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetDebtReminderDueSubject("*", "*")
+		var err error
+		drdCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, debtReminderSourceStreamName, "EXPENSESPLITTER_NOTIFICATION_PROCESSOR_DEBT_REMINDER_DUE", eventSubject, rpProcessor.debtReminderDue)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	cCtxs := []jetstream.ConsumeContext{guCCtx, gdCCtx, pcCCtx, pdCCtx, ecCCtx, euCCtx, edCCtx, ccCCtx, cuCCtx, drdCCtx}
+
+	leaderElection, err := election.NewLeaderElection(
+		ctx,
+		rpProcessor.natsClient,
+		environment.GetLeaderElectionBucketName(),
+		leaderElectionKey,
+		leaseDuration)
+	if err != nil {
+		processor.UnsubscribeConsumeContexts(cCtxs...)
+		return eris.Wrap(err, "failed creating leader election for sending digests")
+	}
+	// only the leading replica sends digests so that users do not receive a digest from every replica
+	leaderElection.Run(ctx, rpProcessor.sendDigestsPeriodically)
+
+	processor.UnsubscribeConsumeContexts(cCtxs...)
+	return nil
+}
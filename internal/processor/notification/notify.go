@@ -0,0 +1,168 @@
+package notification
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	notificationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/notification/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mail"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/proto"
+)
+
+// notification is an event users of a group are notified about
+type notification struct {
+	groupId   string
+	eventType notificationv1.NotificationPreference_EventType
+	// personIds restricts the recipients to the users represented by one of the persons; all users of the group are notified if it is nil
+	personIds []string
+	// excludedPrincipals are users who are not notified, e.g. since they were notified about the same event in another way
+	excludedPrincipals []string
+	data               templateData
+}
+
+// notify sends the notification right away to the users who want to be notified immediately and collects it for the digest of the others
+// and returns the users it notified. Users are never notified about their own changes. If delivering to some of the users fails, the others
+// are notified anyway and an error is returned so that the event is redelivered, which may notify some of the users twice.
+func (rpProcessor *notificationProcessor) notify(ctx context.Context, n notification) ([]string, error) {
+	log := logging.FromContext(ctx).With(
+		logging.String("groupId", n.groupId),
+		logging.String("eventType", n.eventType.String()))
+
+	preferences, err := rpProcessor.selectRecipients(ctx, n)
+	if err != nil {
+		log.Error("failed selecting recipients of notification", logging.Error(err))
+		return nil, errSelectRecipients
+	}
+	excluded := make(map[string]bool, len(n.excludedPrincipals))
+	for _, p := range n.excludedPrincipals {
+		excluded[p] = true
+	}
+
+	actor := principal.FromContext(ctx)
+	n.data.Actor = actorName(actor)
+	var notified []string
+	var failed bool
+	for _, preference := range preferences {
+		if preference.Principal == actor || excluded[preference.Principal] || !preference.Notifies(n.eventType) {
+			continue
+		}
+		log := log.With(logging.String("principal", preference.Principal))
+		summary, body, err := rpProcessor.templates.renderNotification(preference.Locale, n.eventType, n.data)
+		if err != nil {
+			log.Error("failed rendering notification", logging.Error(err))
+			failed = true
+			continue
+		}
+		if preference.Delivery == notificationv1.NotificationPreference_DELIVERY_DAILY_DIGEST {
+			if _, err := rpProcessor.dbClient.NewInsert().Model(&model.PendingNotification{
+				Id:         util.GenerateIdWithPrefix("notification"),
+				Principal:  preference.Principal,
+				GroupId:    n.groupId,
+				EventType:  n.eventType,
+				Summary:    summary,
+				CreateTime: time.Now(),
+			}).Exec(ctx); err != nil {
+				log.Error("failed collecting notification for digest", logging.Error(err))
+				failed = true
+				continue
+			}
+			notified = append(notified, preference.Principal)
+			continue
+		}
+		if err := rpProcessor.sender.Send(ctx, mail.Message{
+			To:      preference.Email,
+			Subject: summary,
+			Body:    body,
+		}); err != nil {
+			log.Error("failed sending notification", logging.Error(err))
+			failed = true
+			continue
+		}
+		notified = append(notified, preference.Principal)
+	}
+	if failed {
+		return notified, errDeliverNotification
+	}
+	return notified, nil
+}
+
+// selectRecipients returns the preferences of the users represented by a person of the group the notification is about
+func (rpProcessor *notificationProcessor) selectRecipients(ctx context.Context, n notification) ([]*model.NotificationPreference, error) {
+	if n.personIds != nil && len(n.personIds) == 0 {
+		return nil, nil
+	}
+	var links []*model.NotificationPreferencePerson
+	query := rpProcessor.dbClient.NewSelect().Model(&links).Where("group_id = ?", n.groupId)
+	if n.personIds != nil {
+		query = query.Where("person_id IN (?)", bun.In(n.personIds))
+	}
+	if err := query.Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	principals := make([]string, 0, len(links))
+	seen := make(map[string]bool, len(links))
+	for _, link := range links {
+		if seen[link.Principal] {
+			continue
+		}
+		seen[link.Principal] = true
+		principals = append(principals, link.Principal)
+	}
+	if len(principals) == 0 {
+		return nil, nil
+	}
+
+	var preferences []*model.NotificationPreference
+	if err := rpProcessor.dbClient.NewSelect().Model(&preferences).
+		Where("principal IN (?)", bun.In(principals)).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+// selectWithDeleted returns the resource with the passed ID even if it is deleted since users are notified about deletions as well,
+// or nil if it does not exist anymore, e.g. because it was purged before the event was processed
+func selectWithDeleted[T interface {
+	proto.Message
+	GetId() string
+}](ctx context.Context, db bun.IDB, id string) (T, error) {
+	resource, err := util.CheckResourceExistsWithDeleted[T](ctx, db, id)
+	if err != nil {
+		var zero T
+		if eris.As(err, &util.ResourceNotFoundError{}) {
+			return zero, nil
+		}
+		return zero, err
+	}
+	return resource, nil
+}
+
+// excerpt shortens the text of a comment quoted in a notification
+func excerpt(text string) string {
+	const maxLength = 280
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:maxLength])) + "…"
+}
+
+// actorName returns the name the principal causing an event is called in notifications or an empty string if it is unknown
+func actorName(actor string) string {
+	switch actor {
+	case principal.Anonymous:
+		return ""
+	case principal.System:
+		return "Expense Splitter"
+	}
+	return actor
+}
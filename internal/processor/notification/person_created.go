@@ -0,0 +1,36 @@
+package notification
+
+import (
+	"context"
+
+	notificationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/notification/v1"
+	personprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/person/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+func (rpProcessor *notificationProcessor) personCreated(ctx context.Context, req *personprocv1.PersonCreated) error {
+	log := logging.FromContext(ctx).With(
+		logging.String("groupId", req.GetGroupId()),
+		logging.String("personId", req.GetId()))
+	log.Info("processing person.PersonCreated event")
+
+	group, err := selectWithDeleted[*model.Group](ctx, rpProcessor.dbClient, req.GetGroupId())
+	if err != nil {
+		log.Error("failed selecting group of created person", logging.Error(err))
+		return errSelectResource
+	}
+	if group == nil {
+		log.Info("the group of the created person does not exist anymore")
+		return nil
+	}
+	_, err = rpProcessor.notify(ctx, notification{
+		groupId:   req.GetGroupId(),
+		eventType: notificationv1.NotificationPreference_EVENT_TYPE_PERSON_CREATED,
+		data: templateData{
+			GroupName: group.GetName(),
+			Name:      req.GetName(),
+		},
+	})
+	return err
+}
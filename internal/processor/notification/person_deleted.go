@@ -0,0 +1,45 @@
+package notification
+
+import (
+	"context"
+
+	notificationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/notification/v1"
+	personprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/person/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+func (rpProcessor *notificationProcessor) personDeleted(ctx context.Context, req *personprocv1.PersonDeleted) error {
+	log := logging.FromContext(ctx).With(
+		logging.String("groupId", req.GetGroupId()),
+		logging.String("personId", req.GetId()))
+	log.Info("processing person.PersonDeleted event")
+
+	if req.GetDeleteCause() != req.GetId() {
+		log.Debug("not notifying about person deleted along with another resource")
+		return nil
+	}
+	person, err := selectWithDeleted[*model.Person](ctx, rpProcessor.dbClient, req.GetId())
+	if err != nil {
+		log.Error("failed selecting deleted person", logging.Error(err))
+		return errSelectResource
+	}
+	group, err := selectWithDeleted[*model.Group](ctx, rpProcessor.dbClient, req.GetGroupId())
+	if err != nil {
+		log.Error("failed selecting group of deleted person", logging.Error(err))
+		return errSelectResource
+	}
+	if person == nil || group == nil {
+		log.Info("the deleted person does not exist anymore")
+		return nil
+	}
+	_, err = rpProcessor.notify(ctx, notification{
+		groupId:   req.GetGroupId(),
+		eventType: notificationv1.NotificationPreference_EVENT_TYPE_PERSON_DELETED,
+		data: templateData{
+			GroupName: group.GetName(),
+			Name:      person.GetName(),
+		},
+	})
+	return err
+}
@@ -0,0 +1,144 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mail"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+// sendDigestsPeriodically sends the due digests initially and then once per ticker period until the context is done
+func (rpProcessor *notificationProcessor) sendDigestsPeriodically(ctx context.Context) {
+	log := logging.FromContext(ctx)
+
+	if err := rpProcessor.sendDigests(ctx); err != nil {
+		log.Error("could not send digests initially", logging.Error(err))
+	} else {
+		log.Info("successfully sent digests initially")
+	}
+
+	ticker := time.NewTicker(tickerPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := rpProcessor.sendDigests(ctx); err != nil {
+				log.Error("could not send digests", logging.Error(err))
+			} else {
+				log.Debug("successfully sent digests")
+			}
+		case <-ctx.Done():
+			log.Info("stopped sending digests")
+			return
+		}
+	}
+}
+
+// sendDigests sends a digest of the pending notifications to every user whose digest hour passed since the last digest was sent.
+// The notifications of users who switched to immediate notifications in the meantime are sent with their next digest as well.
+func (rpProcessor *notificationProcessor) sendDigests(ctx context.Context) error {
+	log := logging.FromContext(ctx)
+
+	var principals []string
+	if err := rpProcessor.dbClient.NewSelect().Model((*model.PendingNotification)(nil)).
+		ColumnExpr("DISTINCT principal").
+		Scan(ctx, &principals); err != nil {
+		log.Error("failed selecting users with pending notifications", logging.Error(err))
+		return errSelectPendingNotifications
+	}
+	if len(principals) == 0 {
+		return nil
+	}
+	var preferences []*model.NotificationPreference
+	if err := rpProcessor.dbClient.NewSelect().Model(&preferences).
+		Where("principal IN (?)", bun.In(principals)).
+		Scan(ctx); err != nil {
+		log.Error("failed selecting notification preferences of users with pending notifications", logging.Error(err))
+		return errSelectPendingNotifications
+	}
+
+	now := time.Now()
+	var failed bool
+	for _, preference := range preferences {
+		if !digestDue(preference, now) {
+			continue
+		}
+		if err := rpProcessor.sendDigest(ctx, preference, now); err != nil {
+			log.Error("failed sending digest", logging.String("principal", preference.Principal), logging.Error(err))
+			failed = true
+		}
+	}
+	if failed {
+		return errSendDigest
+	}
+	return nil
+}
+
+// sendDigest sends the pending notifications of the user and drops them afterwards. If dropping them fails, they are sent again
+// with the next digest.
+func (rpProcessor *notificationProcessor) sendDigest(ctx context.Context, preference *model.NotificationPreference, now time.Time) error {
+	var pending []*model.PendingNotification
+	if err := rpProcessor.dbClient.NewSelect().Model(&pending).
+		Where("principal = ?", preference.Principal).
+		Order("create_time ASC", "id ASC").
+		Scan(ctx); err != nil {
+		return eris.Wrap(err, "failed selecting pending notifications")
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	ids := make([]string, len(pending))
+	summaries := make([]string, len(pending))
+	for i, p := range pending {
+		ids[i] = p.Id
+		summaries[i] = p.Summary
+	}
+
+	subject, body, err := rpProcessor.templates.renderDigest(preference.Locale, summaries)
+	if err != nil {
+		return eris.Wrap(err, "failed rendering digest")
+	}
+	if err := rpProcessor.sender.Send(ctx, mail.Message{
+		To:      preference.Email,
+		Subject: subject,
+		Body:    body,
+	}); err != nil {
+		return eris.Wrap(err, "failed sending digest")
+	}
+
+	return transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().Model((*model.PendingNotification)(nil)).
+			Where("id IN (?)", bun.In(ids)).
+			Exec(ctx); err != nil {
+			return eris.Wrap(err, "failed dropping sent notifications")
+		}
+		preference.LastDigestTime = &now
+		if _, err := tx.NewUpdate().Model(preference).
+			Column("last_digest_time").
+			WherePK().
+			Exec(ctx); err != nil {
+			return eris.Wrap(err, "failed recording time of digest")
+		}
+		return nil
+	})
+}
+
+// digestDue tells whether the digest hour of the user passed in the time zone of the user since the last digest was sent
+func digestDue(preference *model.NotificationPreference, now time.Time) bool {
+	location, err := time.LoadLocation(preference.GetTimeZone())
+	if err != nil {
+		location = time.UTC
+	}
+	local := now.In(location)
+	scheduled := time.Date(local.Year(), local.Month(), local.Day(), int(preference.GetDigestHour()), 0, 0, 0, location)
+	if scheduled.After(local) {
+		scheduled = scheduled.AddDate(0, 0, -1)
+	}
+	return preference.LastDigestTime == nil || preference.LastDigestTime.Before(scheduled)
+}
@@ -0,0 +1,146 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+
+	notificationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/notification/v1"
+	"github.com/rotisserie/eris"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// defaultLocale is the locale notifications are written in if the locale of a user is not supported
+const defaultLocale = "en"
+
+// summaryTemplateNames are the names of the templates rendering the single line summaries of the events
+var summaryTemplateNames = map[notificationv1.NotificationPreference_EventType]string{
+	notificationv1.NotificationPreference_EVENT_TYPE_GROUP_UPDATED:   "group_updated",
+	notificationv1.NotificationPreference_EVENT_TYPE_GROUP_DELETED:   "group_deleted",
+	notificationv1.NotificationPreference_EVENT_TYPE_PERSON_CREATED:  "person_created",
+	notificationv1.NotificationPreference_EVENT_TYPE_PERSON_DELETED:  "person_deleted",
+	notificationv1.NotificationPreference_EVENT_TYPE_EXPENSE_CREATED: "expense_created",
+	notificationv1.NotificationPreference_EVENT_TYPE_EXPENSE_UPDATED: "expense_updated",
+	notificationv1.NotificationPreference_EVENT_TYPE_EXPENSE_DELETED: "expense_deleted",
+	notificationv1.NotificationPreference_EVENT_TYPE_COMMENT_CREATED: "comment_created",
+	notificationv1.NotificationPreference_EVENT_TYPE_MENTIONED:       "mentioned",
+	notificationv1.NotificationPreference_EVENT_TYPE_DEBT_REMINDER:   "debt_reminder",
+}
+
+const (
+	notificationBodyTemplateName = "notification.body"
+	digestSubjectTemplateName    = "digest.subject"
+	digestBodyTemplateName       = "digest.body"
+)
+
+// templateData is passed to the templates of a notification
+type templateData struct {
+	// Actor is who caused the event or empty if it is unknown
+	Actor     string
+	GroupName string
+	// Name is the name of the person or expense the event is about
+	Name string
+	// Author is the name of the person who wrote the comment the event is about
+	Author string
+	// Text is the text of the comment the event is about
+	Text string
+	// Amount is the formatted amount of the debt the event is about
+	Amount string
+	// Currency is the acronym of the currency of the debt the event is about
+	Currency string
+	// Since is the formatted date the debt the event is about has been outstanding since
+	Since string
+	// Summary is the rendered summary of the event which is set before the body is rendered
+	Summary string
+}
+
+// digestData is passed to the templates of a digest
+type digestData struct {
+	Summaries []string
+}
+
+// templates holds the parsed templates of each supported locale
+type templates map[string]*template.Template
+
+// parseTemplates parses the embedded template file of each locale and makes sure it defines all templates
+func parseTemplates() (templates, error) {
+	names, err := fs.Glob(templateFiles, "templates/*.tmpl")
+	if err != nil {
+		return nil, eris.Wrap(err, "failed listing template files")
+	}
+	required := []string{notificationBodyTemplateName, digestSubjectTemplateName, digestBodyTemplateName}
+	for _, name := range summaryTemplateNames {
+		required = append(required, name)
+	}
+
+	t := make(templates, len(names))
+	for _, name := range names {
+		locale := strings.TrimSuffix(path.Base(name), path.Ext(name))
+		tmpl, err := template.New(locale).Option("missingkey=error").ParseFS(templateFiles, name)
+		if err != nil {
+			return nil, eris.Wrapf(err, "failed parsing templates of locale %s", locale)
+		}
+		for _, r := range required {
+			if tmpl.Lookup(r) == nil {
+				return nil, eris.Errorf("the templates of locale %s do not define %s", locale, r)
+			}
+		}
+		t[locale] = tmpl
+	}
+	if _, ok := t[defaultLocale]; !ok {
+		return nil, eris.Errorf("there are no templates of the default locale %s", defaultLocale)
+	}
+	return t, nil
+}
+
+// renderNotification returns the summary of the event, which is the subject of the email notifying about it and the line of the digest
+// listing it, as well as the body of the email
+func (t templates) renderNotification(locale string, eventType notificationv1.NotificationPreference_EventType, data templateData) (string, string, error) {
+	name, ok := summaryTemplateNames[eventType]
+	if !ok {
+		return "", "", eris.Errorf("there is no template for event type %s", eventType)
+	}
+	summary, err := t.execute(locale, name, data)
+	if err != nil {
+		return "", "", err
+	}
+	data.Summary = summary
+	body, err := t.execute(locale, notificationBodyTemplateName, data)
+	if err != nil {
+		return "", "", err
+	}
+	return summary, body, nil
+}
+
+// renderDigest returns the subject and body of the email listing the passed summaries
+func (t templates) renderDigest(locale string, summaries []string) (string, string, error) {
+	data := digestData{
+		Summaries: summaries,
+	}
+	subject, err := t.execute(locale, digestSubjectTemplateName, data)
+	if err != nil {
+		return "", "", err
+	}
+	body, err := t.execute(locale, digestBodyTemplateName, data)
+	if err != nil {
+		return "", "", err
+	}
+	return subject, body, nil
+}
+
+func (t templates) execute(locale string, name string, data interface{}) (string, error) {
+	tmpl, ok := t[locale]
+	if !ok {
+		tmpl = t[defaultLocale]
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", eris.Wrapf(err, "failed executing template %s", name)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -2,13 +2,16 @@ package person
 
 import (
 	"context"
-	"database/sql"
+	"time"
 
-	personv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/person/v1"
 	groupprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/group/v1"
 	personprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/person/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/uptrace/bun"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/proto"
@@ -18,31 +21,38 @@ func (rpProcessor *personProcessor) groupDeleted(ctx context.Context, req *group
 	log := logging.FromContext(ctx).With(logging.String("groupId", req.GetId()))
 	log.Info("processing group.GroupDeleted event")
 
-	return rpProcessor.dbClient.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		var people []*personv1.Person
-		if err := tx.NewDelete().Model(&people).Where("group_id = ?", req.GetId()).Returning("id").Scan(ctx); err != nil {
+	var people []*model.Person
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := util.UpdateMatchedReturning(ctx, tx, model.SoftDelete(tx.NewUpdate().Model(&people), req.GetDeleteCause(), time.Now()), func(q bun.QueryBuilder) bun.QueryBuilder {
+			return q.Where("group_id = ?", req.GetId())
+		}, "id"); err != nil {
 			log.Error("failed deleting people related to deleted group", logging.Error(err))
 			return errDeletePeople
 		}
+		return nil
+	}); err != nil {
+		return err
+	}
 
-		g, _ := errgroup.WithContext(ctx)
-		for _, c := range people {
-			person := c
-			g.Go(func() error {
-				marshalled, err := proto.Marshal(&personprocv1.PersonDeleted{
-					Id: person.Id,
-				})
-				if err != nil {
-					log.Error("failed marshalling person deleted event", logging.Error(err))
-					return errMarshalPersonDeleted
-				}
-				if err := rpProcessor.natsClient.Publish(environment.GetPersonDeletedSubject(req.GetId(), person.Id), marshalled); err != nil {
-					log.Error("failed publishing person deleted event", logging.Error(err))
-					return errPublishPersonDeleted
-				}
-				return nil
+	g, _ := errgroup.WithContext(ctx)
+	for _, c := range people {
+		person := c
+		g.Go(func() error {
+			marshalled, err := proto.Marshal(&personprocv1.PersonDeleted{
+				Id:          person.Id,
+				GroupId:     req.GetId(),
+				DeleteCause: req.GetDeleteCause(),
 			})
-		}
-		return g.Wait()
-	})
+			if err != nil {
+				log.Error("failed marshalling person deleted event", logging.Error(err))
+				return errMarshalPersonDeleted
+			}
+			if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetPersonDeletedSubject(req.GetId(), person.Id), marshalled); err != nil {
+				log.Error("failed publishing person deleted event", logging.Error(err))
+				return errPublishPersonDeleted
+			}
+			return nil
+		})
+	}
+	return g.Wait()
 }
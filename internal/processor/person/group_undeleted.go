@@ -0,0 +1,57 @@
+package person
+
+import (
+	"context"
+
+	groupprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/group/v1"
+	personprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/person/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/uptrace/bun"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
+)
+
+func (rpProcessor *personProcessor) groupUndeleted(ctx context.Context, req *groupprocv1.GroupUndeleted) error {
+	log := logging.FromContext(ctx).With(logging.String("groupId", req.GetId()))
+	log.Info("processing group.GroupUndeleted event")
+
+	var people []*model.Person
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := util.UpdateMatchedReturning(ctx, tx, model.Undelete(tx.NewUpdate().Model(&people)), func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereDeletedBecauseOf(q.Where("group_id = ?", req.GetId()), req.GetDeleteCause())
+		}, "id"); err != nil {
+			log.Error("failed restoring people related to restored group", logging.Error(err))
+			return errUndeletePeople
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	g, _ := errgroup.WithContext(ctx)
+	for _, c := range people {
+		person := c
+		g.Go(func() error {
+			marshalled, err := proto.Marshal(&personprocv1.PersonUndeleted{
+				Id:          person.Id,
+				GroupId:     req.GetId(),
+				DeleteCause: req.GetDeleteCause(),
+			})
+			if err != nil {
+				log.Error("failed marshalling person undeleted event", logging.Error(err))
+				return errMarshalPersonUndeleted
+			}
+			if err := mqClient.PublishEventData(ctx, rpProcessor.natsClient, environment.GetPersonUndeletedSubject(req.GetId(), person.Id), marshalled); err != nil {
+				log.Error("failed publishing person undeleted event", logging.Error(err))
+				return errPublishPersonUndeleted
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
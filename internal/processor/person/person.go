@@ -22,16 +22,28 @@ type personProcessor struct {
 var errDeletePeople = eris.New("failed deleting people")
 var errMarshalPersonDeleted = eris.New("could not marshal person deleted message")
 var errPublishPersonDeleted = eris.New("could not publish person deleted event")
+var errUndeletePeople = eris.New("failed restoring people")
+var errMarshalPersonUndeleted = eris.New("could not marshal person undeleted message")
+var errPublishPersonUndeleted = eris.New("could not publish person undeleted event")
 
 // NewPersonServer creates a new instance of person server.
-func NewPersonProcessor(natsUrl, dbUser, dbPass, dbAddr, db string) (*personProcessor, error) {
+func NewPersonProcessor(natsUrl string, dbConfig client.Config) (*personProcessor, error) {
+	db, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed creating database client")
+	}
+	return NewPersonProcessorWithDBClient(natsUrl, db)
+}
+
+// NewPersonProcessorWithDBClient creates a new instance of person processor using the passed database client.
+func NewPersonProcessorWithDBClient(natsUrl string, db bun.IDB) (*personProcessor, error) {
 	nc, err := nats.Connect(natsUrl)
 	if err != nil {
 		return nil, eris.Wrap(err, "failed connecting to NATS server")
 	}
 	return &personProcessor{
 		natsClient: nc,
-		dbClient:   client.NewPostgresDBClient(dbUser, dbPass, dbAddr, db),
+		dbClient:   db,
 	}, nil
 }
 
@@ -57,7 +69,7 @@ func (rpProcessor *personProcessor) Process(ctx context.Context) error {
 	{
 		eventSubject := environment.GetPersonCreatedSubject("*", "*")
 		var err error
-		pcCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, sourceStreamName, "EXPENSESPLITTER_CATEGORY_PROCESSOR_CATEGORY_CREATED", eventSubject, rpProcessor.personCreated)
+		pcCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, sourceStreamName, "EXPENSESPLITTER_PERSON_PROCESSOR_PERSON_CREATED", eventSubject, rpProcessor.personCreated)
 		if err != nil {
 			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
 		}
@@ -66,7 +78,7 @@ func (rpProcessor *personProcessor) Process(ctx context.Context) error {
 	{
 		eventSubject := environment.GetPersonDeletedSubject("*", "*")
 		var err error
-		pdCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, sourceStreamName, "EXPENSESPLITTER_CATEGORY_PROCESSOR_CATEGORY_DELETED", eventSubject, rpProcessor.personDeleted)
+		pdCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, sourceStreamName, "EXPENSESPLITTER_PERSON_PROCESSOR_PERSON_DELETED", eventSubject, rpProcessor.personDeleted)
 		if err != nil {
 			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
 		}
@@ -75,7 +87,7 @@ func (rpProcessor *personProcessor) Process(ctx context.Context) error {
 	{
 		eventSubject := environment.GetPersonUpdatedSubject("*", "*")
 		var err error
-		puCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, sourceStreamName, "EXPENSESPLITTER_CATEGORY_PROCESSOR_CATEGORY_UPDATED", eventSubject, rpProcessor.personUpdated)
+		puCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, sourceStreamName, "EXPENSESPLITTER_PERSON_PROCESSOR_PERSON_UPDATED", eventSubject, rpProcessor.personUpdated)
 		if err != nil {
 			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
 		}
@@ -84,7 +96,16 @@ func (rpProcessor *personProcessor) Process(ctx context.Context) error {
 	{
 		eventSubject := environment.GetGroupDeletedSubject("*")
 		var err error
-		gdCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, groupSourceStreamName, "EXPENSESPLITTER_CATEGORY_PROCESSOR_GROUP_DELETED", eventSubject, rpProcessor.groupDeleted)
+		gdCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, groupSourceStreamName, "EXPENSESPLITTER_PERSON_PROCESSOR_GROUP_DELETED", eventSubject, rpProcessor.groupDeleted)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+	var guCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetGroupUndeletedSubject("*")
+		var err error
+		guCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, groupSourceStreamName, "EXPENSESPLITTER_PERSON_PROCESSOR_GROUP_UNDELETED", eventSubject, rpProcessor.groupUndeleted)
 		if err != nil {
 			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
 		}
@@ -92,6 +113,6 @@ func (rpProcessor *personProcessor) Process(ctx context.Context) error {
 
 	<-ctx.Done()
 	log.Info("the context is done")
-	processor.UnsubscribeConsumeContexts(pcCCtx, pdCCtx, puCCtx, gdCCtx)
+	processor.UnsubscribeConsumeContexts(pcCCtx, pdCCtx, puCCtx, gdCCtx, guCCtx)
 	return nil
 }
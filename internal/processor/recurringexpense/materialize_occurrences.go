@@ -0,0 +1,246 @@
+package recurringexpense
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	currencyv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/currency/v1"
+	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expense/v1"
+	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensestake/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// materializeOccurrencesPeriodically materializes the due occurrences initially and then once per ticker period until the context is done
+func (rpProcessor *recurringExpenseProcessor) materializeOccurrencesPeriodically(ctx context.Context) {
+	log := logging.FromContext(ctx)
+
+	if err := rpProcessor.materializeOccurrences(ctx); err != nil {
+		log.Error("could not materialize occurrences initially", logging.Error(err))
+	} else {
+		log.Info("successfully materialized occurrences initially")
+	}
+
+	ticker := time.NewTicker(tickerPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := rpProcessor.materializeOccurrences(ctx); err != nil {
+				log.Error("could not materialize occurrences", logging.Error(err))
+			} else {
+				log.Debug("successfully materialized occurrences")
+			}
+		case <-ctx.Done():
+			log.Info("stopped materializing occurrences")
+			return
+		}
+	}
+}
+
+// materializeOccurrences publishes the events of occurrences whose publication was interrupted, turns the due occurrences
+// of all recurring expenses into expenses and drops the occurrences of deleted recurring expenses
+func (rpProcessor *recurringExpenseProcessor) materializeOccurrences(ctx context.Context) error {
+	ctx = principal.IntoContext(ctx, principal.System)
+	log := logging.FromContext(ctx)
+
+	if err := rpProcessor.publishPendingOccurrences(ctx); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var recurringExpenseIds []string
+	if err := rpProcessor.dbClient.NewSelect().Model((*model.RecurringExpense)(nil)).
+		Column("id").
+		Where("next_occurrence_time <= ?", now).
+		Order("next_occurrence_time ASC").
+		Scan(ctx, &recurringExpenseIds); err != nil {
+		log.Error("failed selecting due recurring expenses", logging.Error(err))
+		return errSelectRecurringExpenses
+	}
+	for _, recurringExpenseId := range recurringExpenseIds {
+		log := log.With(logging.String("recurringExpenseId", recurringExpenseId))
+		ctx := logging.IntoContext(ctx, log)
+
+		// a failing recurring expense must not keep the others from being materialized
+		if err := rpProcessor.materializeDueOccurrences(ctx, recurringExpenseId, now); err != nil {
+			log.Error("failed materializing occurrences of recurring expense", logging.Error(err))
+		}
+	}
+
+	// the occurrences of a deleted recurring expense are only needed until their events are published
+	if _, err := rpProcessor.dbClient.NewDelete().Model((*model.RecurringExpenseOccurrence)(nil)).
+		Where("published = ?", true).
+		Where("recurring_expense_id NOT IN (?)", rpProcessor.dbClient.NewSelect().Model((*model.RecurringExpense)(nil)).Column("id")).
+		Exec(ctx); err != nil {
+		log.Error("failed deleting occurrences of deleted recurring expenses", logging.Error(err))
+		return errDeleteOccurrences
+	}
+	return nil
+}
+
+// materializeDueOccurrences materializes the occurrences of the recurring expense up to the passed time one after another
+// so that the occurrences missed while no replica was leading are caught up on. A recurring expense that can no longer
+// be materialized since it references a resource that was deleted is paused.
+func (rpProcessor *recurringExpenseProcessor) materializeDueOccurrences(ctx context.Context, recurringExpenseId string, now time.Time) error {
+	log := logging.FromContext(ctx)
+
+	for {
+		occurrence, due, err := rpProcessor.materializeOccurrence(ctx, recurringExpenseId, now)
+		if err != nil {
+			if refErr := new(util.InvalidReferenceError); eris.As(err, refErr) {
+				log.Warn("pausing recurring expense referencing an invalid resource", logging.String("field", refErr.Field), logging.String("reason", refErr.Description()))
+				return rpProcessor.pauseRecurringExpense(ctx, recurringExpenseId)
+			}
+			return err
+		}
+		if !due {
+			return nil
+		}
+		if occurrence != nil {
+			if err := rpProcessor.publishOccurrence(ctx, occurrence); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// materializeOccurrence turns the next occurrence of the recurring expense into an expense if it is due at the passed time
+// and schedules the occurrence after it. It returns whether an occurrence was due and the recorded occurrence unless it was
+// materialized before, e.g. by a replica that lost its leadership before it could schedule the next occurrence.
+func (rpProcessor *recurringExpenseProcessor) materializeOccurrence(ctx context.Context, recurringExpenseId string, now time.Time) (*model.RecurringExpenseOccurrence, bool, error) {
+	log := logging.FromContext(ctx)
+
+	var occurrence *model.RecurringExpenseOccurrence
+	var due bool
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		recurringExpense, err := util.CheckResourceExists[*model.RecurringExpense](ctx, tx, recurringExpenseId)
+		if err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("recurring expense was deleted")
+				return nil
+			}
+			return err
+		}
+		if recurringExpense.NextOccurrenceTime == nil || recurringExpense.NextOccurrenceTime.After(now) {
+			return nil
+		}
+		occurrenceTime := *recurringExpense.NextOccurrenceTime
+		log := log.With(logging.Time("occurrenceTime", occurrenceTime))
+		ctx := logging.IntoContext(ctx, log)
+
+		recorded := &model.RecurringExpenseOccurrence{
+			RecurringExpenseId: recurringExpenseId,
+			OccurrenceTime:     occurrenceTime,
+			GroupId:            recurringExpense.GroupId,
+			ExpenseId:          util.GenerateIdWithPrefix("expense"),
+		}
+		res, err := tx.NewInsert().Model(recorded).On("CONFLICT DO NOTHING").Exec(ctx)
+		if err != nil {
+			log.Error("failed recording occurrence", logging.Error(err))
+			return errMaterializeOccurrence
+		}
+		if inserted, err := res.RowsAffected(); err != nil {
+			log.Error("failed recording occurrence", logging.Error(err))
+			return errMaterializeOccurrence
+		} else if inserted == 0 {
+			log.Info("occurrence was materialized before")
+		} else {
+			if err := insertExpense(ctx, tx, recurringExpense, recorded); err != nil {
+				return err
+			}
+			occurrence = recorded
+		}
+
+		recurringExpense.ScheduleAfter(occurrenceTime)
+		// the schedule must not have changed since it was read so that no pause, resume or end time is overwritten
+		res, err = tx.NewUpdate().Model(recurringExpense).
+			Column("next_occurrence_time").
+			WherePK().
+			Where("revision = ?", recurringExpense.Revision.Revision).
+			Exec(ctx)
+		if err != nil {
+			log.Error("failed scheduling next occurrence", logging.Error(err))
+			return errMaterializeOccurrence
+		}
+		if updated, err := res.RowsAffected(); err != nil {
+			log.Error("failed scheduling next occurrence", logging.Error(err))
+			return errMaterializeOccurrence
+		} else if updated == 0 {
+			log.Info("recurring expense was modified concurrently")
+			return errConcurrentModification
+		}
+		due = true
+		return nil
+	}); err != nil {
+		return nil, false, err
+	}
+	return occurrence, due, nil
+}
+
+// insertExpense inserts the expense of the occurrence with the stakes of the recurring expense and records its first revision
+func insertExpense(ctx context.Context, tx bun.Tx, recurringExpense *model.RecurringExpense, occurrence *model.RecurringExpenseOccurrence) error {
+	log := logging.FromContext(ctx)
+
+	if _, err := util.CheckReference[*model.Group](ctx, tx, "group_id", recurringExpense.GroupId); err != nil {
+		return err
+	}
+	if _, err := util.CheckGroupScopedReference[*model.Person](ctx, tx, "by_id", recurringExpense.ById, recurringExpense.GroupId); err != nil {
+		return err
+	}
+	if _, err := util.CheckReference[*currencyv1.Currency](ctx, tx, "currency_id", recurringExpense.CurrencyId); err != nil {
+		return err
+	}
+	protoRecurringExpense, err := recurringExpense.IntoProtoRecurringExpense()
+	if err != nil {
+		log.Error("failed converting recurring expense", logging.Error(err))
+		return errMaterializeOccurrence
+	}
+	for i, stake := range protoRecurringExpense.GetStakes() {
+		if _, err := util.CheckGroupScopedReference[*model.Person](ctx, tx, fmt.Sprintf("stakes[%d].for_id", i), stake.GetForId(), recurringExpense.GroupId); err != nil {
+			return err
+		}
+	}
+
+	metadata := model.NewCreatedMetadata(ctx, time.Now())
+	if _, err := tx.NewInsert().Model(
+		model.NewExpense(&expensev1.Expense{
+			Id:         occurrence.ExpenseId,
+			GroupId:    recurringExpense.GroupId,
+			Name:       recurringExpense.Name,
+			ById:       recurringExpense.ById,
+			Timestamp:  timestamppb.New(occurrence.OccurrenceTime),
+			CurrencyId: recurringExpense.CurrencyId,
+		}, metadata),
+	).Exec(ctx); err != nil {
+		log.Error("failed inserting expense", logging.Error(err))
+		return errMaterializeOccurrence
+	}
+	for _, stake := range protoRecurringExpense.GetStakes() {
+		if _, err := tx.NewInsert().Model(
+			model.NewExpenseStake(&expensestakev1.ExpenseStake{
+				Id:              util.GenerateIdWithPrefix("expensestake"),
+				ExpenseId:       occurrence.ExpenseId,
+				ForId:           stake.GetForId(),
+				MainValue:       stake.GetMainValue(),
+				FractionalValue: stake.FractionalValue,
+			}, metadata),
+		).Exec(ctx); err != nil {
+			log.Error("failed inserting expense stake", logging.Error(err))
+			return errMaterializeOccurrence
+		}
+	}
+	if _, err := model.RecordExpenseRevision(ctx, tx, occurrence.ExpenseId); err != nil {
+		log.Error("failed recording expense revision", logging.Error(err))
+		return errMaterializeOccurrence
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package recurringexpense
+
+import (
+	"context"
+	"time"
+
+	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expense/v1"
+	recurringexpensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/recurringexpense/v1"
+	expenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expense/v1"
+	expensestakeprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expensestake/v1"
+	recurringexpenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/recurringexpense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/proto"
+)
+
+// publishPendingOccurrences publishes the events of the occurrences which were materialized without their events being published,
+// e.g. because the replica materializing them stopped in between
+func (rpProcessor *recurringExpenseProcessor) publishPendingOccurrences(ctx context.Context) error {
+	log := logging.FromContext(ctx)
+
+	var occurrences []*model.RecurringExpenseOccurrence
+	if err := rpProcessor.dbClient.NewSelect().Model(&occurrences).
+		Where("published = ?", false).
+		Order("occurrence_time ASC").
+		Scan(ctx); err != nil {
+		log.Error("failed selecting unpublished occurrences", logging.Error(err))
+		return errSelectOccurrences
+	}
+	for _, occurrence := range occurrences {
+		if err := rpProcessor.publishOccurrence(ctx, occurrence); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishOccurrence publishes the events about the expense created for the occurrence and its stakes and marks the occurrence as published.
+// The events are published at least once since a replica may stop after publishing them but before marking the occurrence.
+func (rpProcessor *recurringExpenseProcessor) publishOccurrence(ctx context.Context, occurrence *model.RecurringExpenseOccurrence) error {
+	log := logging.FromContext(ctx).With(
+		logging.String("recurringExpenseId", occurrence.RecurringExpenseId),
+		logging.String("expenseId", occurrence.ExpenseId))
+
+	// the occurrences are materialized by the system which is why it requests the creation of the expenses
+	requestorEmail := principal.FromContext(ctx)
+
+	// the expense may have been deleted or purged since it was created, in which case the events about its creation are no longer of interest
+	expense, err := util.CheckResourceExistsWithDeleted[*model.Expense](ctx, rpProcessor.dbClient, occurrence.ExpenseId)
+	if err != nil && !eris.As(err, &util.ResourceNotFoundError{}) {
+		log.Error("failed getting expense of occurrence", logging.Error(err))
+		return errSelectExpense
+	}
+	if err == nil {
+		// the events carry the state the expense and its stakes were created in which is their first revision
+		// rather than their current state since they may have changed since then
+		revision := &model.ExpenseRevision{
+			ExpenseRevision: expensev1.ExpenseRevision{
+				ExpenseId: expense.Id,
+				Revision:  1,
+			},
+		}
+		if err := rpProcessor.dbClient.NewSelect().Model(revision).WherePK().Scan(ctx); err != nil {
+			log.Error("failed getting first revision of expense of occurrence", logging.Error(err))
+			return errSelectExpense
+		}
+		created, err := revision.IntoProtoExpenseRevision()
+		if err != nil {
+			log.Error("failed converting first revision of expense of occurrence", logging.Error(err))
+			return errSelectExpense
+		}
+
+		marshalled, err := proto.Marshal(&expenseprocv1.ExpenseCreated{
+			Id:             created.GetExpense().GetId(),
+			GroupId:        created.GetExpense().GetGroupId(),
+			Name:           created.GetExpense().Name,
+			ById:           created.GetExpense().GetById(),
+			Timestamp:      created.GetExpense().GetTimestamp(),
+			CurrencyId:     created.GetExpense().GetCurrencyId(),
+			RequestorEmail: requestorEmail,
+		})
+		if err != nil {
+			log.Error("failed marshalling expense created event", logging.Error(err))
+			return errMarshalExpenseCreated
+		}
+		if err := mqClient.PublishResourceEventData(ctx, rpProcessor.natsClient, environment.GetExpenseCreatedSubject(expense.GroupId, expense.Id), marshalled, created.GetExpense()); err != nil {
+			log.Error("failed publishing expense created event", logging.Error(err))
+			return errPublishExpenseCreated
+		}
+		for _, stake := range created.GetStakes() {
+			marshalled, err := proto.Marshal(&expensestakeprocv1.ExpenseStakeCreated{
+				Id:              stake.GetId(),
+				ExpenseId:       stake.GetExpenseId(),
+				ForId:           stake.GetForId(),
+				MainValue:       stake.GetMainValue(),
+				FractionalValue: stake.FractionalValue,
+				RequestorEmail:  requestorEmail,
+			})
+			if err != nil {
+				log.Error("failed marshalling expense stake created event", logging.Error(err))
+				return errMarshalExpenseStakeCreated
+			}
+			if err := mqClient.PublishResourceEventData(ctx, rpProcessor.natsClient, environment.GetExpenseStakeCreatedSubject(expense.GroupId, expense.Id, stake.GetId()), marshalled, stake); err != nil {
+				log.Error("failed publishing expense stake created event", logging.Error(err))
+				return errPublishExpenseStakeCreated
+			}
+		}
+	} else {
+		log.Info("expense of occurrence no longer exists")
+	}
+
+	occurrence.Published = true
+	if _, err := rpProcessor.dbClient.NewUpdate().Model(occurrence).Column("published").WherePK().Exec(ctx); err != nil {
+		log.Error("failed marking occurrence as published", logging.Error(err))
+		return errMarkOccurrencePublished
+	}
+	return nil
+}
+
+// pauseRecurringExpense pauses the recurring expense with the passed ID and publishes a recurring expense updated event
+func (rpProcessor *recurringExpenseProcessor) pauseRecurringExpense(ctx context.Context, recurringExpenseId string) error {
+	log := logging.FromContext(ctx)
+
+	recurringExpense := &model.RecurringExpense{
+		RecurringExpense: recurringexpensev1.RecurringExpense{
+			Id:     recurringExpenseId,
+			Paused: true,
+		},
+		Metadata: model.NewModifiedMetadata(ctx, time.Now()),
+	}
+	var protoRecurringExpense *recurringexpensev1.RecurringExpense
+	if err := transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		query := model.IncrementRevision(tx.NewUpdate().Model(recurringExpense).Column("paused", "next_occurrence_time").Column(model.MetadataUpdateColumns...))
+		if err := util.UpdateReturning(ctx, tx, query, util.WherePK, "group_id"); err != nil {
+			log.Error("failed pausing recurring expense", logging.Error(err))
+			return errPauseRecurringExpense
+		}
+		// the event carries the whole recurring expense in the state it was paused in
+		paused, err := util.CheckResourceExists[*model.RecurringExpense](ctx, tx, recurringExpenseId)
+		if err != nil {
+			log.Error("failed getting paused recurring expense", logging.Error(err))
+			return errPauseRecurringExpense
+		}
+		if protoRecurringExpense, err = paused.IntoProtoRecurringExpense(); err != nil {
+			log.Error("failed converting paused recurring expense", logging.Error(err))
+			return errPauseRecurringExpense
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	marshalled, err := proto.Marshal(&recurringexpenseprocv1.RecurringExpenseUpdated{
+		Id:      recurringExpenseId,
+		GroupId: recurringExpense.GroupId,
+	})
+	if err != nil {
+		log.Error("failed marshalling recurring expense updated event", logging.Error(err))
+		return errMarshalRecurringExpenseUpdated
+	}
+	if err := mqClient.PublishResourceEventData(ctx, rpProcessor.natsClient, environment.GetRecurringExpenseUpdatedSubject(recurringExpense.GroupId, recurringExpenseId), marshalled, protoRecurringExpense); err != nil {
+		log.Error("failed publishing recurring expense updated event", logging.Error(err))
+		return errPublishRecurringExpenseUpdated
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package recurringexpense
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/election"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/processor"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+type recurringExpenseProcessor struct {
+	natsClient *nats.Conn
+	dbClient   bun.IDB
+}
+
+const tickerPeriod = time.Minute
+const leaseDuration = 15 * time.Second
+const leaderElectionKey = "recurring-expense-scheduler"
+
+var errSelectRecurringExpenses = eris.New("failed selecting due recurring expenses")
+var errSelectOccurrences = eris.New("failed selecting unpublished occurrences")
+var errDeleteOccurrences = eris.New("failed deleting occurrences of deleted recurring expenses")
+var errMaterializeOccurrence = eris.New("failed materializing occurrence")
+var errConcurrentModification = eris.New("the recurring expense was modified concurrently")
+var errPauseRecurringExpense = eris.New("failed pausing recurring expense")
+var errMarshalRecurringExpenseUpdated = eris.New("could not marshal recurring expense updated message")
+var errPublishRecurringExpenseUpdated = eris.New("could not publish recurring expense updated event")
+var errSelectExpense = eris.New("failed selecting materialized expense")
+var errMarshalExpenseCreated = eris.New("could not marshal expense created message")
+var errPublishExpenseCreated = eris.New("could not publish expense created event")
+var errMarshalExpenseStakeCreated = eris.New("could not marshal expense stake created message")
+var errPublishExpenseStakeCreated = eris.New("could not publish expense stake created event")
+var errMarkOccurrencePublished = eris.New("failed marking occurrence as published")
+
+// NewRecurringExpenseProcessor creates a new instance of recurring expense processor.
+func NewRecurringExpenseProcessor(natsUrl string, dbConfig client.Config) (*recurringExpenseProcessor, error) {
+	db, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed creating database client")
+	}
+	return NewRecurringExpenseProcessorWithDBClient(natsUrl, db)
+}
+
+// NewRecurringExpenseProcessorWithDBClient creates a new instance of recurring expense processor using the passed database client.
+func NewRecurringExpenseProcessorWithDBClient(natsUrl string, db bun.IDB) (*recurringExpenseProcessor, error) {
+	nc, err := nats.Connect(natsUrl)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed connecting to NATS server")
+	}
+	return &recurringExpenseProcessor{
+		natsClient: nc,
+		dbClient:   db,
+	}, nil
+}
+
+// Process starts materializing the occurrences of recurring expenses once this replica leads and returns when the context is done
+func (rpProcessor *recurringExpenseProcessor) Process(ctx context.Context) error {
+	log := logging.FromContext(ctx).Named("Process")
+	ctx = logging.IntoContext(ctx, log)
+
+	_, err := processor.CreateOrUpdateSourceStream(
+		ctx,
+		rpProcessor.natsClient,
+		environment.GetRecurringExpenseSourceStreamName(),
+		fmt.Sprintf("%s.*", environment.GetRecurringExpenseSubject("*", "*")),
+	)
+	if err != nil {
+		return err
+	}
+
+	leaderElection, err := election.NewLeaderElection(
+		ctx,
+		rpProcessor.natsClient,
+		environment.GetLeaderElectionBucketName(),
+		leaderElectionKey,
+		leaseDuration)
+	if err != nil {
+		return eris.Wrap(err, "failed creating leader election for scheduling recurring expenses")
+	}
+	// only the leading replica materializes occurrences so that replicas do not race each other;
+	// the recorded occurrences keep a replica taking over from materializing an occurrence twice
+	leaderElection.Run(ctx, rpProcessor.materializeOccurrencesPeriodically)
+	return nil
+}
@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	activityv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/activity/v1"
+	webhookv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/webhook/v1"
+	activityprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/activity/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// payload is the JSON body of a delivery
+type payload struct {
+	// Id is the ID of the delivery which receivers can deduplicate deliveries by
+	Id       string          `json:"id"`
+	Event    string          `json:"event"`
+	GroupId  string          `json:"groupId"`
+	Activity json.RawMessage `json:"activity"`
+}
+
+// activityRecorded queues the recorded activity for delivery to every enabled webhook of its group whose filter lets it through
+func (rpProcessor *webhookProcessor) activityRecorded(ctx context.Context, req *activityprocv1.ActivityRecorded) error {
+	activity := req.GetActivity()
+	log := logging.FromContext(ctx).With(
+		logging.String("groupId", activity.GetGroupId()),
+		logging.String("activityId", activity.GetId()))
+	log.Info("processing activity.ActivityRecorded event")
+
+	var webhooks []*model.Webhook
+	if err := rpProcessor.dbClient.NewSelect().Model(&webhooks).
+		Where("group_id = ?", activity.GetGroupId()).
+		Where("disabled = ?", false).
+		Scan(ctx); err != nil {
+		log.Error("failed selecting webhooks of group", logging.Error(err))
+		return errSelectWebhooks
+	}
+
+	event := eventName(activity)
+	marshalledActivity, err := protojson.Marshal(activity)
+	if err != nil {
+		log.Error("failed marshalling activity", logging.Error(err))
+		return eris.Wrap(err, "failed marshalling activity")
+	}
+	now := time.Now()
+	var deliveries []*model.WebhookDelivery
+	for _, webhook := range webhooks {
+		if !webhook.Matches(activity) {
+			continue
+		}
+		id := util.GenerateIdWithPrefix("webhookdelivery")
+		body, err := json.Marshal(payload{
+			Id:       id,
+			Event:    event,
+			GroupId:  activity.GetGroupId(),
+			Activity: marshalledActivity,
+		})
+		if err != nil {
+			log.Error("failed marshalling payload", logging.Error(err))
+			return eris.Wrap(err, "failed marshalling payload")
+		}
+		deliveries = append(deliveries, &model.WebhookDelivery{
+			WebhookDelivery: webhookv1.WebhookDelivery{
+				Id:         id,
+				WebhookId:  webhook.GetId(),
+				GroupId:    activity.GetGroupId(),
+				ActivityId: activity.GetId(),
+				Event:      event,
+				Payload:    string(body),
+				Status:     webhookv1.WebhookDelivery_STATUS_PENDING,
+			},
+			CreateTime:      now,
+			NextAttemptTime: &now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	// a redelivered event must not queue the activity twice
+	if _, err := rpProcessor.dbClient.NewInsert().Model(&deliveries).
+		On("CONFLICT (webhook_id, activity_id) DO NOTHING").
+		Exec(ctx); err != nil {
+		log.Error("failed inserting webhook deliveries", logging.Error(err))
+		return errInsertDeliveries
+	}
+	return nil
+}
+
+// eventName returns the name of the event an activity is delivered as, e.g. expense_stake.created
+func eventName(activity *activityv1.Activity) string {
+	resourceType := strings.TrimPrefix(activity.GetResourceType().String(), "RESOURCE_TYPE_")
+	action := strings.TrimPrefix(activity.GetAction().String(), "ACTION_")
+	return strings.ToLower(resourceType) + "." + strings.ToLower(action)
+}
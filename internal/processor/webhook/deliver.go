@@ -0,0 +1,220 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	webhookv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/webhook/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/webhook"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+// maxErrorLength is the maximum length of the error of a failed attempt kept in the delivery log
+const maxErrorLength = 512
+
+// disabledDeliveryError is the error of the pending deliveries of a webhook given up on since the webhook is disabled
+const disabledDeliveryError = "the webhook is disabled"
+
+// deliverPeriodically attempts the due deliveries initially and then once per ticker period until the context is done
+func (rpProcessor *webhookProcessor) deliverPeriodically(ctx context.Context) {
+	log := logging.FromContext(ctx)
+
+	if err := rpProcessor.deliverDue(ctx); err != nil {
+		log.Error("could not attempt due webhook deliveries initially", logging.Error(err))
+	} else {
+		log.Info("successfully attempted due webhook deliveries initially")
+	}
+
+	ticker := time.NewTicker(tickerPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := rpProcessor.deliverDue(ctx); err != nil {
+				log.Error("could not attempt due webhook deliveries", logging.Error(err))
+			} else {
+				log.Debug("successfully attempted due webhook deliveries")
+			}
+		case <-ctx.Done():
+			log.Info("stopped attempting webhook deliveries")
+			return
+		}
+	}
+}
+
+// deliverDue attempts the pending deliveries whose next attempt is due. The deliveries of a webhook are attempted one after
+// another in the order they were queued while the webhooks are delivered to concurrently so that a slow receiver does not
+// hold up the others.
+func (rpProcessor *webhookProcessor) deliverDue(ctx context.Context) error {
+	log := logging.FromContext(ctx)
+	// changes like disabling a webhook are not caused by a request
+	ctx = principal.IntoContext(ctx, principal.System)
+
+	var deliveries []*model.WebhookDelivery
+	if err := rpProcessor.dbClient.NewSelect().Model(&deliveries).
+		Where("status = ?", webhookv1.WebhookDelivery_STATUS_PENDING).
+		Where("next_attempt_time <= ?", time.Now()).
+		Order("next_attempt_time ASC", "id ASC").
+		Limit(deliveryBatchSize).
+		Scan(ctx); err != nil {
+		log.Error("failed selecting due webhook deliveries", logging.Error(err))
+		return errSelectDeliveries
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	deliveriesByWebhook := make(map[string][]*model.WebhookDelivery)
+	webhookIds := make([]string, 0)
+	for _, delivery := range deliveries {
+		if _, ok := deliveriesByWebhook[delivery.GetWebhookId()]; !ok {
+			webhookIds = append(webhookIds, delivery.GetWebhookId())
+		}
+		deliveriesByWebhook[delivery.GetWebhookId()] = append(deliveriesByWebhook[delivery.GetWebhookId()], delivery)
+	}
+	var webhooks []*model.Webhook
+	if err := rpProcessor.dbClient.NewSelect().Model(&webhooks).
+		Where("id IN (?)", bun.In(webhookIds)).
+		Scan(ctx); err != nil {
+		log.Error("failed selecting webhooks of due deliveries", logging.Error(err))
+		return errSelectWebhooks
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var failed bool
+	for _, w := range webhooks {
+		w := w
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := rpProcessor.deliverToWebhook(ctx, w, deliveriesByWebhook[w.GetId()]); err != nil {
+				log.Error("failed attempting deliveries of webhook", logging.String("webhookId", w.GetId()), logging.Error(err))
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	// the deliveries of deleted webhooks are deleted along with their webhook so that there are none left to handle here
+	if failed {
+		return errUpdateDelivery
+	}
+	return nil
+}
+
+// deliverToWebhook attempts the passed deliveries of the webhook one after another and records their outcomes. Once the
+// webhook is disabled, either before or because of the attempts, its remaining pending deliveries are given up on.
+func (rpProcessor *webhookProcessor) deliverToWebhook(ctx context.Context, w *model.Webhook, deliveries []*model.WebhookDelivery) error {
+	log := logging.FromContext(ctx).With(logging.String("webhookId", w.GetId()))
+
+	for _, delivery := range deliveries {
+		if w.GetDisabled() {
+			if err := failPendingDeliveries(ctx, rpProcessor.dbClient, w.GetId()); err != nil {
+				log.Error("failed giving up on pending deliveries of disabled webhook", logging.Error(err))
+				return errUpdateDelivery
+			}
+			return nil
+		}
+		statusCode, err := rpProcessor.webhookClient.Deliver(ctx, w.GetUrl(), w.Secret, webhook.Delivery{
+			Id:    delivery.GetId(),
+			Event: delivery.GetEvent(),
+			Body:  []byte(delivery.GetPayload()),
+		})
+		now := time.Now()
+		delivery.Attempts++
+		delivery.LastAttemptTime = &now
+		delivery.ResponseStatus = int32(statusCode)
+		if err == nil {
+			delivery.Status = webhookv1.WebhookDelivery_STATUS_SUCCEEDED
+			delivery.Error = ""
+			delivery.NextAttemptTime = nil
+			w.ConsecutiveFailures = 0
+		} else {
+			log.Info("webhook delivery failed", logging.String("deliveryId", delivery.GetId()), logging.Error(err))
+			delivery.Error = truncate(err.Error(), maxErrorLength)
+			if delivery.GetAttempts() >= maxAttempts {
+				delivery.Status = webhookv1.WebhookDelivery_STATUS_FAILED
+				delivery.NextAttemptTime = nil
+			} else {
+				next := now.Add(webhook.Backoff(int(delivery.GetAttempts())))
+				delivery.NextAttemptTime = &next
+			}
+			w.ConsecutiveFailures++
+		}
+		if err := rpProcessor.recordAttempt(ctx, w, delivery); err != nil {
+			log.Error("failed recording webhook delivery attempt", logging.String("deliveryId", delivery.GetId()), logging.Error(err))
+			return errUpdateDelivery
+		}
+	}
+	return nil
+}
+
+// recordAttempt stores the outcome of an attempt along with the consecutive failures of the webhook. Once they reach the
+// maximum, the webhook is disabled and its pending deliveries, including the attempted one, are given up on.
+func (rpProcessor *webhookProcessor) recordAttempt(ctx context.Context, w *model.Webhook, delivery *model.WebhookDelivery) error {
+	return transaction.RunInTx(ctx, rpProcessor.dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewUpdate().Model(delivery).
+			Column("status", "attempts", "response_status", "error", "last_attempt_time", "next_attempt_time").
+			WherePK().
+			Exec(ctx); err != nil {
+			return eris.Wrap(err, "failed updating webhook delivery")
+		}
+		if w.GetConsecutiveFailures() < maxConsecutiveFailures {
+			if _, err := tx.NewUpdate().Model(w).
+				Column("consecutive_failures").
+				WherePK().
+				Exec(ctx); err != nil {
+				return eris.Wrap(err, "failed updating consecutive failures of webhook")
+			}
+			return nil
+		}
+
+		w.Disabled = true
+		w.DisabledReason = "disabled after repeatedly failing deliveries"
+		w.Metadata = model.NewModifiedMetadata(ctx, time.Now())
+		if _, err := model.IncrementRevision(tx.NewUpdate().Model(w).
+			Column("consecutive_failures", "disabled", "disabled_reason").
+			Column(model.MetadataUpdateColumns...)).
+			WherePK().
+			Exec(ctx); err != nil {
+			return eris.Wrap(err, "failed disabling webhook")
+		}
+		logging.FromContext(ctx).Warn("disabled webhook after repeatedly failing deliveries", logging.String("webhookId", w.GetId()))
+		return failPendingDeliveries(ctx, tx, w.GetId())
+	})
+}
+
+// failPendingDeliveries gives up on the pending deliveries of a disabled webhook
+func failPendingDeliveries(ctx context.Context, db bun.IDB, webhookId string) error {
+	if _, err := db.NewUpdate().Model((*model.WebhookDelivery)(nil)).
+		Set("status = ?", webhookv1.WebhookDelivery_STATUS_FAILED).
+		Set("error = ?", disabledDeliveryError).
+		Set("next_attempt_time = NULL").
+		Where("webhook_id = ?", webhookId).
+		Where("status = ?", webhookv1.WebhookDelivery_STATUS_PENDING).
+		Exec(ctx); err != nil {
+		return eris.Wrap(err, "failed giving up on pending deliveries of disabled webhook")
+	}
+	return nil
+}
+
+// truncate shortens the passed string to at most the passed number of bytes without splitting a character
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
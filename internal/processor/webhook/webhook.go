@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/election"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/processor"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/webhook"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+type webhookProcessor struct {
+	natsClient    *nats.Conn
+	dbClient      bun.IDB
+	webhookClient *webhook.Client
+}
+
+const tickerPeriod = 5 * time.Second
+const leaseDuration = 15 * time.Second
+const leaderElectionKey = "webhook-delivery"
+
+// deliveryTimeout is how long a receiver may take to respond to a delivery
+const deliveryTimeout = 10 * time.Second
+
+// deliveryBatchSize is the maximum number of due deliveries attempted per tick
+const deliveryBatchSize = 100
+
+// maxAttempts is the number of attempts after which a delivery is given up on
+const maxAttempts = 8
+
+// maxConsecutiveFailures is the number of attempts failing in a row after which a webhook is disabled
+const maxConsecutiveFailures = 15
+
+var errSelectWebhooks = eris.New("failed selecting webhooks")
+var errInsertDeliveries = eris.New("failed inserting webhook deliveries")
+var errSelectDeliveries = eris.New("failed selecting due webhook deliveries")
+var errUpdateDelivery = eris.New("failed updating webhook delivery")
+
+// NewWebhookProcessor creates a new instance of webhook processor.
+func NewWebhookProcessor(natsUrl string, dbConfig client.Config) (*webhookProcessor, error) {
+	db, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed creating database client")
+	}
+	return NewWebhookProcessorWithDBClient(natsUrl, db)
+}
+
+// NewWebhookProcessorWithDBClient creates a new instance of webhook processor using the passed database client.
+func NewWebhookProcessorWithDBClient(natsUrl string, db bun.IDB) (*webhookProcessor, error) {
+	nc, err := nats.Connect(natsUrl)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed connecting to NATS server")
+	}
+	return &webhookProcessor{
+		natsClient:    nc,
+		dbClient:      db,
+		webhookClient: webhook.NewClient(webhook.Config{Timeout: deliveryTimeout}),
+	}, nil
+}
+
+// Process queues the recorded activities for delivery to the webhooks of their groups and delivers the queued activities
+// once this replica leads until the context is done
+func (rpProcessor *webhookProcessor) Process(ctx context.Context) error {
+	log := logging.FromContext(ctx).Named("Process")
+	ctx = logging.IntoContext(ctx, log)
+
+	sourceStreamName := environment.GetActivitySourceStreamName()
+
+	// the activity source stream is owned by the activity processor and created with the same subject here in case this processor starts first
+	if _, err := processor.CreateOrUpdateSourceStream(
+		ctx,
+		rpProcessor.natsClient,
+		sourceStreamName,
+		fmt.Sprintf("%s.*", environment.GetActivitySubject("*", "*")),
+	); err != nil {
+		return err
+	}
+
+	var arCCtx jetstream.ConsumeContext
+	{
+		eventSubject := environment.GetActivityRecordedSubject("*", "*")
+		var err error
+		arCCtx, err = processor.GetStreamProcessor(ctx, rpProcessor.natsClient, sourceStreamName, "EXPENSESPLITTER_WEBHOOK_PROCESSOR_ACTIVITY_RECORDED", eventSubject, rpProcessor.activityRecorded)
+		if err != nil {
+			return eris.Wrapf(err, "an error occurred processing subject %s", eventSubject)
+		}
+	}
+
+	leaderElection, err := election.NewLeaderElection(
+		ctx,
+		rpProcessor.natsClient,
+		environment.GetLeaderElectionBucketName(),
+		leaderElectionKey,
+		leaseDuration)
+	if err != nil {
+		processor.UnsubscribeConsumeContexts(arCCtx)
+		return eris.Wrap(err, "failed creating leader election for webhook deliveries")
+	}
+	// only the leading replica delivers so that receivers do not get every delivery from every replica
+	leaderElection.Run(ctx, rpProcessor.deliverPeriodically)
+
+	processor.UnsubscribeConsumeContexts(arCCtx)
+	return nil
+}
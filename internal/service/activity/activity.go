@@ -0,0 +1,65 @@
+package activity
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/activity/v1/activityv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+var _ activityv1connect.ActivityServiceHandler = (*activityServer)(nil)
+
+var errSelectActivities = eris.New("failed selecting activities")
+var errConvertActivity = eris.New("failed converting activity")
+
+// defaultPageSize is the number of activities listed if the request does not specify a page size
+const defaultPageSize = 20
+
+type activityServer struct {
+	// dbReads is used by all endpoints since the activities are only written by the activity processor
+	dbReads    *client.ReadRouter
+	natsClient *nats.EncodedConn
+}
+
+// NewActivityServer creates a new instance of activity server. The context has no effect on the server's lifecycle.
+func NewActivityServer(ctx context.Context, natsServer string, dbConfig client.Config) (*activityServer, error) {
+	log := logging.FromContext(ctx).Named("NewActivityServer")
+	ctx = logging.IntoContext(ctx, log)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewActivityServerWithDBClient(ctx, dbClient, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
+}
+
+// NewActivityServerWithDBClient creates a new instance of activity server. The context has no effect on the server's lifecycle.
+func NewActivityServerWithDBClient(ctx context.Context, dbClient bun.IDB, natsServer string) (*activityServer, error) {
+	log := logging.FromContext(ctx).Named("NewActivityServerWithDBClient")
+	nc, err := mqClient.NewProtoMQClient(natsServer)
+	if err != nil {
+		msg := "failed connecting to NATS server"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	return &activityServer{
+		dbReads:    client.NewReadRouter(dbClient),
+		natsClient: nc,
+	}, nil
+}
+
+func (rps *activityServer) Close() error {
+	rps.natsClient.Close()
+	return rps.dbReads.Close()
+}
@@ -0,0 +1,91 @@
+package activity
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	activityv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/activity/v1"
+	activitysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/activity/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *activityServer) ListGroupActivity(ctx context.Context, req *connect.Request[activitysvcv1.ListGroupActivityRequest]) (*connect.Response[activitysvcv1.ListGroupActivityResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"groupId",
+				req.Msg.GetGroupId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	pageSize := int(req.Msg.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	activities, nextPageToken, err := listGroupActivities(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetGroupId(), pageSize, req.Msg.GetPageToken())
+	if err != nil {
+		if eris.Is(err, errSelectActivities) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&activitysvcv1.ListGroupActivityResponse{
+		Activities:    activities,
+		NextPageToken: nextPageToken,
+	}), nil
+}
+
+// listGroupActivities returns a page of the activities of a group starting with the most recent one. The page token is the ID
+// of the last activity of the previous page, which is stable since activities are never modified and their IDs sort by creation.
+func listGroupActivities(ctx context.Context, dbClient bun.IDB, groupId string, pageSize int, pageToken string) ([]*activityv1.Activity, string, error) {
+	log := logging.FromContext(ctx)
+
+	var activities []*model.Activity
+	query := dbClient.NewSelect().Model(&activities).
+		ExcludeColumn("snapshot_json").
+		Where("group_id = ?", groupId)
+	if pageToken != "" {
+		query = query.Where("id < ?", pageToken)
+	}
+	// one more activity than requested is selected to tell whether there is a next page
+	if err := query.Order("id DESC").Limit(pageSize + 1).Scan(ctx); err != nil {
+		log.Error("failed getting activities", logging.Error(err))
+		return nil, "", errSelectActivities
+	}
+
+	var nextPageToken string
+	if len(activities) > pageSize {
+		activities = activities[:pageSize]
+		nextPageToken = activities[pageSize-1].Id
+	}
+	protoActivities := make([]*activityv1.Activity, len(activities))
+	for i, a := range activities {
+		protoActivity, err := a.IntoProtoActivity()
+		if err != nil {
+			log.Error("failed converting activity", logging.Error(err), logging.String("activityId", a.Id))
+			return nil, "", errConvertActivity
+		}
+		protoActivities[i] = protoActivity
+	}
+	return protoActivities, nextPageToken, nil
+}
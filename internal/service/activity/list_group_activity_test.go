@@ -0,0 +1,124 @@
+package activity_test // the dedicated _test package prevents import cycles with the testing package
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/DATA-DOG/go-sqlmock"
+	activityv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/activity/v1"
+	activitysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/activity/v1"
+	activityTesting "github.com/nico151999/high-availability-expense-splitter/internal/service/activity/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestListGroupActivity(t *testing.T) {
+	log := logging.GetLogger().Named("testListGroupActivity")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	client, _, closeServer := activityTesting.SetupActivityTest(t, ctx, bun.NewDB(db, pgdialect.New()))
+	// we want to close the server only which cascadingly closes the client as well
+	defer func() {
+		if err := closeServer(); err != nil {
+			t.Errorf("failed closing activity server: %+v", err)
+		}
+	}()
+
+	groupId := "group-123456789012345"
+	resourceId := "person-123456789012345"
+	activityIds := []string{"activity-300000000000000", "activity-200000000000000", "activity-100000000000000"}
+	tsFormat := "2006-01-02 15:04:05-07"
+	timestamp := time.Unix(1693523248, 0)
+	activityColumns := []string{"id", "group_id", "actor", "action", "resource_type", "resource_id", "before_json", "after_json", "create_time"}
+	activityRow := func(rows *sqlmock.Rows, id string) *sqlmock.Rows {
+		return rows.AddRow(id, groupId, "ab@c.de", int32(activityv1.Activity_ACTION_UPDATED), int32(activityv1.Activity_RESOURCE_TYPE_PERSON), resourceId, `{"name":"before"}`, `{"name":"after"}`, timestamp.Format(tsFormat))
+	}
+
+	t.Run("List first page of Group Activity successfully", func(t *testing.T) {
+		rows := sqlmock.NewRows(activityColumns)
+		for _, id := range activityIds {
+			rows = activityRow(rows, id)
+		}
+		// one activity more than requested is selected to tell whether there is a next page
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "activities" (.+) WHERE (.+)group_id = '%s'(.+) ORDER BY id DESC LIMIT 3`, groupId)).
+			WillReturnRows(rows)
+		resp, err := client.ListGroupActivity(ctx, connect.NewRequest(&activitysvcv1.ListGroupActivityRequest{
+			GroupId:  groupId,
+			PageSize: 2,
+		}))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		activities := resp.Msg.GetActivities()
+		if len(activities) != 2 || activities[0].GetId() != activityIds[0] || activities[1].GetId() != activityIds[1] {
+			t.Fatalf("expected the two most recent activities; got: %+v", activities)
+		}
+		if name := activities[0].GetBefore().AsMap()["name"]; name != "before" {
+			t.Errorf("expected the changed fields before the change; got: %+v", activities[0].GetBefore())
+		}
+		if name := activities[0].GetAfter().AsMap()["name"]; name != "after" {
+			t.Errorf("expected the changed fields after the change; got: %+v", activities[0].GetAfter())
+		}
+		if token := resp.Msg.GetNextPageToken(); token != activityIds[1] {
+			t.Errorf("expected the ID of the last listed activity as next page token; got: %s", token)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("List last page of Group Activity successfully", func(t *testing.T) {
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "activities" (.+) WHERE (.+)group_id = '%s'(.+)id < '%s'(.+) ORDER BY id DESC LIMIT 3`, groupId, activityIds[1])).
+			WillReturnRows(activityRow(sqlmock.NewRows(activityColumns), activityIds[2]))
+		resp, err := client.ListGroupActivity(ctx, connect.NewRequest(&activitysvcv1.ListGroupActivityRequest{
+			GroupId:   groupId,
+			PageSize:  2,
+			PageToken: activityIds[1],
+		}))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		if activities := resp.Msg.GetActivities(); len(activities) != 1 || activities[0].GetId() != activityIds[2] {
+			t.Fatalf("expected the oldest activity only; got: %+v", activities)
+		}
+		if token := resp.Msg.GetNextPageToken(); token != "" {
+			t.Errorf("expected no next page token on the last page; got: %s", token)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail listing Group Activity due to database error", func(t *testing.T) {
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "activities" (.+) WHERE (.+)group_id = '%s'(.+)`, groupId)).
+			WillReturnError(sql.ErrConnDone)
+		resp, err := client.ListGroupActivity(ctx, connect.NewRequest(&activitysvcv1.ListGroupActivityRequest{
+			GroupId: groupId,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		if connectErr := new(connect.Error); eris.As(err, &connectErr) {
+			if connectErr.Code() != connect.CodeInternal {
+				t.Fatalf("Expected code: %+v; got: %+v", connect.CodeInternal, connectErr.Code())
+			}
+		} else {
+			t.Fatalf("Expected connect error, got: %+v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package activity
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	activityprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/activity/v1"
+	activitysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/activity/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/service"
+	"github.com/rotisserie/eris"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var streamGroupActivityAlive = activitysvcv1.StreamGroupActivityResponse{
+	Update: &activitysvcv1.StreamGroupActivityResponse_StillAlive{},
+}
+
+func (s *activityServer) StreamGroupActivity(ctx context.Context, req *connect.Request[activitysvcv1.StreamGroupActivityRequest], srv *connect.ServerStream[activitysvcv1.StreamGroupActivityResponse]) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Hour)
+	defer cancel()
+
+	if err := service.StreamEvents(ctx, s.natsClient.Conn, environment.GetActivityRecordedSubject(req.Msg.GetGroupId(), "*"), func(ctx context.Context, event *activityprocv1.ActivityRecorded) (*activitysvcv1.StreamGroupActivityResponse, error) {
+		return &activitysvcv1.StreamGroupActivityResponse{
+			Update: &activitysvcv1.StreamGroupActivityResponse_Activity{
+				Activity: event.GetActivity(),
+			},
+		}, nil
+	}, srv, &streamGroupActivityAlive); err != nil {
+		if eris.Is(err, service.ErrSubscribeResource) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed subscribing to updates",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetMessageSubscriptionErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, service.ErrSendCurrentResourceMessage) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeCanceled,
+				"failed returning activity",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetSendCurrentResourceErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, service.ErrSendStreamAliveMessage) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeCanceled,
+				"failed sending alive message to client",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetSendStreamAliveErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else {
+			return connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package testing
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+
+	activityv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/activity/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/activity/v1/activityv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/service/activity"
+	clienttesting "github.com/nico151999/high-availability-expense-splitter/pkg/connect/client/testing"
+	servertesting "github.com/nico151999/high-availability-expense-splitter/pkg/connect/server/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/uptrace/bun"
+)
+
+// SetupActivityTest creates gRPC server and client and returns instances of interfaces allowing to close both the server and the client. The passed context has no effect on the server's lifecycle.
+func SetupActivityTest(t *testing.T, ctx context.Context, db bun.IDB) (activityv1connect.ActivityServiceClient, net.Listener, func() error) {
+	log := logging.FromContext(ctx).Named("setupActivityTest")
+	ctx = logging.IntoContext(ctx, log)
+
+	for k, v := range map[string]string{
+		"GLOBAL_DOMAIN":          "de.test",
+		"DB_SELECT_ERROR_REASON": "DB_SELECT_ERROR",
+	} {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatalf("failed to set env variable %s: %+v", k, err)
+		}
+	}
+
+	ln, shutdownServer := servertesting.StartTestServer(
+		t,
+		ctx,
+		db,
+		activity.NewActivityServerWithDBClient,
+		activityv1.RegisterActivityServiceHandler,
+		activityv1connect.NewActivityServiceHandler)
+	cl := clienttesting.SetupTestClient(ln, activityv1connect.NewActivityServiceClient)
+	return cl, ln, shutdownServer
+}
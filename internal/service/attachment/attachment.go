@@ -0,0 +1,102 @@
+package attachment
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/attachment/v1/attachmentv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/blob"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+var _ attachmentv1connect.AttachmentServiceHandler = (*attachmentServer)(nil)
+
+var errNoAttachmentWithId = eris.New("there is no attachment with that ID")
+var errMissingHeader = eris.New("the first message does not carry the specs of the attachment")
+var errUnsupportedContentType = eris.New("the content type is not supported")
+var errContentTypeMismatch = eris.New("the content does not match the content type")
+var errEmptyContent = eris.New("the content is empty")
+var errContentTooLarge = eris.New("the content exceeds the maximum size")
+var errReceiveContent = eris.New("failed receiving content")
+var errStoreContent = eris.New("failed storing content")
+var errReadContent = eris.New("failed reading content")
+var errInvalidRange = eris.New("the range exceeds the content")
+var errSendContent = eris.New("failed sending content")
+var errInsertAttachment = eris.New("failed inserting attachment")
+var errSelectAttachmentIds = eris.New("failed selecting attachment IDs")
+var errDeleteAttachment = eris.New("failed deleting attachment")
+var errPublishAttachmentCreated = eris.New("failed publishing attachment created event")
+var errPublishAttachmentDeleted = eris.New("failed publishing attachment deleted event")
+
+// chunkSize is the maximum size of the chunks the content of an attachment is downloaded in
+const chunkSize = 64 * 1024
+
+// supportedContentTypes are the media types of receipts, i.e. photos and scanned documents, which attachments can be uploaded with
+var supportedContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"image/heic":      true,
+	"image/heif":      true,
+	"application/pdf": true,
+}
+
+type attachmentServer struct {
+	dbClient bun.IDB
+	// dbReads is used by read-only endpoints while writes and reads within transactions always use dbClient
+	dbReads    *client.ReadRouter
+	natsClient *nats.EncodedConn
+	blobStore  blob.Store
+}
+
+// NewAttachmentServer creates a new instance of attachment server. The context has no effect on the server's lifecycle.
+func NewAttachmentServer(ctx context.Context, natsServer string, dbConfig client.Config, blobConfig blob.Config) (*attachmentServer, error) {
+	log := logging.FromContext(ctx).Named("NewAttachmentServer")
+	ctx = logging.IntoContext(ctx, log)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	blobStore, err := blob.NewStore(blobConfig)
+	if err != nil {
+		msg := "failed creating blob store"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewAttachmentServerWithClients(ctx, dbClient, blobStore, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
+}
+
+// NewAttachmentServerWithClients creates a new instance of attachment server using the passed database client and blob store.
+// The context has no effect on the server's lifecycle.
+func NewAttachmentServerWithClients(ctx context.Context, dbClient bun.IDB, blobStore blob.Store, natsServer string) (*attachmentServer, error) {
+	log := logging.FromContext(ctx).Named("NewAttachmentServerWithClients")
+	nc, err := mqClient.NewProtoMQClient(natsServer)
+	if err != nil {
+		msg := "failed connecting to NATS server"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	return &attachmentServer{
+		dbClient:   dbClient,
+		dbReads:    client.NewReadRouter(dbClient),
+		natsClient: nc,
+		blobStore:  blobStore,
+	}, nil
+}
+
+func (rps *attachmentServer) Close() error {
+	rps.natsClient.Close()
+	return rps.dbReads.Close()
+}
@@ -0,0 +1,104 @@
+package attachment
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/nats-io/nats.go"
+	attachmentv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/attachment/v1"
+	attachmentprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/attachment/v1"
+	attachmentsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/attachment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *attachmentServer) DeleteAttachment(ctx context.Context, req *connect.Request[attachmentsvcv1.DeleteAttachmentRequest]) (*connect.Response[attachmentsvcv1.DeleteAttachmentResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"attachmentId",
+				req.Msg.GetId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := deleteAttachment(ctx, s.natsClient, s.dbClient, req.Msg.GetId()); err != nil {
+		if eris.Is(err, errPublishAttachmentDeleted) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed finalizing attachment deletion",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetMessagePublicationErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errDeleteAttachment) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBDeleteErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoAttachmentWithId) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the attachment ID does not exist"))
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&attachmentsvcv1.DeleteAttachmentResponse{}), nil
+}
+
+// deleteAttachment deletes the attachment for good instead of moving it to the trash since it is immutable and can simply be uploaded again.
+// The attachment processor deletes its content once the attachment deleted event is published.
+func deleteAttachment(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, attachmentId string) error {
+	log := logging.FromContext(ctx)
+
+	attachment := attachmentv1.Attachment{
+		Id: attachmentId,
+	}
+	// attachments in the trash are only deleted along with their expense
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := util.DeleteReturning(ctx, tx, &attachment, func(q bun.QueryBuilder) bun.QueryBuilder {
+			return q.WherePK().Where("delete_time IS NULL")
+		}, "expense_id", "group_id"); err != nil {
+			if eris.Is(err, sql.ErrNoRows) {
+				log.Info("attachment not found", logging.Error(err))
+				return errNoAttachmentWithId
+			}
+			log.Error("failed deleting attachment", logging.Error(err))
+			return errDeleteAttachment
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := mqClient.PublishEvent(ctx, nc, environment.GetAttachmentDeletedSubject(attachment.GetGroupId(), attachment.GetExpenseId(), attachmentId), &attachmentprocv1.AttachmentDeleted{
+		Id:        attachmentId,
+		ExpenseId: attachment.GetExpenseId(),
+		GroupId:   attachment.GetGroupId(),
+	}); err != nil {
+		log.Error("failed publishing attachment deleted event", logging.Error(err))
+		return errPublishAttachmentDeleted
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package attachment
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"connectrpc.com/connect"
+	attachmentsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/attachment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/blob"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *attachmentServer) DownloadAttachment(ctx context.Context, req *connect.Request[attachmentsvcv1.DownloadAttachmentRequest], srv *connect.ServerStream[attachmentsvcv1.DownloadAttachmentResponse]) error {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"attachmentId",
+				req.Msg.GetId())))
+
+	length := int64(-1)
+	if req.Msg.Length != nil {
+		length = req.Msg.GetLength()
+	}
+	// the download takes as long as the client needs to receive the content which is why only the interaction with the database times out
+	if err := downloadAttachment(ctx, s.dbReads.For(req.Spec().Procedure), s.blobStore, req.Msg.GetId(), req.Msg.GetOffset(), length, func(chunk []byte) error {
+		return srv.Send(&attachmentsvcv1.DownloadAttachmentResponse{
+			Chunk: chunk,
+		})
+	}); err != nil {
+		if eris.Is(err, util.ErrSelectResource) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errReadContent) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed reading the content",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetBlobStoreErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errSendContent) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed sending the content",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetSendCurrentResourceErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errInvalidRange) {
+			return connect.NewError(connect.CodeOutOfRange, eris.New("the offset exceeds the size of the attachment"))
+		} else if resErr := new(util.ResourceNotFoundError); eris.As(err, resErr) {
+			return connect.NewError(connect.CodeNotFound, eris.Errorf("the %s with ID %s does not exist", resErr.ResourceName, resErr.ResourceId))
+		} else {
+			return connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+	return nil
+}
+
+// downloadAttachment passes the content of the attachment starting at the offset in chunks to send; a negative length sends the content to its end
+func downloadAttachment(ctx context.Context, dbClient bun.IDB, blobStore blob.Store, attachmentId string, offset, length int64, send func(chunk []byte) error) error {
+	log := logging.FromContext(ctx)
+
+	attachment, err := checkAttachment(ctx, dbClient, attachmentId)
+	if err != nil {
+		return err
+	}
+	if offset > attachment.GetSize() {
+		log.Info("offset exceeds size of attachment", logging.Int64("offset", offset))
+		return errInvalidRange
+	}
+
+	content, err := blobStore.Open(ctx, attachmentId, offset, length)
+	if err != nil {
+		log.Error("failed opening content", logging.Error(err))
+		return errReadContent
+	}
+	defer content.Close()
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := io.ReadFull(content, buf)
+		if n > 0 {
+			if err := send(buf[:n]); err != nil {
+				log.Info("failed sending content", logging.Error(err))
+				return errSendContent
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		} else if err != nil {
+			log.Error("failed reading content", logging.Error(err))
+			return errReadContent
+		}
+	}
+}
+
+// checkAttachment returns the attachment with the passed ID unless it does not exist or is in the trash
+func checkAttachment(ctx context.Context, dbClient bun.IDB, attachmentId string) (*model.Attachment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return util.CheckResourceExists[*model.Attachment](ctx, dbClient, attachmentId)
+}
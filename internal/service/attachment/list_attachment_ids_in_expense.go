@@ -0,0 +1,57 @@
+package attachment
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	metadatav1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/metadata/v1"
+	attachmentsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/attachment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *attachmentServer) ListAttachmentIdsInExpense(ctx context.Context, req *connect.Request[attachmentsvcv1.ListAttachmentIdsInExpenseRequest]) (*connect.Response[attachmentsvcv1.ListAttachmentIdsInExpenseResponse], error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	attachmentIds, err := listAttachmentIdsInExpense(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetExpenseId(), req.Msg.GetOrderBy(), req.Msg.GetFilter())
+	if err != nil {
+		if eris.Is(err, errSelectAttachmentIds) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&attachmentsvcv1.ListAttachmentIdsInExpenseResponse{
+		Ids: attachmentIds,
+	}), nil
+}
+
+func listAttachmentIdsInExpense(ctx context.Context, dbClient bun.IDB, expenseId string, order *metadatav1.MetadataOrder, filter *metadatav1.MetadataFilter) ([]string, error) {
+	log := logging.FromContext(ctx)
+	var attachmentIds []string
+	query := dbClient.NewSelect().Model((*model.Attachment)(nil)).Where("expense_id = ?", expenseId).Column("id")
+	if err := model.ApplyMetadataListOptions(query, order, filter).Order("file_name ASC").Scan(ctx, &attachmentIds); err != nil {
+		log.Error("failed getting attachment IDs", logging.Error(err))
+		return nil, errSelectAttachmentIds
+	}
+
+	return attachmentIds, nil
+}
@@ -0,0 +1,246 @@
+package attachment
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	attachmentsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/attachment/v1"
+	"github.com/rotisserie/eris"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	uploadAttachmentProcedure   = "/service.attachment.v1.AttachmentService/UploadAttachment"
+	downloadAttachmentProcedure = "/service.attachment.v1.AttachmentService/DownloadAttachment"
+	uploadAttachmentPattern     = "/v1/expenses/{expense_id}/attachments"
+	downloadAttachmentPattern   = "/v1/attachments/{id}/content"
+	// fileFormName is the name of the multipart form part carrying the uploaded file
+	fileFormName = "file"
+)
+
+// RegisterAttachmentServiceHandler registers the generated REST endpoints of the attachment service as well as a multipart
+// upload and a download supporting range requests, both of which cannot be expressed with HTTP annotations.
+// The custom endpoints call the service through the passed connection so that they pass the same interceptors.
+func RegisterAttachmentServiceHandler(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	if err := attachmentsvcv1.RegisterAttachmentServiceHandler(ctx, mux, conn); err != nil {
+		return err
+	}
+	client := attachmentsvcv1.NewAttachmentServiceClient(conn)
+	if err := mux.HandlePath(http.MethodPost, uploadAttachmentPattern, uploadMultipartHandler(mux, client)); err != nil {
+		return eris.Wrap(err, "failed registering multipart upload")
+	}
+	if err := mux.HandlePath(http.MethodGet, downloadAttachmentPattern, downloadContentHandler(mux, client)); err != nil {
+		return eris.Wrap(err, "failed registering content download")
+	}
+	return nil
+}
+
+// uploadMultipartHandler streams the file part of a multipart form to the upload of the attachment service
+func uploadMultipartHandler(mux *runtime.ServeMux, client attachmentsvcv1.AttachmentServiceClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		_, outboundMarshaler := runtime.MarshalerForRequest(mux, r)
+		ctx, err := runtime.AnnotateContext(ctx, mux, r, uploadAttachmentProcedure, runtime.WithHTTPPathPattern(uploadAttachmentPattern))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+
+		part, err := fileFormPart(r)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+		defer part.Close()
+
+		var header, trailer metadata.MD
+		stream, err := client.UploadAttachment(ctx, grpc.Header(&header), grpc.Trailer(&trailer))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+		if err := stream.Send(&attachmentsvcv1.UploadAttachmentRequest{
+			Part: &attachmentsvcv1.UploadAttachmentRequest_Header_{
+				Header: &attachmentsvcv1.UploadAttachmentRequest_Header{
+					ExpenseId:   pathParams["expense_id"],
+					FileName:    part.FileName(),
+					ContentType: part.Header.Get("Content-Type"),
+				},
+			},
+		}); err == nil {
+			buf := make([]byte, chunkSize)
+			for {
+				n, err := io.ReadFull(part, buf)
+				if n > 0 {
+					// a failed send means the service aborted the upload which tells why when closing the stream
+					if err := stream.Send(&attachmentsvcv1.UploadAttachmentRequest{
+						Part: &attachmentsvcv1.UploadAttachmentRequest_Chunk{
+							Chunk: buf[:n],
+						},
+					}); err != nil {
+						break
+					}
+				}
+				if err == io.EOF || err == io.ErrUnexpectedEOF {
+					break
+				} else if err != nil {
+					runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, status.Error(codes.InvalidArgument, "failed reading the file"))
+					return
+				}
+			}
+		}
+		resp, err := stream.CloseAndRecv()
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+		ctx = runtime.NewServerMetadataContext(ctx, runtime.ServerMetadata{HeaderMD: header, TrailerMD: trailer})
+		runtime.ForwardResponseMessage(ctx, mux, outboundMarshaler, w, r, resp)
+	}
+}
+
+// fileFormPart returns the part of the multipart form carrying the uploaded file
+func fileFormPart(r *http.Request) (*multipart.Part, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "the request has to be a multipart form")
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, status.Errorf(codes.InvalidArgument, "the multipart form has no %s part", fileFormName)
+		} else if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "failed reading the multipart form")
+		}
+		if part.FormName() == fileFormName {
+			return part, nil
+		}
+		part.Close()
+	}
+}
+
+// downloadContentHandler serves the content of an attachment downloaded from the attachment service and supports single range requests
+func downloadContentHandler(mux *runtime.ServeMux, client attachmentsvcv1.AttachmentServiceClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		_, outboundMarshaler := runtime.MarshalerForRequest(mux, r)
+		ctx, err := runtime.AnnotateContext(ctx, mux, r, downloadAttachmentProcedure, runtime.WithHTTPPathPattern(downloadAttachmentPattern))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+
+		getResp, err := client.GetAttachment(ctx, &attachmentsvcv1.GetAttachmentRequest{
+			Id: pathParams["id"],
+		})
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+		attachment := getResp.GetAttachment()
+		size := attachment.GetSize()
+
+		w.Header().Set("Accept-Ranges", "bytes")
+		offset, length, partial, err := parseRange(r.Header.Get("Range"), size)
+		if err != nil {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+
+		stream, err := client.DownloadAttachment(ctx, &attachmentsvcv1.DownloadAttachmentRequest{
+			Id:     attachment.GetId(),
+			Offset: offset,
+			Length: &length,
+		})
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+		// the headers are only written once the service started sending the content so that errors can still be reported
+		first, err := stream.Recv()
+		if err != nil && err != io.EOF {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", attachment.GetContentType())
+		w.Header().Set("Content-Length", strconv.FormatInt(length, 10))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.GetFileName()}))
+		if partial {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+length-1, size))
+			w.WriteHeader(http.StatusPartialContent)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+		if err == io.EOF {
+			return
+		}
+		for msg := first; ; {
+			if _, err := w.Write(msg.GetChunk()); err != nil {
+				return
+			}
+			if msg, err = stream.Recv(); err != nil {
+				// the status code was sent already which is why a failure can only be told by the content falling short of its length
+				return
+			}
+		}
+	}
+}
+
+// parseRange returns the offset and length of the content requested by the value of a Range header and whether only a part is requested.
+// Malformed and multiple ranges are ignored in favour of the whole content as permitted by RFC 9110 while ranges beyond the content are
+// unsatisfiable.
+func parseRange(header string, size int64) (offset int64, length int64, partial bool, err error) {
+	spec := strings.TrimPrefix(header, "bytes=")
+	if spec == header || strings.Contains(spec, ",") {
+		return 0, size, false, nil
+	}
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return 0, size, false, nil
+	}
+	if first == "" {
+		// a suffix range requests the last bytes of the content
+		suffix, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || suffix < 0 {
+			return 0, size, false, nil
+		}
+		if suffix == 0 {
+			return 0, 0, false, errInvalidRange
+		}
+		if suffix > size {
+			suffix = size
+		}
+		return size - suffix, suffix, true, nil
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, size, false, nil
+	}
+	if start >= size {
+		return 0, 0, false, errInvalidRange
+	}
+	end := size - 1
+	if last != "" {
+		if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+			return 0, size, false, nil
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+	return start, end - start + 1, true, nil
+}
@@ -0,0 +1,47 @@
+package attachment
+
+import (
+	"testing"
+
+	"github.com/rotisserie/eris"
+)
+
+func TestParseRange(t *testing.T) {
+	const size = 100
+	for _, tc := range []struct {
+		header  string
+		offset  int64
+		length  int64
+		partial bool
+		err     error
+	}{
+		{header: "", offset: 0, length: size},
+		{header: "bytes=0-9", offset: 0, length: 10, partial: true},
+		{header: "bytes=90-", offset: 90, length: 10, partial: true},
+		{header: "bytes=90-200", offset: 90, length: 10, partial: true},
+		{header: "bytes=-10", offset: 90, length: 10, partial: true},
+		{header: "bytes=-200", offset: 0, length: size, partial: true},
+		{header: "bytes=100-", err: errInvalidRange},
+		{header: "bytes=-0", err: errInvalidRange},
+		{header: "bytes=0-9,20-29", offset: 0, length: size},
+		{header: "bytes=9-0", offset: 0, length: size},
+		{header: "items=0-9", offset: 0, length: size},
+		{header: "bytes=a-b", offset: 0, length: size},
+	} {
+		t.Run(tc.header, func(t *testing.T) {
+			offset, length, partial, err := parseRange(tc.header, size)
+			if tc.err != nil {
+				if !eris.Is(err, tc.err) {
+					t.Fatalf("expected error %+v but got %+v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			if offset != tc.offset || length != tc.length || partial != tc.partial {
+				t.Errorf("expected offset %d, length %d and partial %t but got %d, %d and %t", tc.offset, tc.length, tc.partial, offset, length, partial)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package testing
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/attachment/v1/attachmentv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/service/attachment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/blob"
+	clienttesting "github.com/nico151999/high-availability-expense-splitter/pkg/connect/client/testing"
+	servertesting "github.com/nico151999/high-availability-expense-splitter/pkg/connect/server/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/uptrace/bun"
+)
+
+// SetupAttachmentTest creates gRPC server and client and returns instances of interfaces allowing to close both the server and the client
+// as well as the in-memory blob store holding the content of the attachments. The passed context has no effect on the server's lifecycle.
+func SetupAttachmentTest(t *testing.T, ctx context.Context, db bun.IDB) (attachmentv1connect.AttachmentServiceClient, blob.Store, net.Listener, func() error) {
+	log := logging.FromContext(ctx).Named("setupAttachmentTest")
+	ctx = logging.IntoContext(ctx, log)
+
+	for k, v := range map[string]string{
+		"GLOBAL_DOMAIN":                      "de.test",
+		"DB_SELECT_ERROR_REASON":             "DB_SELECT_ERROR",
+		"DB_DELETE_ERROR_REASON":             "DB_DELETE_ERROR",
+		"DB_UPDATE_ERROR_REASON":             "DB_UPDATE_ERROR",
+		"DB_INSERT_ERROR_REASON":             "DB_INSERT_ERROR",
+		"BLOB_STORE_ERROR_REASON":            "BLOB_STORE_ERROR",
+		"MESSAGE_PUBLICATION_ERROR_REASON":   "MESSAGE_PUBLICATION_ERROR",
+		"SEND_CURRENT_RESOURCE_ERROR_REASON": "SEND_CURRENT_RESOURCE_ERROR",
+		"ATTACHMENT_MAX_SIZE":                "1024",
+	} {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatalf("failed to set env variable %s: %+v", k, err)
+		}
+	}
+
+	blobStore := blob.NewMemoryStore()
+	ln, shutdownServer := servertesting.StartTestServer(
+		t,
+		ctx,
+		db,
+		func(ctx context.Context, dbClient bun.IDB, natsServer string) (attachmentv1connect.AttachmentServiceHandler, error) {
+			return attachment.NewAttachmentServerWithClients(ctx, dbClient, blobStore, natsServer)
+		},
+		attachment.RegisterAttachmentServiceHandler,
+		attachmentv1connect.NewAttachmentServiceHandler)
+	cl := clienttesting.SetupTestClient(ln, attachmentv1connect.NewAttachmentServiceClient)
+	return cl, blobStore, ln, shutdownServer
+}
@@ -0,0 +1,272 @@
+package attachment
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/nats-io/nats.go"
+	attachmentv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/attachment/v1"
+	attachmentprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/attachment/v1"
+	attachmentsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/attachment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/blob"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// sniffLength is the number of bytes at the beginning of the content the content type is checked against
+const sniffLength = 512
+
+func (s *attachmentServer) UploadAttachment(ctx context.Context, stream *connect.ClientStream[attachmentsvcv1.UploadAttachmentRequest]) (*connect.Response[attachmentsvcv1.UploadAttachmentResponse], error) {
+	var header *attachmentsvcv1.UploadAttachmentRequest_Header
+	if stream.Receive() {
+		header = stream.Msg().GetHeader()
+	} else if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errors.NewFieldViolationError(ctx, "the first message has to carry the specs of the attachment", "header", errMissingHeader.Error())
+	}
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"expenseId",
+				header.GetExpenseId())))
+
+	maxSize := environment.GetAttachmentMaxSize(ctx)
+	content := &chunkReader{
+		receive: func() ([]byte, error) {
+			if !stream.Receive() {
+				if err := stream.Err(); err != nil {
+					return nil, eris.Wrap(errReceiveContent, err.Error())
+				}
+				return nil, io.EOF
+			}
+			if stream.Msg().GetHeader() != nil {
+				return nil, eris.Wrap(errReceiveContent, "only the first message may carry the specs of the attachment")
+			}
+			return stream.Msg().GetChunk(), nil
+		},
+	}
+	// the upload takes as long as the client needs to send the content which is why only the interactions with the database time out
+	attachment, err := uploadAttachment(ctx, s.natsClient, s.dbClient, s.blobStore, header, maxSize, content)
+	if err != nil {
+		if eris.Is(err, errPublishAttachmentCreated) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed finalizing attachment creation",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetMessagePublicationErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errInsertAttachment) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBInsertErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errStoreContent) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed storing the content",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetBlobStoreErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errUnsupportedContentType) {
+			return nil, errors.NewFieldViolationError(ctx, "the content type is not supported", "header.content_type", fmt.Sprintf("the content type has to be one of %s", strings.Join(supportedContentTypeList(), ", ")))
+		} else if eris.Is(err, errContentTypeMismatch) {
+			return nil, errors.NewFieldViolationError(ctx, "the content does not match the content type", "chunk", fmt.Sprintf("the content is no %s", header.GetContentType()))
+		} else if eris.Is(err, errEmptyContent) {
+			return nil, errors.NewFieldViolationError(ctx, "the content is empty", "chunk", "at least one byte of content has to be uploaded")
+		} else if eris.Is(err, errContentTooLarge) {
+			return nil, errors.NewFieldViolationError(ctx, "the content is too large", "chunk", fmt.Sprintf("the content must not exceed %d bytes", maxSize))
+		} else if eris.Is(err, errReceiveContent) {
+			if err := stream.Err(); err != nil {
+				return nil, err
+			}
+			return nil, errors.NewFieldViolationError(ctx, "the specs of the attachment were sent twice", "header", "only the first message may carry the specs of the attachment")
+		} else if refErr := new(util.InvalidReferenceError); eris.As(err, refErr) {
+			return nil, errors.NewFieldViolationError(ctx, "the request references an invalid resource", refErr.Field, refErr.Description())
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&attachmentsvcv1.UploadAttachmentResponse{
+		Attachment: attachment,
+	}), nil
+}
+
+func uploadAttachment(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, blobStore blob.Store, header *attachmentsvcv1.UploadAttachmentRequest_Header, maxSize int64, content io.Reader) (*attachmentv1.Attachment, error) {
+	log := logging.FromContext(ctx)
+
+	contentType, _, err := mime.ParseMediaType(header.GetContentType())
+	if err != nil || !supportedContentTypes[contentType] {
+		log.Info("unsupported content type", logging.String("contentType", header.GetContentType()))
+		return nil, errUnsupportedContentType
+	}
+	// the expense is checked before the content is received so that uploads to an expense that does not exist fail fast
+	if err := checkExpense(ctx, dbClient, header.GetExpenseId()); err != nil {
+		return nil, err
+	}
+
+	buffered := bufio.NewReaderSize(&sizeLimitedReader{
+		r:         content,
+		remaining: maxSize,
+	}, sniffLength)
+	head, err := buffered.Peek(sniffLength)
+	if err != nil && err != io.EOF {
+		log.Info("failed receiving beginning of content", logging.Error(err))
+		return nil, err
+	}
+	if len(head) == 0 {
+		return nil, errEmptyContent
+	}
+	if !matchesContentType(head, contentType) {
+		log.Info("content does not match content type", logging.String("contentType", contentType))
+		return nil, errContentTypeMismatch
+	}
+
+	attachmentId := util.GenerateIdWithPrefix("attachment")
+	size, err := blobStore.Put(ctx, attachmentId, buffered)
+	if err != nil {
+		if eris.Is(err, errContentTooLarge) || eris.Is(err, errReceiveContent) {
+			log.Info("failed receiving content", logging.Error(err))
+			return nil, err
+		}
+		log.Error("failed storing content", logging.Error(err))
+		return nil, errStoreContent
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	var attachment *model.Attachment
+	if err := transaction.RunInTx(dbCtx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		expense, err := util.CheckReference[*model.Expense](ctx, tx, "expense_id", header.GetExpenseId())
+		if err != nil {
+			return err
+		}
+		attachment = model.NewAttachment(&attachmentv1.Attachment{
+			Id:          attachmentId,
+			ExpenseId:   expense.GetId(),
+			GroupId:     expense.GetGroupId(),
+			FileName:    header.GetFileName(),
+			ContentType: contentType,
+			Size:        size,
+		}, model.NewCreatedMetadata(ctx, time.Now()))
+		if _, err := tx.NewInsert().Model(attachment).Exec(ctx); err != nil {
+			log.Error("failed inserting attachment", logging.Error(err))
+			return errInsertAttachment
+		}
+		return nil
+	}); err != nil {
+		// nothing would ever delete the content of an attachment that was not created
+		if err := blobStore.Delete(ctx, attachmentId); err != nil {
+			log.Error("failed deleting content of attachment that was not created", logging.Error(err))
+		}
+		return nil, err
+	}
+
+	if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetAttachmentCreatedSubject(attachment.GetGroupId(), attachment.GetExpenseId(), attachmentId), &attachmentprocv1.AttachmentCreated{
+		Id:        attachmentId,
+		ExpenseId: attachment.GetExpenseId(),
+		GroupId:   attachment.GetGroupId(),
+	}, attachment.IntoProtoAttachment()); err != nil {
+		log.Error("failed publishing attachment created event", logging.Error(err))
+		return nil, errPublishAttachmentCreated
+	}
+
+	return attachment.IntoProtoAttachment(), nil
+}
+
+// checkExpense returns a util.InvalidReferenceError if the expense with the passed ID does not exist
+func checkExpense(ctx context.Context, dbClient bun.IDB, expenseId string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	_, err := util.CheckReference[*model.Expense](ctx, dbClient, "expense_id", expenseId)
+	return err
+}
+
+// matchesContentType tells whether the beginning of the content matches the content type so that no other kind of file can be uploaded as receipt
+func matchesContentType(head []byte, contentType string) bool {
+	switch contentType {
+	case "image/heic", "image/heif":
+		// the content sniffing algorithm does not know HEIF images which start with a file type box
+		return len(head) >= 12 && string(head[4:8]) == "ftyp"
+	default:
+		return http.DetectContentType(head) == contentType
+	}
+}
+
+// supportedContentTypeList returns the supported content types in alphabetical order
+func supportedContentTypeList() []string {
+	contentTypes := make([]string, 0, len(supportedContentTypes))
+	for contentType := range supportedContentTypes {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+	return contentTypes
+}
+
+// chunkReader reads the content of an attachment from the chunks received one after another until receive returns io.EOF
+type chunkReader struct {
+	receive func() ([]byte, error)
+	chunk   []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		chunk, err := r.receive()
+		if err != nil {
+			return 0, err
+		}
+		r.chunk = chunk
+	}
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
+
+// sizeLimitedReader fails with errContentTooLarge once more than the remaining number of bytes were read
+type sizeLimitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, errContentTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,131 @@
+package attachment_test // the dedicated _test package prevents import cycles with the testing package
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/DATA-DOG/go-sqlmock"
+	attachmentsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/attachment/v1"
+	attachmentTesting "github.com/nico151999/high-availability-expense-splitter/internal/service/attachment/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestUploadAttachment(t *testing.T) {
+	log := logging.GetLogger().Named("testUploadAttachment")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	client, _, _, closeServer := attachmentTesting.SetupAttachmentTest(t, ctx, bun.NewDB(db, pgdialect.New()))
+	// we want to close the server only which cascadingly closes the client as well
+	defer func() {
+		if err := closeServer(); err != nil {
+			t.Errorf("failed closing attachment server: %+v", err)
+		}
+	}()
+
+	expenseId := "expense-123456789012345"
+	groupId := "group-123456789012345"
+	pdf := []byte("%PDF-1.7\n1 0 obj\n<<>>\nendobj\n")
+
+	upload := func(contentType string, content []byte) error {
+		stream := client.UploadAttachment(ctx)
+		if err := stream.Send(&attachmentsvcv1.UploadAttachmentRequest{
+			Part: &attachmentsvcv1.UploadAttachmentRequest_Header_{
+				Header: &attachmentsvcv1.UploadAttachmentRequest_Header{
+					ExpenseId:   expenseId,
+					FileName:    "receipt.pdf",
+					ContentType: contentType,
+				},
+			},
+		}); err != nil {
+			return err
+		}
+		for len(content) > 0 {
+			n := len(content)
+			if n > 100 {
+				n = 100
+			}
+			if err := stream.Send(&attachmentsvcv1.UploadAttachmentRequest{
+				Part: &attachmentsvcv1.UploadAttachmentRequest_Chunk{
+					Chunk: content[:n],
+				},
+			}); err != nil {
+				break
+			}
+			content = content[n:]
+		}
+		resp, err := stream.CloseAndReceive()
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		return err
+	}
+	expectInvalidArgument := func(t *testing.T, err error) {
+		if connectErr := new(connect.Error); eris.As(err, &connectErr) {
+			if connectErr.Code() != connect.CodeInvalidArgument {
+				t.Fatalf("Expected code: %+v; got: %+v", connect.CodeInvalidArgument, connectErr.Code())
+			}
+		} else {
+			t.Fatalf("Expected connect error, got: %+v", err)
+		}
+	}
+	expectExpense := func() {
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "expenses" (.+) WHERE (.+)"id" = '%s'(.+)`, expenseId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id"}).
+				FromCSVString(fmt.Sprintf("%s,%s", expenseId, groupId)))
+	}
+
+	t.Run("Fail uploading Attachment due to unsupported content type", func(t *testing.T) {
+		err := upload("text/plain", []byte("a receipt"))
+		expectInvalidArgument(t, err)
+		t.Logf("Got an error as expected: %+v", err)
+	})
+
+	t.Run("Fail uploading Attachment due to non existent expense", func(t *testing.T) {
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "expenses" (.+) WHERE (.+)"id" = '%s'(.+)`, expenseId)).WillReturnError(sql.ErrNoRows)
+		err := upload("application/pdf", pdf)
+		expectInvalidArgument(t, err)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail uploading Attachment due to content not matching the content type", func(t *testing.T) {
+		expectExpense()
+		err := upload("image/png", pdf)
+		expectInvalidArgument(t, err)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail uploading Attachment due to empty content", func(t *testing.T) {
+		expectExpense()
+		err := upload("application/pdf", nil)
+		expectInvalidArgument(t, err)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail uploading Attachment due to content exceeding the maximum size", func(t *testing.T) {
+		expectExpense()
+		err := upload("application/pdf", append(pdf, bytes.Repeat([]byte{' '}, 1024)...))
+		expectInvalidArgument(t, err)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package budget
+
+import (
+	"context"
+
+	budgetv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/budget/v1"
+	budgetsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/budget/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/budget/v1/budgetv1connect"
+	curClient "github.com/nico151999/high-availability-expense-splitter/pkg/currency/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+var _ budgetv1connect.BudgetServiceHandler = (*budgetServer)(nil)
+
+var errNoBudgetWithId = eris.New("there is no budget with that ID")
+var errInsertBudget = eris.New("failed inserting budget")
+var errUpdateBudget = eris.New("failed updating budget")
+var errDeleteBudget = eris.New("failed deleting budget")
+var errSelectBudgetIds = eris.New("failed selecting budget IDs")
+var errSelectBudgetExpenses = eris.New("failed selecting expenses of budget")
+var errGetExchangeRate = eris.New("failed getting exchange rate into currency of group")
+var errNoTripRange = eris.New("trip budgets require a trip")
+var errUnexpectedTripRange = eris.New("only trip budgets have a trip")
+var errInvalidTripRange = eris.New("the end of the trip has to be after its start")
+
+type budgetServer struct {
+	dbClient bun.IDB
+	// dbReads is used by read-only endpoints while writes and reads within transactions always use dbClient
+	dbReads        *client.ReadRouter
+	currencyClient curClient.Client
+}
+
+// NewBudgetServer creates a new instance of budget server. The context has no effect on the server's lifecycle.
+func NewBudgetServer(ctx context.Context, natsServer string, dbConfig client.Config) (*budgetServer, error) {
+	log := logging.FromContext(ctx).Named("NewBudgetServer")
+	ctx = logging.IntoContext(ctx, log)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewBudgetServerWithDBClient(ctx, dbClient, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
+}
+
+// NewBudgetServerWithDBClient creates a new instance of budget server. The context has no effect on the server's lifecycle.
+// It does not connect to the NATS server since the budget processor publishes the events about reached thresholds.
+func NewBudgetServerWithDBClient(ctx context.Context, dbClient bun.IDB, _ string) (*budgetServer, error) {
+	return &budgetServer{
+		dbClient:       dbClient,
+		dbReads:        client.NewReadRouter(dbClient),
+		currencyClient: curClient.NewCachingCurrencyClient(curClient.NewCurrencyClient()),
+	}, nil
+}
+
+func (rps *budgetServer) Close() error {
+	return rps.dbReads.Close()
+}
+
+// checkTrip makes sure that exactly trip budgets have a trip which ends after it starts
+func checkTrip(period budgetv1.Budget_Period, trip *budgetsvcv1.TimeRange) error {
+	if period != budgetv1.Budget_PERIOD_TRIP {
+		if trip != nil {
+			return errUnexpectedTripRange
+		}
+		return nil
+	}
+	if trip == nil {
+		return errNoTripRange
+	}
+	if !trip.GetEndTime().AsTime().After(trip.GetStartTime().AsTime()) {
+		return errInvalidTripRange
+	}
+	return nil
+}
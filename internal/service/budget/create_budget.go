@@ -0,0 +1,92 @@
+package budget
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	budgetv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/budget/v1"
+	budgetsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/budget/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *budgetServer) CreateBudget(ctx context.Context, req *connect.Request[budgetsvcv1.CreateBudgetRequest]) (*connect.Response[budgetsvcv1.CreateBudgetResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"groupId",
+				req.Msg.GetGroupId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := checkTrip(req.Msg.GetPeriod(), req.Msg.GetTrip()); err != nil {
+		return nil, errors.NewFieldViolationError(ctx, "the request contains an invalid trip", "trip", err.Error())
+	}
+
+	budget, err := createBudget(ctx, s.dbClient, req.Msg)
+	if err != nil {
+		if eris.Is(err, errInsertBudget) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBInsertErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if refErr := new(util.InvalidReferenceError); eris.As(err, refErr) {
+			return nil, errors.NewFieldViolationError(ctx, "the request references an invalid resource", refErr.Field, refErr.Description())
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&budgetsvcv1.CreateBudgetResponse{
+		Budget: budget,
+	}), nil
+}
+
+func createBudget(ctx context.Context, dbClient bun.IDB, req *budgetsvcv1.CreateBudgetRequest) (*budgetv1.Budget, error) {
+	log := logging.FromContext(ctx)
+
+	var budget *model.Budget
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := util.CheckReference[*model.Group](ctx, tx, "group_id", req.GetGroupId()); err != nil {
+			return err
+		}
+		if _, err := util.CheckGroupScopedReference[*model.Category](ctx, tx, "category_id", req.GetCategoryId(), req.GetGroupId()); err != nil {
+			return err
+		}
+		budget = model.NewBudget(&budgetv1.Budget{
+			Id:              util.GenerateIdWithPrefix("budget"),
+			GroupId:         req.GetGroupId(),
+			CategoryId:      req.GetCategoryId(),
+			Period:          req.GetPeriod(),
+			MainValue:       req.GetMainValue(),
+			FractionalValue: req.GetFractionalValue(),
+			StartTime:       req.GetTrip().GetStartTime(),
+			EndTime:         req.GetTrip().GetEndTime(),
+		}, model.NewCreatedMetadata(ctx, time.Now()))
+		if _, err := tx.NewInsert().Model(budget).Exec(ctx); err != nil {
+			log.Error("failed inserting budget", logging.Error(err))
+			return errInsertBudget
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return budget.IntoProtoBudget(), nil
+}
@@ -0,0 +1,159 @@
+package budget_test // the dedicated _test package prevents import cycles with the testing package
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/DATA-DOG/go-sqlmock"
+	budgetv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/budget/v1"
+	budgetsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/budget/v1"
+	budgetTesting "github.com/nico151999/high-availability-expense-splitter/internal/service/budget/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestCreateBudget(t *testing.T) {
+	log := logging.GetLogger().Named("testCreateBudget")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	client, _, closeServer := budgetTesting.SetupBudgetTest(t, ctx, bun.NewDB(db, pgdialect.New()))
+	// we want to close the server only which cascadingly closes the client as well
+	defer func() {
+		if err := closeServer(); err != nil {
+			t.Errorf("failed closing budget server: %+v", err)
+		}
+	}()
+
+	groupId := "group-123456789012345"
+	otherGroupId := "group-543210987654321"
+	categoryId := "category-123456789012345"
+	tripStart := time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC)
+	expectInvalidArgument := func(t *testing.T, err error) {
+		if connectErr := new(connect.Error); eris.As(err, &connectErr) {
+			if connectErr.Code() != connect.CodeInvalidArgument {
+				t.Fatalf("Expected code: %+v; got: %+v", connect.CodeInvalidArgument, connectErr.Code())
+			}
+		} else {
+			t.Fatalf("Expected connect error, got: %+v", err)
+		}
+	}
+
+	t.Run("Create Budget successfully", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "groups" (.+) WHERE (.+)"id" = '%s'(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).
+				FromCSVString(groupId))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "categories" (.+) WHERE (.+)"id" = '%s'(.+)`, categoryId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id"}).
+				FromCSVString(fmt.Sprintf("%s,%s", categoryId, groupId)))
+		mock.ExpectQuery(fmt.Sprintf(`INSERT INTO "budgets" (.+)'%s', '%s', 1, 250, 0(.+)`, groupId, categoryId)).
+			WillReturnRows(sqlmock.NewRows([]string{"revision"}).
+				FromCSVString("1"))
+		mock.ExpectCommit()
+		resp, err := client.CreateBudget(ctx, connect.NewRequest(&budgetsvcv1.CreateBudgetRequest{
+			GroupId:    groupId,
+			CategoryId: categoryId,
+			Period:     budgetv1.Budget_PERIOD_MONTHLY,
+			MainValue:  250,
+		}))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		budget := resp.Msg.GetBudget()
+		if budget.GetCategoryId() != categoryId {
+			t.Errorf("Expected the budget to belong to category %s; got: %s", categoryId, budget.GetCategoryId())
+		}
+		if budget.GetEtag() != `"1"` {
+			t.Errorf("Expected the etag of the first revision; got: %s", budget.GetEtag())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail creating trip Budget without trip", func(t *testing.T) {
+		resp, err := client.CreateBudget(ctx, connect.NewRequest(&budgetsvcv1.CreateBudgetRequest{
+			GroupId:    groupId,
+			CategoryId: categoryId,
+			Period:     budgetv1.Budget_PERIOD_TRIP,
+			MainValue:  250,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectInvalidArgument(t, err)
+	})
+
+	t.Run("Fail creating trip Budget due to a trip ending before it starts", func(t *testing.T) {
+		resp, err := client.CreateBudget(ctx, connect.NewRequest(&budgetsvcv1.CreateBudgetRequest{
+			GroupId:    groupId,
+			CategoryId: categoryId,
+			Period:     budgetv1.Budget_PERIOD_TRIP,
+			MainValue:  250,
+			Trip: &budgetsvcv1.TimeRange{
+				StartTime: timestamppb.New(tripStart),
+				EndTime:   timestamppb.New(tripStart.Add(-24 * time.Hour)),
+			},
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectInvalidArgument(t, err)
+	})
+
+	t.Run("Fail creating Budget due to category of different group", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "groups" (.+) WHERE (.+)"id" = '%s'(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).
+				FromCSVString(groupId))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "categories" (.+) WHERE (.+)"id" = '%s'(.+)`, categoryId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id"}).
+				FromCSVString(fmt.Sprintf("%s,%s", categoryId, otherGroupId)))
+		mock.ExpectRollback()
+		resp, err := client.CreateBudget(ctx, connect.NewRequest(&budgetsvcv1.CreateBudgetRequest{
+			GroupId:    groupId,
+			CategoryId: categoryId,
+			Period:     budgetv1.Budget_PERIOD_MONTHLY,
+			MainValue:  250,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectInvalidArgument(t, err)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail creating Budget due to non existent group", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "groups" (.+) WHERE (.+)"id" = '%s'(.+)`, groupId)).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+		resp, err := client.CreateBudget(ctx, connect.NewRequest(&budgetsvcv1.CreateBudgetRequest{
+			GroupId:    groupId,
+			CategoryId: categoryId,
+			Period:     budgetv1.Budget_PERIOD_MONTHLY,
+			MainValue:  250,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectInvalidArgument(t, err)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package budget
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	budgetsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/budget/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *budgetServer) DeleteBudget(ctx context.Context, req *connect.Request[budgetsvcv1.DeleteBudgetRequest]) (*connect.Response[budgetsvcv1.DeleteBudgetResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"budgetId",
+				req.Msg.GetId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := deleteBudget(ctx, s.dbClient, req.Msg.GetId(), req.Msg.GetEtag()); err != nil {
+		if eris.Is(err, errDeleteBudget) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBDeleteErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoBudgetWithId) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the budget ID does not exist"))
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the budget was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&budgetsvcv1.DeleteBudgetResponse{}), nil
+}
+
+// deleteBudget deletes the budget along with the record of the thresholds it reached for good
+func deleteBudget(ctx context.Context, dbClient bun.IDB, budgetId string, etag string) error {
+	log := logging.FromContext(ctx)
+
+	return transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := model.CheckCurrentEtag[*model.Budget](ctx, tx, budgetId, etag); err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("budget not found", logging.Error(err))
+				return errNoBudgetWithId
+			}
+			return err
+		}
+		res, err := tx.NewDelete().Model((*model.Budget)(nil)).Where("id = ?", budgetId).ApplyQueryBuilder(func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereEtag(q, etag)
+		}).Exec(ctx)
+		if err != nil {
+			log.Error("failed deleting budget", logging.Error(err))
+			return errDeleteBudget
+		}
+		if deleted, err := res.RowsAffected(); err == nil && deleted == 0 {
+			if etag != "" {
+				if err := model.ConcurrentEtagMismatch[*model.Budget](ctx, tx, budgetId); !eris.As(err, &util.ResourceNotFoundError{}) {
+					return err
+				}
+			}
+			log.Info("budget not found")
+			return errNoBudgetWithId
+		}
+		if _, err := tx.NewDelete().Model((*model.BudgetAlert)(nil)).Where("budget_id = ?", budgetId).Exec(ctx); err != nil {
+			log.Error("failed deleting budget alerts", logging.Error(err))
+			return errDeleteBudget
+		}
+		return nil
+	})
+}
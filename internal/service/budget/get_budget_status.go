@@ -0,0 +1,109 @@
+package budget
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	budgetsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/budget/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/balance"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/budget"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	curClient "github.com/nico151999/high-availability-expense-splitter/pkg/currency/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *budgetServer) GetBudgetStatus(ctx context.Context, req *connect.Request[budgetsvcv1.GetBudgetStatusRequest]) (*connect.Response[budgetsvcv1.GetBudgetStatusResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"budgetId",
+				req.Msg.GetId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	t := time.Now()
+	if req.Msg.GetTime() != nil {
+		t = req.Msg.GetTime().AsTime()
+	}
+	status, err := getBudgetStatus(ctx, s.dbReads.For(req.Spec().Procedure), s.currencyClient, req.Msg.GetId(), t)
+	if err != nil {
+		if eris.Is(err, util.ErrSelectResource) || eris.Is(err, errSelectBudgetExpenses) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if resErr := new(util.ResourceNotFoundError); eris.As(err, resErr) {
+			return nil, connect.NewError(connect.CodeNotFound, eris.Errorf("the %s with ID %s does not exist", resErr.ResourceName, resErr.ResourceId))
+		} else if eris.Is(err, curClient.ErrCurrencyExchangeRateNotFound) {
+			return nil, connect.NewError(connect.CodeUnavailable, eris.New("the exchange rate of an expense is not available"))
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(status), nil
+}
+
+// getBudgetStatus sums up the expenses of the category of the budget within the period the passed time is in, or within the trip of a
+// trip budget, converting each expense into the currency of the group at the exchange rate of its time
+func getBudgetStatus(ctx context.Context, dbClient bun.IDB, currencyClient curClient.Client, budgetId string, t time.Time) (*budgetsvcv1.GetBudgetStatusResponse, error) {
+	log := logging.FromContext(ctx)
+
+	b, err := util.CheckResourceExists[*model.Budget](ctx, dbClient, budgetId)
+	if err != nil {
+		return nil, err
+	}
+	group, err := util.CheckResourceExists[*model.Group](ctx, dbClient, b.GetGroupId())
+	if err != nil {
+		return nil, err
+	}
+	currency, err := util.CheckResourceExists[*model.Currency](ctx, dbClient, group.GetCurrencyId())
+	if err != nil {
+		return nil, err
+	}
+
+	// the trip of a trip budget is its only period regardless of the passed time
+	period, _ := b.PeriodAt(t)
+	expenses, err := model.SelectBudgetExpenses(ctx, dbClient, b, period)
+	if err != nil {
+		log.Error("failed selecting expenses of budget", logging.Error(err))
+		return nil, errSelectBudgetExpenses
+	}
+	spent, err := budget.Spent(ctx, expenses, currency.GetAcronym(), currencyClient.GetExchangeRate)
+	if err != nil {
+		log.Error("failed getting exchange rate into currency of group", logging.Error(err))
+		return nil, eris.Wrap(err, errGetExchangeRate.Error())
+	}
+
+	spentMainValue, spentFractionalValue := balance.Split(spent)
+	var percentage float64
+	if amount := b.Amount(); amount > 0 {
+		percentage = float64(spent) * 100 / float64(amount)
+	}
+	return &budgetsvcv1.GetBudgetStatusResponse{
+		PeriodStartTime:       timestamppb.New(period.Start),
+		PeriodEndTime:         timestamppb.New(period.End),
+		CurrencyId:            group.GetCurrencyId(),
+		SpentMainValue:        spentMainValue,
+		SpentFractionalValue:  spentFractionalValue,
+		BudgetMainValue:       b.GetMainValue(),
+		BudgetFractionalValue: b.GetFractionalValue(),
+		Percentage:            percentage,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package budget
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	metadatav1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/metadata/v1"
+	budgetsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/budget/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *budgetServer) ListBudgetIdsInGroup(ctx context.Context, req *connect.Request[budgetsvcv1.ListBudgetIdsInGroupRequest]) (*connect.Response[budgetsvcv1.ListBudgetIdsInGroupResponse], error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	budgetIds, err := listBudgetIdsInGroup(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetGroupId(), req.Msg.GetOrderBy(), req.Msg.GetFilter())
+	if err != nil {
+		if eris.Is(err, errSelectBudgetIds) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&budgetsvcv1.ListBudgetIdsInGroupResponse{
+		Ids: budgetIds,
+	}), nil
+}
+
+func listBudgetIdsInGroup(ctx context.Context, dbClient bun.IDB, groupId string, order *metadatav1.MetadataOrder, filter *metadatav1.MetadataFilter) ([]string, error) {
+	log := logging.FromContext(ctx)
+	var budgetIds []string
+	query := dbClient.NewSelect().Model((*model.Budget)(nil)).Where("group_id = ?", groupId).Column("id")
+	if err := model.ApplyMetadataListOptions(query, order, filter).Order("create_time ASC", "id ASC").Scan(ctx, &budgetIds); err != nil {
+		log.Error("failed getting budget IDs", logging.Error(err))
+		return nil, errSelectBudgetIds
+	}
+
+	return budgetIds, nil
+}
@@ -0,0 +1,45 @@
+package testing
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+
+	budgetv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/budget/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/budget/v1/budgetv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/service/budget"
+	clienttesting "github.com/nico151999/high-availability-expense-splitter/pkg/connect/client/testing"
+	servertesting "github.com/nico151999/high-availability-expense-splitter/pkg/connect/server/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/uptrace/bun"
+)
+
+// SetupBudgetTest creates gRPC server and client and returns instances of interfaces allowing to close both the server and the client. The passed context has no effect on the server's lifecycle.
+func SetupBudgetTest(t *testing.T, ctx context.Context, db bun.IDB) (budgetv1connect.BudgetServiceClient, net.Listener, func() error) {
+	log := logging.FromContext(ctx).Named("setupBudgetTest")
+	ctx = logging.IntoContext(ctx, log)
+
+	for k, v := range map[string]string{
+		"GLOBAL_DOMAIN":              "de.test",
+		"DB_SELECT_ERROR_REASON":     "DB_SELECT_ERROR",
+		"DB_DELETE_ERROR_REASON":     "DB_DELETE_ERROR",
+		"DB_UPDATE_ERROR_REASON":     "DB_UPDATE_ERROR",
+		"DB_INSERT_ERROR_REASON":     "DB_INSERT_ERROR",
+		"ETAG_MISMATCH_ERROR_REASON": "ETAG_MISMATCH_ERROR",
+	} {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatalf("failed to set env variable %s: %+v", k, err)
+		}
+	}
+
+	ln, shutdownServer := servertesting.StartTestServer(
+		t,
+		ctx,
+		db,
+		budget.NewBudgetServerWithDBClient,
+		budgetv1.RegisterBudgetServiceHandler,
+		budgetv1connect.NewBudgetServiceHandler)
+	cl := clienttesting.SetupTestClient(ln, budgetv1connect.NewBudgetServiceClient)
+	return cl, ln, shutdownServer
+}
@@ -0,0 +1,129 @@
+package budget
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"connectrpc.com/connect"
+	budgetv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/budget/v1"
+	budgetsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/budget/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *budgetServer) UpdateBudget(ctx context.Context, req *connect.Request[budgetsvcv1.UpdateBudgetRequest]) (*connect.Response[budgetsvcv1.UpdateBudgetResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"budgetId",
+				req.Msg.GetId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	budget, err := updateBudget(ctx, s.dbClient, req.Msg.GetId(), req.Msg.GetUpdateFields(), req.Msg.GetEtag())
+	if err != nil {
+		if eris.Is(err, errUpdateBudget) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBUpdateErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoBudgetWithId) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the budget ID does not exist"))
+		} else if eris.Is(err, errUnexpectedTripRange) || eris.Is(err, errInvalidTripRange) {
+			return nil, errors.NewFieldViolationError(ctx, "the request contains an invalid trip", "update_fields.trip", err.Error())
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the budget was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&budgetsvcv1.UpdateBudgetResponse{
+		Budget: budget,
+	}), nil
+}
+
+// updateBudget changes the amount of a budget or the trip of a trip budget. The thresholds already reached are not alerted again
+// even if a larger amount makes the spending fall below them.
+func updateBudget(ctx context.Context, dbClient bun.IDB, budgetId string, params []*budgetsvcv1.UpdateBudgetRequest_UpdateField, etag string) (*budgetv1.Budget, error) {
+	log := logging.FromContext(ctx)
+
+	var budget *model.Budget
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		currentBudget, err := util.CheckResourceExists[*model.Budget](ctx, tx, budgetId)
+		if err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("budget not found", logging.Error(err))
+				return errNoBudgetWithId
+			}
+			return err
+		}
+		if err := currentBudget.CheckEtag(etag); err != nil {
+			return err
+		}
+
+		budget = model.NewBudget(currentBudget.IntoProtoBudget(), model.NewModifiedMetadata(ctx, time.Now()))
+		query := model.IncrementRevision(tx.NewUpdate().Column(model.MetadataUpdateColumns...))
+		for _, param := range params {
+			switch option := param.GetUpdateOption().(type) {
+			case *budgetsvcv1.UpdateBudgetRequest_UpdateField_Amount:
+				budget.MainValue = option.Amount.GetMainValue()
+				budget.FractionalValue = option.Amount.GetFractionalValue()
+				query.Column("main_value", "fractional_value")
+			case *budgetsvcv1.UpdateBudgetRequest_UpdateField_Trip:
+				if err := checkTrip(budget.GetPeriod(), option.Trip); err != nil {
+					return err
+				}
+				startTime, endTime := option.Trip.GetStartTime().AsTime(), option.Trip.GetEndTime().AsTime()
+				budget.StartTime, budget.EndTime = &startTime, &endTime
+				query.Column("start_time", "end_time")
+			}
+		}
+		if err := util.UpdateReturning(ctx, tx, model.MatchEtag(query.Model(budget), etag), util.WherePK, append([]string{"revision"}, model.MetadataReturningColumns...)...); err != nil {
+			if eris.Is(err, sql.ErrNoRows) {
+				if etag != "" {
+					if err := model.ConcurrentEtagMismatch[*model.Budget](ctx, tx, budgetId); !eris.As(err, &util.ResourceNotFoundError{}) {
+						return err
+					}
+				}
+				log.Info("budget not found", logging.Error(err))
+				return errNoBudgetWithId
+			}
+			log.Error("failed updating budget", logging.Error(err))
+			return errUpdateBudget
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return budget.IntoProtoBudget(), nil
+}
@@ -18,25 +18,37 @@ var errNoCategoryWithId = eris.New("there is no category with that ID")
 var errInsertCategory = eris.New("failed inserting category")
 var errPublishCategoryCreated = eris.New("failed publishing category created event")
 var errPublishCategoryDeleted = eris.New("failed publishing category deleted event")
+var errPublishCategoryUndeleted = eris.New("failed publishing category undeleted event")
 var errPublishCategoryUpdated = eris.New("failed publishing category updated event")
 var errSelectCategoryIds = eris.New("failed selecting category IDs")
 var errDeleteCategory = eris.New("failed deleting category")
+var errUndeleteCategory = eris.New("failed restoring category")
 var errUpdateCategory = eris.New("failed updating category")
 
 type categoryServer struct {
-	dbClient   bun.IDB
+	dbClient bun.IDB
+	// dbReads is used by read-only endpoints while writes and reads within transactions always use dbClient
+	dbReads    *client.ReadRouter
 	natsClient *nats.EncodedConn
 	// TODO: add clients to servers this server will communicate with
 }
 
 // NewCategoryServer creates a new instance of category server. The context has no effect on the server's lifecycle.
-func NewCategoryServer(ctx context.Context, natsServer, dbUser, dbPass, dbAddr, db string) (*categoryServer, error) {
+func NewCategoryServer(ctx context.Context, natsServer string, dbConfig client.Config) (*categoryServer, error) {
 	log := logging.FromContext(ctx).Named("NewCategoryServer")
 	ctx = logging.IntoContext(ctx, log)
-	return NewCategoryServerWithDBClient(
-		ctx,
-		client.NewPostgresDBClient(dbUser, dbPass, dbAddr, db),
-		natsServer)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewCategoryServerWithDBClient(ctx, dbClient, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
 }
 
 // NewCategoryServerWithDBClient creates a new instance of category server. The context has no effect on the server's lifecycle.
@@ -50,11 +62,12 @@ func NewCategoryServerWithDBClient(ctx context.Context, dbClient bun.IDB, natsSe
 	}
 	return &categoryServer{
 		dbClient:   dbClient,
+		dbReads:    client.NewReadRouter(dbClient),
 		natsClient: nc,
 	}, nil
 }
 
 func (rps *categoryServer) Close() error {
 	rps.natsClient.Close()
-	return nil
+	return rps.dbReads.Close()
 }
@@ -2,19 +2,20 @@ package category
 
 import (
 	"context"
-	"database/sql"
 	"time"
 
 	"connectrpc.com/connect"
 	"github.com/nats-io/nats.go"
 	categoryv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/category/v1"
-	groupv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/group/v1"
 	categoryprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/category/v1"
 	categorysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/category/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -73,32 +74,33 @@ func createCategory(ctx context.Context, nc *nats.EncodedConn, db bun.IDB, req *
 	categoryId := util.GenerateIdWithPrefix("category")
 	requestorEmail := "ab@c.de" // TODO: take user email from context
 
-	if err := db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		if _, err := util.CheckResourceExists[*groupv1.Group](ctx, tx, req.GetGroupId()); err != nil {
+	category := model.NewCategory(&categoryv1.Category{
+		Id:      categoryId,
+		GroupId: req.GetGroupId(),
+		Name:    req.GetName(),
+	}, model.NewCreatedMetadata(ctx, time.Now()))
+	if err := transaction.RunInTx(ctx, db, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := util.CheckResourceExists[*model.Group](ctx, tx, req.GetGroupId()); err != nil {
 			return err
 		}
 
-		if _, err := tx.NewInsert().Model(&categoryv1.Category{
-			Id:      categoryId,
-			GroupId: req.GetGroupId(),
-			Name:    req.GetName(),
-		}).Exec(ctx); err != nil {
+		if _, err := tx.NewInsert().Model(category).Exec(ctx); err != nil {
 			log.Error("failed inserting category", logging.Error(err))
 			return errInsertCategory
 		}
-
-		if err := nc.Publish(environment.GetCategoryCreatedSubject(req.GetGroupId(), categoryId), &categoryprocv1.CategoryCreated{
-			Id:             categoryId,
-			GroupId:        req.GetGroupId(),
-			Name:           req.GetName(),
-			RequestorEmail: requestorEmail,
-		}); err != nil {
-			log.Error("failed publishing category created event", logging.Error(err))
-			return errPublishCategoryCreated
-		}
 		return nil
 	}); err != nil {
 		return "", err
 	}
+
+	if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetCategoryCreatedSubject(req.GetGroupId(), categoryId), &categoryprocv1.CategoryCreated{
+		Id:             categoryId,
+		GroupId:        req.GetGroupId(),
+		Name:           req.GetName(),
+		RequestorEmail: requestorEmail,
+	}, category.IntoProtoCategory()); err != nil {
+		log.Error("failed publishing category created event", logging.Error(err))
+		return "", errPublishCategoryCreated
+	}
 	return categoryId, nil
 }
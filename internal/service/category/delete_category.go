@@ -10,9 +10,13 @@ import (
 	categoryv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/category/v1"
 	categoryprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/category/v1"
 	categorysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/category/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -29,7 +33,7 @@ func (s *categoryServer) DeleteCategory(ctx context.Context, req *connect.Reques
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	if err := deleteCategory(ctx, s.natsClient, s.dbClient, req.Msg.GetId()); err != nil {
+	if err := deleteCategory(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetEtag()); err != nil {
 		if eris.Is(err, errDeleteCategory) {
 			return nil, errors.NewErrorWithDetails(
 				ctx,
@@ -45,6 +49,18 @@ func (s *categoryServer) DeleteCategory(ctx context.Context, req *connect.Reques
 			return nil, connect.NewError(
 				connect.CodeNotFound,
 				eris.New("the category ID does not exist"))
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the category was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
 		} else {
 			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
 		}
@@ -53,29 +69,49 @@ func (s *categoryServer) DeleteCategory(ctx context.Context, req *connect.Reques
 	return connect.NewResponse(&categorysvcv1.DeleteCategoryResponse{}), nil
 }
 
-func deleteCategory(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, categoryId string) error {
+func deleteCategory(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, categoryId string, etag string) error {
 	log := logging.FromContext(ctx)
 
-	return dbClient.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		category := categoryv1.Category{
+	category := model.Category{
+		Category: categoryv1.Category{
 			Id: categoryId,
+		},
+	}
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := model.CheckCurrentEtag[*model.Category](ctx, tx, categoryId, etag); err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("category not found", logging.Error(err))
+				return errNoCategoryWithId
+			}
+			return err
 		}
-		if err := tx.NewDelete().Model(&category).WherePK().Returning("group_id").Scan(ctx); err != nil {
+		if err := util.UpdateMatchedReturning(ctx, tx, model.SoftDelete(tx.NewUpdate().Model(&category), categoryId, time.Now()), func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereEtag(q.WherePK(), etag)
+		}, "group_id"); err != nil {
 			if eris.Is(err, sql.ErrNoRows) {
+				if etag != "" {
+					if err := model.ConcurrentEtagMismatch[*model.Category](ctx, tx, categoryId); !eris.As(err, &util.ResourceNotFoundError{}) {
+						return err
+					}
+				}
 				log.Info("category not found", logging.Error(err))
 				return errNoCategoryWithId
 			}
 			log.Error("failed deleting category", logging.Error(err))
 			return errDeleteCategory
 		}
-
-		if err := nc.Publish(environment.GetCategoryDeletedSubject(category.GroupId, categoryId), &categoryprocv1.CategoryDeleted{
-			Id:      categoryId,
-			GroupId: category.GroupId,
-		}); err != nil {
-			log.Error("failed publishing category deleted event", logging.Error(err))
-			return errPublishCategoryDeleted
-		}
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	if err := mqClient.PublishEvent(ctx, nc, environment.GetCategoryDeletedSubject(category.GroupId, categoryId), &categoryprocv1.CategoryDeleted{
+		Id:          categoryId,
+		GroupId:     category.GroupId,
+		DeleteCause: categoryId,
+	}); err != nil {
+		log.Error("failed publishing category deleted event", logging.Error(err))
+		return errPublishCategoryDeleted
+	}
+	return nil
 }
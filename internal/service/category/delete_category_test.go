@@ -38,7 +38,7 @@ func TestDeleteCategory(t *testing.T) {
 		mock.ExpectBegin()
 		groupId := "group-543210987654321"
 		categoryId := "category-123456789012345"
-		mock.ExpectQuery(fmt.Sprintf(`DELETE FROM "categories" (.+) WHERE (.+)"id" = '%s'(.+)`, categoryId)).
+		mock.ExpectQuery(fmt.Sprintf(`UPDATE "categories" (.+) WHERE (.+)"id" = '%s'(.+)`, categoryId)).
 			WillReturnRows(sqlmock.NewRows([]string{"group_id"}).
 				FromCSVString(groupId))
 		mock.ExpectCommit()
@@ -66,7 +66,7 @@ func TestDeleteCategory(t *testing.T) {
 	t.Run("Fail deleting Category due to non existence", func(t *testing.T) {
 		categoryId := "category-543210987654321"
 		mock.ExpectBegin()
-		mock.ExpectQuery(fmt.Sprintf(`DELETE FROM "categories" (.+) WHERE (.+)"id" = '%s'(.+)`, categoryId)).WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(fmt.Sprintf(`UPDATE "categories" (.+) WHERE (.+)"id" = '%s'(.+)`, categoryId)).WillReturnError(sql.ErrNoRows)
 		mock.ExpectRollback()
 		resp, err := client.DeleteCategory(ctx, connect.NewRequest(&categorysvcv1.DeleteCategoryRequest{
 			Id: categoryId,
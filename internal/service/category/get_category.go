@@ -5,8 +5,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	categoryv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/category/v1"
 	categorysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/category/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
@@ -26,7 +26,7 @@ func (s *categoryServer) GetCategory(ctx context.Context, req *connect.Request[c
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	category, err := util.CheckResourceExists[*categoryv1.Category](ctx, s.dbClient, req.Msg.GetId())
+	category, err := util.CheckResourceExistsWithDeleted[*model.Category](ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetId())
 	if err != nil {
 		if eris.Is(err, util.ErrSelectResource) {
 			return nil, errors.NewErrorWithDetails(
@@ -47,6 +47,6 @@ func (s *categoryServer) GetCategory(ctx context.Context, req *connect.Request[c
 	}
 
 	return connect.NewResponse(&categorysvcv1.GetCategoryResponse{
-		Category: category,
+		Category: category.IntoProtoCategory(),
 	}), nil
 }
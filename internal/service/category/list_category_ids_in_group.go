@@ -5,8 +5,9 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	categoryv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/category/v1"
+	metadatav1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/metadata/v1"
 	categorysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/category/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
@@ -20,7 +21,7 @@ func (s *categoryServer) ListCategoryIdsInGroup(ctx context.Context, req *connec
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	categoryIds, err := listCategoryIds(ctx, s.dbClient, req.Msg.GetGroupId())
+	categoryIds, err := listCategoryIds(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetGroupId(), req.Msg.GetOrderBy(), req.Msg.GetFilter(), req.Msg.GetDeleted())
 	if err != nil {
 		if eris.Is(err, errSelectCategoryIds) {
 			return nil, errors.NewErrorWithDetails(
@@ -43,10 +44,14 @@ func (s *categoryServer) ListCategoryIdsInGroup(ctx context.Context, req *connec
 	}), nil
 }
 
-func listCategoryIds(ctx context.Context, dbClient bun.IDB, groupId string) ([]string, error) {
+func listCategoryIds(ctx context.Context, dbClient bun.IDB, groupId string, order *metadatav1.MetadataOrder, filter *metadatav1.MetadataFilter, deleted bool) ([]string, error) {
 	log := logging.FromContext(ctx)
 	var categoryIds []string
-	if err := dbClient.NewSelect().Model((*categoryv1.Category)(nil)).Where("group_id = ?", groupId).Column("id").Order("name ASC").Scan(ctx, &categoryIds); err != nil {
+	query := dbClient.NewSelect().Model((*model.Category)(nil)).Where("group_id = ?", groupId).Column("id")
+	if deleted {
+		query = query.WhereDeleted()
+	}
+	if err := model.ApplyMetadataListOptions(query, order, filter).Order("name ASC").Scan(ctx, &categoryIds); err != nil {
 		log.Error("failed getting category IDs", logging.Error(err))
 		// TODO: determine reason why category ID couldn't be fetched and return error-specific ErrVariable; e.g. use unit testing with dummy return values to determine potential return values unless there is something in the bun documentation
 		return nil, errSelectCategoryIds
@@ -6,8 +6,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	categoryv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/category/v1"
 	categorysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/category/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
@@ -36,7 +36,7 @@ func (s *categoryServer) StreamCategory(ctx context.Context, req *connect.Reques
 
 	streamSubject := fmt.Sprintf("%s.*", environment.GetCategorySubject("*", req.Msg.GetId()))
 	if err := service.StreamResource(ctx, s.natsClient.Conn, streamSubject, func(ctx context.Context) (*categorysvcv1.StreamCategoryResponse, error) {
-		return sendCurrentCategory(ctx, s.dbClient, req.Msg.GetId())
+		return sendCurrentCategory(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetId())
 	}, srv, &streamCategoryAlive); err != nil {
 		if eris.Is(err, service.ErrResourceNoLongerFound) {
 			return connect.NewError(
@@ -88,13 +88,13 @@ func (s *categoryServer) StreamCategory(ctx context.Context, req *connect.Reques
 }
 
 func sendCurrentCategory(ctx context.Context, dbClient bun.IDB, categoryId string) (*categorysvcv1.StreamCategoryResponse, error) {
-	category, err := util.CheckResourceExists[*categoryv1.Category](ctx, dbClient, categoryId)
+	category, err := util.CheckResourceExists[*model.Category](ctx, dbClient, categoryId)
 	if err != nil {
 		return nil, err
 	}
 	return &categorysvcv1.StreamCategoryResponse{
 		Update: &categorysvcv1.StreamCategoryResponse_Category{
-			Category: category,
+			Category: category.IntoProtoCategory(),
 		},
 	}, nil
 }
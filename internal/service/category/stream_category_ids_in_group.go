@@ -6,8 +6,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	categoryv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/category/v1"
 	categorysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/category/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
@@ -27,7 +27,7 @@ func (s *categoryServer) StreamCategoryIdsInGroup(ctx context.Context, req *conn
 	defer cancel()
 
 	if err := service.StreamResource(ctx, s.natsClient.Conn, fmt.Sprintf("%s.*", environment.GetCategorySubject(req.Msg.GetGroupId(), "*")), func(ctx context.Context) (*categorysvcv1.StreamCategoryIdsInGroupResponse, error) {
-		return sendCurrentCategoryIds(ctx, s.dbClient, req.Msg.GetGroupId())
+		return sendCurrentCategoryIds(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetGroupId())
 	}, srv, &streamCategoryIdsAlive); err != nil {
 		if eris.Is(err, errSelectCategoryIds) {
 			return errors.NewErrorWithDetails(
@@ -85,7 +85,7 @@ func sendCurrentCategoryIds(ctx context.Context, dbClient bun.IDB, groupId strin
 	log := logging.FromContext(ctx)
 
 	var categoryIds []string
-	if err := dbClient.NewSelect().Model((*categoryv1.Category)(nil)).Where("group_id = ?", groupId).Column("id").Order("name ASC").Scan(ctx, &categoryIds); err != nil {
+	if err := dbClient.NewSelect().Model((*model.Category)(nil)).Where("group_id = ?", groupId).Column("id").Order("name ASC").Scan(ctx, &categoryIds); err != nil {
 		log.Error("failed getting category IDs", logging.Error(err))
 		// TODO: determine reason why category IDs couldn't be fetched and return error-specific ErrVariable; e.g. use unit testing with dummy return values to determine potential return values unless there is something in the bun documentation
 		return nil, errSelectCategoryIds
@@ -0,0 +1,114 @@
+package category
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/nats-io/nats.go"
+	categoryv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/category/v1"
+	categoryprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/category/v1"
+	categorysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/category/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *categoryServer) UndeleteCategory(ctx context.Context, req *connect.Request[categorysvcv1.UndeleteCategoryRequest]) (*connect.Response[categorysvcv1.UndeleteCategoryResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"categoryId",
+				req.Msg.GetId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	category, err := undeleteCategory(ctx, s.natsClient, s.dbClient, req.Msg.GetId())
+	if err != nil {
+		if eris.Is(err, errUndeleteCategory) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBUpdateErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoCategoryWithId) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the category ID does not exist"))
+		} else if restoreErr := new(model.RestoreError); eris.As(err, restoreErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeFailedPrecondition,
+				"the category cannot be restored",
+				[]protoreflect.ProtoMessage{
+					&errdetails.PreconditionFailure{
+						Violations: []*errdetails.PreconditionFailure_Violation{
+							{
+								Type:        restoreErr.Type,
+								Subject:     restoreErr.Subject,
+								Description: restoreErr.Description,
+							},
+						},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&categorysvcv1.UndeleteCategoryResponse{
+		Category: category,
+	}), nil
+}
+
+func undeleteCategory(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, categoryId string) (*categoryv1.Category, error) {
+	log := logging.FromContext(ctx)
+
+	var category *model.Category
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		category, err = model.CheckRestorable[*model.Category](ctx, tx, categoryId)
+		if err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("category not found", logging.Error(err))
+				return errNoCategoryWithId
+			}
+			return err
+		}
+		if err := model.CheckParentNotDeleted[*model.Group](ctx, tx, category.GetGroupId()); err != nil {
+			return err
+		}
+		if err := model.Restore(ctx, tx, category); err != nil {
+			log.Error("failed restoring category", logging.Error(err))
+			return errUndeleteCategory
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := mqClient.PublishEvent(ctx, nc, environment.GetCategoryUndeletedSubject(category.GetGroupId(), categoryId), &categoryprocv1.CategoryUndeleted{
+		Id:          categoryId,
+		GroupId:     category.GetGroupId(),
+		DeleteCause: categoryId,
+	}); err != nil {
+		log.Error("failed publishing category undeleted event", logging.Error(err))
+		return nil, errPublishCategoryUndeleted
+	}
+
+	return category.IntoProtoCategory(), nil
+}
@@ -10,10 +10,13 @@ import (
 	categoryv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/category/v1"
 	categoryprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/category/v1"
 	categorysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/category/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -30,7 +33,7 @@ func (s *categoryServer) UpdateCategory(ctx context.Context, req *connect.Reques
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	category, err := updateCategory(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetUpdateFields())
+	category, err := updateCategory(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetUpdateFields(), req.Msg.GetEtag())
 	if err != nil {
 		if eris.Is(err, errUpdateCategory) {
 			return nil, errors.NewErrorWithDetails(
@@ -49,6 +52,18 @@ func (s *categoryServer) UpdateCategory(ctx context.Context, req *connect.Reques
 				eris.New("the category ID does not exist"))
 		} else if resErr := new(util.ResourceNotFoundError); eris.As(err, &resErr) {
 			return nil, connect.NewError(connect.CodeNotFound, eris.Errorf("the %s with ID %s does not exist", resErr.ResourceName, resErr.ResourceId))
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the category was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
 		} else {
 			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
 		}
@@ -59,14 +74,21 @@ func (s *categoryServer) UpdateCategory(ctx context.Context, req *connect.Reques
 	}), nil
 }
 
-func updateCategory(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, categoryId string, params []*categorysvcv1.UpdateCategoryRequest_UpdateField) (*categoryv1.Category, error) {
+func updateCategory(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, categoryId string, params []*categorysvcv1.UpdateCategoryRequest_UpdateField, etag string) (*categoryv1.Category, error) {
 	log := logging.FromContext(ctx)
-	category := categoryv1.Category{
+	category := model.NewCategory(&categoryv1.Category{
 		Id: categoryId,
-	}
+	}, model.NewModifiedMetadata(ctx, time.Now()))
 
-	if err := dbClient.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		query := tx.NewUpdate()
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := model.CheckCurrentEtag[*model.Category](ctx, tx, categoryId, etag); err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("category not found", logging.Error(err))
+				return errNoCategoryWithId
+			}
+			return err
+		}
+		query := model.IncrementRevision(tx.NewUpdate().Column(model.MetadataUpdateColumns...))
 		for _, param := range params {
 			switch param.GetUpdateOption().(type) {
 			case *categorysvcv1.UpdateCategoryRequest_UpdateField_Name:
@@ -74,26 +96,38 @@ func updateCategory(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB,
 				query.Column("name")
 			}
 		}
-		if err := query.Model(&category).WherePK().Returning("group_id").Scan(ctx); err != nil {
+		if err := util.UpdateReturning(ctx, tx, model.MatchEtag(query.Model(category), etag), util.WherePK, append([]string{"group_id", "revision"}, model.MetadataReturningColumns...)...); err != nil {
 			if eris.Is(err, sql.ErrNoRows) {
+				if etag != "" {
+					if err := model.ConcurrentEtagMismatch[*model.Category](ctx, tx, categoryId); !eris.As(err, &util.ResourceNotFoundError{}) {
+						return err
+					}
+				}
 				log.Info("category not found", logging.Error(err))
 				return errNoCategoryWithId
 			}
 			log.Error("failed updating category", logging.Error(err))
 			return errUpdateCategory
 		}
-
-		if err := nc.Publish(environment.GetCategoryUpdatedSubject(category.GroupId, categoryId), &categoryprocv1.CategoryUpdated{
-			Id:      categoryId,
-			GroupId: category.GroupId,
-		}); err != nil {
-			log.Error("failed publishing category updated event", logging.Error(err))
-			return errPublishCategoryUpdated
+		// only the updated columns are returned but the event carries the whole category
+		updated, err := util.CheckResourceExists[*model.Category](ctx, tx, categoryId)
+		if err != nil {
+			log.Error("failed getting updated category", logging.Error(err))
+			return errUpdateCategory
 		}
+		category = updated
 		return nil
 	}); err != nil {
 		return nil, err
 	}
 
-	return &category, nil
+	if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetCategoryUpdatedSubject(category.GroupId, categoryId), &categoryprocv1.CategoryUpdated{
+		Id:      categoryId,
+		GroupId: category.GroupId,
+	}, category.IntoProtoCategory()); err != nil {
+		log.Error("failed publishing category updated event", logging.Error(err))
+		return nil, errPublishCategoryUpdated
+	}
+
+	return category.IntoProtoCategory(), nil
 }
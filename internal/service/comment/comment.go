@@ -0,0 +1,74 @@
+package comment
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/comment/v1/commentv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+var _ commentv1connect.CommentServiceHandler = (*commentServer)(nil)
+
+var errNoCommentWithId = eris.New("there is no comment with that ID")
+var errInsertComment = eris.New("failed inserting comment")
+var errUpdateComment = eris.New("failed updating comment")
+var errDeleteComment = eris.New("failed deleting comment")
+var errSelectCommentIds = eris.New("failed selecting comment IDs")
+var errSelectCommentRevisions = eris.New("failed selecting comment revisions")
+var errPublishCommentCreated = eris.New("failed publishing comment created event")
+var errPublishCommentUpdated = eris.New("failed publishing comment updated event")
+var errPublishCommentDeleted = eris.New("failed publishing comment deleted event")
+
+// defaultPageSize is the number of revisions listed if the request does not specify a page size
+const defaultPageSize = 20
+
+type commentServer struct {
+	dbClient bun.IDB
+	// dbReads is used by read-only endpoints while writes and reads within transactions always use dbClient
+	dbReads    *client.ReadRouter
+	natsClient *nats.EncodedConn
+}
+
+// NewCommentServer creates a new instance of comment server. The context has no effect on the server's lifecycle.
+func NewCommentServer(ctx context.Context, natsServer string, dbConfig client.Config) (*commentServer, error) {
+	log := logging.FromContext(ctx).Named("NewCommentServer")
+	ctx = logging.IntoContext(ctx, log)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewCommentServerWithDBClient(ctx, dbClient, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
+}
+
+// NewCommentServerWithDBClient creates a new instance of comment server. The context has no effect on the server's lifecycle.
+func NewCommentServerWithDBClient(ctx context.Context, dbClient bun.IDB, natsServer string) (*commentServer, error) {
+	log := logging.FromContext(ctx).Named("NewCommentServerWithDBClient")
+	nc, err := mqClient.NewProtoMQClient(natsServer)
+	if err != nil {
+		msg := "failed connecting to NATS server"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	return &commentServer{
+		dbClient:   dbClient,
+		dbReads:    client.NewReadRouter(dbClient),
+		natsClient: nc,
+	}, nil
+}
+
+func (rps *commentServer) Close() error {
+	rps.natsClient.Close()
+	return rps.dbReads.Close()
+}
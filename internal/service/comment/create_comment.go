@@ -0,0 +1,131 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/nats-io/nats.go"
+	commentv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/comment/v1"
+	commentprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/comment/v1"
+	commentsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/comment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *commentServer) CreateComment(ctx context.Context, req *connect.Request[commentsvcv1.CreateCommentRequest]) (*connect.Response[commentsvcv1.CreateCommentResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"expenseId",
+				req.Msg.GetExpenseId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	comment, err := createComment(ctx, s.natsClient, s.dbClient, req.Msg)
+	if err != nil {
+		if eris.Is(err, errPublishCommentCreated) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed finalizing comment creation",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetMessagePublicationErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errInsertComment) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBInsertErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if refErr := new(util.InvalidReferenceError); eris.As(err, refErr) {
+			return nil, errors.NewFieldViolationError(ctx, "the request references an invalid resource", refErr.Field, refErr.Description())
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&commentsvcv1.CreateCommentResponse{
+		Comment: comment,
+	}), nil
+}
+
+func createComment(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, req *commentsvcv1.CreateCommentRequest) (*commentv1.Comment, error) {
+	log := logging.FromContext(ctx)
+
+	var comment *model.Comment
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		expense, err := util.CheckReference[*model.Expense](ctx, tx, "expense_id", req.GetExpenseId())
+		if err != nil {
+			return err
+		}
+		if _, err := util.CheckGroupScopedReference[*model.Person](ctx, tx, "author_id", req.GetAuthorId(), expense.GetGroupId()); err != nil {
+			return err
+		}
+		if err := checkMentionedPersons(ctx, tx, "mentioned_person_ids", req.GetMentionedPersonIds(), expense.GetGroupId()); err != nil {
+			return err
+		}
+		now := time.Now()
+		comment = model.NewComment(&commentv1.Comment{
+			Id:                 util.GenerateIdWithPrefix("comment"),
+			ExpenseId:          expense.GetId(),
+			GroupId:            expense.GetGroupId(),
+			AuthorId:           req.GetAuthorId(),
+			Text:               req.GetText(),
+			MentionedPersonIds: req.GetMentionedPersonIds(),
+		}, model.NewCreatedMetadata(ctx, now))
+		if _, err := tx.NewInsert().Model(comment).Exec(ctx); err != nil {
+			log.Error("failed inserting comment", logging.Error(err))
+			return errInsertComment
+		}
+		if err := model.RecordCommentRevision(ctx, tx, comment, now); err != nil {
+			log.Error("failed recording comment revision", logging.Error(err))
+			return errInsertComment
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetCommentCreatedSubject(comment.GetGroupId(), comment.GetExpenseId(), comment.GetId()), &commentprocv1.CommentCreated{
+		Id:                 comment.GetId(),
+		ExpenseId:          comment.GetExpenseId(),
+		GroupId:            comment.GetGroupId(),
+		AuthorId:           comment.GetAuthorId(),
+		MentionedPersonIds: comment.GetMentionedPersonIds(),
+	}, comment.IntoProtoComment()); err != nil {
+		log.Error("failed publishing comment created event", logging.Error(err))
+		return nil, errPublishCommentCreated
+	}
+
+	return comment.IntoProtoComment(), nil
+}
+
+// checkMentionedPersons returns a util.InvalidReferenceError naming the element of the passed field if a mentioned person does not belong to the group
+func checkMentionedPersons(ctx context.Context, db bun.IDB, field string, personIds []string, groupId string) error {
+	for i, personId := range personIds {
+		if _, err := util.CheckGroupScopedReference[*model.Person](ctx, db, fmt.Sprintf("%s[%d]", field, i), personId, groupId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package comment_test // the dedicated _test package prevents import cycles with the testing package
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/DATA-DOG/go-sqlmock"
+	commentsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/comment/v1"
+	commentTesting "github.com/nico151999/high-availability-expense-splitter/internal/service/comment/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestCreateComment(t *testing.T) {
+	log := logging.GetLogger().Named("testCreateComment")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	client, _, closeServer := commentTesting.SetupCommentTest(t, ctx, bun.NewDB(db, pgdialect.New()))
+	// we want to close the server only which cascadingly closes the client as well
+	defer func() {
+		if err := closeServer(); err != nil {
+			t.Errorf("failed closing comment server: %+v", err)
+		}
+	}()
+
+	expenseId := "expense-123456789012345"
+	groupId := "group-123456789012345"
+	authorId := "person-123456789012345"
+	mentionedId := "person-543210987654321"
+	expectInvalidArgument := func(t *testing.T, err error) {
+		if connectErr := new(connect.Error); eris.As(err, &connectErr) {
+			if connectErr.Code() != connect.CodeInvalidArgument {
+				t.Fatalf("Expected code: %+v; got: %+v", connect.CodeInvalidArgument, connectErr.Code())
+			}
+		} else {
+			t.Fatalf("Expected connect error, got: %+v", err)
+		}
+	}
+	expectReference := func(table string, id string, groupId string) {
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "%s" (.+) WHERE (.+)"id" = '%s'(.+)`, table, id)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id"}).
+				FromCSVString(fmt.Sprintf("%s,%s", id, groupId)))
+	}
+
+	t.Run("Create Comment successfully", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectReference("expenses", expenseId, groupId)
+		expectReference("people", authorId, groupId)
+		expectReference("people", mentionedId, groupId)
+		mock.ExpectQuery(`INSERT INTO "comments" (.+)`).
+			WillReturnRows(sqlmock.NewRows([]string{"revision"}).
+				FromCSVString("1"))
+		mock.ExpectExec(`INSERT INTO "comment_revisions" (.+)`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		resp, err := client.CreateComment(ctx, connect.NewRequest(&commentsvcv1.CreateCommentRequest{
+			ExpenseId:          expenseId,
+			AuthorId:           authorId,
+			Text:               "I did not have any of the wine",
+			MentionedPersonIds: []string{mentionedId},
+		}))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		comment := resp.Msg.GetComment()
+		if comment.GetGroupId() != groupId {
+			t.Errorf("Expected the comment to belong to group %s; got: %s", groupId, comment.GetGroupId())
+		}
+		if comment.GetEdited() {
+			t.Error("Expected a new comment not to be edited")
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail creating Comment due to empty text", func(t *testing.T) {
+		resp, err := client.CreateComment(ctx, connect.NewRequest(&commentsvcv1.CreateCommentRequest{
+			ExpenseId: expenseId,
+			AuthorId:  authorId,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectInvalidArgument(t, err)
+	})
+
+	t.Run("Fail creating Comment due to non existent expense", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "expenses" (.+) WHERE (.+)"id" = '%s'(.+)`, expenseId)).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+		resp, err := client.CreateComment(ctx, connect.NewRequest(&commentsvcv1.CreateCommentRequest{
+			ExpenseId: expenseId,
+			AuthorId:  authorId,
+			Text:      "Who paid for the taxi?",
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectInvalidArgument(t, err)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail creating Comment due to mentioning a person of another group", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectReference("expenses", expenseId, groupId)
+		expectReference("people", authorId, groupId)
+		expectReference("people", mentionedId, "group-543210987654321")
+		mock.ExpectRollback()
+		resp, err := client.CreateComment(ctx, connect.NewRequest(&commentsvcv1.CreateCommentRequest{
+			ExpenseId:          expenseId,
+			AuthorId:           authorId,
+			Text:               "I did not have any of the wine",
+			MentionedPersonIds: []string{mentionedId},
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectInvalidArgument(t, err)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+}
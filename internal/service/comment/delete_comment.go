@@ -0,0 +1,132 @@
+package comment
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/nats-io/nats.go"
+	commentv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/comment/v1"
+	commentprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/comment/v1"
+	commentsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/comment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *commentServer) DeleteComment(ctx context.Context, req *connect.Request[commentsvcv1.DeleteCommentRequest]) (*connect.Response[commentsvcv1.DeleteCommentResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"commentId",
+				req.Msg.GetId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := deleteComment(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetEtag()); err != nil {
+		if eris.Is(err, errPublishCommentDeleted) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed finalizing comment deletion",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetMessagePublicationErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errDeleteComment) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBDeleteErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoCommentWithId) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the comment ID does not exist"))
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the comment was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&commentsvcv1.DeleteCommentResponse{}), nil
+}
+
+// deleteComment deletes the comment along with its edit history for good instead of moving it to the trash since its author
+// withdrew it. Comments are only moved to the trash along with their expense.
+func deleteComment(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, commentId string, etag string) error {
+	log := logging.FromContext(ctx)
+
+	comment := commentv1.Comment{
+		Id: commentId,
+	}
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := model.CheckCurrentEtag[*model.Comment](ctx, tx, commentId, etag); err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("comment not found", logging.Error(err))
+				return errNoCommentWithId
+			}
+			return err
+		}
+		if err := util.DeleteReturning(ctx, tx, &comment, func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereEtag(q.WherePK().Where("delete_time IS NULL"), etag)
+		}, "expense_id", "group_id"); err != nil {
+			if eris.Is(err, sql.ErrNoRows) {
+				if etag != "" {
+					if err := model.ConcurrentEtagMismatch[*model.Comment](ctx, tx, commentId); !eris.As(err, &util.ResourceNotFoundError{}) {
+						return err
+					}
+				}
+				log.Info("comment not found", logging.Error(err))
+				return errNoCommentWithId
+			}
+			log.Error("failed deleting comment", logging.Error(err))
+			return errDeleteComment
+		}
+		if _, err := tx.NewDelete().Model((*model.CommentRevision)(nil)).Where("comment_id = ?", commentId).Exec(ctx); err != nil {
+			log.Error("failed deleting comment revisions", logging.Error(err))
+			return errDeleteComment
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := mqClient.PublishEvent(ctx, nc, environment.GetCommentDeletedSubject(comment.GetGroupId(), comment.GetExpenseId(), commentId), &commentprocv1.CommentDeleted{
+		Id:        commentId,
+		ExpenseId: comment.GetExpenseId(),
+		GroupId:   comment.GetGroupId(),
+	}); err != nil {
+		log.Error("failed publishing comment deleted event", logging.Error(err))
+		return errPublishCommentDeleted
+	}
+	return nil
+}
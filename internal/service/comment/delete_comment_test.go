@@ -0,0 +1,79 @@
+package comment_test // the dedicated _test package prevents import cycles with the testing package
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/DATA-DOG/go-sqlmock"
+	commentsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/comment/v1"
+	commentTesting "github.com/nico151999/high-availability-expense-splitter/internal/service/comment/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestDeleteComment(t *testing.T) {
+	log := logging.GetLogger().Named("testDeleteComment")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	client, _, closeServer := commentTesting.SetupCommentTest(t, ctx, bun.NewDB(db, pgdialect.New()))
+	// we want to close the server only which cascadingly closes the client as well
+	defer func() {
+		if err := closeServer(); err != nil {
+			t.Errorf("failed closing comment server: %+v", err)
+		}
+	}()
+
+	t.Run("Delete Comment along with its edit history successfully", func(t *testing.T) {
+		mock.ExpectBegin()
+		commentId := "comment-123456789012345"
+		mock.ExpectQuery(fmt.Sprintf(`DELETE FROM "comments" (.+) WHERE (.+)"id" = '%s'(.+)`, commentId)).
+			WillReturnRows(sqlmock.NewRows([]string{"expense_id", "group_id"}).
+				FromCSVString("expense-123456789012345,group-123456789012345"))
+		mock.ExpectExec(fmt.Sprintf(`DELETE FROM "comment_revisions" (.+) WHERE (.+)comment_id = '%s'`, commentId)).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+		_, err := client.DeleteComment(ctx, connect.NewRequest(&commentsvcv1.DeleteCommentRequest{
+			Id: commentId,
+		}))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail deleting Comment due to non existence", func(t *testing.T) {
+		mock.ExpectBegin()
+		commentId := "comment-543210987654321"
+		mock.ExpectQuery(fmt.Sprintf(`DELETE FROM "comments" (.+) WHERE (.+)"id" = '%s'(.+)`, commentId)).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+		resp, err := client.DeleteComment(ctx, connect.NewRequest(&commentsvcv1.DeleteCommentRequest{
+			Id: commentId,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		if connectErr := new(connect.Error); eris.As(err, &connectErr) {
+			if connectErr.Code() != connect.CodeNotFound {
+				t.Fatalf("Expected code: %+v; got: %+v", connect.CodeNotFound, connectErr.Code())
+			}
+		} else {
+			t.Fatalf("Expected connect error, got: %+v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package comment
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	metadatav1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/metadata/v1"
+	commentsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/comment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *commentServer) ListCommentIdsInExpense(ctx context.Context, req *connect.Request[commentsvcv1.ListCommentIdsInExpenseRequest]) (*connect.Response[commentsvcv1.ListCommentIdsInExpenseResponse], error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	commentIds, err := listCommentIdsInExpense(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetExpenseId(), req.Msg.GetOrderBy(), req.Msg.GetFilter())
+	if err != nil {
+		if eris.Is(err, errSelectCommentIds) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&commentsvcv1.ListCommentIdsInExpenseResponse{
+		Ids: commentIds,
+	}), nil
+}
+
+func listCommentIdsInExpense(ctx context.Context, dbClient bun.IDB, expenseId string, order *metadatav1.MetadataOrder, filter *metadatav1.MetadataFilter) ([]string, error) {
+	log := logging.FromContext(ctx)
+	var commentIds []string
+	query := dbClient.NewSelect().Model((*model.Comment)(nil)).Where("expense_id = ?", expenseId).Column("id")
+	if err := model.ApplyMetadataListOptions(query, order, filter).Order("create_time ASC", "id ASC").Scan(ctx, &commentIds); err != nil {
+		log.Error("failed getting comment IDs", logging.Error(err))
+		return nil, errSelectCommentIds
+	}
+
+	return commentIds, nil
+}
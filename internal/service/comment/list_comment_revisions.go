@@ -0,0 +1,92 @@
+package comment
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	commentv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/comment/v1"
+	commentsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/comment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *commentServer) ListCommentRevisions(ctx context.Context, req *connect.Request[commentsvcv1.ListCommentRevisionsRequest]) (*connect.Response[commentsvcv1.ListCommentRevisionsResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"commentId",
+				req.Msg.GetCommentId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	pageSize := int(req.Msg.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	revisions, nextPageToken, err := listCommentRevisions(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetCommentId(), pageSize, req.Msg.GetPageToken())
+	if err != nil {
+		if eris.Is(err, errSelectCommentRevisions) || eris.Is(err, util.ErrSelectResource) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if resErr := new(util.ResourceNotFoundError); eris.As(err, resErr) {
+			return nil, connect.NewError(connect.CodeNotFound, eris.Errorf("the %s with ID %s does not exist", resErr.ResourceName, resErr.ResourceId))
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&commentsvcv1.ListCommentRevisionsResponse{
+		Revisions:     revisions,
+		NextPageToken: nextPageToken,
+	}), nil
+}
+
+// listCommentRevisions returns a page of the edit history of a comment starting with the most recent revision.
+// The page token is the number of the last revision of the previous page.
+func listCommentRevisions(ctx context.Context, dbClient bun.IDB, commentId string, pageSize int, pageToken int64) ([]*commentv1.CommentRevision, int64, error) {
+	log := logging.FromContext(ctx)
+
+	if _, err := util.CheckResourceExistsWithDeleted[*model.Comment](ctx, dbClient, commentId); err != nil {
+		return nil, 0, err
+	}
+
+	var revisions []*model.CommentRevision
+	query := dbClient.NewSelect().Model(&revisions).
+		Where("comment_id = ?", commentId)
+	if pageToken != 0 {
+		query = query.Where("revision < ?", pageToken)
+	}
+	// one more revision than requested is selected to tell whether there is a next page
+	if err := query.Order("revision DESC").Limit(pageSize + 1).Scan(ctx); err != nil {
+		log.Error("failed getting comment revisions", logging.Error(err))
+		return nil, 0, errSelectCommentRevisions
+	}
+
+	var nextPageToken int64
+	if len(revisions) > pageSize {
+		revisions = revisions[:pageSize]
+		nextPageToken = revisions[pageSize-1].Revision
+	}
+	protoRevisions := make([]*commentv1.CommentRevision, len(revisions))
+	for i, r := range revisions {
+		protoRevisions[i] = r.IntoProtoCommentRevision()
+	}
+	return protoRevisions, nextPageToken, nil
+}
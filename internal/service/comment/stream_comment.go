@@ -0,0 +1,100 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	commentsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/comment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/service"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var streamCommentAlive = commentsvcv1.StreamCommentResponse{
+	Update: &commentsvcv1.StreamCommentResponse_StillAlive{},
+}
+
+func (s *commentServer) StreamComment(ctx context.Context, req *connect.Request[commentsvcv1.StreamCommentRequest], srv *connect.ServerStream[commentsvcv1.StreamCommentResponse]) error {
+	ctx, cancel := context.WithTimeout(
+		logging.IntoContext(
+			ctx,
+			logging.FromContext(ctx).With(
+				logging.String(
+					"commentId",
+					req.Msg.GetId()))),
+		time.Hour)
+	defer cancel()
+
+	streamSubject := fmt.Sprintf("%s.*", environment.GetCommentSubject("*", "*", req.Msg.GetId()))
+	if err := service.StreamResource(ctx, s.natsClient.Conn, streamSubject, func(ctx context.Context) (*commentsvcv1.StreamCommentResponse, error) {
+		return sendCurrentComment(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetId())
+	}, srv, &streamCommentAlive); err != nil {
+		if eris.Is(err, service.ErrResourceNoLongerFound) {
+			return connect.NewError(
+				connect.CodeDataLoss,
+				eris.New("the comment does no longer exist"))
+		} else if eris.As(err, &util.ResourceNotFoundError{}) {
+			return connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the comment does not exist"))
+		} else if eris.Is(err, service.ErrSubscribeResource) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed subscribing to updates",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetMessageSubscriptionErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, service.ErrSendCurrentResourceMessage) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeCanceled,
+				"failed returning current resource",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetSendCurrentResourceErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, service.ErrSendStreamAliveMessage) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeCanceled,
+				"failed sending alive message to client",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetSendStreamAliveErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else {
+			return connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return nil
+}
+
+func sendCurrentComment(ctx context.Context, dbClient bun.IDB, commentId string) (*commentsvcv1.StreamCommentResponse, error) {
+	comment, err := util.CheckResourceExists[*model.Comment](ctx, dbClient, commentId)
+	if err != nil {
+		return nil, err
+	}
+	return &commentsvcv1.StreamCommentResponse{
+		Update: &commentsvcv1.StreamCommentResponse_Comment{
+			Comment: comment.IntoProtoComment(),
+		},
+	}, nil
+}
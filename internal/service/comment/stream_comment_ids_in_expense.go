@@ -0,0 +1,99 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	commentsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/comment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/service"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var streamCommentIdsInExpenseAlive = commentsvcv1.StreamCommentIdsInExpenseResponse{
+	Update: &commentsvcv1.StreamCommentIdsInExpenseResponse_StillAlive{},
+}
+
+func (s *commentServer) StreamCommentIdsInExpense(ctx context.Context, req *connect.Request[commentsvcv1.StreamCommentIdsInExpenseRequest], srv *connect.ServerStream[commentsvcv1.StreamCommentIdsInExpenseResponse]) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Hour)
+	defer cancel()
+
+	if err := service.StreamResource(ctx, s.natsClient.Conn, fmt.Sprintf("%s.*", environment.GetCommentSubject("*", req.Msg.GetExpenseId(), "*")), func(ctx context.Context) (*commentsvcv1.StreamCommentIdsInExpenseResponse, error) {
+		return sendCurrentCommentIdsInExpense(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetExpenseId())
+	}, srv, &streamCommentIdsInExpenseAlive); err != nil {
+		if eris.Is(err, errSelectCommentIds) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, service.ErrSubscribeResource) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed subscribing to updates",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetMessageSubscriptionErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, service.ErrSendCurrentResourceMessage) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeCanceled,
+				"failed returning current resource",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetSendCurrentResourceErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, service.ErrSendStreamAliveMessage) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeCanceled,
+				"failed sending alive message to client",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetSendStreamAliveErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else {
+			return connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return nil
+}
+
+func sendCurrentCommentIdsInExpense(ctx context.Context, dbClient bun.IDB, expenseId string) (*commentsvcv1.StreamCommentIdsInExpenseResponse, error) {
+	log := logging.FromContext(ctx)
+
+	var commentIds []string
+	if err := dbClient.NewSelect().Model((*model.Comment)(nil)).Where("expense_id = ?", expenseId).Column("id").Order("create_time ASC", "id ASC").Scan(ctx, &commentIds); err != nil {
+		log.Error("failed getting comment IDs", logging.Error(err))
+		return nil, errSelectCommentIds
+	}
+	return &commentsvcv1.StreamCommentIdsInExpenseResponse{
+		Update: &commentsvcv1.StreamCommentIdsInExpenseResponse_Ids{
+			Ids: &commentsvcv1.StreamCommentIdsInExpenseResponse_CommentIds{
+				Ids: commentIds,
+			},
+		},
+	}, nil
+}
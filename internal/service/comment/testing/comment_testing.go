@@ -0,0 +1,46 @@
+package testing
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+
+	commentv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/comment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/comment/v1/commentv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/service/comment"
+	clienttesting "github.com/nico151999/high-availability-expense-splitter/pkg/connect/client/testing"
+	servertesting "github.com/nico151999/high-availability-expense-splitter/pkg/connect/server/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/uptrace/bun"
+)
+
+// SetupCommentTest creates gRPC server and client and returns instances of interfaces allowing to close both the server and the client. The passed context has no effect on the server's lifecycle.
+func SetupCommentTest(t *testing.T, ctx context.Context, db bun.IDB) (commentv1connect.CommentServiceClient, net.Listener, func() error) {
+	log := logging.FromContext(ctx).Named("setupCommentTest")
+	ctx = logging.IntoContext(ctx, log)
+
+	for k, v := range map[string]string{
+		"GLOBAL_DOMAIN":                    "de.test",
+		"DB_SELECT_ERROR_REASON":           "DB_SELECT_ERROR",
+		"DB_DELETE_ERROR_REASON":           "DB_DELETE_ERROR",
+		"DB_UPDATE_ERROR_REASON":           "DB_UPDATE_ERROR",
+		"DB_INSERT_ERROR_REASON":           "DB_INSERT_ERROR",
+		"ETAG_MISMATCH_ERROR_REASON":       "ETAG_MISMATCH_ERROR",
+		"MESSAGE_PUBLICATION_ERROR_REASON": "MESSAGE_PUBLICATION_ERROR",
+	} {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatalf("failed to set env variable %s: %+v", k, err)
+		}
+	}
+
+	ln, shutdownServer := servertesting.StartTestServer(
+		t,
+		ctx,
+		db,
+		comment.NewCommentServerWithDBClient,
+		commentv1.RegisterCommentServiceHandler,
+		commentv1connect.NewCommentServiceHandler)
+	cl := clienttesting.SetupTestClient(ln, commentv1connect.NewCommentServiceClient)
+	return cl, ln, shutdownServer
+}
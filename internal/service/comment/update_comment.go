@@ -0,0 +1,173 @@
+package comment
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/nats-io/nats.go"
+	commentv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/comment/v1"
+	commentprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/comment/v1"
+	commentsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/comment/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *commentServer) UpdateComment(ctx context.Context, req *connect.Request[commentsvcv1.UpdateCommentRequest]) (*connect.Response[commentsvcv1.UpdateCommentResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"commentId",
+				req.Msg.GetId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	comment, err := updateComment(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetUpdateFields(), req.Msg.GetEtag())
+	if err != nil {
+		if eris.Is(err, errPublishCommentUpdated) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed finalizing comment update",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetMessagePublicationErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errUpdateComment) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBUpdateErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoCommentWithId) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the comment ID does not exist"))
+		} else if refErr := new(util.InvalidReferenceError); eris.As(err, refErr) {
+			return nil, errors.NewFieldViolationError(ctx, "the request references an invalid resource", refErr.Field, refErr.Description())
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the comment was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&commentsvcv1.UpdateCommentResponse{
+		Comment: comment,
+	}), nil
+}
+
+// updateComment edits the text and mentions of a comment and records the edited comment in its edit history
+func updateComment(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, commentId string, params []*commentsvcv1.UpdateCommentRequest_UpdateField, etag string) (*commentv1.Comment, error) {
+	log := logging.FromContext(ctx)
+
+	var comment *model.Comment
+	var newlyMentionedPersonIds []string
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		currentComment, err := util.CheckResourceExists[*model.Comment](ctx, tx, commentId)
+		if err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("comment not found", logging.Error(err))
+				return errNoCommentWithId
+			}
+			return err
+		}
+		if err := currentComment.CheckEtag(etag); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		comment = model.NewComment(currentComment.IntoProtoComment(), model.NewModifiedMetadata(ctx, now))
+		query := model.IncrementRevision(tx.NewUpdate().Column(model.MetadataUpdateColumns...))
+		for _, param := range params {
+			switch option := param.GetUpdateOption().(type) {
+			case *commentsvcv1.UpdateCommentRequest_UpdateField_Text:
+				comment.Text = option.Text
+				query.Column("text")
+			case *commentsvcv1.UpdateCommentRequest_UpdateField_MentionedPersonIds:
+				if err := checkMentionedPersons(ctx, tx, "update_fields.mentioned_person_ids.ids", option.MentionedPersonIds.GetIds(), currentComment.GetGroupId()); err != nil {
+					return err
+				}
+				comment.MentionedPersonIds = option.MentionedPersonIds.GetIds()
+				newlyMentionedPersonIds = missingIds(option.MentionedPersonIds.GetIds(), currentComment.GetMentionedPersonIds())
+				query.Column("mentioned_person_ids")
+			}
+		}
+		if err := util.UpdateReturning(ctx, tx, model.MatchEtag(query.Model(comment), etag), util.WherePK, append([]string{"revision"}, model.MetadataReturningColumns...)...); err != nil {
+			if eris.Is(err, sql.ErrNoRows) {
+				if etag != "" {
+					if err := model.ConcurrentEtagMismatch[*model.Comment](ctx, tx, commentId); !eris.As(err, &util.ResourceNotFoundError{}) {
+						return err
+					}
+				}
+				log.Info("comment not found", logging.Error(err))
+				return errNoCommentWithId
+			}
+			log.Error("failed updating comment", logging.Error(err))
+			return errUpdateComment
+		}
+		if err := model.RecordCommentRevision(ctx, tx, comment, now); err != nil {
+			log.Error("failed recording comment revision", logging.Error(err))
+			return errUpdateComment
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetCommentUpdatedSubject(comment.GetGroupId(), comment.GetExpenseId(), commentId), &commentprocv1.CommentUpdated{
+		Id:                      commentId,
+		ExpenseId:               comment.GetExpenseId(),
+		GroupId:                 comment.GetGroupId(),
+		AuthorId:                comment.GetAuthorId(),
+		NewlyMentionedPersonIds: newlyMentionedPersonIds,
+	}, comment.IntoProtoComment()); err != nil {
+		log.Error("failed publishing comment updated event", logging.Error(err))
+		return nil, errPublishCommentUpdated
+	}
+
+	return comment.IntoProtoComment(), nil
+}
+
+// missingIds returns the IDs that are not part of the passed existing IDs in the order they were passed in
+func missingIds(ids []string, existingIds []string) []string {
+	existing := make(map[string]bool, len(existingIds))
+	for _, id := range existingIds {
+		existing[id] = true
+	}
+	var missing []string
+	for _, id := range ids {
+		if !existing[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
@@ -20,21 +20,34 @@ var errSubscribeCurrency = eris.New("failed subscribing to currency subject")
 var errSendStreamAliveMessage = eris.New("failed sending stream alive message")
 var errSendCurrentExchangeRateMessage = eris.New("failed sending current exchange rate")
 var errCurrencyNoLongerFound = eris.New("the currency does no longer exist")
+var errInvalidExchangeRateSeriesRange = eris.New("the end of the exchange rate series is before its start")
+var errExchangeRateSeriesTooLong = eris.New("the exchange rate series is too long")
 
 type currencyServer struct {
-	dbClient       bun.IDB
-	natsClient     *nats.EncodedConn
-	currencyClient curClient.Client
+	dbClient bun.IDB
+	// dbReads is used by read-only endpoints while writes and reads within transactions always use dbClient
+	dbReads         *client.ReadRouter
+	natsClient      *nats.EncodedConn
+	currencyClient  curClient.Client
+	exchangeRateHub *exchangeRateHub
 }
 
 // NewCurrencyServer creates a new instance of currency server. The context has no effect on the server's lifecycle.
-func NewCurrencyServer(ctx context.Context, natsServer, dbUser, dbPass, dbAddr, db string) (*currencyServer, error) {
+func NewCurrencyServer(ctx context.Context, natsServer string, dbConfig client.Config) (*currencyServer, error) {
 	log := logging.FromContext(ctx).Named("NewCurrencyServer")
 	ctx = logging.IntoContext(ctx, log)
-	return NewCurrencyServerWithDBClient(
-		ctx,
-		client.NewPostgresDBClient(dbUser, dbPass, dbAddr, db),
-		natsServer)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewCurrencyServerWithDBClient(ctx, dbClient, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
 }
 
 // NewCurrencyServerWithDBClient creates a new instance of currency server. The context has no effect on the server's lifecycle.
@@ -46,14 +59,19 @@ func NewCurrencyServerWithDBClient(ctx context.Context, dbClient bun.IDB, natsSe
 		log.Error(msg, logging.Error(err))
 		return nil, eris.Wrap(err, msg)
 	}
-	return &currencyServer{
+	s := &currencyServer{
 		dbClient:       dbClient,
+		dbReads:        client.NewReadRouter(dbClient),
 		natsClient:     nc,
-		currencyClient: curClient.NewCurrencyClient(),
-	}, nil
+		currencyClient: curClient.NewCachingCurrencyClient(curClient.NewCurrencyClient()),
+	}
+	s.exchangeRateHub = newExchangeRateHub(nc.Conn, func(ctx context.Context, pair currencyPair) (float64, error) {
+		return fetchCurrentExchangeRate(ctx, s.dbReads.For("StreamExchangeRate"), s.currencyClient, pair.srcCurrencyId, pair.destCurrencyId)
+	})
+	return s, nil
 }
 
 func (rps *currencyServer) Close() error {
 	rps.natsClient.Close()
-	return nil
+	return rps.dbReads.Close()
 }
@@ -0,0 +1,180 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+)
+
+const tickerPeriod = time.Minute
+
+type currencyPair struct {
+	srcCurrencyId  string
+	destCurrencyId string
+}
+
+// exchangeRateUpdate is either the latest exchange rate of a currency pair or the error that stopped its polling
+type exchangeRateUpdate struct {
+	rate float64
+	err  error
+}
+
+type fetchExchangeRateFunc func(ctx context.Context, pair currencyPair) (float64, error)
+
+// exchangeRatePoller polls the latest exchange rate of a single currency pair on behalf of all its subscribers
+type exchangeRatePoller struct {
+	subscribers map[chan exchangeRateUpdate]struct{}
+	latest      *exchangeRateUpdate
+	cancel      context.CancelFunc
+}
+
+// exchangeRateHub shares one poll loop per currency pair across all streams subscribing to the pair.
+// A poll loop is started with the first subscription of a pair and stopped with the last unsubscription.
+type exchangeRateHub struct {
+	natsClient        *nats.Conn
+	fetchExchangeRate fetchExchangeRateFunc
+	pollers           map[currencyPair]*exchangeRatePoller
+	mutex             sync.Mutex
+}
+
+func newExchangeRateHub(natsClient *nats.Conn, fetchExchangeRate fetchExchangeRateFunc) *exchangeRateHub {
+	return &exchangeRateHub{
+		natsClient:        natsClient,
+		fetchExchangeRate: fetchExchangeRate,
+		pollers:           make(map[currencyPair]*exchangeRatePoller),
+	}
+}
+
+// subscribe returns a channel receiving the exchange rate of the pair whenever it changes and a function ending the subscription.
+// The latest known exchange rate is sent right away. After an update with an error the channel receives no more updates.
+func (h *exchangeRateHub) subscribe(ctx context.Context, pair currencyPair) (<-chan exchangeRateUpdate, func()) {
+	updates := make(chan exchangeRateUpdate, 1)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	poller, ok := h.pollers[pair]
+	if !ok {
+		// the poll loop must outlive the context of the subscribing stream since other streams may share it
+		pollCtx, cancel := context.WithCancel(logging.IntoContext(context.Background(), logging.FromContext(ctx)))
+		poller = &exchangeRatePoller{
+			subscribers: make(map[chan exchangeRateUpdate]struct{}),
+			cancel:      cancel,
+		}
+		h.pollers[pair] = poller
+		go h.poll(pollCtx, pair, poller)
+	}
+	poller.subscribers[updates] = struct{}{}
+	if poller.latest != nil {
+		updates <- *poller.latest
+	}
+
+	return updates, func() {
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+
+		delete(poller.subscribers, updates)
+		if len(poller.subscribers) == 0 {
+			poller.cancel()
+			if h.pollers[pair] == poller {
+				delete(h.pollers, pair)
+			}
+		}
+	}
+}
+
+// poll fetches the exchange rate of the pair periodically and whenever one of its currencies changes until the context is done
+func (h *exchangeRateHub) poll(ctx context.Context, pair currencyPair, poller *exchangeRatePoller) {
+	log := logging.FromContext(ctx).With(
+		logging.String("srcCurrency", pair.srcCurrencyId),
+		logging.String("destCurrency", pair.destCurrencyId))
+	ctx = logging.IntoContext(ctx, log)
+
+	ticker := time.NewTicker(tickerPeriod)
+	defer ticker.Stop()
+
+	curChan := make(chan *nats.Msg)
+	for _, currencyId := range []string{pair.srcCurrencyId, pair.destCurrencyId} {
+		s := fmt.Sprintf("%s.*", environment.GetCurrencySubject(currencyId))
+		sub, err := h.natsClient.ChanSubscribe(s, curChan)
+		if err != nil {
+			log.Error("failed subscribing to currency events", logging.Error(err), logging.String("subject", s))
+			h.broadcast(pair, poller, exchangeRateUpdate{err: errSubscribeCurrency})
+			return
+		}
+		defer func() {
+			if err := sub.Unsubscribe(); err != nil {
+				log.Error("failed unsubscribing from currency events", logging.Error(err), logging.String("subject", s))
+			}
+		}()
+	}
+
+	if !h.fetch(ctx, pair, poller) {
+		return
+	}
+
+	for {
+		select {
+		case <-curChan:
+			if !h.fetch(ctx, pair, poller) {
+				return
+			}
+		case <-ticker.C:
+			if !h.fetch(ctx, pair, poller) {
+				return
+			}
+		case <-ctx.Done():
+			log.Info("no more subscribers of the exchange rate")
+			return
+		}
+	}
+}
+
+// fetch fetches the exchange rate of the pair and broadcasts it if it changed. It returns false if polling has to stop.
+func (h *exchangeRateHub) fetch(ctx context.Context, pair currencyPair, poller *exchangeRatePoller) bool {
+	rate, err := h.fetchExchangeRate(ctx, pair)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false
+		}
+		if eris.As(err, &util.ResourceNotFoundError{}) && poller.latest != nil {
+			err = eris.Wrap(errCurrencyNoLongerFound, err.Error())
+		}
+		h.broadcast(pair, poller, exchangeRateUpdate{err: err})
+		return false
+	}
+
+	h.mutex.Lock()
+	changed := poller.latest == nil || poller.latest.rate != rate
+	h.mutex.Unlock()
+	if changed {
+		h.broadcast(pair, poller, exchangeRateUpdate{rate: rate})
+	}
+	return true
+}
+
+// broadcast sends an update to all subscribers of a poller. Subscribers that did not consume
+// the previous update yet only receive the latest one. A poller broadcasting an error is removed from the hub.
+func (h *exchangeRateHub) broadcast(pair currencyPair, poller *exchangeRatePoller, update exchangeRateUpdate) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	poller.latest = &update
+	if update.err != nil && h.pollers[pair] == poller {
+		delete(h.pollers, pair)
+	}
+	for subscriber := range poller.subscribers {
+		select {
+		case <-subscriber:
+		default:
+		}
+		subscriber <- update
+	}
+}
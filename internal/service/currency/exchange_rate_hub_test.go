@@ -0,0 +1,177 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqtesting "github.com/nico151999/high-availability-expense-splitter/pkg/mq/testing"
+	"github.com/rotisserie/eris"
+)
+
+// fakeExchangeRates serves the exchange rates of currency pairs and counts how often each pair was fetched
+type fakeExchangeRates struct {
+	mutex   sync.Mutex
+	rates   map[currencyPair]float64
+	errs    map[currencyPair]error
+	fetches map[currencyPair]int
+}
+
+func (f *fakeExchangeRates) fetch(_ context.Context, pair currencyPair) (float64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.fetches[pair]++
+	if err := f.errs[pair]; err != nil {
+		return 0, err
+	}
+	return f.rates[pair], nil
+}
+
+func (f *fakeExchangeRates) set(pair currencyPair, rate float64, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.rates[pair] = rate
+	f.errs[pair] = err
+}
+
+func (f *fakeExchangeRates) fetchCount(pair currencyPair) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.fetches[pair]
+}
+
+func TestExchangeRateHub(t *testing.T) {
+	log := logging.GetLogger().Named("testExchangeRateHub")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	server, port := mqtesting.RunMQServer(-1)
+	defer server.Shutdown()
+	nc, err := nats.Connect(fmt.Sprintf("localhost:%d", port))
+	if err != nil {
+		t.Fatalf("failed connecting to NATS server: %+v", err)
+	}
+	defer nc.Close()
+
+	rates := &fakeExchangeRates{
+		rates:   make(map[currencyPair]float64),
+		errs:    make(map[currencyPair]error),
+		fetches: make(map[currencyPair]int),
+	}
+	hub := newExchangeRateHub(nc, rates.fetch)
+
+	receive := func(t *testing.T, updates <-chan exchangeRateUpdate) exchangeRateUpdate {
+		t.Helper()
+		select {
+		case update := <-updates:
+			return update
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected an exchange rate update")
+			return exchangeRateUpdate{}
+		}
+	}
+	pollerCount := func() int {
+		hub.mutex.Lock()
+		defer hub.mutex.Unlock()
+		return len(hub.pollers)
+	}
+	eventually := func(t *testing.T, condition func() bool, msg string) {
+		t.Helper()
+		for deadline := time.Now().Add(5 * time.Second); !condition(); time.Sleep(10 * time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatal(msg)
+			}
+		}
+	}
+
+	t.Run("Subscribers of a pair share a single poller", func(t *testing.T) {
+		pair := currencyPair{srcCurrencyId: "currency-123456789012345", destCurrencyId: "currency-543210987654321"}
+		rates.set(pair, 1.5, nil)
+
+		first, unsubscribeFirst := hub.subscribe(ctx, pair)
+		if update := receive(t, first); update.err != nil || update.rate != 1.5 {
+			t.Fatalf("expected the rate 1.5; got: %+v", update)
+		}
+		second, unsubscribeSecond := hub.subscribe(ctx, pair)
+		// the latest rate is sent to later subscribers right away instead of being fetched again
+		if update := receive(t, second); update.err != nil || update.rate != 1.5 {
+			t.Fatalf("expected the rate 1.5; got: %+v", update)
+		}
+		if count := rates.fetchCount(pair); count != 1 {
+			t.Errorf("expected the rate to be fetched once; got: %d", count)
+		}
+		if count := pollerCount(); count != 1 {
+			t.Errorf("expected a single poller; got: %d", count)
+		}
+
+		unsubscribeFirst()
+		if count := pollerCount(); count != 1 {
+			t.Errorf("expected the poller to remain while the pair has subscribers; got: %d", count)
+		}
+		unsubscribeSecond()
+		if count := pollerCount(); count != 0 {
+			t.Errorf("expected the poller to be removed with the last subscriber; got: %d", count)
+		}
+	})
+
+	t.Run("Changes of a currency are fanned out to all subscribers", func(t *testing.T) {
+		pair := currencyPair{srcCurrencyId: "currency-234567890123456", destCurrencyId: "currency-654321098765432"}
+		rates.set(pair, 2, nil)
+
+		first, unsubscribeFirst := hub.subscribe(ctx, pair)
+		defer unsubscribeFirst()
+		second, unsubscribeSecond := hub.subscribe(ctx, pair)
+		defer unsubscribeSecond()
+		// the poller subscribed to the currency events before it fetched the rate received first
+		receive(t, first)
+		receive(t, second)
+
+		rates.set(pair, 2.5, nil)
+		if err := nc.Publish(fmt.Sprintf("%s.updated", environment.GetCurrencySubject(pair.destCurrencyId)), nil); err != nil {
+			t.Fatalf("failed publishing currency event: %+v", err)
+		}
+		for _, updates := range []<-chan exchangeRateUpdate{first, second} {
+			if update := receive(t, updates); update.err != nil || update.rate != 2.5 {
+				t.Errorf("expected the rate 2.5; got: %+v", update)
+			}
+		}
+
+		// an unchanged rate is not broadcast again
+		if err := nc.Publish(fmt.Sprintf("%s.updated", environment.GetCurrencySubject(pair.srcCurrencyId)), nil); err != nil {
+			t.Fatalf("failed publishing currency event: %+v", err)
+		}
+		eventually(t, func() bool { return rates.fetchCount(pair) == 3 }, "expected the rate to be fetched again")
+		select {
+		case update := <-first:
+			t.Errorf("expected no update of an unchanged rate; got: %+v", update)
+		default:
+		}
+	})
+
+	t.Run("Polling stops once fetching fails", func(t *testing.T) {
+		pair := currencyPair{srcCurrencyId: "currency-345678901234567", destCurrencyId: "currency-765432109876543"}
+		errFetch := eris.New("upstream unavailable")
+		rates.set(pair, 0, errFetch)
+
+		updates, unsubscribe := hub.subscribe(ctx, pair)
+		defer unsubscribe()
+		if update := receive(t, updates); !eris.Is(update.err, errFetch) {
+			t.Errorf("expected the fetch error; got: %+v", update)
+		}
+		if count := pollerCount(); count != 0 {
+			t.Errorf("expected the failed poller to be removed; got: %d", count)
+		}
+
+		// a new subscription starts polling afresh
+		rates.set(pair, 3, nil)
+		retried, unsubscribeRetried := hub.subscribe(ctx, pair)
+		defer unsubscribeRetried()
+		if update := receive(t, retried); update.err != nil || update.rate != 3 {
+			t.Errorf("expected the rate 3; got: %+v", update)
+		}
+	})
+}
@@ -5,8 +5,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	currencyv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/currency/v1"
 	currencysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/currency/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
@@ -26,7 +26,7 @@ func (s *currencyServer) GetCurrency(ctx context.Context, req *connect.Request[c
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	currency, err := util.CheckResourceExists[*currencyv1.Currency](ctx, s.dbClient, req.Msg.GetId())
+	currency, err := util.CheckResourceExists[*model.Currency](ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetId())
 	if err != nil {
 		if eris.Is(err, util.ErrSelectResource) {
 			return nil, errors.NewErrorWithDetails(
@@ -47,6 +47,6 @@ func (s *currencyServer) GetCurrency(ctx context.Context, req *connect.Request[c
 	}
 
 	return connect.NewResponse(&currencysvcv1.GetCurrencyResponse{
-		Currency: currency,
+		Currency: currency.IntoProtoCurrency(),
 	}), nil
 }
@@ -32,7 +32,7 @@ func (s *currencyServer) GetExchangeRate(ctx context.Context, req *connect.Reque
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	rate, err := getExchangeRate(ctx, s.dbClient, s.currencyClient, req.Msg)
+	rate, err := getExchangeRate(ctx, s.dbReads.For(req.Spec().Procedure), s.currencyClient, req.Msg)
 	if err != nil {
 		if eris.Is(err, util.ErrSelectResource) {
 			return nil, errors.NewErrorWithDetails(
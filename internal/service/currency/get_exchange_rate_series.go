@@ -0,0 +1,164 @@
+package currency
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	currencyv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/currency/v1"
+	currencysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/currency/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/currency/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxExchangeRateSeriesLength is the maximum number of exchange rates a series may consist of
+const maxExchangeRateSeriesLength = 366
+
+// maxConcurrentExchangeRateFetches limits the number of exchange rates of a series fetched upstream at the same time
+const maxConcurrentExchangeRateFetches = 10
+
+func (s *currencyServer) GetExchangeRateSeries(ctx context.Context, req *connect.Request[currencysvcv1.GetExchangeRateSeriesRequest]) (*connect.Response[currencysvcv1.GetExchangeRateSeriesResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"srcCurrency",
+				req.Msg.GetSourceCurrencyId()),
+			logging.String(
+				"destCurrency",
+				req.Msg.GetDestinationCurrencyId())))
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rates, err := getExchangeRateSeries(ctx, s.dbReads.For(req.Spec().Procedure), s.currencyClient, req.Msg)
+	if err != nil {
+		if eris.Is(err, errInvalidExchangeRateSeriesRange) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInvalidArgument,
+				"the requested time range is invalid",
+				[]protoreflect.ProtoMessage{
+					&errdetails.BadRequest{
+						FieldViolations: []*errdetails.BadRequest_FieldViolation{
+							{
+								Field:       "to",
+								Description: "must not be before from",
+							},
+						},
+					},
+				})
+		} else if eris.Is(err, errExchangeRateSeriesTooLong) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInvalidArgument,
+				"the requested series is too long",
+				[]protoreflect.ProtoMessage{
+					&errdetails.BadRequest{
+						FieldViolations: []*errdetails.BadRequest_FieldViolation{
+							{
+								Field:       "interval_days",
+								Description: "the time range divided by the interval must not exceed the maximum series length",
+							},
+						},
+					},
+				})
+		} else if eris.Is(err, util.ErrSelectResource) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if resErr := new(util.ResourceNotFoundError); eris.As(err, resErr) {
+			return nil, connect.NewError(connect.CodeNotFound, eris.Errorf("the %s with ID %s does not exist", resErr.ResourceName, resErr.ResourceId))
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&currencysvcv1.GetExchangeRateSeriesResponse{
+		Rates: rates,
+	}), nil
+}
+
+func getExchangeRateSeries(ctx context.Context, db bun.IDB, curClient client.Client, msg *currencysvcv1.GetExchangeRateSeriesRequest) ([]*currencysvcv1.GetExchangeRateSeriesResponse_ExchangeRate, error) {
+	log := logging.FromContext(ctx)
+
+	from := truncateToDay(msg.GetFrom().AsTime())
+	to := truncateToDay(msg.GetTo().AsTime())
+	if to.Before(from) {
+		log.Info("the end of the requested time range is before its start")
+		return nil, errInvalidExchangeRateSeriesRange
+	}
+	intervalDays := int(msg.GetIntervalDays())
+	if intervalDays == 0 {
+		intervalDays = 1
+	}
+	length := int(to.Sub(from)/(24*time.Hour))/intervalDays + 1
+	if length > maxExchangeRateSeriesLength {
+		log.Info("the requested exchange rate series is too long", logging.Int("length", length))
+		return nil, errExchangeRateSeriesTooLong
+	}
+
+	src, err := util.CheckResourceExists[*currencyv1.Currency](ctx, db, msg.GetSourceCurrencyId())
+	if err != nil {
+		return nil, err
+	}
+	dest, err := util.CheckResourceExists[*currencyv1.Currency](ctx, db, msg.GetDestinationCurrencyId())
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make([]*currencysvcv1.GetExchangeRateSeriesResponse_ExchangeRate, length)
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentExchangeRateFetches)
+	for i := range rates {
+		index := i
+		date := from.AddDate(0, 0, index*intervalDays)
+		g.Go(func() error {
+			rate, err := curClient.GetExchangeRate(gCtx, src.GetAcronym(), dest.GetAcronym(), date)
+			if err != nil {
+				if eris.Is(err, client.ErrCurrencyExchangeRateNotFound) {
+					// days without a known exchange rate are omitted
+					return nil
+				}
+				return err
+			}
+			rates[index] = &currencysvcv1.GetExchangeRateSeriesResponse_ExchangeRate{
+				Timestamp: timestamppb.New(date),
+				Rate:      rate,
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		log.Error("failed fetching exchange rate series", logging.Error(err))
+		return nil, err
+	}
+
+	series := make([]*currencysvcv1.GetExchangeRateSeriesResponse_ExchangeRate, 0, len(rates))
+	for _, rate := range rates {
+		if rate != nil {
+			series = append(series, rate)
+		}
+	}
+	return series, nil
+}
+
+func truncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
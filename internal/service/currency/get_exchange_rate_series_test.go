@@ -0,0 +1,148 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	currencysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/currency/v1"
+	curClient "github.com/nico151999/high-availability-expense-splitter/pkg/currency/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeCurrencyClient serves the exchange rates of the days it is asked for by the passed function
+type fakeCurrencyClient struct {
+	exchangeRate func(date time.Time) (float64, error)
+	requests     atomic.Int32
+}
+
+var _ curClient.Client = (*fakeCurrencyClient)(nil)
+
+func (c *fakeCurrencyClient) FetchCurrencies(context.Context) (map[string]string, error) {
+	return nil, eris.New("not implemented")
+}
+
+func (c *fakeCurrencyClient) GetExchangeRate(_ context.Context, _ string, _ string, date time.Time) (float64, error) {
+	c.requests.Add(1)
+	return c.exchangeRate(date)
+}
+
+func (c *fakeCurrencyClient) GetLatestExchangeRate(context.Context, string, string) (float64, error) {
+	return 0, eris.New("not implemented")
+}
+
+func TestGetExchangeRateSeries(t *testing.T) {
+	log := logging.GetLogger().Named("testGetExchangeRateSeries")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	bunDb := bun.NewDB(db, pgdialect.New())
+
+	srcCurrencyId := "currency-123456789012345"
+	destCurrencyId := "currency-543210987654321"
+	from := time.Date(2023, time.January, 1, 12, 30, 0, 0, time.UTC)
+	expectCurrencies := func() {
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "currencies" (.+) WHERE (.+)"id" = '%s'(.+)`, srcCurrencyId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "acronym"}).
+				FromCSVString(fmt.Sprintf("%s,EUR", srcCurrencyId)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "currencies" (.+) WHERE (.+)"id" = '%s'(.+)`, destCurrencyId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "acronym"}).
+				FromCSVString(fmt.Sprintf("%s,USD", destCurrencyId)))
+	}
+	request := func(to time.Time, intervalDays uint32) *currencysvcv1.GetExchangeRateSeriesRequest {
+		return &currencysvcv1.GetExchangeRateSeriesRequest{
+			SourceCurrencyId:      srcCurrencyId,
+			DestinationCurrencyId: destCurrencyId,
+			From:                  timestamppb.New(from),
+			To:                    timestamppb.New(to),
+			IntervalDays:          intervalDays,
+		}
+	}
+
+	t.Run("Get the longest series of daily exchange rates successfully", func(t *testing.T) {
+		expectCurrencies()
+		client := &fakeCurrencyClient{exchangeRate: func(date time.Time) (float64, error) {
+			return float64(date.YearDay()), nil
+		}}
+		series, err := getExchangeRateSeries(ctx, bunDb, client, request(from.AddDate(0, 0, maxExchangeRateSeriesLength-1), 0))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		if len(series) != maxExchangeRateSeriesLength {
+			t.Fatalf("expected %d exchange rates; got: %d", maxExchangeRateSeriesLength, len(series))
+		}
+		for i, rate := range series {
+			date := time.Date(2023, time.January, 1+i, 0, 0, 0, 0, time.UTC)
+			if !rate.GetTimestamp().AsTime().Equal(date) || rate.GetRate() != float64(date.YearDay()) {
+				t.Fatalf("expected the rate of %s at index %d; got: %+v", date, i, rate)
+			}
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Get a series of exchange rates at an interval omitting days without exchange rate successfully", func(t *testing.T) {
+		expectCurrencies()
+		client := &fakeCurrencyClient{exchangeRate: func(date time.Time) (float64, error) {
+			if date.Weekday() == time.Sunday {
+				return 0, curClient.ErrCurrencyExchangeRateNotFound
+			}
+			return 1.1, nil
+		}}
+		// 2023-01-01 and 2023-01-15 are Sundays
+		series, err := getExchangeRateSeries(ctx, bunDb, client, request(from.AddDate(0, 0, 14), 7))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		if len(series) != 1 || !series[0].GetTimestamp().AsTime().Equal(time.Date(2023, time.January, 8, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("expected only the rate of 2023-01-08; got: %+v", series)
+		}
+		if requests := client.requests.Load(); requests != 3 {
+			t.Errorf("expected 3 exchange rates to be requested; got: %d", requests)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail getting a series exceeding the maximum length", func(t *testing.T) {
+		client := &fakeCurrencyClient{exchangeRate: func(time.Time) (float64, error) {
+			return 1, nil
+		}}
+		for _, req := range []*currencysvcv1.GetExchangeRateSeriesRequest{
+			request(from.AddDate(0, 0, maxExchangeRateSeriesLength), 0),
+			request(from.AddDate(0, 0, 2*maxExchangeRateSeriesLength), 2),
+		} {
+			if _, err := getExchangeRateSeries(ctx, bunDb, client, req); !eris.Is(err, errExchangeRateSeriesTooLong) {
+				t.Errorf("expected the series from %s to %s to be too long; got: %+v", req.GetFrom().AsTime(), req.GetTo().AsTime(), err)
+			}
+		}
+		if requests := client.requests.Load(); requests != 0 {
+			t.Errorf("expected no exchange rate to be requested; got: %d", requests)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail getting a series ending before it starts", func(t *testing.T) {
+		client := &fakeCurrencyClient{exchangeRate: func(time.Time) (float64, error) {
+			return 1, nil
+		}}
+		if _, err := getExchangeRateSeries(ctx, bunDb, client, request(from.AddDate(0, 0, -1), 0)); !eris.Is(err, errInvalidExchangeRateSeriesRange) {
+			t.Errorf("expected the range to be invalid; got: %+v", err)
+		}
+	})
+}
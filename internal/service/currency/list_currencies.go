@@ -6,7 +6,9 @@ import (
 
 	"connectrpc.com/connect"
 	currencyv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/currency/v1"
+	metadatav1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/metadata/v1"
 	currencysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/currency/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
@@ -20,7 +22,7 @@ func (s *currencyServer) ListCurrencies(ctx context.Context, req *connect.Reques
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	currencies, err := listCurrencies(ctx, s.dbClient)
+	currencies, err := listCurrencies(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetOrderBy(), req.Msg.GetFilter())
 	if err != nil {
 		if eris.Is(err, errSelectCurrencies) {
 			return nil, errors.NewErrorWithDetails(
@@ -43,14 +45,20 @@ func (s *currencyServer) ListCurrencies(ctx context.Context, req *connect.Reques
 	}), nil
 }
 
-func listCurrencies(ctx context.Context, dbClient bun.IDB) ([]*currencyv1.Currency, error) {
+func listCurrencies(ctx context.Context, dbClient bun.IDB, order *metadatav1.MetadataOrder, filter *metadatav1.MetadataFilter) ([]*currencyv1.Currency, error) {
 	log := logging.FromContext(ctx)
-	var currencies []*currencyv1.Currency
-	if err := dbClient.NewSelect().Model(&currencies).Order("acronym ASC").Scan(ctx); err != nil {
+	var currencyModels []*model.Currency
+	query := dbClient.NewSelect().Model(&currencyModels)
+	if err := model.ApplyMetadataListOptions(query, order, filter).Order("acronym ASC").Scan(ctx); err != nil {
 		log.Error("failed getting currencies", logging.Error(err))
 		// TODO: determine reason why currencies couldn't be fetched and return error-specific ErrVariable; e.g. use unit testing with dummy return values to determine potential return values unless there is something in the bun documentation
 		return nil, errSelectCurrencies
 	}
 
+	currencies := make([]*currencyv1.Currency, len(currencyModels))
+	for i, currency := range currencyModels {
+		currencies[i] = currency.IntoProtoCurrency()
+	}
+
 	return currencies, nil
 }
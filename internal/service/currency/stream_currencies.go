@@ -8,6 +8,7 @@ import (
 	"connectrpc.com/connect"
 	currencyv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/currency/v1"
 	currencysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/currency/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
@@ -27,7 +28,7 @@ func (s *currencyServer) StreamCurrencies(ctx context.Context, req *connect.Requ
 	defer cancel()
 
 	if err := service.StreamResource(ctx, s.natsClient.Conn, fmt.Sprintf("%s.*", environment.GetCurrencySubject("*")), func(ctx context.Context) (*currencysvcv1.StreamCurrenciesResponse, error) {
-		return sendCurrentCurrencies(ctx, s.dbClient)
+		return sendCurrentCurrencies(ctx, s.dbReads.For(req.Spec().Procedure))
 	}, srv, &streamCurrencyIdsAlive); err != nil {
 		if eris.Is(err, errSelectCurrencies) {
 			return errors.NewErrorWithDetails(
@@ -84,12 +85,17 @@ func (s *currencyServer) StreamCurrencies(ctx context.Context, req *connect.Requ
 func sendCurrentCurrencies(ctx context.Context, dbClient bun.IDB) (*currencysvcv1.StreamCurrenciesResponse, error) {
 	log := logging.FromContext(ctx)
 
-	var currencies []*currencyv1.Currency
-	if err := dbClient.NewSelect().Model(&currencies).Order("acronym ASC").Scan(ctx); err != nil {
+	var currencyModels []*model.Currency
+	if err := dbClient.NewSelect().Model(&currencyModels).Order("acronym ASC").Scan(ctx); err != nil {
 		log.Error("failed getting currencies", logging.Error(err))
 		// TODO: determine reason why currencies couldn't be fetched and return error-specific ErrVariable; e.g. use unit testing with dummy return values to determine potential return values unless there is something in the bun documentation
 		return nil, errSelectCurrencies
 	}
+
+	currencies := make([]*currencyv1.Currency, len(currencyModels))
+	for i, currency := range currencyModels {
+		currencies[i] = currency.IntoProtoCurrency()
+	}
 	return &currencysvcv1.StreamCurrenciesResponse{
 		Update: &currencysvcv1.StreamCurrenciesResponse_Currencies_{
 			Currencies: &currencysvcv1.StreamCurrenciesResponse_Currencies{
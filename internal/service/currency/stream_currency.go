@@ -6,8 +6,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	currencyv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/currency/v1"
 	currencysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/currency/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
@@ -36,7 +36,7 @@ func (s *currencyServer) StreamCurrency(ctx context.Context, req *connect.Reques
 
 	streamSubject := fmt.Sprintf("%s.*", environment.GetCurrencySubject(req.Msg.GetId()))
 	if err := service.StreamResource(ctx, s.natsClient.Conn, streamSubject, func(ctx context.Context) (*currencysvcv1.StreamCurrencyResponse, error) {
-		return sendCurrentCurrency(ctx, s.dbClient, req.Msg.GetId())
+		return sendCurrentCurrency(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetId())
 	}, srv, &streamCurrencyAlive); err != nil {
 		if eris.Is(err, service.ErrResourceNoLongerFound) {
 			return connect.NewError(
@@ -88,13 +88,13 @@ func (s *currencyServer) StreamCurrency(ctx context.Context, req *connect.Reques
 }
 
 func sendCurrentCurrency(ctx context.Context, dbClient bun.IDB, currencyId string) (*currencysvcv1.StreamCurrencyResponse, error) {
-	currency, err := util.CheckResourceExists[*currencyv1.Currency](ctx, dbClient, currencyId)
+	currency, err := util.CheckResourceExists[*model.Currency](ctx, dbClient, currencyId)
 	if err != nil {
 		return nil, err
 	}
 	return &currencysvcv1.StreamCurrencyResponse{
 		Update: &currencysvcv1.StreamCurrencyResponse_Currency{
-			Currency: currency,
+			Currency: currency.IntoProtoCurrency(),
 		},
 	}, nil
 }
@@ -2,12 +2,9 @@ package currency
 
 import (
 	"context"
-	"fmt"
-	"sync"
 	"time"
 
 	"connectrpc.com/connect"
-	"github.com/nats-io/nats.go"
 	currencyv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/currency/v1"
 	currencysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/currency/v1"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
@@ -21,27 +18,6 @@ import (
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
-type exchangeRate struct {
-	exchangeRate  float64
-	changeChannel chan float64
-	mutex         sync.Mutex
-}
-
-// update updates the exchange rate to the new value.
-// If it changed it writes the new value to the channel and returns true, otherwise false.
-func (er *exchangeRate) update(val float64) bool {
-	if val != er.exchangeRate {
-		er.mutex.Lock()
-		er.exchangeRate = val
-		er.mutex.Unlock()
-		er.changeChannel <- val
-		return true
-	}
-	return false
-}
-
-const tickerPeriod = time.Minute
-
 func (s *currencyServer) StreamExchangeRate(
 	ctx context.Context,
 	req *connect.Request[currencysvcv1.StreamExchangeRateRequest],
@@ -58,142 +34,131 @@ func (s *currencyServer) StreamExchangeRate(
 	ctx, cancel := context.WithTimeout(ctx, time.Hour)
 	defer cancel()
 
-	err := streamCurrentExchangeRate(
+	pair := currencyPair{
+		srcCurrencyId:  req.Msg.GetSourceCurrencyId(),
+		destCurrencyId: req.Msg.GetDestinationCurrencyId(),
+	}
+	if err := streamExchangeRates(
 		ctx,
-		srv,
-		s.natsClient.Conn,
-		s.dbClient,
-		s.currencyClient,
-		req.Msg.GetSourceCurrencyId(),
-		req.Msg.GetDestinationCurrencyId())
-	if err != nil {
-		if eris.Is(err, errCurrencyNoLongerFound) {
-			return connect.NewError(
-				connect.CodeDataLoss,
-				eris.New("the currency does no longer exist"))
-		} else if nfErr := new(util.ResourceNotFoundError); eris.As(err, nfErr) {
-			return connect.NewError(
-				connect.CodeNotFound,
-				eris.Errorf("the %s %s does not exist", nfErr.ResourceName, nfErr.ResourceId))
-		} else if eris.Is(err, errSubscribeCurrency) {
-			return errors.NewErrorWithDetails(
-				ctx,
-				connect.CodeInternal,
-				"failed subscribing to updates",
-				[]protoreflect.ProtoMessage{
-					&errdetails.ErrorInfo{
-						Reason: environment.GetMessageSubscriptionErrorReason(ctx),
-						Domain: environment.GetGlobalDomain(ctx),
-					},
-				})
-		} else if eris.Is(err, errSendCurrentExchangeRateMessage) {
-			return errors.NewErrorWithDetails(
-				ctx,
-				connect.CodeCanceled,
-				"failed returning current resource",
-				[]protoreflect.ProtoMessage{
-					&errdetails.ErrorInfo{
-						Reason: environment.GetSendCurrentResourceErrorReason(ctx),
-						Domain: environment.GetGlobalDomain(ctx),
-					},
-				})
-		} else if eris.Is(err, errSendStreamAliveMessage) {
-			return errors.NewErrorWithDetails(
-				ctx,
-				connect.CodeCanceled,
-				"failed sending alive message to client",
-				[]protoreflect.ProtoMessage{
-					&errdetails.ErrorInfo{
-						Reason: environment.GetSendStreamAliveErrorReason(ctx),
-						Domain: environment.GetGlobalDomain(ctx),
-					},
-				})
-		} else {
-			return connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
-		}
+		s.exchangeRateHub,
+		[]currencyPair{pair},
+		func(ctx context.Context, _ currencyPair, rate float64) error {
+			return sendCurrentExchangeRate(ctx, srv, rate)
+		},
+		func(ctx context.Context) error {
+			return sendExchangeRateAlive(ctx, srv)
+		}); err != nil {
+		return exchangeRateStreamError(ctx, err)
 	}
 
 	return nil
 }
 
-func streamCurrentExchangeRate(
+// exchangeRateStreamError maps an error returned by streamExchangeRates to a connect error
+func exchangeRateStreamError(ctx context.Context, err error) error {
+	if eris.Is(err, errCurrencyNoLongerFound) {
+		return connect.NewError(
+			connect.CodeDataLoss,
+			eris.New("the currency does no longer exist"))
+	} else if nfErr := new(util.ResourceNotFoundError); eris.As(err, nfErr) {
+		return connect.NewError(
+			connect.CodeNotFound,
+			eris.Errorf("the %s %s does not exist", nfErr.ResourceName, nfErr.ResourceId))
+	} else if eris.Is(err, errSubscribeCurrency) {
+		return errors.NewErrorWithDetails(
+			ctx,
+			connect.CodeInternal,
+			"failed subscribing to updates",
+			[]protoreflect.ProtoMessage{
+				&errdetails.ErrorInfo{
+					Reason: environment.GetMessageSubscriptionErrorReason(ctx),
+					Domain: environment.GetGlobalDomain(ctx),
+				},
+			})
+	} else if eris.Is(err, errSendCurrentExchangeRateMessage) {
+		return errors.NewErrorWithDetails(
+			ctx,
+			connect.CodeCanceled,
+			"failed returning current resource",
+			[]protoreflect.ProtoMessage{
+				&errdetails.ErrorInfo{
+					Reason: environment.GetSendCurrentResourceErrorReason(ctx),
+					Domain: environment.GetGlobalDomain(ctx),
+				},
+			})
+	} else if eris.Is(err, errSendStreamAliveMessage) {
+		return errors.NewErrorWithDetails(
+			ctx,
+			connect.CodeCanceled,
+			"failed sending alive message to client",
+			[]protoreflect.ProtoMessage{
+				&errdetails.ErrorInfo{
+					Reason: environment.GetSendStreamAliveErrorReason(ctx),
+					Domain: environment.GetGlobalDomain(ctx),
+				},
+			})
+	} else {
+		return connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+	}
+}
+
+type currencyPairUpdate struct {
+	exchangeRateUpdate
+	pair currencyPair
+}
+
+// streamExchangeRates sends the exchange rate of each pair whenever it changes and an alive message if nothing changed for a ticker period.
+// The exchange rates are polled by the hub which shares its poll loops with all other streams subscribing to the same pairs.
+func streamExchangeRates(
 	ctx context.Context,
-	srv *connect.ServerStream[currencysvcv1.StreamExchangeRateResponse],
-	natsClient *nats.Conn,
-	dbClient bun.IDB,
-	currencyClient client.Client,
-	srcCurrencyId string,
-	destCurrencyId string) error {
+	hub *exchangeRateHub,
+	pairs []currencyPair,
+	sendRate func(ctx context.Context, pair currencyPair, rate float64) error,
+	sendAlive func(ctx context.Context) error) error {
 	log := logging.FromContext(ctx)
 
-	ticker := time.NewTicker(tickerPeriod)
-	defer ticker.Stop()
-
-	srcSubject := fmt.Sprintf("%s.*", environment.GetCurrencySubject(srcCurrencyId))
-	destSubject := fmt.Sprintf("%s.*", environment.GetCurrencySubject(destCurrencyId))
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	curChan := make(chan *nats.Msg)
-	for _, subject := range []string{srcSubject, destSubject} {
-		s := subject
-		sub, err := natsClient.ChanSubscribe(s, curChan)
-		if err != nil {
-			log.Error("failed subscribing to currency events", logging.Error(err), logging.String("subject", s))
-			return errSubscribeCurrency
-		}
-		defer func() {
-			if err := sub.Unsubscribe(); err != nil {
-				log.Error("failed unsubscribing from currency events", logging.Error(err), logging.String("subject", s))
+	updates := make(chan currencyPairUpdate)
+	for _, p := range pairs {
+		pair := p
+		pairUpdates, unsubscribe := hub.subscribe(ctx, pair)
+		defer unsubscribe()
+		go func() {
+			for {
+				select {
+				case update := <-pairUpdates:
+					select {
+					case updates <- currencyPairUpdate{exchangeRateUpdate: update, pair: pair}:
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
 			}
 		}()
 	}
 
-	latestEr := exchangeRate{
-		changeChannel: make(chan float64),
-	}
-
-	er, err := fetchCurrentExchangeRate(ctx, dbClient, currencyClient, srcCurrencyId, destCurrencyId)
-	if err != nil {
-		return err
-	}
-	latestEr.update(er)
-
-	if err := sendCurrentExchangeRate(ctx, srv, er); err != nil {
-		return err
-	}
+	ticker := time.NewTicker(tickerPeriod)
+	defer ticker.Stop()
 
 loop:
 	for {
 		select {
-		case <-curChan:
-			er, err := fetchCurrentExchangeRate(ctx, dbClient, currencyClient, srcCurrencyId, destCurrencyId)
-			if err != nil {
-				if eris.As(err, &util.ResourceNotFoundError{}) {
-					return eris.Wrap(errCurrencyNoLongerFound, err.Error())
-				}
-				return err
+		case update := <-updates:
+			if update.err != nil {
+				return update.err
 			}
-			latestEr.update(er)
-		case er := <-latestEr.changeChannel:
-			if err := sendCurrentExchangeRate(ctx, srv, er); err != nil {
+			if err := sendRate(ctx, update.pair, update.rate); err != nil {
 				return err
 			}
 			ticker.Reset(tickerPeriod)
 		case <-ticker.C:
-			er, err := fetchCurrentExchangeRate(ctx, dbClient, currencyClient, srcCurrencyId, destCurrencyId)
-			if err != nil {
-				if eris.As(err, &util.ResourceNotFoundError{}) {
-					return eris.Wrap(errCurrencyNoLongerFound, err.Error())
-				}
+			if err := sendAlive(ctx); err != nil {
 				return err
 			}
-			if !latestEr.update(er) {
-				if err := srv.Send(&currencysvcv1.StreamExchangeRateResponse{
-					Update: &currencysvcv1.StreamExchangeRateResponse_StillAlive{},
-				}); err != nil {
-					log.Error("failed sending still alive message to client", logging.Error(err))
-					return errSendStreamAliveMessage
-				}
-			}
 		case <-ctx.Done():
 			log.Info("the context is done")
 			break loop
@@ -221,6 +186,21 @@ func sendCurrentExchangeRate(
 	return nil
 }
 
+func sendExchangeRateAlive(
+	ctx context.Context,
+	srv *connect.ServerStream[currencysvcv1.StreamExchangeRateResponse]) error {
+	log := logging.FromContext(ctx)
+
+	if err := srv.Send(&currencysvcv1.StreamExchangeRateResponse{
+		Update: &currencysvcv1.StreamExchangeRateResponse_StillAlive{},
+	}); err != nil {
+		log.Error("failed sending still alive message to client", logging.Error(err))
+		return errSendStreamAliveMessage
+	}
+
+	return nil
+}
+
 func fetchCurrentExchangeRate(
 	ctx context.Context,
 	dbClient bun.IDB,
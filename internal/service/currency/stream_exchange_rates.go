@@ -0,0 +1,93 @@
+package currency
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	currencysvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/currency/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+)
+
+func (s *currencyServer) StreamExchangeRates(
+	ctx context.Context,
+	req *connect.Request[currencysvcv1.StreamExchangeRatesRequest],
+	srv *connect.ServerStream[currencysvcv1.StreamExchangeRatesResponse]) error {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.Int(
+				"pairCount",
+				len(req.Msg.GetPairs()))))
+	ctx, cancel := context.WithTimeout(ctx, time.Hour)
+	defer cancel()
+
+	pairSet := make(map[currencyPair]struct{}, len(req.Msg.GetPairs()))
+	pairs := make([]currencyPair, 0, len(req.Msg.GetPairs()))
+	for _, p := range req.Msg.GetPairs() {
+		pair := currencyPair{
+			srcCurrencyId:  p.GetSourceCurrencyId(),
+			destCurrencyId: p.GetDestinationCurrencyId(),
+		}
+		if _, ok := pairSet[pair]; ok {
+			continue
+		}
+		pairSet[pair] = struct{}{}
+		pairs = append(pairs, pair)
+	}
+
+	if err := streamExchangeRates(
+		ctx,
+		s.exchangeRateHub,
+		pairs,
+		func(ctx context.Context, pair currencyPair, rate float64) error {
+			return sendCurrentExchangeRates(ctx, srv, pair, rate)
+		},
+		func(ctx context.Context) error {
+			return sendExchangeRatesAlive(ctx, srv)
+		}); err != nil {
+		return exchangeRateStreamError(ctx, err)
+	}
+
+	return nil
+}
+
+func sendCurrentExchangeRates(
+	ctx context.Context,
+	srv *connect.ServerStream[currencysvcv1.StreamExchangeRatesResponse],
+	pair currencyPair,
+	exchangeRate float64) error {
+	log := logging.FromContext(ctx)
+
+	if err := srv.Send(&currencysvcv1.StreamExchangeRatesResponse{
+		Update: &currencysvcv1.StreamExchangeRatesResponse_Rate{
+			Rate: &currencysvcv1.StreamExchangeRatesResponse_ExchangeRate{
+				Pair: &currencysvcv1.CurrencyPair{
+					SourceCurrencyId:      pair.srcCurrencyId,
+					DestinationCurrencyId: pair.destCurrencyId,
+				},
+				Rate: exchangeRate,
+			},
+		},
+	}); err != nil {
+		log.Error("failed sending current exchange rate to client", logging.Error(err))
+		return errSendCurrentExchangeRateMessage
+	}
+
+	return nil
+}
+
+func sendExchangeRatesAlive(
+	ctx context.Context,
+	srv *connect.ServerStream[currencysvcv1.StreamExchangeRatesResponse]) error {
+	log := logging.FromContext(ctx)
+
+	if err := srv.Send(&currencysvcv1.StreamExchangeRatesResponse{
+		Update: &currencysvcv1.StreamExchangeRatesResponse_StillAlive{},
+	}); err != nil {
+		log.Error("failed sending still alive message to client", logging.Error(err))
+		return errSendStreamAliveMessage
+	}
+
+	return nil
+}
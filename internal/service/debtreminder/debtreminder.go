@@ -0,0 +1,59 @@
+package debtreminder
+
+import (
+	"context"
+
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/debtreminder/v1/debtreminderv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+var _ debtreminderv1connect.DebtReminderServiceHandler = (*debtReminderServer)(nil)
+
+var errNoDebtReminderPolicy = eris.New("there is no debt reminder policy of the group")
+var errNoDebtReminderSnooze = eris.New("there is no snooze of the person")
+var errSelectDebtReminderPolicy = eris.New("failed selecting debt reminder policy")
+var errSetDebtReminderPolicy = eris.New("failed setting debt reminder policy")
+var errDeleteDebtReminderPolicy = eris.New("failed deleting debt reminder policy")
+var errSetDebtReminderSnooze = eris.New("failed setting debt reminder snooze")
+var errDeleteDebtReminderSnooze = eris.New("failed deleting debt reminder snooze")
+var errSelectDebtReminderSnoozes = eris.New("failed selecting debt reminder snoozes")
+
+type debtReminderServer struct {
+	dbClient bun.IDB
+	// dbReads is used by read-only endpoints while writes and reads within transactions always use dbClient
+	dbReads *client.ReadRouter
+}
+
+// NewDebtReminderServer creates a new instance of debt reminder server. The context has no effect on the server's lifecycle.
+func NewDebtReminderServer(ctx context.Context, natsServer string, dbConfig client.Config) (*debtReminderServer, error) {
+	log := logging.FromContext(ctx).Named("NewDebtReminderServer")
+	ctx = logging.IntoContext(ctx, log)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewDebtReminderServerWithDBClient(ctx, dbClient, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
+}
+
+// NewDebtReminderServerWithDBClient creates a new instance of debt reminder server. The context has no effect on the server's lifecycle.
+// Like the notification server it does not connect to the NATS server since policies and snoozes are only read by the debt reminder processor.
+func NewDebtReminderServerWithDBClient(ctx context.Context, dbClient bun.IDB, _ string) (*debtReminderServer, error) {
+	return &debtReminderServer{
+		dbClient: dbClient,
+		dbReads:  client.NewReadRouter(dbClient),
+	}, nil
+}
+
+func (rps *debtReminderServer) Close() error {
+	return rps.dbReads.Close()
+}
@@ -0,0 +1,118 @@
+package debtreminder
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"connectrpc.com/connect"
+	debtremindersvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/debtreminder/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *debtReminderServer) DeleteDebtReminderPolicy(ctx context.Context, req *connect.Request[debtremindersvcv1.DeleteDebtReminderPolicyRequest]) (*connect.Response[debtremindersvcv1.DeleteDebtReminderPolicyResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"groupId",
+				req.Msg.GetGroupId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := deleteDebtReminderPolicy(ctx, s.dbClient, req.Msg.GetGroupId(), req.Msg.GetEtag()); err != nil {
+		if eris.Is(err, errSelectDebtReminderPolicy) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errDeleteDebtReminderPolicy) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBDeleteErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoDebtReminderPolicy) {
+			return nil, connect.NewError(connect.CodeNotFound, eris.New("no debt reminder policy has been set for the group"))
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the debt reminder policy was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&debtremindersvcv1.DeleteDebtReminderPolicyResponse{}), nil
+}
+
+// deleteDebtReminderPolicy deletes the debt reminder policy of the group along with the times the persons of the group were last reminded at
+// so that reminders start afresh if a policy is set again. The snoozes are kept since they were explicitly asked for.
+func deleteDebtReminderPolicy(ctx context.Context, dbClient bun.IDB, groupId string, etag string) error {
+	log := logging.FromContext(ctx)
+
+	return transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if etag != "" {
+			current, err := selectDebtReminderPolicy(ctx, tx, groupId)
+			if err != nil {
+				if eris.Is(err, sql.ErrNoRows) {
+					log.Info("debt reminder policy not found", logging.Error(err))
+					return errNoDebtReminderPolicy
+				}
+				log.Error("failed getting debt reminder policy", logging.Error(err))
+				return errSelectDebtReminderPolicy
+			}
+			if err := current.CheckEtag(etag); err != nil {
+				return err
+			}
+		}
+
+		res, err := tx.NewDelete().Model((*model.DebtReminderPolicy)(nil)).Where("group_id = ?", groupId).ApplyQueryBuilder(func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereEtag(q, etag)
+		}).Exec(ctx)
+		if err != nil {
+			log.Error("failed deleting debt reminder policy", logging.Error(err))
+			return errDeleteDebtReminderPolicy
+		}
+		if deleted, err := res.RowsAffected(); err == nil && deleted == 0 {
+			if etag != "" {
+				log.Info("debt reminder policy was modified concurrently")
+				return concurrentEtagMismatch(ctx, tx, groupId)
+			}
+			log.Info("debt reminder policy not found")
+			return errNoDebtReminderPolicy
+		}
+		if _, err := tx.NewDelete().Model((*model.DebtReminder)(nil)).Where("group_id = ?", groupId).Exec(ctx); err != nil {
+			log.Error("failed deleting debt reminders of group", logging.Error(err))
+			return errDeleteDebtReminderPolicy
+		}
+		return nil
+	})
+}
@@ -0,0 +1,92 @@
+package debtreminder
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"connectrpc.com/connect"
+	debtremindersvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/debtreminder/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *debtReminderServer) GetDebtReminderPolicy(ctx context.Context, req *connect.Request[debtremindersvcv1.GetDebtReminderPolicyRequest]) (*connect.Response[debtremindersvcv1.GetDebtReminderPolicyResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"groupId",
+				req.Msg.GetGroupId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	policy, err := getDebtReminderPolicy(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetGroupId())
+	if err != nil {
+		if eris.Is(err, errSelectDebtReminderPolicy) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoDebtReminderPolicy) {
+			return nil, connect.NewError(connect.CodeNotFound, eris.New("no debt reminder policy has been set for the group"))
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&debtremindersvcv1.GetDebtReminderPolicyResponse{
+		DebtReminderPolicy: policy.IntoProtoDebtReminderPolicy(),
+	}), nil
+}
+
+func getDebtReminderPolicy(ctx context.Context, db bun.IDB, groupId string) (*model.DebtReminderPolicy, error) {
+	log := logging.FromContext(ctx)
+
+	policy, err := selectDebtReminderPolicy(ctx, db, groupId)
+	if err != nil {
+		if eris.Is(err, sql.ErrNoRows) {
+			log.Info("debt reminder policy not found", logging.Error(err))
+			return nil, errNoDebtReminderPolicy
+		}
+		log.Error("failed getting debt reminder policy", logging.Error(err))
+		return nil, errSelectDebtReminderPolicy
+	}
+	return policy, nil
+}
+
+// selectDebtReminderPolicy returns the debt reminder policy of the group or an error wrapping sql.ErrNoRows if the group has none
+func selectDebtReminderPolicy(ctx context.Context, db bun.IDB, groupId string) (*model.DebtReminderPolicy, error) {
+	policy := &model.DebtReminderPolicy{}
+	if err := db.NewSelect().Model(policy).Where("group_id = ?", groupId).Limit(1).Scan(ctx); err != nil {
+		return nil, eris.Wrap(err, "failed selecting debt reminder policy")
+	}
+	return policy, nil
+}
+
+// concurrentEtagMismatch returns an EtagMismatchError with the current etag of the debt reminder policy of the group after an update or
+// delete restricted by the checked etag matched no row
+func concurrentEtagMismatch(ctx context.Context, db bun.IDB, groupId string) error {
+	current, err := selectDebtReminderPolicy(ctx, db, groupId)
+	if err != nil {
+		if eris.Is(err, sql.ErrNoRows) {
+			return errNoDebtReminderPolicy
+		}
+		return errSelectDebtReminderPolicy
+	}
+	return model.EtagMismatchError{
+		CurrentEtag: current.Etag(),
+	}
+}
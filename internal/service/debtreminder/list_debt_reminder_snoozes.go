@@ -0,0 +1,71 @@
+package debtreminder
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	debtreminderv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/debtreminder/v1"
+	debtremindersvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/debtreminder/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *debtReminderServer) ListDebtReminderSnoozes(ctx context.Context, req *connect.Request[debtremindersvcv1.ListDebtReminderSnoozesRequest]) (*connect.Response[debtremindersvcv1.ListDebtReminderSnoozesResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"groupId",
+				req.Msg.GetGroupId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	snoozes, err := listDebtReminderSnoozes(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetGroupId())
+	if err != nil {
+		if eris.Is(err, errSelectDebtReminderSnoozes) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&debtremindersvcv1.ListDebtReminderSnoozesResponse{
+		DebtReminderSnoozes: snoozes,
+	}), nil
+}
+
+// listDebtReminderSnoozes returns the snoozes of the group which have not expired yet ordered by the time they last until
+func listDebtReminderSnoozes(ctx context.Context, db bun.IDB, groupId string) ([]*debtreminderv1.DebtReminderSnooze, error) {
+	log := logging.FromContext(ctx)
+
+	var snoozes []*model.DebtReminderSnooze
+	if err := db.NewSelect().Model(&snoozes).
+		Where("group_id = ?", groupId).
+		Where("snooze_until > ?", time.Now()).
+		Order("snooze_until ASC", "person_id ASC").
+		Scan(ctx); err != nil {
+		log.Error("failed selecting debt reminder snoozes", logging.Error(err))
+		return nil, errSelectDebtReminderSnoozes
+	}
+	protoSnoozes := make([]*debtreminderv1.DebtReminderSnooze, len(snoozes))
+	for i, snooze := range snoozes {
+		protoSnoozes[i] = snooze.IntoProtoDebtReminderSnooze()
+	}
+	return protoSnoozes, nil
+}
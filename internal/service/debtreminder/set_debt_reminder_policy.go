@@ -0,0 +1,138 @@
+package debtreminder
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"connectrpc.com/connect"
+	debtreminderv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/debtreminder/v1"
+	debtremindersvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/debtreminder/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *debtReminderServer) SetDebtReminderPolicy(ctx context.Context, req *connect.Request[debtremindersvcv1.SetDebtReminderPolicyRequest]) (*connect.Response[debtremindersvcv1.SetDebtReminderPolicyResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"groupId",
+				req.Msg.GetGroupId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	policy, err := setDebtReminderPolicy(ctx, s.dbClient, req.Msg)
+	if err != nil {
+		if eris.Is(err, errSelectDebtReminderPolicy) || eris.Is(err, util.ErrSelectResource) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errSetDebtReminderPolicy) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBUpdateErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoDebtReminderPolicy) {
+			return nil, connect.NewError(connect.CodeNotFound, eris.New("the debt reminder policy the etag belongs to no longer exists"))
+		} else if refErr := new(util.InvalidReferenceError); eris.As(err, refErr) {
+			return nil, errors.NewFieldViolationError(ctx, "the request references an invalid resource", refErr.Field, refErr.Description())
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the debt reminder policy was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&debtremindersvcv1.SetDebtReminderPolicyResponse{
+		DebtReminderPolicy: policy.IntoProtoDebtReminderPolicy(),
+	}), nil
+}
+
+// setDebtReminderPolicy creates the debt reminder policy of the group or replaces it
+func setDebtReminderPolicy(ctx context.Context, dbClient bun.IDB, req *debtremindersvcv1.SetDebtReminderPolicyRequest) (*model.DebtReminderPolicy, error) {
+	log := logging.FromContext(ctx)
+
+	var policy *model.DebtReminderPolicy
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := util.CheckReference[*model.Group](ctx, tx, "group_id", req.GetGroupId()); err != nil {
+			return err
+		}
+
+		current, err := selectDebtReminderPolicy(ctx, tx, req.GetGroupId())
+		if err != nil && !eris.Is(err, sql.ErrNoRows) {
+			log.Error("failed getting debt reminder policy", logging.Error(err))
+			return errSelectDebtReminderPolicy
+		}
+
+		now := time.Now()
+		policy = model.NewDebtReminderPolicy(&debtreminderv1.DebtReminderPolicy{
+			GroupId:                  req.GetGroupId(),
+			ThresholdMainValue:       req.GetThresholdMainValue(),
+			ThresholdFractionalValue: req.GetThresholdFractionalValue(),
+			MinAgeDays:               req.GetMinAgeDays(),
+			RepeatIntervalDays:       req.GetRepeatIntervalDays(),
+		}, model.NewCreatedMetadata(ctx, now))
+		if current == nil {
+			if req.GetEtag() != "" {
+				log.Info("debt reminder policy the etag belongs to not found")
+				return errNoDebtReminderPolicy
+			}
+			if _, err := tx.NewInsert().Model(policy).Exec(ctx); err != nil {
+				log.Error("failed inserting debt reminder policy", logging.Error(err))
+				return errSetDebtReminderPolicy
+			}
+			return nil
+		}
+		if err := current.CheckEtag(req.GetEtag()); err != nil {
+			return err
+		}
+		policy.Metadata = model.NewModifiedMetadata(ctx, now)
+		query := model.IncrementRevision(tx.NewUpdate().Model(policy).
+			Column("threshold_main_value", "threshold_fractional_value", "min_age_days", "repeat_interval_days").
+			Column(model.MetadataUpdateColumns...))
+		if err := util.UpdateReturning(ctx, tx, model.MatchEtag(query, req.GetEtag()), util.WherePK, append([]string{"revision"}, model.MetadataReturningColumns...)...); err != nil {
+			if eris.Is(err, sql.ErrNoRows) && req.GetEtag() != "" {
+				log.Info("debt reminder policy was modified concurrently", logging.Error(err))
+				return concurrentEtagMismatch(ctx, tx, req.GetGroupId())
+			}
+			log.Error("failed updating debt reminder policy", logging.Error(err))
+			return errSetDebtReminderPolicy
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
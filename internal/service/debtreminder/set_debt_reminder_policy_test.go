@@ -0,0 +1,162 @@
+package debtreminder_test // the dedicated _test package prevents import cycles with the testing package
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/DATA-DOG/go-sqlmock"
+	debtremindersvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/debtreminder/v1"
+	debtreminderTesting "github.com/nico151999/high-availability-expense-splitter/internal/service/debtreminder/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestSetDebtReminderPolicy(t *testing.T) {
+	log := logging.GetLogger().Named("testSetDebtReminderPolicy")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	client, _, closeServer := debtreminderTesting.SetupDebtReminderTest(t, ctx, bun.NewDB(db, pgdialect.New()))
+	// we want to close the server only which cascadingly closes the client as well
+	defer func() {
+		if err := closeServer(); err != nil {
+			t.Errorf("failed closing debt reminder server: %+v", err)
+		}
+	}()
+
+	groupId := "group-123456789012345"
+	expectCode := func(t *testing.T, err error, code connect.Code) {
+		if connectErr := new(connect.Error); eris.As(err, &connectErr) {
+			if connectErr.Code() != code {
+				t.Fatalf("Expected code: %+v; got: %+v", code, connectErr.Code())
+			}
+		} else {
+			t.Fatalf("Expected connect error, got: %+v", err)
+		}
+	}
+
+	t.Run("Create DebtReminderPolicy successfully", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "groups" (.+) WHERE (.+)"id" = '%s'(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).
+				FromCSVString(groupId))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "debt_reminder_policies" (.+) WHERE \(group_id = '%s'\)(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"group_id"}))
+		mock.ExpectQuery(fmt.Sprintf(`INSERT INTO "debt_reminder_policies" (.+)'%s', 20, 50, 14, 7(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"revision"}).
+				FromCSVString("1"))
+		mock.ExpectCommit()
+		resp, err := client.SetDebtReminderPolicy(ctx, connect.NewRequest(&debtremindersvcv1.SetDebtReminderPolicyRequest{
+			GroupId:                  groupId,
+			ThresholdMainValue:       20,
+			ThresholdFractionalValue: 50,
+			MinAgeDays:               14,
+			RepeatIntervalDays:       7,
+		}))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		policy := resp.Msg.GetDebtReminderPolicy()
+		if policy.GetGroupId() != groupId {
+			t.Errorf("Expected the policy to belong to group %s; got: %s", groupId, policy.GetGroupId())
+		}
+		if policy.GetEtag() != `"1"` {
+			t.Errorf("Expected the etag of the first revision; got: %s", policy.GetEtag())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail replacing DebtReminderPolicy due to outdated etag", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "groups" (.+) WHERE (.+)"id" = '%s'(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).
+				FromCSVString(groupId))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "debt_reminder_policies" (.+) WHERE \(group_id = '%s'\)(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"group_id", "revision"}).
+				FromCSVString(fmt.Sprintf("%s,2", groupId)))
+		mock.ExpectRollback()
+		resp, err := client.SetDebtReminderPolicy(ctx, connect.NewRequest(&debtremindersvcv1.SetDebtReminderPolicyRequest{
+			GroupId:            groupId,
+			MinAgeDays:         14,
+			RepeatIntervalDays: 7,
+			Etag:               `"1"`,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectCode(t, err, connect.CodeAborted)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail replacing DebtReminderPolicy due to concurrent modification", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "groups" (.+) WHERE (.+)"id" = '%s'(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).
+				FromCSVString(groupId))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "debt_reminder_policies" (.+) WHERE \(group_id = '%s'\)(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"group_id", "revision"}).
+				FromCSVString(fmt.Sprintf("%s,2", groupId)))
+		mock.ExpectQuery(`UPDATE "debt_reminder_policies" (.+) WHERE (.+)"revision" = 2(.*)`).
+			WillReturnRows(sqlmock.NewRows([]string{"revision"}))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "debt_reminder_policies" (.+) WHERE \(group_id = '%s'\)(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"group_id", "revision"}).
+				FromCSVString(fmt.Sprintf("%s,3", groupId)))
+		mock.ExpectRollback()
+		resp, err := client.SetDebtReminderPolicy(ctx, connect.NewRequest(&debtremindersvcv1.SetDebtReminderPolicyRequest{
+			GroupId:            groupId,
+			MinAgeDays:         14,
+			RepeatIntervalDays: 7,
+			Etag:               `"2"`,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectCode(t, err, connect.CodeAborted)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail setting DebtReminderPolicy due to non existent group", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "groups" (.+) WHERE (.+)"id" = '%s'(.+)`, groupId)).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+		resp, err := client.SetDebtReminderPolicy(ctx, connect.NewRequest(&debtremindersvcv1.SetDebtReminderPolicyRequest{
+			GroupId:            groupId,
+			MinAgeDays:         14,
+			RepeatIntervalDays: 7,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectCode(t, err, connect.CodeInvalidArgument)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail setting DebtReminderPolicy without minimum age", func(t *testing.T) {
+		resp, err := client.SetDebtReminderPolicy(ctx, connect.NewRequest(&debtremindersvcv1.SetDebtReminderPolicyRequest{
+			GroupId:            groupId,
+			RepeatIntervalDays: 7,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectCode(t, err, connect.CodeInvalidArgument)
+	})
+}
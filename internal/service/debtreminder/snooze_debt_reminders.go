@@ -0,0 +1,111 @@
+package debtreminder
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	debtreminderv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/debtreminder/v1"
+	debtremindersvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/debtreminder/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *debtReminderServer) SnoozeDebtReminders(ctx context.Context, req *connect.Request[debtremindersvcv1.SnoozeDebtRemindersRequest]) (*connect.Response[debtremindersvcv1.SnoozeDebtRemindersResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"groupId",
+				req.Msg.GetGroupId()),
+			logging.String(
+				"personId",
+				req.Msg.GetPersonId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	snooze, err := snoozeDebtReminders(ctx, s.dbClient, req.Msg)
+	if err != nil {
+		if eris.Is(err, errSetDebtReminderSnooze) || eris.Is(err, util.ErrSelectResource) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBUpdateErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if refErr := new(util.InvalidReferenceError); eris.As(err, refErr) {
+			return nil, errors.NewFieldViolationError(ctx, "the request references an invalid resource", refErr.Field, refErr.Description())
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&debtremindersvcv1.SnoozeDebtRemindersResponse{
+		DebtReminderSnooze: snooze.IntoProtoDebtReminderSnooze(),
+	}), nil
+}
+
+// snoozeDebtReminders creates the snooze of the person or replaces the time it lasts until
+func snoozeDebtReminders(ctx context.Context, dbClient bun.IDB, req *debtremindersvcv1.SnoozeDebtRemindersRequest) (*model.DebtReminderSnooze, error) {
+	log := logging.FromContext(ctx)
+
+	var snooze *model.DebtReminderSnooze
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := util.CheckReference[*model.Group](ctx, tx, "group_id", req.GetGroupId()); err != nil {
+			return err
+		}
+		if _, err := util.CheckGroupScopedReference[*model.Person](ctx, tx, "person_id", req.GetPersonId(), req.GetGroupId()); err != nil {
+			return err
+		}
+
+		exists, err := tx.NewSelect().Model((*model.DebtReminderSnooze)(nil)).
+			Where("group_id = ?", req.GetGroupId()).
+			Where("person_id = ?", req.GetPersonId()).
+			Exists(ctx)
+		if err != nil {
+			log.Error("failed checking existence of debt reminder snooze", logging.Error(err))
+			return errSetDebtReminderSnooze
+		}
+
+		now := time.Now()
+		snooze = &model.DebtReminderSnooze{
+			DebtReminderSnooze: debtreminderv1.DebtReminderSnooze{
+				GroupId:  req.GetGroupId(),
+				PersonId: req.GetPersonId(),
+			},
+			SnoozeUntil: req.GetSnoozeUntil().AsTime(),
+			Metadata:    model.NewCreatedMetadata(ctx, now),
+		}
+		if !exists {
+			if _, err := tx.NewInsert().Model(snooze).Exec(ctx); err != nil {
+				log.Error("failed inserting debt reminder snooze", logging.Error(err))
+				return errSetDebtReminderSnooze
+			}
+			return nil
+		}
+		snooze.Metadata = model.NewModifiedMetadata(ctx, now)
+		query := tx.NewUpdate().Model(snooze).
+			Column("snooze_until").
+			Column(model.MetadataUpdateColumns...)
+		if err := util.UpdateReturning(ctx, tx, query, util.WherePK, model.MetadataReturningColumns...); err != nil {
+			log.Error("failed updating debt reminder snooze", logging.Error(err))
+			return errSetDebtReminderSnooze
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return snooze, nil
+}
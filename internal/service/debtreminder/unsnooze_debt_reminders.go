@@ -0,0 +1,70 @@
+package debtreminder
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	debtremindersvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/debtreminder/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *debtReminderServer) UnsnoozeDebtReminders(ctx context.Context, req *connect.Request[debtremindersvcv1.UnsnoozeDebtRemindersRequest]) (*connect.Response[debtremindersvcv1.UnsnoozeDebtRemindersResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"groupId",
+				req.Msg.GetGroupId()),
+			logging.String(
+				"personId",
+				req.Msg.GetPersonId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := unsnoozeDebtReminders(ctx, s.dbClient, req.Msg.GetGroupId(), req.Msg.GetPersonId()); err != nil {
+		if eris.Is(err, errDeleteDebtReminderSnooze) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBDeleteErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoDebtReminderSnooze) {
+			return nil, connect.NewError(connect.CodeNotFound, eris.New("the person is not snoozed"))
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&debtremindersvcv1.UnsnoozeDebtRemindersResponse{}), nil
+}
+
+func unsnoozeDebtReminders(ctx context.Context, dbClient bun.IDB, groupId string, personId string) error {
+	log := logging.FromContext(ctx)
+
+	res, err := dbClient.NewDelete().Model((*model.DebtReminderSnooze)(nil)).
+		Where("group_id = ?", groupId).
+		Where("person_id = ?", personId).
+		Exec(ctx)
+	if err != nil {
+		log.Error("failed deleting debt reminder snooze", logging.Error(err))
+		return errDeleteDebtReminderSnooze
+	}
+	if deleted, err := res.RowsAffected(); err == nil && deleted == 0 {
+		log.Info("debt reminder snooze not found")
+		return errNoDebtReminderSnooze
+	}
+	return nil
+}
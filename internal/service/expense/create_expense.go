@@ -2,21 +2,21 @@ package expense
 
 import (
 	"context"
-	"database/sql"
 	"time"
 
 	"connectrpc.com/connect"
 	"github.com/nats-io/nats.go"
+	currencyv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/currency/v1"
 	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expense/v1"
-	groupv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/group/v1"
-	personv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/person/v1"
 	expenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expense/v1"
 	expensesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expense/v1"
 	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -57,6 +57,8 @@ func (s *expenseServer) CreateExpense(ctx context.Context, req *connect.Request[
 						Domain: environment.GetGlobalDomain(ctx),
 					},
 				})
+		} else if refErr := new(util.InvalidReferenceError); eris.As(err, refErr) {
+			return nil, errors.NewFieldViolationError(ctx, "the request references an invalid resource", refErr.Field, refErr.Description())
 		} else if resErr := new(util.ResourceNotFoundError); eris.As(err, resErr) {
 			return nil, connect.NewError(connect.CodeNotFound, eris.Errorf("the %s with ID %s does not exist", resErr.ResourceName, resErr.ResourceId))
 		} else {
@@ -75,19 +77,22 @@ func createExpense(ctx context.Context, nc *nats.EncodedConn, db bun.IDB, req *e
 	expenseId := util.GenerateIdWithPrefix("expense")
 	requestorEmail := "ab@c.de" // TODO: take user email from context
 
-	if err := db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		if _, err := util.CheckResourceExists[*groupv1.Group](ctx, tx, req.GetGroupId()); err != nil {
+	var name *string
+	if req != nil {
+		name = req.Name
+	}
+	var expense *model.Expense
+	if err := transaction.RunInTx(ctx, db, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := util.CheckReference[*model.Group](ctx, tx, "group_id", req.GetGroupId()); err != nil {
 			return err
 		}
-		if _, err := util.CheckResourceExists[*personv1.Person](ctx, tx, req.GetById()); err != nil {
+		if _, err := util.CheckGroupScopedReference[*model.Person](ctx, tx, "by_id", req.GetById(), req.GetGroupId()); err != nil {
 			return err
 		}
-		// TODO: also check if currency exists
-
-		var name *string
-		if req != nil {
-			name = req.Name
+		if _, err := util.CheckNonDeprecatedReference[*currencyv1.Currency](ctx, tx, "currency_id", req.GetCurrencyId()); err != nil {
+			return err
 		}
+
 		if _, err := tx.NewInsert().Model(
 			model.NewExpense(&expensev1.Expense{
 				Id:         expenseId,
@@ -96,24 +101,29 @@ func createExpense(ctx context.Context, nc *nats.EncodedConn, db bun.IDB, req *e
 				ById:       req.GetById(),
 				Timestamp:  req.GetTimestamp(),
 				CurrencyId: req.GetCurrencyId(),
-			}),
+			}, model.NewCreatedMetadata(ctx, time.Now())),
 		).Exec(ctx); err != nil {
 			log.Error("failed inserting expense", logging.Error(err))
 			return errInsertExpense
 		}
-
-		if err := nc.Publish(environment.GetExpenseCreatedSubject(req.GetGroupId(), expenseId), &expenseprocv1.ExpenseCreated{
-			Id:             expenseId,
-			GroupId:        req.GetGroupId(),
-			Name:           name,
-			RequestorEmail: requestorEmail,
-		}); err != nil {
-			log.Error("failed publishing expense created event", logging.Error(err))
-			return errPublishExpenseCreated
+		var err error
+		if expense, err = model.RecordExpenseRevision(ctx, tx, expenseId); err != nil {
+			log.Error("failed recording expense revision", logging.Error(err))
+			return errInsertExpense
 		}
 		return nil
 	}); err != nil {
 		return "", err
 	}
+
+	if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetExpenseCreatedSubject(req.GetGroupId(), expenseId), &expenseprocv1.ExpenseCreated{
+		Id:             expenseId,
+		GroupId:        req.GetGroupId(),
+		Name:           name,
+		RequestorEmail: requestorEmail,
+	}, expense.IntoProtoExpense()); err != nil {
+		log.Error("failed publishing expense created event", logging.Error(err))
+		return "", errPublishExpenseCreated
+	}
 	return expenseId, nil
 }
@@ -12,8 +12,11 @@ import (
 	expensesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expense/v1"
 	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -30,7 +33,7 @@ func (s *expenseServer) DeleteExpense(ctx context.Context, req *connect.Request[
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	if err := deleteExpense(ctx, s.natsClient, s.dbClient, req.Msg.GetId()); err != nil {
+	if err := deleteExpense(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetEtag()); err != nil {
 		if eris.Is(err, errDeleteExpense) {
 			return nil, errors.NewErrorWithDetails(
 				ctx,
@@ -46,6 +49,18 @@ func (s *expenseServer) DeleteExpense(ctx context.Context, req *connect.Request[
 			return nil, connect.NewError(
 				connect.CodeNotFound,
 				eris.New("the expense ID does not exist"))
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the expense was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
 		} else {
 			logging.FromContext(ctx).Error("Test acmaojaovdijo", logging.Error(err))
 			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
@@ -55,16 +70,30 @@ func (s *expenseServer) DeleteExpense(ctx context.Context, req *connect.Request[
 	return connect.NewResponse(&expensesvcv1.DeleteExpenseResponse{}), nil
 }
 
-func deleteExpense(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, expenseId string) error {
+func deleteExpense(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, expenseId string, etag string) error {
 	log := logging.FromContext(ctx)
 
-	return dbClient.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		expense := &expensev1.Expense{
-			Id: expenseId,
+	var expense *expensev1.Expense
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := model.CheckCurrentEtag[*model.Expense](ctx, tx, expenseId, etag); err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("expense not found", logging.Error(err))
+				return errNoExpenseWithId
+			}
+			return err
 		}
-		expenseModel := model.NewExpense(expense)
-		if err := tx.NewDelete().Model(expenseModel).WherePK().Returning("group_id").Scan(ctx); err != nil {
+		expenseModel := model.NewExpense(&expensev1.Expense{
+			Id: expenseId,
+		}, model.Metadata{})
+		if err := util.UpdateMatchedReturning(ctx, tx, model.SoftDelete(tx.NewUpdate().Model(expenseModel), expenseId, time.Now()), func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereEtag(q.WherePK(), etag)
+		}, "group_id"); err != nil {
 			if eris.Is(err, sql.ErrNoRows) {
+				if etag != "" {
+					if err := model.ConcurrentEtagMismatch[*model.Expense](ctx, tx, expenseId); !eris.As(err, &util.ResourceNotFoundError{}) {
+						return err
+					}
+				}
 				log.Info("expense not found", logging.Error(err))
 				return errNoExpenseWithId
 			}
@@ -72,14 +101,18 @@ func deleteExpense(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB,
 			return errDeleteExpense
 		}
 		expense = expenseModel.IntoProtoExpense()
-
-		if err := nc.Publish(environment.GetExpenseDeletedSubject(expense.GroupId, expenseId), &expenseprocv1.ExpenseDeleted{
-			Id:      expenseId,
-			GroupId: expense.GroupId,
-		}); err != nil {
-			log.Error("failed publishing expense deleted event", logging.Error(err))
-			return errPublishExpenseDeleted
-		}
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	if err := mqClient.PublishEvent(ctx, nc, environment.GetExpenseDeletedSubject(expense.GroupId, expenseId), &expenseprocv1.ExpenseDeleted{
+		Id:          expenseId,
+		GroupId:     expense.GroupId,
+		DeleteCause: expenseId,
+	}); err != nil {
+		log.Error("failed publishing expense deleted event", logging.Error(err))
+		return errPublishExpenseDeleted
+	}
+	return nil
 }
@@ -38,7 +38,7 @@ func TestDeleteExpense(t *testing.T) {
 		mock.ExpectBegin()
 		groupId := "group-543210987654321"
 		expenseId := "expense-123456789012345"
-		mock.ExpectQuery(fmt.Sprintf(`DELETE FROM "expenses" (.+) WHERE (.+)"id" = '%s'(.+)`, expenseId)).
+		mock.ExpectQuery(fmt.Sprintf(`UPDATE "expenses" (.+) WHERE (.+)"id" = '%s'(.+)`, expenseId)).
 			WillReturnRows(sqlmock.NewRows([]string{"group_id"}).
 				FromCSVString(groupId))
 		mock.ExpectCommit()
@@ -66,7 +66,7 @@ func TestDeleteExpense(t *testing.T) {
 	t.Run("Fail deleting Expense due to non existence", func(t *testing.T) {
 		mock.ExpectBegin()
 		expenseId := "expense-543210987654321"
-		mock.ExpectQuery(fmt.Sprintf(`DELETE FROM "expenses" (.+) WHERE (.+)"id" = '%s'(.+)`, expenseId)).WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(fmt.Sprintf(`UPDATE "expenses" (.+) WHERE (.+)"id" = '%s'(.+)`, expenseId)).WillReturnError(sql.ErrNoRows)
 		mock.ExpectRollback()
 		resp, err := client.DeleteExpense(ctx, connect.NewRequest(&expensesvcv1.DeleteExpenseRequest{
 			Id: expenseId,
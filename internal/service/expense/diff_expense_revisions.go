@@ -0,0 +1,146 @@
+package expense
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensestake/v1"
+	expensesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/diff"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func (s *expenseServer) DiffExpenseRevisions(ctx context.Context, req *connect.Request[expensesvcv1.DiffExpenseRevisionsRequest]) (*connect.Response[expensesvcv1.DiffExpenseRevisionsResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"expenseId",
+				req.Msg.GetExpenseId()),
+			logging.Int64(
+				"fromRevision",
+				req.Msg.GetFromRevision()),
+			logging.Int64(
+				"toRevision",
+				req.Msg.GetToRevision())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	resp, err := diffExpenseRevisions(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetExpenseId(), req.Msg.GetFromRevision(), req.Msg.GetToRevision())
+	if err != nil {
+		if eris.Is(err, errSelectExpenseRevisions) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoExpenseRevision) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the revision of the expense does not exist"))
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+func diffExpenseRevisions(ctx context.Context, dbClient bun.IDB, expenseId string, fromRevision int64, toRevision int64) (*expensesvcv1.DiffExpenseRevisionsResponse, error) {
+	log := logging.FromContext(ctx)
+
+	from, err := getExpenseRevision(ctx, dbClient, expenseId, fromRevision)
+	if err != nil {
+		return nil, err
+	}
+	to, err := getExpenseRevision(ctx, dbClient, expenseId, toRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	// the metadata is left out since it differs between any two revisions
+	fromFields, err := diff.Fields(from.GetExpense(), model.MetadataFields...)
+	if err != nil {
+		log.Error("failed converting expense of from revision", logging.Error(err))
+		return nil, errConvertExpenseRevision
+	}
+	toFields, err := diff.Fields(to.GetExpense(), model.MetadataFields...)
+	if err != nil {
+		log.Error("failed converting expense of to revision", logging.Error(err))
+		return nil, errConvertExpenseRevision
+	}
+	before, after := diff.Changes(fromFields, toFields)
+	resp := &expensesvcv1.DiffExpenseRevisionsResponse{}
+	if resp.ExpenseBefore, err = structpb.NewStruct(before); err != nil {
+		log.Error("failed converting changed fields of from revision", logging.Error(err))
+		return nil, errConvertExpenseRevision
+	}
+	if resp.ExpenseAfter, err = structpb.NewStruct(after); err != nil {
+		log.Error("failed converting changed fields of to revision", logging.Error(err))
+		return nil, errConvertExpenseRevision
+	}
+	resp.AddedStakes, resp.RemovedStakes = diffStakes(from.GetStakes(), to.GetStakes())
+	resp.AddedCategoryIds, resp.RemovedCategoryIds = diffIds(from.GetCategoryIds(), to.GetCategoryIds())
+	return resp, nil
+}
+
+// diffStakes returns the stakes only part of the to stakes and those only part of the from stakes. Stakes are identified
+// by their IDs since they cannot be modified.
+func diffStakes(from []*expensestakev1.ExpenseStake, to []*expensestakev1.ExpenseStake) ([]*expensestakev1.ExpenseStake, []*expensestakev1.ExpenseStake) {
+	fromIds := make(map[string]struct{}, len(from))
+	for _, s := range from {
+		fromIds[s.GetId()] = struct{}{}
+	}
+	toIds := make(map[string]struct{}, len(to))
+	var added []*expensestakev1.ExpenseStake
+	for _, s := range to {
+		toIds[s.GetId()] = struct{}{}
+		if _, ok := fromIds[s.GetId()]; !ok {
+			added = append(added, s)
+		}
+	}
+	var removed []*expensestakev1.ExpenseStake
+	for _, s := range from {
+		if _, ok := toIds[s.GetId()]; !ok {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+// diffIds returns the IDs only part of the to IDs and those only part of the from IDs
+func diffIds(from []string, to []string) ([]string, []string) {
+	fromIds := make(map[string]struct{}, len(from))
+	for _, id := range from {
+		fromIds[id] = struct{}{}
+	}
+	toIds := make(map[string]struct{}, len(to))
+	var added []string
+	for _, id := range to {
+		toIds[id] = struct{}{}
+		if _, ok := fromIds[id]; !ok {
+			added = append(added, id)
+		}
+	}
+	var removed []string
+	for _, id := range from {
+		if _, ok := toIds[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	return added, removed
+}
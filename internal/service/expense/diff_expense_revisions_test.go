@@ -0,0 +1,166 @@
+package expense_test // the dedicated _test package prevents import cycles with the testing package
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/DATA-DOG/go-sqlmock"
+	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expense/v1"
+	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensestake/v1"
+	expensesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expense/v1"
+	expenseTesting "github.com/nico151999/high-availability-expense-splitter/internal/service/expense/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDiffExpenseRevisions(t *testing.T) {
+	log := logging.GetLogger().Named("testDiffExpenseRevisions")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	client, _, closeServer := expenseTesting.SetupExpenseTest(t, ctx, bun.NewDB(db, pgdialect.New()))
+	// we want to close the server only which cascadingly closes the client as well
+	defer func() {
+		if err := closeServer(); err != nil {
+			t.Errorf("failed closing expense server: %+v", err)
+		}
+	}()
+
+	groupId := "group-543210987654321"
+	expenseId := "expense-123456789012345"
+	byId := "person-123456789012345"
+	currencyId := "currency-135791357913579"
+	expectRevision := func(t *testing.T, revision int64, name string, creator string, stakeIds []string, categoryIds []string) {
+		t.Helper()
+		stakes := make([]*expensestakev1.ExpenseStake, len(stakeIds))
+		for i, id := range stakeIds {
+			stakes[i] = &expensestakev1.ExpenseStake{
+				Id:        id,
+				ExpenseId: expenseId,
+				ForId:     byId,
+				MainValue: 5,
+			}
+		}
+		content, err := protojson.Marshal(&expensev1.ExpenseRevision{
+			Expense: &expensev1.Expense{
+				Id:         expenseId,
+				GroupId:    groupId,
+				Name:       proto.String(name),
+				ById:       byId,
+				CurrencyId: currencyId,
+				Creator:    "ab@c.de",
+				// the last modifier and etag differ between revisions but are no change of the expense
+				LastModifier: creator,
+				Etag:         fmt.Sprintf(`"%d"`, revision),
+			},
+			Stakes:      stakes,
+			CategoryIds: categoryIds,
+		})
+		if err != nil {
+			t.Fatalf("failed marshalling expense revision: %+v", err)
+		}
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "expense_revisions" (.+) WHERE (.+)"expense_id" = '%s'(.+)"revision" = %d(.+)`, expenseId, revision)).
+			WillReturnRows(sqlmock.NewRows([]string{"expense_id", "revision", "group_id", "creator", "content_json"}).
+				AddRow(expenseId, revision, groupId, creator, string(content)))
+	}
+
+	t.Run("Diff Expense revisions successfully", func(t *testing.T) {
+		removedStakeId := "expensestake-123456789012345"
+		keptStakeId := "expensestake-135791357913579"
+		addedStakeId := "expensestake-543210987654321"
+		keptCategoryId := "category-123456789012345"
+		addedCategoryId := "category-543210987654321"
+		expectRevision(t, 1, "first-name", "ab@c.de", []string{removedStakeId, keptStakeId}, []string{keptCategoryId})
+		expectRevision(t, 3, "third-name", "cd@e.fg", []string{keptStakeId, addedStakeId}, []string{addedCategoryId, keptCategoryId})
+		resp, err := client.DiffExpenseRevisions(ctx, connect.NewRequest(&expensesvcv1.DiffExpenseRevisionsRequest{
+			ExpenseId:    expenseId,
+			FromRevision: 1,
+			ToRevision:   3,
+		}))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		before := resp.Msg.GetExpenseBefore().AsMap()
+		after := resp.Msg.GetExpenseAfter().AsMap()
+		if len(before) != 1 || before["name"] != "first-name" {
+			t.Errorf("expected only the name of the from revision to be changed; got: %+v", before)
+		}
+		if len(after) != 1 || after["name"] != "third-name" {
+			t.Errorf("expected only the name of the to revision to be changed; got: %+v", after)
+		}
+		if added := resp.Msg.GetAddedStakes(); len(added) != 1 || added[0].GetId() != addedStakeId {
+			t.Errorf("expected stake %s to be added; got: %+v", addedStakeId, added)
+		}
+		if removed := resp.Msg.GetRemovedStakes(); len(removed) != 1 || removed[0].GetId() != removedStakeId {
+			t.Errorf("expected stake %s to be removed; got: %+v", removedStakeId, removed)
+		}
+		if added := resp.Msg.GetAddedCategoryIds(); len(added) != 1 || added[0] != addedCategoryId {
+			t.Errorf("expected category %s to be added; got: %+v", addedCategoryId, added)
+		}
+		if removed := resp.Msg.GetRemovedCategoryIds(); len(removed) != 0 {
+			t.Errorf("expected no category to be removed; got: %+v", removed)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Diff identical Expense revisions successfully", func(t *testing.T) {
+		stakeId := "expensestake-123456789012345"
+		expectRevision(t, 2, "same-name", "ab@c.de", []string{stakeId}, nil)
+		expectRevision(t, 2, "same-name", "ab@c.de", []string{stakeId}, nil)
+		resp, err := client.DiffExpenseRevisions(ctx, connect.NewRequest(&expensesvcv1.DiffExpenseRevisionsRequest{
+			ExpenseId:    expenseId,
+			FromRevision: 2,
+			ToRevision:   2,
+		}))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		if len(resp.Msg.GetExpenseBefore().AsMap()) != 0 || len(resp.Msg.GetExpenseAfter().AsMap()) != 0 {
+			t.Errorf("expected no changed fields; got: %+v and %+v", resp.Msg.GetExpenseBefore(), resp.Msg.GetExpenseAfter())
+		}
+		if len(resp.Msg.GetAddedStakes()) != 0 || len(resp.Msg.GetRemovedStakes()) != 0 {
+			t.Errorf("expected no changed stakes; got: %+v", resp.Msg)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail diffing Expense revisions due to non existent revision", func(t *testing.T) {
+		expectRevision(t, 1, "first-name", "ab@c.de", nil, nil)
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "expense_revisions" (.+) WHERE (.+)"expense_id" = '%s'(.+)"revision" = 7(.+)`, expenseId)).
+			WillReturnError(sql.ErrNoRows)
+		resp, err := client.DiffExpenseRevisions(ctx, connect.NewRequest(&expensesvcv1.DiffExpenseRevisionsRequest{
+			ExpenseId:    expenseId,
+			FromRevision: 1,
+			ToRevision:   7,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		if connectErr := new(connect.Error); eris.As(err, &connectErr) {
+			if connectErr.Code() != connect.CodeNotFound {
+				t.Fatalf("Expected code: %+v; got: %+v", connect.CodeNotFound, connectErr.Code())
+			}
+		} else {
+			t.Fatalf("Expected connect error, got: %+v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+}
@@ -18,25 +18,44 @@ var errNoExpenseWithId = eris.New("there is no expense with that ID")
 var errInsertExpense = eris.New("failed inserting expense")
 var errPublishExpenseCreated = eris.New("failed publishing expense created event")
 var errPublishExpenseDeleted = eris.New("failed publishing expense deleted event")
+var errPublishExpenseUndeleted = eris.New("failed publishing expense undeleted event")
 var errPublishExpenseUpdated = eris.New("failed publishing expense updated event")
 var errSelectExpenseIds = eris.New("failed selecting expense IDs")
 var errDeleteExpense = eris.New("failed deleting expense")
+var errUndeleteExpense = eris.New("failed restoring expense")
 var errUpdateExpense = eris.New("failed updating expense")
+var errNoExpenseRevision = eris.New("there is no such revision of the expense")
+var errSelectExpenseRevisions = eris.New("failed selecting expense revisions")
+var errConvertExpenseRevision = eris.New("failed converting expense revision")
+var errRevertExpense = eris.New("failed reverting expense")
+
+// defaultPageSize is the number of revisions listed if the request does not specify a page size
+const defaultPageSize = 20
 
 type expenseServer struct {
-	dbClient   bun.IDB
+	dbClient bun.IDB
+	// dbReads is used by read-only endpoints while writes and reads within transactions always use dbClient
+	dbReads    *client.ReadRouter
 	natsClient *nats.EncodedConn
 	// TODO: add clients to servers this server will communicate with
 }
 
 // NewExpenseServer creates a new instance of expense server. The context has no effect on the server's lifecycle.
-func NewExpenseServer(ctx context.Context, natsServer, dbUser, dbPass, dbAddr, db string) (*expenseServer, error) {
+func NewExpenseServer(ctx context.Context, natsServer string, dbConfig client.Config) (*expenseServer, error) {
 	log := logging.FromContext(ctx).Named("NewExpenseServer")
 	ctx = logging.IntoContext(ctx, log)
-	return NewExpenseServerWithDBClient(
-		ctx,
-		client.NewPostgresDBClient(dbUser, dbPass, dbAddr, db),
-		natsServer)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewExpenseServerWithDBClient(ctx, dbClient, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
 }
 
 // NewExpenseServerWithDBClient creates a new instance of expense server. The context has no effect on the server's lifecycle.
@@ -50,11 +69,12 @@ func NewExpenseServerWithDBClient(ctx context.Context, dbClient bun.IDB, natsSer
 	}
 	return &expenseServer{
 		dbClient:   dbClient,
+		dbReads:    client.NewReadRouter(dbClient),
 		natsClient: nc,
 	}, nil
 }
 
 func (rps *expenseServer) Close() error {
 	rps.natsClient.Close()
-	return nil
+	return rps.dbReads.Close()
 }
@@ -26,7 +26,7 @@ func (s *expenseServer) GetExpense(ctx context.Context, req *connect.Request[exp
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	expense, err := util.CheckResourceExists[*model.Expense](ctx, s.dbClient, req.Msg.GetId())
+	expense, err := util.CheckResourceExistsWithDeleted[*model.Expense](ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetId())
 	if err != nil {
 		if eris.Is(err, util.ErrSelectResource) {
 			return nil, errors.NewErrorWithDetails(
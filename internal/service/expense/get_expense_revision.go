@@ -0,0 +1,84 @@
+package expense
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"connectrpc.com/connect"
+	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expense/v1"
+	expensesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *expenseServer) GetExpenseRevision(ctx context.Context, req *connect.Request[expensesvcv1.GetExpenseRevisionRequest]) (*connect.Response[expensesvcv1.GetExpenseRevisionResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"expenseId",
+				req.Msg.GetExpenseId()),
+			logging.Int64(
+				"revision",
+				req.Msg.GetRevision())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	revision, err := getExpenseRevision(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetExpenseId(), req.Msg.GetRevision())
+	if err != nil {
+		if eris.Is(err, errSelectExpenseRevisions) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoExpenseRevision) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the revision of the expense does not exist"))
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&expensesvcv1.GetExpenseRevisionResponse{
+		Revision: revision,
+	}), nil
+}
+
+func getExpenseRevision(ctx context.Context, dbClient bun.IDB, expenseId string, revision int64) (*expensev1.ExpenseRevision, error) {
+	log := logging.FromContext(ctx)
+
+	expenseRevision := &model.ExpenseRevision{
+		ExpenseRevision: expensev1.ExpenseRevision{
+			ExpenseId: expenseId,
+			Revision:  revision,
+		},
+	}
+	if err := dbClient.NewSelect().Model(expenseRevision).WherePK().Scan(ctx); err != nil {
+		if eris.Is(err, sql.ErrNoRows) {
+			log.Info("expense revision not found", logging.Error(err))
+			return nil, errNoExpenseRevision
+		}
+		log.Error("failed getting expense revision", logging.Error(err))
+		return nil, errSelectExpenseRevisions
+	}
+	protoRevision, err := expenseRevision.IntoProtoExpenseRevision()
+	if err != nil {
+		log.Error("failed converting expense revision", logging.Error(err))
+		return nil, errConvertExpenseRevision
+	}
+	return protoRevision, nil
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
+	metadatav1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/metadata/v1"
 	expensesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expense/v1"
 	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
@@ -20,7 +21,7 @@ func (s *expenseServer) ListExpenseIdsInGroup(ctx context.Context, req *connect.
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	expenseIds, err := listExpenseIds(ctx, s.dbClient, req.Msg.GetGroupId())
+	expenseIds, err := listExpenseIds(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetGroupId(), req.Msg.GetOrderBy(), req.Msg.GetFilter(), req.Msg.GetDeleted())
 	if err != nil {
 		if eris.Is(err, errSelectExpenseIds) {
 			return nil, errors.NewErrorWithDetails(
@@ -43,10 +44,14 @@ func (s *expenseServer) ListExpenseIdsInGroup(ctx context.Context, req *connect.
 	}), nil
 }
 
-func listExpenseIds(ctx context.Context, dbClient bun.IDB, groupId string) ([]string, error) {
+func listExpenseIds(ctx context.Context, dbClient bun.IDB, groupId string, order *metadatav1.MetadataOrder, filter *metadatav1.MetadataFilter, deleted bool) ([]string, error) {
 	log := logging.FromContext(ctx)
 	var expenseIds []string
-	if err := dbClient.NewSelect().Model((*model.Expense)(nil)).Where("group_id = ?", groupId).Column("id").Order("timestamp DESC").Scan(ctx, &expenseIds); err != nil {
+	query := dbClient.NewSelect().Model((*model.Expense)(nil)).Where("group_id = ?", groupId).Column("id")
+	if deleted {
+		query = query.WhereDeleted()
+	}
+	if err := model.ApplyMetadataListOptions(query, order, filter).Order("timestamp DESC").Scan(ctx, &expenseIds); err != nil {
 		log.Error("failed getting expense IDs", logging.Error(err))
 		// TODO: determine reason why expense ID couldn't be fetched and return error-specific ErrVariable; e.g. use unit testing with dummy return values to determine potential return values unless there is something in the bun documentation
 		return nil, errSelectExpenseIds
@@ -0,0 +1,97 @@
+package expense
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expense/v1"
+	expensesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *expenseServer) ListExpenseRevisions(ctx context.Context, req *connect.Request[expensesvcv1.ListExpenseRevisionsRequest]) (*connect.Response[expensesvcv1.ListExpenseRevisionsResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"expenseId",
+				req.Msg.GetExpenseId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	pageSize := int(req.Msg.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	revisions, nextPageToken, err := listExpenseRevisions(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetExpenseId(), pageSize, req.Msg.GetPageToken())
+	if err != nil {
+		if eris.Is(err, errSelectExpenseRevisions) || eris.Is(err, util.ErrSelectResource) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if resErr := new(util.ResourceNotFoundError); eris.As(err, resErr) {
+			return nil, connect.NewError(connect.CodeNotFound, eris.Errorf("the %s with ID %s does not exist", resErr.ResourceName, resErr.ResourceId))
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&expensesvcv1.ListExpenseRevisionsResponse{
+		Revisions:     revisions,
+		NextPageToken: nextPageToken,
+	}), nil
+}
+
+// listExpenseRevisions returns a page of the revisions of an expense starting with the most recent one.
+// The page token is the number of the last revision of the previous page.
+func listExpenseRevisions(ctx context.Context, dbClient bun.IDB, expenseId string, pageSize int, pageToken int64) ([]*expensev1.ExpenseRevision, int64, error) {
+	log := logging.FromContext(ctx)
+
+	if _, err := util.CheckResourceExistsWithDeleted[*model.Expense](ctx, dbClient, expenseId); err != nil {
+		return nil, 0, err
+	}
+
+	var revisions []*model.ExpenseRevision
+	query := dbClient.NewSelect().Model(&revisions).
+		Where("expense_id = ?", expenseId)
+	if pageToken != 0 {
+		query = query.Where("revision < ?", pageToken)
+	}
+	// one more revision than requested is selected to tell whether there is a next page
+	if err := query.Order("revision DESC").Limit(pageSize + 1).Scan(ctx); err != nil {
+		log.Error("failed getting expense revisions", logging.Error(err))
+		return nil, 0, errSelectExpenseRevisions
+	}
+
+	var nextPageToken int64
+	if len(revisions) > pageSize {
+		revisions = revisions[:pageSize]
+		nextPageToken = revisions[pageSize-1].Revision
+	}
+	protoRevisions := make([]*expensev1.ExpenseRevision, len(revisions))
+	for i, r := range revisions {
+		protoRevision, err := r.IntoProtoExpenseRevision()
+		if err != nil {
+			log.Error("failed converting expense revision", logging.Error(err), logging.Int64("revision", r.Revision))
+			return nil, 0, errConvertExpenseRevision
+		}
+		protoRevisions[i] = protoRevision
+	}
+	return protoRevisions, nextPageToken, nil
+}
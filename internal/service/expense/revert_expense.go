@@ -0,0 +1,308 @@
+package expense
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/nats-io/nats.go"
+	currencyv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/currency/v1"
+	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expense/v1"
+	expensecategoryrelationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensecategoryrelation/v1"
+	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensestake/v1"
+	expenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expense/v1"
+	expensecategoryrelationprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expensecategoryrelation/v1"
+	expensestakeprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expensestake/v1"
+	expensesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// revertViolationInvalidReference is the precondition violation type telling that a revision references a resource that is gone
+const revertViolationInvalidReference = "INVALID_REFERENCE"
+
+func (s *expenseServer) RevertExpense(ctx context.Context, req *connect.Request[expensesvcv1.RevertExpenseRequest]) (*connect.Response[expensesvcv1.RevertExpenseResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"expenseId",
+				req.Msg.GetId()),
+			logging.Int64(
+				"revision",
+				req.Msg.GetRevision())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	expense, err := revertExpense(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetRevision(), req.Msg.GetEtag())
+	if err != nil {
+		if eris.Is(err, errRevertExpense) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBUpdateErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errSelectExpenseRevisions) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errPublishExpenseUpdated) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed finalizing expense revert",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetMessagePublicationErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoExpenseWithId) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the expense ID does not exist"))
+		} else if eris.Is(err, errNoExpenseRevision) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the revision of the expense does not exist"))
+		} else if refErr := new(util.InvalidReferenceError); eris.As(err, refErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeFailedPrecondition,
+				"the expense cannot be reverted to the revision",
+				[]protoreflect.ProtoMessage{
+					&errdetails.PreconditionFailure{
+						Violations: []*errdetails.PreconditionFailure_Violation{
+							{
+								Type:        revertViolationInvalidReference,
+								Subject:     refErr.ResourceId,
+								Description: refErr.Description(),
+							},
+						},
+					},
+				})
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the expense was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&expensesvcv1.RevertExpenseResponse{
+		Expense: expense,
+	}), nil
+}
+
+// revertExpense changes the expense, its stakes and its categories back to their state in the passed revision which makes up
+// the new revision of the expense. Stakes are restored with their former IDs. The changes are published as if they were
+// made one by one so that consumers of the events need not know about reverts.
+func revertExpense(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, expenseId string, revision int64, etag string) (*expensev1.Expense, error) {
+	log := logging.FromContext(ctx)
+
+	requestorEmail := principal.FromContext(ctx)
+
+	var expense *model.Expense
+	var addedStakes, removedStakes []*expensestakev1.ExpenseStake
+	var addedCategoryIds, removedCategoryIds []string
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		currentExpense, err := util.CheckResourceExists[*model.Expense](ctx, tx, expenseId)
+		if err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("expense not found", logging.Error(err))
+				return errNoExpenseWithId
+			}
+			return err
+		}
+		if err := currentExpense.CheckEtag(etag); err != nil {
+			return err
+		}
+		target, err := getExpenseRevision(ctx, tx, expenseId, revision)
+		if err != nil {
+			return err
+		}
+		currentStakes, currentCategoryIds, err := model.SelectExpenseContent(ctx, tx, expenseId)
+		if err != nil {
+			log.Error("failed getting stakes and categories of expense", logging.Error(err))
+			return errSelectExpenseRevisions
+		}
+
+		targetExpense := target.GetExpense()
+		if _, err := util.CheckGroupScopedReference[*model.Person](ctx, tx, "by_id", targetExpense.GetById(), currentExpense.GetGroupId()); err != nil {
+			return err
+		}
+		// the currency of the expense may still be kept if it was deprecated since but must not be changed to a deprecated one
+		checkCurrency := util.CheckReference[*currencyv1.Currency]
+		if targetExpense.GetCurrencyId() != currentExpense.GetCurrencyId() {
+			checkCurrency = util.CheckNonDeprecatedReference[*currencyv1.Currency]
+		}
+		if _, err := checkCurrency(ctx, tx, "currency_id", targetExpense.GetCurrencyId()); err != nil {
+			return err
+		}
+		expenseModel := model.NewExpense(&expensev1.Expense{
+			Id:         expenseId,
+			Name:       targetExpense.Name,
+			ById:       targetExpense.GetById(),
+			Timestamp:  targetExpense.GetTimestamp(),
+			CurrencyId: targetExpense.GetCurrencyId(),
+		}, model.NewModifiedMetadata(ctx, time.Now()))
+		query := model.IncrementRevision(tx.NewUpdate().Model(expenseModel).
+			Column(model.MetadataUpdateColumns...).
+			Column("name", "by_id", "timestamp", "currency_id"))
+		if err := util.UpdateReturning(ctx, tx, model.MatchEtag(query, etag), util.WherePK, "group_id"); err != nil {
+			if eris.Is(err, sql.ErrNoRows) {
+				if etag != "" {
+					if err := model.ConcurrentEtagMismatch[*model.Expense](ctx, tx, expenseId); !eris.As(err, &util.ResourceNotFoundError{}) {
+						return err
+					}
+				}
+				log.Info("expense not found", logging.Error(err))
+				return errNoExpenseWithId
+			}
+			log.Error("failed reverting expense", logging.Error(err))
+			return errRevertExpense
+		}
+
+		currentProtoStakes := make([]*expensestakev1.ExpenseStake, len(currentStakes))
+		for i, s := range currentStakes {
+			currentProtoStakes[i] = s.IntoProtoExpenseStake()
+		}
+		addedStakes, removedStakes = diffStakes(currentProtoStakes, target.GetStakes())
+		for _, s := range removedStakes {
+			if _, err := tx.NewDelete().Model(&expensestakev1.ExpenseStake{
+				Id: s.GetId(),
+			}).WherePK().Exec(ctx); err != nil {
+				log.Error("failed deleting expense stake", logging.Error(err), logging.String("expenseStakeId", s.GetId()))
+				return errRevertExpense
+			}
+		}
+		for _, s := range addedStakes {
+			if _, err := util.CheckGroupScopedReference[*model.Person](ctx, tx, "for_id", s.GetForId(), currentExpense.GetGroupId()); err != nil {
+				return err
+			}
+			if _, err := tx.NewInsert().Model(model.NewExpenseStake(s, model.NewCreatedMetadata(ctx, time.Now()))).Exec(ctx); err != nil {
+				log.Error("failed inserting expense stake", logging.Error(err), logging.String("expenseStakeId", s.GetId()))
+				return errRevertExpense
+			}
+		}
+
+		addedCategoryIds, removedCategoryIds = diffIds(currentCategoryIds, target.GetCategoryIds())
+		for _, categoryId := range removedCategoryIds {
+			if _, err := tx.NewDelete().Model(&expensecategoryrelationv1.ExpenseCategoryRelation{
+				ExpenseId:  expenseId,
+				CategoryId: categoryId,
+			}).WherePK().Exec(ctx); err != nil {
+				log.Error("failed deleting expense category relation", logging.Error(err), logging.String("categoryId", categoryId))
+				return errRevertExpense
+			}
+		}
+		for _, categoryId := range addedCategoryIds {
+			if _, err := util.CheckGroupScopedReference[*model.Category](ctx, tx, "category_id", categoryId, currentExpense.GetGroupId()); err != nil {
+				return err
+			}
+			if _, err := tx.NewInsert().Model(model.NewExpenseCategoryRelation(&expensecategoryrelationv1.ExpenseCategoryRelation{
+				ExpenseId:  expenseId,
+				CategoryId: categoryId,
+			}, model.NewCreatedMetadata(ctx, time.Now()))).Exec(ctx); err != nil {
+				log.Error("failed inserting expense category relation", logging.Error(err), logging.String("categoryId", categoryId))
+				return errRevertExpense
+			}
+		}
+
+		if expense, err = model.RecordExpenseRevision(ctx, tx, expenseId); err != nil {
+			log.Error("failed recording expense revision", logging.Error(err))
+			return errRevertExpense
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	groupId := expense.GetGroupId()
+	if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetExpenseUpdatedSubject(groupId, expenseId), &expenseprocv1.ExpenseUpdated{
+		Id:      expenseId,
+		GroupId: groupId,
+	}, expense.IntoProtoExpense()); err != nil {
+		log.Error("failed publishing expense updated event", logging.Error(err))
+		return nil, errPublishExpenseUpdated
+	}
+	for _, s := range removedStakes {
+		if err := mqClient.PublishEvent(ctx, nc, environment.GetExpenseStakeDeletedSubject(groupId, expenseId, s.GetId()), &expensestakeprocv1.ExpenseStakeDeleted{
+			Id:        s.GetId(),
+			ExpenseId: expenseId,
+			GroupId:   groupId,
+		}); err != nil {
+			log.Error("failed publishing expense stake deleted event", logging.Error(err))
+			return nil, errPublishExpenseUpdated
+		}
+	}
+	for _, s := range addedStakes {
+		if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetExpenseStakeCreatedSubject(groupId, expenseId, s.GetId()), &expensestakeprocv1.ExpenseStakeCreated{
+			Id:              s.GetId(),
+			ExpenseId:       expenseId,
+			ForId:           s.GetForId(),
+			MainValue:       s.GetMainValue(),
+			FractionalValue: s.FractionalValue,
+			RequestorEmail:  requestorEmail,
+		}, s); err != nil {
+			log.Error("failed publishing expense stake created event", logging.Error(err))
+			return nil, errPublishExpenseUpdated
+		}
+	}
+	for _, categoryId := range removedCategoryIds {
+		if err := mqClient.PublishEvent(ctx, nc, environment.GetExpenseCategoryRelationDeletedSubject(groupId, expenseId, categoryId), &expensecategoryrelationprocv1.ExpenseCategoryRelationDeleted{
+			ExpenseId:  expenseId,
+			CategoryId: categoryId,
+		}); err != nil {
+			log.Error("failed publishing expense category relation deleted event", logging.Error(err))
+			return nil, errPublishExpenseUpdated
+		}
+	}
+	for _, categoryId := range addedCategoryIds {
+		if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetExpenseCategoryRelationCreatedSubject(groupId, expenseId, categoryId), &expensecategoryrelationprocv1.ExpenseCategoryRelationCreated{
+			ExpenseId:      expenseId,
+			CategoryId:     categoryId,
+			RequestorEmail: requestorEmail,
+		}, &expensecategoryrelationv1.ExpenseCategoryRelation{
+			ExpenseId:  expenseId,
+			CategoryId: categoryId,
+		}); err != nil {
+			log.Error("failed publishing expense category relation created event", logging.Error(err))
+			return nil, errPublishExpenseUpdated
+		}
+	}
+
+	return expense.IntoProtoExpense(), nil
+}
@@ -0,0 +1,197 @@
+package expense_test // the dedicated _test package prevents import cycles with the testing package
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/DATA-DOG/go-sqlmock"
+	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expense/v1"
+	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensestake/v1"
+	expensesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expense/v1"
+	expenseTesting "github.com/nico151999/high-availability-expense-splitter/internal/service/expense/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestRevertExpense(t *testing.T) {
+	log := logging.GetLogger().Named("testRevertExpense")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	client, _, closeServer := expenseTesting.SetupExpenseTest(t, ctx, bun.NewDB(db, pgdialect.New()))
+	// we want to close the server only which cascadingly closes the client as well
+	defer func() {
+		if err := closeServer(); err != nil {
+			t.Errorf("failed closing expense server: %+v", err)
+		}
+	}()
+
+	groupId := "group-543210987654321"
+	expenseId := "expense-123456789012345"
+	byId := "person-123456789012345"
+	forId := "person-543210987654321"
+	currencyId := "currency-135791357913579"
+	categoryId := "category-123456789012345"
+	restoredStakeId := "expensestake-123456789012345"
+	currentStakeId := "expensestake-543210987654321"
+	tsFormat := "2006-01-02 15:04:05-07"
+	timestamp := time.Unix(1693523248, 0)
+
+	t.Run("Revert Expense successfully", func(t *testing.T) {
+		content, err := protojson.Marshal(&expensev1.ExpenseRevision{
+			Expense: &expensev1.Expense{
+				Id:         expenseId,
+				GroupId:    groupId,
+				Name:       proto.String("reverted-name"),
+				ById:       byId,
+				Timestamp:  timestamppb.New(timestamp),
+				CurrencyId: currencyId,
+			},
+			Stakes: []*expensestakev1.ExpenseStake{
+				{
+					Id:        restoredStakeId,
+					ExpenseId: expenseId,
+					ForId:     forId,
+					MainValue: 5,
+				},
+			},
+			CategoryIds: []string{categoryId},
+		})
+		if err != nil {
+			t.Fatalf("failed marshalling expense revision: %+v", err)
+		}
+		expenseColumns := []string{"id", "name", "group_id", "by_id", "timestamp", "currency_id", "revision"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "expenses" (.+) WHERE (.+)"id" = '%s'(.+)`, expenseId)).
+			WillReturnRows(sqlmock.NewRows(expenseColumns).
+				FromCSVString(fmt.Sprintf("%s,current-name,%s,%s,%s,%s,3", expenseId, groupId, byId, timestamp.Format(tsFormat), currencyId)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "expense_revisions" (.+) WHERE (.+)"expense_id" = '%s'(.+)"revision" = 1(.+)`, expenseId)).
+			WillReturnRows(sqlmock.NewRows([]string{"expense_id", "revision", "group_id", "content_json"}).
+				AddRow(expenseId, 1, groupId, string(content)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+)FROM "expense_stakes" (.+) WHERE (.+)expense_id = '%s'(.+)`, expenseId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "expense_id", "for_id", "main_value"}).
+				FromCSVString(fmt.Sprintf("%s,%s,%s,7", currentStakeId, expenseId, forId)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+)FROM "expense_category_relations" (.+) WHERE (.+)expense_id = '%s'(.+)`, expenseId)).
+			WillReturnRows(sqlmock.NewRows([]string{"category_id"}))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "people" (.+) WHERE (.+)"id" = '%s'(.+)`, byId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id"}).
+				FromCSVString(fmt.Sprintf("%s,%s", byId, groupId)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "currencies" (.+) WHERE (.+)"id" = '%s'(.+)`, currencyId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "acronym"}).
+				FromCSVString(fmt.Sprintf("%s,EUR", currencyId)))
+		mock.ExpectQuery(fmt.Sprintf(`UPDATE "expenses" (.+)"name" = 'reverted-name'(.+) WHERE (.+)"revision" = 3(.+)"id" = '%s'(.+)`, expenseId)).
+			WillReturnRows(sqlmock.NewRows([]string{"group_id"}).
+				FromCSVString(groupId))
+		mock.ExpectExec(fmt.Sprintf(`DELETE FROM "expense_stakes" (.+) WHERE (.+)"id" = '%s'(.+)`, currentStakeId)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "people" (.+) WHERE (.+)"id" = '%s'(.+)`, forId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id"}).
+				FromCSVString(fmt.Sprintf("%s,%s", forId, groupId)))
+		// the stake is restored with the ID it had in the revision
+		mock.ExpectExec(fmt.Sprintf(`INSERT INTO "expense_stakes" (.+)'%s'(.+)`, restoredStakeId)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "categories" (.+) WHERE (.+)"id" = '%s'(.+)`, categoryId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id"}).
+				FromCSVString(fmt.Sprintf("%s,%s", categoryId, groupId)))
+		mock.ExpectExec(fmt.Sprintf(`INSERT INTO "expense_category_relations" (.+)'%s'(.+)`, categoryId)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "expenses" (.+) WHERE (.+)"id" = '%s'(.+)`, expenseId)).
+			WillReturnRows(sqlmock.NewRows(expenseColumns).
+				FromCSVString(fmt.Sprintf("%s,reverted-name,%s,%s,%s,%s,4", expenseId, groupId, byId, timestamp.Format(tsFormat), currencyId)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+)FROM "expense_stakes" (.+) WHERE (.+)expense_id = '%s'(.+)`, expenseId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "expense_id", "for_id", "main_value"}).
+				FromCSVString(fmt.Sprintf("%s,%s,%s,5", restoredStakeId, expenseId, forId)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+)FROM "expense_category_relations" (.+) WHERE (.+)expense_id = '%s'(.+)`, expenseId)).
+			WillReturnRows(sqlmock.NewRows([]string{"category_id"}).
+				FromCSVString(categoryId))
+		// reverting makes up a new revision rather than going back to the old one
+		mock.ExpectExec(fmt.Sprintf(`INSERT INTO "expense_revisions" (.+)'%s'(.+)`, expenseId)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		resp, err := client.RevertExpense(ctx, connect.NewRequest(&expensesvcv1.RevertExpenseRequest{
+			Id:       expenseId,
+			Revision: 1,
+			Etag:     `"3"`,
+		}))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		if name := resp.Msg.GetExpense().GetName(); name != "reverted-name" {
+			t.Errorf("expected the expense name of the revision; got: %s", name)
+		}
+		if etag := resp.Msg.GetExpense().GetEtag(); etag != `"4"` {
+			t.Errorf("expected the etag of the new revision; got: %s", etag)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail reverting Expense due to non existent revision", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "expenses" (.+) WHERE (.+)"id" = '%s'(.+)`, expenseId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id", "revision"}).
+				FromCSVString(fmt.Sprintf("%s,%s,3", expenseId, groupId)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "expense_revisions" (.+) WHERE (.+)"expense_id" = '%s'(.+)`, expenseId)).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+		resp, err := client.RevertExpense(ctx, connect.NewRequest(&expensesvcv1.RevertExpenseRequest{
+			Id:       expenseId,
+			Revision: 5,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		if connectErr := new(connect.Error); eris.As(err, &connectErr) {
+			if connectErr.Code() != connect.CodeNotFound {
+				t.Fatalf("Expected code: %+v; got: %+v", connect.CodeNotFound, connectErr.Code())
+			}
+		} else {
+			t.Fatalf("Expected connect error, got: %+v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail reverting Expense due to outdated etag", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "expenses" (.+) WHERE (.+)"id" = '%s'(.+)`, expenseId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id", "revision"}).
+				FromCSVString(fmt.Sprintf("%s,%s,3", expenseId, groupId)))
+		mock.ExpectRollback()
+		resp, err := client.RevertExpense(ctx, connect.NewRequest(&expensesvcv1.RevertExpenseRequest{
+			Id:       expenseId,
+			Revision: 1,
+			Etag:     `"2"`,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		if connectErr := new(connect.Error); eris.As(err, &connectErr) {
+			if connectErr.Code() != connect.CodeAborted {
+				t.Fatalf("Expected code: %+v; got: %+v", connect.CodeAborted, connectErr.Code())
+			}
+		} else {
+			t.Fatalf("Expected connect error, got: %+v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+}
@@ -36,7 +36,7 @@ func (s *expenseServer) StreamExpense(ctx context.Context, req *connect.Request[
 
 	streamSubject := fmt.Sprintf("%s.*", environment.GetExpenseSubject("*", req.Msg.GetId()))
 	if err := service.StreamResource(ctx, s.natsClient.Conn, streamSubject, func(ctx context.Context) (*expensesvcv1.StreamExpenseResponse, error) {
-		return sendCurrentExpense(ctx, s.dbClient, req.Msg.GetId())
+		return sendCurrentExpense(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetId())
 	}, srv, &streamExpenseAlive); err != nil {
 		if eris.Is(err, service.ErrResourceNoLongerFound) {
 			return connect.NewError(
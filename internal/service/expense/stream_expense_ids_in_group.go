@@ -27,7 +27,7 @@ func (s *expenseServer) StreamExpenseIdsInGroup(ctx context.Context, req *connec
 	defer cancel()
 
 	if err := service.StreamResource(ctx, s.natsClient.Conn, fmt.Sprintf("%s.*", environment.GetExpenseSubject(req.Msg.GetGroupId(), "*")), func(ctx context.Context) (*expensesvcv1.StreamExpenseIdsInGroupResponse, error) {
-		return sendCurrentExpenseIds(ctx, s.dbClient, req.Msg.GetGroupId())
+		return sendCurrentExpenseIds(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetGroupId())
 	}, srv, &streamExpenseIdsAlive); err != nil {
 		if eris.Is(err, errSelectExpenseIds) {
 			return errors.NewErrorWithDetails(
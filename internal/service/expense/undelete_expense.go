@@ -0,0 +1,117 @@
+package expense
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/nats-io/nats.go"
+	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expense/v1"
+	expenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expense/v1"
+	expensesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *expenseServer) UndeleteExpense(ctx context.Context, req *connect.Request[expensesvcv1.UndeleteExpenseRequest]) (*connect.Response[expensesvcv1.UndeleteExpenseResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"expenseId",
+				req.Msg.GetId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	expense, err := undeleteExpense(ctx, s.natsClient, s.dbClient, req.Msg.GetId())
+	if err != nil {
+		if eris.Is(err, errUndeleteExpense) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBUpdateErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoExpenseWithId) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the expense ID does not exist"))
+		} else if restoreErr := new(model.RestoreError); eris.As(err, restoreErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeFailedPrecondition,
+				"the expense cannot be restored",
+				[]protoreflect.ProtoMessage{
+					&errdetails.PreconditionFailure{
+						Violations: []*errdetails.PreconditionFailure_Violation{
+							{
+								Type:        restoreErr.Type,
+								Subject:     restoreErr.Subject,
+								Description: restoreErr.Description,
+							},
+						},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&expensesvcv1.UndeleteExpenseResponse{
+		Expense: expense,
+	}), nil
+}
+
+func undeleteExpense(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, expenseId string) (*expensev1.Expense, error) {
+	log := logging.FromContext(ctx)
+
+	var expense *model.Expense
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		expense, err = model.CheckRestorable[*model.Expense](ctx, tx, expenseId)
+		if err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("expense not found", logging.Error(err))
+				return errNoExpenseWithId
+			}
+			return err
+		}
+		if err := model.CheckParentNotDeleted[*model.Group](ctx, tx, expense.GetGroupId()); err != nil {
+			return err
+		}
+		if err := model.CheckParentNotDeleted[*model.Person](ctx, tx, expense.GetById()); err != nil {
+			return err
+		}
+		if err := model.Restore(ctx, tx, expense); err != nil {
+			log.Error("failed restoring expense", logging.Error(err))
+			return errUndeleteExpense
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := mqClient.PublishEvent(ctx, nc, environment.GetExpenseUndeletedSubject(expense.GetGroupId(), expenseId), &expenseprocv1.ExpenseUndeleted{
+		Id:          expenseId,
+		GroupId:     expense.GetGroupId(),
+		DeleteCause: expenseId,
+	}); err != nil {
+		log.Error("failed publishing expense undeleted event", logging.Error(err))
+		return nil, errPublishExpenseUndeleted
+	}
+
+	return expense.IntoProtoExpense(), nil
+}
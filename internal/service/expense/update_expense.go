@@ -7,15 +7,17 @@ import (
 
 	"connectrpc.com/connect"
 	"github.com/nats-io/nats.go"
+	currencyv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/currency/v1"
 	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expense/v1"
-	personv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/person/v1"
 	expenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expense/v1"
 	expensesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expense/v1"
 	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -32,7 +34,7 @@ func (s *expenseServer) UpdateExpense(ctx context.Context, req *connect.Request[
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	expense, err := updateExpense(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetUpdateFields())
+	expense, err := updateExpense(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetUpdateFields(), req.Msg.GetEtag())
 	if err != nil {
 		if eris.Is(err, errUpdateExpense) {
 			return nil, errors.NewErrorWithDetails(
@@ -49,8 +51,22 @@ func (s *expenseServer) UpdateExpense(ctx context.Context, req *connect.Request[
 			return nil, connect.NewError(
 				connect.CodeNotFound,
 				eris.New("the expense ID does not exist"))
+		} else if refErr := new(util.InvalidReferenceError); eris.As(err, refErr) {
+			return nil, errors.NewFieldViolationError(ctx, "the request references an invalid resource", refErr.Field, refErr.Description())
 		} else if resErr := new(util.ResourceNotFoundError); eris.As(err, resErr) {
 			return nil, connect.NewError(connect.CodeNotFound, eris.Errorf("the %s with ID %s does not exist", resErr.ResourceName, resErr.ResourceId))
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the expense was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
 		} else {
 			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
 		}
@@ -61,21 +77,33 @@ func (s *expenseServer) UpdateExpense(ctx context.Context, req *connect.Request[
 	}), nil
 }
 
-func updateExpense(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, expenseId string, params []*expensesvcv1.UpdateExpenseRequest_UpdateField) (*expensev1.Expense, error) {
+func updateExpense(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, expenseId string, params []*expensesvcv1.UpdateExpenseRequest_UpdateField, etag string) (*expensev1.Expense, error) {
 	log := logging.FromContext(ctx)
 	expense := &expensev1.Expense{
 		Id: expenseId,
 	}
 
-	if err := dbClient.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		query := tx.NewUpdate()
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		currentExpense, err := util.CheckResourceExists[*model.Expense](ctx, tx, expenseId)
+		if err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("expense not found", logging.Error(err))
+				return errNoExpenseWithId
+			}
+			return err
+		}
+		if err := currentExpense.CheckEtag(etag); err != nil {
+			return err
+		}
+
+		query := model.IncrementRevision(tx.NewUpdate().Column(model.MetadataUpdateColumns...))
 		for _, param := range params {
 			switch option := param.GetUpdateOption().(type) {
 			case *expensesvcv1.UpdateExpenseRequest_UpdateField_Name:
 				expense.Name = &option.Name
 				query.Column("name")
 			case *expensesvcv1.UpdateExpenseRequest_UpdateField_ById:
-				if _, err := util.CheckResourceExists[*personv1.Person](ctx, tx, option.ById); err != nil {
+				if _, err := util.CheckGroupScopedReference[*model.Person](ctx, tx, "by_id", option.ById, currentExpense.GetGroupId()); err != nil {
 					return err
 				}
 				expense.ById = option.ById
@@ -84,33 +112,46 @@ func updateExpense(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB,
 				expense.Timestamp = option.Timestamp
 				query.Column("timestamp")
 			case *expensesvcv1.UpdateExpenseRequest_UpdateField_CurrencyId:
-				// TODO: check if currency exists
+				if _, err := util.CheckNonDeprecatedReference[*currencyv1.Currency](ctx, tx, "currency_id", option.CurrencyId); err != nil {
+					return err
+				}
 				expense.CurrencyId = option.CurrencyId
 				query.Column("currency_id")
 			}
 		}
-		expenseModel := model.NewExpense(expense)
-		if err := query.Model(expenseModel).WherePK().Returning("group_id").Scan(ctx); err != nil {
+		expenseModel := model.NewExpense(expense, model.NewModifiedMetadata(ctx, time.Now()))
+		if err := util.UpdateReturning(ctx, tx, model.MatchEtag(query.Model(expenseModel), etag), util.WherePK, append([]string{"group_id", "revision"}, model.MetadataReturningColumns...)...); err != nil {
 			if eris.Is(err, sql.ErrNoRows) {
+				if etag != "" {
+					if err := model.ConcurrentEtagMismatch[*model.Expense](ctx, tx, expenseId); !eris.As(err, &util.ResourceNotFoundError{}) {
+						return err
+					}
+				}
 				log.Info("expense not found", logging.Error(err))
 				return errNoExpenseWithId
 			}
 			log.Error("failed updating expense", logging.Error(err))
 			return errUpdateExpense
 		}
-		expense = expenseModel.IntoProtoExpense()
-
-		if err := nc.Publish(environment.GetExpenseUpdatedSubject(expense.GroupId, expenseId), &expenseprocv1.ExpenseUpdated{
-			Id:      expenseId,
-			GroupId: expense.GroupId,
-		}); err != nil {
-			log.Error("failed publishing expense updated event", logging.Error(err))
-			return errPublishExpenseUpdated
+		// only the updated columns are returned but the event carries the whole expense as recorded in the revision
+		updated, err := model.RecordExpenseRevision(ctx, tx, expenseId)
+		if err != nil {
+			log.Error("failed recording expense revision", logging.Error(err))
+			return errUpdateExpense
 		}
+		expense = updated.IntoProtoExpense()
 		return nil
 	}); err != nil {
 		return nil, err
 	}
 
+	if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetExpenseUpdatedSubject(expense.GroupId, expenseId), &expenseprocv1.ExpenseUpdated{
+		Id:      expenseId,
+		GroupId: expense.GroupId,
+	}, expense); err != nil {
+		log.Error("failed publishing expense updated event", logging.Error(err))
+		return nil, errPublishExpenseUpdated
+	}
+
 	return expense, nil
 }
@@ -2,20 +2,20 @@ package expensecategoryrelation
 
 import (
 	"context"
-	"database/sql"
 	"time"
 
 	"connectrpc.com/connect"
 	"github.com/nats-io/nats.go"
-	categoryv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/category/v1"
 	expensecategoryrelationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensecategoryrelation/v1"
 	expensecategoryrelationprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expensecategoryrelation/v1"
 	expensecategoryrelationsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensecategoryrelation/v1"
 	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -61,13 +61,8 @@ func (s *expensecategoryrelationServer) CreateExpenseCategoryRelation(ctx contex
 						Domain: environment.GetGlobalDomain(ctx),
 					},
 				})
-		} else if eris.Is(err, errExpenseAndCategoryInDifferentGroups) {
-			return nil, connect.NewError(
-				connect.CodeInvalidArgument,
-				eris.Errorf(
-					"the expense with ID %s and category with ID %s need to be in the same group",
-					req.Msg.GetExpenseId(),
-					req.Msg.GetCategoryId()))
+		} else if refErr := new(util.InvalidReferenceError); eris.As(err, refErr) {
+			return nil, errors.NewFieldViolationError(ctx, "the request references an invalid resource", refErr.Field, refErr.Description())
 		} else if resErr := new(util.ResourceNotFoundError); eris.As(err, resErr) {
 			return nil, connect.NewError(connect.CodeNotFound, eris.Errorf("the %s with ID %s does not exist", resErr.ResourceName, resErr.ResourceId))
 		} else {
@@ -83,40 +78,41 @@ func createExpenseCategoryRelation(ctx context.Context, nc *nats.EncodedConn, db
 
 	requestorEmail := "ab@c.de" // TODO: take user email from context
 
-	if err := db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		expense, err := util.CheckResourceExists[*model.Expense](ctx, tx, req.GetExpenseId())
+	relation := model.NewExpenseCategoryRelation(&expensecategoryrelationv1.ExpenseCategoryRelation{
+		ExpenseId:  req.GetExpenseId(),
+		CategoryId: req.GetCategoryId(),
+	}, model.NewCreatedMetadata(ctx, time.Now()))
+	var expense *model.Expense
+	if err := transaction.RunInTx(ctx, db, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		expense, err = util.CheckReference[*model.Expense](ctx, tx, "expense_id", req.GetExpenseId())
 		if err != nil {
 			return err
 		}
-		category, err := util.CheckResourceExists[*categoryv1.Category](ctx, tx, req.GetCategoryId())
-		if err != nil {
+		if _, err := util.CheckGroupScopedReference[*model.Category](ctx, tx, "category_id", req.GetCategoryId(), expense.GetGroupId()); err != nil {
 			return err
 		}
 
-		if expense.GetGroupId() != category.GetGroupId() {
-			log.Error("expense and category need to be in the same group in order to be able to create a relation")
-			return errExpenseAndCategoryInDifferentGroups
-		}
-
-		if _, err := tx.NewInsert().Model(&expensecategoryrelationv1.ExpenseCategoryRelation{
-			ExpenseId:  req.GetExpenseId(),
-			CategoryId: req.GetCategoryId(),
-		}).Exec(ctx); err != nil {
+		if _, err := tx.NewInsert().Model(relation).Exec(ctx); err != nil {
 			log.Error("failed inserting expense category relation", logging.Error(err))
 			return errInsertExpenseCategoryRelation
 		}
-
-		if err := nc.Publish(environment.GetExpenseCategoryRelationCreatedSubject(expense.GetGroupId(), req.GetExpenseId(), req.GetCategoryId()), &expensecategoryrelationprocv1.ExpenseCategoryRelationCreated{
-			ExpenseId:      req.GetExpenseId(),
-			CategoryId:     req.GetCategoryId(),
-			RequestorEmail: requestorEmail,
-		}); err != nil {
-			log.Error("failed publishing expense category relation created event", logging.Error(err))
-			return errPublishExpenseCategoryRelationCreated
+		if _, err := model.ReviseExpense(ctx, tx, req.GetExpenseId()); err != nil {
+			log.Error("failed revising expense", logging.Error(err))
+			return errInsertExpenseCategoryRelation
 		}
 		return nil
 	}); err != nil {
 		return err
 	}
+
+	if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetExpenseCategoryRelationCreatedSubject(expense.GetGroupId(), req.GetExpenseId(), req.GetCategoryId()), &expensecategoryrelationprocv1.ExpenseCategoryRelationCreated{
+		ExpenseId:      req.GetExpenseId(),
+		CategoryId:     req.GetCategoryId(),
+		RequestorEmail: requestorEmail,
+	}, relation.IntoProtoExpenseCategoryRelation()); err != nil {
+		log.Error("failed publishing expense category relation created event", logging.Error(err))
+		return errPublishExpenseCategoryRelationCreated
+	}
 	return nil
 }
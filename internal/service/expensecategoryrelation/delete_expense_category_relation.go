@@ -12,9 +12,11 @@ import (
 	expensecategoryrelationsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensecategoryrelation/v1"
 	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -63,11 +65,12 @@ func (s *expensecategoryrelationServer) DeleteExpenseCategoryRelation(ctx contex
 func deleteExpenseCategoryRelation(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, expenseId string, categoryId string) error {
 	log := logging.FromContext(ctx)
 
-	return dbClient.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		expensecategoryrelation := expensecategoryrelationv1.ExpenseCategoryRelation{
-			ExpenseId:  expenseId,
-			CategoryId: categoryId,
-		}
+	expensecategoryrelation := expensecategoryrelationv1.ExpenseCategoryRelation{
+		ExpenseId:  expenseId,
+		CategoryId: categoryId,
+	}
+	var expense *model.Expense
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
 		if _, err := tx.NewDelete().Model(&expensecategoryrelation).WherePK().Exec(ctx); err != nil {
 			if eris.Is(err, sql.ErrNoRows) {
 				log.Info("expense category relation not found", logging.Error(err))
@@ -76,23 +79,29 @@ func deleteExpenseCategoryRelation(ctx context.Context, nc *nats.EncodedConn, db
 			log.Error("failed deleting expense category relation", logging.Error(err))
 			return errDeleteExpenseCategoryRelation
 		}
-		expense, err := util.CheckResourceExists[*model.Expense](ctx, tx, expensecategoryrelation.GetExpenseId())
-		if err != nil {
-			return err
-		}
-
-		if err := nc.Publish(environment.GetExpenseCategoryRelationDeletedSubject(
-			expense.GetGroupId(),
-			expenseId,
-			categoryId),
-			&expensecategoryrelationprocv1.ExpenseCategoryRelationDeleted{
-				ExpenseId:  expenseId,
-				CategoryId: categoryId,
-			}); err != nil {
-			log.Error("failed publishing expense category relation deleted event", logging.Error(err))
-			return errPublishExpenseCategoryRelationDeleted
+		var err error
+		if expense, err = model.ReviseExpense(ctx, tx, expensecategoryrelation.GetExpenseId()); err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				return err
+			}
+			log.Error("failed revising expense", logging.Error(err))
+			return errDeleteExpenseCategoryRelation
 		}
-
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	if err := mqClient.PublishEvent(ctx, nc, environment.GetExpenseCategoryRelationDeletedSubject(
+		expense.GetGroupId(),
+		expenseId,
+		categoryId),
+		&expensecategoryrelationprocv1.ExpenseCategoryRelationDeleted{
+			ExpenseId:  expenseId,
+			CategoryId: categoryId,
+		}); err != nil {
+		log.Error("failed publishing expense category relation deleted event", logging.Error(err))
+		return errPublishExpenseCategoryRelationDeleted
+	}
+	return nil
 }
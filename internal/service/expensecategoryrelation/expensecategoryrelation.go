@@ -21,22 +21,31 @@ var errPublishExpenseCategoryRelationDeleted = eris.New("failed publishing expen
 var errSelectCategoryIdsForExpense = eris.New("failed selecting category IDs by expense")
 var errSelectExpenseIdsForCategory = eris.New("failed selecting expense IDs by category")
 var errDeleteExpenseCategoryRelation = eris.New("failed deleting expense stake")
-var errExpenseAndCategoryInDifferentGroups = eris.New("cannot create relation between expense and category from different groups")
 
 type expensecategoryrelationServer struct {
-	dbClient   bun.IDB
+	dbClient bun.IDB
+	// dbReads is used by read-only endpoints while writes and reads within transactions always use dbClient
+	dbReads    *client.ReadRouter
 	natsClient *nats.EncodedConn
 	// TODO: add clients to servers this server will communicate with
 }
 
 // NewExpenseCategoryRelationServer creates a new instance of expense stake server. The context has no effect on the server's lifecycle.
-func NewExpenseCategoryRelationServer(ctx context.Context, natsServer, dbUser, dbPass, dbAddr, db string) (*expensecategoryrelationServer, error) {
+func NewExpenseCategoryRelationServer(ctx context.Context, natsServer string, dbConfig client.Config) (*expensecategoryrelationServer, error) {
 	log := logging.FromContext(ctx).Named("NewExpenseCategoryRelationServer")
 	ctx = logging.IntoContext(ctx, log)
-	return NewExpenseCategoryRelationServerWithDBClient(
-		ctx,
-		client.NewPostgresDBClient(dbUser, dbPass, dbAddr, db),
-		natsServer)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewExpenseCategoryRelationServerWithDBClient(ctx, dbClient, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
 }
 
 // NewExpenseCategoryRelationServerWithDBClient creates a new instance of expense stake server. The context has no effect on the server's lifecycle.
@@ -50,11 +59,12 @@ func NewExpenseCategoryRelationServerWithDBClient(ctx context.Context, dbClient
 	}
 	return &expensecategoryrelationServer{
 		dbClient:   dbClient,
+		dbReads:    client.NewReadRouter(dbClient),
 		natsClient: nc,
 	}, nil
 }
 
 func (rps *expensecategoryrelationServer) Close() error {
 	rps.natsClient.Close()
-	return nil
+	return rps.dbReads.Close()
 }
@@ -5,8 +5,9 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	expensecategoryrelationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensecategoryrelation/v1"
+	metadatav1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/metadata/v1"
 	expensecategoryrelationsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensecategoryrelation/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
@@ -31,7 +32,7 @@ func (s *expensecategoryrelationServer) ListCategoryIdsForExpense(
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	categoryIds, err := listCategoryIdsForExpense(ctx, s.dbClient, req.Msg.GetExpenseId())
+	categoryIds, err := listCategoryIdsForExpense(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetExpenseId(), req.Msg.GetOrderBy(), req.Msg.GetFilter())
 	if err != nil {
 		if eris.Is(err, errSelectCategoryIdsForExpense) {
 			return nil, errors.NewErrorWithDetails(
@@ -54,10 +55,11 @@ func (s *expensecategoryrelationServer) ListCategoryIdsForExpense(
 	}), nil
 }
 
-func listCategoryIdsForExpense(ctx context.Context, dbClient bun.IDB, expenseId string) ([]string, error) {
+func listCategoryIdsForExpense(ctx context.Context, dbClient bun.IDB, expenseId string, order *metadatav1.MetadataOrder, filter *metadatav1.MetadataFilter) ([]string, error) {
 	log := logging.FromContext(ctx)
 	var categoryIds []string
-	if err := dbClient.NewSelect().Model((*expensecategoryrelationv1.ExpenseCategoryRelation)(nil)).Where("expense_id = ?", expenseId).Column("category_id").Scan(ctx, &categoryIds); err != nil {
+	query := dbClient.NewSelect().Model((*model.ExpenseCategoryRelation)(nil)).Where("expense_id = ?", expenseId).Column("category_id")
+	if err := model.ApplyMetadataListOptions(query, order, filter).Scan(ctx, &categoryIds); err != nil {
 		log.Error("failed getting category IDs", logging.Error(err))
 		// TODO: determine reason why categoryIds couldn't be fetched and return error-specific ErrVariable; e.g. use unit testing with dummy return values to determine potential return values unless there is something in the bun documentation
 		return nil, errSelectCategoryIdsForExpense
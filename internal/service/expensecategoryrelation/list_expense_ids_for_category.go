@@ -5,8 +5,9 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	expensecategoryrelationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensecategoryrelation/v1"
+	metadatav1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/metadata/v1"
 	expensecategoryrelationsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensecategoryrelation/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
@@ -31,7 +32,7 @@ func (s *expensecategoryrelationServer) ListExpenseIdsForCategory(
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	expenseIds, err := listExpenseIdsForCategory(ctx, s.dbClient, req.Msg.GetCategoryId())
+	expenseIds, err := listExpenseIdsForCategory(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetCategoryId(), req.Msg.GetOrderBy(), req.Msg.GetFilter())
 	if err != nil {
 		if eris.Is(err, errSelectCategoryIdsForExpense) {
 			return nil, errors.NewErrorWithDetails(
@@ -54,10 +55,11 @@ func (s *expensecategoryrelationServer) ListExpenseIdsForCategory(
 	}), nil
 }
 
-func listExpenseIdsForCategory(ctx context.Context, dbClient bun.IDB, categoryId string) ([]string, error) {
+func listExpenseIdsForCategory(ctx context.Context, dbClient bun.IDB, categoryId string, order *metadatav1.MetadataOrder, filter *metadatav1.MetadataFilter) ([]string, error) {
 	log := logging.FromContext(ctx)
 	var expenseIds []string
-	if err := dbClient.NewSelect().Model((*expensecategoryrelationv1.ExpenseCategoryRelation)(nil)).Where("category_id = ?", categoryId).Column("category_id").Scan(ctx, &expenseIds); err != nil {
+	query := dbClient.NewSelect().Model((*model.ExpenseCategoryRelation)(nil)).Where("category_id = ?", categoryId).Column("expense_id")
+	if err := model.ApplyMetadataListOptions(query, order, filter).Scan(ctx, &expenseIds); err != nil {
 		log.Error("failed getting expense IDs", logging.Error(err))
 		// TODO: determine reason why expenseIds couldn't be fetched and return error-specific ErrVariable; e.g. use unit testing with dummy return values to determine potential return values unless there is something in the bun documentation
 		return nil, errSelectExpenseIdsForCategory
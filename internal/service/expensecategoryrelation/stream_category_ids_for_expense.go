@@ -6,8 +6,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	expensecategoryrelationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensecategoryrelation/v1"
 	expensecategoryrelationsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensecategoryrelation/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
@@ -39,7 +39,7 @@ func (s *expensecategoryrelationServer) StreamCategoryIdsForExpense(
 	defer cancel()
 
 	if err := service.StreamResource(ctx, s.natsClient.Conn, fmt.Sprintf("%s.*", environment.GetExpenseCategoryRelationSubject("*", req.Msg.GetExpenseId(), "*")), func(ctx context.Context) (*expensecategoryrelationsvcv1.StreamCategoryIdsForExpenseResponse, error) {
-		return sendCurrentCategoryIdsForExpense(ctx, s.dbClient, req.Msg.GetExpenseId())
+		return sendCurrentCategoryIdsForExpense(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetExpenseId())
 	}, srv, &streamCategoryIdsForExpenseAlive); err != nil {
 		if eris.Is(err, errSelectCategoryIdsForExpense) {
 			return errors.NewErrorWithDetails(
@@ -97,7 +97,7 @@ func sendCurrentCategoryIdsForExpense(ctx context.Context, dbClient bun.IDB, exp
 	log := logging.FromContext(ctx)
 
 	var categoryIds []string
-	if err := dbClient.NewSelect().Model((*expensecategoryrelationv1.ExpenseCategoryRelation)(nil)).Where("expense_id = ?", expenseId).Column("category_id").Scan(ctx, &categoryIds); err != nil {
+	if err := dbClient.NewSelect().Model((*model.ExpenseCategoryRelation)(nil)).Where("expense_id = ?", expenseId).Column("category_id").Scan(ctx, &categoryIds); err != nil {
 		log.Error("failed getting expense stake IDs", logging.Error(err))
 		// TODO: determine reason why expensecategoryrelation IDs couldn't be fetched and return error-specific ErrVariable; e.g. use unit testing with dummy return values to determine potential return values unless there is something in the bun documentation
 		return nil, errSelectCategoryIdsForExpense
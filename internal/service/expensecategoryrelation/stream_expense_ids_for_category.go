@@ -6,8 +6,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	expensecategoryrelationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensecategoryrelation/v1"
 	expensecategoryrelationsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensecategoryrelation/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
@@ -43,7 +43,7 @@ func (s *expensecategoryrelationServer) StreamExpenseIdsForCategory(
 		s.natsClient.Conn,
 		fmt.Sprintf("%s.*", environment.GetExpenseCategoryRelationSubject("*", "*", req.Msg.GetCategoryId())),
 		func(ctx context.Context) (*expensecategoryrelationsvcv1.StreamExpenseIdsForCategoryResponse, error) {
-			return sendCurrentExpenseIdsForCategory(ctx, s.dbClient, req.Msg.GetCategoryId())
+			return sendCurrentExpenseIdsForCategory(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetCategoryId())
 		},
 		srv,
 		&streamExpenseidsForCategoryAlive); err != nil {
@@ -103,7 +103,7 @@ func sendCurrentExpenseIdsForCategory(ctx context.Context, dbClient bun.IDB, cat
 	log := logging.FromContext(ctx)
 
 	var expenseIds []string
-	if err := dbClient.NewSelect().Model((*expensecategoryrelationv1.ExpenseCategoryRelation)(nil)).Where("category_id = ?", categoryId).Column("expense_id").Scan(ctx, &expenseIds); err != nil {
+	if err := dbClient.NewSelect().Model((*model.ExpenseCategoryRelation)(nil)).Where("category_id = ?", categoryId).Column("expense_id").Scan(ctx, &expenseIds); err != nil {
 		log.Error("failed getting expense stake IDs", logging.Error(err))
 		// TODO: determine reason why expensecategoryrelation IDs couldn't be fetched and return error-specific ErrVariable; e.g. use unit testing with dummy return values to determine potential return values unless there is something in the bun documentation
 		return nil, errSelectExpenseIdsForCategory
@@ -2,20 +2,20 @@ package expensestake
 
 import (
 	"context"
-	"database/sql"
 	"time"
 
 	"connectrpc.com/connect"
 	"github.com/nats-io/nats.go"
 	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensestake/v1"
-	personv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/person/v1"
 	expensestakeprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expensestake/v1"
 	expensestakesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensestake/v1"
 	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -61,6 +61,8 @@ func (s *expensestakeServer) CreateExpenseStake(ctx context.Context, req *connec
 						Domain: environment.GetGlobalDomain(ctx),
 					},
 				})
+		} else if refErr := new(util.InvalidReferenceError); eris.As(err, refErr) {
+			return nil, errors.NewFieldViolationError(ctx, "the request references an invalid resource", refErr.Field, refErr.Description())
 		} else if resErr := new(util.ResourceNotFoundError); eris.As(err, resErr) {
 			return nil, connect.NewError(connect.CodeNotFound, eris.Errorf("the %s with ID %s does not exist", resErr.ResourceName, resErr.ResourceId))
 		} else {
@@ -79,44 +81,51 @@ func createExpenseStake(ctx context.Context, nc *nats.EncodedConn, db bun.IDB, r
 	expensestakeId := util.GenerateIdWithPrefix("expensestake")
 	requestorEmail := "ab@c.de" // TODO: take user email from context
 
-	if err := db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		expense, err := util.CheckResourceExists[*model.Expense](ctx, tx, req.GetExpenseId())
+	var fractionalValue *int32
+	if req != nil {
+		fractionalValue = req.FractionalValue
+	}
+	stake := model.NewExpenseStake(&expensestakev1.ExpenseStake{
+		Id:              expensestakeId,
+		ExpenseId:       req.GetExpenseId(),
+		ForId:           req.GetForId(),
+		MainValue:       req.GetMainValue(),
+		FractionalValue: fractionalValue,
+	}, model.NewCreatedMetadata(ctx, time.Now()))
+	var expense *model.Expense
+	if err := transaction.RunInTx(ctx, db, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		expense, err = util.CheckReference[*model.Expense](ctx, tx, "expense_id", req.GetExpenseId())
 		if err != nil {
 			return err
 		}
-		if _, err := util.CheckResourceExists[*personv1.Person](ctx, tx, req.GetForId()); err != nil {
+		if _, err := util.CheckGroupScopedReference[*model.Person](ctx, tx, "for_id", req.GetForId(), expense.GetGroupId()); err != nil {
 			return err
 		}
 
-		var fractionalValue *int32
-		if req != nil {
-			fractionalValue = req.FractionalValue
-		}
-		if _, err := tx.NewInsert().Model(&expensestakev1.ExpenseStake{
-			Id:              expensestakeId,
-			ExpenseId:       req.GetExpenseId(),
-			ForId:           req.GetForId(),
-			MainValue:       req.GetMainValue(),
-			FractionalValue: fractionalValue,
-		}).Exec(ctx); err != nil {
+		if _, err := tx.NewInsert().Model(stake).Exec(ctx); err != nil {
 			log.Error("failed inserting expense stake", logging.Error(err))
 			return errInsertExpenseStake
 		}
-
-		if err := nc.Publish(environment.GetExpenseStakeCreatedSubject(expense.GetGroupId(), req.GetExpenseId(), expensestakeId), &expensestakeprocv1.ExpenseStakeCreated{
-			Id:              expensestakeId,
-			ExpenseId:       req.GetExpenseId(),
-			ForId:           req.GetForId(),
-			MainValue:       req.GetMainValue(),
-			FractionalValue: fractionalValue,
-			RequestorEmail:  requestorEmail,
-		}); err != nil {
-			log.Error("failed publishing expense stake created event", logging.Error(err))
-			return errPublishExpenseStakeCreated
+		if _, err := model.ReviseExpense(ctx, tx, req.GetExpenseId()); err != nil {
+			log.Error("failed revising expense", logging.Error(err))
+			return errInsertExpenseStake
 		}
 		return nil
 	}); err != nil {
 		return "", err
 	}
+
+	if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetExpenseStakeCreatedSubject(expense.GetGroupId(), req.GetExpenseId(), expensestakeId), &expensestakeprocv1.ExpenseStakeCreated{
+		Id:              expensestakeId,
+		ExpenseId:       req.GetExpenseId(),
+		ForId:           req.GetForId(),
+		MainValue:       req.GetMainValue(),
+		FractionalValue: fractionalValue,
+		RequestorEmail:  requestorEmail,
+	}, stake.IntoProtoExpenseStake()); err != nil {
+		log.Error("failed publishing expense stake created event", logging.Error(err))
+		return "", errPublishExpenseStakeCreated
+	}
 	return expensestakeId, nil
 }
@@ -12,9 +12,11 @@ import (
 	expensestakesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensestake/v1"
 	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -58,11 +60,12 @@ func (s *expensestakeServer) DeleteExpenseStake(ctx context.Context, req *connec
 func deleteExpenseStake(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, expensestakeId string) error {
 	log := logging.FromContext(ctx)
 
-	return dbClient.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		expensestake := expensestakev1.ExpenseStake{
-			Id: expensestakeId,
-		}
-		if err := tx.NewDelete().Model(&expensestake).WherePK().Returning("expense_id").Scan(ctx); err != nil {
+	expensestake := expensestakev1.ExpenseStake{
+		Id: expensestakeId,
+	}
+	var expense *model.Expense
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := util.DeleteReturning(ctx, tx, &expensestake, util.WherePK, "expense_id"); err != nil {
 			if eris.Is(err, sql.ErrNoRows) {
 				log.Info("expense stake not found", logging.Error(err))
 				return errNoExpenseStakeWithId
@@ -70,20 +73,26 @@ func deleteExpenseStake(ctx context.Context, nc *nats.EncodedConn, dbClient bun.
 			log.Error("failed deleting expense stake", logging.Error(err))
 			return errDeleteExpenseStake
 		}
-		expense, err := util.CheckResourceExists[*model.Expense](ctx, tx, expensestake.GetExpenseId())
-		if err != nil {
-			return err
-		}
-
-		if err := nc.Publish(environment.GetExpenseStakeDeletedSubject(expense.GetGroupId(), expense.GetId(), expensestakeId), &expensestakeprocv1.ExpenseStakeDeleted{
-			Id:        expensestakeId,
-			ExpenseId: expense.GetId(),
-			GroupId:   expense.GetGroupId(),
-		}); err != nil {
-			log.Error("failed publishing expense stake deleted event", logging.Error(err))
-			return errPublishExpenseStakeDeleted
+		var err error
+		if expense, err = model.ReviseExpense(ctx, tx, expensestake.GetExpenseId()); err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				return err
+			}
+			log.Error("failed revising expense", logging.Error(err))
+			return errDeleteExpenseStake
 		}
-
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	if err := mqClient.PublishEvent(ctx, nc, environment.GetExpenseStakeDeletedSubject(expense.GetGroupId(), expense.GetId(), expensestakeId), &expensestakeprocv1.ExpenseStakeDeleted{
+		Id:        expensestakeId,
+		ExpenseId: expense.GetId(),
+		GroupId:   expense.GetGroupId(),
+	}); err != nil {
+		log.Error("failed publishing expense stake deleted event", logging.Error(err))
+		return errPublishExpenseStakeDeleted
+	}
+	return nil
 }
@@ -42,9 +42,17 @@ func TestDeleteExpenseStake(t *testing.T) {
 		mock.ExpectQuery(fmt.Sprintf(`DELETE FROM "expense_stakes" (.+) WHERE (.+)"id" = '%s'(.+)`, expensestakeId)).
 			WillReturnRows(sqlmock.NewRows([]string{"expense_id"}).
 				FromCSVString(expenseId))
+		mock.ExpectExec(fmt.Sprintf(`UPDATE "expenses" (.+) SET (.+)"revision" = "revision" \+ 1(.+) WHERE (.+)"id" = '%s'(.+)`, expenseId)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+)FROM "expenses" (.+) WHERE (.+)"id" = '%s'(.+)`, expenseId)).
-			WillReturnRows(sqlmock.NewRows([]string{"group_id"}).
-				FromCSVString(groupId))
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id", "revision"}).
+				FromCSVString(fmt.Sprintf("%s,%s,2", expenseId, groupId)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+)FROM "expense_stakes" (.+) WHERE (.+)expense_id = '%s'(.+)`, expenseId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+)FROM "expense_category_relations" (.+) WHERE (.+)expense_id = '%s'(.+)`, expenseId)).
+			WillReturnRows(sqlmock.NewRows([]string{"category_id"}))
+		mock.ExpectExec(`INSERT INTO "expense_revisions" (.+)`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectCommit()
 		_, err := client.DeleteExpenseStake(ctx, connect.NewRequest(&expensestakesvcv1.DeleteExpenseStakeRequest{
 			Id: expensestakeId,
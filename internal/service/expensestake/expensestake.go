@@ -22,19 +22,29 @@ var errSelectExpenseStakeIds = eris.New("failed selecting expense stake IDs")
 var errDeleteExpenseStake = eris.New("failed deleting expense stake")
 
 type expensestakeServer struct {
-	dbClient   bun.IDB
+	dbClient bun.IDB
+	// dbReads is used by read-only endpoints while writes and reads within transactions always use dbClient
+	dbReads    *client.ReadRouter
 	natsClient *nats.EncodedConn
 	// TODO: add clients to servers this server will communicate with
 }
 
 // NewExpenseStakeServer creates a new instance of expense stake server. The context has no effect on the server's lifecycle.
-func NewExpenseStakeServer(ctx context.Context, natsServer, dbUser, dbPass, dbAddr, db string) (*expensestakeServer, error) {
+func NewExpenseStakeServer(ctx context.Context, natsServer string, dbConfig client.Config) (*expensestakeServer, error) {
 	log := logging.FromContext(ctx).Named("NewExpenseStakeServer")
 	ctx = logging.IntoContext(ctx, log)
-	return NewExpenseStakeServerWithDBClient(
-		ctx,
-		client.NewPostgresDBClient(dbUser, dbPass, dbAddr, db),
-		natsServer)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewExpenseStakeServerWithDBClient(ctx, dbClient, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
 }
 
 // NewExpenseStakeServerWithDBClient creates a new instance of expense stake server. The context has no effect on the server's lifecycle.
@@ -48,11 +58,12 @@ func NewExpenseStakeServerWithDBClient(ctx context.Context, dbClient bun.IDB, na
 	}
 	return &expensestakeServer{
 		dbClient:   dbClient,
+		dbReads:    client.NewReadRouter(dbClient),
 		natsClient: nc,
 	}, nil
 }
 
 func (rps *expensestakeServer) Close() error {
 	rps.natsClient.Close()
-	return nil
+	return rps.dbReads.Close()
 }
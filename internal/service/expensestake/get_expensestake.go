@@ -5,8 +5,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensestake/v1"
 	expensestakesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensestake/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
@@ -26,7 +26,7 @@ func (s *expensestakeServer) GetExpenseStake(ctx context.Context, req *connect.R
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	expensestake, err := util.CheckResourceExists[*expensestakev1.ExpenseStake](ctx, s.dbClient, req.Msg.GetId())
+	expensestake, err := util.CheckResourceExists[*model.ExpenseStake](ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetId())
 	if err != nil {
 		if eris.Is(err, util.ErrSelectResource) {
 			return nil, errors.NewErrorWithDetails(
@@ -47,6 +47,6 @@ func (s *expensestakeServer) GetExpenseStake(ctx context.Context, req *connect.R
 	}
 
 	return connect.NewResponse(&expensestakesvcv1.GetExpenseStakeResponse{
-		ExpenseStake: expensestake,
+		ExpenseStake: expensestake.IntoProtoExpenseStake(),
 	}), nil
 }
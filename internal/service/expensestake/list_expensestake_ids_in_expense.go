@@ -5,8 +5,9 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensestake/v1"
+	metadatav1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/metadata/v1"
 	expensestakesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensestake/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
@@ -20,7 +21,7 @@ func (s *expensestakeServer) ListExpenseStakeIdsInExpense(ctx context.Context, r
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	expensestakeIds, err := listExpenseStakeIdsInExpense(ctx, s.dbClient, req.Msg.GetExpenseId())
+	expensestakeIds, err := listExpenseStakeIdsInExpense(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetExpenseId(), req.Msg.GetOrderBy(), req.Msg.GetFilter())
 	if err != nil {
 		if eris.Is(err, errSelectExpenseStakeIds) {
 			return nil, errors.NewErrorWithDetails(
@@ -43,10 +44,11 @@ func (s *expensestakeServer) ListExpenseStakeIdsInExpense(ctx context.Context, r
 	}), nil
 }
 
-func listExpenseStakeIdsInExpense(ctx context.Context, dbClient bun.IDB, expenseId string) ([]string, error) {
+func listExpenseStakeIdsInExpense(ctx context.Context, dbClient bun.IDB, expenseId string, order *metadatav1.MetadataOrder, filter *metadatav1.MetadataFilter) ([]string, error) {
 	log := logging.FromContext(ctx)
 	var expensestakeIds []string
-	if err := dbClient.NewSelect().Model((*expensestakev1.ExpenseStake)(nil)).Where("expense_id = ?", expenseId).Column("id").Order("for_id ASC").Scan(ctx, &expensestakeIds); err != nil {
+	query := dbClient.NewSelect().Model((*model.ExpenseStake)(nil)).Where("expense_id = ?", expenseId).Column("id")
+	if err := model.ApplyMetadataListOptions(query, order, filter).Order("for_id ASC").Scan(ctx, &expensestakeIds); err != nil {
 		log.Error("failed getting expense stake IDs", logging.Error(err))
 		// TODO: determine reason why expensestake ID couldn't be fetched and return error-specific ErrVariable; e.g. use unit testing with dummy return values to determine potential return values unless there is something in the bun documentation
 		return nil, errSelectExpenseStakeIds
@@ -5,9 +5,9 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expense/v1"
-	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensestake/v1"
+	metadatav1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/metadata/v1"
 	expensestakesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensestake/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
@@ -21,7 +21,7 @@ func (s *expensestakeServer) ListExpenseStakeIdsInGroup(ctx context.Context, req
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	expensestakeIds, err := listExpenseStakeIdsInGroup(ctx, s.dbClient, req.Msg.GetGroupId())
+	expensestakeIds, err := listExpenseStakeIdsInGroup(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetGroupId(), req.Msg.GetOrderBy(), req.Msg.GetFilter())
 	if err != nil {
 		if eris.Is(err, errSelectExpenseStakeIds) {
 			return nil, errors.NewErrorWithDetails(
@@ -44,19 +44,20 @@ func (s *expensestakeServer) ListExpenseStakeIdsInGroup(ctx context.Context, req
 	}), nil
 }
 
-func listExpenseStakeIdsInGroup(ctx context.Context, dbClient bun.IDB, groupId string) ([]string, error) {
+func listExpenseStakeIdsInGroup(ctx context.Context, dbClient bun.IDB, groupId string, order *metadatav1.MetadataOrder, filter *metadatav1.MetadataFilter) ([]string, error) {
 	log := logging.FromContext(ctx)
 	var expensestakeIds []string
-	if err := dbClient.NewSelect().
-		Model((*expensestakev1.ExpenseStake)(nil)).
+	query := dbClient.NewSelect().
+		Model((*model.ExpenseStake)(nil)).
 		Where(
 			"expense_id IN (?)",
 			dbClient.NewSelect().
-				Model((*expensev1.Expense)(nil)).
+				Model((*model.Expense)(nil)).
 				Column("id").
 				Where("group_id = ?", groupId),
 		).
-		Column("id").
+		Column("id")
+	if err := model.ApplyMetadataListOptions(query, order, filter).
 		Order("for_id ASC").
 		Scan(ctx, &expensestakeIds); err != nil {
 		log.Error("failed getting expense stake IDs", logging.Error(err))
@@ -6,8 +6,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensestake/v1"
 	expensestakesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensestake/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
@@ -36,7 +36,7 @@ func (s *expensestakeServer) StreamExpenseStake(ctx context.Context, req *connec
 
 	streamSubject := fmt.Sprintf("%s.*", environment.GetExpenseStakeSubject("*", "*", req.Msg.GetId()))
 	if err := service.StreamResource(ctx, s.natsClient.Conn, streamSubject, func(ctx context.Context) (*expensestakesvcv1.StreamExpenseStakeResponse, error) {
-		return sendCurrentExpenseStake(ctx, s.dbClient, req.Msg.GetId())
+		return sendCurrentExpenseStake(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetId())
 	}, srv, &streamExpenseStakeAlive); err != nil {
 		if eris.Is(err, service.ErrResourceNoLongerFound) {
 			return connect.NewError(
@@ -88,13 +88,13 @@ func (s *expensestakeServer) StreamExpenseStake(ctx context.Context, req *connec
 }
 
 func sendCurrentExpenseStake(ctx context.Context, dbClient bun.IDB, expensestakeId string) (*expensestakesvcv1.StreamExpenseStakeResponse, error) {
-	expensestake, err := util.CheckResourceExists[*expensestakev1.ExpenseStake](ctx, dbClient, expensestakeId)
+	expensestake, err := util.CheckResourceExists[*model.ExpenseStake](ctx, dbClient, expensestakeId)
 	if err != nil {
 		return nil, err
 	}
 	return &expensestakesvcv1.StreamExpenseStakeResponse{
 		Update: &expensestakesvcv1.StreamExpenseStakeResponse_ExpenseStake{
-			ExpenseStake: expensestake,
+			ExpenseStake: expensestake.IntoProtoExpenseStake(),
 		},
 	}, nil
 }
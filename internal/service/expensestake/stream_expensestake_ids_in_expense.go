@@ -6,8 +6,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensestake/v1"
 	expensestakesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensestake/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
@@ -27,7 +27,7 @@ func (s *expensestakeServer) StreamExpenseStakeIdsInExpense(ctx context.Context,
 	defer cancel()
 
 	if err := service.StreamResource(ctx, s.natsClient.Conn, fmt.Sprintf("%s.*", environment.GetExpenseStakeSubject("*", req.Msg.GetExpenseId(), "*")), func(ctx context.Context) (*expensestakesvcv1.StreamExpenseStakeIdsInExpenseResponse, error) {
-		return sendCurrentExpenseStakeIdsInExpense(ctx, s.dbClient, req.Msg.GetExpenseId())
+		return sendCurrentExpenseStakeIdsInExpense(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetExpenseId())
 	}, srv, &streamExpenseStakeIdsInExpenseAlive); err != nil {
 		if eris.Is(err, errSelectExpenseStakeIds) {
 			return errors.NewErrorWithDetails(
@@ -85,7 +85,7 @@ func sendCurrentExpenseStakeIdsInExpense(ctx context.Context, dbClient bun.IDB,
 	log := logging.FromContext(ctx)
 
 	var expensestakeIds []string
-	if err := dbClient.NewSelect().Model((*expensestakev1.ExpenseStake)(nil)).Where("expense_id = ?", expenseId).Column("id").Order("for_id ASC").Scan(ctx, &expensestakeIds); err != nil {
+	if err := dbClient.NewSelect().Model((*model.ExpenseStake)(nil)).Where("expense_id = ?", expenseId).Column("id").Order("for_id ASC").Scan(ctx, &expensestakeIds); err != nil {
 		log.Error("failed getting expense stake IDs", logging.Error(err))
 		// TODO: determine reason why expensestake IDs couldn't be fetched and return error-specific ErrVariable; e.g. use unit testing with dummy return values to determine potential return values unless there is something in the bun documentation
 		return nil, errSelectExpenseStakeIds
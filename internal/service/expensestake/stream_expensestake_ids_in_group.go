@@ -6,9 +6,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expense/v1"
-	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensestake/v1"
 	expensestakesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/expensestake/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
@@ -28,7 +27,7 @@ func (s *expensestakeServer) StreamExpenseStakeIdsInGroup(ctx context.Context, r
 	defer cancel()
 
 	if err := service.StreamResource(ctx, s.natsClient.Conn, fmt.Sprintf("%s.*", environment.GetExpenseStakeSubject(req.Msg.GetGroupId(), "*", "*")), func(ctx context.Context) (*expensestakesvcv1.StreamExpenseStakeIdsInGroupResponse, error) {
-		return sendCurrentExpenseStakeIdsInGroup(ctx, s.dbClient, req.Msg.GetGroupId())
+		return sendCurrentExpenseStakeIdsInGroup(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetGroupId())
 	}, srv, &streamExpenseStakeIdsInGroupAlive); err != nil {
 		if eris.Is(err, errSelectExpenseStakeIds) {
 			return errors.NewErrorWithDetails(
@@ -87,11 +86,11 @@ func sendCurrentExpenseStakeIdsInGroup(ctx context.Context, dbClient bun.IDB, gr
 
 	var expensestakeIds []string
 	if err := dbClient.NewSelect().
-		Model((*expensestakev1.ExpenseStake)(nil)).
+		Model((*model.ExpenseStake)(nil)).
 		Where(
 			"expense_id IN (?)",
 			dbClient.NewSelect().
-				Model((*expensev1.Expense)(nil)).
+				Model((*model.Expense)(nil)).
 				Column("id").
 				Where("group_id = ?", groupId),
 		).
@@ -0,0 +1,340 @@
+package export
+
+import (
+	"context"
+	"io"
+
+	archivev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/archive/v1"
+	exportsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/export/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/export/v1/exportv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/balance"
+	curClient "github.com/nico151999/high-availability-expense-splitter/pkg/currency/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/export"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var _ exportv1connect.ExportServiceHandler = (*exportServer)(nil)
+
+var errSelectGroupResources = eris.New("failed selecting resources of group")
+var errGetExchangeRate = eris.New("failed getting exchange rate into currency of group")
+var errMarshalArchive = eris.New("failed marshalling archive of group")
+var errSendContent = eris.New("failed sending the content")
+
+// archiveVersion is the version of the archives created by the export which is increased whenever the archive changes incompatibly
+const archiveVersion = 1
+
+// chunkSize is the maximum size of the chunks the exported file is sent in
+const chunkSize = 64 * 1024
+
+type exportServer struct {
+	dbClient bun.IDB
+	// dbReads is used by read-only endpoints while writes and reads within transactions always use dbClient
+	dbReads        *client.ReadRouter
+	currencyClient curClient.Client
+}
+
+// NewExportServer creates a new instance of export server. The context has no effect on the server's lifecycle.
+func NewExportServer(ctx context.Context, natsServer string, dbConfig client.Config) (*exportServer, error) {
+	log := logging.FromContext(ctx).Named("NewExportServer")
+	ctx = logging.IntoContext(ctx, log)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewExportServerWithDBClient(ctx, dbClient, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
+}
+
+// NewExportServerWithDBClient creates a new instance of export server. The context has no effect on the server's lifecycle.
+// It does not connect to the NATS server since exporting neither publishes nor subscribes to events.
+func NewExportServerWithDBClient(ctx context.Context, dbClient bun.IDB, _ string) (*exportServer, error) {
+	return &exportServer{
+		dbClient:       dbClient,
+		dbReads:        client.NewReadRouter(dbClient),
+		currencyClient: curClient.NewCachingCurrencyClient(curClient.NewCurrencyClient()),
+	}, nil
+}
+
+func (rps *exportServer) Close() error {
+	return rps.dbReads.Close()
+}
+
+// groupArchive returns the archive of the group with all of its non-deleted resources ordered by their IDs.
+// The resources are selected in a single read-only transaction so that the archive is a consistent snapshot of the group.
+func groupArchive(ctx context.Context, dbClient bun.IDB, currencyClient curClient.Client, groupId string) (*archivev1.GroupArchive, error) {
+	log := logging.FromContext(ctx)
+
+	var group *model.Group
+	var persons []*model.Person
+	var categories []*model.Category
+	var expenses []*model.Expense
+	var stakes []*model.ExpenseStake
+	var relations []*model.ExpenseCategoryRelation
+	var currencies []*model.Currency
+	if err := transaction.RunInReadOnlyTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		if group, err = util.CheckResourceExists[*model.Group](ctx, tx, groupId); err != nil {
+			return err
+		}
+		if err := tx.NewSelect().Model(&persons).Where("group_id = ?", groupId).Order("id").Scan(ctx); err != nil {
+			log.Error("failed selecting persons of group", logging.Error(err))
+			return errSelectGroupResources
+		}
+		if err := tx.NewSelect().Model(&categories).Where("group_id = ?", groupId).Order("id").Scan(ctx); err != nil {
+			log.Error("failed selecting categories of group", logging.Error(err))
+			return errSelectGroupResources
+		}
+		if err := tx.NewSelect().Model(&expenses).Where("group_id = ?", groupId).Order("id").Scan(ctx); err != nil {
+			log.Error("failed selecting expenses of group", logging.Error(err))
+			return errSelectGroupResources
+		}
+		groupExpenseIds := tx.NewSelect().Model((*model.Expense)(nil)).Column("id").Where("group_id = ?", groupId)
+		if err := tx.NewSelect().Model(&stakes).Where("expense_id IN (?)", groupExpenseIds).Order("id").Scan(ctx); err != nil {
+			log.Error("failed selecting expense stakes of group", logging.Error(err))
+			return errSelectGroupResources
+		}
+		if err := tx.NewSelect().Model(&relations).Where("expense_id IN (?)", groupExpenseIds).Order("expense_id", "category_id").Scan(ctx); err != nil {
+			log.Error("failed selecting expense category relations of group", logging.Error(err))
+			return errSelectGroupResources
+		}
+		if err := tx.NewSelect().Model(&currencies).
+			Where("id IN (?)", tx.NewSelect().Model((*model.Expense)(nil)).
+				Column("currency_id").
+				Where("group_id = ?", groupId)).
+			WhereOr("id = ?", group.GetCurrencyId()).
+			Order("id").
+			Scan(ctx); err != nil {
+			log.Error("failed selecting currencies of group", logging.Error(err))
+			return errSelectGroupResources
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	archive := &archivev1.GroupArchive{
+		Version:    archiveVersion,
+		CreateTime: timestamppb.Now(),
+		Group:      group.IntoProtoGroup(),
+	}
+	for _, currency := range currencies {
+		archive.Currencies = append(archive.Currencies, currency.IntoProtoCurrency())
+	}
+	for _, person := range persons {
+		archive.Persons = append(archive.Persons, person.IntoProtoPerson())
+	}
+	for _, category := range categories {
+		archive.Categories = append(archive.Categories, category.IntoProtoCategory())
+	}
+	for _, expense := range expenses {
+		archive.Expenses = append(archive.Expenses, expense.IntoProtoExpense())
+	}
+	for _, stake := range stakes {
+		archive.ExpenseStakes = append(archive.ExpenseStakes, stake.IntoProtoExpenseStake())
+	}
+	for _, relation := range relations {
+		archive.ExpenseCategoryRelations = append(archive.ExpenseCategoryRelations, relation.IntoProtoExpenseCategoryRelation())
+	}
+	var err error
+	if archive.Settlements, err = settlements(ctx, currencyClient, archive); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// settlements returns the transfers settling the balances of the persons of the archived group. The stakes are converted into the
+// currency of the group at the exchange rates of the days of their expenses.
+func settlements(ctx context.Context, currencyClient curClient.Client, archive *archivev1.GroupArchive) ([]*archivev1.Settlement, error) {
+	log := logging.FromContext(ctx)
+
+	groupCurrencyId := archive.GetGroup().GetCurrencyId()
+	acronyms := make(map[string]string, len(archive.GetCurrencies()))
+	for _, currency := range archive.GetCurrencies() {
+		acronyms[currency.GetId()] = currency.GetAcronym()
+	}
+	stakesByExpense := make(map[string][]balance.Stake, len(archive.GetExpenses()))
+	for _, stake := range archive.GetExpenseStakes() {
+		stakesByExpense[stake.GetExpenseId()] = append(stakesByExpense[stake.GetExpenseId()], balance.Stake{
+			ForId:  stake.GetForId(),
+			Amount: balance.Amount(stake.GetMainValue(), stake.GetFractionalValue()),
+		})
+	}
+	balanceExpenses := make([]balance.Expense, 0, len(archive.GetExpenses()))
+	for _, expense := range archive.GetExpenses() {
+		timestamp := expense.GetTimestamp().AsTime()
+		rate := 1.0
+		if expense.GetCurrencyId() != groupCurrencyId {
+			var err error
+			rate, err = currencyClient.GetExchangeRate(ctx, acronyms[expense.GetCurrencyId()], acronyms[groupCurrencyId], timestamp)
+			if err != nil {
+				log.Error("failed getting exchange rate", logging.String("expenseId", expense.GetId()), logging.Error(err))
+				return nil, eris.Wrap(err, errGetExchangeRate.Error())
+			}
+		}
+		expenseStakes := stakesByExpense[expense.GetId()]
+		for i := range expenseStakes {
+			expenseStakes[i].Amount = balance.Convert(expenseStakes[i].Amount, rate)
+		}
+		balanceExpenses = append(balanceExpenses, balance.Expense{
+			Time:   timestamp,
+			ById:   expense.GetById(),
+			Stakes: expenseStakes,
+		})
+	}
+
+	var settlements []*archivev1.Settlement
+	for _, transfer := range balance.Settle(balance.Balances(balanceExpenses)) {
+		mainValue, fractionalValue := balance.Split(transfer.Amount)
+		settlements = append(settlements, &archivev1.Settlement{
+			FromId:          transfer.FromId,
+			ToId:            transfer.ToId,
+			CurrencyId:      groupCurrencyId,
+			MainValue:       mainValue,
+			FractionalValue: fractionalValue,
+		})
+	}
+	return settlements, nil
+}
+
+// fileSpecs returns the suggested name and the media type of the file the group is exported into
+func fileSpecs(groupId string, format exportsvcv1.Format) (string, string) {
+	switch format {
+	case exportsvcv1.Format_FORMAT_CSV:
+		return groupId + ".csv", "text/csv; charset=utf-8"
+	case exportsvcv1.Format_FORMAT_JSON:
+		return groupId + ".json", "application/json"
+	case exportsvcv1.Format_FORMAT_HLEDGER:
+		return groupId + ".journal", "text/plain; charset=utf-8"
+	default:
+		return groupId + ".beancount", "text/plain; charset=utf-8"
+	}
+}
+
+// writeArchive writes the archive in the format to the writer which only fails if sending the written content fails
+func writeArchive(ctx context.Context, w io.Writer, archive *archivev1.GroupArchive, format exportsvcv1.Format) error {
+	log := logging.FromContext(ctx)
+
+	var err error
+	switch format {
+	case exportsvcv1.Format_FORMAT_JSON:
+		marshalled, marshalErr := protojson.MarshalOptions{Multiline: true}.Marshal(archive)
+		if marshalErr != nil {
+			log.Error("failed marshalling archive of group", logging.Error(marshalErr))
+			return errMarshalArchive
+		}
+		_, err = w.Write(marshalled)
+	case exportsvcv1.Format_FORMAT_CSV:
+		err = export.WriteCSV(w, intoExportGroup(archive))
+	case exportsvcv1.Format_FORMAT_HLEDGER:
+		err = export.WriteHledger(w, intoExportGroup(archive))
+	default:
+		err = export.WriteBeancount(w, intoExportGroup(archive))
+	}
+	if err != nil {
+		log.Info("failed sending content", logging.Error(err))
+		return errSendContent
+	}
+	return nil
+}
+
+// intoExportGroup converts the archive into the group written by the plain-text formats
+func intoExportGroup(archive *archivev1.GroupArchive) *export.Group {
+	acronyms := make(map[string]string, len(archive.GetCurrencies()))
+	for _, currency := range archive.GetCurrencies() {
+		acronyms[currency.GetId()] = currency.GetAcronym()
+	}
+	group := &export.Group{
+		Name:     archive.GetGroup().GetName(),
+		Currency: acronyms[archive.GetGroup().GetCurrencyId()],
+	}
+	for _, person := range archive.GetPersons() {
+		group.Persons = append(group.Persons, export.Person{
+			Id:   person.GetId(),
+			Name: person.GetName(),
+		})
+	}
+	for _, category := range archive.GetCategories() {
+		group.Categories = append(group.Categories, export.Category{
+			Id:   category.GetId(),
+			Name: category.GetName(),
+		})
+	}
+	stakes := make(map[string][]export.Stake, len(archive.GetExpenses()))
+	for _, stake := range archive.GetExpenseStakes() {
+		stakes[stake.GetExpenseId()] = append(stakes[stake.GetExpenseId()], export.Stake{
+			ForId:  stake.GetForId(),
+			Amount: balance.Amount(stake.GetMainValue(), stake.GetFractionalValue()),
+		})
+	}
+	categoryIds := make(map[string][]string, len(archive.GetExpenses()))
+	for _, relation := range archive.GetExpenseCategoryRelations() {
+		categoryIds[relation.GetExpenseId()] = append(categoryIds[relation.GetExpenseId()], relation.GetCategoryId())
+	}
+	for _, expense := range archive.GetExpenses() {
+		group.Expenses = append(group.Expenses, export.Expense{
+			Id:          expense.GetId(),
+			Name:        expense.GetName(),
+			Time:        expense.GetTimestamp().AsTime(),
+			ById:        expense.GetById(),
+			Currency:    acronyms[expense.GetCurrencyId()],
+			CategoryIds: categoryIds[expense.GetId()],
+			Stakes:      stakes[expense.GetId()],
+		})
+	}
+	for _, settlement := range archive.GetSettlements() {
+		group.Settlements = append(group.Settlements, export.Settlement{
+			FromId: settlement.GetFromId(),
+			ToId:   settlement.GetToId(),
+			Amount: balance.Amount(settlement.GetMainValue(), settlement.GetFractionalValue()),
+		})
+	}
+	return group
+}
+
+// chunkWriter sends everything written to it in chunks of at most chunkSize bytes
+type chunkWriter struct {
+	buf  []byte
+	send func(chunk []byte) error
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	written := len(p)
+	for len(p) > 0 {
+		n := chunkSize - len(w.buf)
+		if n > len(p) {
+			n = len(p)
+		}
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+		if len(w.buf) == chunkSize {
+			if err := w.Flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// Flush sends the content written since the last chunk was sent
+func (w *chunkWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	err := w.send(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
@@ -0,0 +1,104 @@
+package export
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	exportsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/export/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	curClient "github.com/nico151999/high-availability-expense-splitter/pkg/currency/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *exportServer) ExportGroup(ctx context.Context, req *connect.Request[exportsvcv1.ExportGroupRequest], srv *connect.ServerStream[exportsvcv1.ExportGroupResponse]) error {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"groupId",
+				req.Msg.GetGroupId())))
+
+	// the export takes as long as the client needs to receive the file which is why only the interaction with the database times out
+	if err := exportGroup(ctx, s.dbReads.For(req.Spec().Procedure), s.currencyClient, req.Msg.GetGroupId(), req.Msg.GetFormat(), srv.Send); err != nil {
+		if eris.Is(err, util.ErrSelectResource) || eris.Is(err, errSelectGroupResources) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errSendContent) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed sending the content",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetSendCurrentResourceErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if resErr := new(util.ResourceNotFoundError); eris.As(err, resErr) {
+			return connect.NewError(connect.CodeNotFound, eris.Errorf("the %s with ID %s does not exist", resErr.ResourceName, resErr.ResourceId))
+		} else if eris.Is(err, curClient.ErrCurrencyExchangeRateNotFound) {
+			return connect.NewError(connect.CodeUnavailable, eris.New("the exchange rate of an expense is not available"))
+		} else {
+			return connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+	return nil
+}
+
+// exportGroup passes the specs of the file the group is exported into followed by its content in chunks to send
+func exportGroup(ctx context.Context, dbClient bun.IDB, currencyClient curClient.Client, groupId string, format exportsvcv1.Format, send func(*exportsvcv1.ExportGroupResponse) error) error {
+	log := logging.FromContext(ctx)
+
+	selectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	archive, err := groupArchive(selectCtx, dbClient, currencyClient, groupId)
+	if err != nil {
+		return err
+	}
+
+	fileName, contentType := fileSpecs(groupId, format)
+	if err := send(&exportsvcv1.ExportGroupResponse{
+		Part: &exportsvcv1.ExportGroupResponse_Header_{
+			Header: &exportsvcv1.ExportGroupResponse_Header{
+				FileName:    fileName,
+				ContentType: contentType,
+			},
+		},
+	}); err != nil {
+		log.Info("failed sending specs of file", logging.Error(err))
+		return errSendContent
+	}
+	w := &chunkWriter{
+		buf: make([]byte, 0, chunkSize),
+		send: func(chunk []byte) error {
+			return send(&exportsvcv1.ExportGroupResponse{
+				Part: &exportsvcv1.ExportGroupResponse_Chunk{
+					Chunk: chunk,
+				},
+			})
+		},
+	}
+	if err := writeArchive(ctx, w, archive, format); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		log.Info("failed sending content", logging.Error(err))
+		return errSendContent
+	}
+	return nil
+}
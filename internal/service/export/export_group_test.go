@@ -0,0 +1,156 @@
+package export_test // the dedicated _test package prevents import cycles with the testing package
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/DATA-DOG/go-sqlmock"
+	archivev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/archive/v1"
+	exportsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/export/v1"
+	exportTesting "github.com/nico151999/high-availability-expense-splitter/internal/service/export/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestExportGroup(t *testing.T) {
+	log := logging.GetLogger().Named("testExportGroup")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	client, _, closeServer := exportTesting.SetupExportTest(t, ctx, bun.NewDB(db, pgdialect.New()))
+	// we want to close the server only which cascadingly closes the client as well
+	defer func() {
+		if err := closeServer(); err != nil {
+			t.Errorf("failed closing export server: %+v", err)
+		}
+	}()
+
+	groupId := "group-123456789012345"
+	currencyId := "currency-123456789012345"
+	categoryId := "category-123456789012345"
+	expenseId := "expense-123456789012345"
+	alice := "person-123456789012345"
+	bob := "person-543210987654321"
+	tsFormat := "2006-01-02 15:04:05-07"
+	timestamp := time.Date(2023, time.March, 14, 19, 30, 0, 0, time.UTC)
+	expectGroup := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "groups" (.+) WHERE (.+)"id" = '%s'(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "currency_id"}).
+				FromCSVString(fmt.Sprintf("%s,Trip,%s", groupId, currencyId)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "people" (.+) WHERE (.+)group_id = '%s'(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id", "name"}).
+				FromCSVString(fmt.Sprintf("%[1]s,%[2]s,Alice\n%[3]s,%[2]s,Bob", alice, groupId, bob)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "categories" (.+) WHERE (.+)group_id = '%s'(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id", "name"}).
+				FromCSVString(fmt.Sprintf("%s,%s,Food", categoryId, groupId)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "expenses" (.+) WHERE (.+)group_id = '%s'(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id", "name", "by_id", "timestamp", "currency_id"}).
+				FromCSVString(fmt.Sprintf("%s,%s,Dinner,%s,%s,%s", expenseId, groupId, alice, timestamp.Format(tsFormat), currencyId)))
+		mock.ExpectQuery(`SELECT (.+) FROM "expense_stakes" (.+) WHERE (.+)expense_id IN (.+)`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "expense_id", "for_id", "main_value", "fractional_value"}).
+				FromCSVString(fmt.Sprintf(
+					"expensestake-123456789012345,%[1]s,%[2]s,15,0\nexpensestake-543210987654321,%[1]s,%[3]s,20,50",
+					expenseId, alice, bob)))
+		mock.ExpectQuery(`SELECT (.+) FROM "expense_category_relations" (.+) WHERE (.+)expense_id IN (.+)`).
+			WillReturnRows(sqlmock.NewRows([]string{"expense_id", "category_id"}).
+				FromCSVString(fmt.Sprintf("%s,%s", expenseId, categoryId)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "currencies" (.+) WHERE (.+)id = '%s'(.+)`, currencyId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "acronym"}).
+				FromCSVString(fmt.Sprintf("%s,EUR", currencyId)))
+		mock.ExpectCommit()
+	}
+	exportGroup := func(format exportsvcv1.Format) (*exportsvcv1.ExportGroupResponse_Header, []byte, error) {
+		stream, err := client.ExportGroup(ctx, connect.NewRequest(&exportsvcv1.ExportGroupRequest{
+			GroupId: groupId,
+			Format:  format,
+		}))
+		if err != nil {
+			return nil, nil, err
+		}
+		var header *exportsvcv1.ExportGroupResponse_Header
+		var content bytes.Buffer
+		for stream.Receive() {
+			if h := stream.Msg().GetHeader(); h != nil {
+				header = h
+			} else {
+				content.Write(stream.Msg().GetChunk())
+			}
+		}
+		return header, content.Bytes(), stream.Err()
+	}
+
+	t.Run("Export Group as CSV successfully", func(t *testing.T) {
+		expectGroup()
+		header, content, err := exportGroup(exportsvcv1.Format_FORMAT_CSV)
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		if header.GetFileName() != groupId+".csv" {
+			t.Errorf("Expected the file name %s.csv; got: %s", groupId, header.GetFileName())
+		}
+		expected := fmt.Sprintf(`expense_id,expense_name,time,currency,paid_by,paid_for,amount,categories
+%[1]s,Dinner,2023-03-14T19:30:00Z,EUR,Alice,Alice,15.00,Food
+%[1]s,Dinner,2023-03-14T19:30:00Z,EUR,Alice,Bob,20.50,Food
+`, expenseId)
+		if string(content) != expected {
+			t.Errorf("Expected the content\n%s\ngot:\n%s", expected, content)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Export Group as JSON archive successfully", func(t *testing.T) {
+		expectGroup()
+		_, content, err := exportGroup(exportsvcv1.Format_FORMAT_JSON)
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		var archive archivev1.GroupArchive
+		if err := protojson.Unmarshal(content, &archive); err != nil {
+			t.Fatalf("Expected the content to be an archive: %+v", err)
+		}
+		if len(archive.GetPersons()) != 2 || len(archive.GetExpenseStakes()) != 2 || len(archive.GetExpenseCategoryRelations()) != 1 {
+			t.Errorf("Expected the archive to contain all resources of the group; got: %+v", &archive)
+		}
+		if settlements := archive.GetSettlements(); len(settlements) != 1 ||
+			settlements[0].GetFromId() != bob || settlements[0].GetToId() != alice ||
+			settlements[0].GetMainValue() != 20 || settlements[0].GetFractionalValue() != 50 {
+			t.Errorf("Expected bob to settle 20.50 with alice; got: %+v", settlements)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail exporting Group due to non existent group", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "groups" (.+) WHERE (.+)"id" = '%s'(.+)`, groupId)).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+		_, _, err := exportGroup(exportsvcv1.Format_FORMAT_HLEDGER)
+		if connectErr := new(connect.Error); eris.As(err, &connectErr) {
+			if connectErr.Code() != connect.CodeNotFound {
+				t.Fatalf("Expected code: %+v; got: %+v", connect.CodeNotFound, connectErr.Code())
+			}
+		} else {
+			t.Fatalf("Expected connect error, got: %+v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package export
+
+import (
+	"context"
+	"mime"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	exportsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/export/v1"
+	"github.com/rotisserie/eris"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	exportGroupProcedure = "/service.export.v1.ExportService/ExportGroup"
+	exportGroupPattern   = "/v1/groups/{group_id}/export"
+	// formatQueryParameter is the name of the query parameter selecting the format of the downloaded file
+	formatQueryParameter = "format"
+)
+
+// formats are the formats a group can be downloaded in by the values of the format query parameter
+var formats = map[string]exportsvcv1.Format{
+	"csv":       exportsvcv1.Format_FORMAT_CSV,
+	"json":      exportsvcv1.Format_FORMAT_JSON,
+	"hledger":   exportsvcv1.Format_FORMAT_HLEDGER,
+	"beancount": exportsvcv1.Format_FORMAT_BEANCOUNT,
+}
+
+// RegisterExportServiceHandler registers the download of the file a group is exported into, which cannot be expressed with HTTP annotations
+// since the export is a server stream. No REST endpoints are generated for the export service for the same reason.
+// The download calls the service through the passed connection so that it passes the same interceptors.
+func RegisterExportServiceHandler(_ context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	client := exportsvcv1.NewExportServiceClient(conn)
+	if err := mux.HandlePath(http.MethodGet, exportGroupPattern, downloadExportHandler(mux, client)); err != nil {
+		return eris.Wrap(err, "failed registering export download")
+	}
+	return nil
+}
+
+// downloadExportHandler serves the file a group is exported into by the export service in the format passed as query parameter
+func downloadExportHandler(mux *runtime.ServeMux, client exportsvcv1.ExportServiceClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		_, outboundMarshaler := runtime.MarshalerForRequest(mux, r)
+		ctx, err := runtime.AnnotateContext(ctx, mux, r, exportGroupProcedure, runtime.WithHTTPPathPattern(exportGroupPattern))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+
+		format, ok := formats[r.URL.Query().Get(formatQueryParameter)]
+		if !ok {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, status.Error(codes.InvalidArgument, "the format has to be one of csv, json, hledger and beancount"))
+			return
+		}
+		stream, err := client.ExportGroup(ctx, &exportsvcv1.ExportGroupRequest{
+			GroupId: pathParams["group_id"],
+			Format:  format,
+		})
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+		// the headers are only written once the service sent the specs of the file so that errors can still be reported
+		first, err := stream.Recv()
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+		header := first.GetHeader()
+		w.Header().Set("Content-Type", header.GetContentType())
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": header.GetFileName()}))
+		w.WriteHeader(http.StatusOK)
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				// the stream ends with io.EOF once the file is complete while a failure can only be told by the content being cut off
+				// since the status code was sent already
+				return
+			}
+			if _, err := w.Write(msg.GetChunk()); err != nil {
+				return
+			}
+		}
+	}
+}
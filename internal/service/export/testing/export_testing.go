@@ -0,0 +1,41 @@
+package testing
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/export/v1/exportv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/service/export"
+	clienttesting "github.com/nico151999/high-availability-expense-splitter/pkg/connect/client/testing"
+	servertesting "github.com/nico151999/high-availability-expense-splitter/pkg/connect/server/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/uptrace/bun"
+)
+
+// SetupExportTest creates gRPC server and client and returns instances of interfaces allowing to close both the server and the client. The passed context has no effect on the server's lifecycle.
+func SetupExportTest(t *testing.T, ctx context.Context, db bun.IDB) (exportv1connect.ExportServiceClient, net.Listener, func() error) {
+	log := logging.FromContext(ctx).Named("setupExportTest")
+	ctx = logging.IntoContext(ctx, log)
+
+	for k, v := range map[string]string{
+		"GLOBAL_DOMAIN":                      "de.test",
+		"DB_SELECT_ERROR_REASON":             "DB_SELECT_ERROR",
+		"SEND_CURRENT_RESOURCE_ERROR_REASON": "SEND_CURRENT_RESOURCE_ERROR",
+	} {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatalf("failed to set env variable %s: %+v", k, err)
+		}
+	}
+
+	ln, shutdownServer := servertesting.StartTestServer(
+		t,
+		ctx,
+		db,
+		export.NewExportServerWithDBClient,
+		export.RegisterExportServiceHandler,
+		exportv1connect.NewExportServiceHandler)
+	cl := clienttesting.SetupTestClient(ln, exportv1connect.NewExportServiceClient)
+	return cl, ln, shutdownServer
+}
@@ -2,18 +2,21 @@ package group
 
 import (
 	"context"
-	"database/sql"
 	"time"
 
 	"connectrpc.com/connect"
 	"github.com/nats-io/nats.go"
+	currencyv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/currency/v1"
 	groupv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/group/v1"
 	groupprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/group/v1"
 	groupsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/group/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -54,6 +57,8 @@ func (s *groupServer) CreateGroup(ctx context.Context, req *connect.Request[grou
 						Domain: environment.GetGlobalDomain(ctx),
 					},
 				})
+		} else if refErr := new(util.InvalidReferenceError); eris.As(err, refErr) {
+			return nil, errors.NewFieldViolationError(ctx, "the request references an invalid resource", refErr.Field, refErr.Description())
 		} else if resErr := new(util.ResourceNotFoundError); eris.As(err, resErr) {
 			return nil, connect.NewError(connect.CodeNotFound, eris.Errorf("the %s with ID %s does not exist", resErr.ResourceName, resErr.ResourceId))
 		} else {
@@ -72,28 +77,31 @@ func createGroup(ctx context.Context, nc *nats.EncodedConn, db bun.IDB, req *gro
 	groupId := util.GenerateIdWithPrefix("group")
 	requestorEmail := "ab@c.de" // TODO: take user email from context
 
-	if err := db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		// TODO: check if currency exists
-		if _, err := tx.NewInsert().Model(&groupv1.Group{
-			Id:         groupId,
-			Name:       req.GetName(),
-			CurrencyId: req.GetCurrencyId(),
-		}).Exec(ctx); err != nil {
+	group := model.NewGroup(&groupv1.Group{
+		Id:         groupId,
+		Name:       req.GetName(),
+		CurrencyId: req.GetCurrencyId(),
+	}, model.NewCreatedMetadata(ctx, time.Now()))
+	if err := transaction.RunInTx(ctx, db, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := util.CheckNonDeprecatedReference[*currencyv1.Currency](ctx, tx, "currency_id", req.GetCurrencyId()); err != nil {
+			return err
+		}
+		if _, err := tx.NewInsert().Model(group).Exec(ctx); err != nil {
 			log.Error("failed inserting group", logging.Error(err))
 			return errInsertGroup
 		}
-
-		if err := nc.Publish(environment.GetGroupCreatedSubject(groupId), &groupprocv1.GroupCreated{
-			Id:             groupId,
-			Name:           req.GetName(),
-			RequestorEmail: requestorEmail,
-		}); err != nil {
-			log.Error("failed publishing group created event", logging.Error(err))
-			return errPublishGroupCreated
-		}
 		return nil
 	}); err != nil {
 		return "", err
 	}
+
+	if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetGroupCreatedSubject(groupId), &groupprocv1.GroupCreated{
+		Id:             groupId,
+		Name:           req.GetName(),
+		RequestorEmail: requestorEmail,
+	}, group.IntoProtoGroup()); err != nil {
+		log.Error("failed publishing group created event", logging.Error(err))
+		return "", errPublishGroupCreated
+	}
 	return groupId, nil
 }
@@ -10,9 +10,13 @@ import (
 	groupv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/group/v1"
 	groupprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/group/v1"
 	groupsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/group/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -29,7 +33,7 @@ func (s *groupServer) DeleteGroup(ctx context.Context, req *connect.Request[grou
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	if err := deleteGroup(ctx, s.natsClient, s.dbClient, req.Msg.GetId()); err != nil {
+	if err := deleteGroup(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetEtag()); err != nil {
 		if eris.Is(err, errDeleteGroup) {
 			return nil, errors.NewErrorWithDetails(
 				ctx,
@@ -45,6 +49,18 @@ func (s *groupServer) DeleteGroup(ctx context.Context, req *connect.Request[grou
 			return nil, connect.NewError(
 				connect.CodeNotFound,
 				eris.New("the group ID does not exist"))
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the group was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
 		} else {
 			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
 		}
@@ -53,29 +69,48 @@ func (s *groupServer) DeleteGroup(ctx context.Context, req *connect.Request[grou
 	return connect.NewResponse(&groupsvcv1.DeleteGroupResponse{}), nil
 }
 
-func deleteGroup(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, groupId string) error {
+func deleteGroup(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, groupId string, etag string) error {
 	log := logging.FromContext(ctx)
 
-	return dbClient.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		group := groupv1.Group{
+	group := model.Group{
+		Group: groupv1.Group{
 			Id: groupId,
+		},
+	}
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := model.CheckCurrentEtag[*model.Group](ctx, tx, groupId, etag); err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("group not found", logging.Error(err))
+				return errNoGroupWithId
+			}
+			return err
 		}
-		if _, err := tx.NewDelete().Model(&group).WherePK().Exec(ctx); err != nil {
+		if err := util.UpdateMatchedReturning(ctx, tx, model.SoftDelete(tx.NewUpdate().Model(&group), groupId, time.Now()), func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereEtag(q.WherePK(), etag)
+		}, "id"); err != nil {
 			if eris.Is(err, sql.ErrNoRows) {
+				if etag != "" {
+					if err := model.ConcurrentEtagMismatch[*model.Group](ctx, tx, groupId); !eris.As(err, &util.ResourceNotFoundError{}) {
+						return err
+					}
+				}
 				log.Debug("group not found", logging.Error(err))
 				return errNoGroupWithId
 			}
 			log.Error("failed deleting group", logging.Error(err))
 			return errDeleteGroup
 		}
-
-		if err := nc.Publish(environment.GetGroupDeletedSubject(groupId), &groupprocv1.GroupDeleted{
-			Id: groupId,
-		}); err != nil {
-			log.Error("failed publishing group deleted event", logging.Error(err))
-			return errPublishGroupDeleted
-		}
-
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	if err := mqClient.PublishEvent(ctx, nc, environment.GetGroupDeletedSubject(groupId), &groupprocv1.GroupDeleted{
+		Id:          groupId,
+		DeleteCause: groupId,
+	}); err != nil {
+		log.Error("failed publishing group deleted event", logging.Error(err))
+		return errPublishGroupDeleted
+	}
+	return nil
 }
@@ -5,8 +5,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	groupv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/group/v1"
 	groupsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/group/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
@@ -26,7 +26,7 @@ func (s *groupServer) GetGroup(ctx context.Context, req *connect.Request[groupsv
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	group, err := util.CheckResourceExists[*groupv1.Group](ctx, s.dbClient, req.Msg.GetId())
+	group, err := util.CheckResourceExistsWithDeleted[*model.Group](ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetId())
 	if err != nil {
 		if eris.Is(err, util.ErrSelectResource) {
 			return nil, errors.NewErrorWithDetails(
@@ -47,6 +47,6 @@ func (s *groupServer) GetGroup(ctx context.Context, req *connect.Request[groupsv
 	}
 
 	return connect.NewResponse(&groupsvcv1.GetGroupResponse{
-		Group: group,
+		Group: group.IntoProtoGroup(),
 	}), nil
 }
@@ -18,25 +18,37 @@ var errNoGroupWithId = eris.New("there is no group with that ID")
 var errInsertGroup = eris.New("failed inserting group")
 var errPublishGroupCreated = eris.New("failed publishing group created event")
 var errPublishGroupDeleted = eris.New("failed publishing group deleted event")
+var errPublishGroupUndeleted = eris.New("failed publishing group undeleted event")
 var errPublishGroupUpdated = eris.New("failed publishing group updated event")
 var errSelectGroupIds = eris.New("failed selecting group IDs")
 var errDeleteGroup = eris.New("failed deleting group")
+var errUndeleteGroup = eris.New("failed restoring group")
 var errUpdateGroup = eris.New("failed updating group")
 
 type groupServer struct {
-	dbClient   bun.IDB
+	dbClient bun.IDB
+	// dbReads is used by read-only endpoints while writes and reads within transactions always use dbClient
+	dbReads    *client.ReadRouter
 	natsClient *nats.EncodedConn
 	// TODO: add clients to servers this server will communicate with
 }
 
 // NewGroupServer creates a new instance of group server. The context has no effect on the server's lifecycle.
-func NewGroupServer(ctx context.Context, natsServer, dbUser, dbPass, dbAddr, db string) (*groupServer, error) {
+func NewGroupServer(ctx context.Context, natsServer string, dbConfig client.Config) (*groupServer, error) {
 	log := logging.FromContext(ctx).Named("NewGroupServer")
 	ctx = logging.IntoContext(ctx, log)
-	return NewGroupServerWithDBClient(
-		ctx,
-		client.NewPostgresDBClient(dbUser, dbPass, dbAddr, db),
-		natsServer)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewGroupServerWithDBClient(ctx, dbClient, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
 }
 
 // NewGroupServerWithDBClient creates a new instance of group server. The context has no effect on the server's lifecycle.
@@ -50,11 +62,12 @@ func NewGroupServerWithDBClient(ctx context.Context, dbClient bun.IDB, natsServe
 	}
 	return &groupServer{
 		dbClient:   dbClient,
+		dbReads:    client.NewReadRouter(dbClient),
 		natsClient: nc,
 	}, nil
 }
 
 func (rps *groupServer) Close() error {
 	rps.natsClient.Close()
-	return nil
+	return rps.dbReads.Close()
 }
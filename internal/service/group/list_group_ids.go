@@ -5,8 +5,9 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	groupv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/group/v1"
+	metadatav1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/metadata/v1"
 	groupsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/group/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
@@ -20,7 +21,7 @@ func (s *groupServer) ListGroupIds(ctx context.Context, req *connect.Request[gro
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	groupIds, err := listGroupIds(ctx, s.dbClient)
+	groupIds, err := listGroupIds(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetOrderBy(), req.Msg.GetFilter(), req.Msg.GetDeleted())
 	if err != nil {
 		if eris.Is(err, errSelectGroupIds) {
 			return nil, errors.NewErrorWithDetails(
@@ -43,10 +44,14 @@ func (s *groupServer) ListGroupIds(ctx context.Context, req *connect.Request[gro
 	}), nil
 }
 
-func listGroupIds(ctx context.Context, dbClient bun.IDB) ([]string, error) {
+func listGroupIds(ctx context.Context, dbClient bun.IDB, order *metadatav1.MetadataOrder, filter *metadatav1.MetadataFilter, deleted bool) ([]string, error) {
 	log := logging.FromContext(ctx)
 	var groupIds []string
-	if err := dbClient.NewSelect().Model((*groupv1.Group)(nil)).Column("id").Order("name ASC").Scan(ctx, &groupIds); err != nil {
+	query := dbClient.NewSelect().Model((*model.Group)(nil)).Column("id")
+	if deleted {
+		query = query.WhereDeleted()
+	}
+	if err := model.ApplyMetadataListOptions(query, order, filter).Order("name ASC").Scan(ctx, &groupIds); err != nil {
 		log.Error("failed getting group IDs", logging.Error(err))
 		// TODO: determine reason why group ID couldn't be fetched and return error-specific ErrVariable; e.g. use unit testing with dummy return values to determine potential return values unless there is something in the bun documentation
 		return nil, errSelectGroupIds
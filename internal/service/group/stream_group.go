@@ -6,8 +6,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	groupv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/group/v1"
 	groupsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/group/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
@@ -35,7 +35,7 @@ func (s *groupServer) StreamGroup(ctx context.Context, req *connect.Request[grou
 	defer cancel()
 
 	if err := service.StreamResource(ctx, s.natsClient.Conn, fmt.Sprintf("%s.*", environment.GetGroupSubject(req.Msg.GetId())), func(ctx context.Context) (*groupsvcv1.StreamGroupResponse, error) {
-		return sendCurrentGroup(ctx, s.dbClient, req.Msg.GetId())
+		return sendCurrentGroup(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetId())
 	}, srv, &streamGroupAlive); err != nil {
 		if eris.Is(err, service.ErrResourceNoLongerFound) {
 			return connect.NewError(
@@ -87,13 +87,13 @@ func (s *groupServer) StreamGroup(ctx context.Context, req *connect.Request[grou
 }
 
 func sendCurrentGroup(ctx context.Context, dbClient bun.IDB, groupId string) (*groupsvcv1.StreamGroupResponse, error) {
-	group, err := util.CheckResourceExists[*groupv1.Group](ctx, dbClient, groupId)
+	group, err := util.CheckResourceExists[*model.Group](ctx, dbClient, groupId)
 	if err != nil {
 		return nil, err
 	}
 	return &groupsvcv1.StreamGroupResponse{
 		Update: &groupsvcv1.StreamGroupResponse_Group{
-			Group: group,
+			Group: group.IntoProtoGroup(),
 		},
 	}, nil
 }
@@ -6,8 +6,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	groupv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/group/v1"
 	groupsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/group/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
@@ -27,7 +27,7 @@ func (s *groupServer) StreamGroupIds(ctx context.Context, req *connect.Request[g
 	defer cancel()
 
 	if err := service.StreamResource(ctx, s.natsClient.Conn, fmt.Sprintf("%s.*", environment.GetGroupSubject("*")), func(ctx context.Context) (*groupsvcv1.StreamGroupIdsResponse, error) {
-		return sendCurrentGroupIds(ctx, s.dbClient)
+		return sendCurrentGroupIds(ctx, s.dbReads.For(req.Spec().Procedure))
 	}, srv, &streamGroupIdsAlive); err != nil {
 		if eris.Is(err, errSelectGroupIds) {
 			return errors.NewErrorWithDetails(
@@ -85,7 +85,7 @@ func sendCurrentGroupIds(ctx context.Context, dbClient bun.IDB) (*groupsvcv1.Str
 	log := logging.FromContext(ctx)
 
 	var groupIds []string
-	if err := dbClient.NewSelect().Model((*groupv1.Group)(nil)).Column("id").Order("name ASC").Scan(ctx, &groupIds); err != nil {
+	if err := dbClient.NewSelect().Model((*model.Group)(nil)).Column("id").Order("name ASC").Scan(ctx, &groupIds); err != nil {
 		log.Error("failed getting group IDs", logging.Error(err))
 		// TODO: determine reason why group IDs couldn't be fetched and return error-specific ErrVariable; e.g. use unit testing with dummy return values to determine potential return values unless there is something in the bun documentation
 		return nil, errSelectGroupIds
@@ -0,0 +1,110 @@
+package group
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/nats-io/nats.go"
+	groupv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/group/v1"
+	groupprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/group/v1"
+	groupsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/group/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *groupServer) UndeleteGroup(ctx context.Context, req *connect.Request[groupsvcv1.UndeleteGroupRequest]) (*connect.Response[groupsvcv1.UndeleteGroupResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"groupId",
+				req.Msg.GetId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	group, err := undeleteGroup(ctx, s.natsClient, s.dbClient, req.Msg.GetId())
+	if err != nil {
+		if eris.Is(err, errUndeleteGroup) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBUpdateErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoGroupWithId) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the group ID does not exist"))
+		} else if restoreErr := new(model.RestoreError); eris.As(err, restoreErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeFailedPrecondition,
+				"the group cannot be restored",
+				[]protoreflect.ProtoMessage{
+					&errdetails.PreconditionFailure{
+						Violations: []*errdetails.PreconditionFailure_Violation{
+							{
+								Type:        restoreErr.Type,
+								Subject:     restoreErr.Subject,
+								Description: restoreErr.Description,
+							},
+						},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&groupsvcv1.UndeleteGroupResponse{
+		Group: group,
+	}), nil
+}
+
+func undeleteGroup(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, groupId string) (*groupv1.Group, error) {
+	log := logging.FromContext(ctx)
+
+	var group *model.Group
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		group, err = model.CheckRestorable[*model.Group](ctx, tx, groupId)
+		if err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("group not found", logging.Error(err))
+				return errNoGroupWithId
+			}
+			return err
+		}
+		if err := model.Restore(ctx, tx, group); err != nil {
+			log.Error("failed restoring group", logging.Error(err))
+			return errUndeleteGroup
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := mqClient.PublishEvent(ctx, nc, environment.GetGroupUndeletedSubject(groupId), &groupprocv1.GroupUndeleted{
+		Id:          groupId,
+		DeleteCause: groupId,
+	}); err != nil {
+		log.Error("failed publishing group undeleted event", logging.Error(err))
+		return nil, errPublishGroupUndeleted
+	}
+
+	return group.IntoProtoGroup(), nil
+}
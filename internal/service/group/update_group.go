@@ -7,13 +7,17 @@ import (
 
 	"connectrpc.com/connect"
 	"github.com/nats-io/nats.go"
+	currencyv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/currency/v1"
 	groupv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/group/v1"
 	groupprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/group/v1"
 	groupsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/group/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -30,7 +34,7 @@ func (s *groupServer) UpdateGroup(ctx context.Context, req *connect.Request[grou
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	group, err := updateGroup(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetUpdateFields())
+	group, err := updateGroup(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetUpdateFields(), req.Msg.GetEtag())
 	if err != nil {
 		if eris.Is(err, errUpdateGroup) {
 			return nil, errors.NewErrorWithDetails(
@@ -47,8 +51,22 @@ func (s *groupServer) UpdateGroup(ctx context.Context, req *connect.Request[grou
 			return nil, connect.NewError(
 				connect.CodeNotFound,
 				eris.New("the group ID does not exist"))
+		} else if refErr := new(util.InvalidReferenceError); eris.As(err, refErr) {
+			return nil, errors.NewFieldViolationError(ctx, "the request references an invalid resource", refErr.Field, refErr.Description())
 		} else if resErr := new(util.ResourceNotFoundError); eris.As(err, resErr) {
 			return nil, connect.NewError(connect.CodeNotFound, eris.Errorf("the %s with ID %s does not exist", resErr.ResourceName, resErr.ResourceId))
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the group was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
 		} else {
 			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
 		}
@@ -59,44 +77,65 @@ func (s *groupServer) UpdateGroup(ctx context.Context, req *connect.Request[grou
 	}), nil
 }
 
-func updateGroup(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, groupId string, params []*groupsvcv1.UpdateGroupRequest_UpdateField) (*groupv1.Group, error) {
+func updateGroup(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, groupId string, params []*groupsvcv1.UpdateGroupRequest_UpdateField, etag string) (*groupv1.Group, error) {
 	log := logging.FromContext(ctx)
-	group := groupv1.Group{
+	group := model.NewGroup(&groupv1.Group{
 		Id: groupId,
-	}
+	}, model.NewModifiedMetadata(ctx, time.Now()))
 
-	if err := dbClient.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		query := tx.NewUpdate()
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := model.CheckCurrentEtag[*model.Group](ctx, tx, groupId, etag); err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("group not found", logging.Error(err))
+				return errNoGroupWithId
+			}
+			return err
+		}
+		query := model.IncrementRevision(tx.NewUpdate().Column(model.MetadataUpdateColumns...))
 		for _, param := range params {
 			switch param.GetUpdateOption().(type) {
 			case *groupsvcv1.UpdateGroupRequest_UpdateField_Name:
 				group.Name = param.GetName()
 				query.Column("name")
 			case *groupsvcv1.UpdateGroupRequest_UpdateField_CurrencyId:
-				// TODO: check if currency exists
+				if _, err := util.CheckNonDeprecatedReference[*currencyv1.Currency](ctx, tx, "currency_id", param.GetCurrencyId()); err != nil {
+					return err
+				}
 				group.CurrencyId = param.GetCurrencyId()
 				query.Column("currency_id")
 			}
 		}
-		if _, err := query.Model(&group).WherePK().Exec(ctx); err != nil {
+		if err := util.UpdateReturning(ctx, tx, model.MatchEtag(query.Model(group), etag), util.WherePK, append([]string{"revision"}, model.MetadataReturningColumns...)...); err != nil {
 			if eris.Is(err, sql.ErrNoRows) {
+				if etag != "" {
+					if err := model.ConcurrentEtagMismatch[*model.Group](ctx, tx, groupId); !eris.As(err, &util.ResourceNotFoundError{}) {
+						return err
+					}
+				}
 				log.Info("group not found", logging.Error(err))
 				return errNoGroupWithId
 			}
 			log.Error("failed updating group", logging.Error(err))
 			return errUpdateGroup
 		}
-
-		if err := nc.Publish(environment.GetGroupUpdatedSubject(groupId), &groupprocv1.GroupUpdated{
-			Id: groupId,
-		}); err != nil {
-			log.Error("failed publishing group updated event", logging.Error(err))
-			return errPublishGroupUpdated
+		// only the updated columns are returned but the event carries the whole group
+		updated, err := util.CheckResourceExists[*model.Group](ctx, tx, groupId)
+		if err != nil {
+			log.Error("failed getting updated group", logging.Error(err))
+			return errUpdateGroup
 		}
+		group = updated
 		return nil
 	}); err != nil {
 		return nil, err
 	}
 
-	return &group, nil
+	if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetGroupUpdatedSubject(groupId), &groupprocv1.GroupUpdated{
+		Id: groupId,
+	}, group.IntoProtoGroup()); err != nil {
+		log.Error("failed publishing group updated event", logging.Error(err))
+		return nil, errPublishGroupUpdated
+	}
+
+	return group.IntoProtoGroup(), nil
 }
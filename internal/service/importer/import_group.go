@@ -0,0 +1,289 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/nats-io/nats.go"
+	expensecategoryrelationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensecategoryrelation/v1"
+	categoryprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/category/v1"
+	expenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expense/v1"
+	expensecategoryrelationprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expensecategoryrelation/v1"
+	expensestakeprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/expensestake/v1"
+	personprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/person/v1"
+	importersvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/importer/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/importer"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *importerServer) ImportGroup(ctx context.Context, req *connect.Request[importersvcv1.ImportGroupRequest]) (*connect.Response[importersvcv1.ImportGroupResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"groupId",
+				req.Msg.GetGroupId())))
+	// an import inserts all rows of a file at once which is why it is granted more time than other requests
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	db := s.dbClient
+	if req.Msg.GetDryRun() {
+		db = s.dbReads.For(req.Spec().Procedure)
+	}
+	resp, err := importGroup(ctx, s.natsClient, db, req.Msg)
+	if err != nil {
+		if eris.Is(err, importer.ErrInvalidFile) {
+			return nil, errors.NewFieldViolationError(ctx, "the file cannot be read", "content", err.Error())
+		} else if eris.Is(err, errImportRows) {
+			violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(resp.GetErrors()))
+			for _, rowError := range resp.GetErrors() {
+				violations = append(violations, &errdetails.BadRequest_FieldViolation{
+					Field:       "content",
+					Description: fmt.Sprintf("row %d: %s", rowError.GetRow(), rowError.GetMessage()),
+				})
+			}
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInvalidArgument,
+				"rows of the file cannot be imported",
+				[]protoreflect.ProtoMessage{
+					&errdetails.BadRequest{
+						FieldViolations: violations,
+					},
+				})
+		} else if eris.Is(err, errPublishImportCreated) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed finalizing import",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetMessagePublicationErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errInsertImport) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBInsertErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, util.ErrSelectResource) || eris.Is(err, errSelectGroupResources) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if resErr := new(util.ResourceNotFoundError); eris.As(err, resErr) {
+			return nil, connect.NewError(connect.CodeNotFound, eris.Errorf("the %s with ID %s does not exist", resErr.ResourceName, resErr.ResourceId))
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+// importGroup reads the file and returns the preview of its import. Unless it is a dry run, the planned persons, categories, expenses,
+// stakes and category relations are created in a single transaction, which fails along with the preview if a row cannot be imported.
+func importGroup(ctx context.Context, nc *nats.EncodedConn, db bun.IDB, req *importersvcv1.ImportGroupRequest) (*importersvcv1.ImportGroupResponse, error) {
+	file, err := readFile(req.GetSource(), req.GetContent())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetDryRun() {
+		plan, err := planImport(ctx, db, req.GetGroupId(), file)
+		if err != nil {
+			return nil, err
+		}
+		return plan.response, nil
+	}
+
+	var plan *importPlan
+	if err := transaction.RunInTx(ctx, db, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		if plan, err = planImport(ctx, tx, req.GetGroupId(), file); err != nil {
+			return err
+		}
+		if len(plan.response.GetErrors()) > 0 {
+			return errImportRows
+		}
+		return insertImport(ctx, tx, plan)
+	}); err != nil {
+		if eris.Is(err, errImportRows) {
+			return plan.response, err
+		}
+		return nil, err
+	}
+	for i, expense := range plan.expenses {
+		plan.response.Expenses[i].Id = expense.expense.GetId()
+	}
+
+	if err := publishImport(ctx, nc, req.GetGroupId(), plan); err != nil {
+		return nil, err
+	}
+	return plan.response, nil
+}
+
+// insertImport inserts the planned resources and records the first revision of each expense
+func insertImport(ctx context.Context, tx bun.Tx, plan *importPlan) error {
+	log := logging.FromContext(ctx)
+
+	metadata := model.NewCreatedMetadata(ctx, time.Now())
+	if len(plan.persons) > 0 {
+		persons := make([]*model.Person, 0, len(plan.persons))
+		for _, person := range plan.persons {
+			persons = append(persons, model.NewPerson(person, metadata))
+		}
+		if _, err := tx.NewInsert().Model(&persons).Exec(ctx); err != nil {
+			log.Error("failed inserting persons", logging.Error(err))
+			return errInsertImport
+		}
+	}
+	if len(plan.categories) > 0 {
+		categories := make([]*model.Category, 0, len(plan.categories))
+		for _, category := range plan.categories {
+			categories = append(categories, model.NewCategory(category, metadata))
+		}
+		if _, err := tx.NewInsert().Model(&categories).Exec(ctx); err != nil {
+			log.Error("failed inserting categories", logging.Error(err))
+			return errInsertImport
+		}
+	}
+	if len(plan.expenses) == 0 {
+		return nil
+	}
+	expenses := make([]*model.Expense, 0, len(plan.expenses))
+	var stakes []*model.ExpenseStake
+	var relations []*model.ExpenseCategoryRelation
+	for _, planned := range plan.expenses {
+		expenses = append(expenses, model.NewExpense(planned.expense, metadata))
+		for _, stake := range planned.stakes {
+			stakes = append(stakes, model.NewExpenseStake(stake, metadata))
+		}
+		for _, categoryId := range planned.categoryIds {
+			relations = append(relations, model.NewExpenseCategoryRelation(&expensecategoryrelationv1.ExpenseCategoryRelation{
+				ExpenseId:  planned.expense.GetId(),
+				CategoryId: categoryId,
+			}, metadata))
+		}
+	}
+	if _, err := tx.NewInsert().Model(&expenses).Exec(ctx); err != nil {
+		log.Error("failed inserting expenses", logging.Error(err))
+		return errInsertImport
+	}
+	if len(stakes) > 0 {
+		if _, err := tx.NewInsert().Model(&stakes).Exec(ctx); err != nil {
+			log.Error("failed inserting expense stakes", logging.Error(err))
+			return errInsertImport
+		}
+	}
+	if len(relations) > 0 {
+		if _, err := tx.NewInsert().Model(&relations).Exec(ctx); err != nil {
+			log.Error("failed inserting expense category relations", logging.Error(err))
+			return errInsertImport
+		}
+	}
+	for _, expense := range expenses {
+		if _, err := model.RecordExpenseRevision(ctx, tx, expense.Id); err != nil {
+			log.Error("failed recording expense revision", logging.String("expenseId", expense.Id), logging.Error(err))
+			return errInsertImport
+		}
+	}
+	return nil
+}
+
+// publishImport publishes the created events of all imported resources
+func publishImport(ctx context.Context, nc *nats.EncodedConn, groupId string, plan *importPlan) error {
+	log := logging.FromContext(ctx)
+
+	requestorEmail := principal.FromContext(ctx)
+	for _, person := range plan.persons {
+		if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetPersonCreatedSubject(groupId, person.GetId()), &personprocv1.PersonCreated{
+			Id:             person.GetId(),
+			GroupId:        groupId,
+			Name:           person.GetName(),
+			RequestorEmail: requestorEmail,
+		}, person); err != nil {
+			log.Error("failed publishing person created event", logging.Error(err))
+			return errPublishImportCreated
+		}
+	}
+	for _, category := range plan.categories {
+		if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetCategoryCreatedSubject(groupId, category.GetId()), &categoryprocv1.CategoryCreated{
+			Id:             category.GetId(),
+			GroupId:        groupId,
+			Name:           category.GetName(),
+			RequestorEmail: requestorEmail,
+		}, category); err != nil {
+			log.Error("failed publishing category created event", logging.Error(err))
+			return errPublishImportCreated
+		}
+	}
+	for _, planned := range plan.expenses {
+		expense := planned.expense
+		if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetExpenseCreatedSubject(groupId, expense.GetId()), &expenseprocv1.ExpenseCreated{
+			Id:             expense.GetId(),
+			GroupId:        groupId,
+			Name:           expense.Name,
+			ById:           expense.GetById(),
+			Timestamp:      expense.GetTimestamp(),
+			CurrencyId:     expense.GetCurrencyId(),
+			RequestorEmail: requestorEmail,
+		}, expense); err != nil {
+			log.Error("failed publishing expense created event", logging.Error(err))
+			return errPublishImportCreated
+		}
+		for _, stake := range planned.stakes {
+			if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetExpenseStakeCreatedSubject(groupId, expense.GetId(), stake.GetId()), &expensestakeprocv1.ExpenseStakeCreated{
+				Id:              stake.GetId(),
+				ExpenseId:       expense.GetId(),
+				ForId:           stake.GetForId(),
+				MainValue:       stake.GetMainValue(),
+				FractionalValue: stake.FractionalValue,
+				RequestorEmail:  requestorEmail,
+			}, stake); err != nil {
+				log.Error("failed publishing expense stake created event", logging.Error(err))
+				return errPublishImportCreated
+			}
+		}
+		for _, categoryId := range planned.categoryIds {
+			if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetExpenseCategoryRelationCreatedSubject(groupId, expense.GetId(), categoryId), &expensecategoryrelationprocv1.ExpenseCategoryRelationCreated{
+				ExpenseId:      expense.GetId(),
+				CategoryId:     categoryId,
+				RequestorEmail: requestorEmail,
+			}, &expensecategoryrelationv1.ExpenseCategoryRelation{
+				ExpenseId:  expense.GetId(),
+				CategoryId: categoryId,
+			}); err != nil {
+				log.Error("failed publishing expense category relation created event", logging.Error(err))
+				return errPublishImportCreated
+			}
+		}
+	}
+	return nil
+}
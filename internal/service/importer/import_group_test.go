@@ -0,0 +1,122 @@
+package importer_test // the dedicated _test package prevents import cycles with the testing package
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/DATA-DOG/go-sqlmock"
+	importersvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/importer/v1"
+	importerTesting "github.com/nico151999/high-availability-expense-splitter/internal/service/importer/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestImportGroup(t *testing.T) {
+	log := logging.GetLogger().Named("testImportGroup")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	client, _, closeServer := importerTesting.SetupImporterTest(t, ctx, bun.NewDB(db, pgdialect.New()))
+	// we want to close the server only which cascadingly closes the client as well
+	defer func() {
+		if err := closeServer(); err != nil {
+			t.Errorf("failed closing importer server: %+v", err)
+		}
+	}()
+
+	groupId := "group-123456789012345"
+	currencyId := "currency-123456789012345"
+	alice := "person-123456789012345"
+	content := []byte(`Date,Description,Category,Cost,Currency,alice,Bob
+2023-03-14,Dinner,Dining out,30.00,eur,20.00,-20.00
+2023-03-15,Taxi,,12.50,USD,-12.50,12.50
+`)
+	expectCode := func(t *testing.T, err error, code connect.Code) {
+		if connectErr := new(connect.Error); eris.As(err, &connectErr) {
+			if connectErr.Code() != code {
+				t.Fatalf("Expected code: %+v; got: %+v", code, connectErr.Code())
+			}
+		} else {
+			t.Fatalf("Expected connect error, got: %+v", err)
+		}
+	}
+
+	t.Run("Preview ImportGroup successfully", func(t *testing.T) {
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "groups" (.+) WHERE (.+)"id" = '%s'(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "currency_id"}).
+				FromCSVString(fmt.Sprintf("%s,%s", groupId, currencyId)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "people" (.+) WHERE (.+)group_id = '%s'(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id", "name"}).
+				FromCSVString(fmt.Sprintf("%s,%s,Alice", alice, groupId)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "categories" (.+) WHERE (.+)group_id = '%s'(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id", "name"}))
+		mock.ExpectQuery(`SELECT (.+) FROM "currencies" (.+) WHERE (.+)UPPER\(acronym\) IN \('EUR', 'USD'\)(.+)`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "acronym"}).
+				FromCSVString(fmt.Sprintf("%s,EUR", currencyId)))
+		resp, err := client.ImportGroup(ctx, connect.NewRequest(&importersvcv1.ImportGroupRequest{
+			GroupId: groupId,
+			Source:  importersvcv1.Source_SOURCE_SPLITWISE_CSV,
+			Content: content,
+			DryRun:  true,
+		}))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		if expenses := resp.Msg.GetExpenses(); len(expenses) != 1 ||
+			expenses[0].GetRow() != 2 || expenses[0].GetId() != "" || expenses[0].GetCurrencyId() != currencyId ||
+			expenses[0].GetByName() != "alice" || len(expenses[0].GetStakes()) != 2 || expenses[0].GetStakes()[0].GetForName() != "Bob" {
+			t.Errorf("Expected the dinner paid by alice to be previewed without ID; got: %+v", expenses)
+		}
+		if names := resp.Msg.GetCreatedPersonNames(); len(names) != 1 || names[0] != "Bob" {
+			t.Errorf("Expected Bob to be created since alice matches Alice; got: %+v", names)
+		}
+		if names := resp.Msg.GetCreatedCategoryNames(); len(names) != 1 || names[0] != "Dining out" {
+			t.Errorf("Expected the category Dining out to be created; got: %+v", names)
+		}
+		if errs := resp.Msg.GetErrors(); len(errs) != 1 || errs[0].GetRow() != 3 {
+			t.Errorf("Expected the taxi in an unknown currency to be reported; got: %+v", errs)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail ImportGroup due to an unreadable file", func(t *testing.T) {
+		resp, err := client.ImportGroup(ctx, connect.NewRequest(&importersvcv1.ImportGroupRequest{
+			GroupId: groupId,
+			Source:  importersvcv1.Source_SOURCE_ARCHIVE,
+			Content: content,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectCode(t, err, connect.CodeInvalidArgument)
+	})
+
+	t.Run("Fail ImportGroup due to non existent group", func(t *testing.T) {
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "groups" (.+) WHERE (.+)"id" = '%s'(.+)`, groupId)).WillReturnError(sql.ErrNoRows)
+		resp, err := client.ImportGroup(ctx, connect.NewRequest(&importersvcv1.ImportGroupRequest{
+			GroupId: groupId,
+			Source:  importersvcv1.Source_SOURCE_SPLITWISE_CSV,
+			Content: content,
+			DryRun:  true,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectCode(t, err, connect.CodeNotFound)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+}
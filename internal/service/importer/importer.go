@@ -0,0 +1,438 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nats-io/nats.go"
+	archivev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/archive/v1"
+	categoryv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/category/v1"
+	expensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expense/v1"
+	expensestakev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/expensestake/v1"
+	personv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/person/v1"
+	importersvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/importer/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/importer/v1/importerv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/balance"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/importer"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var _ importerv1connect.ImporterServiceHandler = (*importerServer)(nil)
+
+var errSelectGroupResources = eris.New("failed selecting resources of group")
+var errImportRows = eris.New("rows of the file cannot be imported")
+var errInsertImport = eris.New("failed inserting imported resources")
+var errPublishImportCreated = eris.New("failed publishing created event of imported resource")
+
+// archiveVersion is the version of the archives created by the export which the import is able to read
+const archiveVersion = 1
+
+// maxNameLength is the maximum length of the names of persons, categories and expenses
+const maxNameLength = 100
+
+type importerServer struct {
+	dbClient bun.IDB
+	// dbReads is used by read-only endpoints while writes and reads within transactions always use dbClient
+	dbReads    *client.ReadRouter
+	natsClient *nats.EncodedConn
+}
+
+// NewImporterServer creates a new instance of importer server. The context has no effect on the server's lifecycle.
+func NewImporterServer(ctx context.Context, natsServer string, dbConfig client.Config) (*importerServer, error) {
+	log := logging.FromContext(ctx).Named("NewImporterServer")
+	ctx = logging.IntoContext(ctx, log)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewImporterServerWithDBClient(ctx, dbClient, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
+}
+
+// NewImporterServerWithDBClient creates a new instance of importer server. The context has no effect on the server's lifecycle.
+func NewImporterServerWithDBClient(ctx context.Context, dbClient bun.IDB, natsServer string) (*importerServer, error) {
+	log := logging.FromContext(ctx).Named("NewImporterServerWithDBClient")
+	nc, err := mqClient.NewProtoMQClient(natsServer)
+	if err != nil {
+		msg := "failed connecting to NATS server"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	return &importerServer{
+		dbClient:   dbClient,
+		dbReads:    client.NewReadRouter(dbClient),
+		natsClient: nc,
+	}, nil
+}
+
+func (rps *importerServer) Close() error {
+	rps.natsClient.Close()
+	return rps.dbReads.Close()
+}
+
+// readFile reads the persons, categories and expenses of the file exported by the source
+func readFile(source importersvcv1.Source, content []byte) (*importer.Group, error) {
+	switch source {
+	case importersvcv1.Source_SOURCE_SPLITWISE_CSV:
+		return importer.ReadSplitwiseCSV(strings.NewReader(string(content)))
+	case importersvcv1.Source_SOURCE_TRICOUNT_CSV:
+		return importer.ReadTricountCSV(strings.NewReader(string(content)))
+	case importersvcv1.Source_SOURCE_ARCHIVE:
+		archive := &archivev1.GroupArchive{}
+		if err := protojson.Unmarshal(content, archive); err != nil {
+			return nil, eris.Wrap(importer.ErrInvalidFile, "the archive is no valid JSON")
+		}
+		if archive.GetVersion() != archiveVersion {
+			return nil, eris.Wrapf(importer.ErrInvalidFile, "archives of version %d are not supported", archive.GetVersion())
+		}
+		return archiveGroup(archive), nil
+	default:
+		return nil, eris.Wrap(importer.ErrInvalidFile, "the source is not supported")
+	}
+}
+
+// archiveGroup turns an archive into the group to import. The expenses are numbered in the order of the archive starting with 1 and
+// resolved from IDs to the names of their persons and categories and the acronyms of their currencies.
+func archiveGroup(archive *archivev1.GroupArchive) *importer.Group {
+	group := &importer.Group{}
+	persons := make(map[string]string, len(archive.GetPersons()))
+	for _, person := range archive.GetPersons() {
+		persons[person.GetId()] = person.GetName()
+		group.Persons = append(group.Persons, person.GetName())
+	}
+	categories := make(map[string]string, len(archive.GetCategories()))
+	for _, category := range archive.GetCategories() {
+		categories[category.GetId()] = category.GetName()
+		group.Categories = append(group.Categories, category.GetName())
+	}
+	currencies := make(map[string]string, len(archive.GetCurrencies()))
+	for _, currency := range archive.GetCurrencies() {
+		currencies[currency.GetId()] = currency.GetAcronym()
+	}
+	stakes := make(map[string][]*expensestakev1.ExpenseStake)
+	for _, stake := range archive.GetExpenseStakes() {
+		stakes[stake.GetExpenseId()] = append(stakes[stake.GetExpenseId()], stake)
+	}
+	relations := make(map[string][]string)
+	for _, relation := range archive.GetExpenseCategoryRelations() {
+		relations[relation.GetExpenseId()] = append(relations[relation.GetExpenseId()], relation.GetCategoryId())
+	}
+
+	for i, expense := range archive.GetExpenses() {
+		row := i + 1
+		rowErr := func(message string) {
+			group.Errors = append(group.Errors, importer.RowError{Row: row, Message: message})
+		}
+		byName, ok := persons[expense.GetById()]
+		if !ok {
+			rowErr(fmt.Sprintf("the payer %s is not part of the archive", expense.GetById()))
+			continue
+		}
+		currency, ok := currencies[expense.GetCurrencyId()]
+		if !ok {
+			rowErr(fmt.Sprintf("the currency %s is not part of the archive", expense.GetCurrencyId()))
+			continue
+		}
+		imported := importer.Expense{
+			Row:      row,
+			Name:     expense.GetName(),
+			Time:     expense.GetTimestamp().AsTime(),
+			ByName:   byName,
+			Currency: currency,
+		}
+		valid := true
+		for _, stake := range stakes[expense.GetId()] {
+			forName, ok := persons[stake.GetForId()]
+			if !ok {
+				rowErr(fmt.Sprintf("the person %s of a stake is not part of the archive", stake.GetForId()))
+				valid = false
+				break
+			}
+			imported.Stakes = append(imported.Stakes, importer.Stake{
+				ForName: forName,
+				Amount:  balance.Amount(stake.GetMainValue(), stake.GetFractionalValue()),
+			})
+		}
+		for _, categoryId := range relations[expense.GetId()] {
+			name, ok := categories[categoryId]
+			if !ok {
+				rowErr(fmt.Sprintf("the category %s is not part of the archive", categoryId))
+				valid = false
+				break
+			}
+			imported.Categories = append(imported.Categories, name)
+		}
+		if valid {
+			group.Expenses = append(group.Expenses, imported)
+		}
+	}
+	return group
+}
+
+// importPlan is what an import creates along with its preview
+type importPlan struct {
+	persons    []*personv1.Person
+	categories []*categoryv1.Category
+	expenses   []plannedExpense
+	response   *importersvcv1.ImportGroupResponse
+}
+
+type plannedExpense struct {
+	expense     *expensev1.Expense
+	stakes      []*expensestakev1.ExpenseStake
+	categoryIds []string
+}
+
+// planImport matches the persons and categories of the file with the ones of the group by their names ignoring case and surrounding
+// spaces as well as the currencies of the expenses with the existing currencies by their acronyms. The persons and categories missing in
+// the group are planned to be created. Expenses which cannot be imported are left out and reported as row errors.
+func planImport(ctx context.Context, db bun.IDB, groupId string, file *importer.Group) (*importPlan, error) {
+	log := logging.FromContext(ctx)
+
+	if _, err := util.CheckResourceExists[*model.Group](ctx, db, groupId); err != nil {
+		return nil, err
+	}
+	var persons []*model.Person
+	if err := db.NewSelect().Model(&persons).Where("group_id = ?", groupId).Order("id").Scan(ctx); err != nil {
+		log.Error("failed selecting persons of group", logging.Error(err))
+		return nil, errSelectGroupResources
+	}
+	var categories []*model.Category
+	if err := db.NewSelect().Model(&categories).Where("group_id = ?", groupId).Order("id").Scan(ctx); err != nil {
+		log.Error("failed selecting categories of group", logging.Error(err))
+		return nil, errSelectGroupResources
+	}
+	acronyms := newNameIndex()
+	for _, expense := range file.Expenses {
+		acronyms.add(strings.ToUpper(strings.TrimSpace(expense.Currency)), "")
+	}
+	currencyIds := make(map[string]string)
+	if len(acronyms.names) > 0 {
+		var currencies []*model.Currency
+		if err := db.NewSelect().Model(&currencies).Where("UPPER(acronym) IN (?)", bun.In(acronyms.names)).Order("id").Scan(ctx); err != nil {
+			log.Error("failed selecting currencies of expenses", logging.Error(err))
+			return nil, errSelectGroupResources
+		}
+		for _, currency := range currencies {
+			// imported expenses are created which is why they must not reference deprecated currencies
+			if currency.GetDeprecated() {
+				continue
+			}
+			if _, ok := currencyIds[strings.ToUpper(currency.GetAcronym())]; !ok {
+				currencyIds[strings.ToUpper(currency.GetAcronym())] = currency.GetId()
+			}
+		}
+	}
+
+	plan := &importPlan{response: &importersvcv1.ImportGroupResponse{}}
+	personIds := newNameIndex()
+	for _, person := range persons {
+		personIds.add(person.GetName(), person.GetId())
+	}
+	categoryIds := newNameIndex()
+	for _, category := range categories {
+		categoryIds.add(category.GetName(), category.GetId())
+	}
+	// resolvePerson returns the ID of the person with the name and plans to create the person if it is missing
+	resolvePerson := func(name string) (string, error) {
+		if id, ok := personIds.get(name); ok {
+			return id, nil
+		}
+		if err := checkName("person", name); err != nil {
+			return "", err
+		}
+		person := &personv1.Person{
+			Id:      util.GenerateIdWithPrefix("person"),
+			GroupId: groupId,
+			Name:    strings.TrimSpace(name),
+		}
+		personIds.add(name, person.GetId())
+		plan.persons = append(plan.persons, person)
+		plan.response.CreatedPersonNames = append(plan.response.CreatedPersonNames, person.GetName())
+		return person.GetId(), nil
+	}
+	// resolveCategory returns the ID of the category with the name and plans to create the category if it is missing
+	resolveCategory := func(name string) (string, error) {
+		if id, ok := categoryIds.get(name); ok {
+			return id, nil
+		}
+		if err := checkName("category", name); err != nil {
+			return "", err
+		}
+		category := &categoryv1.Category{
+			Id:      util.GenerateIdWithPrefix("category"),
+			GroupId: groupId,
+			Name:    strings.TrimSpace(name),
+		}
+		categoryIds.add(name, category.GetId())
+		plan.categories = append(plan.categories, category)
+		plan.response.CreatedCategoryNames = append(plan.response.CreatedCategoryNames, category.GetName())
+		return category.GetId(), nil
+	}
+
+	rowErrors := file.Errors
+	for _, expense := range file.Expenses {
+		planned, imported, err := planExpense(groupId, expense, currencyIds, resolvePerson, resolveCategory)
+		if err != nil {
+			rowErrors = append(rowErrors, importer.RowError{Row: expense.Row, Message: err.Error()})
+			continue
+		}
+		plan.expenses = append(plan.expenses, *planned)
+		plan.response.Expenses = append(plan.response.Expenses, imported)
+	}
+	// the persons and categories without any expense are created as well unless their names are invalid, in which case they are left out
+	for _, name := range file.Persons {
+		_, _ = resolvePerson(name)
+	}
+	for _, name := range file.Categories {
+		_, _ = resolveCategory(name)
+	}
+
+	sort.SliceStable(rowErrors, func(i, j int) bool {
+		return rowErrors[i].Row < rowErrors[j].Row
+	})
+	for _, rowError := range rowErrors {
+		plan.response.Errors = append(plan.response.Errors, &importersvcv1.RowError{
+			Row:     uint32(rowError.Row),
+			Message: rowError.Message,
+		})
+	}
+	return plan, nil
+}
+
+// planExpense resolves the names and currency of an expense read from the file into the expense to create and its preview
+func planExpense(
+	groupId string,
+	expense importer.Expense,
+	currencyIds map[string]string,
+	resolvePerson func(string) (string, error),
+	resolveCategory func(string) (string, error),
+) (*plannedExpense, *importersvcv1.ImportedExpense, error) {
+	if utf8.RuneCountInString(expense.Name) > maxNameLength {
+		return nil, nil, eris.Errorf("the name of the expense is longer than %d characters", maxNameLength)
+	}
+	currencyId, ok := currencyIds[strings.ToUpper(strings.TrimSpace(expense.Currency))]
+	if !ok {
+		return nil, nil, eris.Errorf("the currency %q does not exist or is deprecated", expense.Currency)
+	}
+	byId, err := resolvePerson(expense.ByName)
+	if err != nil {
+		return nil, nil, err
+	}
+	var name *string
+	if expense.Name != "" {
+		name = &expense.Name
+	}
+	planned := &plannedExpense{
+		expense: &expensev1.Expense{
+			Id:         util.GenerateIdWithPrefix("expense"),
+			GroupId:    groupId,
+			Name:       name,
+			ById:       byId,
+			Timestamp:  timestamppb.New(expense.Time),
+			CurrencyId: currencyId,
+		},
+	}
+	imported := &importersvcv1.ImportedExpense{
+		Row:        uint32(expense.Row),
+		Name:       name,
+		Timestamp:  planned.expense.GetTimestamp(),
+		ByName:     strings.TrimSpace(expense.ByName),
+		CurrencyId: currencyId,
+	}
+	for _, stake := range expense.Stakes {
+		if stake.Amount <= 0 {
+			return nil, nil, eris.Errorf("the stake of %s is not positive", stake.ForName)
+		}
+		forId, err := resolvePerson(stake.ForName)
+		if err != nil {
+			return nil, nil, err
+		}
+		mainValue, fractionalValue := balance.Split(stake.Amount)
+		planned.stakes = append(planned.stakes, &expensestakev1.ExpenseStake{
+			Id:              util.GenerateIdWithPrefix("expensestake"),
+			ExpenseId:       planned.expense.GetId(),
+			ForId:           forId,
+			MainValue:       mainValue,
+			FractionalValue: &fractionalValue,
+		})
+		imported.Stakes = append(imported.Stakes, &importersvcv1.ImportedStake{
+			ForName:         strings.TrimSpace(stake.ForName),
+			MainValue:       mainValue,
+			FractionalValue: fractionalValue,
+		})
+	}
+	for _, categoryName := range expense.Categories {
+		categoryId, err := resolveCategory(categoryName)
+		if err != nil {
+			return nil, nil, err
+		}
+		// categories whose names only differ in case are the same category which an expense is related to once
+		if containsId(planned.categoryIds, categoryId) {
+			continue
+		}
+		planned.categoryIds = append(planned.categoryIds, categoryId)
+		imported.CategoryNames = append(imported.CategoryNames, strings.TrimSpace(categoryName))
+	}
+	return planned, imported, nil
+}
+
+func containsId(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+// checkName makes sure that the name of a person or category to be created is valid
+func checkName(resource string, name string) error {
+	length := utf8.RuneCountInString(strings.TrimSpace(name))
+	if length == 0 {
+		return eris.Errorf("the name of a %s is empty", resource)
+	} else if length > maxNameLength {
+		return eris.Errorf("the name of the %s %q is longer than %d characters", resource, strings.TrimSpace(name), maxNameLength)
+	}
+	return nil
+}
+
+// nameIndex keeps values by names which are compared ignoring case and surrounding spaces; the first value added for a name wins
+type nameIndex struct {
+	names  []string
+	values map[string]string
+}
+
+func newNameIndex() *nameIndex {
+	return &nameIndex{values: make(map[string]string)}
+}
+
+func (i *nameIndex) add(name string, value string) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if _, ok := i.values[key]; !ok {
+		i.values[key] = value
+		i.names = append(i.names, name)
+	}
+}
+
+func (i *nameIndex) get(name string) (string, bool) {
+	value, ok := i.values[strings.ToLower(strings.TrimSpace(name))]
+	return value, ok
+}
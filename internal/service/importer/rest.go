@@ -0,0 +1,122 @@
+package importer
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	importersvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/importer/v1"
+	"github.com/rotisserie/eris"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	importGroupProcedure = "/service.importer.v1.ImporterService/ImportGroup"
+	importGroupPattern   = "/v1/groups/{group_id}/import"
+	// fileFormName is the name of the multipart form part carrying the imported file
+	fileFormName = "file"
+	// sourceQueryParameter is the name of the query parameter selecting the app the imported file was exported by
+	sourceQueryParameter = "source"
+	// dryRunQueryParameter is the name of the query parameter telling whether to only preview the import
+	dryRunQueryParameter = "dry_run"
+	// maxFileSize is the maximum size of imported files as accepted by the importer service
+	maxFileSize = 4 * 1024 * 1024
+)
+
+// sources are the apps an imported file may come from by the values of the source query parameter
+var sources = map[string]importersvcv1.Source{
+	"splitwise": importersvcv1.Source_SOURCE_SPLITWISE_CSV,
+	"tricount":  importersvcv1.Source_SOURCE_TRICOUNT_CSV,
+	"archive":   importersvcv1.Source_SOURCE_ARCHIVE,
+}
+
+// RegisterImporterServiceHandler registers the multipart upload of the file imported into a group, which cannot be expressed with HTTP
+// annotations. No REST endpoints are generated for the importer service since a file is best uploaded as multipart form.
+// The upload calls the service through the passed connection so that it passes the same interceptors.
+func RegisterImporterServiceHandler(_ context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	client := importersvcv1.NewImporterServiceClient(conn)
+	if err := mux.HandlePath(http.MethodPost, importGroupPattern, uploadImportHandler(mux, client)); err != nil {
+		return eris.Wrap(err, "failed registering import upload")
+	}
+	return nil
+}
+
+// uploadImportHandler passes the file part of a multipart form to the import of the importer service along with the source and whether
+// it is a dry run as passed as query parameters
+func uploadImportHandler(mux *runtime.ServeMux, client importersvcv1.ImporterServiceClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		_, outboundMarshaler := runtime.MarshalerForRequest(mux, r)
+		ctx, err := runtime.AnnotateContext(ctx, mux, r, importGroupProcedure, runtime.WithHTTPPathPattern(importGroupPattern))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+
+		source, ok := sources[r.URL.Query().Get(sourceQueryParameter)]
+		if !ok {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, status.Error(codes.InvalidArgument, "the source has to be one of splitwise, tricount and archive"))
+			return
+		}
+		dryRun := false
+		if value := r.URL.Query().Get(dryRunQueryParameter); value != "" {
+			if dryRun, err = strconv.ParseBool(value); err != nil {
+				runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, status.Error(codes.InvalidArgument, "dry_run has to be a boolean"))
+				return
+			}
+		}
+		part, err := fileFormPart(r)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+		defer part.Close()
+		// one byte more than allowed is read so that the service rejects files exceeding the maximum size
+		content, err := io.ReadAll(io.LimitReader(part, maxFileSize+1))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, status.Error(codes.InvalidArgument, "failed reading the file"))
+			return
+		}
+
+		var header, trailer metadata.MD
+		resp, err := client.ImportGroup(ctx, &importersvcv1.ImportGroupRequest{
+			GroupId: pathParams["group_id"],
+			Source:  source,
+			Content: content,
+			DryRun:  dryRun,
+		}, grpc.Header(&header), grpc.Trailer(&trailer))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+		ctx = runtime.NewServerMetadataContext(ctx, runtime.ServerMetadata{HeaderMD: header, TrailerMD: trailer})
+		runtime.ForwardResponseMessage(ctx, mux, outboundMarshaler, w, r, resp)
+	}
+}
+
+// fileFormPart returns the part of the multipart form carrying the imported file
+func fileFormPart(r *http.Request) (*multipart.Part, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "the request has to be a multipart form")
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, status.Errorf(codes.InvalidArgument, "the multipart form has no %s part", fileFormName)
+		} else if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "failed reading the multipart form")
+		}
+		if part.FormName() == fileFormName {
+			return part, nil
+		}
+		part.Close()
+	}
+}
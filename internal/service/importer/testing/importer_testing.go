@@ -0,0 +1,42 @@
+package testing
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/importer/v1/importerv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/service/importer"
+	clienttesting "github.com/nico151999/high-availability-expense-splitter/pkg/connect/client/testing"
+	servertesting "github.com/nico151999/high-availability-expense-splitter/pkg/connect/server/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/uptrace/bun"
+)
+
+// SetupImporterTest creates gRPC server and client and returns instances of interfaces allowing to close both the server and the client. The passed context has no effect on the server's lifecycle.
+func SetupImporterTest(t *testing.T, ctx context.Context, db bun.IDB) (importerv1connect.ImporterServiceClient, net.Listener, func() error) {
+	log := logging.FromContext(ctx).Named("setupImporterTest")
+	ctx = logging.IntoContext(ctx, log)
+
+	for k, v := range map[string]string{
+		"GLOBAL_DOMAIN":                    "de.test",
+		"DB_SELECT_ERROR_REASON":           "DB_SELECT_ERROR",
+		"DB_INSERT_ERROR_REASON":           "DB_INSERT_ERROR",
+		"MESSAGE_PUBLICATION_ERROR_REASON": "MESSAGE_PUBLICATION_ERROR",
+	} {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatalf("failed to set env variable %s: %+v", k, err)
+		}
+	}
+
+	ln, shutdownServer := servertesting.StartTestServer(
+		t,
+		ctx,
+		db,
+		importer.NewImporterServerWithDBClient,
+		importer.RegisterImporterServiceHandler,
+		importerv1connect.NewImporterServiceHandler)
+	cl := clienttesting.SetupTestClient(ln, importerv1connect.NewImporterServiceClient)
+	return cl, ln, shutdownServer
+}
@@ -0,0 +1,122 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"connectrpc.com/connect"
+	notificationsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/notification/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *notificationServer) DeleteNotificationPreference(ctx context.Context, req *connect.Request[notificationsvcv1.DeleteNotificationPreferenceRequest]) (*connect.Response[notificationsvcv1.DeleteNotificationPreferenceResponse], error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := deleteNotificationPreference(ctx, s.dbClient, req.Msg.GetEtag()); err != nil {
+		if eris.Is(err, errAnonymousPrincipal) {
+			return nil, connect.NewError(connect.CodeUnauthenticated, eris.New("notification preferences can only be deleted by authenticated users"))
+		} else if eris.Is(err, errSelectNotificationPreference) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errDeleteNotificationPreference) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBDeleteErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoNotificationPreference) {
+			return nil, connect.NewError(connect.CodeNotFound, eris.New("no notification preference has been set"))
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the notification preference was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&notificationsvcv1.DeleteNotificationPreferenceResponse{}), nil
+}
+
+// deleteNotificationPreference deletes the notification preference of the calling user along with the notifications collected for the next digest
+func deleteNotificationPreference(ctx context.Context, dbClient bun.IDB, etag string) error {
+	p, err := callerPrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	log := logging.FromContext(ctx).With(logging.String("principal", p))
+
+	return transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if etag != "" {
+			current, err := model.SelectNotificationPreference(ctx, tx, p)
+			if err != nil {
+				if eris.Is(err, sql.ErrNoRows) {
+					log.Info("notification preference not found", logging.Error(err))
+					return errNoNotificationPreference
+				}
+				log.Error("failed getting notification preference", logging.Error(err))
+				return errSelectNotificationPreference
+			}
+			if err := current.CheckEtag(etag); err != nil {
+				return err
+			}
+		}
+
+		res, err := tx.NewDelete().Model((*model.NotificationPreference)(nil)).Where("principal = ?", p).ApplyQueryBuilder(func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereEtag(q, etag)
+		}).Exec(ctx)
+		if err != nil {
+			log.Error("failed deleting notification preference", logging.Error(err))
+			return errDeleteNotificationPreference
+		}
+		if deleted, err := res.RowsAffected(); err == nil && deleted == 0 {
+			if etag != "" {
+				log.Info("notification preference was modified concurrently")
+				return concurrentEtagMismatch(ctx, tx, p)
+			}
+			log.Info("notification preference not found")
+			return errNoNotificationPreference
+		}
+		for _, m := range []interface{}{
+			(*model.NotificationPreferencePerson)(nil),
+			(*model.PendingNotification)(nil),
+		} {
+			if _, err := tx.NewDelete().Model(m).Where("principal = ?", p).Exec(ctx); err != nil {
+				log.Error("failed deleting data of notification preference", logging.Error(err))
+				return errDeleteNotificationPreference
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,68 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"connectrpc.com/connect"
+	notificationsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/notification/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *notificationServer) GetNotificationPreference(ctx context.Context, req *connect.Request[notificationsvcv1.GetNotificationPreferenceRequest]) (*connect.Response[notificationsvcv1.GetNotificationPreferenceResponse], error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	preference, err := getNotificationPreference(ctx, s.dbReads.For(req.Spec().Procedure))
+	if err != nil {
+		if eris.Is(err, errAnonymousPrincipal) {
+			return nil, connect.NewError(connect.CodeUnauthenticated, eris.New("notification preferences can only be read by authenticated users"))
+		} else if eris.Is(err, errSelectNotificationPreference) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoNotificationPreference) {
+			return nil, connect.NewError(connect.CodeNotFound, eris.New("no notification preference has been set"))
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&notificationsvcv1.GetNotificationPreferenceResponse{
+		NotificationPreference: preference.IntoProtoNotificationPreference(),
+	}), nil
+}
+
+func getNotificationPreference(ctx context.Context, db bun.IDB) (*model.NotificationPreference, error) {
+	p, err := callerPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	log := logging.FromContext(ctx).With(logging.String("principal", p))
+
+	preference, err := model.SelectNotificationPreference(ctx, db, p)
+	if err != nil {
+		if eris.Is(err, sql.ErrNoRows) {
+			log.Info("notification preference not found", logging.Error(err))
+			return nil, errNoNotificationPreference
+		}
+		log.Error("failed getting notification preference", logging.Error(err))
+		return nil, errSelectNotificationPreference
+	}
+	return preference, nil
+}
@@ -0,0 +1,84 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/notification/v1/notificationv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+var _ notificationv1connect.NotificationServiceHandler = (*notificationServer)(nil)
+
+var errAnonymousPrincipal = eris.New("the request does not carry a principal")
+var errNoNotificationPreference = eris.New("there is no notification preference of the principal")
+var errInvalidTimeZone = eris.New("the time zone is unknown")
+var errSelectNotificationPreference = eris.New("failed selecting notification preference")
+var errSetNotificationPreference = eris.New("failed setting notification preference")
+var errDeleteNotificationPreference = eris.New("failed deleting notification preference")
+
+type notificationServer struct {
+	dbClient bun.IDB
+	// dbReads is used by read-only endpoints while writes and reads within transactions always use dbClient
+	dbReads *client.ReadRouter
+}
+
+// NewNotificationServer creates a new instance of notification server. The context has no effect on the server's lifecycle.
+func NewNotificationServer(ctx context.Context, natsServer string, dbConfig client.Config) (*notificationServer, error) {
+	log := logging.FromContext(ctx).Named("NewNotificationServer")
+	ctx = logging.IntoContext(ctx, log)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewNotificationServerWithDBClient(ctx, dbClient, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
+}
+
+// NewNotificationServerWithDBClient creates a new instance of notification server. The context has no effect on the server's lifecycle.
+// Unlike the other servers it does not connect to the NATS server since notification preferences are only read by the notification processor.
+func NewNotificationServerWithDBClient(ctx context.Context, dbClient bun.IDB, _ string) (*notificationServer, error) {
+	return &notificationServer{
+		dbClient: dbClient,
+		dbReads:  client.NewReadRouter(dbClient),
+	}, nil
+}
+
+func (rps *notificationServer) Close() error {
+	return rps.dbReads.Close()
+}
+
+// callerPrincipal returns the principal of the user performing the request since users can only manage their own notification preference
+func callerPrincipal(ctx context.Context) (string, error) {
+	p := principal.FromContext(ctx)
+	if p == principal.Anonymous {
+		return "", errAnonymousPrincipal
+	}
+	return p, nil
+}
+
+// concurrentEtagMismatch returns an EtagMismatchError with the current etag of the notification preference of the principal after an update
+// or delete restricted by the checked etag matched no row
+func concurrentEtagMismatch(ctx context.Context, db bun.IDB, p string) error {
+	current, err := model.SelectNotificationPreference(ctx, db, p)
+	if err != nil {
+		if eris.Is(err, sql.ErrNoRows) {
+			return errNoNotificationPreference
+		}
+		return errSelectNotificationPreference
+	}
+	return model.EtagMismatchError{
+		CurrentEtag: current.Etag(),
+	}
+}
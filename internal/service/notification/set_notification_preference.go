@@ -0,0 +1,181 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	notificationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/notification/v1"
+	notificationsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/notification/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	// time zones are validated against the embedded zoneinfo database so that they are validated the same way the processor resolves them
+	_ "time/tzdata"
+)
+
+// defaultTimeZone is the time zone of users who do not tell theirs
+const defaultTimeZone = "UTC"
+
+func (s *notificationServer) SetNotificationPreference(ctx context.Context, req *connect.Request[notificationsvcv1.SetNotificationPreferenceRequest]) (*connect.Response[notificationsvcv1.SetNotificationPreferenceResponse], error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	preference, err := setNotificationPreference(ctx, s.dbClient, req.Msg)
+	if err != nil {
+		if eris.Is(err, errAnonymousPrincipal) {
+			return nil, connect.NewError(connect.CodeUnauthenticated, eris.New("notification preferences can only be set by authenticated users"))
+		} else if eris.Is(err, errInvalidTimeZone) {
+			return nil, errors.NewFieldViolationError(ctx, "the request contains an invalid time zone", "time_zone", fmt.Sprintf("the time zone %s is unknown", req.Msg.GetTimeZone()))
+		} else if eris.Is(err, errSelectNotificationPreference) || eris.Is(err, util.ErrSelectResource) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errSetNotificationPreference) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBUpdateErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoNotificationPreference) {
+			return nil, connect.NewError(connect.CodeNotFound, eris.New("the notification preference the etag belongs to no longer exists"))
+		} else if refErr := new(util.InvalidReferenceError); eris.As(err, refErr) {
+			return nil, errors.NewFieldViolationError(ctx, "the request references an invalid resource", refErr.Field, refErr.Description())
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the notification preference was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&notificationsvcv1.SetNotificationPreferenceResponse{
+		NotificationPreference: preference.IntoProtoNotificationPreference(),
+	}), nil
+}
+
+// setNotificationPreference creates the notification preference of the calling user or replaces it along with the persons representing the user
+func setNotificationPreference(ctx context.Context, dbClient bun.IDB, req *notificationsvcv1.SetNotificationPreferenceRequest) (*model.NotificationPreference, error) {
+	p, err := callerPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	log := logging.FromContext(ctx).With(logging.String("principal", p))
+
+	timeZone := req.GetTimeZone()
+	if timeZone == "" {
+		timeZone = defaultTimeZone
+	}
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		log.Info("unknown time zone", logging.String("timeZone", timeZone), logging.Error(err))
+		return nil, errInvalidTimeZone
+	}
+
+	var preference *model.NotificationPreference
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		persons := make([]*model.NotificationPreferencePerson, 0, len(req.GetPersonIds()))
+		for i, personId := range req.GetPersonIds() {
+			person, err := util.CheckReference[*model.Person](ctx, tx, fmt.Sprintf("person_ids[%d]", i), personId)
+			if err != nil {
+				return err
+			}
+			persons = append(persons, &model.NotificationPreferencePerson{
+				Principal: p,
+				PersonId:  personId,
+				GroupId:   person.GetGroupId(),
+			})
+		}
+
+		current, err := model.SelectNotificationPreference(ctx, tx, p)
+		if err != nil && !eris.Is(err, sql.ErrNoRows) {
+			log.Error("failed getting notification preference", logging.Error(err))
+			return errSelectNotificationPreference
+		}
+
+		now := time.Now()
+		preference = model.NewNotificationPreference(&notificationv1.NotificationPreference{
+			Principal:  p,
+			Email:      req.GetEmail(),
+			Locale:     req.GetLocale(),
+			PersonIds:  req.GetPersonIds(),
+			EventTypes: req.GetEventTypes(),
+			Delivery:   req.GetDelivery(),
+			DigestHour: req.GetDigestHour(),
+			TimeZone:   timeZone,
+		}, model.NewCreatedMetadata(ctx, now))
+		if current == nil {
+			if req.GetEtag() != "" {
+				log.Info("notification preference the etag belongs to not found")
+				return errNoNotificationPreference
+			}
+			// the first digest is sent at the digest hour following the creation rather than right away
+			preference.LastDigestTime = &now
+			if _, err := tx.NewInsert().Model(preference).Exec(ctx); err != nil {
+				log.Error("failed inserting notification preference", logging.Error(err))
+				return errSetNotificationPreference
+			}
+		} else {
+			if err := current.CheckEtag(req.GetEtag()); err != nil {
+				return err
+			}
+			preference.Metadata = model.NewModifiedMetadata(ctx, now)
+			query := model.IncrementRevision(tx.NewUpdate().Model(preference).
+				Column("email", "locale", "event_types", "delivery", "digest_hour", "time_zone").
+				Column(model.MetadataUpdateColumns...))
+			if err := util.UpdateReturning(ctx, tx, model.MatchEtag(query, req.GetEtag()), util.WherePK, append([]string{"revision"}, model.MetadataReturningColumns...)...); err != nil {
+				if eris.Is(err, sql.ErrNoRows) && req.GetEtag() != "" {
+					log.Info("notification preference was modified concurrently", logging.Error(err))
+					return concurrentEtagMismatch(ctx, tx, p)
+				}
+				log.Error("failed updating notification preference", logging.Error(err))
+				return errSetNotificationPreference
+			}
+		}
+
+		if _, err := tx.NewDelete().Model((*model.NotificationPreferencePerson)(nil)).Where("principal = ?", p).Exec(ctx); err != nil {
+			log.Error("failed deleting persons of notification preference", logging.Error(err))
+			return errSetNotificationPreference
+		}
+		if len(persons) > 0 {
+			if _, err := tx.NewInsert().Model(&persons).Exec(ctx); err != nil {
+				log.Error("failed inserting persons of notification preference", logging.Error(err))
+				return errSetNotificationPreference
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return preference, nil
+}
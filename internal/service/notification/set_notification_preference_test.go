@@ -0,0 +1,153 @@
+package notification_test // the dedicated _test package prevents import cycles with the testing package
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/DATA-DOG/go-sqlmock"
+	notificationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/notification/v1"
+	notificationsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/notification/v1"
+	notificationTesting "github.com/nico151999/high-availability-expense-splitter/internal/service/notification/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestSetNotificationPreference(t *testing.T) {
+	log := logging.GetLogger().Named("testSetNotificationPreference")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	client, _, closeServer := notificationTesting.SetupNotificationTest(t, ctx, bun.NewDB(db, pgdialect.New()))
+	// we want to close the server only which cascadingly closes the client as well
+	defer func() {
+		if err := closeServer(); err != nil {
+			t.Errorf("failed closing notification server: %+v", err)
+		}
+	}()
+
+	principal := "alice@example.com"
+	personId := "person-123456789012345"
+	groupId := "group-123456789012345"
+	newRequest := func(msg *notificationsvcv1.SetNotificationPreferenceRequest) *connect.Request[notificationsvcv1.SetNotificationPreferenceRequest] {
+		req := connect.NewRequest(msg)
+		req.Header().Set(environment.GetPrincipalHeaderKey(), principal)
+		return req
+	}
+	expectCode := func(t *testing.T, err error, code connect.Code) {
+		if connectErr := new(connect.Error); eris.As(err, &connectErr) {
+			if connectErr.Code() != code {
+				t.Fatalf("Expected code: %+v; got: %+v", code, connectErr.Code())
+			}
+		} else {
+			t.Fatalf("Expected connect error, got: %+v", err)
+		}
+	}
+
+	t.Run("Create NotificationPreference successfully", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "people" (.+) WHERE (.+)"id" = '%s'(.+)`, personId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id"}).
+				FromCSVString(fmt.Sprintf("%s,%s", personId, groupId)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "notification_preferences" (.+) WHERE \(principal = '%s'\)(.+)`, principal)).
+			WillReturnRows(sqlmock.NewRows([]string{"principal"}))
+		mock.ExpectQuery(`INSERT INTO "notification_preferences" (.+)`).
+			WillReturnRows(sqlmock.NewRows([]string{"revision"}).
+				FromCSVString("1"))
+		mock.ExpectExec(fmt.Sprintf(`DELETE FROM "notification_preference_people" (.+) WHERE \(principal = '%s'\)`, principal)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(fmt.Sprintf(`INSERT INTO "notification_preference_people" (.+)'%s', '%s', '%s'(.+)`, principal, personId, groupId)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		resp, err := client.SetNotificationPreference(ctx, newRequest(&notificationsvcv1.SetNotificationPreferenceRequest{
+			Email:      "alice@example.com",
+			Locale:     "de",
+			PersonIds:  []string{personId},
+			EventTypes: []notificationv1.NotificationPreference_EventType{notificationv1.NotificationPreference_EVENT_TYPE_EXPENSE_CREATED},
+			Delivery:   notificationv1.NotificationPreference_DELIVERY_DAILY_DIGEST,
+			DigestHour: 18,
+		}))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		preference := resp.Msg.GetNotificationPreference()
+		if preference.GetPrincipal() != principal {
+			t.Errorf("Expected the preference to belong to %s; got: %s", principal, preference.GetPrincipal())
+		}
+		if preference.GetTimeZone() != "UTC" {
+			t.Errorf("Expected the time zone to default to UTC; got: %s", preference.GetTimeZone())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail replacing NotificationPreference due to outdated etag", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "notification_preferences" (.+) WHERE \(principal = '%s'\)(.+)`, principal)).
+			WillReturnRows(sqlmock.NewRows([]string{"principal", "revision"}).
+				FromCSVString(fmt.Sprintf("%s,2", principal)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "notification_preference_people" (.+) WHERE \(principal = '%s'\)(.+)`, principal)).
+			WillReturnRows(sqlmock.NewRows([]string{"person_id"}))
+		mock.ExpectRollback()
+		resp, err := client.SetNotificationPreference(ctx, newRequest(&notificationsvcv1.SetNotificationPreferenceRequest{
+			Email:    "alice@example.com",
+			Locale:   "en",
+			Delivery: notificationv1.NotificationPreference_DELIVERY_IMMEDIATE,
+			Etag:     `"1"`,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectCode(t, err, connect.CodeAborted)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail setting NotificationPreference due to unknown time zone", func(t *testing.T) {
+		resp, err := client.SetNotificationPreference(ctx, newRequest(&notificationsvcv1.SetNotificationPreferenceRequest{
+			Email:    "alice@example.com",
+			Locale:   "nb",
+			Delivery: notificationv1.NotificationPreference_DELIVERY_IMMEDIATE,
+			TimeZone: "Europe/Atlantis",
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectCode(t, err, connect.CodeInvalidArgument)
+	})
+
+	t.Run("Fail setting NotificationPreference due to unsupported locale", func(t *testing.T) {
+		resp, err := client.SetNotificationPreference(ctx, newRequest(&notificationsvcv1.SetNotificationPreferenceRequest{
+			Email:    "alice@example.com",
+			Locale:   "fr",
+			Delivery: notificationv1.NotificationPreference_DELIVERY_IMMEDIATE,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectCode(t, err, connect.CodeInvalidArgument)
+	})
+
+	t.Run("Fail setting NotificationPreference without principal", func(t *testing.T) {
+		resp, err := client.SetNotificationPreference(ctx, connect.NewRequest(&notificationsvcv1.SetNotificationPreferenceRequest{
+			Email:    "alice@example.com",
+			Locale:   "en",
+			Delivery: notificationv1.NotificationPreference_DELIVERY_IMMEDIATE,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectCode(t, err, connect.CodeUnauthenticated)
+	})
+}
@@ -0,0 +1,44 @@
+package testing
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+
+	notificationv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/notification/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/notification/v1/notificationv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/service/notification"
+	clienttesting "github.com/nico151999/high-availability-expense-splitter/pkg/connect/client/testing"
+	servertesting "github.com/nico151999/high-availability-expense-splitter/pkg/connect/server/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/uptrace/bun"
+)
+
+// SetupNotificationTest creates gRPC server and client and returns instances of interfaces allowing to close both the server and the client. The passed context has no effect on the server's lifecycle.
+func SetupNotificationTest(t *testing.T, ctx context.Context, db bun.IDB) (notificationv1connect.NotificationServiceClient, net.Listener, func() error) {
+	log := logging.FromContext(ctx).Named("setupNotificationTest")
+	ctx = logging.IntoContext(ctx, log)
+
+	for k, v := range map[string]string{
+		"GLOBAL_DOMAIN":              "de.test",
+		"DB_SELECT_ERROR_REASON":     "DB_SELECT_ERROR",
+		"DB_DELETE_ERROR_REASON":     "DB_DELETE_ERROR",
+		"DB_UPDATE_ERROR_REASON":     "DB_UPDATE_ERROR",
+		"ETAG_MISMATCH_ERROR_REASON": "ETAG_MISMATCH_ERROR",
+	} {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatalf("failed to set env variable %s: %+v", k, err)
+		}
+	}
+
+	ln, shutdownServer := servertesting.StartTestServer(
+		t,
+		ctx,
+		db,
+		notification.NewNotificationServerWithDBClient,
+		notificationv1.RegisterNotificationServiceHandler,
+		notificationv1connect.NewNotificationServiceHandler)
+	cl := clienttesting.SetupTestClient(ln, notificationv1connect.NewNotificationServiceClient)
+	return cl, ln, shutdownServer
+}
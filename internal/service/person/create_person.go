@@ -2,19 +2,20 @@ package person
 
 import (
 	"context"
-	"database/sql"
 	"time"
 
 	"connectrpc.com/connect"
 	"github.com/nats-io/nats.go"
-	groupv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/group/v1"
 	personv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/person/v1"
 	personprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/person/v1"
 	personsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/person/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -73,32 +74,33 @@ func createPerson(ctx context.Context, nc *nats.EncodedConn, db bun.IDB, req *pe
 	personId := util.GenerateIdWithPrefix("person")
 	requestorEmail := "ab@c.de" // TODO: take user email from context
 
-	if err := db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		if _, err := util.CheckResourceExists[*groupv1.Group](ctx, tx, req.GetGroupId()); err != nil {
+	person := model.NewPerson(&personv1.Person{
+		Id:      personId,
+		GroupId: req.GetGroupId(),
+		Name:    req.GetName(),
+	}, model.NewCreatedMetadata(ctx, time.Now()))
+	if err := transaction.RunInTx(ctx, db, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := util.CheckResourceExists[*model.Group](ctx, tx, req.GetGroupId()); err != nil {
 			return err
 		}
 
-		if _, err := tx.NewInsert().Model(&personv1.Person{
-			Id:      personId,
-			GroupId: req.GetGroupId(),
-			Name:    req.GetName(),
-		}).Exec(ctx); err != nil {
+		if _, err := tx.NewInsert().Model(person).Exec(ctx); err != nil {
 			log.Error("failed inserting person", logging.Error(err))
 			return errInsertPerson
 		}
-
-		if err := nc.Publish(environment.GetPersonCreatedSubject(req.GetGroupId(), personId), &personprocv1.PersonCreated{
-			Id:             personId,
-			GroupId:        req.GetGroupId(),
-			Name:           req.GetName(),
-			RequestorEmail: requestorEmail,
-		}); err != nil {
-			log.Error("failed publishing person created event", logging.Error(err))
-			return errPublishPersonCreated
-		}
 		return nil
 	}); err != nil {
 		return "", err
 	}
+
+	if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetPersonCreatedSubject(req.GetGroupId(), personId), &personprocv1.PersonCreated{
+		Id:             personId,
+		GroupId:        req.GetGroupId(),
+		Name:           req.GetName(),
+		RequestorEmail: requestorEmail,
+	}, person.IntoProtoPerson()); err != nil {
+		log.Error("failed publishing person created event", logging.Error(err))
+		return "", errPublishPersonCreated
+	}
 	return personId, nil
 }
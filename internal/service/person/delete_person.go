@@ -10,9 +10,13 @@ import (
 	personv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/person/v1"
 	personprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/person/v1"
 	personsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/person/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -29,7 +33,7 @@ func (s *personServer) DeletePerson(ctx context.Context, req *connect.Request[pe
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	if err := deletePerson(ctx, s.natsClient, s.dbClient, req.Msg.GetId()); err != nil {
+	if err := deletePerson(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetEtag()); err != nil {
 		if eris.Is(err, errDeletePerson) {
 			return nil, errors.NewErrorWithDetails(
 				ctx,
@@ -45,6 +49,18 @@ func (s *personServer) DeletePerson(ctx context.Context, req *connect.Request[pe
 			return nil, connect.NewError(
 				connect.CodeNotFound,
 				eris.New("the person ID does not exist"))
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the person was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
 		} else {
 			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
 		}
@@ -53,30 +69,49 @@ func (s *personServer) DeletePerson(ctx context.Context, req *connect.Request[pe
 	return connect.NewResponse(&personsvcv1.DeletePersonResponse{}), nil
 }
 
-func deletePerson(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, personId string) error {
+func deletePerson(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, personId string, etag string) error {
 	log := logging.FromContext(ctx)
 
-	return dbClient.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		person := personv1.Person{
+	person := model.Person{
+		Person: personv1.Person{
 			Id: personId,
+		},
+	}
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := model.CheckCurrentEtag[*model.Person](ctx, tx, personId, etag); err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("person not found", logging.Error(err))
+				return errNoPersonWithId
+			}
+			return err
 		}
-		if err := tx.NewDelete().Model(&person).WherePK().Returning("group_id").Scan(ctx); err != nil {
+		if err := util.UpdateMatchedReturning(ctx, tx, model.SoftDelete(tx.NewUpdate().Model(&person), personId, time.Now()), func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereEtag(q.WherePK(), etag)
+		}, "group_id"); err != nil {
 			if eris.Is(err, sql.ErrNoRows) {
+				if etag != "" {
+					if err := model.ConcurrentEtagMismatch[*model.Person](ctx, tx, personId); !eris.As(err, &util.ResourceNotFoundError{}) {
+						return err
+					}
+				}
 				log.Info("person not found", logging.Error(err))
 				return errNoPersonWithId
 			}
 			log.Error("failed deleting person", logging.Error(err))
 			return errDeletePerson
 		}
-
-		if err := nc.Publish(environment.GetPersonDeletedSubject(person.GroupId, personId), &personprocv1.PersonDeleted{
-			Id:      personId,
-			GroupId: person.GroupId,
-		}); err != nil {
-			log.Error("failed publishing person deleted event", logging.Error(err))
-			return errPublishPersonDeleted
-		}
-
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	if err := mqClient.PublishEvent(ctx, nc, environment.GetPersonDeletedSubject(person.GroupId, personId), &personprocv1.PersonDeleted{
+		Id:          personId,
+		GroupId:     person.GroupId,
+		DeleteCause: personId,
+	}); err != nil {
+		log.Error("failed publishing person deleted event", logging.Error(err))
+		return errPublishPersonDeleted
+	}
+	return nil
 }
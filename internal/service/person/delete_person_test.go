@@ -38,7 +38,7 @@ func TestDeletePerson(t *testing.T) {
 		mock.ExpectBegin()
 		groupId := "group-543210987654321"
 		personId := "person-123456789012345"
-		mock.ExpectQuery(fmt.Sprintf(`DELETE FROM "people" (.+) WHERE (.+)"id" = '%s'(.+)`, personId)).
+		mock.ExpectQuery(fmt.Sprintf(`UPDATE "people" (.+) WHERE (.+)"id" = '%s'(.+)`, personId)).
 			WillReturnRows(sqlmock.NewRows([]string{"group_id"}).
 				FromCSVString(groupId))
 		mock.ExpectCommit()
@@ -66,7 +66,7 @@ func TestDeletePerson(t *testing.T) {
 	t.Run("Fail deleting Person due to non existence", func(t *testing.T) {
 		mock.ExpectBegin()
 		personId := "person-543210987654321"
-		mock.ExpectQuery(fmt.Sprintf(`DELETE FROM "people" (.+) WHERE (.+)"id" = '%s'(.+)`, personId)).WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(fmt.Sprintf(`UPDATE "people" (.+) WHERE (.+)"id" = '%s'(.+)`, personId)).WillReturnError(sql.ErrNoRows)
 		mock.ExpectRollback()
 		resp, err := client.DeletePerson(ctx, connect.NewRequest(&personsvcv1.DeletePersonRequest{
 			Id: personId,
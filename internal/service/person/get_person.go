@@ -5,8 +5,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	personv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/person/v1"
 	personsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/person/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
@@ -26,7 +26,7 @@ func (s *personServer) GetPerson(ctx context.Context, req *connect.Request[perso
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	person, err := util.CheckResourceExists[*personv1.Person](ctx, s.dbClient, req.Msg.GetId())
+	person, err := util.CheckResourceExistsWithDeleted[*model.Person](ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetId())
 	if err != nil {
 		if eris.Is(err, util.ErrSelectResource) {
 			return nil, errors.NewErrorWithDetails(
@@ -47,6 +47,6 @@ func (s *personServer) GetPerson(ctx context.Context, req *connect.Request[perso
 	}
 
 	return connect.NewResponse(&personsvcv1.GetPersonResponse{
-		Person: person,
+		Person: person.IntoProtoPerson(),
 	}), nil
 }
@@ -5,8 +5,9 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	personv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/person/v1"
+	metadatav1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/metadata/v1"
 	personsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/person/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
@@ -20,7 +21,7 @@ func (s *personServer) ListPersonIdsInGroup(ctx context.Context, req *connect.Re
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	personIds, err := listPersonIds(ctx, s.dbClient, req.Msg.GetGroupId())
+	personIds, err := listPersonIds(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetGroupId(), req.Msg.GetOrderBy(), req.Msg.GetFilter(), req.Msg.GetDeleted())
 	if err != nil {
 		if eris.Is(err, errSelectPersonIds) {
 			return nil, errors.NewErrorWithDetails(
@@ -43,10 +44,14 @@ func (s *personServer) ListPersonIdsInGroup(ctx context.Context, req *connect.Re
 	}), nil
 }
 
-func listPersonIds(ctx context.Context, dbClient bun.IDB, groupId string) ([]string, error) {
+func listPersonIds(ctx context.Context, dbClient bun.IDB, groupId string, order *metadatav1.MetadataOrder, filter *metadatav1.MetadataFilter, deleted bool) ([]string, error) {
 	log := logging.FromContext(ctx)
 	var personIds []string
-	if err := dbClient.NewSelect().Model((*personv1.Person)(nil)).Where("group_id = ?", groupId).Column("id").Order("name ASC").Scan(ctx, &personIds); err != nil {
+	query := dbClient.NewSelect().Model((*model.Person)(nil)).Where("group_id = ?", groupId).Column("id")
+	if deleted {
+		query = query.WhereDeleted()
+	}
+	if err := model.ApplyMetadataListOptions(query, order, filter).Order("name ASC").Scan(ctx, &personIds); err != nil {
 		log.Error("failed getting person IDs", logging.Error(err))
 		// TODO: determine reason why person ID couldn't be fetched and return error-specific ErrVariable; e.g. use unit testing with dummy return values to determine potential return values unless there is something in the bun documentation
 		return nil, errSelectPersonIds
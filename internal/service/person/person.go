@@ -18,25 +18,37 @@ var errNoPersonWithId = eris.New("there is no person with that ID")
 var errInsertPerson = eris.New("failed inserting person")
 var errPublishPersonCreated = eris.New("failed publishing person created event")
 var errPublishPersonDeleted = eris.New("failed publishing person deleted event")
+var errPublishPersonUndeleted = eris.New("failed publishing person undeleted event")
 var errPublishPersonUpdated = eris.New("failed publishing person updated event")
 var errSelectPersonIds = eris.New("failed selecting person IDs")
 var errDeletePerson = eris.New("failed deleting person")
+var errUndeletePerson = eris.New("failed restoring person")
 var errUpdatePerson = eris.New("failed updating person")
 
 type personServer struct {
-	dbClient   bun.IDB
+	dbClient bun.IDB
+	// dbReads is used by read-only endpoints while writes and reads within transactions always use dbClient
+	dbReads    *client.ReadRouter
 	natsClient *nats.EncodedConn
 	// TODO: add clients to servers this server will communicate with
 }
 
 // NewPersonServer creates a new instance of person server. The context has no effect on the server's lifecycle.
-func NewPersonServer(ctx context.Context, natsServer, dbUser, dbPass, dbAddr, db string) (*personServer, error) {
+func NewPersonServer(ctx context.Context, natsServer string, dbConfig client.Config) (*personServer, error) {
 	log := logging.FromContext(ctx).Named("NewPersonServer")
 	ctx = logging.IntoContext(ctx, log)
-	return NewPersonServerWithDBClient(
-		ctx,
-		client.NewPostgresDBClient(dbUser, dbPass, dbAddr, db),
-		natsServer)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewPersonServerWithDBClient(ctx, dbClient, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
 }
 
 // NewPersonServerWithDBClient creates a new instance of person server. The context has no effect on the server's lifecycle.
@@ -50,11 +62,12 @@ func NewPersonServerWithDBClient(ctx context.Context, dbClient bun.IDB, natsServ
 	}
 	return &personServer{
 		dbClient:   dbClient,
+		dbReads:    client.NewReadRouter(dbClient),
 		natsClient: nc,
 	}, nil
 }
 
 func (rps *personServer) Close() error {
 	rps.natsClient.Close()
-	return nil
+	return rps.dbReads.Close()
 }
@@ -6,8 +6,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	personv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/person/v1"
 	personsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/person/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
@@ -36,7 +36,7 @@ func (s *personServer) StreamPerson(ctx context.Context, req *connect.Request[pe
 
 	streamSubject := fmt.Sprintf("%s.*", environment.GetPersonSubject("*", req.Msg.GetId()))
 	if err := service.StreamResource(ctx, s.natsClient.Conn, streamSubject, func(ctx context.Context) (*personsvcv1.StreamPersonResponse, error) {
-		return sendCurrentPerson(ctx, s.dbClient, req.Msg.GetId())
+		return sendCurrentPerson(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetId())
 	}, srv, &streamPersonAlive); err != nil {
 		if eris.Is(err, service.ErrResourceNoLongerFound) {
 			return connect.NewError(
@@ -88,13 +88,13 @@ func (s *personServer) StreamPerson(ctx context.Context, req *connect.Request[pe
 }
 
 func sendCurrentPerson(ctx context.Context, dbClient bun.IDB, personId string) (*personsvcv1.StreamPersonResponse, error) {
-	person, err := util.CheckResourceExists[*personv1.Person](ctx, dbClient, personId)
+	person, err := util.CheckResourceExists[*model.Person](ctx, dbClient, personId)
 	if err != nil {
 		return nil, err
 	}
 	return &personsvcv1.StreamPersonResponse{
 		Update: &personsvcv1.StreamPersonResponse_Person{
-			Person: person,
+			Person: person.IntoProtoPerson(),
 		},
 	}, nil
 }
@@ -6,8 +6,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
-	personv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/person/v1"
 	personsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/person/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
@@ -27,7 +27,7 @@ func (s *personServer) StreamPersonIdsInGroup(ctx context.Context, req *connect.
 	defer cancel()
 
 	if err := service.StreamResource(ctx, s.natsClient.Conn, fmt.Sprintf("%s.*", environment.GetPersonSubject(req.Msg.GetGroupId(), "*")), func(ctx context.Context) (*personsvcv1.StreamPersonIdsInGroupResponse, error) {
-		return sendCurrentPersonIds(ctx, s.dbClient, req.Msg.GetGroupId())
+		return sendCurrentPersonIds(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetGroupId())
 	}, srv, &streamPersonIdsAlive); err != nil {
 		if eris.Is(err, errSelectPersonIds) {
 			return errors.NewErrorWithDetails(
@@ -85,7 +85,7 @@ func sendCurrentPersonIds(ctx context.Context, dbClient bun.IDB, groupId string)
 	log := logging.FromContext(ctx)
 
 	var personIds []string
-	if err := dbClient.NewSelect().Model((*personv1.Person)(nil)).Where("group_id = ?", groupId).Column("id").Order("name ASC").Scan(ctx, &personIds); err != nil {
+	if err := dbClient.NewSelect().Model((*model.Person)(nil)).Where("group_id = ?", groupId).Column("id").Order("name ASC").Scan(ctx, &personIds); err != nil {
 		log.Error("failed getting person IDs", logging.Error(err))
 		// TODO: determine reason why person IDs couldn't be fetched and return error-specific ErrVariable; e.g. use unit testing with dummy return values to determine potential return values unless there is something in the bun documentation
 		return nil, errSelectPersonIds
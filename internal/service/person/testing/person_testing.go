@@ -26,6 +26,7 @@ func SetupPersonTest(t *testing.T, ctx context.Context, db bun.IDB) (personv1con
 		"DB_SELECT_ERROR_REASON":       "DB_SELECT_ERROR",
 		"DB_DELETE_ERROR_REASON":       "DB_DELETE_ERROR",
 		"DB_UPDATE_ERROR_REASON":       "DB_UPDATE_ERROR",
+		"ETAG_MISMATCH_ERROR_REASON":   "ETAG_MISMATCH_ERROR",
 		"DB_INSERT_ERROR_REASON":       "DB_INSERT_ERROR",
 	} {
 		if err := os.Setenv(k, v); err != nil {
@@ -0,0 +1,114 @@
+package person
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/nats-io/nats.go"
+	personv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/person/v1"
+	personprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/person/v1"
+	personsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/person/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *personServer) UndeletePerson(ctx context.Context, req *connect.Request[personsvcv1.UndeletePersonRequest]) (*connect.Response[personsvcv1.UndeletePersonResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"personId",
+				req.Msg.GetId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	person, err := undeletePerson(ctx, s.natsClient, s.dbClient, req.Msg.GetId())
+	if err != nil {
+		if eris.Is(err, errUndeletePerson) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBUpdateErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoPersonWithId) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the person ID does not exist"))
+		} else if restoreErr := new(model.RestoreError); eris.As(err, restoreErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeFailedPrecondition,
+				"the person cannot be restored",
+				[]protoreflect.ProtoMessage{
+					&errdetails.PreconditionFailure{
+						Violations: []*errdetails.PreconditionFailure_Violation{
+							{
+								Type:        restoreErr.Type,
+								Subject:     restoreErr.Subject,
+								Description: restoreErr.Description,
+							},
+						},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&personsvcv1.UndeletePersonResponse{
+		Person: person,
+	}), nil
+}
+
+func undeletePerson(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, personId string) (*personv1.Person, error) {
+	log := logging.FromContext(ctx)
+
+	var person *model.Person
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		person, err = model.CheckRestorable[*model.Person](ctx, tx, personId)
+		if err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("person not found", logging.Error(err))
+				return errNoPersonWithId
+			}
+			return err
+		}
+		if err := model.CheckParentNotDeleted[*model.Group](ctx, tx, person.GetGroupId()); err != nil {
+			return err
+		}
+		if err := model.Restore(ctx, tx, person); err != nil {
+			log.Error("failed restoring person", logging.Error(err))
+			return errUndeletePerson
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := mqClient.PublishEvent(ctx, nc, environment.GetPersonUndeletedSubject(person.GetGroupId(), personId), &personprocv1.PersonUndeleted{
+		Id:          personId,
+		GroupId:     person.GetGroupId(),
+		DeleteCause: personId,
+	}); err != nil {
+		log.Error("failed publishing person undeleted event", logging.Error(err))
+		return nil, errPublishPersonUndeleted
+	}
+
+	return person.IntoProtoPerson(), nil
+}
@@ -10,10 +10,13 @@ import (
 	personv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/person/v1"
 	personprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/person/v1"
 	personsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/person/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
 	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -30,7 +33,7 @@ func (s *personServer) UpdatePerson(ctx context.Context, req *connect.Request[pe
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	person, err := updatePerson(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetUpdateFields())
+	person, err := updatePerson(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetUpdateFields(), req.Msg.GetEtag())
 	if err != nil {
 		if eris.Is(err, errUpdatePerson) {
 			return nil, errors.NewErrorWithDetails(
@@ -49,6 +52,18 @@ func (s *personServer) UpdatePerson(ctx context.Context, req *connect.Request[pe
 				eris.New("the person ID does not exist"))
 		} else if resErr := new(util.ResourceNotFoundError); eris.As(err, resErr) {
 			return nil, connect.NewError(connect.CodeNotFound, eris.Errorf("the %s with ID %s does not exist", resErr.ResourceName, resErr.ResourceId))
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the person was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
 		} else {
 			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
 		}
@@ -59,14 +74,21 @@ func (s *personServer) UpdatePerson(ctx context.Context, req *connect.Request[pe
 	}), nil
 }
 
-func updatePerson(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, personId string, params []*personsvcv1.UpdatePersonRequest_UpdateField) (*personv1.Person, error) {
+func updatePerson(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, personId string, params []*personsvcv1.UpdatePersonRequest_UpdateField, etag string) (*personv1.Person, error) {
 	log := logging.FromContext(ctx)
-	person := personv1.Person{
+	person := model.NewPerson(&personv1.Person{
 		Id: personId,
-	}
+	}, model.NewModifiedMetadata(ctx, time.Now()))
 
-	if err := dbClient.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		query := tx.NewUpdate()
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := model.CheckCurrentEtag[*model.Person](ctx, tx, personId, etag); err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("person not found", logging.Error(err))
+				return errNoPersonWithId
+			}
+			return err
+		}
+		query := model.IncrementRevision(tx.NewUpdate().Column(model.MetadataUpdateColumns...))
 		for _, param := range params {
 			switch param.GetUpdateOption().(type) {
 			case *personsvcv1.UpdatePersonRequest_UpdateField_Name:
@@ -74,26 +96,38 @@ func updatePerson(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, p
 				query.Column("name")
 			}
 		}
-		if err := query.Model(&person).WherePK().Returning("group_id").Scan(ctx); err != nil {
+		if err := util.UpdateReturning(ctx, tx, model.MatchEtag(query.Model(person), etag), util.WherePK, append([]string{"group_id", "revision"}, model.MetadataReturningColumns...)...); err != nil {
 			if eris.Is(err, sql.ErrNoRows) {
+				if etag != "" {
+					if err := model.ConcurrentEtagMismatch[*model.Person](ctx, tx, personId); !eris.As(err, &util.ResourceNotFoundError{}) {
+						return err
+					}
+				}
 				log.Info("person not found", logging.Error(err))
 				return errNoPersonWithId
 			}
 			log.Error("failed updating person", logging.Error(err))
 			return errUpdatePerson
 		}
-
-		if err := nc.Publish(environment.GetPersonUpdatedSubject(person.GroupId, personId), &personprocv1.PersonUpdated{
-			Id:      personId,
-			GroupId: person.GroupId,
-		}); err != nil {
-			log.Error("failed publishing person updated event", logging.Error(err))
-			return errPublishPersonUpdated
+		// only the updated columns are returned but the event carries the whole person
+		updated, err := util.CheckResourceExists[*model.Person](ctx, tx, personId)
+		if err != nil {
+			log.Error("failed getting updated person", logging.Error(err))
+			return errUpdatePerson
 		}
+		person = updated
 		return nil
 	}); err != nil {
 		return nil, err
 	}
 
-	return &person, nil
+	if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetPersonUpdatedSubject(person.GroupId, personId), &personprocv1.PersonUpdated{
+		Id:      personId,
+		GroupId: person.GroupId,
+	}, person.IntoProtoPerson()); err != nil {
+		log.Error("failed publishing person updated event", logging.Error(err))
+		return nil, errPublishPersonUpdated
+	}
+
+	return person.IntoProtoPerson(), nil
 }
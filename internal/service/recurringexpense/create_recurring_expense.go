@@ -0,0 +1,133 @@
+package recurringexpense
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/nats-io/nats.go"
+	currencyv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/currency/v1"
+	recurringexpensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/recurringexpense/v1"
+	recurringexpenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/recurringexpense/v1"
+	recurringexpensesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/recurringexpense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *recurringExpenseServer) CreateRecurringExpense(ctx context.Context, req *connect.Request[recurringexpensesvcv1.CreateRecurringExpenseRequest]) (*connect.Response[recurringexpensesvcv1.CreateRecurringExpenseResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"recurringExpenseName",
+				req.Msg.GetName())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	recurringExpenseId, err := createRecurringExpense(ctx, s.natsClient, s.dbClient, req.Msg)
+	if err != nil {
+		if eris.Is(err, errPublishRecurringExpenseCreated) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed finalizing recurring expense creation",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetMessagePublicationErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errInsertRecurringExpense) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBInsertErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if refErr := new(util.InvalidReferenceError); eris.As(err, refErr) {
+			return nil, errors.NewFieldViolationError(ctx, "the request references an invalid resource", refErr.Field, refErr.Description())
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&recurringexpensesvcv1.CreateRecurringExpenseResponse{
+		Id: recurringExpenseId,
+	}), nil
+}
+
+func createRecurringExpense(ctx context.Context, nc *nats.EncodedConn, db bun.IDB, req *recurringexpensesvcv1.CreateRecurringExpenseRequest) (string, error) {
+	log := logging.FromContext(ctx)
+
+	recurringExpenseId := util.GenerateIdWithPrefix("recurringexpense")
+
+	var name *string
+	if req != nil {
+		name = req.Name
+	}
+	var recurringExpense *recurringexpensev1.RecurringExpense
+	if err := transaction.RunInTx(ctx, db, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := util.CheckReference[*model.Group](ctx, tx, "group_id", req.GetGroupId()); err != nil {
+			return err
+		}
+		if _, err := util.CheckGroupScopedReference[*model.Person](ctx, tx, "by_id", req.GetById(), req.GetGroupId()); err != nil {
+			return err
+		}
+		if _, err := util.CheckNonDeprecatedReference[*currencyv1.Currency](ctx, tx, "currency_id", req.GetCurrencyId()); err != nil {
+			return err
+		}
+		for i, stake := range req.GetStakes() {
+			if _, err := util.CheckGroupScopedReference[*model.Person](ctx, tx, fmt.Sprintf("stakes[%d].for_id", i), stake.GetForId(), req.GetGroupId()); err != nil {
+				return err
+			}
+		}
+
+		recurringExpenseModel, err := model.NewRecurringExpense(&recurringexpensev1.RecurringExpense{
+			Id:         recurringExpenseId,
+			GroupId:    req.GetGroupId(),
+			Name:       name,
+			ById:       req.GetById(),
+			CurrencyId: req.GetCurrencyId(),
+			Stakes:     req.GetStakes(),
+			Schedule:   req.GetSchedule(),
+		}, model.NewCreatedMetadata(ctx, time.Now()), time.Now())
+		if err != nil {
+			log.Error("failed creating recurring expense", logging.Error(err))
+			return errInsertRecurringExpense
+		}
+		if _, err := tx.NewInsert().Model(recurringExpenseModel).Exec(ctx); err != nil {
+			log.Error("failed inserting recurring expense", logging.Error(err))
+			return errInsertRecurringExpense
+		}
+		if recurringExpense, err = recurringExpenseModel.IntoProtoRecurringExpense(); err != nil {
+			log.Error("failed converting recurring expense", logging.Error(err))
+			return errConvertRecurringExpense
+		}
+		return nil
+	}); err != nil {
+		return "", err
+	}
+
+	if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetRecurringExpenseCreatedSubject(req.GetGroupId(), recurringExpenseId), &recurringexpenseprocv1.RecurringExpenseCreated{
+		Id:      recurringExpenseId,
+		GroupId: req.GetGroupId(),
+	}, recurringExpense); err != nil {
+		log.Error("failed publishing recurring expense created event", logging.Error(err))
+		return "", errPublishRecurringExpenseCreated
+	}
+	return recurringExpenseId, nil
+}
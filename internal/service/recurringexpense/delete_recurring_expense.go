@@ -0,0 +1,129 @@
+package recurringexpense
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/nats-io/nats.go"
+	recurringexpensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/recurringexpense/v1"
+	recurringexpenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/recurringexpense/v1"
+	recurringexpensesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/recurringexpense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *recurringExpenseServer) DeleteRecurringExpense(ctx context.Context, req *connect.Request[recurringexpensesvcv1.DeleteRecurringExpenseRequest]) (*connect.Response[recurringexpensesvcv1.DeleteRecurringExpenseResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"recurringExpenseId",
+				req.Msg.GetId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := deleteRecurringExpense(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetEtag()); err != nil {
+		if eris.Is(err, errDeleteRecurringExpense) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBDeleteErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errPublishRecurringExpenseDeleted) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed finalizing recurring expense deletion",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetMessagePublicationErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoRecurringExpenseWithId) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the recurring expense ID does not exist"))
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the recurring expense was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&recurringexpensesvcv1.DeleteRecurringExpenseResponse{}), nil
+}
+
+// deleteRecurringExpense permanently deletes a recurring expense. The expenses created from it are kept
+// and the recurring expense processor cleans up the occurrences it recorded once their events are published.
+func deleteRecurringExpense(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, recurringExpenseId string, etag string) error {
+	log := logging.FromContext(ctx)
+
+	recurringExpense := model.RecurringExpense{
+		RecurringExpense: recurringexpensev1.RecurringExpense{
+			Id: recurringExpenseId,
+		},
+	}
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := model.CheckCurrentEtag[*model.RecurringExpense](ctx, tx, recurringExpenseId, etag); err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("recurring expense not found", logging.Error(err))
+				return errNoRecurringExpenseWithId
+			}
+			return err
+		}
+		if err := util.DeleteReturning(ctx, tx, &recurringExpense, func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereEtag(q.WherePK(), etag)
+		}, "group_id"); err != nil {
+			if eris.Is(err, sql.ErrNoRows) {
+				if etag != "" {
+					if err := model.ConcurrentEtagMismatch[*model.RecurringExpense](ctx, tx, recurringExpenseId); !eris.As(err, &util.ResourceNotFoundError{}) {
+						return err
+					}
+				}
+				log.Info("recurring expense not found", logging.Error(err))
+				return errNoRecurringExpenseWithId
+			}
+			log.Error("failed deleting recurring expense", logging.Error(err))
+			return errDeleteRecurringExpense
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := mqClient.PublishEvent(ctx, nc, environment.GetRecurringExpenseDeletedSubject(recurringExpense.GroupId, recurringExpenseId), &recurringexpenseprocv1.RecurringExpenseDeleted{
+		Id:      recurringExpenseId,
+		GroupId: recurringExpense.GroupId,
+	}); err != nil {
+		log.Error("failed publishing recurring expense deleted event", logging.Error(err))
+		return errPublishRecurringExpenseDeleted
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package recurringexpense
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	metadatav1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/metadata/v1"
+	recurringexpensesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/recurringexpense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *recurringExpenseServer) ListRecurringExpenseIdsInGroup(ctx context.Context, req *connect.Request[recurringexpensesvcv1.ListRecurringExpenseIdsInGroupRequest]) (*connect.Response[recurringexpensesvcv1.ListRecurringExpenseIdsInGroupResponse], error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	recurringExpenseIds, err := listRecurringExpenseIds(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetGroupId(), req.Msg.GetOrderBy(), req.Msg.GetFilter())
+	if err != nil {
+		if eris.Is(err, errSelectRecurringExpenseIds) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&recurringexpensesvcv1.ListRecurringExpenseIdsInGroupResponse{
+		Ids: recurringExpenseIds,
+	}), nil
+}
+
+func listRecurringExpenseIds(ctx context.Context, dbClient bun.IDB, groupId string, order *metadatav1.MetadataOrder, filter *metadatav1.MetadataFilter) ([]string, error) {
+	log := logging.FromContext(ctx)
+	var recurringExpenseIds []string
+	query := dbClient.NewSelect().Model((*model.RecurringExpense)(nil)).Where("group_id = ?", groupId).Column("id")
+	if err := model.ApplyMetadataListOptions(query, order, filter).Order("start_time ASC", "id ASC").Scan(ctx, &recurringExpenseIds); err != nil {
+		log.Error("failed getting recurring expense IDs", logging.Error(err))
+		return nil, errSelectRecurringExpenseIds
+	}
+
+	return recurringExpenseIds, nil
+}
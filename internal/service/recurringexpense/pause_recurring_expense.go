@@ -0,0 +1,79 @@
+package recurringexpense
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	recurringexpensesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/recurringexpense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *recurringExpenseServer) PauseRecurringExpense(ctx context.Context, req *connect.Request[recurringexpensesvcv1.PauseRecurringExpenseRequest]) (*connect.Response[recurringexpensesvcv1.PauseRecurringExpenseResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"recurringExpenseId",
+				req.Msg.GetId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	recurringExpense, err := updateRecurringExpense(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetEtag(), func(r *model.RecurringExpense) {
+		r.Paused = true
+		r.NextOccurrenceTime = nil
+	})
+	if err != nil {
+		if eris.Is(err, errUpdateRecurringExpense) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBUpdateErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errPublishRecurringExpenseUpdated) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed finalizing recurring expense update",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetMessagePublicationErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoRecurringExpenseWithId) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the recurring expense ID does not exist"))
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the recurring expense was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&recurringexpensesvcv1.PauseRecurringExpenseResponse{
+		RecurringExpense: recurringExpense,
+	}), nil
+}
@@ -0,0 +1,71 @@
+package recurringexpense
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/recurringexpense/v1/recurringexpensev1connect"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+var _ recurringexpensev1connect.RecurringExpenseServiceHandler = (*recurringExpenseServer)(nil)
+
+var errNoRecurringExpenseWithId = eris.New("there is no recurring expense with that ID")
+var errInsertRecurringExpense = eris.New("failed inserting recurring expense")
+var errPublishRecurringExpenseCreated = eris.New("failed publishing recurring expense created event")
+var errPublishRecurringExpenseDeleted = eris.New("failed publishing recurring expense deleted event")
+var errPublishRecurringExpenseUpdated = eris.New("failed publishing recurring expense updated event")
+var errSelectRecurringExpenseIds = eris.New("failed selecting recurring expense IDs")
+var errDeleteRecurringExpense = eris.New("failed deleting recurring expense")
+var errUpdateRecurringExpense = eris.New("failed updating recurring expense")
+var errConvertRecurringExpense = eris.New("failed converting recurring expense")
+
+type recurringExpenseServer struct {
+	dbClient bun.IDB
+	// dbReads is used by read-only endpoints while writes and reads within transactions always use dbClient
+	dbReads    *client.ReadRouter
+	natsClient *nats.EncodedConn
+}
+
+// NewRecurringExpenseServer creates a new instance of recurring expense server. The context has no effect on the server's lifecycle.
+func NewRecurringExpenseServer(ctx context.Context, natsServer string, dbConfig client.Config) (*recurringExpenseServer, error) {
+	log := logging.FromContext(ctx).Named("NewRecurringExpenseServer")
+	ctx = logging.IntoContext(ctx, log)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewRecurringExpenseServerWithDBClient(ctx, dbClient, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
+}
+
+// NewRecurringExpenseServerWithDBClient creates a new instance of recurring expense server. The context has no effect on the server's lifecycle.
+func NewRecurringExpenseServerWithDBClient(ctx context.Context, dbClient bun.IDB, natsServer string) (*recurringExpenseServer, error) {
+	log := logging.FromContext(ctx).Named("NewRecurringExpenseServerWithDBClient")
+	nc, err := mqClient.NewProtoMQClient(natsServer)
+	if err != nil {
+		msg := "failed connecting to NATS server"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	return &recurringExpenseServer{
+		dbClient:   dbClient,
+		dbReads:    client.NewReadRouter(dbClient),
+		natsClient: nc,
+	}, nil
+}
+
+func (rps *recurringExpenseServer) Close() error {
+	rps.natsClient.Close()
+	return rps.dbReads.Close()
+}
@@ -0,0 +1,88 @@
+package recurringexpense
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	recurringexpensesvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/recurringexpense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *recurringExpenseServer) SetRecurringExpenseEndTime(ctx context.Context, req *connect.Request[recurringexpensesvcv1.SetRecurringExpenseEndTimeRequest]) (*connect.Response[recurringexpensesvcv1.SetRecurringExpenseEndTimeResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"recurringExpenseId",
+				req.Msg.GetId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	recurringExpense, err := updateRecurringExpense(ctx, s.natsClient, s.dbClient, req.Msg.GetId(), req.Msg.GetEtag(), func(r *model.RecurringExpense) {
+		r.EndTime = nil
+		if endTime := req.Msg.GetEndTime(); endTime != nil {
+			t := endTime.AsTime()
+			r.EndTime = &t
+		}
+		if r.NextOccurrenceTime != nil {
+			// the pending occurrence is kept unless it is after the new end time
+			r.ScheduleFrom(*r.NextOccurrenceTime)
+		} else {
+			r.ScheduleFrom(time.Now())
+		}
+	})
+	if err != nil {
+		if eris.Is(err, errUpdateRecurringExpense) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBUpdateErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errPublishRecurringExpenseUpdated) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed finalizing recurring expense update",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetMessagePublicationErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoRecurringExpenseWithId) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the recurring expense ID does not exist"))
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the recurring expense was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&recurringexpensesvcv1.SetRecurringExpenseEndTimeResponse{
+		RecurringExpense: recurringExpense,
+	}), nil
+}
@@ -0,0 +1,77 @@
+package recurringexpense
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	recurringexpensev1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/recurringexpense/v1"
+	recurringexpenseprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/recurringexpense/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+// updateRecurringExpense applies the passed change of the schedule to the recurring expense with the passed ID,
+// stores it and publishes a recurring expense updated event
+func updateRecurringExpense(ctx context.Context, nc *nats.EncodedConn, dbClient bun.IDB, recurringExpenseId string, etag string, change func(r *model.RecurringExpense)) (*recurringexpensev1.RecurringExpense, error) {
+	log := logging.FromContext(ctx)
+
+	var recurringExpense *model.RecurringExpense
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		recurringExpense, err = util.CheckResourceExists[*model.RecurringExpense](ctx, tx, recurringExpenseId)
+		if err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("recurring expense not found", logging.Error(err))
+				return errNoRecurringExpenseWithId
+			}
+			log.Error("failed getting recurring expense", logging.Error(err))
+			return errUpdateRecurringExpense
+		}
+		if err := recurringExpense.CheckEtag(etag); err != nil {
+			return err
+		}
+		change(recurringExpense)
+		modified := model.NewModifiedMetadata(ctx, time.Now())
+		recurringExpense.UpdateTime, recurringExpense.LastModifier = modified.UpdateTime, modified.LastModifier
+		query := model.IncrementRevision(tx.NewUpdate().Model(recurringExpense).Column("paused", "end_time", "next_occurrence_time").Column(model.MetadataUpdateColumns...))
+		if err := util.UpdateReturning(ctx, tx, model.MatchEtag(query, etag), util.WherePK, "revision"); err != nil {
+			if eris.Is(err, sql.ErrNoRows) {
+				if etag != "" {
+					if err := model.ConcurrentEtagMismatch[*model.RecurringExpense](ctx, tx, recurringExpenseId); !eris.As(err, &util.ResourceNotFoundError{}) {
+						return err
+					}
+				}
+				log.Info("recurring expense not found", logging.Error(err))
+				return errNoRecurringExpenseWithId
+			}
+			log.Error("failed updating recurring expense", logging.Error(err))
+			return errUpdateRecurringExpense
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	protoRecurringExpense, err := recurringExpense.IntoProtoRecurringExpense()
+	if err != nil {
+		log.Error("failed converting recurring expense", logging.Error(err))
+		return nil, errConvertRecurringExpense
+	}
+
+	if err := mqClient.PublishResourceEvent(ctx, nc, environment.GetRecurringExpenseUpdatedSubject(recurringExpense.GroupId, recurringExpenseId), &recurringexpenseprocv1.RecurringExpenseUpdated{
+		Id:      recurringExpenseId,
+		GroupId: recurringExpense.GroupId,
+	}, protoRecurringExpense); err != nil {
+		log.Error("failed publishing recurring expense updated event", logging.Error(err))
+		return nil, errPublishRecurringExpenseUpdated
+	}
+	return protoRecurringExpense, nil
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	reportsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/report/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	curClient "github.com/nico151999/high-availability-expense-splitter/pkg/currency/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *reportServer) GetGroupReport(ctx context.Context, req *connect.Request[reportsvcv1.GetGroupReportRequest]) (*connect.Response[reportsvcv1.GetGroupReportResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"groupId",
+				req.Msg.GetGroupId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := checkTimeRange(req.Msg.GetStartTime(), req.Msg.GetEndTime()); err != nil {
+		return nil, errors.NewFieldViolationError(ctx, "the request contains an invalid time range", "end_time", err.Error())
+	}
+
+	r, err := groupReport(ctx, s.dbReads.For(req.Spec().Procedure), s.currencyClient, req.Msg.GetGroupId(), req.Msg.GetStartTime().AsTime(), req.Msg.GetEndTime().AsTime(), req.Msg.GetBucket())
+	if err != nil {
+		if eris.Is(err, util.ErrSelectResource) || eris.Is(err, errSelectReport) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if resErr := new(util.ResourceNotFoundError); eris.As(err, resErr) {
+			return nil, connect.NewError(connect.CodeNotFound, eris.Errorf("the %s with ID %s does not exist", resErr.ResourceName, resErr.ResourceId))
+		} else if eris.Is(err, curClient.ErrCurrencyExchangeRateNotFound) {
+			return nil, connect.NewError(connect.CodeUnavailable, eris.New("the exchange rate of an expense is not available"))
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&reportsvcv1.GetGroupReportResponse{
+		Report: r,
+	}), nil
+}
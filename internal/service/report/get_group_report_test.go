@@ -0,0 +1,136 @@
+package report_test // the dedicated _test package prevents import cycles with the testing package
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/DATA-DOG/go-sqlmock"
+	reportsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/report/v1"
+	reportTesting "github.com/nico151999/high-availability-expense-splitter/internal/service/report/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestGetGroupReport(t *testing.T) {
+	log := logging.GetLogger().Named("testGetGroupReport")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	client, _, closeServer := reportTesting.SetupReportTest(t, ctx, bun.NewDB(db, pgdialect.New()))
+	// we want to close the server only which cascadingly closes the client as well
+	defer func() {
+		if err := closeServer(); err != nil {
+			t.Errorf("failed closing report server: %+v", err)
+		}
+	}()
+
+	groupId := "group-123456789012345"
+	currencyId := "currency-123456789012345"
+	categoryId := "category-123456789012345"
+	alice := "person-123456789012345"
+	bob := "person-543210987654321"
+	start := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)
+	expectCode := func(t *testing.T, err error, code connect.Code) {
+		if connectErr := new(connect.Error); eris.As(err, &connectErr) {
+			if connectErr.Code() != code {
+				t.Fatalf("Expected code: %+v; got: %+v", code, connectErr.Code())
+			}
+		} else {
+			t.Fatalf("Expected connect error, got: %+v", err)
+		}
+	}
+
+	t.Run("Get GroupReport successfully", func(t *testing.T) {
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "groups" (.+) WHERE (.+)"id" = '%s'(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "currency_id"}).
+				FromCSVString(fmt.Sprintf("%s,%s", groupId, currencyId)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "currencies" (.+) WHERE (.+)"id" = '%s'(.+)`, currencyId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "acronym"}).
+				FromCSVString(fmt.Sprintf("%s,EUR", currencyId)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM expenses AS e (.+) WHERE \(e.group_id = '%s'\)(.+) GROUP BY (.+)e.by_id, s.for_id`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"currency_id", "acronym", "day", "amount", "by_id", "for_id"}).
+				FromCSVString(fmt.Sprintf(
+					"%[1]s,EUR,2023-03-14,1500,%[2]s,%[2]s\n%[1]s,EUR,2023-03-14,1500,%[2]s,%[3]s\n%[1]s,EUR,2023-03-20,2050,%[3]s,%[3]s",
+					currencyId, alice, bob)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM expenses AS e (.+) LEFT JOIN expense_category_relations (.+) WHERE \(e.group_id = '%s'\)(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"currency_id", "acronym", "day", "amount", "category_id"}).
+				FromCSVString(fmt.Sprintf(
+					"%[1]s,EUR,2023-03-14,3000,%[2]s\n%[1]s,EUR,2023-03-20,2050,",
+					currencyId, categoryId)))
+		resp, err := client.GetGroupReport(ctx, connect.NewRequest(&reportsvcv1.GetGroupReportRequest{
+			GroupId:   groupId,
+			StartTime: timestamppb.New(start),
+			EndTime:   timestamppb.New(end),
+			Bucket:    reportsvcv1.Bucket_BUCKET_WEEK,
+		}))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		report := resp.Msg.GetReport()
+		if report.GetTotalMainValue() != 50 || report.GetTotalFractionalValue() != 50 {
+			t.Errorf("Expected a total of 50.50; got: %d.%02d", report.GetTotalMainValue(), report.GetTotalFractionalValue())
+		}
+		if len(report.GetCategories()) != 2 || report.GetCategories()[0].GetCategoryId() != "" || report.GetCategories()[1].GetMainValue() != 30 {
+			t.Errorf("Expected the uncategorized spending followed by 30.00 of the category; got: %+v", report.GetCategories())
+		}
+		if persons := report.GetPersons(); len(persons) != 2 ||
+			persons[0].GetPersonId() != alice || persons[0].GetSpentMainValue() != 15 || persons[0].GetPaidMainValue() != 30 ||
+			persons[1].GetPersonId() != bob || persons[1].GetSpentMainValue() != 35 || persons[1].GetPaidMainValue() != 20 {
+			t.Errorf("Expected alice to spend 15.00 and pay 30.00 and bob to spend 35.50 and pay 20.50; got: %+v", persons)
+		}
+		if len(report.GetCurrencies()) != 1 || report.GetCurrencies()[0].GetMainValue() != report.GetCurrencies()[0].GetConvertedMainValue() {
+			t.Errorf("Expected a single currency which needs no conversion; got: %+v", report.GetCurrencies())
+		}
+		if buckets := report.GetBuckets(); len(buckets) != 2 ||
+			!buckets[0].GetStartTime().AsTime().Equal(time.Date(2023, time.March, 13, 0, 0, 0, 0, time.UTC)) ||
+			!buckets[1].GetStartTime().AsTime().Equal(time.Date(2023, time.March, 20, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Expected the weeks starting on the 13th and 20th of March; got: %+v", buckets)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail getting GroupReport due to an empty time range", func(t *testing.T) {
+		resp, err := client.GetGroupReport(ctx, connect.NewRequest(&reportsvcv1.GetGroupReportRequest{
+			GroupId:   groupId,
+			StartTime: timestamppb.New(start),
+			EndTime:   timestamppb.New(start),
+			Bucket:    reportsvcv1.Bucket_BUCKET_DAY,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectCode(t, err, connect.CodeInvalidArgument)
+	})
+
+	t.Run("Fail getting GroupReport due to non existent group", func(t *testing.T) {
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "groups" (.+) WHERE (.+)"id" = '%s'(.+)`, groupId)).WillReturnError(sql.ErrNoRows)
+		resp, err := client.GetGroupReport(ctx, connect.NewRequest(&reportsvcv1.GetGroupReportRequest{
+			GroupId:   groupId,
+			StartTime: timestamppb.New(start),
+			EndTime:   timestamppb.New(end),
+			Bucket:    reportsvcv1.Bucket_BUCKET_MONTH,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectCode(t, err, connect.CodeNotFound)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+}
@@ -0,0 +1,227 @@
+package report
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	reportsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/report/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/report/v1/reportv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/balance"
+	curClient "github.com/nico151999/high-availability-expense-splitter/pkg/currency/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/report"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var _ reportv1connect.ReportServiceHandler = (*reportServer)(nil)
+
+var errSelectReport = eris.New("failed selecting spending of group")
+var errGetExchangeRate = eris.New("failed getting exchange rate into currency of group")
+var errInvalidTimeRange = eris.New("the end of the time range has to be after its start")
+
+type reportServer struct {
+	dbClient bun.IDB
+	// dbReads is used by read-only endpoints while writes and reads within transactions always use dbClient
+	dbReads        *client.ReadRouter
+	natsClient     *nats.EncodedConn
+	currencyClient curClient.Client
+}
+
+// NewReportServer creates a new instance of report server. The context has no effect on the server's lifecycle.
+func NewReportServer(ctx context.Context, natsServer string, dbConfig client.Config) (*reportServer, error) {
+	log := logging.FromContext(ctx).Named("NewReportServer")
+	ctx = logging.IntoContext(ctx, log)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewReportServerWithDBClient(ctx, dbClient, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
+}
+
+// NewReportServerWithDBClient creates a new instance of report server. The context has no effect on the server's lifecycle.
+func NewReportServerWithDBClient(ctx context.Context, dbClient bun.IDB, natsServer string) (*reportServer, error) {
+	log := logging.FromContext(ctx).Named("NewReportServerWithDBClient")
+	nc, err := mqClient.NewProtoMQClient(natsServer)
+	if err != nil {
+		msg := "failed connecting to NATS server"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	return &reportServer{
+		dbClient:       dbClient,
+		dbReads:        client.NewReadRouter(dbClient),
+		natsClient:     nc,
+		currencyClient: curClient.NewCachingCurrencyClient(curClient.NewCurrencyClient()),
+	}, nil
+}
+
+func (rps *reportServer) Close() error {
+	rps.natsClient.Close()
+	return rps.dbReads.Close()
+}
+
+// checkTimeRange makes sure that the time range ends after it starts
+func checkTimeRange(start *timestamppb.Timestamp, end *timestamppb.Timestamp) error {
+	if !end.AsTime().After(start.AsTime()) {
+		return errInvalidTimeRange
+	}
+	return nil
+}
+
+// groupReport aggregates the spending of the group within the time range. The database sums up the stakes per day and currency along
+// with the dimensions of the report so that only these sums are converted into the currency of the group at the exchange rates of their
+// days and added up into the buckets.
+func groupReport(ctx context.Context, dbClient bun.IDB, currencyClient curClient.Client, groupId string, start time.Time, end time.Time, bucket reportsvcv1.Bucket) (*reportsvcv1.GroupReport, error) {
+	log := logging.FromContext(ctx)
+
+	group, err := util.CheckResourceExists[*model.Group](ctx, dbClient, groupId)
+	if err != nil {
+		return nil, err
+	}
+	currency, err := util.CheckResourceExists[*model.Currency](ctx, dbClient, group.GetCurrencyId())
+	if err != nil {
+		return nil, err
+	}
+	stakeRows, err := model.SelectReportStakeRows(ctx, dbClient, groupId, start, end)
+	if err != nil {
+		log.Error("failed selecting spending per person of group", logging.Error(err))
+		return nil, errSelectReport
+	}
+	categoryRows, err := model.SelectReportCategoryRows(ctx, dbClient, groupId, start, end)
+	if err != nil {
+		log.Error("failed selecting spending per category of group", logging.Error(err))
+		return nil, errSelectReport
+	}
+	convert := func(spending report.Spending) (int64, error) {
+		converted, err := balance.ConvertCurrency(ctx, spending.Amount, spending.Currency, currency.GetAcronym(), spending.Day, currencyClient.GetExchangeRate)
+		if err != nil {
+			log.Error("failed getting exchange rate into currency of group", logging.Error(err))
+			return 0, eris.Wrap(err, errGetExchangeRate.Error())
+		}
+		return converted, nil
+	}
+
+	var total int64
+	spent := make(map[string]int64)
+	paid := make(map[string]int64)
+	// the spending per currency is kept both in the currency itself and converted
+	currencies := make(map[string]struct{ amount, converted int64 })
+	buckets := make(map[time.Time]int64)
+	reportBucket := intoReportBucket(bucket)
+	for _, row := range stakeRows {
+		converted, err := convert(row.Spending)
+		if err != nil {
+			return nil, err
+		}
+		total += converted
+		spent[row.ForId] += converted
+		paid[row.ById] += converted
+		c := currencies[row.CurrencyId]
+		c.amount += row.Amount
+		c.converted += converted
+		currencies[row.CurrencyId] = c
+		buckets[reportBucket.Start(row.Day)] += converted
+	}
+	categories := make(map[string]int64)
+	for _, row := range categoryRows {
+		converted, err := convert(row.Spending)
+		if err != nil {
+			return nil, err
+		}
+		categories[row.CategoryId] += converted
+	}
+
+	totalMainValue, totalFractionalValue := balance.Split(total)
+	r := &reportsvcv1.GroupReport{
+		CurrencyId:           group.GetCurrencyId(),
+		TotalMainValue:       totalMainValue,
+		TotalFractionalValue: totalFractionalValue,
+	}
+	for _, categoryId := range sortedKeys(categories) {
+		mainValue, fractionalValue := balance.Split(categories[categoryId])
+		r.Categories = append(r.Categories, &reportsvcv1.CategorySpending{
+			CategoryId:      categoryId,
+			MainValue:       mainValue,
+			FractionalValue: fractionalValue,
+		})
+	}
+	// a person may pay expenses without having stakes in them and the other way round
+	personIds := make(map[string]struct{}, len(spent))
+	for _, row := range stakeRows {
+		personIds[row.ForId] = struct{}{}
+		personIds[row.ById] = struct{}{}
+	}
+	for _, personId := range sortedKeys(personIds) {
+		spentMainValue, spentFractionalValue := balance.Split(spent[personId])
+		paidMainValue, paidFractionalValue := balance.Split(paid[personId])
+		r.Persons = append(r.Persons, &reportsvcv1.PersonSpending{
+			PersonId:             personId,
+			SpentMainValue:       spentMainValue,
+			SpentFractionalValue: spentFractionalValue,
+			PaidMainValue:        paidMainValue,
+			PaidFractionalValue:  paidFractionalValue,
+		})
+	}
+	for _, currencyId := range sortedKeys(currencies) {
+		mainValue, fractionalValue := balance.Split(currencies[currencyId].amount)
+		convertedMainValue, convertedFractionalValue := balance.Split(currencies[currencyId].converted)
+		r.Currencies = append(r.Currencies, &reportsvcv1.CurrencySpending{
+			CurrencyId:               currencyId,
+			MainValue:                mainValue,
+			FractionalValue:          fractionalValue,
+			ConvertedMainValue:       convertedMainValue,
+			ConvertedFractionalValue: convertedFractionalValue,
+		})
+	}
+	bucketStarts := make([]time.Time, 0, len(buckets))
+	for bucketStart := range buckets {
+		bucketStarts = append(bucketStarts, bucketStart)
+	}
+	sort.Slice(bucketStarts, func(i, j int) bool {
+		return bucketStarts[i].Before(bucketStarts[j])
+	})
+	for _, bucketStart := range bucketStarts {
+		mainValue, fractionalValue := balance.Split(buckets[bucketStart])
+		r.Buckets = append(r.Buckets, &reportsvcv1.BucketSpending{
+			StartTime:       timestamppb.New(bucketStart),
+			MainValue:       mainValue,
+			FractionalValue: fractionalValue,
+		})
+	}
+	return r, nil
+}
+
+func intoReportBucket(bucket reportsvcv1.Bucket) report.Bucket {
+	switch bucket {
+	case reportsvcv1.Bucket_BUCKET_WEEK:
+		return report.Week
+	case reportsvcv1.Bucket_BUCKET_MONTH:
+		return report.Month
+	default:
+		return report.Day
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,114 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	reportsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/report/v1"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	curClient "github.com/nico151999/high-availability-expense-splitter/pkg/currency/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/service"
+	"github.com/rotisserie/eris"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var streamGroupReportAlive = reportsvcv1.StreamGroupReportResponse{
+	Update: &reportsvcv1.StreamGroupReportResponse_StillAlive{},
+}
+
+func (s *reportServer) StreamGroupReport(ctx context.Context, req *connect.Request[reportsvcv1.StreamGroupReportRequest], srv *connect.ServerStream[reportsvcv1.StreamGroupReportResponse]) error {
+	ctx, cancel := context.WithTimeout(
+		logging.IntoContext(
+			ctx,
+			logging.FromContext(ctx).With(
+				logging.String(
+					"groupId",
+					req.Msg.GetGroupId()))),
+		time.Hour)
+	defer cancel()
+
+	if err := checkTimeRange(req.Msg.GetStartTime(), req.Msg.GetEndTime()); err != nil {
+		return errors.NewFieldViolationError(ctx, "the request contains an invalid time range", "end_time", err.Error())
+	}
+
+	// the report depends on the group itself as well as on its expenses whose stakes and category relations are published below the
+	// subject of the expense so that subscribing to all events below the subject of the group covers them all
+	streamSubject := fmt.Sprintf("%s.>", environment.GetGroupSubject(req.Msg.GetGroupId()))
+	if err := service.StreamResource(ctx, s.natsClient.Conn, streamSubject, func(ctx context.Context) (*reportsvcv1.StreamGroupReportResponse, error) {
+		r, err := groupReport(ctx, s.dbReads.For(req.Spec().Procedure), s.currencyClient, req.Msg.GetGroupId(), req.Msg.GetStartTime().AsTime(), req.Msg.GetEndTime().AsTime(), req.Msg.GetBucket())
+		if err != nil {
+			return nil, err
+		}
+		return &reportsvcv1.StreamGroupReportResponse{
+			Update: &reportsvcv1.StreamGroupReportResponse_Report{
+				Report: r,
+			},
+		}, nil
+	}, srv, &streamGroupReportAlive); err != nil {
+		if eris.Is(err, service.ErrResourceNoLongerFound) {
+			return connect.NewError(
+				connect.CodeDataLoss,
+				eris.New("the group does no longer exist"))
+		} else if eris.As(err, &util.ResourceNotFoundError{}) {
+			return connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the group does not exist"))
+		} else if eris.Is(err, util.ErrSelectResource) || eris.Is(err, errSelectReport) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, curClient.ErrCurrencyExchangeRateNotFound) {
+			return connect.NewError(connect.CodeUnavailable, eris.New("the exchange rate of an expense is not available"))
+		} else if eris.Is(err, service.ErrSubscribeResource) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed subscribing to updates",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetMessageSubscriptionErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, service.ErrSendCurrentResourceMessage) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeCanceled,
+				"failed returning current report",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetSendCurrentResourceErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, service.ErrSendStreamAliveMessage) {
+			return errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeCanceled,
+				"failed sending alive message to client",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetSendStreamAliveErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else {
+			return connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/DATA-DOG/go-sqlmock"
+	groupprocv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/processor/group/v1"
+	reportsvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/report/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/report/v1/reportv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	mqtesting "github.com/nico151999/high-availability-expense-splitter/pkg/mq/testing"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestStreamGroupReport(t *testing.T) {
+	log := logging.GetLogger().Named("testStreamGroupReport")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	// the server is created directly so that the test is able to publish events on its NATS server
+	mqServer, port := mqtesting.RunMQServer(-1)
+	defer mqServer.Shutdown()
+	s, err := NewReportServerWithDBClient(ctx, bun.NewDB(db, pgdialect.New()), fmt.Sprintf("nats://127.0.0.1:%d", port))
+	if err != nil {
+		t.Fatalf("failed creating report server: %+v", err)
+	}
+	defer s.Close()
+	mux := http.NewServeMux()
+	mux.Handle(reportv1connect.NewReportServiceHandler(s))
+	httpServer := httptest.NewServer(mux)
+	defer httpServer.Close()
+	client := reportv1connect.NewReportServiceClient(httpServer.Client(), httpServer.URL)
+
+	groupId := "group-123456789012345"
+	eurId := "currency-123456789012345"
+	usdId := "currency-543210987654321"
+	start := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)
+	expectReport := func(currencyId string, acronym string) {
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "groups" (.+) WHERE (.+)"id" = '%s'(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "currency_id"}).
+				FromCSVString(fmt.Sprintf("%s,%s", groupId, currencyId)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "currencies" (.+) WHERE (.+)"id" = '%s'(.+)`, currencyId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "acronym"}).
+				FromCSVString(fmt.Sprintf("%s,%s", currencyId, acronym)))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM expenses AS e (.+) WHERE \(e.group_id = '%s'\)(.+) GROUP BY (.+)e.by_id, s.for_id`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"currency_id", "acronym", "day", "amount", "by_id", "for_id"}))
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM expenses AS e (.+) LEFT JOIN expense_category_relations (.+) WHERE \(e.group_id = '%s'\)(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"currency_id", "acronym", "day", "amount", "category_id"}))
+	}
+	// receiveReport skips the messages telling that the stream is still alive and returns the next report
+	receiveReport := func(t *testing.T, stream *connect.ServerStreamForClient[reportsvcv1.StreamGroupReportResponse]) *reportsvcv1.GroupReport {
+		t.Helper()
+		for stream.Receive() {
+			if r := stream.Msg().GetReport(); r != nil {
+				return r
+			}
+		}
+		t.Fatalf("expected a report but the stream ended: %+v", stream.Err())
+		return nil
+	}
+	openStream := func(t *testing.T) *connect.ServerStreamForClient[reportsvcv1.StreamGroupReportResponse] {
+		t.Helper()
+		stream, err := client.StreamGroupReport(ctx, connect.NewRequest(&reportsvcv1.StreamGroupReportRequest{
+			GroupId:   groupId,
+			StartTime: timestamppb.New(start),
+			EndTime:   timestamppb.New(end),
+			Bucket:    reportsvcv1.Bucket_BUCKET_WEEK,
+		}))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		return stream
+	}
+
+	t.Run("Stream fresh GroupReport on change of the currency of the group", func(t *testing.T) {
+		expectReport(eurId, "EUR")
+		stream := openStream(t)
+		defer stream.Close()
+		if r := receiveReport(t, stream); r.GetCurrencyId() != eurId {
+			t.Fatalf("Expected the report in the initial currency of the group; got: %s", r.GetCurrencyId())
+		}
+
+		expectReport(usdId, "USD")
+		if err := mqClient.PublishEvent(ctx, s.natsClient, environment.GetGroupUpdatedSubject(groupId), &groupprocv1.GroupUpdated{
+			Id: groupId,
+		}); err != nil {
+			t.Fatalf("failed publishing group updated event: %+v", err)
+		}
+		if r := receiveReport(t, stream); r.GetCurrencyId() != usdId {
+			t.Errorf("Expected the report in the changed currency of the group; got: %s", r.GetCurrencyId())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("End GroupReport stream on deletion of the group", func(t *testing.T) {
+		expectReport(eurId, "EUR")
+		stream := openStream(t)
+		defer stream.Close()
+		receiveReport(t, stream)
+
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "groups" (.+) WHERE (.+)"id" = '%s'(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "currency_id"}))
+		if err := mqClient.PublishEvent(ctx, s.natsClient, environment.GetGroupDeletedSubject(groupId), &groupprocv1.GroupDeleted{
+			Id: groupId,
+		}); err != nil {
+			t.Fatalf("failed publishing group deleted event: %+v", err)
+		}
+		for stream.Receive() {
+			if r := stream.Msg().GetReport(); r != nil {
+				t.Fatalf("Expected the stream to end but received a report: %+v", r)
+			}
+		}
+		if connectErr := new(connect.Error); eris.As(stream.Err(), &connectErr) {
+			if connectErr.Code() != connect.CodeDataLoss {
+				t.Fatalf("Expected code: %+v; got: %+v", connect.CodeDataLoss, connectErr.Code())
+			}
+		} else {
+			t.Fatalf("Expected connect error, got: %+v", stream.Err())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+}
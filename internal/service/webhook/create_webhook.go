@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	webhookv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/webhook/v1"
+	webhooksvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/webhook/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *webhookServer) CreateWebhook(ctx context.Context, req *connect.Request[webhooksvcv1.CreateWebhookRequest]) (*connect.Response[webhooksvcv1.CreateWebhookResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"groupId",
+				req.Msg.GetGroupId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	webhook, err := createWebhook(ctx, s.dbClient, req.Msg)
+	if err != nil {
+		if eris.Is(err, errInsertWebhook) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBInsertErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if refErr := new(util.InvalidReferenceError); eris.As(err, refErr) {
+			return nil, errors.NewFieldViolationError(ctx, "the request references an invalid resource", refErr.Field, refErr.Description())
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&webhooksvcv1.CreateWebhookResponse{
+		Webhook: webhook,
+	}), nil
+}
+
+func createWebhook(ctx context.Context, dbClient bun.IDB, req *webhooksvcv1.CreateWebhookRequest) (*webhookv1.Webhook, error) {
+	log := logging.FromContext(ctx)
+
+	var webhook *model.Webhook
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := util.CheckReference[*model.Group](ctx, tx, "group_id", req.GetGroupId()); err != nil {
+			return err
+		}
+		webhook = model.NewWebhook(&webhookv1.Webhook{
+			Id:            util.GenerateIdWithPrefix("webhook"),
+			GroupId:       req.GetGroupId(),
+			Url:           req.GetUrl(),
+			Secret:        req.GetSecret(),
+			ResourceTypes: req.GetResourceTypes(),
+			Actions:       req.GetActions(),
+		}, model.NewCreatedMetadata(ctx, time.Now()))
+		if _, err := tx.NewInsert().Model(webhook).Exec(ctx); err != nil {
+			log.Error("failed inserting webhook", logging.Error(err))
+			return errInsertWebhook
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return webhook.IntoProtoWebhook(), nil
+}
@@ -0,0 +1,124 @@
+package webhook_test // the dedicated _test package prevents import cycles with the testing package
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/DATA-DOG/go-sqlmock"
+	activityv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/activity/v1"
+	webhooksvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/webhook/v1"
+	webhookTesting "github.com/nico151999/high-availability-expense-splitter/internal/service/webhook/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	log := logging.GetLogger().Named("testCreateWebhook")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	client, _, closeServer := webhookTesting.SetupWebhookTest(t, ctx, bun.NewDB(db, pgdialect.New()))
+	// we want to close the server only which cascadingly closes the client as well
+	defer func() {
+		if err := closeServer(); err != nil {
+			t.Errorf("failed closing webhook server: %+v", err)
+		}
+	}()
+
+	groupId := "group-123456789012345"
+	secret := "a-secret-of-sufficient-length"
+	expectInvalidArgument := func(t *testing.T, err error) {
+		if connectErr := new(connect.Error); eris.As(err, &connectErr) {
+			if connectErr.Code() != connect.CodeInvalidArgument {
+				t.Fatalf("Expected code: %+v; got: %+v", connect.CodeInvalidArgument, connectErr.Code())
+			}
+		} else {
+			t.Fatalf("Expected connect error, got: %+v", err)
+		}
+	}
+
+	t.Run("Create Webhook successfully", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "groups" (.+) WHERE (.+)"id" = '%s'(.+)`, groupId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).
+				FromCSVString(groupId))
+		mock.ExpectQuery(fmt.Sprintf(`INSERT INTO "webhooks" (.+)'%s'(.+)`, secret)).
+			WillReturnRows(sqlmock.NewRows([]string{"revision"}).
+				FromCSVString("1"))
+		mock.ExpectCommit()
+		resp, err := client.CreateWebhook(ctx, connect.NewRequest(&webhooksvcv1.CreateWebhookRequest{
+			GroupId:       groupId,
+			Url:           "https://example.com/hooks/expenses",
+			Secret:        secret,
+			ResourceTypes: []activityv1.Activity_ResourceType{activityv1.Activity_RESOURCE_TYPE_EXPENSE},
+		}))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		webhook := resp.Msg.GetWebhook()
+		if webhook.GetGroupId() != groupId {
+			t.Errorf("Expected the webhook to belong to group %s; got: %s", groupId, webhook.GetGroupId())
+		}
+		if webhook.GetSecret() != "" {
+			t.Error("Expected the secret not to be returned")
+		}
+		if webhook.GetDisabled() {
+			t.Error("Expected a new webhook to be enabled")
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail creating Webhook due to a URL that is not HTTP", func(t *testing.T) {
+		resp, err := client.CreateWebhook(ctx, connect.NewRequest(&webhooksvcv1.CreateWebhookRequest{
+			GroupId: groupId,
+			Url:     "ftp://example.com/hooks",
+			Secret:  secret,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectInvalidArgument(t, err)
+	})
+
+	t.Run("Fail creating Webhook due to a short secret", func(t *testing.T) {
+		resp, err := client.CreateWebhook(ctx, connect.NewRequest(&webhooksvcv1.CreateWebhookRequest{
+			GroupId: groupId,
+			Url:     "https://example.com/hooks/expenses",
+			Secret:  "secret",
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectInvalidArgument(t, err)
+	})
+
+	t.Run("Fail creating Webhook due to non existent group", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "groups" (.+) WHERE (.+)"id" = '%s'(.+)`, groupId)).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+		resp, err := client.CreateWebhook(ctx, connect.NewRequest(&webhooksvcv1.CreateWebhookRequest{
+			GroupId: groupId,
+			Url:     "https://example.com/hooks/expenses",
+			Secret:  secret,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		expectInvalidArgument(t, err)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+}
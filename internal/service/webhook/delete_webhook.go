@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	webhooksvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/webhook/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *webhookServer) DeleteWebhook(ctx context.Context, req *connect.Request[webhooksvcv1.DeleteWebhookRequest]) (*connect.Response[webhooksvcv1.DeleteWebhookResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"webhookId",
+				req.Msg.GetId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := deleteWebhook(ctx, s.dbClient, req.Msg.GetId(), req.Msg.GetEtag()); err != nil {
+		if eris.Is(err, errDeleteWebhook) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBDeleteErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoWebhookWithId) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the webhook ID does not exist"))
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the webhook was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&webhooksvcv1.DeleteWebhookResponse{}), nil
+}
+
+// deleteWebhook deletes the webhook along with its delivery log for good which also stops its pending deliveries
+func deleteWebhook(ctx context.Context, dbClient bun.IDB, webhookId string, etag string) error {
+	log := logging.FromContext(ctx)
+
+	return transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		if err := model.CheckCurrentEtag[*model.Webhook](ctx, tx, webhookId, etag); err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("webhook not found", logging.Error(err))
+				return errNoWebhookWithId
+			}
+			return err
+		}
+		res, err := tx.NewDelete().Model((*model.Webhook)(nil)).Where("id = ?", webhookId).ApplyQueryBuilder(func(q bun.QueryBuilder) bun.QueryBuilder {
+			return model.WhereEtag(q, etag)
+		}).Exec(ctx)
+		if err != nil {
+			log.Error("failed deleting webhook", logging.Error(err))
+			return errDeleteWebhook
+		}
+		if deleted, err := res.RowsAffected(); err == nil && deleted == 0 {
+			if etag != "" {
+				if err := model.ConcurrentEtagMismatch[*model.Webhook](ctx, tx, webhookId); !eris.As(err, &util.ResourceNotFoundError{}) {
+					return err
+				}
+			}
+			log.Info("webhook not found")
+			return errNoWebhookWithId
+		}
+		if _, err := tx.NewDelete().Model((*model.WebhookDelivery)(nil)).Where("webhook_id = ?", webhookId).Exec(ctx); err != nil {
+			log.Error("failed deleting webhook deliveries", logging.Error(err))
+			return errDeleteWebhook
+		}
+		return nil
+	})
+}
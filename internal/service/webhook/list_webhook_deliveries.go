@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	webhookv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/webhook/v1"
+	webhooksvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/webhook/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *webhookServer) ListWebhookDeliveries(ctx context.Context, req *connect.Request[webhooksvcv1.ListWebhookDeliveriesRequest]) (*connect.Response[webhooksvcv1.ListWebhookDeliveriesResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"webhookId",
+				req.Msg.GetWebhookId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	pageSize := int(req.Msg.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	deliveries, nextPageToken, err := listWebhookDeliveries(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetWebhookId(), pageSize, req.Msg.GetPageToken())
+	if err != nil {
+		if eris.Is(err, errSelectWebhookDeliveries) || eris.Is(err, util.ErrSelectResource) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if resErr := new(util.ResourceNotFoundError); eris.As(err, resErr) {
+			return nil, connect.NewError(connect.CodeNotFound, eris.Errorf("the %s with ID %s does not exist", resErr.ResourceName, resErr.ResourceId))
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&webhooksvcv1.ListWebhookDeliveriesResponse{
+		Deliveries:    deliveries,
+		NextPageToken: nextPageToken,
+	}), nil
+}
+
+// listWebhookDeliveries returns a page of the delivery log of a webhook starting with the latest delivery. Like the activities
+// they deliver, deliveries are paged by their IDs which sort by creation.
+func listWebhookDeliveries(ctx context.Context, dbClient bun.IDB, webhookId string, pageSize int, pageToken string) ([]*webhookv1.WebhookDelivery, string, error) {
+	log := logging.FromContext(ctx)
+
+	if _, err := util.CheckResourceExists[*model.Webhook](ctx, dbClient, webhookId); err != nil {
+		return nil, "", err
+	}
+
+	var deliveries []*model.WebhookDelivery
+	query := dbClient.NewSelect().Model(&deliveries).
+		Where("webhook_id = ?", webhookId)
+	if pageToken != "" {
+		query = query.Where("id < ?", pageToken)
+	}
+	// one more delivery than requested is selected to tell whether there is a next page
+	if err := query.Order("id DESC").Limit(pageSize + 1).Scan(ctx); err != nil {
+		log.Error("failed getting webhook deliveries", logging.Error(err))
+		return nil, "", errSelectWebhookDeliveries
+	}
+
+	var nextPageToken string
+	if len(deliveries) > pageSize {
+		deliveries = deliveries[:pageSize]
+		nextPageToken = deliveries[pageSize-1].Id
+	}
+	protoDeliveries := make([]*webhookv1.WebhookDelivery, len(deliveries))
+	for i, d := range deliveries {
+		protoDeliveries[i] = d.IntoProtoWebhookDelivery()
+	}
+	return protoDeliveries, nextPageToken, nil
+}
@@ -0,0 +1,121 @@
+package webhook_test // the dedicated _test package prevents import cycles with the testing package
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/DATA-DOG/go-sqlmock"
+	webhookv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/webhook/v1"
+	webhooksvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/webhook/v1"
+	webhookTesting "github.com/nico151999/high-availability-expense-splitter/internal/service/webhook/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestListWebhookDeliveries(t *testing.T) {
+	log := logging.GetLogger().Named("testListWebhookDeliveries")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	client, _, closeServer := webhookTesting.SetupWebhookTest(t, ctx, bun.NewDB(db, pgdialect.New()))
+	// we want to close the server only which cascadingly closes the client as well
+	defer func() {
+		if err := closeServer(); err != nil {
+			t.Errorf("failed closing webhook server: %+v", err)
+		}
+	}()
+
+	webhookId := "webhook-123456789012345"
+	expectWebhook := func() {
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "webhooks" (.+) WHERE (.+)"id" = '%s'(.+)`, webhookId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).
+				FromCSVString(webhookId))
+	}
+	deliveryColumns := []string{"id", "webhook_id", "status", "attempts", "response_status", "error", "create_time", "next_attempt_time"}
+
+	t.Run("List the first page of WebhookDeliveries successfully", func(t *testing.T) {
+		expectWebhook()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "webhook_deliveries" (.+) WHERE \(webhook_id = '%s'\) ORDER BY id DESC LIMIT 3`, webhookId)).
+			WillReturnRows(sqlmock.NewRows(deliveryColumns).
+				AddRow("webhookdelivery-333333333333333", webhookId, 1, 2, 503, "the receiver responded with status 503", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 12, 1, 0, 0, time.UTC)).
+				AddRow("webhookdelivery-222222222222222", webhookId, 2, 1, 200, "", time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC), nil).
+				AddRow("webhookdelivery-111111111111111", webhookId, 3, 8, 0, "failed posting delivery", time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), nil))
+		resp, err := client.ListWebhookDeliveries(ctx, connect.NewRequest(&webhooksvcv1.ListWebhookDeliveriesRequest{
+			WebhookId: webhookId,
+			PageSize:  2,
+		}))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		deliveries := resp.Msg.GetDeliveries()
+		if len(deliveries) != 2 {
+			t.Fatalf("Expected 2 deliveries; got: %d", len(deliveries))
+		}
+		if resp.Msg.GetNextPageToken() != "webhookdelivery-222222222222222" {
+			t.Errorf("Expected the next page to start after the last listed delivery; got: %s", resp.Msg.GetNextPageToken())
+		}
+		if deliveries[0].GetStatus() != webhookv1.WebhookDelivery_STATUS_PENDING || deliveries[0].GetNextAttemptTime() == nil {
+			t.Errorf("Expected the latest delivery to be pending with a next attempt; got: %+v", deliveries[0])
+		}
+		if deliveries[1].GetStatus() != webhookv1.WebhookDelivery_STATUS_SUCCEEDED || deliveries[1].GetNextAttemptTime() != nil {
+			t.Errorf("Expected the second delivery to have succeeded without a next attempt; got: %+v", deliveries[1])
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("List the last page of WebhookDeliveries successfully", func(t *testing.T) {
+		expectWebhook()
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "webhook_deliveries" (.+) WHERE \(webhook_id = '%s'\) AND \(id < 'webhookdelivery-222222222222222'\) ORDER BY id DESC LIMIT 21`, webhookId)).
+			WillReturnRows(sqlmock.NewRows(deliveryColumns).
+				AddRow("webhookdelivery-111111111111111", webhookId, 3, 8, 0, "failed posting delivery", time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), nil))
+		resp, err := client.ListWebhookDeliveries(ctx, connect.NewRequest(&webhooksvcv1.ListWebhookDeliveriesRequest{
+			WebhookId: webhookId,
+			PageToken: "webhookdelivery-222222222222222",
+		}))
+		if err != nil {
+			t.Fatalf("Request failed: %+v", err)
+		}
+		if len(resp.Msg.GetDeliveries()) != 1 {
+			t.Fatalf("Expected 1 delivery; got: %d", len(resp.Msg.GetDeliveries()))
+		}
+		if resp.Msg.GetNextPageToken() != "" {
+			t.Errorf("Expected no next page; got: %s", resp.Msg.GetNextPageToken())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail listing WebhookDeliveries due to non existent webhook", func(t *testing.T) {
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "webhooks" (.+) WHERE (.+)"id" = '%s'(.+)`, webhookId)).WillReturnError(sql.ErrNoRows)
+		resp, err := client.ListWebhookDeliveries(ctx, connect.NewRequest(&webhooksvcv1.ListWebhookDeliveriesRequest{
+			WebhookId: webhookId,
+		}))
+		if err == nil {
+			t.Fatalf("Expected request to fail but received a response: %+v", resp)
+		}
+		if connectErr := new(connect.Error); eris.As(err, &connectErr) {
+			if connectErr.Code() != connect.CodeNotFound {
+				t.Fatalf("Expected code: %+v; got: %+v", connect.CodeNotFound, connectErr.Code())
+			}
+		} else {
+			t.Fatalf("Expected connect error, got: %+v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+}
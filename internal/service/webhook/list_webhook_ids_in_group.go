@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	metadatav1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/metadata/v1"
+	webhooksvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/webhook/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *webhookServer) ListWebhookIdsInGroup(ctx context.Context, req *connect.Request[webhooksvcv1.ListWebhookIdsInGroupRequest]) (*connect.Response[webhooksvcv1.ListWebhookIdsInGroupResponse], error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	webhookIds, err := listWebhookIdsInGroup(ctx, s.dbReads.For(req.Spec().Procedure), req.Msg.GetGroupId(), req.Msg.GetOrderBy(), req.Msg.GetFilter())
+	if err != nil {
+		if eris.Is(err, errSelectWebhookIds) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBSelectErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&webhooksvcv1.ListWebhookIdsInGroupResponse{
+		Ids: webhookIds,
+	}), nil
+}
+
+func listWebhookIdsInGroup(ctx context.Context, dbClient bun.IDB, groupId string, order *metadatav1.MetadataOrder, filter *metadatav1.MetadataFilter) ([]string, error) {
+	log := logging.FromContext(ctx)
+	var webhookIds []string
+	query := dbClient.NewSelect().Model((*model.Webhook)(nil)).Where("group_id = ?", groupId).Column("id")
+	if err := model.ApplyMetadataListOptions(query, order, filter).Order("create_time ASC", "id ASC").Scan(ctx, &webhookIds); err != nil {
+		log.Error("failed getting webhook IDs", logging.Error(err))
+		return nil, errSelectWebhookIds
+	}
+
+	return webhookIds, nil
+}
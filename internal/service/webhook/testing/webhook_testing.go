@@ -0,0 +1,45 @@
+package testing
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+
+	webhookv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/webhook/v1"
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/webhook/v1/webhookv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/internal/service/webhook"
+	clienttesting "github.com/nico151999/high-availability-expense-splitter/pkg/connect/client/testing"
+	servertesting "github.com/nico151999/high-availability-expense-splitter/pkg/connect/server/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/uptrace/bun"
+)
+
+// SetupWebhookTest creates gRPC server and client and returns instances of interfaces allowing to close both the server and the client. The passed context has no effect on the server's lifecycle.
+func SetupWebhookTest(t *testing.T, ctx context.Context, db bun.IDB) (webhookv1connect.WebhookServiceClient, net.Listener, func() error) {
+	log := logging.FromContext(ctx).Named("setupWebhookTest")
+	ctx = logging.IntoContext(ctx, log)
+
+	for k, v := range map[string]string{
+		"GLOBAL_DOMAIN":              "de.test",
+		"DB_SELECT_ERROR_REASON":     "DB_SELECT_ERROR",
+		"DB_DELETE_ERROR_REASON":     "DB_DELETE_ERROR",
+		"DB_UPDATE_ERROR_REASON":     "DB_UPDATE_ERROR",
+		"DB_INSERT_ERROR_REASON":     "DB_INSERT_ERROR",
+		"ETAG_MISMATCH_ERROR_REASON": "ETAG_MISMATCH_ERROR",
+	} {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatalf("failed to set env variable %s: %+v", k, err)
+		}
+	}
+
+	ln, shutdownServer := servertesting.StartTestServer(
+		t,
+		ctx,
+		db,
+		webhook.NewWebhookServerWithDBClient,
+		webhookv1.RegisterWebhookServiceHandler,
+		webhookv1connect.NewWebhookServiceHandler)
+	cl := clienttesting.SetupTestClient(ln, webhookv1connect.NewWebhookServiceClient)
+	return cl, ln, shutdownServer
+}
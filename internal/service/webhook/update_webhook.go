@@ -0,0 +1,136 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"connectrpc.com/connect"
+	webhookv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/common/webhook/v1"
+	webhooksvcv1 "github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/webhook/v1"
+	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/connect/errors"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (s *webhookServer) UpdateWebhook(ctx context.Context, req *connect.Request[webhooksvcv1.UpdateWebhookRequest]) (*connect.Response[webhooksvcv1.UpdateWebhookResponse], error) {
+	ctx = logging.IntoContext(
+		ctx,
+		logging.FromContext(ctx).With(
+			logging.String(
+				"webhookId",
+				req.Msg.GetId())))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	webhook, err := updateWebhook(ctx, s.dbClient, req.Msg.GetId(), req.Msg.GetUpdateFields(), req.Msg.GetEtag())
+	if err != nil {
+		if eris.Is(err, errUpdateWebhook) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeInternal,
+				"failed interacting with database",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason: environment.GetDBUpdateErrorReason(ctx),
+						Domain: environment.GetGlobalDomain(ctx),
+					},
+				})
+		} else if eris.Is(err, errNoWebhookWithId) {
+			return nil, connect.NewError(
+				connect.CodeNotFound,
+				eris.New("the webhook ID does not exist"))
+		} else if etagErr := new(model.EtagMismatchError); eris.As(err, etagErr) {
+			return nil, errors.NewErrorWithDetails(
+				ctx,
+				connect.CodeAborted,
+				"the webhook was modified concurrently",
+				[]protoreflect.ProtoMessage{
+					&errdetails.ErrorInfo{
+						Reason:   environment.GetEtagMismatchErrorReason(ctx),
+						Domain:   environment.GetGlobalDomain(ctx),
+						Metadata: map[string]string{"etag": etagErr.CurrentEtag},
+					},
+				})
+		} else {
+			return nil, connect.NewError(connect.CodeInternal, eris.New("an unexpected error occurred"))
+		}
+	}
+
+	return connect.NewResponse(&webhooksvcv1.UpdateWebhookResponse{
+		Webhook: webhook,
+	}), nil
+}
+
+// updateWebhook changes the URL, secret or filter of a webhook or disables it. Enabling a webhook again resets its
+// consecutive failures so that it is not disabled again by the next failing delivery.
+func updateWebhook(ctx context.Context, dbClient bun.IDB, webhookId string, params []*webhooksvcv1.UpdateWebhookRequest_UpdateField, etag string) (*webhookv1.Webhook, error) {
+	log := logging.FromContext(ctx)
+
+	var webhook *model.Webhook
+	if err := transaction.RunInTx(ctx, dbClient, func(ctx context.Context, tx bun.Tx) error {
+		currentWebhook, err := util.CheckResourceExists[*model.Webhook](ctx, tx, webhookId)
+		if err != nil {
+			if eris.As(err, &util.ResourceNotFoundError{}) {
+				log.Info("webhook not found", logging.Error(err))
+				return errNoWebhookWithId
+			}
+			return err
+		}
+		if err := currentWebhook.CheckEtag(etag); err != nil {
+			return err
+		}
+
+		// the embedded message is passed instead of the one returned by IntoProtoWebhook since the latter lacks the secret
+		webhook = model.NewWebhook(&currentWebhook.Webhook, model.NewModifiedMetadata(ctx, time.Now()))
+		query := model.IncrementRevision(tx.NewUpdate().Column(model.MetadataUpdateColumns...))
+		for _, param := range params {
+			switch option := param.GetUpdateOption().(type) {
+			case *webhooksvcv1.UpdateWebhookRequest_UpdateField_Url:
+				webhook.Url = option.Url
+				query.Column("url")
+			case *webhooksvcv1.UpdateWebhookRequest_UpdateField_Secret:
+				webhook.Secret = option.Secret
+				query.Column("secret")
+			case *webhooksvcv1.UpdateWebhookRequest_UpdateField_Filter:
+				webhook.ResourceTypes = option.Filter.GetResourceTypes()
+				webhook.Actions = option.Filter.GetActions()
+				query.Column("resource_types", "actions")
+			case *webhooksvcv1.UpdateWebhookRequest_UpdateField_Disabled:
+				webhook.Disabled = option.Disabled
+				if option.Disabled {
+					webhook.DisabledReason = disabledManuallyReason
+				} else {
+					webhook.DisabledReason = ""
+					webhook.ConsecutiveFailures = 0
+				}
+				query.Column("disabled", "disabled_reason", "consecutive_failures")
+			}
+		}
+		if err := util.UpdateReturning(ctx, tx, model.MatchEtag(query.Model(webhook), etag), util.WherePK, append([]string{"revision"}, model.MetadataReturningColumns...)...); err != nil {
+			if eris.Is(err, sql.ErrNoRows) {
+				if etag != "" {
+					if err := model.ConcurrentEtagMismatch[*model.Webhook](ctx, tx, webhookId); !eris.As(err, &util.ResourceNotFoundError{}) {
+						return err
+					}
+				}
+				log.Info("webhook not found", logging.Error(err))
+				return errNoWebhookWithId
+			}
+			log.Error("failed updating webhook", logging.Error(err))
+			return errUpdateWebhook
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return webhook.IntoProtoWebhook(), nil
+}
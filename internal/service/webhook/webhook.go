@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/nico151999/high-availability-expense-splitter/gen/lib/go/service/webhook/v1/webhookv1connect"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+var _ webhookv1connect.WebhookServiceHandler = (*webhookServer)(nil)
+
+var errNoWebhookWithId = eris.New("there is no webhook with that ID")
+var errInsertWebhook = eris.New("failed inserting webhook")
+var errUpdateWebhook = eris.New("failed updating webhook")
+var errDeleteWebhook = eris.New("failed deleting webhook")
+var errSelectWebhookIds = eris.New("failed selecting webhook IDs")
+var errSelectWebhookDeliveries = eris.New("failed selecting webhook deliveries")
+
+// defaultPageSize is the number of deliveries listed if the request does not specify a page size
+const defaultPageSize = 20
+
+// disabledManuallyReason is the reason of webhooks disabled through an update
+const disabledManuallyReason = "disabled manually"
+
+type webhookServer struct {
+	dbClient bun.IDB
+	// dbReads is used by read-only endpoints while writes and reads within transactions always use dbClient
+	dbReads *client.ReadRouter
+}
+
+// NewWebhookServer creates a new instance of webhook server. The context has no effect on the server's lifecycle.
+func NewWebhookServer(ctx context.Context, natsServer string, dbConfig client.Config) (*webhookServer, error) {
+	log := logging.FromContext(ctx).Named("NewWebhookServer")
+	ctx = logging.IntoContext(ctx, log)
+	dbClient, err := client.NewDBClient(dbConfig)
+	if err != nil {
+		msg := "failed creating database client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	s, err := NewWebhookServerWithDBClient(ctx, dbClient, natsServer)
+	if err != nil {
+		return nil, err
+	}
+	s.dbReads = client.NewDBReadRouter(dbClient, dbConfig)
+	return s, nil
+}
+
+// NewWebhookServerWithDBClient creates a new instance of webhook server. The context has no effect on the server's lifecycle.
+// Like the notification server it does not connect to the NATS server since webhooks are only read by the webhook processor.
+func NewWebhookServerWithDBClient(ctx context.Context, dbClient bun.IDB, _ string) (*webhookServer, error) {
+	return &webhookServer{
+		dbClient: dbClient,
+		dbReads:  client.NewReadRouter(dbClient),
+	}, nil
+}
+
+func (rps *webhookServer) Close() error {
+	return rps.dbReads.Close()
+}
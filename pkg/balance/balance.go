@@ -0,0 +1,184 @@
+package balance
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+)
+
+// Expense is an expense whose amounts are minor units of a single currency, e.g. cents
+type Expense struct {
+	Time time.Time
+	// ById is the person who paid the expense
+	ById   string
+	Stakes []Stake
+}
+
+// Stake is the share of an expense a person owes the payer
+type Stake struct {
+	ForId  string
+	Amount int64
+}
+
+// Debt is the amount a person owes the rest of a group
+type Debt struct {
+	PersonId string
+	Amount   int64
+	// Since is the time of the expense the balance of the person last turned negative with
+	Since time.Time
+}
+
+// Amount returns the minor units of an amount consisting of main units and hundredths like the values of expense stakes
+func Amount(mainValue int32, fractionalValue int32) int64 {
+	return int64(mainValue)*100 + int64(fractionalValue)
+}
+
+// Split splits minor units into main units and hundredths like the values of expense stakes
+func Split(amount int64) (int32, int32) {
+	return int32(amount / 100), int32(amount % 100)
+}
+
+// Convert converts minor units by the passed exchange rate and rounds the result to minor units
+func Convert(amount int64, rate float64) int64 {
+	return int64(math.Round(float64(amount) * rate))
+}
+
+// RateFunc returns the exchange rate from the source to the destination currency, both identified by their acronyms, at the passed time
+type RateFunc func(ctx context.Context, srcAcronym string, destAcronym string, t time.Time) (float64, error)
+
+// ConvertCurrency converts minor units of the source currency into the destination currency at the exchange rate of the passed time.
+// Amounts already in the destination currency are returned as they are without asking for an exchange rate.
+func ConvertCurrency(ctx context.Context, amount int64, srcAcronym string, destAcronym string, t time.Time, rate RateFunc) (int64, error) {
+	if srcAcronym == destAcronym {
+		return amount, nil
+	}
+	r, err := rate(ctx, srcAcronym, destAcronym, t)
+	if err != nil {
+		return 0, err
+	}
+	return Convert(amount, r), nil
+}
+
+// Debts returns the persons whose balance is negative after all the expenses, ordered by their ID. An expense credits its payer
+// with the stakes of the other persons and debits each of them with their stake. The expenses are replayed in chronological order
+// so that the time a debt has been outstanding since is known; it resets whenever the balance of the person is settled in between.
+func Debts(expenses []Expense) []Debt {
+	sorted := make([]Expense, len(expenses))
+	copy(sorted, expenses)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+
+	balances := make(map[string]int64)
+	since := make(map[string]time.Time)
+	for _, expense := range sorted {
+		changed := make(map[string]bool, len(expense.Stakes)+1)
+		for _, stake := range expense.Stakes {
+			if stake.ForId == expense.ById {
+				continue
+			}
+			balances[expense.ById] += stake.Amount
+			balances[stake.ForId] -= stake.Amount
+			changed[expense.ById] = true
+			changed[stake.ForId] = true
+		}
+		for personId := range changed {
+			if balances[personId] >= 0 {
+				delete(since, personId)
+			} else if _, ok := since[personId]; !ok {
+				since[personId] = expense.Time
+			}
+		}
+	}
+
+	var debts []Debt
+	for personId, balance := range balances {
+		if balance < 0 {
+			debts = append(debts, Debt{
+				PersonId: personId,
+				Amount:   -balance,
+				Since:    since[personId],
+			})
+		}
+	}
+	sort.Slice(debts, func(i, j int) bool {
+		return debts[i].PersonId < debts[j].PersonId
+	})
+	return debts
+}
+
+// Transfer is a payment from a person to another person of a group
+type Transfer struct {
+	FromId string
+	ToId   string
+	Amount int64
+}
+
+// Balances returns the balance of every person involved in the expenses. An expense credits its payer with the stakes of the other
+// persons and debits each of them with their stake, so a positive balance is owed to the person and a negative one is owed by them.
+func Balances(expenses []Expense) map[string]int64 {
+	balances := make(map[string]int64)
+	for _, expense := range expenses {
+		for _, stake := range expense.Stakes {
+			if stake.ForId == expense.ById {
+				continue
+			}
+			balances[expense.ById] += stake.Amount
+			balances[stake.ForId] -= stake.Amount
+		}
+	}
+	return balances
+}
+
+// Settle returns transfers which settle the balances. The person owing the most repeatedly pays the person being owed the most, ties
+// being broken by ID, so that the balances are settled deterministically with fewer transfers than persons whose balance is not settled.
+func Settle(balances map[string]int64) []Transfer {
+	var debtors, creditors []string
+	remaining := make(map[string]int64, len(balances))
+	for personId, balance := range balances {
+		if balance < 0 {
+			debtors = append(debtors, personId)
+		} else if balance > 0 {
+			creditors = append(creditors, personId)
+		} else {
+			continue
+		}
+		remaining[personId] = balance
+	}
+	byAmount := func(ids []string, amount func(string) int64) func(int, int) bool {
+		return func(i, j int) bool {
+			if amount(ids[i]) != amount(ids[j]) {
+				return amount(ids[i]) > amount(ids[j])
+			}
+			return ids[i] < ids[j]
+		}
+	}
+	owes := func(personId string) int64 { return -remaining[personId] }
+	isOwed := func(personId string) int64 { return remaining[personId] }
+
+	var transfers []Transfer
+	for len(debtors) > 0 && len(creditors) > 0 {
+		sort.Slice(debtors, byAmount(debtors, owes))
+		sort.Slice(creditors, byAmount(creditors, isOwed))
+		debtor, creditor := debtors[0], creditors[0]
+		amount := owes(debtor)
+		if isOwed(creditor) < amount {
+			amount = isOwed(creditor)
+		}
+		transfers = append(transfers, Transfer{
+			FromId: debtor,
+			ToId:   creditor,
+			Amount: amount,
+		})
+		remaining[debtor] += amount
+		remaining[creditor] -= amount
+		if remaining[debtor] == 0 {
+			debtors = debtors[1:]
+		}
+		if remaining[creditor] == 0 {
+			creditors = creditors[1:]
+		}
+	}
+	return transfers
+}
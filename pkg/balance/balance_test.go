@@ -0,0 +1,176 @@
+package balance_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/balance"
+	"github.com/rotisserie/eris"
+)
+
+func day(d int) time.Time {
+	return time.Date(2023, time.March, d, 12, 0, 0, 0, time.UTC)
+}
+
+func TestDebts(t *testing.T) {
+	for name, params := range map[string]struct {
+		expenses []balance.Expense
+		expected []balance.Debt
+	}{
+		"No expenses": {
+			nil,
+			nil,
+		},
+		"Split between payer and others": {
+			[]balance.Expense{
+				{Time: day(1), ById: "alice", Stakes: []balance.Stake{{"alice", 1000}, {"bob", 1000}, {"carol", 500}}},
+			},
+			[]balance.Debt{
+				{PersonId: "bob", Amount: 1000, Since: day(1)},
+				{PersonId: "carol", Amount: 500, Since: day(1)},
+			},
+		},
+		"Debt outstanding since it first arose": {
+			[]balance.Expense{
+				{Time: day(5), ById: "alice", Stakes: []balance.Stake{{"bob", 300}}},
+				{Time: day(1), ById: "alice", Stakes: []balance.Stake{{"bob", 200}}},
+			},
+			[]balance.Debt{
+				{PersonId: "bob", Amount: 500, Since: day(1)},
+			},
+		},
+		"Settled debt resets the time it is outstanding since": {
+			[]balance.Expense{
+				{Time: day(1), ById: "alice", Stakes: []balance.Stake{{"bob", 200}}},
+				{Time: day(2), ById: "bob", Stakes: []balance.Stake{{"alice", 200}}},
+				{Time: day(7), ById: "alice", Stakes: []balance.Stake{{"bob", 150}}},
+			},
+			[]balance.Debt{
+				{PersonId: "bob", Amount: 150, Since: day(7)},
+			},
+		},
+		"Partial repayment keeps the time it is outstanding since": {
+			[]balance.Expense{
+				{Time: day(1), ById: "alice", Stakes: []balance.Stake{{"bob", 200}}},
+				{Time: day(3), ById: "bob", Stakes: []balance.Stake{{"alice", 150}}},
+			},
+			[]balance.Debt{
+				{PersonId: "bob", Amount: 50, Since: day(1)},
+			},
+		},
+		"Creditor turning debtor": {
+			[]balance.Expense{
+				{Time: day(1), ById: "alice", Stakes: []balance.Stake{{"bob", 200}}},
+				{Time: day(4), ById: "bob", Stakes: []balance.Stake{{"alice", 500}}},
+			},
+			[]balance.Debt{
+				{PersonId: "alice", Amount: 300, Since: day(4)},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if debts := balance.Debts(params.expenses); !reflect.DeepEqual(debts, params.expected) {
+				t.Errorf("expected debts %+v but got %+v", params.expected, debts)
+			}
+		})
+	}
+}
+
+func TestAmount(t *testing.T) {
+	amount := balance.Amount(12, 34)
+	if amount != 1234 {
+		t.Errorf("expected 1234 minor units but got %d", amount)
+	}
+	if mainValue, fractionalValue := balance.Split(amount); mainValue != 12 || fractionalValue != 34 {
+		t.Errorf("expected the amount to be split into 12 and 34 but got %d and %d", mainValue, fractionalValue)
+	}
+	if converted := balance.Convert(amount, 0.5); converted != 617 {
+		t.Errorf("expected the converted amount to be rounded to 617 but got %d", converted)
+	}
+}
+
+func TestConvertCurrency(t *testing.T) {
+	rates := func(ctx context.Context, src string, dest string, t time.Time) (float64, error) {
+		if src == "USD" && dest == "EUR" && t.Equal(day(15)) {
+			return 0.9, nil
+		}
+		return 0, eris.Errorf("no exchange rate from %s to %s at %s", src, dest, t)
+	}
+
+	for name, params := range map[string]struct {
+		currency string
+		expected int64
+	}{
+		"Same currency":  {"EUR", 1000},
+		"Other currency": {"USD", 900},
+	} {
+		params := params
+		t.Run(name, func(t *testing.T) {
+			converted, err := balance.ConvertCurrency(context.Background(), 1000, params.currency, "EUR", day(15), rates)
+			if err != nil {
+				t.Fatalf("failed converting amount: %+v", err)
+			}
+			if converted != params.expected {
+				t.Errorf("expected %d but got %d", params.expected, converted)
+			}
+		})
+	}
+
+	t.Run("Fail without exchange rate", func(t *testing.T) {
+		if _, err := balance.ConvertCurrency(context.Background(), 1000, "USD", "EUR", day(16), rates); err == nil {
+			t.Errorf("expected converting amount to fail")
+		}
+	})
+}
+
+func TestBalances(t *testing.T) {
+	balances := balance.Balances([]balance.Expense{
+		{Time: day(1), ById: "alice", Stakes: []balance.Stake{{"alice", 1000}, {"bob", 1000}, {"carol", 500}}},
+		{Time: day(2), ById: "bob", Stakes: []balance.Stake{{"alice", 200}}},
+	})
+	expected := map[string]int64{"alice": 1300, "bob": -800, "carol": -500}
+	if !reflect.DeepEqual(balances, expected) {
+		t.Errorf("expected balances %+v but got %+v", expected, balances)
+	}
+}
+
+func TestSettle(t *testing.T) {
+	for name, params := range map[string]struct {
+		balances map[string]int64
+		expected []balance.Transfer
+	}{
+		"Nothing to settle": {
+			map[string]int64{"alice": 0},
+			nil,
+		},
+		"Several debtors of one creditor": {
+			map[string]int64{"alice": 1300, "bob": -800, "carol": -500},
+			[]balance.Transfer{
+				{FromId: "bob", ToId: "alice", Amount: 800},
+				{FromId: "carol", ToId: "alice", Amount: 500},
+			},
+		},
+		"Debtor paying several creditors": {
+			map[string]int64{"alice": 300, "bob": -1000, "carol": 700},
+			[]balance.Transfer{
+				{FromId: "bob", ToId: "carol", Amount: 700},
+				{FromId: "bob", ToId: "alice", Amount: 300},
+			},
+		},
+		"Ties broken by ID": {
+			map[string]int64{"alice": 500, "bob": 500, "carol": -500, "dave": -500},
+			[]balance.Transfer{
+				{FromId: "carol", ToId: "alice", Amount: 500},
+				{FromId: "dave", ToId: "bob", Amount: 500},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if transfers := balance.Settle(params.balances); !reflect.DeepEqual(transfers, params.expected) {
+				t.Errorf("expected transfers %+v but got %+v", params.expected, transfers)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"regexp"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/rotisserie/eris"
+)
+
+// ErrNotFound tells that there is no blob with the requested key
+var ErrNotFound = eris.New("blob not found")
+
+// ErrInvalidKey tells that a key cannot be used to store a blob
+var ErrInvalidKey = eris.New("invalid blob key")
+
+var errUnknownBackend = eris.New("unknown blob store backend")
+
+// keyPattern restricts keys to names that are safe as file names and in URL paths
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]{0,254}$`)
+
+// Store stores binary large objects like the content of attachments by key
+type Store interface {
+	// Put stores the content read from the reader under the key replacing the blob stored under it before and returns the size of the content
+	Put(ctx context.Context, key string, content io.Reader) (int64, error)
+	// Open returns a reader of at most length bytes of the blob stored under the key starting at the offset; a negative length reads to the end.
+	// It returns ErrNotFound if there is no blob with the key.
+	Open(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error)
+	// Delete deletes the blob stored under the key; deleting a blob that does not exist is no error
+	Delete(ctx context.Context, key string) error
+}
+
+// checkKey returns ErrInvalidKey unless the key can be used to store a blob
+func checkKey(key string) error {
+	if !keyPattern.MatchString(key) {
+		return eris.Wrap(ErrInvalidKey, key)
+	}
+	return nil
+}
+
+// Backend is the kind of storage a blob store stores blobs in
+type Backend string
+
+const (
+	// BackendFilesystem stores blobs as files in a directory, e.g. on a persistent volume shared by all replicas
+	BackendFilesystem Backend = "filesystem"
+	// BackendS3 stores blobs in a bucket of an S3 compatible object storage like MinIO
+	BackendS3 Backend = "s3"
+	// BackendMemory keeps blobs in memory which is why they are lost on restart and not shared between processes, e.g. for local development
+	BackendMemory Backend = "memory"
+)
+
+// Config configures the blob store of a service or processor
+type Config struct {
+	Backend Backend
+	// Dir is the directory of the filesystem backend
+	Dir string
+	// S3 configures the S3 backend
+	S3 S3Config
+}
+
+// ParseBackend parses the name of a backend; an empty name selects the filesystem backend
+func ParseBackend(backend string) (Backend, error) {
+	switch b := Backend(backend); b {
+	case "":
+		return BackendFilesystem, nil
+	case BackendFilesystem, BackendS3, BackendMemory:
+		return b, nil
+	default:
+		return "", eris.Wrap(errUnknownBackend, backend)
+	}
+}
+
+// ConfigFromEnvironment reads the blob store config from the environment variables required by the selected backend
+func ConfigFromEnvironment(ctx context.Context) (Config, error) {
+	backend, err := ParseBackend(environment.GetBlobBackend())
+	if err != nil {
+		return Config{}, err
+	}
+	switch backend {
+	case BackendS3:
+		return Config{
+			Backend: backend,
+			S3: S3Config{
+				Endpoint:        environment.GetBlobS3Endpoint(ctx),
+				Region:          environment.GetBlobS3Region(),
+				Bucket:          environment.GetBlobS3Bucket(ctx),
+				AccessKeyId:     environment.GetBlobS3AccessKeyId(ctx),
+				SecretAccessKey: environment.GetBlobS3SecretAccessKey(ctx),
+			},
+		}, nil
+	case BackendMemory:
+		return Config{
+			Backend: backend,
+		}, nil
+	default:
+		return Config{
+			Backend: backend,
+			Dir:     environment.GetBlobFilesystemDir(ctx),
+		}, nil
+	}
+}
+
+// NewStore creates a blob store for the backend selected by the config
+func NewStore(cfg Config) (Store, error) {
+	switch cfg.Backend {
+	case BackendFilesystem, "":
+		return NewFilesystemStore(cfg.Dir)
+	case BackendS3:
+		return NewS3Store(cfg.S3)
+	case BackendMemory:
+		return NewMemoryStore(), nil
+	default:
+		return nil, eris.Wrap(errUnknownBackend, string(cfg.Backend))
+	}
+}
@@ -0,0 +1,152 @@
+package blob_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/blob"
+	"github.com/rotisserie/eris"
+)
+
+func TestParseBackend(t *testing.T) {
+	for name, params := range map[string]struct {
+		backend  string
+		expected blob.Backend
+	}{
+		"Default to the filesystem": {"", blob.BackendFilesystem},
+		"Parse the filesystem":      {"filesystem", blob.BackendFilesystem},
+		"Parse S3":                  {"s3", blob.BackendS3},
+		"Parse memory":              {"memory", blob.BackendMemory},
+	} {
+		params := params
+		t.Run(name, func(t *testing.T) {
+			backend, err := blob.ParseBackend(params.backend)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if backend != params.expected {
+				t.Errorf("expected backend %s but got %s", params.expected, backend)
+			}
+		})
+	}
+
+	t.Run("Reject an unknown backend", func(t *testing.T) {
+		if _, err := blob.ParseBackend("floppy"); err == nil {
+			t.Error("expected the backend to be rejected")
+		}
+	})
+}
+
+func TestStores(t *testing.T) {
+	for name, newStore := range map[string]func(t *testing.T) blob.Store{
+		"Filesystem": func(t *testing.T) blob.Store {
+			store, err := blob.NewStore(blob.Config{
+				Backend: blob.BackendFilesystem,
+				Dir:     t.TempDir(),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return store
+		},
+		"Memory": func(t *testing.T) blob.Store {
+			store, err := blob.NewStore(blob.Config{
+				Backend: blob.BackendMemory,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return store
+		},
+	} {
+		newStore := newStore
+		t.Run(name, func(t *testing.T) {
+			testStore(t, newStore(t))
+		})
+	}
+}
+
+func testStore(t *testing.T, store blob.Store) {
+	ctx := context.Background()
+	const content = "0123456789"
+
+	t.Run("Put a blob", func(t *testing.T) {
+		size, err := store.Put(ctx, "attachment-123456789012345", strings.NewReader(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size != int64(len(content)) {
+			t.Errorf("expected size %d but got %d", len(content), size)
+		}
+	})
+
+	for name, params := range map[string]struct {
+		offset   int64
+		length   int64
+		expected string
+	}{
+		"Read the whole blob":              {0, -1, content},
+		"Read a range":                     {2, 3, "234"},
+		"Read to the end from an offset":   {7, -1, "789"},
+		"Read a range exceeding the end":   {8, 5, "89"},
+		"Read nothing at the end":          {10, -1, ""},
+		"Read nothing with an empty range": {4, 0, ""},
+	} {
+		params := params
+		t.Run(name, func(t *testing.T) {
+			r, err := store.Open(ctx, "attachment-123456789012345", params.offset, params.length)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+			read, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(read) != params.expected {
+				t.Errorf("expected to read %q but got %q", params.expected, read)
+			}
+		})
+	}
+
+	t.Run("Replace a blob", func(t *testing.T) {
+		if _, err := store.Put(ctx, "attachment-123456789012345", strings.NewReader("replaced")); err != nil {
+			t.Fatal(err)
+		}
+		r, err := store.Open(ctx, "attachment-123456789012345", 0, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		read, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(read) != "replaced" {
+			t.Errorf("expected to read the replaced blob but got %q", read)
+		}
+	})
+
+	t.Run("Delete a blob", func(t *testing.T) {
+		if err := store.Delete(ctx, "attachment-123456789012345"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.Open(ctx, "attachment-123456789012345", 0, -1); !eris.Is(err, blob.ErrNotFound) {
+			t.Errorf("expected the deleted blob not to be found but got %v", err)
+		}
+	})
+
+	t.Run("Delete a blob that does not exist", func(t *testing.T) {
+		if err := store.Delete(ctx, "attachment-123456789012345"); err != nil {
+			t.Errorf("expected deleting a missing blob to succeed but got %v", err)
+		}
+	})
+
+	t.Run("Reject a key escaping the store", func(t *testing.T) {
+		if _, err := store.Put(ctx, "../escaped", strings.NewReader(content)); !eris.Is(err, blob.ErrInvalidKey) {
+			t.Errorf("expected the key to be rejected but got %v", err)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/rotisserie/eris"
+)
+
+var _ Store = (*filesystemStore)(nil)
+
+type filesystemStore struct {
+	dir string
+}
+
+// NewFilesystemStore creates a blob store storing each blob as a file named after its key in the passed directory which is created if it does not exist
+func NewFilesystemStore(dir string) (*filesystemStore, error) {
+	if dir == "" {
+		return nil, eris.New("the blob directory must not be empty")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, eris.Wrap(err, "failed creating blob directory")
+	}
+	return &filesystemStore{
+		dir: dir,
+	}, nil
+}
+
+func (s *filesystemStore) Put(ctx context.Context, key string, content io.Reader) (int64, error) {
+	if err := checkKey(key); err != nil {
+		return 0, err
+	}
+	// the content is written to a temporary file which replaces the blob once it is complete so that readers never see a partial blob
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return 0, eris.Wrap(err, "failed creating temporary blob file")
+	}
+	defer os.Remove(tmp.Name())
+	size, err := io.Copy(tmp, content)
+	if err != nil {
+		tmp.Close()
+		return 0, eris.Wrap(err, "failed writing blob")
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, eris.Wrap(err, "failed writing blob")
+	}
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		return 0, eris.Wrap(err, "failed storing blob")
+	}
+	return size, nil
+}
+
+func (s *filesystemStore) Open(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(s.path(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, eris.Wrap(ErrNotFound, key)
+		}
+		return nil, eris.Wrap(err, "failed opening blob")
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, eris.Wrap(err, "failed seeking blob")
+	}
+	if length < 0 {
+		return f, nil
+	}
+	return limitedReadCloser{
+		Reader: io.LimitReader(f, length),
+		Closer: f,
+	}, nil
+}
+
+func (s *filesystemStore) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return eris.Wrap(err, "failed deleting blob")
+	}
+	return nil
+}
+
+func (s *filesystemStore) path(key string) string {
+	return filepath.Join(s.dir, key)
+}
+
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
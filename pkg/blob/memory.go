@@ -0,0 +1,70 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/rotisserie/eris"
+)
+
+var _ Store = (*memoryStore)(nil)
+
+type memoryStore struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+}
+
+// NewMemoryStore creates a blob store keeping all blobs in memory
+func NewMemoryStore() *memoryStore {
+	return &memoryStore{
+		blobs: make(map[string][]byte),
+	}
+}
+
+func (s *memoryStore) Put(ctx context.Context, key string, content io.Reader) (int64, error) {
+	if err := checkKey(key); err != nil {
+		return 0, err
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return 0, eris.Wrap(err, "failed reading blob")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blobs[key] = data
+	return int64(len(data)), nil
+}
+
+func (s *memoryStore) Open(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	data, ok := s.blobs[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, eris.Wrap(ErrNotFound, key)
+	}
+	// the stored slice is never modified but replaced which is why it can be read without holding the lock
+	size := int64(len(data))
+	if offset > size {
+		offset = size
+	}
+	end := size
+	if length >= 0 && offset+length < size {
+		end = offset + length
+	}
+	return io.NopCloser(bytes.NewReader(data[offset:end])), nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.blobs, key)
+	return nil
+}
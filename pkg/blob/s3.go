@@ -0,0 +1,254 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+// emptyPayloadHash is the hex encoded SHA-256 hash of an empty request body
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Config configures a blob store storing blobs in a bucket of an S3 compatible object storage
+type S3Config struct {
+	// Endpoint is the URL of the object storage, e.g. https://s3.eu-central-1.amazonaws.com or http://minio:9000
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyId     string
+	SecretAccessKey string
+}
+
+var _ Store = (*s3Store)(nil)
+
+type s3Store struct {
+	endpoint   *url.URL
+	cfg        S3Config
+	httpClient *http.Client
+}
+
+// NewS3Store creates a blob store storing each blob as object named after its key in the configured bucket. It addresses the bucket
+// in the path of requests rather than in the host name since that is what S3 compatible object storages like MinIO support best.
+func NewS3Store(cfg S3Config) (*s3Store, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed parsing S3 endpoint")
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" || endpoint.Host == "" {
+		return nil, eris.Errorf("the S3 endpoint %s is no HTTP URL", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" {
+		return nil, eris.New("the S3 bucket must not be empty")
+	}
+	if cfg.Region == "" {
+		// S3 compatible object storages that do not know of regions accept the default region of S3
+		cfg.Region = "us-east-1"
+	}
+	return &s3Store{
+		endpoint:   endpoint,
+		cfg:        cfg,
+		httpClient: http.DefaultClient,
+	}, nil
+}
+
+// Put uploads the blob in a single request which requires the content to be read into memory to sign it
+func (s *s3Store) Put(ctx context.Context, key string, content io.Reader) (int64, error) {
+	if err := checkKey(key); err != nil {
+		return 0, err
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return 0, eris.Wrap(err, "failed reading blob")
+	}
+	hash := sha256.Sum256(data)
+	req, err := s.newRequest(ctx, http.MethodPut, key, bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	req.ContentLength = int64(len(data))
+	res, err := s.do(req, hex.EncodeToString(hash[:]))
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return 0, responseError(res, "failed storing blob")
+	}
+	return int64(len(data)), nil
+}
+
+func (s *s3Store) Open(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	if length == 0 {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	if length > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	} else if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	res, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+		return res.Body, nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the offset is at or beyond the end of the blob
+		res.Body.Close()
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	case http.StatusNotFound:
+		res.Body.Close()
+		return nil, eris.Wrap(ErrNotFound, key)
+	default:
+		defer res.Body.Close()
+		return nil, responseError(res, "failed reading blob")
+	}
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	res, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return responseError(res, "failed deleting blob")
+	}
+}
+
+// newRequest creates a request for the object with the passed key
+func (s *s3Store) newRequest(ctx context.Context, method string, key string, body io.Reader) (*http.Request, error) {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.cfg.Bucket + "/" + key
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed creating S3 request")
+	}
+	return req, nil
+}
+
+// do signs and sends the request whose body has the passed SHA-256 hash
+func (s *s3Store) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	signV4(req, payloadHash, s.cfg.Region, s.cfg.AccessKeyId, s.cfg.SecretAccessKey, time.Now())
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed sending S3 request")
+	}
+	return res, nil
+}
+
+// responseError creates an error from an unexpected response including the beginning of the error document S3 responds with
+func responseError(res *http.Response, msg string) error {
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	return eris.Errorf("%s: S3 responded with status %d: %s", msg, res.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// signV4 signs the request for the S3 service with AWS Signature Version 4 by setting its authorization header
+func signV4(req *http.Request, payloadHash string, region string, accessKeyId string, secretAccessKey string, t time.Time) {
+	amzDate := t.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	headers := map[string]string{
+		"host": req.URL.Host,
+	}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "range" || name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL.Path),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyId, scope, signedHeaders, signature))
+}
+
+// canonicalURI URI encodes each segment of the path the way S3 expects it which leaves only unreserved characters unencoded
+func canonicalURI(path string) string {
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		var encoded strings.Builder
+		for _, b := range []byte(segment) {
+			if 'A' <= b && b <= 'Z' || 'a' <= b && b <= 'z' || '0' <= b && b <= '9' || b == '-' || b == '.' || b == '_' || b == '~' {
+				encoded.WriteByte(b)
+			} else {
+				fmt.Fprintf(&encoded, "%%%02X", b)
+			}
+		}
+		segments[i] = encoded.String()
+	}
+	return strings.Join(segments, "/")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,67 @@
+package budget
+
+import (
+	"context"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/balance"
+)
+
+// Thresholds are the percentages of a budget whose reaching is alerted, in ascending order
+var Thresholds = []int32{80, 100}
+
+// Period is the time range from Start inclusive to End exclusive the spending of a budget is summed up in
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains tells if the passed time is within the period
+func (p Period) Contains(t time.Time) bool {
+	return !t.Before(p.Start) && t.Before(p.End)
+}
+
+// Month returns the calendar month in UTC the passed time is in
+func Month(t time.Time) Period {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return Period{
+		Start: start,
+		End:   start.AddDate(0, 1, 0),
+	}
+}
+
+// Expense is an expense counting towards a budget whose amount is the sum of its stakes in minor units of its currency
+type Expense struct {
+	// Currency is the acronym of the currency of the expense
+	Currency string
+	Time     time.Time
+	Amount   int64
+}
+
+// Spent sums up the amounts of the expenses converted into the passed currency at the exchange rates of the times of the expenses
+func Spent(ctx context.Context, expenses []Expense, currency string, rate balance.RateFunc) (int64, error) {
+	var spent int64
+	for _, expense := range expenses {
+		converted, err := balance.ConvertCurrency(ctx, expense.Amount, expense.Currency, currency, expense.Time, rate)
+		if err != nil {
+			return 0, err
+		}
+		spent += converted
+	}
+	return spent, nil
+}
+
+// Reached returns the thresholds the spent amount reaches of the amount of a budget in ascending order; a budget without amount reaches none
+func Reached(spent int64, amount int64) []int32 {
+	if amount <= 0 {
+		return nil
+	}
+	var reached []int32
+	for _, threshold := range Thresholds {
+		if spent*100 >= amount*int64(threshold) {
+			reached = append(reached, threshold)
+		}
+	}
+	return reached
+}
@@ -0,0 +1,103 @@
+package budget_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/budget"
+	"github.com/rotisserie/eris"
+)
+
+func TestMonth(t *testing.T) {
+	for name, params := range map[string]struct {
+		t        time.Time
+		expected budget.Period
+	}{
+		"Middle of a month": {
+			time.Date(2023, time.March, 15, 12, 0, 0, 0, time.UTC),
+			budget.Period{
+				Start: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		"End of the year": {
+			time.Date(2023, time.December, 31, 23, 59, 59, 0, time.UTC),
+			budget.Period{
+				Start: time.Date(2023, time.December, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		"Other time zone": {
+			time.Date(2023, time.April, 1, 1, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+			budget.Period{
+				Start: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			period := budget.Month(params.t)
+			if !period.Start.Equal(params.expected.Start) || !period.End.Equal(params.expected.End) {
+				t.Errorf("Expected period %+v; got: %+v", params.expected, period)
+			}
+			if !period.Contains(params.t) {
+				t.Errorf("Expected period %+v to contain %s", period, params.t)
+			}
+		})
+	}
+}
+
+func TestSpent(t *testing.T) {
+	day := time.Date(2023, time.March, 15, 0, 0, 0, 0, time.UTC)
+	rates := func(ctx context.Context, src string, dest string, t time.Time) (float64, error) {
+		if src == "USD" && dest == "EUR" {
+			return 0.9, nil
+		}
+		return 0, eris.Errorf("no exchange rate from %s to %s", src, dest)
+	}
+
+	t.Run("Convert other currencies", func(t *testing.T) {
+		spent, err := budget.Spent(context.Background(), []budget.Expense{
+			{Currency: "EUR", Time: day, Amount: 1000},
+			{Currency: "USD", Time: day, Amount: 1000},
+		}, "EUR", rates)
+		if err != nil {
+			t.Fatalf("Failed summing up spending: %+v", err)
+		}
+		if spent != 1900 {
+			t.Errorf("Expected 1900 to be spent; got: %d", spent)
+		}
+	})
+
+	t.Run("Fail without exchange rate", func(t *testing.T) {
+		if _, err := budget.Spent(context.Background(), []budget.Expense{
+			{Currency: "NOK", Time: day, Amount: 1000},
+		}, "EUR", rates); err == nil {
+			t.Errorf("Expected summing up spending to fail")
+		}
+	})
+}
+
+func TestReached(t *testing.T) {
+	for name, params := range map[string]struct {
+		spent    int64
+		amount   int64
+		expected []int32
+	}{
+		"Nothing spent":         {0, 10000, nil},
+		"Below first":           {7999, 10000, nil},
+		"Exactly first":         {8000, 10000, []int32{80}},
+		"Between thresholds":    {9999, 10000, []int32{80}},
+		"Exactly the budget":    {10000, 10000, []int32{80, 100}},
+		"Exceeding budget":      {25000, 10000, []int32{80, 100}},
+		"Budget without amount": {100, 0, nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if reached := budget.Reached(params.spent, params.amount); !reflect.DeepEqual(reached, params.expected) {
+				t.Errorf("Expected thresholds %v to be reached; got: %v", params.expected, reached)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"connectrpc.com/connect"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
 	"github.com/rotisserie/eris"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
@@ -86,3 +87,21 @@ func FirstTypedDetailFromError[T protoreflect.ProtoMessage](ctx context.Context,
 	}
 	return details[0], nil
 }
+
+// NewFieldViolationError creates an invalid argument error with bad request details naming the offending field
+func NewFieldViolationError(ctx context.Context, msg string, field string, description string) *connect.Error {
+	return NewErrorWithDetails(
+		ctx,
+		connect.CodeInvalidArgument,
+		msg,
+		[]protoreflect.ProtoMessage{
+			&errdetails.BadRequest{
+				FieldViolations: []*errdetails.BadRequest_FieldViolation{
+					{
+						Field:       field,
+						Description: description,
+					},
+				},
+			},
+		})
+}
@@ -64,3 +64,21 @@ func TestErrorDetail(t *testing.T) {
 		})
 	}
 }
+
+func TestFieldViolationError(t *testing.T) {
+	ctx := context.Background()
+	conErr := errors.NewFieldViolationError(ctx, "the request references an invalid resource", "by_id", "the Person with ID person-123456789012345 does not exist")
+	if conErr.Code() != connect.CodeInvalidArgument {
+		t.Errorf("expected code %d, got %d", connect.CodeInvalidArgument, conErr.Code())
+	}
+	detail, err := errors.FirstTypedDetailFromError[*errdetails.BadRequest](ctx, conErr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(detail.GetFieldViolations()) != 1 {
+		t.Fatalf("expected one field violation, got %d", len(detail.GetFieldViolations()))
+	}
+	if field := detail.GetFieldViolations()[0].GetField(); field != "by_id" {
+		t.Errorf("expected the field violation to name by_id, got %s", field)
+	}
+}
@@ -0,0 +1,55 @@
+package interceptor
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// NewEtagInterceptor creates a connect interceptor that passes the etag sent in the etag header to requests with an etag field
+// unless it is set in the message itself. This allows REST clients to make updates and deletes conditional using If-Match.
+func NewEtagInterceptor() *etagInterceptor {
+	return &etagInterceptor{}
+}
+
+var _ connect.Interceptor = (*etagInterceptor)(nil)
+
+type etagInterceptor struct{}
+
+func (i *etagInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return connect.UnaryFunc(func(
+		ctx context.Context,
+		req connect.AnyRequest,
+	) (connect.AnyResponse, error) {
+		if etag := req.Header().Get(environment.GetEtagHeaderKey()); etag != "" {
+			if msg, ok := req.Any().(proto.Message); ok {
+				setEtag(msg.ProtoReflect(), etag)
+			}
+		}
+		return next(ctx, req)
+	})
+}
+
+// WrapStreamingClient does nothing since this interceptor is a server only implementation
+func (i *etagInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler does nothing since only unary requests accept an etag
+func (i *etagInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}
+
+// setEtag sets the etag field of the message if it has an empty one
+func setEtag(msg protoreflect.Message, etag string) {
+	field := msg.Descriptor().Fields().ByName("etag")
+	if field == nil || field.Kind() != protoreflect.StringKind || field.IsList() {
+		return
+	}
+	if msg.Get(field).String() == "" {
+		msg.Set(field, protoreflect.ValueOfString(etag))
+	}
+}
@@ -0,0 +1,61 @@
+package interceptor
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func newRequestMessage(t *testing.T, etagType descriptorpb.FieldDescriptorProto_Type) protoreflect.Message {
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("test.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Request"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("etag"),
+				JsonName: proto.String("etag"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     etagType.Enum(),
+			}},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dynamicpb.NewMessage(file.Messages().Get(0))
+}
+
+func TestSetEtag(t *testing.T) {
+	t.Run("Set an empty etag", func(t *testing.T) {
+		msg := newRequestMessage(t, descriptorpb.FieldDescriptorProto_TYPE_STRING)
+		setEtag(msg, `"2"`)
+		if etag := msg.Get(msg.Descriptor().Fields().ByName("etag")).String(); etag != `"2"` {
+			t.Errorf("expected the etag to be set but got %s", etag)
+		}
+	})
+
+	t.Run("Keep an etag set in the message", func(t *testing.T) {
+		msg := newRequestMessage(t, descriptorpb.FieldDescriptorProto_TYPE_STRING)
+		field := msg.Descriptor().Fields().ByName("etag")
+		msg.Set(field, protoreflect.ValueOfString(`"1"`))
+		setEtag(msg, `"2"`)
+		if etag := msg.Get(field).String(); etag != `"1"` {
+			t.Errorf("expected the etag of the message to be kept but got %s", etag)
+		}
+	})
+
+	t.Run("Ignore etag fields of other types", func(t *testing.T) {
+		msg := newRequestMessage(t, descriptorpb.FieldDescriptorProto_TYPE_INT64)
+		setEtag(msg, `"2"`)
+		if msg.Has(msg.Descriptor().Fields().ByName("etag")) {
+			t.Error("expected the etag field not to be set")
+		}
+	})
+}
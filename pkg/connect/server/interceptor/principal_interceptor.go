@@ -0,0 +1,39 @@
+package interceptor
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
+)
+
+// NewPrincipalInterceptor creates a connect interceptor that adds the principal passed in the principal header to the context of each request.
+// The header is expected to be set by an authenticating proxy which removes it from unauthenticated requests.
+func NewPrincipalInterceptor() *principalInterceptor {
+	return &principalInterceptor{}
+}
+
+var _ connect.Interceptor = (*principalInterceptor)(nil)
+
+type principalInterceptor struct{}
+
+func (i *principalInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return connect.UnaryFunc(func(
+		ctx context.Context,
+		req connect.AnyRequest,
+	) (connect.AnyResponse, error) {
+		return next(principal.IntoContext(ctx, req.Header().Get(environment.GetPrincipalHeaderKey())), req)
+	})
+}
+
+// WrapStreamingClient does nothing since this interceptor is a server only implementation
+func (i *principalInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *principalInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return next(principal.IntoContext(ctx, conn.RequestHeader().Get(environment.GetPrincipalHeaderKey())), conn)
+	}
+}
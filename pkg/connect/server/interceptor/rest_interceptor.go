@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
@@ -32,3 +33,14 @@ func HttpResponseCodeModifier(ctx context.Context, w http.ResponseWriter, p prot
 
 	return nil
 }
+
+// IncomingHeaderMatcher is a REST header matcher that forwards the principal and the etag header to the gRPC server as they are
+// in addition to the headers forwarded by default
+func IncomingHeaderMatcher(key string) (string, bool) {
+	for _, header := range []string{environment.GetPrincipalHeaderKey(), environment.GetEtagHeaderKey()} {
+		if strings.EqualFold(key, header) {
+			return header, true
+		}
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
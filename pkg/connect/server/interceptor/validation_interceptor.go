@@ -3,6 +3,7 @@ package interceptor
 import (
 	"context"
 	"fmt"
+	"io"
 	"reflect"
 
 	"connectrpc.com/connect"
@@ -186,6 +187,10 @@ type streamingValidationHandlerConn struct {
 
 func (p *streamingValidationHandlerConn) Receive(msg any) error {
 	if err := p.StreamingHandlerConn.Receive(msg); err != nil {
+		// the end of a client stream is no failure and has to reach the handler unchanged
+		if eris.Is(err, io.EOF) {
+			return err
+		}
 		p.log.Error("failed receiving message", logging.Error(err))
 		return connect.NewError(connect.CodeInternal, eris.New("failed receiving message"))
 	}
@@ -126,7 +126,7 @@ func ListenAndServe[CONNECT_HANDLER any](
 	if err != nil {
 		return eris.Wrapf(err, "failed to listen on %s", addr)
 	}
-	spanExporter, err := createOtlpExporter(ctx, traceCollectorUrl)
+	spanExporter, err := NewOtlpExporter(ctx, traceCollectorUrl)
 	if err != nil {
 		return eris.Wrap(err, "failed creating OTLP span exporter")
 	}
@@ -188,6 +188,7 @@ func NewServer[CONNECT_HANDLER any](
 
 	restMux := runtime.NewServeMux(
 		runtime.WithForwardResponseOption(interceptor.HttpResponseCodeModifier),
+		runtime.WithIncomingHeaderMatcher(interceptor.IncomingHeaderMatcher),
 		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.HTTPBodyMarshaler{
 			Marshaler: &runtime.JSONPb{
 				MarshalOptions: protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
@@ -204,6 +205,8 @@ func NewServer[CONNECT_HANDLER any](
 		connect.WithInterceptors(
 			otelconnect.NewInterceptor(otelconnect.WithTracerProvider(tp)),
 			interceptor.NewLogInterceptor(ctx),
+			interceptor.NewPrincipalInterceptor(),
+			interceptor.NewEtagInterceptor(),
 			interceptor.NewValidationInterceptor(ctx),
 		),
 	))
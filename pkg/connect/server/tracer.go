@@ -14,7 +14,8 @@ import (
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
-func createOtlpExporter(ctx context.Context, collectorUrl string) (sdktrace.SpanExporter, error) {
+// NewOtlpExporter creates a span exporter sending traces to the OTLP collector at the passed URL
+func NewOtlpExporter(ctx context.Context, collectorUrl string) (sdktrace.SpanExporter, error) {
 	log := logging.FromContext(ctx)
 
 	log.Debug("creating OpenTelemetry exporter")
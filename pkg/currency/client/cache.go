@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+var _ Client = (*cachingClient)(nil)
+
+// maxCachedExchangeRates is the number of exchange rates after which the cache is reset to bound its memory usage
+const maxCachedExchangeRates = 100000
+
+type exchangeRateKey struct {
+	src  string
+	dest string
+	date string
+}
+
+// cachingClient caches exchange rates of past days since they do not change anymore once they are published
+type cachingClient struct {
+	Client
+	exchangeRates map[exchangeRateKey]float64
+	mutex         sync.RWMutex
+}
+
+// NewCachingCurrencyClient wraps a client and caches the exchange rates of past days it returns
+func NewCachingCurrencyClient(c Client) *cachingClient {
+	return &cachingClient{
+		Client:        c,
+		exchangeRates: make(map[exchangeRateKey]float64),
+	}
+}
+
+func (c *cachingClient) GetExchangeRate(ctx context.Context, src string, dest string, date time.Time) (float64, error) {
+	date = date.UTC()
+	key := exchangeRateKey{
+		src:  strings.ToLower(src),
+		dest: strings.ToLower(dest),
+		date: date.Format("2006-01-02"),
+	}
+
+	c.mutex.RLock()
+	rate, ok := c.exchangeRates[key]
+	c.mutex.RUnlock()
+	if ok {
+		return rate, nil
+	}
+
+	rate, err := c.Client.GetExchangeRate(ctx, src, dest, date)
+	if err != nil {
+		return 0, err
+	}
+
+	// the exchange rate of the current day may still change
+	if key.date < time.Now().UTC().Format("2006-01-02") {
+		c.mutex.Lock()
+		if len(c.exchangeRates) >= maxCachedExchangeRates {
+			c.exchangeRates = make(map[exchangeRateKey]float64)
+		}
+		c.exchangeRates[key] = rate
+		c.mutex.Unlock()
+	}
+	return rate, nil
+}
@@ -0,0 +1,57 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/currency/client"
+)
+
+type countingClient struct {
+	client.Client
+	calls int
+}
+
+func (c *countingClient) GetExchangeRate(ctx context.Context, src string, dest string, date time.Time) (float64, error) {
+	c.calls++
+	return float64(date.Day()), nil
+}
+
+func TestCachingCurrencyClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Cache exchange rates of past days", func(t *testing.T) {
+		upstream := &countingClient{}
+		c := client.NewCachingCurrencyClient(upstream)
+		date := time.Date(2023, time.March, 5, 12, 0, 0, 0, time.UTC)
+		for i := 0; i < 3; i++ {
+			rate, err := c.GetExchangeRate(ctx, "EUR", "usd", date)
+			if err != nil {
+				t.Fatalf("failed getting exchange rate: %+v", err)
+			}
+			if rate != 5 {
+				t.Errorf("expected rate to be 5 but it was %f", rate)
+			}
+		}
+		if _, err := c.GetExchangeRate(ctx, "eur", "USD", date.Add(time.Hour)); err != nil {
+			t.Fatalf("failed getting exchange rate: %+v", err)
+		}
+		if upstream.calls != 1 {
+			t.Errorf("expected one upstream call but there were %d", upstream.calls)
+		}
+	})
+
+	t.Run("Do not cache exchange rates of the current day", func(t *testing.T) {
+		upstream := &countingClient{}
+		c := client.NewCachingCurrencyClient(upstream)
+		for i := 0; i < 2; i++ {
+			if _, err := c.GetExchangeRate(ctx, "eur", "usd", time.Now()); err != nil {
+				t.Fatalf("failed getting exchange rate: %+v", err)
+			}
+		}
+		if upstream.calls != 2 {
+			t.Errorf("expected two upstream calls but there were %d", upstream.calls)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"database/sql"
 
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
 	"github.com/uptrace/bun/driver/pgdriver"
@@ -10,16 +11,63 @@ import (
 )
 
 func NewPostgresDBClient(user, password, addr, db string) *bun.DB {
+	return newPostgresDBClient(db,
+		pgdriver.WithUser(user),
+		pgdriver.WithPassword(password),
+		pgdriver.WithAddr(addr),
+		pgdriver.WithDatabase(db),
+		// we intentionally use an insecure connection assuming that the service mesh performs the encryption
+		pgdriver.WithInsecure(true))
+}
+
+// NewPostgresFollowerReadDBClient creates a client whose sessions only run read-only transactions which CockroachDB
+// executes as bounded staleness follower reads, i.e. AS OF SYSTEM TIME follower_read_timestamp()
+func NewPostgresFollowerReadDBClient(user, password, addr, db string) *bun.DB {
+	return newPostgresDBClient(db,
+		pgdriver.WithUser(user),
+		pgdriver.WithPassword(password),
+		pgdriver.WithAddr(addr),
+		pgdriver.WithDatabase(db),
+		// we intentionally use an insecure connection assuming that the service mesh performs the encryption
+		pgdriver.WithInsecure(true),
+		withConnParams(map[string]interface{}{
+			"default_transaction_read_only":          "on",
+			"default_transaction_use_follower_reads": "on",
+		}))
+}
+
+// NewPostgresReplicaDBClient creates a read-only client for the read replica with the passed DSN.
+// The user, password and database are used unless the DSN specifies them.
+func NewPostgresReplicaDBClient(user, password, db, dsn string) *bun.DB {
+	return newPostgresDBClient(db,
+		pgdriver.WithUser(user),
+		pgdriver.WithPassword(password),
+		pgdriver.WithDatabase(db),
+		// we intentionally use an insecure connection assuming that the service mesh performs the encryption
+		pgdriver.WithInsecure(true),
+		pgdriver.WithDSN(dsn),
+		withConnParams(map[string]interface{}{
+			"default_transaction_read_only": "on",
+		}))
+}
+
+func newPostgresDBClient(db string, opts ...pgdriver.Option) *bun.DB {
 	bunDb := bun.NewDB(
-		sql.OpenDB(
-			pgdriver.NewConnector(
-				pgdriver.WithUser(user),
-				pgdriver.WithPassword(password),
-				pgdriver.WithAddr(addr),
-				pgdriver.WithDatabase(db),
-				// we intentionally use an insecure connection assuming that the service mesh performs the encryption
-				pgdriver.WithInsecure(true))),
+		sql.OpenDB(pgdriver.NewConnector(opts...)),
 		pgdialect.New())
 	bunDb.AddQueryHook(bunotel.NewQueryHook(bunotel.WithDBName(db)))
+	bunDb.AddQueryHook(transaction.RetryHook{})
 	return bunDb
 }
+
+// withConnParams adds the passed connection parameters to those already configured, e.g. by a DSN
+func withConnParams(params map[string]interface{}) pgdriver.Option {
+	return func(cfg *pgdriver.Config) {
+		if cfg.ConnParams == nil {
+			cfg.ConnParams = make(map[string]interface{}, len(params))
+		}
+		for key, value := range params {
+			cfg.ConnParams[key] = value
+		}
+	}
+}
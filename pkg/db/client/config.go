@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+// Backend is the kind of database a client connects to
+type Backend string
+
+const (
+	// BackendPostgres connects to a Postgres compatible database server like CockroachDB
+	BackendPostgres Backend = "postgres"
+	// BackendSQLite uses an embedded SQLite database which requires no database server, e.g. for local development and integration tests
+	BackendSQLite Backend = "sqlite"
+)
+
+var errUnknownBackend = eris.New("unknown database backend")
+
+// Config configures the database of a service or processor
+type Config struct {
+	Backend Backend
+	// User, Password, Addr and Database are the connection parameters of the Postgres backend
+	User     string
+	Password string
+	Addr     string
+	Database string
+	// SQLiteDSN is the data source name of the SQLite backend, e.g. file:expense_splitter.db or file::memory:?cache=shared
+	SQLiteDSN string
+	// Reads configures which database the read-only endpoints of a service read from; it only applies to the Postgres backend
+	Reads ReadConfig
+}
+
+// ParseBackend parses the name of a backend; an empty name selects the Postgres backend
+func ParseBackend(backend string) (Backend, error) {
+	switch b := Backend(backend); b {
+	case "":
+		return BackendPostgres, nil
+	case BackendPostgres, BackendSQLite:
+		return b, nil
+	default:
+		return "", eris.Wrap(errUnknownBackend, backend)
+	}
+}
+
+// ConfigFromEnvironment reads the database config from the environment variables required by the selected backend
+func ConfigFromEnvironment(ctx context.Context) (Config, error) {
+	backend, err := ParseBackend(environment.GetDbBackend())
+	if err != nil {
+		return Config{}, err
+	}
+	if backend == BackendSQLite {
+		return Config{
+			Backend:   backend,
+			SQLiteDSN: environment.GetDbSQLiteDSN(ctx),
+		}, nil
+	}
+	reads, err := ParseReadConfig(environment.GetDbReadEndpoints(), environment.GetDbReadReplicaDSN())
+	if err != nil {
+		return Config{}, err
+	}
+	return Config{
+		Backend:  backend,
+		User:     environment.GetDbUser(ctx),
+		Password: environment.GetDbPassword(ctx),
+		Addr:     fmt.Sprintf("%s:%d", environment.GetDbHost(ctx), environment.GetDbPort(ctx)),
+		Database: environment.GetDbName(ctx),
+		Reads:    reads,
+	}, nil
+}
+
+// NewDBClient creates a client for the backend selected by the config
+func NewDBClient(cfg Config) (*bun.DB, error) {
+	switch cfg.Backend {
+	case BackendPostgres, "":
+		return NewPostgresDBClient(cfg.User, cfg.Password, cfg.Addr, cfg.Database), nil
+	case BackendSQLite:
+		return NewSQLiteDBClient(cfg.SQLiteDSN)
+	default:
+		return nil, eris.Wrap(errUnknownBackend, string(cfg.Backend))
+	}
+}
+
+// NewDBReadRouter creates a read router for the primary created from the config. Backends other than
+// Postgres have no follower reads or read replicas which is why all their endpoints read from the primary.
+func NewDBReadRouter(primary *bun.DB, cfg Config) *ReadRouter {
+	if cfg.Backend == BackendPostgres || cfg.Backend == "" {
+		return NewPostgresReadRouter(primary, cfg.User, cfg.Password, cfg.Addr, cfg.Database, cfg.Reads)
+	}
+	return NewReadRouter(primary)
+}
@@ -0,0 +1,72 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+func TestParseBackend(t *testing.T) {
+	for name, params := range map[string]struct {
+		backend  string
+		expected client.Backend
+	}{
+		"Default to Postgres": {"", client.BackendPostgres},
+		"Parse Postgres":      {"postgres", client.BackendPostgres},
+		"Parse SQLite":        {"sqlite", client.BackendSQLite},
+	} {
+		params := params
+		t.Run(name, func(t *testing.T) {
+			backend, err := client.ParseBackend(params.backend)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if backend != params.expected {
+				t.Errorf("expected backend %s but got %s", params.expected, backend)
+			}
+		})
+	}
+
+	t.Run("Reject an unknown backend", func(t *testing.T) {
+		if _, err := client.ParseBackend("oracle"); err == nil {
+			t.Error("expected the backend to be rejected")
+		}
+	})
+}
+
+func TestNewDBClient(t *testing.T) {
+	t.Run("Create an embedded SQLite database", func(t *testing.T) {
+		cfg := client.Config{
+			Backend:   client.BackendSQLite,
+			SQLiteDSN: "file::memory:",
+			Reads: client.ReadConfig{
+				Endpoints: map[string]client.ReadMode{
+					"ListGroupIds": client.ReadModeFollower,
+				},
+			},
+		}
+		db, err := client.NewDBClient(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		if db.Dialect().Name() != dialect.SQLite {
+			t.Errorf("expected the SQLite dialect but got %s", db.Dialect().Name())
+		}
+		if err := db.PingContext(context.Background()); err != nil {
+			t.Errorf("expected the embedded database to be reachable: %+v", err)
+		}
+		if router := client.NewDBReadRouter(db, cfg); router.For("ListGroupIds") != bun.IDB(db) {
+			t.Error("expected the embedded database to serve all reads")
+		}
+	})
+
+	t.Run("Reject an unknown backend", func(t *testing.T) {
+		if _, err := client.NewDBClient(client.Config{Backend: "oracle"}); err == nil {
+			t.Error("expected the backend to be rejected")
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+)
+
+// ReadMode determines which database the read-only queries of an endpoint are sent to
+type ReadMode string
+
+const (
+	// ReadModePrimary reads from the primary and therefore always sees the latest committed state
+	ReadModePrimary ReadMode = "primary"
+	// ReadModeFollower reads from the closest replica using bounded staleness follower reads (AS OF SYSTEM TIME follower_read_timestamp())
+	ReadModeFollower ReadMode = "follower"
+	// ReadModeReplica reads from a separate read replica
+	ReadModeReplica ReadMode = "replica"
+)
+
+var (
+	errInvalidReadEndpoint = eris.New("invalid read endpoint configuration")
+	errInvalidReadMode     = eris.New("invalid read mode")
+	errMissingReplicaDSN   = eris.New("an endpoint reads from the read replica but no read replica DSN is configured")
+	errInvalidReplicaDSN   = eris.New("invalid read replica DSN")
+)
+
+// ReadConfig configures which database the read-only endpoints of a service read from
+type ReadConfig struct {
+	// Endpoints maps endpoint (i.e. RPC method) names to their read mode; endpoints that are not listed read from the primary
+	Endpoints map[string]ReadMode
+	// ReplicaDSN is the DSN of the read replica which is required if an endpoint uses ReadModeReplica
+	ReplicaDSN string
+}
+
+// ParseReadConfig parses a comma separated list of <endpoint>=<primary|follower|replica> pairs along with an optional read replica DSN
+func ParseReadConfig(endpoints, replicaDSN string) (ReadConfig, error) {
+	cfg := ReadConfig{
+		Endpoints:  make(map[string]ReadMode),
+		ReplicaDSN: replicaDSN,
+	}
+	for _, pair := range strings.Split(endpoints, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		endpoint, mode, found := strings.Cut(pair, "=")
+		endpoint = strings.TrimSpace(endpoint)
+		if !found || endpoint == "" {
+			return ReadConfig{}, eris.Wrap(errInvalidReadEndpoint, pair)
+		}
+		switch readMode := ReadMode(strings.TrimSpace(mode)); readMode {
+		case ReadModePrimary, ReadModeFollower, ReadModeReplica:
+			cfg.Endpoints[endpoint] = readMode
+		default:
+			return ReadConfig{}, eris.Wrapf(errInvalidReadMode, "%s of endpoint %s", readMode, endpoint)
+		}
+	}
+	if cfg.uses(ReadModeReplica) {
+		if replicaDSN == "" {
+			return ReadConfig{}, errMissingReplicaDSN
+		}
+		if u, err := url.Parse(replicaDSN); err != nil {
+			return ReadConfig{}, eris.Wrap(errInvalidReplicaDSN, err.Error())
+		} else if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+			return ReadConfig{}, eris.Wrapf(errInvalidReplicaDSN, "unsupported scheme %s", u.Scheme)
+		}
+	}
+	return cfg, nil
+}
+
+func (cfg ReadConfig) uses(mode ReadMode) bool {
+	for _, m := range cfg.Endpoints {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadRouter routes the read-only queries of endpoints to the database configured for them.
+// Writes and reads inside of write transactions must always use the primary since the other read paths may be stale.
+type ReadRouter struct {
+	primary   bun.IDB
+	clients   map[ReadMode]*bun.DB
+	endpoints map[string]ReadMode
+}
+
+// NewReadRouter creates a router sending all reads to the primary
+func NewReadRouter(primary bun.IDB) *ReadRouter {
+	return &ReadRouter{
+		primary:   primary,
+		clients:   make(map[ReadMode]*bun.DB),
+		endpoints: make(map[string]ReadMode),
+	}
+}
+
+// NewPostgresReadRouter creates a router for the passed config which opens connections for the follower reads and the read replica only if an endpoint uses them.
+// The read replica is accessed with the credentials of the primary unless its DSN contains different ones.
+func NewPostgresReadRouter(primary bun.IDB, user, password, addr, db string, cfg ReadConfig) *ReadRouter {
+	router := NewReadRouter(primary)
+	for endpoint, mode := range cfg.Endpoints {
+		router.endpoints[endpoint] = mode
+	}
+	if cfg.uses(ReadModeFollower) {
+		router.clients[ReadModeFollower] = NewPostgresFollowerReadDBClient(user, password, addr, db)
+	}
+	if cfg.uses(ReadModeReplica) {
+		router.clients[ReadModeReplica] = NewPostgresReplicaDBClient(user, password, db, cfg.ReplicaDSN)
+	}
+	return router
+}
+
+// For returns the database the passed endpoint reads from. The endpoint may be the bare method name
+// or the full procedure of the endpoint (e.g. /service.group.v1.GroupService/ListGroupIds).
+func (r *ReadRouter) For(endpoint string) bun.IDB {
+	if i := strings.LastIndex(endpoint, "/"); i >= 0 {
+		endpoint = endpoint[i+1:]
+	}
+	if client, ok := r.clients[r.endpoints[endpoint]]; ok {
+		return client
+	}
+	return r.primary
+}
+
+// Close closes the connections opened for the read paths other than the primary
+func (r *ReadRouter) Close() error {
+	var closeErr error
+	for _, client := range r.clients {
+		if err := client.Close(); err != nil && closeErr == nil {
+			closeErr = err
+		}
+	}
+	return closeErr
+}
@@ -0,0 +1,82 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/client"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestParseReadConfig(t *testing.T) {
+	t.Run("Parse endpoints and their read modes", func(t *testing.T) {
+		cfg, err := client.ParseReadConfig(" ListGroupIds=follower, StreamGroupIds = replica,GetGroup=primary,", "postgresql://replica:26257/expense_splitter")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]client.ReadMode{
+			"ListGroupIds":   client.ReadModeFollower,
+			"StreamGroupIds": client.ReadModeReplica,
+			"GetGroup":       client.ReadModePrimary,
+		}
+		if len(cfg.Endpoints) != len(expected) {
+			t.Fatalf("expected %d endpoints but got %d", len(expected), len(cfg.Endpoints))
+		}
+		for endpoint, mode := range expected {
+			if cfg.Endpoints[endpoint] != mode {
+				t.Errorf("expected endpoint %s to read from %s but got %s", endpoint, mode, cfg.Endpoints[endpoint])
+			}
+		}
+	})
+
+	t.Run("Accept an empty configuration", func(t *testing.T) {
+		cfg, err := client.ParseReadConfig("", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cfg.Endpoints) != 0 {
+			t.Errorf("expected no endpoints but got %d", len(cfg.Endpoints))
+		}
+	})
+
+	for name, params := range map[string][2]string{
+		"Reject an endpoint without read mode":        {"ListGroupIds", ""},
+		"Reject an unknown read mode":                 {"ListGroupIds=nearest", ""},
+		"Reject reading from a replica without DSN":   {"ListGroupIds=replica", ""},
+		"Reject a replica DSN with an unknown scheme": {"ListGroupIds=replica", "mysql://replica:3306/expense_splitter"},
+	} {
+		params := params
+		t.Run(name, func(t *testing.T) {
+			if _, err := client.ParseReadConfig(params[0], params[1]); err == nil {
+				t.Error("expected the configuration to be rejected")
+			}
+		})
+	}
+}
+
+func TestReadRouter(t *testing.T) {
+	sqlDb, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer sqlDb.Close()
+	primary := bun.NewDB(sqlDb, pgdialect.New())
+
+	cfg, err := client.ParseReadConfig("ListGroupIds=follower", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := client.NewPostgresReadRouter(primary, "user", "password", "localhost:26257", "expense_splitter", cfg)
+	defer router.Close()
+
+	if db := router.For("/service.group.v1.GroupService/ListGroupIds"); db == bun.IDB(primary) {
+		t.Error("expected the follower read endpoint not to read from the primary")
+	}
+	if db := router.For("ListGroupIds"); db == bun.IDB(primary) {
+		t.Error("expected the endpoint to be matched by its method name")
+	}
+	if db := router.For("/service.group.v1.GroupService/GetGroup"); db != bun.IDB(primary) {
+		t.Error("expected an endpoint without configuration to read from the primary")
+	}
+}
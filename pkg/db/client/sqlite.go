@@ -0,0 +1,32 @@
+package client
+
+import (
+	"database/sql"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/extra/bunotel"
+	_ "modernc.org/sqlite"
+)
+
+var errOpenSQLite = eris.New("failed opening SQLite database")
+
+// NewSQLiteDBClient creates a client for the embedded SQLite database with the passed data source name
+func NewSQLiteDBClient(dsn string) (*bun.DB, error) {
+	sqlDb, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, eris.Wrap(errOpenSQLite, err.Error())
+	}
+	// SQLite only allows a single writer at a time and every new connection to an in-memory database opens an empty one
+	sqlDb.SetMaxOpenConns(1)
+	sqlDb.SetMaxIdleConns(1)
+	sqlDb.SetConnMaxLifetime(0)
+	sqlDb.SetConnMaxIdleTime(0)
+
+	bunDb := bun.NewDB(sqlDb, sqlitedialect.New())
+	bunDb.AddQueryHook(bunotel.NewQueryHook(bunotel.WithDBName(dsn)))
+	bunDb.AddQueryHook(transaction.RetryHook{})
+	return bunDb, nil
+}
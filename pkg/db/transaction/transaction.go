@@ -0,0 +1,140 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	maxAttempts    = 5
+	initialBackoff = 10 * time.Millisecond
+	maxBackoff     = time.Second
+
+	tracerName = "github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+)
+
+// serializationFailure is the SQLSTATE CockroachDB (and Postgres) return for transactions that may succeed when retried
+const serializationFailure = "40001"
+
+type attemptKey struct{}
+
+// attempt is put into the context of a transaction attempt so the RetryHook can mark it as retryable.
+// Callers usually replace the underlying DB error with their own one which is why the returned error is not sufficient.
+type attempt struct {
+	retryable atomic.Bool
+}
+
+// RunInTx runs f in a transaction and retries the whole transaction with exponential backoff and jitter if it failed
+// due to a retryable error like a serialization failure under contention. Since f may run several times it must not have
+// side effects outside the transaction; e.g. messages must be published after RunInTx returned successfully.
+// If db already is a transaction f runs in a nested transaction without retries since only the outermost one can be retried.
+func RunInTx(ctx context.Context, db bun.IDB, f func(ctx context.Context, tx bun.Tx) error) error {
+	return runInTx(ctx, db, &sql.TxOptions{}, f)
+}
+
+// RunInReadOnlyTx is like RunInTx but runs f in a read-only transaction so that all reads of f see the same snapshot of the database
+func RunInReadOnlyTx(ctx context.Context, db bun.IDB, f func(ctx context.Context, tx bun.Tx) error) error {
+	return runInTx(ctx, db, &sql.TxOptions{ReadOnly: true}, f)
+}
+
+func runInTx(ctx context.Context, db bun.IDB, opts *sql.TxOptions, f func(ctx context.Context, tx bun.Tx) error) error {
+	if _, ok := db.(bun.Tx); ok {
+		return db.RunInTx(ctx, opts, f)
+	}
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "db.transaction")
+	defer span.End()
+	log := logging.FromContext(ctx)
+
+	for attemptNo := 1; ; attemptNo++ {
+		retryable, err := runAttempt(ctx, db, opts, attemptNo, f)
+		span.SetAttributes(attribute.Int("db.transaction.attempts", attemptNo))
+		if err == nil {
+			return nil
+		}
+		if !retryable || attemptNo == maxAttempts {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+
+		delay := backoff(attemptNo)
+		log.Warn("retrying transaction",
+			logging.Int("attempt", attemptNo),
+			logging.Duration("delay", delay),
+			logging.Error(err))
+		span.AddEvent("retrying transaction", trace.WithAttributes(
+			attribute.Int("db.transaction.attempt", attemptNo),
+			attribute.String("db.transaction.delay", delay.String()),
+			attribute.String("error", err.Error())))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+	}
+}
+
+// runAttempt runs a single attempt of a transaction in its own span and tells whether a failed attempt may be retried
+func runAttempt(ctx context.Context, db bun.IDB, opts *sql.TxOptions, attemptNo int, f func(ctx context.Context, tx bun.Tx) error) (bool, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "db.transaction.attempt",
+		trace.WithAttributes(attribute.Int("db.transaction.attempt", attemptNo)))
+	defer span.End()
+
+	a := &attempt{}
+	if err := db.RunInTx(context.WithValue(ctx, attemptKey{}, a), opts, f); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return IsRetryable(err) || a.retryable.Load(), err
+	}
+	return false, nil
+}
+
+// backoff returns a random delay of up to the exponentially growing backoff of the attempt ("full jitter")
+func backoff(attemptNo int) time.Duration {
+	ceiling := initialBackoff << (attemptNo - 1)
+	if ceiling <= 0 || ceiling > maxBackoff {
+		ceiling = maxBackoff
+	}
+	return time.Duration(rand.Int63n(int64(ceiling))) + 1
+}
+
+// IsRetryable tells whether an error returned by the database means that the transaction may succeed when retried
+func IsRetryable(err error) bool {
+	var dbErr interface {
+		error
+		Field(k byte) string
+	}
+	return err != nil && eris.As(err, &dbErr) && dbErr.Field('C') == serializationFailure
+}
+
+// RetryHook marks the transaction attempt a query belongs to as retryable if the query failed with a retryable error
+type RetryHook struct{}
+
+var _ bun.QueryHook = RetryHook{}
+
+func (RetryHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (RetryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	if a, ok := ctx.Value(attemptKey{}).(*attempt); ok && IsRetryable(event.Err) {
+		a.retryable.Store(true)
+	}
+}
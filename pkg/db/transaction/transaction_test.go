@@ -0,0 +1,133 @@
+package transaction_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/transaction"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// dbError mimics the errors of the Postgres driver which expose the SQLSTATE as field 'C'
+type dbError struct {
+	code string
+}
+
+func (e dbError) Error() string {
+	return "database error " + e.code
+}
+
+func (e dbError) Field(k byte) string {
+	if k == 'C' {
+		return e.code
+	}
+	return ""
+}
+
+var errReplaced = eris.New("failed doing something")
+
+func TestRunInTx(t *testing.T) {
+	sqlDb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer sqlDb.Close()
+	db := bun.NewDB(sqlDb, pgdialect.New())
+	db.AddQueryHook(transaction.RetryHook{})
+
+	t.Run("Retry transaction after serialization failure", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE (.+)`).WillReturnError(dbError{code: "40001"})
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE (.+)`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		attempts := 0
+		if err := transaction.RunInTx(context.Background(), db, func(ctx context.Context, tx bun.Tx) error {
+			attempts++
+			if _, err := tx.ExecContext(ctx, "UPDATE groups SET name = 'a'"); err != nil {
+				return errReplaced
+			}
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if attempts != 2 {
+			t.Errorf("expected 2 attempts but got %d", attempts)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Retry transaction after failed commit", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectCommit().WillReturnError(dbError{code: "40001"})
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		attempts := 0
+		if err := transaction.RunInTx(context.Background(), db, func(ctx context.Context, tx bun.Tx) error {
+			attempts++
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if attempts != 2 {
+			t.Errorf("expected 2 attempts but got %d", attempts)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail without retrying due to non-retryable error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE (.+)`).WillReturnError(dbError{code: "23505"})
+		mock.ExpectRollback()
+
+		attempts := 0
+		err := transaction.RunInTx(context.Background(), db, func(ctx context.Context, tx bun.Tx) error {
+			attempts++
+			if _, err := tx.ExecContext(ctx, "UPDATE groups SET name = 'a'"); err != nil {
+				return errReplaced
+			}
+			return nil
+		})
+		if !eris.Is(err, errReplaced) {
+			t.Errorf("expected the error of the transaction function but got %+v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("expected 1 attempt but got %d", attempts)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Give up after too many serialization failures", func(t *testing.T) {
+		attempts := 0
+		for i := 0; i < 5; i++ {
+			mock.ExpectBegin()
+			mock.ExpectCommit().WillReturnError(dbError{code: "40001"})
+		}
+
+		err := transaction.RunInTx(context.Background(), db, func(ctx context.Context, tx bun.Tx) error {
+			attempts++
+			return nil
+		})
+		if !transaction.IsRetryable(err) {
+			t.Errorf("expected the last serialization failure but got %+v", err)
+		}
+		if attempts != 5 {
+			t.Errorf("expected 5 attempts but got %d", attempts)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+}
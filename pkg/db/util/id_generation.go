@@ -1,13 +1,129 @@
 package util
 
 import (
+	"crypto/rand"
+	"encoding/binary"
 	"fmt"
-	"strconv"
+	"io"
+	"sync"
 	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+// idAlphabet holds the base62 digits in ascending ASCII order so that IDs sort like the values they encode
+const idAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+const (
+	// idTimeLength is the number of characters encoding the milliseconds since idEpoch which suffices for about 111 years
+	idTimeLength = 7
+	// idRandomLength is the number of characters encoding the random part, i.e. almost 48 bits of randomness
+	idRandomLength = 8
 )
 
-// GenerateIdWithPrefix generate an ID using the last 15 characters of the deciaml representation of the current unix timestamp prefixed with a keyword
+var (
+	idEpoch       = time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	idMaxTime     = pow62(idTimeLength)
+	idMaxRandom   = pow62(idRandomLength)
+	errReadRandom = eris.New("failed reading randomness for ID")
+	idGeneratorMu sync.RWMutex
+	idGenerator   IdGenerator = NewSortableIdGenerator(time.Now, rand.Reader)
+)
+
+// IdGenerator generates the IDs of new resources
+type IdGenerator interface {
+	// GenerateId generates an ID matching ^<prefix>-[A-Za-z0-9]{15}$
+	GenerateId(prefix string) string
+}
+
+// IdGeneratorFunc allows using an ordinary function as IdGenerator, e.g. for deterministic IDs in tests
+type IdGeneratorFunc func(prefix string) string
+
+func (f IdGeneratorFunc) GenerateId(prefix string) string {
+	return f(prefix)
+}
+
+type sortableIdGenerator struct {
+	mu         sync.Mutex
+	now        func() time.Time
+	random     io.Reader
+	lastTime   uint64
+	lastRandom uint64
+}
+
+// NewSortableIdGenerator creates a generator of IDs whose first 7 base62 characters encode the milliseconds since 2023 and whose
+// last 8 characters are read from random. IDs of the same generator are strictly increasing: within the same millisecond, or if
+// the clock goes backwards, the random part of the previous ID is incremented instead of reading new randomness.
+func NewSortableIdGenerator(now func() time.Time, random io.Reader) IdGenerator {
+	return &sortableIdGenerator{
+		now:    now,
+		random: random,
+	}
+}
+
+func (g *sortableIdGenerator) GenerateId(prefix string) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var ms uint64
+	if elapsed := g.now().Sub(idEpoch).Milliseconds(); elapsed > 0 {
+		ms = uint64(elapsed) % idMaxTime
+	}
+	if ms <= g.lastTime && g.lastRandom+1 < idMaxRandom {
+		ms = g.lastTime
+		g.lastRandom++
+	} else {
+		if ms <= g.lastTime {
+			// the random part of this millisecond is exhausted so we borrow the next one
+			ms = g.lastTime + 1
+		}
+		var b [8]byte
+		if _, err := io.ReadFull(g.random, b[:]); err != nil {
+			panic(eris.Wrap(errReadRandom, err.Error()))
+		}
+		g.lastRandom = binary.BigEndian.Uint64(b[:]) % idMaxRandom
+	}
+	g.lastTime = ms
+
+	var id [idTimeLength + idRandomLength]byte
+	encodeBase62(id[:idTimeLength], ms)
+	encodeBase62(id[idTimeLength:], g.lastRandom)
+	return fmt.Sprintf("%s-%s", prefix, id[:])
+}
+
+// SetIdGenerator replaces the generator used by GenerateIdWithPrefix and returns a function restoring the previous one
+func SetIdGenerator(generator IdGenerator) func() {
+	idGeneratorMu.Lock()
+	defer idGeneratorMu.Unlock()
+	previous := idGenerator
+	idGenerator = generator
+	return func() {
+		idGeneratorMu.Lock()
+		defer idGeneratorMu.Unlock()
+		idGenerator = previous
+	}
+}
+
+// GenerateIdWithPrefix generates an ID prefixed with a keyword using the generator set by SetIdGenerator
+// which defaults to a sortable one combining the current time with randomness
 func GenerateIdWithPrefix(prefix string) string {
-	timestamp := strconv.FormatInt(time.Now().UnixNano(), 10)
-	return fmt.Sprintf("%s-%s", prefix, timestamp[len(timestamp)-15:])
+	idGeneratorMu.RLock()
+	defer idGeneratorMu.RUnlock()
+	return idGenerator.GenerateId(prefix)
+}
+
+// encodeBase62 writes the value into dst as zero-padded base62 number
+func encodeBase62(dst []byte, value uint64) {
+	for i := len(dst) - 1; i >= 0; i-- {
+		dst[i] = idAlphabet[value%62]
+		value /= 62
+	}
+}
+
+func pow62(exp int) uint64 {
+	result := uint64(1)
+	for i := 0; i < exp; i++ {
+		result *= 62
+	}
+	return result
 }
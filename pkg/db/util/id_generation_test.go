@@ -0,0 +1,109 @@
+package util_test
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+)
+
+var idPattern = regexp.MustCompile("^expense-[A-Za-z0-9]{15}$")
+
+func TestSortableIdGenerator(t *testing.T) {
+	t.Run("Generate deterministic IDs", func(t *testing.T) {
+		now := time.Date(2023, time.January, 1, 0, 0, 1, 0, time.UTC)
+		generate := func() string {
+			return util.NewSortableIdGenerator(func() time.Time { return now }, bytes.NewReader(make([]byte, 8))).GenerateId("expense")
+		}
+		if id := generate(); id != "expense-00000G800000000" {
+			t.Errorf("expected the ID to encode one second and zero randomness but got %s", id)
+		}
+		if first, second := generate(), generate(); first != second {
+			t.Errorf("expected generators with the same clock and randomness to generate the same ID but got %s and %s", first, second)
+		}
+	})
+
+	t.Run("Generate monotonic IDs within the same millisecond", func(t *testing.T) {
+		now := time.Now()
+		generator := util.NewSortableIdGenerator(func() time.Time { return now }, bytes.NewReader(bytes.Repeat([]byte{0xff}, 8)))
+		previous := generator.GenerateId("expense")
+		for i := 0; i < 100; i++ {
+			id := generator.GenerateId("expense")
+			if !idPattern.MatchString(id) {
+				t.Fatalf("expected %s to match %s", id, idPattern)
+			}
+			if id <= previous {
+				t.Fatalf("expected %s to sort after %s", id, previous)
+			}
+			previous = id
+		}
+	})
+
+	t.Run("Generate monotonic IDs when the clock goes backwards", func(t *testing.T) {
+		now := time.Now()
+		generator := util.NewSortableIdGenerator(func() time.Time { return now }, bytes.NewReader(make([]byte, 16)))
+		first := generator.GenerateId("expense")
+		now = now.Add(-time.Second)
+		if second := generator.GenerateId("expense"); second <= first {
+			t.Errorf("expected %s to sort after %s", second, first)
+		}
+	})
+
+	t.Run("Generate IDs sorted by creation time", func(t *testing.T) {
+		now := time.Now()
+		generator := util.NewSortableIdGenerator(func() time.Time { return now }, bytes.NewReader(bytes.Repeat([]byte{0xff}, 8)))
+		first := generator.GenerateId("expense")
+		now = now.Add(time.Millisecond)
+		generator = util.NewSortableIdGenerator(func() time.Time { return now }, bytes.NewReader(make([]byte, 8)))
+		if second := generator.GenerateId("expense"); second <= first {
+			t.Errorf("expected %s to sort after %s", second, first)
+		}
+	})
+}
+
+func TestGenerateIdWithPrefix(t *testing.T) {
+	t.Run("Generate unique IDs concurrently", func(t *testing.T) {
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		ids := make([]string, 0, 1000)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					id := util.GenerateIdWithPrefix("expense")
+					mu.Lock()
+					ids = append(ids, id)
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		sort.Strings(ids)
+		for i, id := range ids {
+			if !idPattern.MatchString(id) {
+				t.Fatalf("expected %s to match %s", id, idPattern)
+			}
+			if i > 0 && ids[i-1] == id {
+				t.Fatalf("expected IDs to be unique but %s was generated twice", id)
+			}
+		}
+	})
+
+	t.Run("Inject a deterministic generator", func(t *testing.T) {
+		restore := util.SetIdGenerator(util.IdGeneratorFunc(func(prefix string) string {
+			return prefix + "-000000000000001"
+		}))
+		if id := util.GenerateIdWithPrefix("expense"); id != "expense-000000000000001" {
+			t.Errorf("expected the injected ID but got %s", id)
+		}
+		restore()
+		if id := util.GenerateIdWithPrefix("expense"); id == "expense-000000000000001" {
+			t.Error("expected the default generator to be restored")
+		}
+	})
+}
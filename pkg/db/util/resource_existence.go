@@ -32,6 +32,15 @@ type protoWithId interface {
 var ErrSelectResource = eris.New("could not select resource")
 
 func CheckResourceExists[T protoWithId](ctx context.Context, db bun.IDB, id string) (T, error) {
+	return checkResourceExists[T](ctx, db, id, false)
+}
+
+// CheckResourceExistsWithDeleted is like CheckResourceExists but also finds resources whose soft deleting model marks them as deleted
+func CheckResourceExistsWithDeleted[T protoWithId](ctx context.Context, db bun.IDB, id string) (T, error) {
+	return checkResourceExists[T](ctx, db, id, true)
+}
+
+func checkResourceExists[T protoWithId](ctx context.Context, db bun.IDB, id string, withDeleted bool) (T, error) {
 	var model T
 	model = reflect.New(reflect.TypeOf(model).Elem()).Interface().(T)
 	modelReflect := model.ProtoReflect()
@@ -47,7 +56,11 @@ func CheckResourceExists[T protoWithId](ctx context.Context, db bun.IDB, id stri
 		modelDescriptor.Fields().ByName("id"),
 		protoreflect.ValueOfString(id),
 	)
-	if err := db.NewSelect().Model(model).WherePK().Limit(1).Scan(ctx); err != nil {
+	query := db.NewSelect().Model(model).WherePK().Limit(1)
+	if withDeleted {
+		query = query.WhereAllWithDeleted()
+	}
+	if err := query.Scan(ctx); err != nil {
 		if eris.Is(err, sql.ErrNoRows) {
 			msg := "resource not found"
 			log.Debug(msg, logging.Error(err))
@@ -61,3 +74,103 @@ func CheckResourceExists[T protoWithId](ctx context.Context, db bun.IDB, id stri
 	}
 	return model, nil
 }
+
+var _ error = (*InvalidReferenceError)(nil)
+
+const (
+	ReferenceReasonNotFound       = "does not exist"
+	ReferenceReasonDifferentGroup = "belongs to a different group"
+	ReferenceReasonDeprecated     = "is deprecated"
+)
+
+// InvalidReferenceError tells that a field references a resource that does not exist or must not be referenced
+type InvalidReferenceError struct {
+	Field        string
+	ResourceName string
+	ResourceId   string
+	Reason       string
+}
+
+func (e InvalidReferenceError) Error() string {
+	return fmt.Sprintf("the field %s is invalid: %s", e.Field, e.Description())
+}
+
+// Description describes why the referenced resource is invalid without naming the field
+func (e InvalidReferenceError) Description() string {
+	return fmt.Sprintf("the %s with ID %s %s", e.ResourceName, e.ResourceId, e.Reason)
+}
+
+type protoWithDeprecation interface {
+	protoWithId
+	GetDeprecated() bool
+}
+
+type protoWithGroupId interface {
+	protoWithId
+	GetGroupId() string
+}
+
+// CheckReference checks that the resource referenced by a field exists.
+// Unlike CheckResourceExists it returns an InvalidReferenceError naming the field if it does not.
+func CheckReference[T protoWithId](ctx context.Context, db bun.IDB, field string, id string) (T, error) {
+	model, err := CheckResourceExists[T](ctx, db, id)
+	if err != nil {
+		if resErr := new(ResourceNotFoundError); eris.As(err, resErr) {
+			return model, InvalidReferenceError{
+				Field:        field,
+				ResourceName: resErr.ResourceName,
+				ResourceId:   resErr.ResourceId,
+				Reason:       ReferenceReasonNotFound,
+			}
+		}
+		return model, err
+	}
+	return model, nil
+}
+
+// CheckNonDeprecatedReference checks that the resource referenced by a field exists and is not deprecated. It is meant for references
+// that are created or changed since references to resources deprecated after they were made remain valid.
+func CheckNonDeprecatedReference[T protoWithDeprecation](ctx context.Context, db bun.IDB, field string, id string) (T, error) {
+	model, err := CheckReference[T](ctx, db, field, id)
+	if err != nil {
+		return model, err
+	}
+	if model.GetDeprecated() {
+		modelName := string(model.ProtoReflect().Descriptor().Name())
+		logging.FromContext(ctx).Info(
+			"referenced resource is deprecated",
+			logging.String("resource", modelName),
+			logging.String("field", field))
+		return model, InvalidReferenceError{
+			Field:        field,
+			ResourceName: modelName,
+			ResourceId:   id,
+			Reason:       ReferenceReasonDeprecated,
+		}
+	}
+	return model, nil
+}
+
+// CheckGroupScopedReference checks that the resource referenced by a field exists and belongs to the passed group
+func CheckGroupScopedReference[T protoWithGroupId](ctx context.Context, db bun.IDB, field string, id string, groupId string) (T, error) {
+	model, err := CheckReference[T](ctx, db, field, id)
+	if err != nil {
+		return model, err
+	}
+	if model.GetGroupId() != groupId {
+		modelName := string(model.ProtoReflect().Descriptor().Name())
+		logging.FromContext(ctx).Info(
+			"referenced resource belongs to a different group",
+			logging.String("resource", modelName),
+			logging.String("field", field),
+			logging.String("groupId", groupId),
+			logging.String("referencedGroupId", model.GetGroupId()))
+		return model, InvalidReferenceError{
+			Field:        field,
+			ResourceName: modelName,
+			ResourceId:   id,
+			Reason:       ReferenceReasonDifferentGroup,
+		}
+	}
+	return model, nil
+}
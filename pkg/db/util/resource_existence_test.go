@@ -2,12 +2,14 @@ package util_test
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/nico151999/high-availability-expense-splitter/internal/db/model"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/rotisserie/eris"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
 )
@@ -35,4 +37,65 @@ func TestCheckResourceExistence(t *testing.T) {
 			t.Errorf("there were unfulfilled expectations: %+v", err)
 		}
 	})
+
+	t.Run("Check group-scoped reference successfully", func(t *testing.T) {
+		expenseId := util.GenerateIdWithPrefix("expense")
+		groupId := util.GenerateIdWithPrefix("group")
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "expenses" (.+) WHERE (.+)"id" = '%s'(.+)`, expenseId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id"}).
+				FromCSVString(fmt.Sprintf("%s,%s", expenseId, groupId)))
+		if _, err := util.CheckGroupScopedReference[*model.Expense](context.Background(), bun.NewDB(db, pgdialect.New()), "expense_id", expenseId, groupId); err != nil {
+			t.Error(err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail checking group-scoped reference due to different group", func(t *testing.T) {
+		expenseId := util.GenerateIdWithPrefix("expense")
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "expenses" (.+) WHERE (.+)"id" = '%s'(.+)`, expenseId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "group_id"}).
+				FromCSVString(fmt.Sprintf("%s,%s", expenseId, "group-123456789012345")))
+		_, err := util.CheckGroupScopedReference[*model.Expense](context.Background(), bun.NewDB(db, pgdialect.New()), "expense_id", expenseId, "group-543210987654321")
+		if refErr := new(util.InvalidReferenceError); !eris.As(err, refErr) {
+			t.Fatalf("expected an invalid reference error, got: %+v", err)
+		} else if refErr.Field != "expense_id" || refErr.Reason != util.ReferenceReasonDifferentGroup {
+			t.Errorf("unexpected invalid reference error: %+v", refErr)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail checking non-deprecated reference due to deprecation", func(t *testing.T) {
+		currencyId := util.GenerateIdWithPrefix("currency")
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "currencies" (.+) WHERE (.+)"id" = '%s'(.+)`, currencyId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "deprecated"}).
+				FromCSVString(fmt.Sprintf("%s,true", currencyId)))
+		_, err := util.CheckNonDeprecatedReference[*model.Currency](context.Background(), bun.NewDB(db, pgdialect.New()), "currency_id", currencyId)
+		if refErr := new(util.InvalidReferenceError); !eris.As(err, refErr) {
+			t.Fatalf("expected an invalid reference error, got: %+v", err)
+		} else if refErr.Field != "currency_id" || refErr.Reason != util.ReferenceReasonDeprecated {
+			t.Errorf("unexpected invalid reference error: %+v", refErr)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
+
+	t.Run("Fail checking reference due to non existence", func(t *testing.T) {
+		expenseId := util.GenerateIdWithPrefix("expense")
+		mock.ExpectQuery(fmt.Sprintf(`SELECT (.+) FROM "expenses" (.+) WHERE (.+)"id" = '%s'(.+)`, expenseId)).
+			WillReturnError(sql.ErrNoRows)
+		_, err := util.CheckReference[*model.Expense](context.Background(), bun.NewDB(db, pgdialect.New()), "expense_id", expenseId)
+		if refErr := new(util.InvalidReferenceError); !eris.As(err, refErr) {
+			t.Fatalf("expected an invalid reference error, got: %+v", err)
+		} else if refErr.Field != "expense_id" || refErr.Reason != util.ReferenceReasonNotFound {
+			t.Errorf("unexpected invalid reference error: %+v", refErr)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %+v", err)
+		}
+	})
 }
@@ -0,0 +1,65 @@
+package util
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/feature"
+)
+
+// DeleteReturning deletes the rows of the model matched by where and scans the passed columns of the deleted rows into the model.
+// Dialects without RETURNING select the rows before deleting them which is why it should be called within a transaction.
+// Like a query with RETURNING it returns sql.ErrNoRows if the model is a struct and no row matched.
+func DeleteReturning(ctx context.Context, db bun.IDB, model interface{}, where func(bun.QueryBuilder) bun.QueryBuilder, columns ...string) error {
+	if db.Dialect().Features().Has(feature.Returning) {
+		return db.NewDelete().Model(model).ApplyQueryBuilder(where).Returning(strings.Join(columns, ", ")).Scan(ctx)
+	}
+	if err := db.NewSelect().Model(model).Column(columns...).ApplyQueryBuilder(where).Scan(ctx); err != nil {
+		return err
+	}
+	_, err := db.NewDelete().Model(model).ApplyQueryBuilder(where).Exec(ctx)
+	return err
+}
+
+// UpdateReturning executes the update query restricted by where and scans the passed columns of the updated row into the query's model.
+// Dialects without RETURNING select the row after updating it which is why it should be called within a transaction.
+// Like a query with RETURNING it returns sql.ErrNoRows if no row matched.
+func UpdateReturning(ctx context.Context, db bun.IDB, query *bun.UpdateQuery, where func(bun.QueryBuilder) bun.QueryBuilder, columns ...string) error {
+	query = query.ApplyQueryBuilder(where)
+	if db.Dialect().Features().Has(feature.Returning) {
+		return query.Returning(strings.Join(columns, ", ")).Scan(ctx)
+	}
+	res, err := query.Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return db.NewSelect().Model(query.GetModel().Value()).Column(columns...).ApplyQueryBuilder(where).Scan(ctx)
+}
+
+// UpdateMatchedReturning executes the update query restricted by where and scans the passed columns of the matched rows into the query's model.
+// Unlike UpdateReturning, dialects without RETURNING select the rows before updating them which suits updates after which the rows no longer
+// match where, like soft deletes, as long as the returned columns are not updated. It should be called within a transaction.
+// Like a query with RETURNING it returns sql.ErrNoRows if the model is a struct and no row matched.
+func UpdateMatchedReturning(ctx context.Context, db bun.IDB, query *bun.UpdateQuery, where func(bun.QueryBuilder) bun.QueryBuilder, columns ...string) error {
+	query = query.ApplyQueryBuilder(where)
+	if db.Dialect().Features().Has(feature.Returning) {
+		return query.Returning(strings.Join(columns, ", ")).Scan(ctx)
+	}
+	if err := db.NewSelect().Model(query.GetModel().Value()).Column(columns...).ApplyQueryBuilder(where).Scan(ctx); err != nil {
+		return err
+	}
+	_, err := query.Exec(ctx)
+	return err
+}
+
+// WherePK restricts a query to the primary key of its model
+func WherePK(q bun.QueryBuilder) bun.QueryBuilder {
+	return q.WherePK()
+}
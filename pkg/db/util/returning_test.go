@@ -0,0 +1,176 @@
+package util_test
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
+	"github.com/rotisserie/eris"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/schema"
+	_ "modernc.org/sqlite"
+)
+
+type person struct {
+	bun.BaseModel `bun:"table:people"`
+	Id            string `bun:",pk"`
+	GroupId       string
+	Name          string
+}
+
+type deletablePerson struct {
+	bun.BaseModel `bun:"table:deletable_people"`
+	Id            string `bun:",pk"`
+	GroupId       string
+	DeleteTime    *time.Time `bun:",soft_delete,nullzero"`
+}
+
+// noReturningDialect behaves like a dialect which does not support RETURNING
+type noReturningDialect struct {
+	*sqlitedialect.Dialect
+}
+
+func (d noReturningDialect) Features() feature.Feature {
+	return d.Dialect.Features() &^ feature.Returning
+}
+
+func TestReturning(t *testing.T) {
+	for name, dialect := range map[string]schema.Dialect{
+		"with RETURNING":    sqlitedialect.New(),
+		"without RETURNING": noReturningDialect{sqlitedialect.New()},
+	} {
+		db := openSQLite(t, dialect)
+		ctx := context.Background()
+		if _, err := db.NewCreateTable().Model((*person)(nil)).Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.NewInsert().Model(&[]person{
+			{Id: "person-1", GroupId: "group-1", Name: "Alice"},
+			{Id: "person-2", GroupId: "group-1", Name: "Bob"},
+			{Id: "person-3", GroupId: "group-2", Name: "Carol"},
+		}).Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run("Update returning columns "+name, func(t *testing.T) {
+			p := person{Id: "person-3", Name: "Caroline"}
+			if err := util.UpdateReturning(ctx, db, db.NewUpdate().Model(&p).Column("name"), util.WherePK, "group_id"); err != nil {
+				t.Fatal(err)
+			}
+			if p.GroupId != "group-2" {
+				t.Errorf("expected the group ID of the updated person but got %q", p.GroupId)
+			}
+		})
+
+		t.Run("Update returning columns of missing row "+name, func(t *testing.T) {
+			p := person{Id: "person-4", Name: "Dave"}
+			if err := util.UpdateReturning(ctx, db, db.NewUpdate().Model(&p).Column("name"), util.WherePK, "group_id"); !eris.Is(err, sql.ErrNoRows) {
+				t.Errorf("expected no rows but got %+v", err)
+			}
+		})
+
+		t.Run("Delete returning columns "+name, func(t *testing.T) {
+			var people []*person
+			if err := util.DeleteReturning(ctx, db, &people, func(q bun.QueryBuilder) bun.QueryBuilder {
+				return q.Where("group_id = ?", "group-1")
+			}, "id"); err != nil {
+				t.Fatal(err)
+			}
+			ids := make([]string, len(people))
+			for i, p := range people {
+				ids[i] = p.Id
+			}
+			sort.Strings(ids)
+			if len(ids) != 2 || ids[0] != "person-1" || ids[1] != "person-2" {
+				t.Errorf("expected the IDs of the deleted people but got %v", ids)
+			}
+			if count, err := db.NewSelect().Model((*person)(nil)).Count(ctx); err != nil {
+				t.Fatal(err)
+			} else if count != 1 {
+				t.Errorf("expected 1 remaining person but got %d", count)
+			}
+		})
+
+		t.Run("Delete returning columns of missing row "+name, func(t *testing.T) {
+			p := person{Id: "person-1"}
+			if err := util.DeleteReturning(ctx, db, &p, util.WherePK, "group_id"); !eris.Is(err, sql.ErrNoRows) {
+				t.Errorf("expected no rows but got %+v", err)
+			}
+		})
+	}
+}
+
+func TestUpdateMatchedReturning(t *testing.T) {
+	for name, dialect := range map[string]schema.Dialect{
+		"with RETURNING":    sqlitedialect.New(),
+		"without RETURNING": noReturningDialect{sqlitedialect.New()},
+	} {
+		db := openSQLite(t, dialect)
+		ctx := context.Background()
+		if _, err := db.NewCreateTable().Model((*deletablePerson)(nil)).Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.NewInsert().Model(&[]deletablePerson{
+			{Id: "person-1", GroupId: "group-1"},
+			{Id: "person-2", GroupId: "group-1"},
+			{Id: "person-3", GroupId: "group-2"},
+		}).Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run("Soft delete returning columns "+name, func(t *testing.T) {
+			var people []*deletablePerson
+			if err := util.UpdateMatchedReturning(ctx, db, db.NewUpdate().Model(&people).Set("delete_time = ?", time.Now()), func(q bun.QueryBuilder) bun.QueryBuilder {
+				return q.Where("group_id = ?", "group-1")
+			}, "id"); err != nil {
+				t.Fatal(err)
+			}
+			if len(people) != 2 {
+				t.Errorf("expected the 2 people of the group to be soft deleted but got %d", len(people))
+			}
+			if count, err := db.NewSelect().Model((*deletablePerson)(nil)).Count(ctx); err != nil {
+				t.Fatal(err)
+			} else if count != 1 {
+				t.Errorf("expected 1 remaining person but got %d", count)
+			}
+		})
+
+		t.Run("Restore returning columns "+name, func(t *testing.T) {
+			p := deletablePerson{Id: "person-1"}
+			if err := util.UpdateMatchedReturning(ctx, db, db.NewUpdate().Model(&p).Set("delete_time = NULL"), func(q bun.QueryBuilder) bun.QueryBuilder {
+				return q.WherePK().WhereDeleted()
+			}, "group_id"); err != nil {
+				t.Fatal(err)
+			}
+			if p.GroupId != "group-1" {
+				t.Errorf("expected the group ID of the restored person but got %q", p.GroupId)
+			}
+		})
+
+		t.Run("Restore returning columns of row that is not deleted "+name, func(t *testing.T) {
+			p := deletablePerson{Id: "person-3"}
+			if err := util.UpdateMatchedReturning(ctx, db, db.NewUpdate().Model(&p).Set("delete_time = NULL"), func(q bun.QueryBuilder) bun.QueryBuilder {
+				return q.WherePK().WhereDeleted()
+			}, "group_id"); !eris.Is(err, sql.ErrNoRows) {
+				t.Errorf("expected no rows but got %+v", err)
+			}
+		})
+	}
+}
+
+func openSQLite(t *testing.T, dialect schema.Dialect) *bun.DB {
+	sqlDb, err := sql.Open("sqlite", "file::memory:")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening an in-memory database", err)
+	}
+	sqlDb.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		sqlDb.Close()
+	})
+	return bun.NewDB(sqlDb, dialect)
+}
@@ -0,0 +1,47 @@
+package diff
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/rotisserie/eris"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Fields returns the fields of the message as they are represented in JSON, using the field names of the proto definition
+// and including unpopulated fields so that clearing a field shows up as change, without the passed fields
+func Fields(message proto.Message, omit ...string) (map[string]interface{}, error) {
+	data, err := protojson.MarshalOptions{
+		UseProtoNames:   true,
+		EmitUnpopulated: true,
+	}.Marshal(message)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed marshalling message")
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, eris.Wrap(err, "failed unmarshalling fields")
+	}
+	for _, field := range omit {
+		delete(fields, field)
+	}
+	return fields, nil
+}
+
+// Changes returns the fields whose values differ between the passed states with their values in the respective state
+func Changes(before, after map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	changedBefore := make(map[string]interface{})
+	changedAfter := make(map[string]interface{})
+	for field, value := range before {
+		if other, ok := after[field]; !ok || !reflect.DeepEqual(value, other) {
+			changedBefore[field] = value
+		}
+	}
+	for field, value := range after {
+		if other, ok := before[field]; !ok || !reflect.DeepEqual(value, other) {
+			changedAfter[field] = value
+		}
+	}
+	return changedBefore, changedAfter
+}
@@ -0,0 +1,68 @@
+package diff_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/diff"
+	"google.golang.org/protobuf/types/known/apipb"
+)
+
+func TestFields(t *testing.T) {
+	fields, err := diff.Fields(&apipb.Method{
+		Name:             "GetExpense",
+		RequestStreaming: true,
+	}, "options", "syntax")
+	if err != nil {
+		t.Fatalf("failed getting fields: %v", err)
+	}
+	expected := map[string]interface{}{
+		"name":               "GetExpense",
+		"request_type_url":   "",
+		"request_streaming":  true,
+		"response_type_url":  "",
+		"response_streaming": false,
+	}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected fields %v but got %v", expected, fields)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	for name, params := range map[string]struct {
+		before         map[string]interface{}
+		after          map[string]interface{}
+		expectedBefore map[string]interface{}
+		expectedAfter  map[string]interface{}
+	}{
+		"No changes": {
+			map[string]interface{}{"name": "a", "values": []interface{}{"x"}},
+			map[string]interface{}{"name": "a", "values": []interface{}{"x"}},
+			map[string]interface{}{},
+			map[string]interface{}{},
+		},
+		"Changed field": {
+			map[string]interface{}{"name": "a", "value": 1.0},
+			map[string]interface{}{"name": "b", "value": 1.0},
+			map[string]interface{}{"name": "a"},
+			map[string]interface{}{"name": "b"},
+		},
+		"Added and removed fields": {
+			map[string]interface{}{"name": "a"},
+			map[string]interface{}{"value": 1.0},
+			map[string]interface{}{"name": "a"},
+			map[string]interface{}{"value": 1.0},
+		},
+	} {
+		params := params
+		t.Run(name, func(t *testing.T) {
+			before, after := diff.Changes(params.before, params.after)
+			if !reflect.DeepEqual(before, params.expectedBefore) {
+				t.Errorf("expected changed fields before %v but got %v", params.expectedBefore, before)
+			}
+			if !reflect.DeepEqual(after, params.expectedAfter) {
+				t.Errorf("expected changed fields after %v but got %v", params.expectedAfter, after)
+			}
+		})
+	}
+}
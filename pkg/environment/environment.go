@@ -4,10 +4,16 @@ import (
 	"context"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
 )
 
+// LookupString returns the value of an optional environment variable or an empty string if it is not set
+func LookupString(key string) string {
+	return os.Getenv(key)
+}
+
 func MustLookupString(ctx context.Context, key string) string {
 	val, exists := os.LookupEnv(key)
 	if !exists {
@@ -27,3 +33,27 @@ func MustLookupUint16(ctx context.Context, key string) uint16 {
 	}
 	return uint16(parsedVal)
 }
+
+func MustLookupDuration(ctx context.Context, key string) time.Duration {
+	val, exists := os.LookupEnv(key)
+	if !exists {
+		logging.FromContext(ctx).Panic("failed looking up required environment variable", logging.String("envKey", key))
+	}
+	parsedVal, err := time.ParseDuration(val)
+	if err != nil {
+		logging.FromContext(ctx).Panic("failed parsing looked up environment variable as duration", logging.String("envKey", key), logging.String("envValue", val))
+	}
+	return parsedVal
+}
+
+func MustLookupInt64(ctx context.Context, key string) int64 {
+	val, exists := os.LookupEnv(key)
+	if !exists {
+		logging.FromContext(ctx).Panic("failed looking up required environment variable", logging.String("envKey", key))
+	}
+	parsedVal, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		logging.FromContext(ctx).Panic("failed parsing looked up environment variable as int64", logging.String("envKey", key), logging.String("envValue", val))
+	}
+	return parsedVal
+}
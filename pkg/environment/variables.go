@@ -3,6 +3,7 @@ package environment
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // GetGroupServerPort returns the port the group service will run on
@@ -34,6 +35,61 @@ func GetExpensecategoryrelationServerPort(ctx context.Context) uint16 {
 	return MustLookupUint16(ctx, "EXPENSECATEGORYRELATION_SERVER_PORT")
 }
 
+// GetActivityServerPort returns the port the activity service will run on
+func GetActivityServerPort(ctx context.Context) uint16 {
+	return MustLookupUint16(ctx, "ACTIVITY_SERVER_PORT")
+}
+
+// GetRecurringexpenseServerPort returns the port the recurring expense service will run on
+func GetRecurringexpenseServerPort(ctx context.Context) uint16 {
+	return MustLookupUint16(ctx, "RECURRINGEXPENSE_SERVER_PORT")
+}
+
+// GetAttachmentServerPort returns the port the attachment service will run on
+func GetAttachmentServerPort(ctx context.Context) uint16 {
+	return MustLookupUint16(ctx, "ATTACHMENT_SERVER_PORT")
+}
+
+// GetCommentServerPort returns the port the comment service will run on
+func GetCommentServerPort(ctx context.Context) uint16 {
+	return MustLookupUint16(ctx, "COMMENT_SERVER_PORT")
+}
+
+// GetNotificationServerPort returns the port the notification service will run on
+func GetNotificationServerPort(ctx context.Context) uint16 {
+	return MustLookupUint16(ctx, "NOTIFICATION_SERVER_PORT")
+}
+
+// GetWebhookServerPort returns the port the webhook service will run on
+func GetWebhookServerPort(ctx context.Context) uint16 {
+	return MustLookupUint16(ctx, "WEBHOOK_SERVER_PORT")
+}
+
+// GetDebtReminderServerPort returns the port the debt reminder service will run on
+func GetDebtReminderServerPort(ctx context.Context) uint16 {
+	return MustLookupUint16(ctx, "DEBTREMINDER_SERVER_PORT")
+}
+
+// GetBudgetServerPort returns the port the budget service will run on
+func GetBudgetServerPort(ctx context.Context) uint16 {
+	return MustLookupUint16(ctx, "BUDGET_SERVER_PORT")
+}
+
+// GetReportServerPort returns the port the report service will run on
+func GetReportServerPort(ctx context.Context) uint16 {
+	return MustLookupUint16(ctx, "REPORT_SERVER_PORT")
+}
+
+// GetExportServerPort returns the port the export service will run on
+func GetExportServerPort(ctx context.Context) uint16 {
+	return MustLookupUint16(ctx, "EXPORT_SERVER_PORT")
+}
+
+// GetImporterServerPort returns the port the importer service will run on
+func GetImporterServerPort(ctx context.Context) uint16 {
+	return MustLookupUint16(ctx, "IMPORTER_SERVER_PORT")
+}
+
 // GetCurrencyServerPort returns the port the expense service will run on
 func GetCurrencyServerPort(ctx context.Context) uint16 {
 	return MustLookupUint16(ctx, "CURRENCY_SERVER_PORT")
@@ -59,11 +115,106 @@ func GetDbName(ctx context.Context) string {
 	return MustLookupString(ctx, "DB_NAME")
 }
 
+// GetDbBackend returns the optional database backend (postgres or sqlite) which defaults to postgres
+func GetDbBackend() string {
+	return LookupString("DB_BACKEND")
+}
+
+// GetDbSQLiteDSN returns the data source name of the embedded SQLite database, e.g. file:expense_splitter.db
+func GetDbSQLiteDSN(ctx context.Context) string {
+	return MustLookupString(ctx, "DB_SQLITE_DSN")
+}
+
+// GetDbReadEndpoints returns the optional comma separated list of <endpoint>=<primary|follower|replica> pairs telling which database read-only endpoints read from
+func GetDbReadEndpoints() string {
+	return LookupString("DB_READ_ENDPOINTS")
+}
+
+// GetDbReadReplicaDSN returns the optional DSN of the read replica endpoints may read from
+func GetDbReadReplicaDSN() string {
+	return LookupString("DB_READ_REPLICA_DSN")
+}
+
+// GetBlobBackend returns the optional blob store backend (filesystem, s3 or memory) which defaults to filesystem
+func GetBlobBackend() string {
+	return LookupString("BLOB_BACKEND")
+}
+
+// GetBlobFilesystemDir returns the directory the filesystem blob store stores blobs in
+func GetBlobFilesystemDir(ctx context.Context) string {
+	return MustLookupString(ctx, "BLOB_FILESYSTEM_DIR")
+}
+
+// GetBlobS3Endpoint returns the URL of the S3 compatible object storage blobs are stored in, e.g. http://minio:9000
+func GetBlobS3Endpoint(ctx context.Context) string {
+	return MustLookupString(ctx, "BLOB_S3_ENDPOINT")
+}
+
+// GetBlobS3Region returns the optional region of the S3 compatible object storage which defaults to us-east-1
+func GetBlobS3Region() string {
+	return LookupString("BLOB_S3_REGION")
+}
+
+// GetBlobS3Bucket returns the bucket of the S3 compatible object storage blobs are stored in
+func GetBlobS3Bucket(ctx context.Context) string {
+	return MustLookupString(ctx, "BLOB_S3_BUCKET")
+}
+
+// GetBlobS3AccessKeyId returns the ID of the access key used to authenticate with the S3 compatible object storage
+func GetBlobS3AccessKeyId(ctx context.Context) string {
+	return MustLookupString(ctx, "BLOB_S3_ACCESS_KEY_ID")
+}
+
+// GetBlobS3SecretAccessKey returns the secret of the access key used to authenticate with the S3 compatible object storage
+func GetBlobS3SecretAccessKey(ctx context.Context) string {
+	return MustLookupString(ctx, "BLOB_S3_SECRET_ACCESS_KEY")
+}
+
+// GetAttachmentMaxSize returns the maximum size of the content of an attachment in bytes
+func GetAttachmentMaxSize(ctx context.Context) int64 {
+	return MustLookupInt64(ctx, "ATTACHMENT_MAX_SIZE")
+}
+
+// GetSMTPHost returns the host of the SMTP server notifications are sent through
+func GetSMTPHost(ctx context.Context) string {
+	return MustLookupString(ctx, "SMTP_HOST")
+}
+
+// GetSMTPPort returns the port of the SMTP server notifications are sent through, e.g. 587
+func GetSMTPPort(ctx context.Context) uint16 {
+	return MustLookupUint16(ctx, "SMTP_PORT")
+}
+
+// GetSMTPUsername returns the optional user authenticating with the SMTP server; no authentication takes place if it is empty
+func GetSMTPUsername() string {
+	return LookupString("SMTP_USERNAME")
+}
+
+// GetSMTPPassword returns the optional password authenticating with the SMTP server
+func GetSMTPPassword() string {
+	return LookupString("SMTP_PASSWORD")
+}
+
+// GetSMTPFrom returns the address notifications are sent from, e.g. Expense Splitter <noreply@example.com>
+func GetSMTPFrom(ctx context.Context) string {
+	return MustLookupString(ctx, "SMTP_FROM")
+}
+
 // GetServerPort returns the port the service will run on
 func GetReflectionServerPort(ctx context.Context) uint16 {
 	return MustLookupUint16(ctx, "REFLECTION_SERVER_PORT")
 }
 
+// GetAllInOneServerPort returns the port the all-in-one binary serves all services on
+func GetAllInOneServerPort(ctx context.Context) uint16 {
+	return MustLookupUint16(ctx, "ALL_IN_ONE_SERVER_PORT")
+}
+
+// GetAllInOneNatsStoreDir returns the optional directory the embedded NATS server of the all-in-one binary stores its JetStream data in
+func GetAllInOneNatsStoreDir() string {
+	return LookupString("ALL_IN_ONE_NATS_STORE_DIR")
+}
+
 // GetGlobalDomain returns the infrastructure's global domain which can be used for various purposes like error details
 func GetGlobalDomain(ctx context.Context) string {
 	return MustLookupString(ctx, "GLOBAL_DOMAIN")
@@ -89,6 +240,16 @@ func GetDBUpdateErrorReason(ctx context.Context) string {
 	return MustLookupString(ctx, "DB_UPDATE_ERROR_REASON")
 }
 
+// GetBlobStoreErrorReason returns the error reason that an interaction with the blob store failed in UPPER_SNAKE_CASE
+func GetBlobStoreErrorReason(ctx context.Context) string {
+	return MustLookupString(ctx, "BLOB_STORE_ERROR_REASON")
+}
+
+// GetEtagMismatchErrorReason returns the error reason that the etag passed to a request does not match the current etag of the resource in UPPER_SNAKE_CASE
+func GetEtagMismatchErrorReason(ctx context.Context) string {
+	return MustLookupString(ctx, "ETAG_MISMATCH_ERROR_REASON")
+}
+
 // GetMessagePublicationErrorReason returns the error reason that a message could not be published in UPPER_SNAKE_CASE
 func GetMessagePublicationErrorReason(ctx context.Context) string {
 	return MustLookupString(ctx, "MESSAGE_PUBLICATION_ERROR_REASON")
@@ -109,6 +270,11 @@ func GetSendStreamAliveErrorReason(ctx context.Context) string {
 	return MustLookupString(ctx, "SEND_STREAM_ALIVE_ERROR_REASON")
 }
 
+// GetTombstoneRetention returns how long deleted resources are kept in the trash before they are purged, e.g. 720h
+func GetTombstoneRetention(ctx context.Context) time.Duration {
+	return MustLookupDuration(ctx, "TOMBSTONE_RETENTION")
+}
+
 // GetNatsServerHost returns the host address of the NATS server
 func GetNatsServerHost(ctx context.Context) string {
 	return MustLookupString(ctx, "NATS_SERVER_HOST")
@@ -145,6 +311,12 @@ func GetGroupDeletedSubject(groupId string) string {
 	return fmt.Sprintf("%s.deleted", GetGroupSubject(groupId))
 }
 
+// TODO: as env variable with %s parameter
+// GetGroupUndeletedSubject returns the name of the subject events are published on when a group was restored from the trash
+func GetGroupUndeletedSubject(groupId string) string {
+	return fmt.Sprintf("%s.undeleted", GetGroupSubject(groupId))
+}
+
 // TODO: as env variable with %s parameter
 // GetGroupUpdatedSubject returns the name of the subject events are published on when a group was updated
 func GetGroupUpdatedSubject(groupId string) string {
@@ -179,6 +351,12 @@ func GetPersonDeletedSubject(groupId string, personId string) string {
 	return fmt.Sprintf("%s.deleted", GetPersonSubject(groupId, personId))
 }
 
+// TODO: as env variable with %s parameter
+// GetPersonUndeletedSubject returns the name of the subject events are published on when a person was restored from the trash
+func GetPersonUndeletedSubject(groupId string, personId string) string {
+	return fmt.Sprintf("%s.undeleted", GetPersonSubject(groupId, personId))
+}
+
 // TODO: as env variable with %s parameter
 // GetPersonUpdatedSubject returns the name of the subject events are published on when a person was updated
 func GetPersonUpdatedSubject(groupId string, personId string) string {
@@ -213,6 +391,12 @@ func GetCategoryDeletedSubject(groupId string, categoryId string) string {
 	return fmt.Sprintf("%s.deleted", GetCategorySubject(groupId, categoryId))
 }
 
+// TODO: as env variable with %s parameter
+// GetCategoryUndeletedSubject returns the name of the subject events are published on when a category was restored from the trash
+func GetCategoryUndeletedSubject(groupId string, categoryId string) string {
+	return fmt.Sprintf("%s.undeleted", GetCategorySubject(groupId, categoryId))
+}
+
 // TODO: as env variable with %s parameter
 // GetCategoryUpdatedSubject returns the name of the subject events are published on when a category was updated
 func GetCategoryUpdatedSubject(groupId string, categoryId string) string {
@@ -247,6 +431,12 @@ func GetExpenseDeletedSubject(groupId string, expenseId string) string {
 	return fmt.Sprintf("%s.deleted", GetExpenseSubject(groupId, expenseId))
 }
 
+// TODO: as env variable with %s parameter
+// GetExpenseUndeletedSubject returns the name of the subject events are published on when a expense was restored from the trash
+func GetExpenseUndeletedSubject(groupId string, expenseId string) string {
+	return fmt.Sprintf("%s.undeleted", GetExpenseSubject(groupId, expenseId))
+}
+
 // TODO: as env variable with %s parameter
 // GetExpenseUpdatedSubject returns the name of the subject events are published on when a expense was updated
 func GetExpenseUpdatedSubject(groupId string, expenseId string) string {
@@ -281,6 +471,12 @@ func GetExpenseStakeDeletedSubject(groupId string, expenseId string, stakeId str
 	return fmt.Sprintf("%s.deleted", GetExpenseStakeSubject(groupId, expenseId, stakeId))
 }
 
+// TODO: as env variable with %s parameter
+// GetExpenseStakeUndeletedSubject returns the name of the subject events are published on when a expense stake was restored from the trash
+func GetExpenseStakeUndeletedSubject(groupId string, expenseId string, stakeId string) string {
+	return fmt.Sprintf("%s.undeleted", GetExpenseStakeSubject(groupId, expenseId, stakeId))
+}
+
 // TODO: as env variable with %s parameter
 // GetExpenseStakeUpdatedSubject returns the name of the subject events are published on when a expense stake was updated
 func GetExpenseStakeUpdatedSubject(groupId string, expenseId string, stakeId string) string {
@@ -337,6 +533,11 @@ func GetCurrencySourceStreamName() string {
 	return "EXPENSESPLITTER_CURRENCY"
 }
 
+// GetLeaderElectionBucketName returns the name of the key-value bucket leadership leases are stored in
+func GetLeaderElectionBucketName() string {
+	return "EXPENSESPLITTER_LEADER_ELECTION"
+}
+
 // TODO: as env variable with %s parameter
 // GetExpenseCategoryRelationCreatedSubject returns the name of the subject events are published on when a expense stake was created
 func GetExpenseCategoryRelationCreatedSubject(groupId string, expenseId string, categoryId string) string {
@@ -349,6 +550,12 @@ func GetExpenseCategoryRelationDeletedSubject(groupId string, expenseId string,
 	return fmt.Sprintf("%s.deleted", GetExpenseCategoryRelationSubject(groupId, expenseId, categoryId))
 }
 
+// TODO: as env variable with %s parameter
+// GetExpenseCategoryRelationUndeletedSubject returns the name of the subject events are published on when an expense category relation was restored from the trash
+func GetExpenseCategoryRelationUndeletedSubject(groupId string, expenseId string, categoryId string) string {
+	return fmt.Sprintf("%s.undeleted", GetExpenseCategoryRelationSubject(groupId, expenseId, categoryId))
+}
+
 // TODO: as env variable with %s parameter
 // GetExpenseCategoryRelationSubject returns the name of the subject events of a single expense stake are published on
 func GetExpenseCategoryRelationSubject(groupId string, expenseId string, categoryId string) string {
@@ -365,7 +572,198 @@ func GetExpenseCategoryRelationSourceStreamName() string {
 	return "EXPENSESPLITTER_EXPENSECATEGORYRELATION"
 }
 
+// GetActivityRecordedSubject returns the name of the subject events are published on when an activity was recorded
+func GetActivityRecordedSubject(groupId string, activityId string) string {
+	return fmt.Sprintf("%s.recorded", GetActivitySubject(groupId, activityId))
+}
+
+// GetActivitySubject returns the name of the subject events of a single activity are published on
+func GetActivitySubject(groupId string, activityId string) string {
+	return fmt.Sprintf("%s.%s", GetActivitiesSubject(groupId), activityId)
+}
+
+// GetActivitiesSubject returns the name of the subject events of all activities of a group are published on
+func GetActivitiesSubject(groupId string) string {
+	return fmt.Sprintf("%s.activity", GetGroupSubject(groupId))
+}
+
+func GetActivitySourceStreamName() string {
+	return "EXPENSESPLITTER_ACTIVITY"
+}
+
+// TODO: as env variable with %s parameter
+// GetRecurringExpenseCreatedSubject returns the name of the subject events are published on when a recurring expense was created
+func GetRecurringExpenseCreatedSubject(groupId string, recurringExpenseId string) string {
+	return fmt.Sprintf("%s.created", GetRecurringExpenseSubject(groupId, recurringExpenseId))
+}
+
+// TODO: as env variable with %s parameter
+// GetRecurringExpenseDeletedSubject returns the name of the subject events are published on when a recurring expense was deleted
+func GetRecurringExpenseDeletedSubject(groupId string, recurringExpenseId string) string {
+	return fmt.Sprintf("%s.deleted", GetRecurringExpenseSubject(groupId, recurringExpenseId))
+}
+
+// TODO: as env variable with %s parameter
+// GetRecurringExpenseUpdatedSubject returns the name of the subject events are published on when a recurring expense was updated
+func GetRecurringExpenseUpdatedSubject(groupId string, recurringExpenseId string) string {
+	return fmt.Sprintf("%s.updated", GetRecurringExpenseSubject(groupId, recurringExpenseId))
+}
+
+// TODO: as env variable with %s parameter
+// GetRecurringExpenseSubject returns the name of the subject events of a single recurring expense are published on
+func GetRecurringExpenseSubject(groupId string, recurringExpenseId string) string {
+	return fmt.Sprintf("%s.%s", GetRecurringExpensesSubject(groupId), recurringExpenseId)
+}
+
 // TODO: as env variable
+// GetRecurringExpensesSubject returns the name of the subject events of all recurring expenses are published on
+func GetRecurringExpensesSubject(groupId string) string {
+	return fmt.Sprintf("%s.recurringexpense", GetGroupSubject(groupId))
+}
+
+func GetRecurringExpenseSourceStreamName() string {
+	return "EXPENSESPLITTER_RECURRINGEXPENSE"
+}
+
+// TODO: as env variable with %s parameter
+// GetAttachmentCreatedSubject returns the name of the subject events are published on when an attachment was created
+func GetAttachmentCreatedSubject(groupId string, expenseId string, attachmentId string) string {
+	return fmt.Sprintf("%s.created", GetAttachmentSubject(groupId, expenseId, attachmentId))
+}
+
+// TODO: as env variable with %s parameter
+// GetAttachmentDeletedSubject returns the name of the subject events are published on when an attachment was deleted
+func GetAttachmentDeletedSubject(groupId string, expenseId string, attachmentId string) string {
+	return fmt.Sprintf("%s.deleted", GetAttachmentSubject(groupId, expenseId, attachmentId))
+}
+
+// TODO: as env variable with %s parameter
+// GetAttachmentUndeletedSubject returns the name of the subject events are published on when an attachment was restored from the trash
+func GetAttachmentUndeletedSubject(groupId string, expenseId string, attachmentId string) string {
+	return fmt.Sprintf("%s.undeleted", GetAttachmentSubject(groupId, expenseId, attachmentId))
+}
+
+// TODO: as env variable with %s parameter
+// GetAttachmentSubject returns the name of the subject events of a single attachment are published on
+func GetAttachmentSubject(groupId string, expenseId string, attachmentId string) string {
+	return fmt.Sprintf("%s.%s", GetAttachmentsSubject(groupId, expenseId), attachmentId)
+}
+
+// TODO: as env variable
+// GetAttachmentsSubject returns the name of the subject events of all attachments of an expense are published on
+func GetAttachmentsSubject(groupId string, expenseId string) string {
+	return fmt.Sprintf("%s.attachment", GetExpenseSubject(groupId, expenseId))
+}
+
+func GetAttachmentSourceStreamName() string {
+	return "EXPENSESPLITTER_ATTACHMENT"
+}
+
+// TODO: as env variable with %s parameter
+// GetCommentCreatedSubject returns the name of the subject events are published on when a comment was created
+func GetCommentCreatedSubject(groupId string, expenseId string, commentId string) string {
+	return fmt.Sprintf("%s.created", GetCommentSubject(groupId, expenseId, commentId))
+}
+
+// TODO: as env variable with %s parameter
+// GetCommentUpdatedSubject returns the name of the subject events are published on when a comment was edited
+func GetCommentUpdatedSubject(groupId string, expenseId string, commentId string) string {
+	return fmt.Sprintf("%s.updated", GetCommentSubject(groupId, expenseId, commentId))
+}
+
+// TODO: as env variable with %s parameter
+// GetCommentDeletedSubject returns the name of the subject events are published on when a comment was deleted
+func GetCommentDeletedSubject(groupId string, expenseId string, commentId string) string {
+	return fmt.Sprintf("%s.deleted", GetCommentSubject(groupId, expenseId, commentId))
+}
+
+// TODO: as env variable with %s parameter
+// GetCommentUndeletedSubject returns the name of the subject events are published on when a comment was restored from the trash
+func GetCommentUndeletedSubject(groupId string, expenseId string, commentId string) string {
+	return fmt.Sprintf("%s.undeleted", GetCommentSubject(groupId, expenseId, commentId))
+}
+
+// TODO: as env variable with %s parameter
+// GetCommentSubject returns the name of the subject events of a single comment are published on
+func GetCommentSubject(groupId string, expenseId string, commentId string) string {
+	return fmt.Sprintf("%s.%s", GetCommentsSubject(groupId, expenseId), commentId)
+}
+
+// TODO: as env variable
+// GetCommentsSubject returns the name of the subject events of all comments of an expense are published on
+func GetCommentsSubject(groupId string, expenseId string) string {
+	return fmt.Sprintf("%s.comment", GetExpenseSubject(groupId, expenseId))
+}
+
+func GetCommentSourceStreamName() string {
+	return "EXPENSESPLITTER_COMMENT"
+}
+
+// TODO: as env variable with %s parameter
+// GetDebtReminderDueSubject returns the name of the subject events are published on when a person is due to be reminded of a debt
+func GetDebtReminderDueSubject(groupId string, personId string) string {
+	return fmt.Sprintf("%s.due", GetDebtReminderSubject(groupId, personId))
+}
+
+// TODO: as env variable
+// GetDebtReminderSubject returns the name of the subject events about the debt reminders of a single person are published on
+func GetDebtReminderSubject(groupId string, personId string) string {
+	return fmt.Sprintf("%s.%s", GetDebtRemindersSubject(groupId), personId)
+}
+
+// TODO: as env variable
+// GetDebtRemindersSubject returns the name of the subject events about the debt reminders of a group are published on
+func GetDebtRemindersSubject(groupId string) string {
+	return fmt.Sprintf("%s.debtreminder", GetGroupSubject(groupId))
+}
+
+func GetDebtReminderSourceStreamName() string {
+	return "EXPENSESPLITTER_DEBTREMINDER"
+}
+
+// TODO: as env variable with %s parameter
+// GetBudgetThresholdReachedSubject returns the name of the subject events are published on when the spending reached a threshold of a budget
+func GetBudgetThresholdReachedSubject(groupId string, budgetId string) string {
+	return fmt.Sprintf("%s.thresholdreached", GetBudgetSubject(groupId, budgetId))
+}
+
+// TODO: as env variable
+// GetBudgetSubject returns the name of the subject events of a single budget are published on
+func GetBudgetSubject(groupId string, budgetId string) string {
+	return fmt.Sprintf("%s.%s", GetBudgetsSubject(groupId), budgetId)
+}
+
+// TODO: as env variable
+// GetBudgetsSubject returns the name of the subject events of all budgets of a group are published on
+func GetBudgetsSubject(groupId string) string {
+	return fmt.Sprintf("%s.budget", GetGroupSubject(groupId))
+}
+
+func GetBudgetSourceStreamName() string {
+	return "EXPENSESPLITTER_BUDGET"
+}
+
+// TODO: as env variable
+// GetPrincipalHeaderKey returns the header key the authenticating proxy in front of the services passes the principal of a request in
+func GetPrincipalHeaderKey() string {
+	return "x-forwarded-user"
+}
+
+// GetEtagHeaderKey returns the header key clients may pass the etag of a resource in to make an update or delete conditional on it
+func GetEtagHeaderKey() string {
+	return "if-match"
+}
+
+// GetEventPrincipalHeaderKey returns the header key events carry the principal that caused them in
+func GetEventPrincipalHeaderKey() string {
+	return "Expensesplitter-Principal"
+}
+
+// GetEventResourceHeaderKey returns the header key events carry the state of the resource they are about after the change in
+func GetEventResourceHeaderKey() string {
+	return "Expensesplitter-Resource"
+}
+
 // GetHttpStatusCodeKey returns the header key used internally to modify the http status code as suggested here: https://grpc-ecosystem.github.io/grpc-gateway/docs/mapping/customizing_your_gateway/
 func GetHttpStatusCodeKey() string {
 	return "x-http-code"
@@ -0,0 +1,308 @@
+// Package export writes the expenses of a group into files readable by spreadsheets and plain-text accounting tools
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Group is a group along with everything exported of it; all amounts are minor units, e.g. cents
+type Group struct {
+	Name string
+	// Currency is the acronym of the currency of the group
+	Currency    string
+	Persons     []Person
+	Categories  []Category
+	Expenses    []Expense
+	Settlements []Settlement
+}
+
+type Person struct {
+	Id   string
+	Name string
+}
+
+type Category struct {
+	Id   string
+	Name string
+}
+
+type Expense struct {
+	Id   string
+	Name string
+	Time time.Time
+	// ById is the person who paid the expense
+	ById string
+	// Currency is the acronym of the currency of the expense
+	Currency    string
+	CategoryIds []string
+	Stakes      []Stake
+}
+
+type Stake struct {
+	ForId  string
+	Amount int64
+}
+
+// Settlement is a transfer settling the balances of the persons in the currency of the group
+type Settlement struct {
+	FromId string
+	ToId   string
+	Amount int64
+}
+
+// WriteCSV writes a header and one row per stake ordered by the time of the expenses
+func WriteCSV(w io.Writer, group *Group) error {
+	persons := personNames(group.Persons)
+	categories := categoryNames(group.Categories)
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"expense_id", "expense_name", "time", "currency", "paid_by", "paid_for", "amount", "categories"}); err != nil {
+		return err
+	}
+	for _, expense := range sortedExpenses(group.Expenses) {
+		for _, stake := range expense.Stakes {
+			if err := cw.Write([]string{
+				expense.Id,
+				expense.Name,
+				expense.Time.UTC().Format(time.RFC3339),
+				expense.Currency,
+				persons[expense.ById],
+				persons[stake.ForId],
+				formatAmount(stake.Amount),
+				strings.Join(expenseCategories(expense, categories), ";"),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteHledger writes an hledger journal. Every expense is a transaction crediting the account of its payer with the sum of its stakes
+// and debiting the account of each person with their stake, so that the balance of an account is what the rest of the group owes the person.
+func WriteHledger(w io.Writer, group *Group) error {
+	accounts := personAccounts(group.Persons, "assets:persons:")
+	categories := categoryNames(group.Categories)
+	if _, err := fmt.Fprintf(w, "; %s\n", singleLine(group.Name)); err != nil {
+		return err
+	}
+	for _, expense := range sortedExpenses(group.Expenses) {
+		if len(expense.Stakes) == 0 {
+			continue
+		}
+		tags := []string{"expense:" + expense.Id}
+		for _, category := range expenseCategories(expense, categories) {
+			tags = append(tags, "category:"+strings.ReplaceAll(category, ",", " "))
+		}
+		if _, err := fmt.Fprintf(w, "\n%s %s  ; %s\n", expense.Time.UTC().Format("2006-01-02"), singleLine(expenseName(expense)), strings.Join(tags, ", ")); err != nil {
+			return err
+		}
+		if err := writePostings(w, expense, accounts, expense.Currency); err != nil {
+			return err
+		}
+	}
+	return writeSettlements(w, group, accounts, group.Currency)
+}
+
+// WriteBeancount writes a beancount ledger. Every expense is a transaction crediting the account of its payer with the sum of its stakes
+// and debiting the account of each person with their stake, so that the balance of an account is what the rest of the group owes the person.
+func WriteBeancount(w io.Writer, group *Group) error {
+	accounts := personAccounts(group.Persons, "Assets:Persons:")
+	categories := categoryNames(group.Categories)
+	expenses := sortedExpenses(group.Expenses)
+	if _, err := fmt.Fprintf(w, "option \"title\" %s\noption \"operating_currency\" %q\n", quote(group.Name), commodity(group.Currency)); err != nil {
+		return err
+	}
+	if len(expenses) > 0 {
+		// the accounts are opened on the day of the first expense since a transaction must not use an account before its opening
+		opening := expenses[0].Time.UTC().Format("2006-01-02")
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+		for _, person := range group.Persons {
+			if _, err := fmt.Fprintf(w, "%s open %s\n", opening, accounts[person.Id]); err != nil {
+				return err
+			}
+		}
+	}
+	for _, expense := range expenses {
+		if len(expense.Stakes) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "\n%s * %s\n  expense: %s\n", expense.Time.UTC().Format("2006-01-02"), quote(expenseName(expense)), quote(expense.Id)); err != nil {
+			return err
+		}
+		if expenseCategories := expenseCategories(expense, categories); len(expenseCategories) > 0 {
+			if _, err := fmt.Fprintf(w, "  categories: %s\n", quote(strings.Join(expenseCategories, ", "))); err != nil {
+				return err
+			}
+		}
+		if err := writePostings(w, expense, accounts, commodity(expense.Currency)); err != nil {
+			return err
+		}
+	}
+	return writeSettlements(w, group, accounts, commodity(group.Currency))
+}
+
+// writePostings writes the posting crediting the payer of the expense followed by the postings debiting the persons with their stakes
+func writePostings(w io.Writer, expense Expense, accounts map[string]string, commodity string) error {
+	var total int64
+	for _, stake := range expense.Stakes {
+		total += stake.Amount
+	}
+	if _, err := fmt.Fprintf(w, "    %s  %s %s\n", accounts[expense.ById], formatAmount(total), commodity); err != nil {
+		return err
+	}
+	for _, stake := range expense.Stakes {
+		if _, err := fmt.Fprintf(w, "    %s  %s %s\n", accounts[stake.ForId], formatAmount(-stake.Amount), commodity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSettlements writes the settlements as comments since they are suggestions rather than transfers which happened
+func writeSettlements(w io.Writer, group *Group, accounts map[string]string, commodity string) error {
+	if len(group.Settlements) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "\n; settlements\n"); err != nil {
+		return err
+	}
+	for _, settlement := range group.Settlements {
+		if _, err := fmt.Fprintf(w, "; %s pays %s %s %s\n", accounts[settlement.FromId], accounts[settlement.ToId], formatAmount(settlement.Amount), commodity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatAmount formats minor units as a decimal number with two fractional digits
+func formatAmount(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
+
+// personNames returns the names of the persons by their ID
+func personNames(persons []Person) map[string]string {
+	names := make(map[string]string, len(persons))
+	for _, person := range persons {
+		names[person.Id] = person.Name
+	}
+	return names
+}
+
+// categoryNames returns the names of the categories by their ID
+func categoryNames(categories []Category) map[string]string {
+	names := make(map[string]string, len(categories))
+	for _, category := range categories {
+		names[category.Id] = category.Name
+	}
+	return names
+}
+
+// expenseCategories returns the sorted names of the categories of the expense
+func expenseCategories(expense Expense, categories map[string]string) []string {
+	names := make([]string, 0, len(expense.CategoryIds))
+	for _, categoryId := range expense.CategoryIds {
+		if name, ok := categories[categoryId]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedExpenses returns the expenses ordered by their time and ID
+func sortedExpenses(expenses []Expense) []Expense {
+	sorted := make([]Expense, len(expenses))
+	copy(sorted, expenses)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].Time.Equal(sorted[j].Time) {
+			return sorted[i].Time.Before(sorted[j].Time)
+		}
+		return sorted[i].Id < sorted[j].Id
+	})
+	return sorted
+}
+
+// expenseName returns the name of the expense or a placeholder for expenses without a name
+func expenseName(expense Expense) string {
+	if expense.Name == "" {
+		return "Expense"
+	}
+	return expense.Name
+}
+
+// personAccounts returns the accounts of the persons by their ID. An account is named after the person with all characters but letters
+// and digits replaced by dashes and its first letter capitalised, which both hledger and beancount accept. Persons whose names result in
+// the same account are told apart by a numeric suffix assigned in the order of their IDs.
+func personAccounts(persons []Person, prefix string) map[string]string {
+	sorted := make([]Person, len(persons))
+	copy(sorted, persons)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Id < sorted[j].Id
+	})
+	accounts := make(map[string]string, len(sorted))
+	taken := make(map[string]bool, len(sorted))
+	for _, person := range sorted {
+		name := accountName(person.Name)
+		account := prefix + name
+		for i := 2; taken[account]; i++ {
+			account = prefix + name + "-" + strconv.Itoa(i)
+		}
+		taken[account] = true
+		accounts[person.Id] = account
+	}
+	return accounts
+}
+
+// accountName turns the name of a person into an account name component
+func accountName(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteRune('-')
+			}
+			dash = false
+			if b.Len() == 0 {
+				r = unicode.ToUpper(r)
+			}
+			b.WriteRune(r)
+		} else {
+			dash = true
+		}
+	}
+	if b.Len() == 0 {
+		return "Person"
+	}
+	return b.String()
+}
+
+// commodity returns the acronym of a currency in upper case as required by beancount
+func commodity(acronym string) string {
+	return strings.ToUpper(acronym)
+}
+
+// quote returns the value as a beancount string on a single line
+func quote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(singleLine(value)) + `"`
+}
+
+// singleLine replaces the line breaks of the value by spaces
+func singleLine(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
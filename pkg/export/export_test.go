@@ -0,0 +1,120 @@
+package export_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/export"
+)
+
+func testGroup() *export.Group {
+	return &export.Group{
+		Name:     "Trip to Rome",
+		Currency: "EUR",
+		Persons: []export.Person{
+			{Id: "person-1", Name: "Alice"},
+			{Id: "person-2", Name: "Bob Smith"},
+			{Id: "person-3", Name: "Bob, Smith"},
+		},
+		Categories: []export.Category{
+			{Id: "category-1", Name: "Food"},
+			{Id: "category-2", Name: "Drinks"},
+		},
+		Expenses: []export.Expense{
+			{
+				Id:          "expense-2",
+				Name:        "Bar",
+				Time:        time.Date(2023, time.March, 2, 22, 0, 0, 0, time.UTC),
+				ById:        "person-2",
+				Currency:    "usd",
+				CategoryIds: []string{"category-2"},
+				Stakes:      []export.Stake{{ForId: "person-1", Amount: 550}},
+			},
+			{
+				Id:          "expense-1",
+				Name:        "Dinner \"Da Enzo\"",
+				Time:        time.Date(2023, time.March, 1, 20, 0, 0, 0, time.UTC),
+				ById:        "person-1",
+				Currency:    "EUR",
+				CategoryIds: []string{"category-1", "category-2"},
+				Stakes:      []export.Stake{{ForId: "person-1", Amount: 1500}, {ForId: "person-3", Amount: 1505}},
+			},
+		},
+		Settlements: []export.Settlement{
+			{FromId: "person-3", ToId: "person-1", Amount: 1000},
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := export.WriteCSV(&buf, testGroup()); err != nil {
+		t.Fatalf("failed writing CSV: %+v", err)
+	}
+	expected := `expense_id,expense_name,time,currency,paid_by,paid_for,amount,categories
+expense-1,"Dinner ""Da Enzo""",2023-03-01T20:00:00Z,EUR,Alice,Alice,15.00,Drinks;Food
+expense-1,"Dinner ""Da Enzo""",2023-03-01T20:00:00Z,EUR,Alice,"Bob, Smith",15.05,Drinks;Food
+expense-2,Bar,2023-03-02T22:00:00Z,usd,Bob Smith,Alice,5.50,Drinks
+`
+	if buf.String() != expected {
+		t.Errorf("expected CSV\n%s\nbut got\n%s", expected, buf.String())
+	}
+}
+
+func TestWriteHledger(t *testing.T) {
+	var buf bytes.Buffer
+	if err := export.WriteHledger(&buf, testGroup()); err != nil {
+		t.Fatalf("failed writing hledger journal: %+v", err)
+	}
+	expected := `; Trip to Rome
+
+2023-03-01 Dinner "Da Enzo"  ; expense:expense-1, category:Drinks, category:Food
+    assets:persons:Alice  30.05 EUR
+    assets:persons:Alice  -15.00 EUR
+    assets:persons:Bob-Smith-2  -15.05 EUR
+
+2023-03-02 Bar  ; expense:expense-2, category:Drinks
+    assets:persons:Bob-Smith  5.50 usd
+    assets:persons:Alice  -5.50 usd
+
+; settlements
+; assets:persons:Bob-Smith-2 pays assets:persons:Alice 10.00 EUR
+`
+	if buf.String() != expected {
+		t.Errorf("expected hledger journal\n%s\nbut got\n%s", expected, buf.String())
+	}
+}
+
+func TestWriteBeancount(t *testing.T) {
+	var buf bytes.Buffer
+	if err := export.WriteBeancount(&buf, testGroup()); err != nil {
+		t.Fatalf("failed writing beancount ledger: %+v", err)
+	}
+	expected := `option "title" "Trip to Rome"
+option "operating_currency" "EUR"
+
+2023-03-01 open Assets:Persons:Alice
+2023-03-01 open Assets:Persons:Bob-Smith
+2023-03-01 open Assets:Persons:Bob-Smith-2
+
+2023-03-01 * "Dinner \"Da Enzo\""
+  expense: "expense-1"
+  categories: "Drinks, Food"
+    Assets:Persons:Alice  30.05 EUR
+    Assets:Persons:Alice  -15.00 EUR
+    Assets:Persons:Bob-Smith-2  -15.05 EUR
+
+2023-03-02 * "Bar"
+  expense: "expense-2"
+  categories: "Drinks"
+    Assets:Persons:Bob-Smith  5.50 USD
+    Assets:Persons:Alice  -5.50 USD
+
+; settlements
+; Assets:Persons:Bob-Smith-2 pays Assets:Persons:Alice 10.00 EUR
+`
+	if buf.String() != expected {
+		t.Errorf("expected beancount ledger\n%s\nbut got\n%s", expected, buf.String())
+	}
+}
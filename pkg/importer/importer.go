@@ -0,0 +1,358 @@
+// Package importer reads the expenses of groups exported by other expense splitting apps
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+// ErrInvalidFile is returned if a file cannot be read at all as opposed to single rows of it
+var ErrInvalidFile = eris.New("the file cannot be read")
+
+// Group is what is read of a group; all amounts are minor units, e.g. cents
+type Group struct {
+	// Persons are the names of all persons of the group including the ones without any expense
+	Persons []string
+	// Categories are the names of all categories of the group including the ones without any expense
+	Categories []string
+	Expenses   []Expense
+	// Errors are the rows which cannot be imported
+	Errors []RowError
+}
+
+type Expense struct {
+	// Row is the number of the row the expense was read from with the header being the first row
+	Row  int
+	Name string
+	Time time.Time
+	// ByName is the name of the person who paid the expense
+	ByName string
+	// Currency is the acronym of the currency of the expense
+	Currency   string
+	Categories []string
+	Stakes     []Stake
+}
+
+type Stake struct {
+	ForName string
+	Amount  int64
+}
+
+// RowError tells why a row cannot be imported
+type RowError struct {
+	Row     int
+	Message string
+}
+
+// splitwiseColumns is the number of columns of a Splitwise export preceding the columns of the persons
+const splitwiseColumns = 5
+
+// splitwiseTotal is the description of the last row of a Splitwise export which sums up the balances
+const splitwiseTotal = "Total balance"
+
+// ReadSplitwiseCSV reads a CSV export of Splitwise. It has the columns Date, Description, Category, Cost and Currency followed by a column
+// per person telling how the expense changes the balance of the person: the payer is credited with the cost minus their own share and
+// everybody else is debited with their share. Expenses paid by several persons cannot be told apart and are therefore rejected.
+func ReadSplitwiseCSV(r io.Reader) (*Group, error) {
+	records, err := readRecords(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 || len(records[0]) <= splitwiseColumns || !strings.EqualFold(strings.TrimSpace(records[0][0]), "Date") {
+		return nil, eris.Wrap(ErrInvalidFile, "a Splitwise export starts with the columns Date, Description, Category, Cost and Currency followed by the persons")
+	}
+	persons := trimAll(records[0][splitwiseColumns:])
+	group := &Group{Persons: persons}
+	categories := newNameSet()
+	for i, record := range records[1:] {
+		row := i + 2
+		if len(record) > 1 && strings.TrimSpace(record[0]) == "" && strings.TrimSpace(record[1]) == splitwiseTotal {
+			continue
+		}
+		if len(record) != len(records[0]) {
+			group.Errors = append(group.Errors, RowError{Row: row, Message: fmt.Sprintf("the row has %d instead of %d columns", len(record), len(records[0]))})
+			continue
+		}
+		expense, err := splitwiseExpense(row, record, persons)
+		if err != nil {
+			group.Errors = append(group.Errors, RowError{Row: row, Message: err.Error()})
+			continue
+		}
+		categories.add(expense.Categories...)
+		group.Expenses = append(group.Expenses, *expense)
+	}
+	group.Categories = categories.names
+	return group, nil
+}
+
+// splitwiseExpense turns a row of a Splitwise export into an expense
+func splitwiseExpense(row int, record []string, persons []string) (*Expense, error) {
+	timestamp, err := parseTime(record[0])
+	if err != nil {
+		return nil, err
+	}
+	cost, err := ParseAmount(record[3])
+	if err != nil {
+		return nil, eris.Wrap(err, "invalid cost")
+	}
+	expense := &Expense{
+		Row:      row,
+		Name:     strings.TrimSpace(record[1]),
+		Time:     timestamp,
+		Currency: strings.TrimSpace(record[4]),
+	}
+	if category := strings.TrimSpace(record[2]); category != "" {
+		expense.Categories = []string{category}
+	}
+	var sum, payerBalance int64
+	for i, value := range record[splitwiseColumns:] {
+		balance, err := ParseAmount(value)
+		if err != nil {
+			return nil, eris.Wrapf(err, "invalid balance of %s", persons[i])
+		}
+		sum += balance
+		if balance > 0 {
+			if expense.ByName != "" {
+				return nil, eris.New("expenses paid by several persons are not supported")
+			}
+			expense.ByName = persons[i]
+			payerBalance = balance
+		} else if balance < 0 {
+			expense.Stakes = append(expense.Stakes, Stake{ForName: persons[i], Amount: -balance})
+		}
+	}
+	if sum != 0 {
+		return nil, eris.New("the balances of the persons do not add up to zero")
+	}
+	if expense.ByName == "" {
+		return nil, eris.New("the payer cannot be told since nobody owes them a share")
+	}
+	if own := cost - payerBalance; own > 0 {
+		expense.Stakes = append(expense.Stakes, Stake{ForName: expense.ByName, Amount: own})
+	} else if own < 0 {
+		return nil, eris.New("the payer is owed more than the cost")
+	}
+	return expense, nil
+}
+
+// tricountSharePrefixes are the prefixes of the headers of the columns of a Tricount export holding the shares of the persons
+var tricountSharePrefixes = []string{"impacted to ", "paid for "}
+
+// ReadTricountCSV reads a CSV export of Tricount. Its columns are found by their headers: Title, Amount, Currency, Date & time and Paid by
+// are required while Category and Type are optional, and every column whose header starts with "Impacted to" or "Paid for" followed by the
+// name of a person holds the share of that person. Incomes are rejected and negative amounts of expenses are taken as positive ones.
+func ReadTricountCSV(r io.Reader) (*Group, error) {
+	records, err := readRecords(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, eris.Wrap(ErrInvalidFile, "the file is empty")
+	}
+	columns := make(map[string]int)
+	var persons []string
+	personColumns := make(map[int]string)
+	for i, header := range records[0] {
+		header = strings.TrimSpace(header)
+		lower := strings.ToLower(header)
+		share := false
+		for _, prefix := range tricountSharePrefixes {
+			if strings.HasPrefix(lower, prefix) {
+				name := strings.TrimSpace(header[len(prefix):])
+				persons = append(persons, name)
+				personColumns[i] = name
+				share = true
+				break
+			}
+		}
+		if !share {
+			columns[lower] = i
+		}
+	}
+	column := func(names ...string) int {
+		for _, name := range names {
+			if i, ok := columns[name]; ok {
+				return i
+			}
+		}
+		return -1
+	}
+	title, amount, currency := column("title", "description"), column("amount"), column("currency")
+	date, payer := column("date & time", "date"), column("paid by")
+	category, kind := column("category"), column("type")
+	if title < 0 || amount < 0 || currency < 0 || date < 0 || payer < 0 || len(persons) == 0 {
+		return nil, eris.Wrap(ErrInvalidFile, "a Tricount export needs the columns Title, Amount, Currency, Date & time and Paid by as well as a column per person")
+	}
+
+	group := &Group{Persons: persons}
+	categories := newNameSet()
+	for i, record := range records[1:] {
+		row := i + 2
+		if len(record) != len(records[0]) {
+			group.Errors = append(group.Errors, RowError{Row: row, Message: fmt.Sprintf("the row has %d instead of %d columns", len(record), len(records[0]))})
+			continue
+		}
+		expense, err := func() (*Expense, error) {
+			if kind >= 0 && strings.EqualFold(strings.TrimSpace(record[kind]), "income") {
+				return nil, eris.New("incomes are not supported")
+			}
+			timestamp, err := parseTime(record[date])
+			if err != nil {
+				return nil, err
+			}
+			total, err := ParseAmount(record[amount])
+			if err != nil {
+				return nil, eris.Wrap(err, "invalid amount")
+			}
+			sign := int64(1)
+			if total < 0 {
+				sign = -1
+			}
+			expense := &Expense{
+				Row:      row,
+				Name:     strings.TrimSpace(record[title]),
+				Time:     timestamp,
+				ByName:   strings.TrimSpace(record[payer]),
+				Currency: strings.TrimSpace(record[currency]),
+			}
+			if category >= 0 && strings.TrimSpace(record[category]) != "" {
+				expense.Categories = []string{strings.TrimSpace(record[category])}
+			}
+			if expense.ByName == "" {
+				return nil, eris.New("the payer is missing")
+			}
+			var sum int64
+			for j := range record {
+				name, ok := personColumns[j]
+				if !ok || strings.TrimSpace(record[j]) == "" {
+					continue
+				}
+				share, err := ParseAmount(record[j])
+				if err != nil {
+					return nil, eris.Wrapf(err, "invalid share of %s", name)
+				}
+				if share*sign < 0 {
+					return nil, eris.Errorf("the share of %s has the opposite sign of the amount", name)
+				}
+				if share != 0 {
+					expense.Stakes = append(expense.Stakes, Stake{ForName: name, Amount: share * sign})
+					sum += share * sign
+				}
+			}
+			if sum != total*sign {
+				return nil, eris.New("the shares of the persons do not add up to the amount")
+			}
+			return expense, nil
+		}()
+		if err != nil {
+			group.Errors = append(group.Errors, RowError{Row: row, Message: err.Error()})
+			continue
+		}
+		categories.add(expense.Categories...)
+		group.Expenses = append(group.Expenses, *expense)
+	}
+	group.Categories = categories.names
+	return group, nil
+}
+
+// ParseAmount parses a decimal amount into minor units. Both dots and commas are accepted as decimal separator if they are followed by at
+// most two digits; all other dots and commas are taken as thousands separators.
+func ParseAmount(value string) (int64, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), " ", "")
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimLeft(value, "+-")
+	integer, fraction := value, ""
+	if sep := strings.LastIndexAny(value, ".,"); sep >= 0 && len(value)-sep-1 <= 2 {
+		integer, fraction = value[:sep], value[sep+1:]
+	}
+	integer = strings.NewReplacer(".", "", ",", "").Replace(integer)
+	if integer == "" && fraction == "" {
+		return 0, eris.Errorf("%q is not an amount", value)
+	}
+	if integer == "" {
+		integer = "0"
+	}
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+	main, err := strconv.ParseUint(integer, 10, 31)
+	if err != nil {
+		return 0, eris.Errorf("%q is not an amount", value)
+	}
+	hundredths, err := strconv.ParseUint(fraction, 10, 8)
+	if err != nil {
+		return 0, eris.Errorf("%q is not an amount", value)
+	}
+	amount := int64(main)*100 + int64(hundredths)
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// timeLayouts are the layouts of the times of the expenses in the exports
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"02/01/2006 15:04:05",
+	"02/01/2006 15:04",
+	"02/01/2006",
+}
+
+// parseTime parses the time of an expense which is taken as UTC unless it tells its time zone
+func parseTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, eris.Errorf("%q is not a date", value)
+}
+
+// readRecords reads all records of a CSV file whose rows may have different numbers of columns
+func readRecords(r io.Reader) ([][]string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, eris.Wrap(ErrInvalidFile, err.Error())
+	}
+	return records, nil
+}
+
+// trimAll trims the spaces around all the values
+func trimAll(values []string) []string {
+	trimmed := make([]string, len(values))
+	for i, value := range values {
+		trimmed[i] = strings.TrimSpace(value)
+	}
+	return trimmed
+}
+
+// nameSet keeps names in the order they were added first in
+type nameSet struct {
+	names []string
+	seen  map[string]bool
+}
+
+func newNameSet() *nameSet {
+	return &nameSet{seen: make(map[string]bool)}
+}
+
+func (s *nameSet) add(names ...string) {
+	for _, name := range names {
+		if !s.seen[name] {
+			s.seen[name] = true
+			s.names = append(s.names, name)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package importer_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/importer"
+	"github.com/rotisserie/eris"
+)
+
+func TestReadSplitwiseCSV(t *testing.T) {
+	group, err := importer.ReadSplitwiseCSV(strings.NewReader(`Date,Description,Category,Cost,Currency,Alice,Bob,Carol
+2023-03-14,Dinner,Dining out,30.00,EUR,20.00,-10.00,-10.00
+2023-03-15,Taxi,Taxi,12.50,EUR,-6.25,12.50,-6.25
+2023-03-16,Tickets,Entertainment,20.00,EUR,10.00,10.00,-20.00
+2023-03-17,Museum,Entertainment,not a number,EUR,0.00,0.00,0.00
+
+2023-03-18,Total balance,,,EUR,23.75,12.25,-36.00
+,Total balance,,,EUR,23.75,12.25,-36.00
+`))
+	if err != nil {
+		t.Fatalf("failed reading Splitwise export: %+v", err)
+	}
+	if !reflect.DeepEqual(group.Persons, []string{"Alice", "Bob", "Carol"}) {
+		t.Errorf("expected the persons of the header but got %+v", group.Persons)
+	}
+	if !reflect.DeepEqual(group.Categories, []string{"Dining out", "Taxi"}) {
+		t.Errorf("expected the categories of the imported expenses but got %+v", group.Categories)
+	}
+	expected := []importer.Expense{
+		{
+			Row: 2, Name: "Dinner", Time: time.Date(2023, time.March, 14, 0, 0, 0, 0, time.UTC), ByName: "Alice", Currency: "EUR",
+			Categories: []string{"Dining out"},
+			Stakes:     []importer.Stake{{ForName: "Bob", Amount: 1000}, {ForName: "Carol", Amount: 1000}, {ForName: "Alice", Amount: 1000}},
+		},
+		{
+			Row: 3, Name: "Taxi", Time: time.Date(2023, time.March, 15, 0, 0, 0, 0, time.UTC), ByName: "Bob", Currency: "EUR",
+			Categories: []string{"Taxi"},
+			Stakes:     []importer.Stake{{ForName: "Alice", Amount: 625}, {ForName: "Carol", Amount: 625}},
+		},
+	}
+	if !reflect.DeepEqual(group.Expenses, expected) {
+		t.Errorf("expected expenses %+v but got %+v", expected, group.Expenses)
+	}
+	if len(group.Errors) != 3 || group.Errors[0].Row != 4 || group.Errors[1].Row != 5 || group.Errors[2].Row != 6 {
+		t.Errorf("expected errors for the rows 4 to 6 but got %+v", group.Errors)
+	}
+}
+
+func TestReadTricountCSV(t *testing.T) {
+	group, err := importer.ReadTricountCSV(strings.NewReader(`Title,Amount,Currency,Date & time,Paid by,Type,Impacted to Alice,Impacted to Bob
+Groceries,-24.10,EUR,2023-03-14 18:30:00,Bob,Expense,-12.05,-12.05
+Refund,5.00,EUR,2023-03-15 09:00:00,Alice,Income,2.50,2.50
+Hotel,100.00,EUR,2023-03-16 12:00:00,Alice,Expense,50.00,40.00
+`))
+	if err != nil {
+		t.Fatalf("failed reading Tricount export: %+v", err)
+	}
+	if !reflect.DeepEqual(group.Persons, []string{"Alice", "Bob"}) {
+		t.Errorf("expected the persons of the header but got %+v", group.Persons)
+	}
+	expected := []importer.Expense{
+		{
+			Row: 2, Name: "Groceries", Time: time.Date(2023, time.March, 14, 18, 30, 0, 0, time.UTC), ByName: "Bob", Currency: "EUR",
+			Stakes: []importer.Stake{{ForName: "Alice", Amount: 1205}, {ForName: "Bob", Amount: 1205}},
+		},
+	}
+	if !reflect.DeepEqual(group.Expenses, expected) {
+		t.Errorf("expected expenses %+v but got %+v", expected, group.Expenses)
+	}
+	if len(group.Errors) != 2 || group.Errors[0].Row != 3 || group.Errors[1].Row != 4 {
+		t.Errorf("expected errors for the income and the shares not adding up but got %+v", group.Errors)
+	}
+
+	if _, err := importer.ReadTricountCSV(strings.NewReader("Title,Amount\nDinner,10.00\n")); !eris.Is(err, importer.ErrInvalidFile) {
+		t.Errorf("expected an export without the required columns to be invalid but got %+v", err)
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	for value, expected := range map[string]int64{
+		"12":        1200,
+		"12.5":      1250,
+		"-12.50":    -1250,
+		"12,50":     1250,
+		"1,234":     123400,
+		"1.234,56":  123456,
+		"1,234.56":  123456,
+		"+0.05":     5,
+		" 3 000.1 ": 300010,
+	} {
+		if amount, err := importer.ParseAmount(value); err != nil || amount != expected {
+			t.Errorf("expected %q to be parsed into %d but got %d and %+v", value, expected, amount, err)
+		}
+	}
+	for _, value := range []string{"", "abc", "12.5x", "-"} {
+		if _, err := importer.ParseAmount(value); err == nil {
+			t.Errorf("expected %q not to be an amount", value)
+		}
+	}
+}
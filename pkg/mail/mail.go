@@ -0,0 +1,42 @@
+package mail
+
+import (
+	"context"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+)
+
+// Message is a plain text email to a single recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers emails
+type Sender interface {
+	// Send delivers the message to its recipient; it returns once the message was accepted for delivery
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config configures the SMTP server emails are delivered through
+type Config struct {
+	Host string
+	Port uint16
+	// Username and Password authenticate with the SMTP server unless the username is empty
+	Username string
+	Password string
+	// From is the address emails are sent from, e.g. Expense Splitter <noreply@example.com>
+	From string
+}
+
+// ConfigFromEnvironment reads the SMTP config from the environment variables
+func ConfigFromEnvironment(ctx context.Context) Config {
+	return Config{
+		Host:     environment.GetSMTPHost(ctx),
+		Port:     environment.GetSMTPPort(ctx),
+		Username: environment.GetSMTPUsername(),
+		Password: environment.GetSMTPPassword(),
+		From:     environment.GetSMTPFrom(ctx),
+	}
+}
@@ -0,0 +1,132 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+var _ Sender = (*smtpSender)(nil)
+
+type smtpSender struct {
+	cfg  Config
+	from *netmail.Address
+	now  func() time.Time
+}
+
+// NewSMTPSender creates a sender delivering emails through the configured SMTP server. The connection is upgraded
+// with STARTTLS whenever the server supports it, which is required for authenticating unless the server is local.
+func NewSMTPSender(cfg Config) (*smtpSender, error) {
+	if cfg.Host == "" {
+		return nil, eris.New("the SMTP host must not be empty")
+	}
+	from, err := netmail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed parsing the address emails are sent from")
+	}
+	return &smtpSender{
+		cfg:  cfg,
+		from: from,
+		now:  time.Now,
+	}, nil
+}
+
+// Send opens a new connection for every message since notifications are rare enough for that not to matter
+func (s *smtpSender) Send(ctx context.Context, msg Message) error {
+	to, err := netmail.ParseAddress(msg.To)
+	if err != nil {
+		return eris.Wrap(err, "failed parsing the recipient address")
+	}
+	data, err := s.compose(to, msg)
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.cfg.Host, strconv.Itoa(int(s.cfg.Port))))
+	if err != nil {
+		return eris.Wrap(err, "failed connecting to SMTP server")
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return eris.Wrap(err, "failed setting deadline of SMTP connection")
+		}
+	}
+	c, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return eris.Wrap(err, "failed greeting SMTP server")
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return eris.Wrap(err, "failed upgrading SMTP connection to TLS")
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return eris.Wrap(err, "failed authenticating with SMTP server")
+		}
+	}
+	if err := c.Mail(s.from.Address); err != nil {
+		return eris.Wrap(err, "the SMTP server rejected the sender")
+	}
+	if err := c.Rcpt(to.Address); err != nil {
+		return eris.Wrap(err, "the SMTP server rejected the recipient")
+	}
+	w, err := c.Data()
+	if err != nil {
+		return eris.Wrap(err, "the SMTP server rejected the message")
+	}
+	if _, err := w.Write(data); err != nil {
+		return eris.Wrap(err, "failed writing message")
+	}
+	if err := w.Close(); err != nil {
+		return eris.Wrap(err, "the SMTP server rejected the message")
+	}
+	if err := c.Quit(); err != nil {
+		return eris.Wrap(err, "failed closing SMTP session")
+	}
+	return nil
+}
+
+// compose renders the message as UTF-8 plain text email whose body is quoted-printable encoded so that it may contain any characters
+func (s *smtpSender) compose(to *netmail.Address, msg Message) ([]byte, error) {
+	messageId := make([]byte, 16)
+	if _, err := rand.Read(messageId); err != nil {
+		return nil, eris.Wrap(err, "failed generating message ID")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", s.now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(messageId), s.cfg.Host)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	buf.WriteString("\r\n")
+	w := quotedprintable.NewWriter(&buf)
+	if _, err := w.Write([]byte(msg.Body)); err != nil {
+		return nil, eris.Wrap(err, "failed encoding message body")
+	}
+	if err := w.Close(); err != nil {
+		return nil, eris.Wrap(err, "failed encoding message body")
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,144 @@
+package mail_test
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mail"
+	mailtesting "github.com/nico151999/high-availability-expense-splitter/pkg/mail/testing"
+)
+
+func TestSMTPSender(t *testing.T) {
+	server, err := mailtesting.RunSMTPServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	t.Run("Sending a message delivers it to the recipient", func(t *testing.T) {
+		sender, err := mail.NewSMTPSender(mail.Config{
+			Host: server.Host(),
+			Port: server.Port(),
+			From: "Expense Splitter <noreply@example.com>",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		body := "Die Ausgabe „Abendessen“ wurde hinzugefügt.\n.\nEin Punkt am Zeilenanfang bleibt erhalten."
+		if err := sender.Send(ctx, mail.Message{
+			To:      "Bob <bob@example.com>",
+			Subject: "Neue Ausgabe in „Urlaub“",
+			Body:    body,
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		messages := server.Messages()
+		if len(messages) != 1 {
+			t.Fatalf("expected 1 message but got %d", len(messages))
+		}
+		received := messages[0]
+		if received.From != "noreply@example.com" {
+			t.Errorf("expected the message to be sent from noreply@example.com but got %s", received.From)
+		}
+		if len(received.To) != 1 || received.To[0] != "bob@example.com" {
+			t.Errorf("expected the message to be sent to bob@example.com but got %v", received.To)
+		}
+		if received.Username != "" {
+			t.Errorf("expected no authentication but got user %s", received.Username)
+		}
+
+		parsed, err := netmail.ReadMessage(strings.NewReader(received.Data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if subject != "Neue Ausgabe in „Urlaub“" {
+			t.Errorf("expected the subject to be decoded to the sent subject but got %s", subject)
+		}
+		if to := parsed.Header.Get("To"); to != `"Bob" <bob@example.com>` {
+			t.Errorf("expected the To header to name the recipient but got %s", to)
+		}
+		if contentType := parsed.Header.Get("Content-Type"); contentType != "text/plain; charset=utf-8" {
+			t.Errorf("expected a UTF-8 plain text message but got %s", contentType)
+		}
+		decoded, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the fake server reads the lines of the message with LF line endings and the message ends with a line break
+		if expected := body + "\n"; string(decoded) != expected {
+			t.Errorf("expected body %q but got %q", expected, decoded)
+		}
+	})
+
+	t.Run("Sending a message with credentials authenticates", func(t *testing.T) {
+		sender, err := mail.NewSMTPSender(mail.Config{
+			Host:     server.Host(),
+			Port:     server.Port(),
+			Username: "expense-splitter",
+			Password: "secret",
+			From:     "noreply@example.com",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sender.Send(ctx, mail.Message{
+			To:      "alice@example.com",
+			Subject: "Hello",
+			Body:    "Hello Alice",
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		messages := server.Messages()
+		received := messages[len(messages)-1]
+		if received.Username != "expense-splitter" || received.Password != "secret" {
+			t.Errorf("expected the configured credentials to be passed but got %s:%s", received.Username, received.Password)
+		}
+	})
+
+	t.Run("Sending a message to an invalid address fails", func(t *testing.T) {
+		sender, err := mail.NewSMTPSender(mail.Config{
+			Host: server.Host(),
+			Port: server.Port(),
+			From: "noreply@example.com",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		before := len(server.Messages())
+		if err := sender.Send(ctx, mail.Message{
+			To:      "not an address",
+			Subject: "Hello",
+			Body:    "Hello",
+		}); err == nil {
+			t.Error("expected sending to an invalid address to fail")
+		}
+		if after := len(server.Messages()); after != before {
+			t.Errorf("expected no message to be delivered but got %d", after-before)
+		}
+	})
+
+	t.Run("Creating a sender with an invalid sender address fails", func(t *testing.T) {
+		if _, err := mail.NewSMTPSender(mail.Config{
+			Host: server.Host(),
+			Port: server.Port(),
+			From: "",
+		}); err == nil {
+			t.Error("expected creating a sender without sender address to fail")
+		}
+	})
+}
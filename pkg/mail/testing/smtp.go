@@ -0,0 +1,180 @@
+package testing
+
+import (
+	"encoding/base64"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// ReceivedMessage is a message accepted by the fake SMTP server
+type ReceivedMessage struct {
+	From string
+	To   []string
+	// Username is the user the client authenticated as or empty if it did not authenticate
+	Username string
+	Password string
+	// Data is the message including its headers as it was transmitted
+	Data string
+}
+
+// SMTPServer is a fake SMTP server meant for testing which accepts all messages without delivering them
+type SMTPServer struct {
+	listener net.Listener
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	messages []ReceivedMessage
+}
+
+// RunSMTPServer starts a fake SMTP server listening on a random free port of the loopback interface
+func RunSMTPServer() (*SMTPServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &SMTPServer{
+		listener: listener,
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.serve(conn)
+			}()
+		}
+	}()
+	return s, nil
+}
+
+// Host returns the host the server listens on
+func (s *SMTPServer) Host() string {
+	return s.listener.Addr().(*net.TCPAddr).IP.String()
+}
+
+// Port returns the port the server listens on
+func (s *SMTPServer) Port() uint16 {
+	return uint16(s.listener.Addr().(*net.TCPAddr).Port)
+}
+
+// Messages returns the messages accepted so far in the order they were accepted
+func (s *SMTPServer) Messages() []ReceivedMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ReceivedMessage(nil), s.messages...)
+}
+
+// Close stops the server and waits for open sessions to end
+func (s *SMTPServer) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+// serve speaks just enough SMTP for clients to authenticate with the PLAIN mechanism and transmit messages
+func (s *SMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	var msg ReceivedMessage
+	reply := func(line string) bool {
+		return tp.PrintfLine("%s", line) == nil
+	}
+
+	if !reply("220 localhost fake SMTP server") {
+		return
+	}
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			if !reply("250-localhost") || !reply("250 AUTH PLAIN") {
+				return
+			}
+		case "HELO", "NOOP":
+			if !reply("250 OK") {
+				return
+			}
+		case "AUTH":
+			mechanism, initialResponse, _ := strings.Cut(arg, " ")
+			credentials, err := base64.StdEncoding.DecodeString(initialResponse)
+			fields := strings.Split(string(credentials), "\x00")
+			if !strings.EqualFold(mechanism, "PLAIN") || err != nil || len(fields) != 3 {
+				if !reply("535 authentication failed") {
+					return
+				}
+				continue
+			}
+			msg.Username, msg.Password = fields[1], fields[2]
+			if !reply("235 authenticated") {
+				return
+			}
+		case "MAIL":
+			msg.From = trimPath(arg, "FROM:")
+			if !reply("250 OK") {
+				return
+			}
+		case "RCPT":
+			msg.To = append(msg.To, trimPath(arg, "TO:"))
+			if !reply("250 OK") {
+				return
+			}
+		case "DATA":
+			if !reply("354 end data with <CR><LF>.<CR><LF>") {
+				return
+			}
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.Data = string(data)
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			msg = ReceivedMessage{
+				Username: msg.Username,
+				Password: msg.Password,
+			}
+			if !reply("250 OK") {
+				return
+			}
+		case "RSET":
+			msg = ReceivedMessage{
+				Username: msg.Username,
+				Password: msg.Password,
+			}
+			if !reply("250 OK") {
+				return
+			}
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			if !reply("502 command not implemented") {
+				return
+			}
+		}
+	}
+}
+
+// trimPath returns the address of a path argument like FROM:<alice@example.com>
+func trimPath(arg string, prefix string) string {
+	if len(arg) >= len(prefix) && strings.EqualFold(arg[:len(prefix)], prefix) {
+		arg = arg[len(prefix):]
+	}
+	arg = strings.TrimSpace(arg)
+	if i := strings.Index(arg, " "); i >= 0 {
+		arg = arg[:i]
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(arg, "<"), ">")
+}
@@ -1,8 +1,15 @@
 package client
 
 import (
+	"context"
+	"encoding/base64"
+
 	"github.com/nats-io/nats.go"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/serialization"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
+	"github.com/rotisserie/eris"
+	"google.golang.org/protobuf/proto"
 )
 
 const PROTOBUF_ENCODER = "protobuf"
@@ -22,3 +29,71 @@ func NewProtoMQClient(natsServer string) (*nats.EncodedConn, error) {
 	}
 	return encodedClient, nil
 }
+
+// PublishEvent encodes the event and publishes it on the subject attributed to the principal of the context
+func PublishEvent(ctx context.Context, nc *nats.EncodedConn, subject string, event any) error {
+	data, err := nc.Enc.Encode(subject, event)
+	if err != nil {
+		return err
+	}
+	return PublishEventData(ctx, nc.Conn, subject, data)
+}
+
+// PublishEventData publishes the already encoded event on the subject attributed to the principal of the context
+func PublishEventData(ctx context.Context, nc *nats.Conn, subject string, data []byte) error {
+	return nc.PublishMsg(newEventMsg(ctx, subject, data))
+}
+
+// PublishResourceEvent is like PublishEvent but the event also carries the state of the resource it is about after the change, see EventResource
+func PublishResourceEvent(ctx context.Context, nc *nats.EncodedConn, subject string, event any, resource proto.Message) error {
+	data, err := nc.Enc.Encode(subject, event)
+	if err != nil {
+		return err
+	}
+	return PublishResourceEventData(ctx, nc.Conn, subject, data, resource)
+}
+
+// PublishResourceEventData is like PublishEventData but the event also carries the state of the resource it is about after the change, see EventResource
+func PublishResourceEventData(ctx context.Context, nc *nats.Conn, subject string, data []byte, resource proto.Message) error {
+	state, err := proto.Marshal(resource)
+	if err != nil {
+		return eris.Wrap(err, "failed marshalling resource of event")
+	}
+	msg := newEventMsg(ctx, subject, data)
+	// headers cannot hold arbitrary bytes
+	msg.Header.Set(environment.GetEventResourceHeaderKey(), base64.StdEncoding.EncodeToString(state))
+	return nc.PublishMsg(msg)
+}
+
+func newEventMsg(ctx context.Context, subject string, data []byte) *nats.Msg {
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	msg.Header.Set(environment.GetEventPrincipalHeaderKey(), principal.FromContext(ctx))
+	return msg
+}
+
+// EventPrincipal returns the principal an event with the passed headers is attributed to or Anonymous if it carries none
+func EventPrincipal(header nats.Header) string {
+	if p := header.Get(environment.GetEventPrincipalHeaderKey()); p != "" {
+		return p
+	}
+	return principal.Anonymous
+}
+
+// EventResource unmarshals the state of the resource an event with the passed headers carries into the passed resource and returns false
+// if it carries none. Consumers interested in the state of a resource at the time of an event should prefer it over reading the resource
+// since the resource may have changed again by the time they process the event.
+func EventResource(header nats.Header, resource proto.Message) (bool, error) {
+	encoded := header.Get(environment.GetEventResourceHeaderKey())
+	if encoded == "" {
+		return false, nil
+	}
+	state, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return false, eris.Wrap(err, "failed decoding resource of event")
+	}
+	if err := proto.Unmarshal(state, resource); err != nil {
+		return false, eris.Wrap(err, "failed unmarshalling resource of event")
+	}
+	return true, nil
+}
@@ -0,0 +1,134 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/environment"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	mqtesting "github.com/nico151999/high-availability-expense-splitter/pkg/mq/testing"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestPublishEvent(t *testing.T) {
+	server, port := mqtesting.RunMQServer(-1)
+	defer server.Shutdown()
+
+	nc, err := client.NewProtoMQClient(fmt.Sprintf("localhost:%d", port))
+	if err != nil {
+		t.Fatalf("failed connecting to NATS server: %+v", err)
+	}
+	defer nc.Close()
+
+	for name, params := range map[string]struct {
+		ctx       context.Context
+		principal string
+	}{
+		"Attribute event to principal":          {principal.IntoContext(context.Background(), "alice"), "alice"},
+		"Attribute event to anonymous if unset": {context.Background(), principal.Anonymous},
+	} {
+		params := params
+		t.Run(name, func(t *testing.T) {
+			sub, err := nc.Conn.SubscribeSync("test.event")
+			if err != nil {
+				t.Fatalf("failed subscribing: %+v", err)
+			}
+			defer sub.Unsubscribe()
+
+			if err := client.PublishEvent(params.ctx, nc, "test.event", wrapperspb.String("foo")); err != nil {
+				t.Fatalf("failed publishing event: %+v", err)
+			}
+			msg, err := sub.NextMsg(time.Second)
+			if err != nil {
+				t.Fatalf("failed receiving event: %+v", err)
+			}
+			if p := client.EventPrincipal(msg.Header); p != params.principal {
+				t.Errorf("expected the event to be attributed to %s but got %s", params.principal, p)
+			}
+			var event wrapperspb.StringValue
+			if err := proto.Unmarshal(msg.Data, &event); err != nil {
+				t.Fatalf("failed unmarshalling event: %+v", err)
+			}
+			if event.GetValue() != "foo" {
+				t.Errorf("expected the event to be encoded but got %s", event.GetValue())
+			}
+		})
+	}
+
+	t.Run("Attribute events without header to anonymous", func(t *testing.T) {
+		if p := client.EventPrincipal(nats.Header{}); p != principal.Anonymous {
+			t.Errorf("expected the event to be attributed to %s but got %s", principal.Anonymous, p)
+		}
+	})
+}
+
+func TestPublishResourceEvent(t *testing.T) {
+	server, port := mqtesting.RunMQServer(-1)
+	defer server.Shutdown()
+
+	nc, err := client.NewProtoMQClient(fmt.Sprintf("localhost:%d", port))
+	if err != nil {
+		t.Fatalf("failed connecting to NATS server: %+v", err)
+	}
+	defer nc.Close()
+
+	sub, err := nc.Conn.SubscribeSync("test.event")
+	if err != nil {
+		t.Fatalf("failed subscribing: %+v", err)
+	}
+	defer sub.Unsubscribe()
+
+	t.Run("Carry the state of the resource", func(t *testing.T) {
+		ctx := principal.IntoContext(context.Background(), "alice")
+		if err := client.PublishResourceEvent(ctx, nc, "test.event", wrapperspb.String("foo"), wrapperspb.String("bar")); err != nil {
+			t.Fatalf("failed publishing event: %+v", err)
+		}
+		msg, err := sub.NextMsg(time.Second)
+		if err != nil {
+			t.Fatalf("failed receiving event: %+v", err)
+		}
+		if p := client.EventPrincipal(msg.Header); p != "alice" {
+			t.Errorf("expected the event to be attributed to alice but got %s", p)
+		}
+		var resource wrapperspb.StringValue
+		if ok, err := client.EventResource(msg.Header, &resource); err != nil || !ok {
+			t.Fatalf("expected the event to carry the resource; got: %t, %+v", ok, err)
+		}
+		if resource.GetValue() != "bar" {
+			t.Errorf("expected the state of the resource but got %s", resource.GetValue())
+		}
+		var event wrapperspb.StringValue
+		if err := proto.Unmarshal(msg.Data, &event); err != nil {
+			t.Fatalf("failed unmarshalling event: %+v", err)
+		}
+		if event.GetValue() != "foo" {
+			t.Errorf("expected the event to be encoded but got %s", event.GetValue())
+		}
+	})
+
+	t.Run("Carry no state of a resource", func(t *testing.T) {
+		if err := client.PublishEvent(context.Background(), nc, "test.event", wrapperspb.String("foo")); err != nil {
+			t.Fatalf("failed publishing event: %+v", err)
+		}
+		msg, err := sub.NextMsg(time.Second)
+		if err != nil {
+			t.Fatalf("failed receiving event: %+v", err)
+		}
+		if ok, err := client.EventResource(msg.Header, &wrapperspb.StringValue{}); err != nil || ok {
+			t.Errorf("expected the event to carry no resource; got: %t, %+v", ok, err)
+		}
+	})
+
+	t.Run("Fail reading a malformed state", func(t *testing.T) {
+		header := nats.Header{}
+		header.Set(environment.GetEventResourceHeaderKey(), "not base64!")
+		if _, err := client.EventResource(header, &wrapperspb.StringValue{}); err == nil {
+			t.Error("expected reading the malformed resource to fail")
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package election
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/rotisserie/eris"
+)
+
+// LeaderElection elects a single leader among all replicas campaigning for the same key.
+// The leadership is a lease stored in a NATS key-value bucket whose entries expire after the lease duration
+// unless the leader renews them. If the leader dies another replica takes over after at most
+// the lease duration plus the retry period.
+type LeaderElection struct {
+	natsClient    *nats.Conn
+	kv            nats.KeyValue
+	key           string
+	identity      string
+	leaseDuration time.Duration
+	retryPeriod   time.Duration
+}
+
+// NewLeaderElection creates a leader election campaigning for the passed key in the passed bucket.
+// The bucket is created if it does not exist yet. All elections sharing a bucket must use the same lease duration.
+func NewLeaderElection(ctx context.Context, natsClient *nats.Conn, bucket string, key string, leaseDuration time.Duration) (*LeaderElection, error) {
+	log := logging.FromContext(ctx).With(
+		logging.String("bucket", bucket),
+		logging.String("key", key),
+	)
+
+	js, err := natsClient.JetStream()
+	if err != nil {
+		msg := "failed creating NATS jetstream client"
+		log.Error(msg, logging.Error(err))
+		return nil, eris.Wrap(err, msg)
+	}
+	kv, err := js.KeyValue(bucket)
+	if err != nil {
+		if !eris.Is(err, nats.ErrBucketNotFound) {
+			msg := "failed getting NATS key-value bucket"
+			log.Error(msg, logging.Error(err))
+			return nil, eris.Wrap(err, msg)
+		}
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:  bucket,
+			TTL:     leaseDuration,
+			Storage: nats.FileStorage,
+		})
+		if err != nil {
+			msg := "failed creating NATS key-value bucket"
+			log.Error(msg, logging.Error(err))
+			return nil, eris.Wrap(err, msg)
+		}
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return &LeaderElection{
+		natsClient:    natsClient,
+		kv:            kv,
+		key:           key,
+		identity:      fmt.Sprintf("%s-%d", hostname, time.Now().UnixNano()),
+		leaseDuration: leaseDuration,
+		retryPeriod:   leaseDuration / 3,
+	}, nil
+}
+
+// Run campaigns for the leadership until the context is done. Whenever the leadership is acquired onLeading is called
+// with a context that is canceled as soon as the leadership is lost. The lease is released once onLeading returns.
+func (e *LeaderElection) Run(ctx context.Context, onLeading func(ctx context.Context)) {
+	log := logging.FromContext(ctx).With(
+		logging.String("key", e.key),
+		logging.String("identity", e.identity),
+	)
+	ctx = logging.IntoContext(ctx, log)
+
+	ticker := time.NewTicker(e.retryPeriod)
+	defer ticker.Stop()
+
+	for {
+		// the ticker may have fired while the context was done already, in which case the leadership must not be acquired again
+		if ctx.Err() != nil {
+			log.Info("the context is done")
+			return
+		}
+		if revision, err := e.kv.Create(e.key, []byte(e.identity)); err == nil {
+			log.Info("acquired leadership")
+			e.lead(ctx, revision, onLeading)
+			log.Info("gave up leadership")
+			if ctx.Err() != nil {
+				log.Info("the context is done")
+				return
+			}
+		} else if !eris.Is(err, nats.ErrKeyExists) {
+			log.Error("failed acquiring leadership", logging.Error(err))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			log.Info("the context is done")
+			return
+		}
+	}
+}
+
+// lead calls onLeading and renews the lease until onLeading returns, the renewal fails or the context is done
+func (e *LeaderElection) lead(ctx context.Context, revision uint64, onLeading func(ctx context.Context)) {
+	log := logging.FromContext(ctx)
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		onLeading(leaderCtx)
+	}()
+
+	ticker := time.NewTicker(e.retryPeriod)
+	defer ticker.Stop()
+
+loop:
+	for {
+		select {
+		case <-ticker.C:
+			var err error
+			if revision, err = e.kv.Update(e.key, []byte(e.identity), revision); err != nil {
+				log.Error("failed renewing leadership lease", logging.Error(err))
+				cancel()
+				<-done
+				return
+			}
+		case <-done:
+			break loop
+		case <-ctx.Done():
+			cancel()
+			<-done
+			break loop
+		}
+	}
+
+	// the connection may have been closed while shutting down, in which case the lease expires after the lease duration instead
+	if e.natsClient.IsClosed() {
+		log.Info("not releasing leadership lease since the connection is closed")
+		return
+	}
+	if err := e.kv.Delete(e.key, nats.LastRevision(revision)); err != nil {
+		log.Error("failed releasing leadership lease", logging.Error(err))
+	}
+}
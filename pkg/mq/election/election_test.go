@@ -0,0 +1,75 @@
+package election_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/election"
+	mqtesting "github.com/nico151999/high-availability-expense-splitter/pkg/mq/testing"
+)
+
+func TestLeaderElection(t *testing.T) {
+	log := logging.GetLogger().Named("testLeaderElection")
+	ctx := logging.IntoContext(context.Background(), log)
+
+	server, port := mqtesting.RunJetStreamMQServer(t.TempDir())
+	defer server.Shutdown()
+
+	nc, err := nats.Connect(fmt.Sprintf("localhost:%d", port))
+	if err != nil {
+		t.Fatalf("failed connecting to NATS server: %+v", err)
+	}
+	defer nc.Close()
+
+	leaseDuration := 3 * time.Second
+
+	t.Run("Only one replica leads and another one takes over", func(t *testing.T) {
+		leading := make(chan int, 2)
+		stopped := make(chan int, 2)
+		cancels := make([]context.CancelFunc, 2)
+		for i := range cancels {
+			replica := i
+			e, err := election.NewLeaderElection(ctx, nc, "TEST_LEADER_ELECTION", "test", leaseDuration)
+			if err != nil {
+				t.Fatalf("failed creating leader election: %+v", err)
+			}
+			var replicaCtx context.Context
+			replicaCtx, cancels[replica] = context.WithCancel(ctx)
+			defer cancels[replica]()
+			go e.Run(replicaCtx, func(ctx context.Context) {
+				leading <- replica
+				<-ctx.Done()
+				stopped <- replica
+			})
+		}
+
+		var leader int
+		select {
+		case leader = <-leading:
+		case <-time.After(2 * leaseDuration):
+			t.Fatal("expected a replica to acquire the leadership")
+		}
+		select {
+		case replica := <-leading:
+			t.Fatalf("expected only one leader but replica %d leads as well", replica)
+		case <-time.After(leaseDuration):
+		}
+
+		cancels[leader]()
+		if replica := <-stopped; replica != leader {
+			t.Fatalf("expected replica %d to stop leading but it was replica %d", leader, replica)
+		}
+		select {
+		case replica := <-leading:
+			if replica == leader {
+				t.Fatalf("expected the other replica to take over but replica %d leads again", replica)
+			}
+		case <-time.After(2 * leaseDuration):
+			t.Fatal("expected the other replica to take over the leadership")
+		}
+	})
+}
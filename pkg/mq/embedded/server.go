@@ -0,0 +1,38 @@
+package embedded
+
+import (
+	"net"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/rotisserie/eris"
+)
+
+const readyTimeout = 10 * time.Second
+
+var errCreateServer = eris.New("failed creating embedded MQ server")
+var errServerNotReady = eris.New("embedded MQ server did not become ready for connections")
+
+// RunJetStreamMQServer starts an MQ server with JetStream enabled within the current process, e.g. for running the whole system in a single binary.
+// It listens on the passed host and port; passing port -1 causes a random free port to be chosen.
+// The JetStream data is stored in the passed directory.
+// The actual port used will be returned along the server.
+func RunJetStreamMQServer(host string, port int, storeDir string) (*natsserver.Server, int, error) {
+	server, err := natsserver.NewServer(&natsserver.Options{
+		Host:      host,
+		Port:      port,
+		JetStream: true,
+		StoreDir:  storeDir,
+		// signals are handled by the binary embedding the server
+		NoSigs: true,
+	})
+	if err != nil {
+		return nil, 0, eris.Wrap(errCreateServer, err.Error())
+	}
+	go server.Start()
+	if !server.ReadyForConnections(readyTimeout) {
+		server.Shutdown()
+		return nil, 0, errServerNotReady
+	}
+	return server, server.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,35 @@
+package embedded_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/mq/embedded"
+)
+
+func TestRunJetStreamMQServer(t *testing.T) {
+	server, port, err := embedded.RunJetStreamMQServer("127.0.0.1", -1, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Shutdown()
+	if port <= 0 {
+		t.Fatalf("expected a random free port to be chosen but got %d", port)
+	}
+
+	nc, err := nats.Connect(fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		t.Fatalf("failed connecting to embedded server: %+v", err)
+	}
+	defer nc.Close()
+	js, err := jetstream.New(nc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := js.AccountInfo(context.Background()); err != nil {
+		t.Errorf("expected JetStream to be enabled: %+v", err)
+	}
+}
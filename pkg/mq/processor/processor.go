@@ -7,6 +7,8 @@ import (
 	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
+	mqClient "github.com/nico151999/high-availability-expense-splitter/pkg/mq/client"
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
 	"github.com/rotisserie/eris"
 
 	"google.golang.org/protobuf/proto"
@@ -51,6 +53,26 @@ func GetStreamProcessor[E proto.Message](
 	streamAndConsumerName string,
 	subject string,
 	processor func(ctx context.Context, event E) error,
+) (jetstream.ConsumeContext, error) {
+	return GetMsgStreamProcessor(ctx, natsClient, sourceStreamName, streamAndConsumerName, subject, func(ctx context.Context, msg jetstream.Msg) error {
+		var event E
+		event = reflect.New(reflect.TypeOf(event).Elem()).Interface().(E)
+		if err := proto.Unmarshal(msg.Data(), event); err != nil {
+			return eris.Wrap(err, "failed to unmarshal data of a message")
+		}
+		return processor(ctx, event)
+	})
+}
+
+// GetMsgStreamProcessor is like GetStreamProcessor but passes the raw messages to the processor, e.g. for processing events of different types.
+// The context passed to the processor carries the principal the event is attributed to so that changes and events caused by it are attributed to the same principal.
+func GetMsgStreamProcessor(
+	ctx context.Context,
+	natsClient *nats.Conn,
+	sourceStreamName string,
+	streamAndConsumerName string,
+	subject string,
+	processor func(ctx context.Context, msg jetstream.Msg) error,
 ) (jetstream.ConsumeContext, error) {
 	log := logging.FromContext(ctx).With(
 		logging.String("sourceStream", sourceStreamName),
@@ -100,15 +122,8 @@ func GetStreamProcessor[E proto.Message](
 			return
 		}
 
-		var event E
-		event = reflect.New(reflect.TypeOf(event).Elem()).Interface().(E)
-		if err := proto.Unmarshal(msg.Data(), event); err != nil {
-			log.Error("failed to unmarshal data of a message", logging.Error(err))
-			return
-		}
-
 		log.Debug("processing event")
-		if err := processor(ctx, event); err != nil {
+		if err := processor(principal.IntoContext(ctx, mqClient.EventPrincipal(msg.Headers())), msg); err != nil {
 			log.Error("failed to process a message", logging.Error(err))
 			return
 		}
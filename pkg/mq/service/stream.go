@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"reflect"
 	"time"
 
 	"connectrpc.com/connect"
@@ -9,6 +10,7 @@ import (
 	"github.com/nico151999/high-availability-expense-splitter/pkg/db/util"
 	"github.com/nico151999/high-availability-expense-splitter/pkg/logging"
 	"github.com/rotisserie/eris"
+	"google.golang.org/protobuf/proto"
 )
 
 type retrieveCurrentResourceFunc[T any] func(context.Context) (*T, error)
@@ -73,6 +75,65 @@ loop:
 	return nil
 }
 
+// StreamEvents sends a message converted from each event published on the subject to the client until the context is done.
+// Unlike StreamResource it does not send the current state first since the events themselves are what the client is interested in.
+func StreamEvents[E proto.Message, T any](
+	ctx context.Context,
+	natsClient *nats.Conn,
+	subj string,
+	convertEvent func(context.Context, E) (*T, error),
+	srv *connect.ServerStream[T],
+	stillAliveMsg *T) error {
+	log := logging.FromContext(ctx)
+
+	ticker := time.NewTicker(tickerPeriod)
+	defer ticker.Stop()
+
+	resChan := make(chan *nats.Msg)
+	sub, err := natsClient.ChanSubscribe(subj, resChan)
+	if err != nil {
+		log.Error("failed subscribing to events", logging.Error(err), logging.String("subject", subj))
+		return ErrSubscribeResource
+	}
+	defer func() {
+		if err := sub.Unsubscribe(); err != nil {
+			log.Error("failed unsubscribing from events", logging.Error(err), logging.String("subject", subj))
+		}
+	}()
+
+loop:
+	for {
+		select {
+		case msg := <-resChan:
+			var event E
+			event = reflect.New(reflect.TypeOf(event).Elem()).Interface().(E)
+			if err := proto.Unmarshal(msg.Data, event); err != nil {
+				log.Error("failed unmarshalling event", logging.Error(err), logging.String("subject", msg.Subject))
+				continue
+			}
+			res, err := convertEvent(ctx, event)
+			if err != nil {
+				return eris.Wrap(err, "failed to convert event")
+			}
+			if err := srv.Send(res); err != nil {
+				log.Error("failed sending event message to client", logging.Error(err))
+				return ErrSendCurrentResourceMessage
+			}
+			ticker.Reset(tickerPeriod)
+		case <-ticker.C:
+			if err := srv.Send(stillAliveMsg); err != nil {
+				log.Error("failed sending still alive message to client", logging.Error(err))
+				return ErrSendStreamAliveMessage
+			}
+		case <-ctx.Done():
+			log.Info("the context is done")
+			break loop
+		}
+	}
+	log.Info("the stream ends now")
+	return nil
+}
+
 func sendCurrentResource[T any](
 	ctx context.Context,
 	srv *connect.ServerStream[T],
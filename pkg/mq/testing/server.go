@@ -15,3 +15,15 @@ func RunMQServer(port int) (*natsserver.Server, int) {
 	server := natstestserver.RunServer(&opts)
 	return server, server.Addr().(*net.TCPAddr).Port
 }
+
+// RunJetStreamMQServer creates a new MQ server with JetStream enabled meant for testing.
+// The JetStream data is stored in the passed directory.
+// The actual port used will be returned along the server.
+func RunJetStreamMQServer(storeDir string) (*natsserver.Server, int) {
+	opts := natstestserver.DefaultTestOptions
+	opts.Port = -1
+	opts.JetStream = true
+	opts.StoreDir = storeDir
+	server := natstestserver.RunServer(&opts)
+	return server, server.Addr().(*net.TCPAddr).Port
+}
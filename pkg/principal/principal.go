@@ -0,0 +1,27 @@
+package principal
+
+import "context"
+
+type principalCtxKeyType int
+
+const principalCtxKey principalCtxKeyType = iota
+
+const (
+	// Anonymous is the principal of requests that do not carry a principal, e.g. because no authenticating proxy forwarded them
+	Anonymous = "anonymous"
+	// System is the principal of changes not caused by a request, e.g. currencies synchronised by the currency processor
+	System = "system"
+)
+
+// IntoContext packages the principal performing an operation into a given context
+func IntoContext(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalCtxKey, principal)
+}
+
+// FromContext extracts the principal performing an operation from a context or defaults to Anonymous if none is present
+func FromContext(ctx context.Context) string {
+	if principal, ok := ctx.Value(principalCtxKey).(string); ok && principal != "" {
+		return principal
+	}
+	return Anonymous
+}
@@ -0,0 +1,26 @@
+package principal_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/principal"
+)
+
+func TestFromContext(t *testing.T) {
+	for name, params := range map[string]struct {
+		ctx      context.Context
+		expected string
+	}{
+		"Default to anonymous":          {context.Background(), principal.Anonymous},
+		"Default to anonymous if empty": {principal.IntoContext(context.Background(), ""), principal.Anonymous},
+		"Extract principal":             {principal.IntoContext(context.Background(), "alice"), "alice"},
+	} {
+		params := params
+		t.Run(name, func(t *testing.T) {
+			if p := principal.FromContext(params.ctx); p != params.expected {
+				t.Errorf("expected principal %s but got %s", params.expected, p)
+			}
+		})
+	}
+}
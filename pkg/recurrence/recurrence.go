@@ -0,0 +1,95 @@
+package recurrence
+
+import (
+	"time"
+)
+
+// Frequency is the unit of the period a rule recurs with
+type Frequency int
+
+const (
+	Daily Frequency = iota + 1
+	Weekly
+	Monthly
+	Yearly
+)
+
+// Rule describes a schedule recurring every interval units of the frequency starting at the start time.
+// Monthly and yearly occurrences fall on the day of the month of the start time or on the last day of shorter months.
+type Rule struct {
+	Frequency Frequency
+	Interval  int
+	Start     time.Time
+	// End is the time after which there are no more occurrences; there is no end if it is the zero time
+	End time.Time
+}
+
+// Occurrence returns the nth occurrence of the rule with the first occurrence being the start time.
+// Occurrences are always derived from the start time so that shortened months do not shift later occurrences.
+func (r Rule) Occurrence(n int) time.Time {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	switch r.Frequency {
+	case Weekly:
+		return r.Start.AddDate(0, 0, 7*n*interval)
+	case Monthly:
+		return addMonths(r.Start, n*interval)
+	case Yearly:
+		return addMonths(r.Start, 12*n*interval)
+	default:
+		return r.Start.AddDate(0, 0, n*interval)
+	}
+}
+
+// Next returns the first occurrence after the passed time or false if there is none before the end of the rule
+func (r Rule) Next(after time.Time) (time.Time, bool) {
+	n := 0
+	if after.After(r.Start) {
+		// the estimate is rather too small than too large so that no occurrence is skipped
+		n = r.estimate(after) - 1
+		if n < 0 {
+			n = 0
+		}
+	}
+	next := r.Occurrence(n)
+	for !next.After(after) {
+		n++
+		next = r.Occurrence(n)
+	}
+	if !r.End.IsZero() && next.After(r.End) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// estimate returns roughly the number of occurrences between the start and the passed time
+func (r Rule) estimate(t time.Time) int {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	switch r.Frequency {
+	case Weekly:
+		return int(t.Sub(r.Start)/(7*24*time.Hour)) / interval
+	case Monthly, Yearly:
+		months := (t.Year()-r.Start.Year())*12 + int(t.Month()) - int(r.Start.Month())
+		if r.Frequency == Yearly {
+			months /= 12
+		}
+		return months / interval
+	default:
+		return int(t.Sub(r.Start)/(24*time.Hour)) / interval
+	}
+}
+
+// addMonths adds the passed number of months to the passed time using the last day of the month if the day does not exist in it
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
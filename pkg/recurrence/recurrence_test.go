@@ -0,0 +1,87 @@
+package recurrence_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/recurrence"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+}
+
+func TestNext(t *testing.T) {
+	for name, params := range map[string]struct {
+		rule     recurrence.Rule
+		after    time.Time
+		expected time.Time
+		ok       bool
+	}{
+		"Start before it": {
+			recurrence.Rule{Frequency: recurrence.Monthly, Interval: 1, Start: date(2023, time.March, 1)},
+			date(2023, time.January, 1),
+			date(2023, time.March, 1),
+			true,
+		},
+		"Daily": {
+			recurrence.Rule{Frequency: recurrence.Daily, Interval: 1, Start: date(2023, time.March, 1)},
+			date(2023, time.March, 10),
+			date(2023, time.March, 11),
+			true,
+		},
+		"Every second week": {
+			recurrence.Rule{Frequency: recurrence.Weekly, Interval: 2, Start: date(2023, time.March, 1)},
+			date(2023, time.March, 16),
+			date(2023, time.March, 29),
+			true,
+		},
+		"Monthly at the end of shorter months": {
+			recurrence.Rule{Frequency: recurrence.Monthly, Interval: 1, Start: date(2023, time.January, 31)},
+			date(2023, time.February, 1),
+			date(2023, time.February, 28),
+			true,
+		},
+		"Monthly after a shorter month": {
+			recurrence.Rule{Frequency: recurrence.Monthly, Interval: 1, Start: date(2023, time.January, 31)},
+			date(2023, time.February, 28),
+			date(2023, time.March, 31),
+			true,
+		},
+		"Exactly at an occurrence": {
+			recurrence.Rule{Frequency: recurrence.Monthly, Interval: 3, Start: date(2023, time.January, 15)},
+			date(2023, time.April, 15),
+			date(2023, time.July, 15),
+			true,
+		},
+		"Yearly on leap day": {
+			recurrence.Rule{Frequency: recurrence.Yearly, Interval: 1, Start: date(2024, time.February, 29)},
+			date(2024, time.March, 1),
+			date(2025, time.February, 28),
+			true,
+		},
+		"After the end": {
+			recurrence.Rule{Frequency: recurrence.Weekly, Interval: 1, Start: date(2023, time.March, 1), End: date(2023, time.March, 20)},
+			date(2023, time.March, 15),
+			time.Time{},
+			false,
+		},
+		"At the end": {
+			recurrence.Rule{Frequency: recurrence.Weekly, Interval: 1, Start: date(2023, time.March, 1), End: date(2023, time.March, 22)},
+			date(2023, time.March, 15),
+			date(2023, time.March, 22),
+			true,
+		},
+	} {
+		params := params
+		t.Run(name, func(t *testing.T) {
+			next, ok := params.rule.Next(params.after)
+			if ok != params.ok {
+				t.Fatalf("expected an occurrence to be found to be %t but got %t", params.ok, ok)
+			}
+			if !next.Equal(params.expected) {
+				t.Errorf("expected next occurrence %s but got %s", params.expected, next)
+			}
+		})
+	}
+}
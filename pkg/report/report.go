@@ -0,0 +1,39 @@
+package report
+
+import (
+	"time"
+)
+
+// Bucket is the length of the time ranges spending is aggregated in
+type Bucket int
+
+const (
+	Day Bucket = iota + 1
+	// Week starts on Monday
+	Week
+	Month
+)
+
+// Start returns the start in UTC of the bucket the passed time is in
+func (b Bucket) Start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch b {
+	case Week:
+		// time.Sunday is 0 so that Sunday has to go back six days
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case Month:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// Spending is the sum of stakes the database computed for the expenses of a day in one currency
+type Spending struct {
+	// Currency is the acronym of the currency of the expenses
+	Currency string
+	// Day is the start of the day in UTC
+	Day    time.Time
+	Amount int64
+}
@@ -0,0 +1,32 @@
+package report_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/report"
+)
+
+func TestBucketStart(t *testing.T) {
+	// 2023-03-15 is a Wednesday
+	wednesday := time.Date(2023, time.March, 15, 18, 30, 0, 0, time.UTC)
+	for name, params := range map[string]struct {
+		bucket   report.Bucket
+		t        time.Time
+		expected time.Time
+	}{
+		"Day":                     {report.Day, wednesday, time.Date(2023, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		"Week":                    {report.Week, wednesday, time.Date(2023, time.March, 13, 0, 0, 0, 0, time.UTC)},
+		"Week starting on Monday": {report.Week, time.Date(2023, time.March, 13, 0, 0, 0, 0, time.UTC), time.Date(2023, time.March, 13, 0, 0, 0, 0, time.UTC)},
+		"Week ending on Sunday":   {report.Week, time.Date(2023, time.March, 19, 23, 0, 0, 0, time.UTC), time.Date(2023, time.March, 13, 0, 0, 0, 0, time.UTC)},
+		"Week across years":       {report.Week, time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, time.December, 26, 0, 0, 0, 0, time.UTC)},
+		"Month":                   {report.Month, wednesday, time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		"Other time zone":         {report.Day, time.Date(2023, time.March, 16, 1, 0, 0, 0, time.FixedZone("CET", 60*60)), time.Date(2023, time.March, 16, 0, 0, 0, 0, time.UTC)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if start := params.bucket.Start(params.t); !start.Equal(params.expected) {
+				t.Errorf("Expected the bucket to start at %s; got: %s", params.expected, start)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+// maxErrorBodySize is the number of bytes of the response body of a failed delivery kept as part of its error
+const maxErrorBodySize = 256
+
+// ErrForbiddenAddress tells that the URL of a webhook resolved to an address deliveries must not be posted to
+var ErrForbiddenAddress = eris.New("the address is not publicly routable")
+
+// nonPublicNetworks are the networks which are not covered by the checks of net.IP but must not be reached either,
+// like the shared address space used by some clusters and the NAT64 prefix embedding IPv4 addresses
+var nonPublicNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"100.64.0.0/10",
+	"192.0.0.0/24",
+	"198.18.0.0/15",
+	"240.0.0.0/4",
+	"64:ff9b::/96",
+	"64:ff9b:1::/48",
+)
+
+// Delivery is a signed JSON request posted to the URL of a webhook
+type Delivery struct {
+	// Id identifies the delivery across its attempts so that receivers can deduplicate it
+	Id    string
+	Event string
+	Body  []byte
+}
+
+// StatusError tells that the receiver of a delivery answered with a status code other than 2xx
+type StatusError struct {
+	StatusCode int
+	// Body is the beginning of the response body
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("the receiver responded with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("the receiver responded with status %d: %s", e.StatusCode, e.Body)
+}
+
+// Config configures the client posting deliveries
+type Config struct {
+	// Timeout is how long a receiver may take to respond to a delivery
+	Timeout time.Duration
+	// AllowPrivateAddresses allows posting deliveries to loopback, link-local and private addresses, which is only meant
+	// for tests and local development since webhooks could otherwise reach services in the cluster
+	AllowPrivateAddresses bool
+}
+
+// Client posts deliveries to the URLs of webhooks
+type Client struct {
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewClient creates a client which gives up on a delivery once the configured timeout elapsed. Redirects are not followed
+// since the signature only vouches for the URL the webhook was registered with. Unless configured otherwise, connections
+// to addresses that are not publicly routable are refused when they are dialed so that a URL cannot be pointed at the
+// cluster by rebinding its DNS name after the webhook was created.
+func NewClient(config Config) *Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if !config.AllowPrivateAddresses {
+		dialer.Control = denyNonPublicAddresses
+	}
+	return &Client{
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+			// no proxy is configured since the addresses dialed would be the ones of the proxy rather than of the receivers
+			Transport: &http.Transport{
+				DialContext:           dialer.DialContext,
+				ForceAttemptHTTP2:     true,
+				MaxIdleConns:          100,
+				IdleConnTimeout:       90 * time.Second,
+				TLSHandshakeTimeout:   10 * time.Second,
+				ExpectContinueTimeout: time.Second,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// denyNonPublicAddresses is the control function of a dialer refusing to connect to addresses that are not publicly routable.
+// It is called with the resolved address right before connecting which is why it also covers DNS names resolving to such addresses.
+func denyNonPublicAddresses(_ string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return eris.Wrapf(ErrForbiddenAddress, "failed parsing the address %s", address)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublic(ip) {
+		return eris.Wrapf(ErrForbiddenAddress, "the address %s must not be reached", host)
+	}
+	return nil
+}
+
+// isPublic tells whether the IP address is publicly routable
+func isPublic(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// Deliver posts the delivery signed with the secret to the URL. It returns the status code the receiver responded with,
+// which is 0 if no response was received, and a *StatusError if the status code is not 2xx.
+func (c *Client) Deliver(ctx context.Context, url string, secret string, delivery Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(delivery.Body))
+	if err != nil {
+		return 0, eris.Wrap(err, "failed creating delivery request")
+	}
+	timestamp := c.now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "expense-splitter-webhook")
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign(secret, timestamp, delivery.Body))
+	req.Header.Set(DeliveryHeader, delivery.Id)
+	req.Header.Set(EventHeader, delivery.Event)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, eris.Wrap(err, "failed posting delivery")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return resp.StatusCode, &StatusError{
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+		}
+	}
+	// the body is drained so that the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	return resp.StatusCode, nil
+}
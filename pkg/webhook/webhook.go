@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+// The headers every delivery carries so that receivers can tell deliveries apart and verify their origin
+const (
+	SignatureHeader = "X-Expense-Splitter-Signature"
+	TimestampHeader = "X-Expense-Splitter-Timestamp"
+	DeliveryHeader  = "X-Expense-Splitter-Delivery"
+	EventHeader     = "X-Expense-Splitter-Event"
+)
+
+const signaturePrefix = "sha256="
+
+var ErrInvalidSignature = eris.New("the signature of the delivery is invalid")
+var ErrTimestampOutOfTolerance = eris.New("the timestamp of the delivery is out of tolerance")
+
+// Sign returns the value of the signature header of a delivery of the passed body sent at the passed time. The signature is
+// the hex encoded HMAC-SHA256 of the unix timestamp and the body joined by a dot so that a delivery cannot be replayed later on.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks that the headers of a received delivery carry a valid signature of its body and that it was sent at most
+// the passed tolerance ago. It is meant for receivers of deliveries written in Go.
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration, now time.Time) error {
+	unix, err := strconv.ParseInt(header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return eris.Wrap(ErrInvalidSignature, "the timestamp header is missing or malformed")
+	}
+	timestamp := time.Unix(unix, 0)
+	if diff := now.Sub(timestamp); diff > tolerance || diff < -tolerance {
+		return ErrTimestampOutOfTolerance
+	}
+	signature := header.Get(SignatureHeader)
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return eris.Wrap(ErrInvalidSignature, "the signature header is missing or malformed")
+	}
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Backoff returns how long to wait before the next attempt of a delivery that failed the passed number of times.
+// The delay doubles with every attempt starting at 10 seconds and is capped at an hour.
+func Backoff(attempts int) time.Duration {
+	const base = 10 * time.Second
+	const max = time.Hour
+	if attempts < 1 {
+		return base
+	}
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
@@ -0,0 +1,172 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nico151999/high-availability-expense-splitter/pkg/webhook"
+	"github.com/rotisserie/eris"
+)
+
+func TestClient(t *testing.T) {
+	secret := "whsec-123456789012345"
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan received, 1)
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed reading request body: %+v", err)
+		}
+		requests <- received{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+		if status >= 300 {
+			_, _ = w.Write([]byte("nope"))
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client := webhook.NewClient(webhook.Config{
+		Timeout: 5 * time.Second,
+		// the test server listens on a loopback address
+		AllowPrivateAddresses: true,
+	})
+	delivery := webhook.Delivery{
+		Id:    "webhookdelivery-123456789012345",
+		Event: "expense.created",
+		Body:  []byte(`{"event":"expense.created"}`),
+	}
+
+	t.Run("Delivering posts a signed request the receiver can verify", func(t *testing.T) {
+		status = http.StatusNoContent
+		code, err := client.Deliver(ctx, server.URL, secret, delivery)
+		if err != nil {
+			t.Fatalf("expected the delivery to succeed but got: %+v", err)
+		}
+		if code != http.StatusNoContent {
+			t.Errorf("expected status %d but got %d", http.StatusNoContent, code)
+		}
+		req := <-requests
+		if string(req.body) != string(delivery.Body) {
+			t.Errorf("expected body %s but got %s", delivery.Body, req.body)
+		}
+		if req.header.Get(webhook.DeliveryHeader) != delivery.Id {
+			t.Errorf("expected delivery header %s but got %s", delivery.Id, req.header.Get(webhook.DeliveryHeader))
+		}
+		if req.header.Get(webhook.EventHeader) != delivery.Event {
+			t.Errorf("expected event header %s but got %s", delivery.Event, req.header.Get(webhook.EventHeader))
+		}
+		if contentType := req.header.Get("Content-Type"); contentType != "application/json" {
+			t.Errorf("expected a JSON request but got %s", contentType)
+		}
+		if err := webhook.Verify(secret, req.header, req.body, 5*time.Minute, time.Now()); err != nil {
+			t.Errorf("expected the signature to be valid but got: %+v", err)
+		}
+		if err := webhook.Verify("another secret", req.header, req.body, 5*time.Minute, time.Now()); !eris.Is(err, webhook.ErrInvalidSignature) {
+			t.Errorf("expected the signature to be invalid for another secret but got: %+v", err)
+		}
+		if err := webhook.Verify(secret, req.header, []byte(`{"event":"expense.deleted"}`), 5*time.Minute, time.Now()); !eris.Is(err, webhook.ErrInvalidSignature) {
+			t.Errorf("expected the signature to be invalid for a tampered body but got: %+v", err)
+		}
+		if err := webhook.Verify(secret, req.header, req.body, 5*time.Minute, time.Now().Add(time.Hour)); !eris.Is(err, webhook.ErrTimestampOutOfTolerance) {
+			t.Errorf("expected a replayed delivery to be rejected but got: %+v", err)
+		}
+	})
+
+	t.Run("Delivering fails if the receiver responds with an error status", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		code, err := client.Deliver(ctx, server.URL, secret, delivery)
+		<-requests
+		if code != http.StatusInternalServerError {
+			t.Errorf("expected status %d but got %d", http.StatusInternalServerError, code)
+		}
+		var statusErr *webhook.StatusError
+		if !errors.As(err, &statusErr) {
+			t.Fatalf("expected a status error but got: %+v", err)
+		}
+		if statusErr.Body != "nope" {
+			t.Errorf("expected the error to carry the response body but got %q", statusErr.Body)
+		}
+	})
+
+	t.Run("Delivering does not follow redirects", func(t *testing.T) {
+		status = http.StatusFound
+		code, err := client.Deliver(ctx, server.URL, secret, delivery)
+		<-requests
+		if code != http.StatusFound || err == nil {
+			t.Errorf("expected the redirect to fail the delivery but got status %d and error %+v", code, err)
+		}
+	})
+
+	t.Run("Delivering fails if the receiver is unreachable", func(t *testing.T) {
+		unreachable := httptest.NewServer(http.NotFoundHandler())
+		url := unreachable.URL
+		unreachable.Close()
+		code, err := client.Deliver(ctx, url, secret, delivery)
+		if err == nil {
+			t.Fatal("expected the delivery to fail")
+		}
+		if code != 0 {
+			t.Errorf("expected no status but got %d", code)
+		}
+	})
+
+	t.Run("Delivering refuses addresses that are not publicly routable", func(t *testing.T) {
+		client := webhook.NewClient(webhook.Config{
+			Timeout: 5 * time.Second,
+		})
+		for _, url := range []string{
+			server.URL,
+			"http://localhost:8080",
+			"http://169.254.169.254/latest/meta-data",
+			"http://10.0.0.1",
+			"http://192.168.1.1",
+			"http://100.64.0.1",
+			"http://[::1]:8080",
+			"http://[fd00::1]",
+			"http://[::ffff:127.0.0.1]",
+		} {
+			code, err := client.Deliver(ctx, url, secret, delivery)
+			if !errors.Is(err, webhook.ErrForbiddenAddress) {
+				t.Errorf("expected the delivery to %s to be refused but got: %+v", url, err)
+			}
+			if code != 0 {
+				t.Errorf("expected no status for %s but got %d", url, code)
+			}
+		}
+		select {
+		case <-requests:
+			t.Error("expected the receiver not to be reached")
+		default:
+		}
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	for _, tc := range []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{0, 10 * time.Second},
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{4, 80 * time.Second},
+		{9, 2560 * time.Second},
+		{10, time.Hour},
+		{100, time.Hour},
+	} {
+		if actual := webhook.Backoff(tc.attempts); actual != tc.expected {
+			t.Errorf("expected a backoff of %s after %d attempts but got %s", tc.expected, tc.attempts, actual)
+		}
+	}
+}